@@ -0,0 +1,54 @@
+package app
+
+import (
+	"io"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// alertCooldown keeps a burst of errors, e.g. one per failed segment
+// save on a full disk, from ringing the bell for each.
+const alertCooldown = 10 * time.Second
+
+// flashDuration is how long the dividers stay red after an alert.
+const flashDuration = 300 * time.Millisecond
+
+// FlashDoneMsg ends an alert flash.
+type FlashDoneMsg struct{}
+
+// alert cues a critical event as configured in `alerts`: a terminal bell,
+// a flash of the dividers, or both. Critical means an error on the bar
+// (which includes recovery giving up and failed saves) or losing the
+// daemon. Nil when alerts are off or one fired within alertCooldown.
+func (m *Model) alert() tea.Cmd {
+	if !m.alerts.Bell && !m.alerts.Flash {
+		return nil
+	}
+	now := m.now()
+	if !m.lastAlertAt.IsZero() && now.Sub(m.lastAlertAt) < alertCooldown {
+		return nil
+	}
+	m.lastAlertAt = now
+
+	var cmds []tea.Cmd
+	if m.alerts.Bell {
+		cmds = append(cmds, bellCmd(m.bellOut))
+	}
+	if m.alerts.Flash {
+		m.flashing = true
+		cmds = append(cmds, m.tick(flashDuration, func(time.Time) tea.Msg { return FlashDoneMsg{} }))
+	}
+	return tea.Batch(cmds...)
+}
+
+// bellCmd writes BEL to w. The TUI passes stderr: bubbletea owns stdout,
+// and a lone BEL is harmless wherever it lands between frames.
+func bellCmd(w io.Writer) tea.Cmd {
+	return func() tea.Msg {
+		if w != nil {
+			io.WriteString(w, "\a")
+		}
+		return nil
+	}
+}
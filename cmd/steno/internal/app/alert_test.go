@@ -0,0 +1,145 @@
+package app
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jwulff/steno/internal/config"
+	"github.com/jwulff/steno/internal/daemon"
+	"github.com/jwulff/steno/internal/ui"
+)
+
+// runCmd runs cmd and any batch it returns, collecting the messages.
+func runCmd(cmd tea.Cmd) []tea.Msg {
+	if cmd == nil {
+		return nil
+	}
+	msg := cmd()
+	if batch, ok := msg.(tea.BatchMsg); ok {
+		var msgs []tea.Msg
+		for _, c := range batch {
+			msgs = append(msgs, runCmd(c)...)
+		}
+		return msgs
+	}
+	if msg == nil {
+		return nil
+	}
+	return []tea.Msg{msg}
+}
+
+func alertModel(alerts config.AlertsConfig) (Model, *bytes.Buffer, *manualClock) {
+	c := newManualClock()
+	m := NewWithConfig(config.Config{Alerts: alerts}).WithClock(c)
+	m.width, m.height = 80, 24
+	bell := &bytes.Buffer{}
+	m.bellOut = bell
+	return m, bell, c
+}
+
+func TestAlertOnError(t *testing.T) {
+	m, bell, c := alertModel(config.AlertsConfig{Bell: true, Flash: true})
+
+	// Warnings don't alert.
+	transient := true
+	runCmd(m.handleEvent(daemon.Event{Event: "error", Message: "Mic busy", Transient: &transient}))
+	if bell.Len() != 0 || m.flashing {
+		t.Fatalf("warning alerted: bell %q, flashing %v", bell, m.flashing)
+	}
+
+	msgs := runCmd(m.handleEvent(daemon.Event{Event: "error", Message: "Failed to save segment: database or disk is full"}))
+	if bell.String() != "\a" {
+		t.Errorf("bell = %q, want one BEL", bell)
+	}
+	if !m.flashing || !strings.Contains(m.View(), ui.FlashStyle.Render(strings.Repeat(" ", m.width))) {
+		t.Error("an error should flash the dividers")
+	}
+	var done bool
+	for _, msg := range msgs {
+		if _, ok := msg.(FlashDoneMsg); ok {
+			done = true
+			m, _ = applyUpdate(m, msg)
+		}
+	}
+	if !done || m.flashing {
+		t.Errorf("flash did not end (done msg %v, flashing %v)", done, m.flashing)
+	}
+
+	// A second error inside the cooldown stays quiet.
+	c.advance(time.Second)
+	runCmd(m.handleEvent(daemon.Event{Event: "error", Message: "recovery_exhausted: mic"}))
+	if bell.Len() != 1 {
+		t.Errorf("bell rang %d times inside the cooldown", bell.Len())
+	}
+	c.advance(alertCooldown)
+	runCmd(m.handleEvent(daemon.Event{Event: "error", Message: "recovery_exhausted: mic again"}))
+	if bell.Len() != 2 {
+		t.Errorf("bell rang %d times, want 2 after the cooldown", bell.Len())
+	}
+}
+
+func TestAlertOnDisconnect(t *testing.T) {
+	m, bell, _ := alertModel(config.AlertsConfig{Bell: true})
+	m.connected = true
+
+	m, cmd := applyUpdate(m, DaemonEventErrorMsg{Err: errors.New("EOF")})
+	runCmd(cmd)
+	if bell.String() != "\a" {
+		t.Errorf("bell = %q after losing the daemon", bell)
+	}
+	if m.flashing {
+		t.Error("flash is off in config")
+	}
+
+	// Failed reconnects don't ring again, cooldown or not.
+	m.lastAlertAt = time.Time{}
+	_, cmd = applyUpdate(m, DaemonEventErrorMsg{Err: errors.New("EOF")})
+	runCmd(cmd)
+	if bell.Len() != 1 {
+		t.Errorf("bell rang %d times, want once per disconnect", bell.Len())
+	}
+}
+
+func TestAlertOnUnrequestedStop(t *testing.T) {
+	m, bell, c := alertModel(config.AlertsConfig{Bell: true})
+	m.connected = true
+	m.client = &daemon.Client{} // zero-value client; won't be invoked
+	recording := func(on bool) daemon.Event {
+		return daemon.Event{Event: "status", Recording: daemon.BoolPtr(on)}
+	}
+
+	// The TUI's own pause doesn't alert, whichever of the status and
+	// pause_state events lands first.
+	runCmd(m.handleEvent(recording(true)))
+	m, _ = applyUpdate(m, runeKey('p'))
+	runCmd(m.handleEvent(recording(false)))
+	if bell.Len() != 0 {
+		t.Fatalf("bell = %q after pausing", bell)
+	}
+
+	// Recording that stops on its own does, once.
+	runCmd(m.handleEvent(recording(true)))
+	runCmd(m.handleEvent(recording(false)))
+	if bell.String() != "\a" || m.engineStatus != StatusIdle {
+		t.Errorf("bell = %q, status %v, want an alert going idle", bell, m.engineStatus)
+	}
+	c.advance(alertCooldown)
+	runCmd(m.handleEvent(recording(false)))
+	if bell.Len() != 1 {
+		t.Errorf("bell rang %d times, want once per stop", bell.Len())
+	}
+}
+
+func TestAlertsOffByDefault(t *testing.T) {
+	m, bell, _ := alertModel(config.AlertsConfig{})
+	m.connected = true
+	runCmd(m.handleEvent(daemon.Event{Event: "error", Message: "recovery_exhausted: mic"}))
+	m, _ = applyUpdate(m, DaemonEventErrorMsg{Err: errors.New("EOF")})
+	if bell.Len() != 0 || m.flashing {
+		t.Errorf("alerts fired with none configured: bell %q, flashing %v", bell, m.flashing)
+	}
+}
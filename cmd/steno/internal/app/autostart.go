@@ -0,0 +1,99 @@
+package app
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jwulff/steno/internal/daemon"
+)
+
+// autoStartCancelWindow is how long after an auto-start `A` cancels it
+// (the speech was a podcast, not a meeting) instead of meaning "disarm".
+const autoStartCancelWindow = 30 * time.Second
+
+// armCmd sends an `arm` command: the daemon meters the mic while idle
+// and starts a session with opts on sustained speech.
+func armCmd(client *daemon.Client, opts daemon.StartOptions) tea.Cmd {
+	return func() tea.Msg {
+		resp, err := sendCommand(client, daemon.ArmCmd(opts))
+		if err != nil {
+			return DaemonEventErrorMsg{Err: err}
+		}
+		return ArmResponseMsg{Response: resp}
+	}
+}
+
+// disarmCmd sends a `disarm` command.
+func disarmCmd(client *daemon.Client) tea.Cmd {
+	return func() tea.Msg {
+		resp, err := sendCommand(client, daemon.DisarmCmd())
+		if err != nil {
+			return DaemonEventErrorMsg{Err: err}
+		}
+		return ArmResponseMsg{Response: resp}
+	}
+}
+
+// autoStartCancellable reports whether a session auto-started recently
+// enough for `A` to cancel it.
+func (m Model) autoStartCancellable() bool {
+	return !m.autoStartedAt.IsZero() && m.now().Sub(m.autoStartedAt) < autoStartCancelWindow
+}
+
+// idle reports whether the daemon has no session open, the only state
+// arming is allowed from.
+func (m Model) idle() bool {
+	switch m.engineStatus {
+	case StatusIdle, StatusUnknown, StatusError:
+		return !m.recording
+	}
+	return false
+}
+
+// toggleArm handles `A`: arm voice-activated start while idle, disarm
+// while armed, and right after an auto-start, stop that session and arm
+// again.
+func (m Model) toggleArm() (tea.Model, tea.Cmd) {
+	if m.client == nil {
+		return m, nil
+	}
+	switch {
+	case m.autoStartCancellable():
+		m.autoStartedAt = time.Time{}
+		m.notice = "Auto-start cancelled, listening again"
+		m.stopRequested = true
+		return m, tea.Sequence(stopCmd(m.client), armCmd(m.client, m.startOptions(m.deviceName, m.systemAudio)), m.clearNoticeCmd())
+	case m.armed:
+		return m, disarmCmd(m.client)
+	case !m.idle():
+		m.notice = "Auto-start arms only while idle"
+		return m, m.clearNoticeCmd()
+	}
+	return m, armCmd(m.client, m.startOptions(m.deviceName, m.systemAudio))
+}
+
+// applyArmResponse records the daemon's answer to `arm` / `disarm`.
+func (m Model) applyArmResponse(r daemon.Response) (tea.Model, tea.Cmd) {
+	if !r.OK {
+		return m, m.pushError(SeverityWarn, "auto-start: "+r.Error, true)
+	}
+	if r.Armed != nil {
+		m.armed = *r.Armed
+	}
+	if m.armed {
+		m.notice = "Armed: recording starts when someone speaks"
+	} else {
+		m.notice = "Auto-start off"
+	}
+	return m, m.clearNoticeCmd()
+}
+
+// autoStarted handles `event:"auto_started"`: show the banner and open
+// the cancel window. The session itself arrives on the status events
+// that accompany it.
+func (m *Model) autoStarted() tea.Cmd {
+	m.armed = false
+	at := m.now()
+	m.autoStartedAt = at
+	return m.tick(autoStartCancelWindow, func(time.Time) tea.Msg { return AutoStartExpiredMsg{StartedAt: at} })
+}
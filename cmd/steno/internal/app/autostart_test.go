@@ -0,0 +1,76 @@
+package app
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jwulff/steno/internal/daemon"
+)
+
+func TestToggleArm(t *testing.T) {
+	m := New()
+	m.client = &daemon.Client{} // zero-value client; won't be invoked
+	m.engineStatus = StatusIdle
+
+	if _, cmd := applyUpdate(m, runeKey('A')); cmd == nil {
+		t.Error("A while idle should send arm")
+	}
+
+	m.engineStatus = StatusRecording
+	m.recording = true
+	m, _ = applyUpdate(m, runeKey('A'))
+	if m.notice != "Auto-start arms only while idle" {
+		t.Errorf("notice = %q, want the idle-only note", m.notice)
+	}
+
+	m.engineStatus, m.recording = StatusIdle, false
+	m, _ = applyUpdate(m, ArmResponseMsg{Response: daemon.Response{OK: true, Armed: daemon.BoolPtr(true)}})
+	if !m.armed || !strings.HasPrefix(m.notice, "Armed") {
+		t.Errorf("armed = %v, notice = %q", m.armed, m.notice)
+	}
+	if _, cmd := applyUpdate(m, runeKey('A')); cmd == nil {
+		t.Error("A while armed should send disarm")
+	}
+
+	m, _ = applyUpdate(m, ArmResponseMsg{Response: daemon.Response{OK: false, Error: "Permission denied"}})
+	if !m.armed || len(m.errorStack) != 1 {
+		t.Errorf("failed arm: armed = %v, errors = %v", m.armed, m.errorStack)
+	}
+}
+
+func TestAutoStartedCancelWindow(t *testing.T) {
+	c := newManualClock()
+	m := New().WithClock(c)
+	m.client = &daemon.Client{}
+	m.connected = true
+	m.width, m.height = 200, 24
+	m.handleEvent(daemon.Event{Event: "armed", Armed: daemon.BoolPtr(true)})
+	if label, _ := m.statusLabel(); !strings.Contains(label, "ARMED") {
+		t.Errorf("label = %q, want ARMED", label)
+	}
+
+	expire := m.handleEvent(daemon.Event{Event: "auto_started", SessionID: "s2"})
+	m.handleEvent(daemon.Event{Event: "status", Recording: daemon.BoolPtr(true)})
+	if m.armed || !strings.Contains(m.renderStatusBar(), "auto-started") {
+		t.Errorf("armed = %v, bar = %q; want the auto-started banner", m.armed, m.renderStatusBar())
+	}
+
+	// Within the window, A cancels rather than warning about recording.
+	c.advance(10 * time.Second)
+	got, cmd := applyUpdate(m, runeKey('A'))
+	if cmd == nil || got.notice != "Auto-start cancelled, listening again" || got.autoStartCancellable() {
+		t.Errorf("notice = %q, cmd = %v; want a cancel", got.notice, cmd)
+	}
+
+	// Once the window passes, the banner goes and A is back to normal.
+	c.advance(autoStartCancelWindow)
+	m, _ = applyUpdate(m, expire())
+	if !m.autoStartedAt.IsZero() || strings.Contains(m.renderStatusBar(), "auto-started") {
+		t.Errorf("banner still up after the window: %q", m.renderStatusBar())
+	}
+	m, _ = applyUpdate(m, runeKey('A'))
+	if m.notice != "Auto-start arms only while idle" {
+		t.Errorf("notice = %q, want the idle-only note", m.notice)
+	}
+}
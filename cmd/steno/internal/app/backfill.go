@@ -0,0 +1,86 @@
+package app
+
+import (
+	"sort"
+	"time"
+)
+
+// backfillHighlightTTL is how long a late segment stays highlighted.
+const backfillHighlightTTL = 3 * time.Second
+
+// BackfillHighlightMsg re-renders the transcript once a backfill
+// highlight has expired.
+type BackfillHighlightMsg struct{}
+
+// insertSegment places e in m.entries by (session, sequence number) and
+// reports whether it landed above the tail. Sessions keep the order they
+// first appeared in; within one, a late or backfilled segment goes before
+// any higher-numbered segment already shown, and a redelivered sequence
+// number replaces its earlier copy. Segments without a sequence number
+// fall back to timestamp order.
+func (m *Model) insertSegment(e TranscriptEntry) bool {
+	if e.SeqNum == 0 {
+		i := sort.Search(len(m.entries), func(j int) bool {
+			return m.entries[j].Timestamp.After(e.Timestamp)
+		})
+		m.insertEntry(i, e)
+		return false
+	}
+
+	// Walk back over e's session to the last entry numbered below it;
+	// e goes right after that one.
+	at, found := len(m.entries), false
+	for i := len(m.entries) - 1; i >= 0; i-- {
+		x := m.entries[i]
+		if x.IsBoundary || x.SeqNum == 0 || x.SessionID != e.SessionID {
+			if found && !x.IsBoundary && x.SessionID != e.SessionID {
+				break // before the start of e's session
+			}
+			continue
+		}
+		found = true
+		if x.SeqNum == e.SeqNum {
+			e.BackfilledAt = x.BackfilledAt
+			m.entries[i] = e
+			return false
+		}
+		if x.SeqNum < e.SeqNum {
+			at = i + 1
+			break
+		}
+		at = i
+	}
+	if at == len(m.entries) {
+		m.entries = append(m.entries, e)
+		return false
+	}
+	e.BackfilledAt = m.now()
+	m.insertEntry(at, e)
+	return true
+}
+
+func (m *Model) insertEntry(i int, e TranscriptEntry) {
+	m.entries = append(m.entries, TranscriptEntry{})
+	copy(m.entries[i+1:], m.entries[i:])
+	m.entries[i] = e
+}
+
+// backfillHighlighted reports whether e is inside its highlight window.
+func (m Model) backfillHighlighted(e TranscriptEntry) bool {
+	return !e.BackfilledAt.IsZero() && m.now().Sub(e.BackfilledAt) < backfillHighlightTTL
+}
+
+// resumePoint is the last segment the transcript holds, which a
+// resubscribe asks the daemon to replay from so segments spoken while
+// disconnected aren't lost; dedupSegments drops the replay's overlap with
+// what arrived. Entries are ordered by (session, sequence number), so it
+// is the last numbered one. Empty before any segment.
+func (m Model) resumePoint() (sessionID string, seq int) {
+	for i := len(m.entries) - 1; i >= 0; i-- {
+		e := m.entries[i]
+		if !e.IsBoundary && e.SeqNum > 0 && e.SessionID != "" {
+			return e.SessionID, e.SeqNum
+		}
+	}
+	return "", 0
+}
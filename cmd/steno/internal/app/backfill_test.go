@@ -0,0 +1,107 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jwulff/steno/internal/daemon"
+)
+
+func segmentEvent(session string, seq int, text string) daemon.Event {
+	return daemon.Event{Event: "segment", SessionID: session, SequenceNumber: &seq, Text: text, Source: "microphone"}
+}
+
+func entryTexts(m Model) string {
+	var texts []string
+	for _, e := range m.entries {
+		if e.IsBoundary {
+			texts = append(texts, "|")
+			continue
+		}
+		texts = append(texts, e.Text)
+	}
+	return strings.Join(texts, " ")
+}
+
+func TestBackfilledSegmentInsertedInPlace(t *testing.T) {
+	c := newManualClock()
+	m := New().WithClock(c)
+
+	for _, ev := range []daemon.Event{segmentEvent("s1", 1, "one"), segmentEvent("s1", 2, "two"), segmentEvent("s1", 4, "four")} {
+		if cmd := m.handleEvent(ev); cmd != nil {
+			t.Fatalf("in-order segment %q scheduled a highlight", ev.Text)
+		}
+	}
+	cmd := m.handleEvent(segmentEvent("s1", 3, "three"))
+	if got := entryTexts(m); got != "one two three four" {
+		t.Fatalf("entries = %q", got)
+	}
+	if cmd == nil {
+		t.Fatal("backfill should schedule the highlight's expiry")
+	}
+	if _, ok := cmd().(BackfillHighlightMsg); !ok {
+		t.Error("highlight tick should deliver BackfillHighlightMsg")
+	}
+	if !m.backfillHighlighted(m.entries[2]) || m.backfillHighlighted(m.entries[3]) {
+		t.Error("only the backfilled entry should be highlighted")
+	}
+	c.advance(backfillHighlightTTL)
+	if m.backfillHighlighted(m.entries[2]) {
+		t.Error("highlight should expire after backfillHighlightTTL")
+	}
+}
+
+func TestRedeliveredSegmentReplacesCopy(t *testing.T) {
+	m := New()
+	m.handleEvent(segmentEvent("s1", 1, "one"))
+	m.handleEvent(segmentEvent("s1", 2, "two"))
+	m.handleEvent(segmentEvent("s1", 1, "one, corrected"))
+	if got := entryTexts(m); got != "one, corrected two" {
+		t.Errorf("entries = %q", got)
+	}
+}
+
+func TestLateSegmentStaysInItsSession(t *testing.T) {
+	m := New()
+	m.connected = true
+	m.sessionID = "s1"
+	m.handleEvent(segmentEvent("s1", 1, "old one"))
+	m, _ = applyUpdate(m, DemarcateResponseMsg{Response: daemon.Response{OK: true, SessionID: "s2"}})
+	m.handleEvent(segmentEvent("s2", 1, "new one"))
+
+	// The old session's tail finalizes after the boundary.
+	m.handleEvent(segmentEvent("s1", 2, "old two"))
+	if got := entryTexts(m); got != "old one old two | new one" {
+		t.Errorf("entries = %q", got)
+	}
+	if !m.backfillHighlighted(m.entries[1]) {
+		t.Error("the late segment should be highlighted")
+	}
+}
+
+func TestSegmentWithoutSessionUsesCurrent(t *testing.T) {
+	m := New()
+	m.sessionID = "s1"
+	seq := 1
+	m.handleEvent(daemon.Event{Event: "segment", SequenceNumber: &seq, Text: "hi", Source: "microphone"})
+	if m.entries[0].SessionID != "s1" {
+		t.Errorf("SessionID = %q, want the current session", m.entries[0].SessionID)
+	}
+}
+
+func TestResumePointIsLastSegment(t *testing.T) {
+	m := New()
+	if id, seq := m.resumePoint(); id != "" || seq != 0 {
+		t.Errorf("empty transcript resumes from %q #%d", id, seq)
+	}
+	for _, ev := range []daemon.Event{segmentEvent("s1", 1, "one"), segmentEvent("s1", 3, "three"), segmentEvent("s1", 2, "two")} {
+		m.handleEvent(ev)
+	}
+	if id, seq := m.resumePoint(); id != "s1" || seq != 3 {
+		t.Errorf("resumePoint = %q #%d, want s1 #3", id, seq)
+	}
+	m.entries = append(m.entries, TranscriptEntry{IsBoundary: true})
+	if id, seq := m.resumePoint(); id != "s1" || seq != 3 {
+		t.Errorf("after a boundary, resumePoint = %q #%d, want s1 #3", id, seq)
+	}
+}
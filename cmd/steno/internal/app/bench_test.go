@@ -0,0 +1,91 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jwulff/steno/internal/daemon"
+	"github.com/jwulff/steno/internal/perf"
+)
+
+// benchModel returns a connected model with n transcript entries, sized
+// like a large terminal.
+func benchModel(n int) Model {
+	m := New()
+	m.connected = true
+	m.width, m.height = 200, 60
+	m.engineStatus = StatusRecording
+	base := time.Unix(1710000000, 0)
+	m.entries = make([]TranscriptEntry, 0, n)
+	for i := range n {
+		src := "microphone"
+		if i%3 == 0 {
+			src = "systemAudio"
+		}
+		m.entries = append(m.entries, TranscriptEntry{
+			Text:      fmt.Sprintf("Segment %d: we talked about the rollout plan and who owns the follow-up items for next week.", i),
+			Source:    src,
+			Timestamp: base.Add(time.Duration(i) * 5 * time.Second),
+			SeqNum:    i + 1,
+		})
+	}
+	m.partials["microphone"] = "and then the next thing we need to"
+	m.scrollToBottom()
+	return m
+}
+
+func BenchmarkView10kEntries(b *testing.B) {
+	m := benchModel(10_000)
+	b.ReportAllocs()
+	for b.Loop() {
+		_ = m.View()
+	}
+}
+
+// BenchmarkViewPartialUpdate measures the common frame: the partial grew
+// and nothing else changed, so the segments' cached lines are reused.
+func BenchmarkViewPartialUpdate(b *testing.B) {
+	m := benchModel(10_000)
+	_ = m.View()
+	words := strings.Fields("and then the next thing we need to talk about is the launch date")
+	b.ReportAllocs()
+	i := 0
+	for b.Loop() {
+		m.partials["microphone"] = strings.Join(words[:i%len(words)+1], " ")
+		_ = m.View()
+		i++
+	}
+}
+
+func BenchmarkWrapText(b *testing.B) {
+	text := strings.Repeat("the quick brown fox jumps over the lazy dog ", 40)
+	b.ReportAllocs()
+	for b.Loop() {
+		_ = wrapText(text, 80)
+	}
+}
+
+// BenchmarkHandleSegmentEvent measures the Update path for one streamed
+// segment landing on a model that already holds 10k entries.
+func BenchmarkHandleSegmentEvent(b *testing.B) {
+	m := benchModel(10_000)
+	seq := 10_001
+	b.ReportAllocs()
+	for b.Loop() {
+		ev := daemon.Event{Event: "segment", Text: "one more thing before we wrap", Source: "microphone", SequenceNumber: &seq}
+		// Update has a value receiver, so m keeps its 10k entries and
+		// every iteration inserts into the same-sized slice.
+		_, _ = m.Update(DaemonEventMsg{Event: ev})
+	}
+}
+
+func TestPerformanceBudgets(t *testing.T) {
+	perf.Check(t, []perf.Budget{
+		{Name: "View/10k entries", Bench: BenchmarkView10kEntries, Max: 250 * time.Millisecond},
+		{Name: "View/partial update, 10k entries", Bench: BenchmarkViewPartialUpdate, Max: 50 * time.Millisecond},
+		{Name: "wrapText", Bench: BenchmarkWrapText, Max: 100 * time.Microsecond},
+		{Name: "segment event/10k entries", Bench: BenchmarkHandleSegmentEvent, Max: 5 * time.Millisecond},
+	})
+}
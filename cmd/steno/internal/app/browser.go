@@ -0,0 +1,257 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jwulff/steno/internal/db"
+	"github.com/jwulff/steno/internal/ui"
+)
+
+// sessionBrowserLimit caps how many sessions the browser loads per filter.
+const sessionBrowserLimit = 50
+
+// sysAudioFilter is the browser's tri-state system-audio filter, cycled
+// by `a`: all → with system audio → mic only → all.
+type sysAudioFilter int
+
+const (
+	sysAudioAll sysAudioFilter = iota
+	sysAudioWith
+	sysAudioWithout
+)
+
+func (f sysAudioFilter) next() sysAudioFilter {
+	return (f + 1) % 3
+}
+
+func (f sysAudioFilter) label() string {
+	switch f {
+	case sysAudioWith:
+		return "with system audio"
+	case sysAudioWithout:
+		return "mic only"
+	default:
+		return "any"
+	}
+}
+
+func (f sysAudioFilter) value() *bool {
+	switch f {
+	case sysAudioWith:
+		v := true
+		return &v
+	case sysAudioWithout:
+		v := false
+		return &v
+	default:
+		return nil
+	}
+}
+
+// sessionBrowser is the state behind the `b` overlay.
+type sessionBrowser struct {
+	open     bool
+	sessions []SessionRow
+	devices  []string
+	selected int
+
+	// deviceIdx indexes devices; -1 means "any device".
+	deviceIdx int
+	sysAudio  sysAudioFilter
+}
+
+func (b sessionBrowser) deviceFilter() string {
+	if b.deviceIdx < 0 || b.deviceIdx >= len(b.devices) {
+		return ""
+	}
+	return b.devices[b.deviceIdx]
+}
+
+// loadSessionsCmd reads the browser's session list and device choices.
+func loadSessionsCmd(store *db.Store, device string, systemAudio *bool) tea.Cmd {
+	return func() tea.Msg {
+		sessions, err := store.FilterSessions(db.SessionFilter{
+			Limit:       sessionBrowserLimit,
+			Device:      device,
+			SystemAudio: systemAudio,
+		})
+		if err != nil {
+			return SessionsLoadedMsg{} // silently ignore DB errors
+		}
+		devices, _ := store.SessionDevices()
+		rows := sessionRows(sessions)
+		return SessionsLoadedMsg{Sessions: rows, Devices: devices}
+	}
+}
+
+// sessionRows converts store results into display rows.
+func sessionRows(sessions []db.SessionWithCounts) []SessionRow {
+	rows := make([]SessionRow, 0, len(sessions))
+	for _, swc := range sessions {
+		s := swc.Session
+		rows = append(rows, SessionRow{
+			ID:          s.ID,
+			Title:       s.Title,
+			Status:      s.Status,
+			Locale:      s.Locale,
+			Device:      s.Device,
+			SystemAudio: s.SystemAudio,
+			StartedAt:   s.StartedAt,
+			Segments:    swc.Counts.Segments,
+
+			ForegroundApp: s.ForegroundApp,
+			MeetingURL:    s.MeetingURL,
+		})
+	}
+	return rows
+}
+
+// recordSessionMetadataCmd persists the daemon-reported device,
+// system-audio flag and app (the call's, or when opted in the foreground
+// one) for the active session, so the browser can show and filter on them later. The daemon's
+// schema doesn't carry these, hence the client-owned session_metadata
+// table.
+func recordSessionMetadataCmd(store *db.Store, sessionID, device string, systemAudio bool, foregroundApp string, at time.Time) tea.Cmd {
+	return func() tea.Msg {
+		// Read-only stores (db.Open fallback) reject the write; the
+		// browser simply shows no device for this session.
+		_ = store.UpsertSessionMetadata(db.SessionMetadata{
+			SessionID:     sessionID,
+			Device:        device,
+			SystemAudio:   &systemAudio,
+			ForegroundApp: foregroundApp,
+			UpdatedAt:     at,
+		})
+		return nil
+	}
+}
+
+// metadataCmd returns recordSessionMetadataCmd for the current session
+// when everything it needs is known, or nil.
+func (m Model) metadataCmd() tea.Cmd {
+	if m.store == nil || m.sessionID == "" || m.deviceName == "" {
+		return nil
+	}
+	// A detected call tags its session whether or not foreground apps
+	// are recorded: the daemon's callDetection setting opted into it.
+	app := m.callApp
+	if app == "" && m.metadata.ForegroundApp {
+		app = m.foregroundApp
+	}
+	return recordSessionMetadataCmd(m.store, m.sessionID, m.deviceName, m.systemAudio, app, m.now())
+}
+
+// sessionContext renders a row's foreground app and meeting link, which
+// tell apart sessions with similar titles. The link drops its scheme.
+func sessionContext(s SessionRow) string {
+	var parts []string
+	if s.ForegroundApp != "" {
+		parts = append(parts, s.ForegroundApp)
+	}
+	if s.MeetingURL != "" {
+		u := strings.TrimPrefix(strings.TrimPrefix(s.MeetingURL, "https://"), "http://")
+		parts = append(parts, strings.TrimSuffix(u, "/"))
+	}
+	return strings.Join(parts, " · ")
+}
+
+func (m Model) reloadSessionsCmd() tea.Cmd {
+	if m.store == nil {
+		return nil
+	}
+	return loadSessionsCmd(m.store, m.browser.deviceFilter(), m.browser.sysAudio.value())
+}
+
+// handleBrowserKey handles keys while the session browser is open.
+func (m Model) handleBrowserKey(key string) (tea.Model, tea.Cmd) {
+	switch key {
+	case KeySessionBrowser, KeyEsc:
+		m.browser.open = false
+		return m, nil
+	case KeyQuit, KeyQuitUpper, KeyCtrlC:
+		return m.quit()
+	case KeyJ, KeyDown:
+		if m.browser.selected < len(m.browser.sessions)-1 {
+			m.browser.selected++
+		}
+		return m, nil
+	case KeyK, KeyUp:
+		if m.browser.selected > 0 {
+			m.browser.selected--
+		}
+		return m, nil
+	case KeyEnter:
+		if m.store == nil || m.browser.selected >= len(m.browser.sessions) {
+			return m, nil
+		}
+		s := m.browser.sessions[m.browser.selected]
+		return m, loadReplayCmd(m.store, s.ID, s.Title)
+	case KeyBrowserDeviceFilter:
+		// Cycle any → each recorded device → any.
+		m.browser.deviceIdx++
+		if m.browser.deviceIdx >= len(m.browser.devices) {
+			m.browser.deviceIdx = -1
+		}
+		m.browser.selected = 0
+		return m, m.reloadSessionsCmd()
+	case KeyBrowserSysAudioFilter:
+		m.browser.sysAudio = m.browser.sysAudio.next()
+		m.browser.selected = 0
+		return m, m.reloadSessionsCmd()
+	}
+	// Other keys are no-ops while the browser is open.
+	return m, nil
+}
+
+// renderSessionBrowser renders the `b` overlay in place of the main
+// content panels.
+func (m Model) renderSessionBrowser() string {
+	b := m.browser
+	device := b.deviceFilter()
+	if device == "" {
+		device = "any"
+	}
+
+	var lines []string
+	lines = append(lines, ui.PanelTitleActiveStyle.Render(fmt.Sprintf("Sessions (%d)", len(b.sessions))))
+	lines = append(lines, ui.DimStyle.Render(fmt.Sprintf("device: %s · system audio: %s", device, b.sysAudio.label())))
+
+	if m.store == nil {
+		lines = append(lines, ui.DimStyle.Render("No database available yet."))
+	} else if len(b.sessions) == 0 {
+		lines = append(lines, ui.DimStyle.Render("No sessions match."))
+	}
+
+	width := max(20, m.width-6)
+	for i, s := range b.sessions {
+		title := s.Title
+		if title == "" {
+			title = "(untitled)"
+		}
+		deviceName := s.Device
+		if deviceName == "" {
+			deviceName = "—"
+		}
+		sys := "   "
+		if s.SystemAudio != nil && *s.SystemAudio {
+			sys = "SYS"
+		}
+		if ctx := sessionContext(s); ctx != "" {
+			title += " · " + ctx
+		}
+		line := fmt.Sprintf("%s%s  %-6s  %s  %-24s  %s", ui.Marker(i == b.selected),
+			s.StartedAt.Format("2006-01-02 15:04"), s.Locale, sys,
+			truncateToWidth(deviceName, 24), title)
+		line = truncateToWidth(line, width)
+		if i == b.selected {
+			line = ui.SelectedStyle.Render(line)
+		}
+		lines = append(lines, line)
+	}
+
+	lines = append(lines, ui.DimStyle.Render("j/k move · enter replay · d device · a system audio · b or esc to close"))
+	return ui.SessionBrowserStyle.Render(strings.Join(lines, "\n"))
+}
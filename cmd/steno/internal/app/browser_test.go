@@ -0,0 +1,131 @@
+package app
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jwulff/steno/internal/daemon"
+)
+
+func runeKey(r rune) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}}
+}
+
+func TestSessionBrowserToggle(t *testing.T) {
+	m := New()
+	m.width, m.height = 120, 30
+
+	updated, _ := m.Update(runeKey('b'))
+	got := updated.(Model)
+	if !got.browser.open {
+		t.Fatal("browser should open on 'b'")
+	}
+	if !strings.Contains(got.View(), "No database available yet.") {
+		t.Error("browser without a store should say so")
+	}
+
+	updated, _ = got.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	got = updated.(Model)
+	if got.browser.open {
+		t.Error("browser should close on esc")
+	}
+}
+
+func TestSessionBrowserRendersMetadata(t *testing.T) {
+	m := New()
+	m.width, m.height = 140, 30
+	m.browser.open = true
+
+	yes := true
+	updated, _ := m.Update(SessionsLoadedMsg{
+		Sessions: []SessionRow{
+			{ID: "s1", Title: "Design review", Locale: "en_US", Device: "Conference Room", SystemAudio: &yes, StartedAt: time.Unix(1710000000, 0)},
+			{ID: "s2", Locale: "de_DE", StartedAt: time.Unix(1709990000, 0)},
+		},
+		Devices: []string{"Conference Room"},
+	})
+	got := updated.(Model)
+
+	view := got.renderSessionBrowser()
+	for _, want := range []string{"Sessions (2)", "Design review", "Conference Room", "SYS", "de_DE", "(untitled)"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("browser view missing %q:\n%s", want, view)
+		}
+	}
+}
+
+func TestSessionBrowserRendersContext(t *testing.T) {
+	m := New()
+	m.width, m.height = 160, 30
+	m.browser.open = true
+
+	updated, _ := m.Update(SessionsLoadedMsg{Sessions: []SessionRow{
+		{ID: "s1", Title: "Weekly sync", Locale: "en_US", StartedAt: time.Unix(1710000000, 0),
+			ForegroundApp: "zoom.us", MeetingURL: "https://zoom.example.com/j/123/"},
+		{ID: "s2", Title: "Weekly sync", Locale: "en_US", StartedAt: time.Unix(1709990000, 0),
+			ForegroundApp: "Google Chrome"},
+	}})
+	view := updated.(Model).renderSessionBrowser()
+	for _, want := range []string{"Weekly sync · zoom.us · zoom.example.com/j/123", "Weekly sync · Google Chrome"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("browser view missing %q:\n%s", want, view)
+		}
+	}
+}
+
+func TestStatusTracksSessionForegroundApp(t *testing.T) {
+	m := New()
+	m.connected = true
+
+	m, _ = applyUpdate(m, StatusResponseMsg{Response: daemon.Response{OK: true, SessionID: "s1", ForegroundApp: "zoom.us"}})
+	if m.foregroundApp != "zoom.us" {
+		t.Fatalf("foregroundApp = %q, want zoom.us", m.foregroundApp)
+	}
+	// A boundary opens a new session the old app doesn't describe.
+	m, _ = applyUpdate(m, DemarcateResponseMsg{Response: daemon.Response{OK: true, SessionID: "s2"}})
+	if m.foregroundApp != "" {
+		t.Errorf("foregroundApp = %q after demarcate, want empty", m.foregroundApp)
+	}
+}
+
+func TestSessionBrowserFilterCycling(t *testing.T) {
+	m := New()
+	m.browser.open = true
+	m.browser.devices = []string{"Mic A", "Mic B"}
+
+	updated, _ := m.Update(runeKey('d'))
+	got := updated.(Model)
+	if got.browser.deviceFilter() != "Mic A" {
+		t.Errorf("device filter = %q, want Mic A", got.browser.deviceFilter())
+	}
+	updated, _ = got.Update(runeKey('d'))
+	updated, _ = updated.(Model).Update(runeKey('d'))
+	got = updated.(Model)
+	if got.browser.deviceFilter() != "" {
+		t.Errorf("device filter should wrap to any, got %q", got.browser.deviceFilter())
+	}
+
+	updated, _ = got.Update(runeKey('a'))
+	got = updated.(Model)
+	if v := got.browser.sysAudio.value(); v == nil || !*v {
+		t.Errorf("system-audio filter = %v, want true", v)
+	}
+	updated, _ = got.Update(runeKey('a'))
+	got = updated.(Model)
+	if v := got.browser.sysAudio.value(); v == nil || *v {
+		t.Errorf("system-audio filter = %v, want false", v)
+	}
+}
+
+func TestSessionBrowserSwallowsGlobalKeys(t *testing.T) {
+	m := New()
+	m.browser.open = true
+	m.showSummary = false
+
+	updated, _ := m.Update(runeKey('s'))
+	if updated.(Model).showSummary {
+		t.Error("'s' should not toggle the summary while the browser is open")
+	}
+}
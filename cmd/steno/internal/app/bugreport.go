@@ -0,0 +1,101 @@
+package app
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jwulff/steno/internal/bugreport"
+	"github.com/jwulff/steno/internal/daemon"
+	"github.com/jwulff/steno/internal/db"
+	"github.com/jwulff/steno/internal/version"
+)
+
+// tuiState is the tui-state.json summary in a bug report: counts and
+// flags only, no transcript text.
+type tuiState struct {
+	Connected          bool     `json:"connected"`
+	Reconnecting       bool     `json:"reconnecting"`
+	ReconnectAttempt   int      `json:"reconnect_attempt"`
+	ConnError          string   `json:"conn_error,omitempty"`
+	EngineStatus       string   `json:"engine_status"`
+	Recording          bool     `json:"recording"`
+	SessionID          string   `json:"session_id,omitempty"`
+	Device             string   `json:"device,omitempty"`
+	SystemAudio        bool     `json:"system_audio"`
+	PausedIndefinitely bool     `json:"paused_indefinitely"`
+	PermissionRevoked  bool     `json:"permission_revoked"`
+	Entries            int      `json:"entries"`
+	Partials           int      `json:"partials"`
+	Topics             int      `json:"topics"`
+	HealMarkers        int      `json:"heal_markers"`
+	Density            string   `json:"density"`
+	Scrubbing          bool     `json:"scrubbing"`
+	StoreOpen          bool     `json:"store_open"`
+	Width              int      `json:"width"`
+	Height             int      `json:"height"`
+	Errors             []string `json:"errors,omitempty"`
+}
+
+// bugReport snapshots everything the report needs from the model. Slow
+// parts (log tail, schema query) run later in bugReportCmd.
+func (m Model) bugReport() bugreport.Report {
+	state := tuiState{
+		Connected:          m.connected,
+		Reconnecting:       m.reconnecting,
+		ReconnectAttempt:   m.reconnectAttempt,
+		ConnError:          m.reportScrubber.Apply(m.connError),
+		EngineStatus:       string(m.engineStatus),
+		Recording:          m.recording,
+		SessionID:          m.sessionID,
+		Device:             m.deviceName,
+		SystemAudio:        m.systemAudio,
+		PausedIndefinitely: m.pausedIndefinitely,
+		PermissionRevoked:  m.permissionRevoked,
+		Entries:            len(m.entries),
+		Partials:           len(m.partials),
+		Topics:             len(m.topics),
+		HealMarkers:        len(m.healMarkers),
+		Density:            m.density.String(),
+		Scrubbing:          m.scrubber != nil,
+		StoreOpen:          m.store != nil,
+		Width:              m.width,
+		Height:             m.height,
+	}
+	for _, e := range m.errorHistory {
+		state.Errors = append(state.Errors, e.Timestamp.Format(time.RFC3339)+" "+e.Severity.String()+" "+m.reportScrubber.Apply(e.Message))
+	}
+	return bugreport.Report{
+		CreatedAt: m.now(),
+		Versions: bugreport.Versions{
+			Steno:          version.Version,
+			StenoBuild:     version.Build(),
+			Daemon:         m.daemonVersion,
+			DaemonBuild:    m.daemonBuild,
+			Skew:           version.MinorSkew(version.Version, m.daemonVersion),
+			Protocol:       daemon.ProtocolVersion,
+			DaemonProtocol: m.daemonProtocol,
+		},
+		Messages: m.msgLog.Messages(),
+		TUIState: state,
+		Scrubber: m.reportScrubber,
+	}
+}
+
+// bugReportCmd fills in the daemon log and schema version and saves the
+// bundle under dir.
+func bugReportCmd(r bugreport.Report, store *db.Store, dir string) tea.Cmd {
+	return func() tea.Msg {
+		r.DaemonLog = daemon.NewManager().LogTail(bugreport.LogLines)
+		if store != nil {
+			if info, err := store.SchemaInfo(); err != nil {
+				r.SchemaError = err.Error()
+			} else {
+				r.Schema = &info
+			}
+		} else {
+			r.SchemaError = "database not open"
+		}
+		path, err := bugreport.Save(dir, r)
+		return BugReportSavedMsg{Path: path, Err: err}
+	}
+}
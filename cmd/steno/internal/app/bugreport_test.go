@@ -0,0 +1,48 @@
+package app
+
+import (
+	"archive/zip"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBugReportKeyWritesBundleWithoutTranscript(t *testing.T) {
+	m := New()
+	m.connected = true
+	m.entries = []TranscriptEntry{{Text: "confidential roadmap", Source: "microphone", Timestamp: time.Now(), SeqNum: 1}}
+	m.pushError(SeverityError, "permission denied for token sk-abcdefghijklmnopqrstuv", false)
+
+	msg := bugReportCmd(m.bugReport(), nil, t.TempDir())()
+	saved, ok := msg.(BugReportSavedMsg)
+	if !ok || saved.Err != nil {
+		t.Fatalf("bugReportCmd = %#v", msg)
+	}
+
+	zr, err := zip.OpenReader(saved.Path)
+	if err != nil {
+		t.Fatalf("open zip: %v", err)
+	}
+	defer zr.Close()
+	var state string
+	for _, f := range zr.File {
+		if f.Name == "tui-state.json" {
+			rc, _ := f.Open()
+			b, _ := io.ReadAll(rc)
+			rc.Close()
+			state = string(b)
+		}
+	}
+	if !strings.Contains(state, `"entries": 1`) {
+		t.Errorf("tui-state.json = %s", state)
+	}
+	if strings.Contains(state, "confidential") || strings.Contains(state, "sk-abcdef") {
+		t.Errorf("tui-state.json leaked text: %s", state)
+	}
+
+	m, _ = applyUpdate(m, saved)
+	if !strings.Contains(m.notice, saved.Path) {
+		t.Errorf("notice = %q", m.notice)
+	}
+}
@@ -0,0 +1,33 @@
+package app
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jwulff/steno/internal/daemon"
+)
+
+// callEvent handles `event:"call_started"` and `event:"call_ended"`:
+// the daemon saw a conferencing app start or stop using the mic (its
+// callDetection setting). With "auto" the daemon has already given the
+// call its own session; with "prompt" the TUI offers space, which splits
+// the session, and the new one is tagged with the call. Either way the
+// status refresh picks up the session and its call tag, which
+// metadataCmd records.
+func (m *Model) callEvent(ev daemon.Event) tea.Cmd {
+	if ev.App == "" {
+		return nil
+	}
+	var refresh tea.Cmd
+	if m.client != nil {
+		refresh = statusCmd(m.client)
+	}
+	if ev.Event == "call_ended" {
+		m.call = ""
+		return tea.Batch(m.pushError(SeverityInfo, ev.App+" call ended", true), refresh)
+	}
+	m.call = ev.App
+	msg := ev.App + " call started: press space to give it its own session"
+	if ev.AutoRecorded != nil && *ev.AutoRecorded {
+		msg = ev.App + " call started: recording it in its own session"
+	}
+	return tea.Batch(m.pushError(SeverityInfo, msg, true), m.alert(), refresh)
+}
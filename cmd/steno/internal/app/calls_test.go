@@ -0,0 +1,43 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/jwulff/steno/internal/config"
+	"github.com/jwulff/steno/internal/daemon"
+)
+
+func TestCallEvents(t *testing.T) {
+	m, bell, _ := alertModel(config.AlertsConfig{Bell: true})
+
+	runCmd(m.handleEvent(daemon.Event{Event: "call_started", App: "Zoom"}))
+	if m.call != "Zoom" || len(m.errorStack) != 1 || m.errorStack[0].Message != "Zoom call started: press space to give it its own session" {
+		t.Fatalf("call = %q, error stack = %+v", m.call, m.errorStack)
+	}
+	if bell.String() != "\a" {
+		t.Errorf("bell = %q, a call should alert", bell)
+	}
+
+	runCmd(m.handleEvent(daemon.Event{Event: "call_ended", App: "Zoom"}))
+	if m.call != "" || m.errorStack[len(m.errorStack)-1].Message != "Zoom call ended" {
+		t.Errorf("call = %q, error stack = %+v", m.call, m.errorStack)
+	}
+
+	auto := true
+	runCmd(m.handleEvent(daemon.Event{Event: "call_started", App: "Google Chrome", AutoRecorded: &auto}))
+	if got := m.errorStack[len(m.errorStack)-1].Message; got != "Google Chrome call started: recording it in its own session" {
+		t.Errorf("auto-recorded notice = %q", got)
+	}
+}
+
+func TestCallTagsSession(t *testing.T) {
+	m := New()
+	m, _ = applyUpdate(m, StatusResponseMsg{Response: daemon.Response{OK: true, SessionID: "s1", CallApp: "Zoom", ForegroundApp: "Finder"}})
+	if m.callApp != "Zoom" {
+		t.Fatalf("callApp = %q, want the status's", m.callApp)
+	}
+	m, _ = applyUpdate(m, StatusResponseMsg{Response: daemon.Response{OK: true, SessionID: "s2"}})
+	if m.callApp != "" {
+		t.Errorf("callApp = %q, a session outside a call has none", m.callApp)
+	}
+}
@@ -0,0 +1,51 @@
+package app
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Clock is the TUI's time source. Everything the model timestamps,
+// measures (pause countdown, recovery gap, last-segment age) or
+// schedules (status tick, notice expiry, reconnect backoff, replay) goes
+// through it, so tests can pin and advance time instead of racing the
+// wall clock.
+type Clock interface {
+	Now() time.Time
+	// Tick returns a command that delivers fn's message after d, like
+	// tea.Tick.
+	Tick(d time.Duration, fn func(time.Time) tea.Msg) tea.Cmd
+}
+
+// systemClock is the wall clock.
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+func (systemClock) Tick(d time.Duration, fn func(time.Time) tea.Msg) tea.Cmd {
+	return tea.Tick(d, fn)
+}
+
+// WithClock returns m with c as its time source.
+func (m Model) WithClock(c Clock) Model {
+	m.clock = c
+	return m
+}
+
+// now reads the model's clock. A Model built without New (replay's
+// private view, some tests) has none and uses the wall clock.
+func (m Model) now() time.Time {
+	if m.clock == nil {
+		return time.Now()
+	}
+	return m.clock.Now()
+}
+
+// tick schedules fn on the model's clock.
+func (m Model) tick(d time.Duration, fn func(time.Time) tea.Msg) tea.Cmd {
+	if m.clock == nil {
+		return tea.Tick(d, fn)
+	}
+	return m.clock.Tick(d, fn)
+}
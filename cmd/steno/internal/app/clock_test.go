@@ -0,0 +1,137 @@
+package app
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jwulff/steno/internal/daemon"
+)
+
+// manualClock is a Clock that only moves when told to. Its ticks fire
+// immediately, at the time they would have fired, and are recorded.
+type manualClock struct {
+	now   time.Time
+	ticks []time.Duration
+}
+
+func newManualClock() *manualClock {
+	return &manualClock{now: time.Date(2026, 3, 9, 14, 0, 0, 0, time.Local)}
+}
+
+func (c *manualClock) Now() time.Time { return c.now }
+
+func (c *manualClock) Tick(d time.Duration, fn func(time.Time) tea.Msg) tea.Cmd {
+	c.ticks = append(c.ticks, d)
+	at := c.now.Add(d)
+	return func() tea.Msg { return fn(at) }
+}
+
+func (c *manualClock) advance(d time.Duration) { c.now = c.now.Add(d) }
+
+func TestClockDrivesStatusBarTimers(t *testing.T) {
+	c := newManualClock()
+	m := New().WithClock(c)
+	m.connected = true
+	m.engineStatus = StatusRecording
+	m.width, m.height = 200, 24
+
+	m.handleEvent(daemon.Event{Event: "segment", Text: "hello", Source: "microphone"})
+	if !m.lastSegmentAt.Equal(c.now) {
+		t.Errorf("lastSegmentAt = %v, want the clock's %v", m.lastSegmentAt, c.now)
+	}
+	c.advance(65 * time.Second)
+	if got, _ := m.lastSegmentAnnotation(true); !strings.Contains(got, "last heard 65s ago") {
+		t.Errorf("annotation = %q", got)
+	}
+
+	expires := c.now.Add(30 * time.Minute)
+	m.engineStatus = StatusPaused
+	m.pauseExpiresAt = &expires
+	c.advance(10 * time.Minute)
+	if got, _ := m.statusLabel(); !strings.Contains(got, "resumes in 20:00") {
+		t.Errorf("pause label = %q", got)
+	}
+}
+
+func TestStatusSeedsSegmentActivity(t *testing.T) {
+	c := newManualClock()
+	m := New().WithClock(c)
+	m.connected = true
+	m.engineStatus = StatusRecording
+	m.width, m.height = 200, 24
+
+	// A TUI attached mid-session learns the count and last-heard time
+	// from status, before any segment event.
+	count, heard := 41, float64(c.now.Add(-45*time.Second).Unix())
+	m, _ = applyUpdate(m, StatusResponseMsg{Response: daemon.Response{
+		OK: true, SessionID: "s1", Status: "recording", Segments: &count, LastSegmentAt: &heard,
+	}})
+	if got, _ := m.lastSegmentAnnotation(true); !strings.Contains(got, "last heard 45s ago") {
+		t.Errorf("annotation = %q", got)
+	}
+	if !strings.Contains(m.View(), "TRANSCRIPT (41)") {
+		t.Error("transcript header should show the session's segment count")
+	}
+
+	seq := 42
+	m.handleEvent(daemon.Event{Event: "segment", Text: "hello", Source: "microphone", SessionID: "s1", SequenceNumber: &seq})
+	if m.segmentCount != 42 {
+		t.Errorf("segmentCount = %d after a live segment", m.segmentCount)
+	}
+	// A stale status can't move the indicator backwards.
+	m, _ = applyUpdate(m, StatusResponseMsg{Response: daemon.Response{OK: true, SessionID: "s1", LastSegmentAt: &heard}})
+	if !m.lastSegmentAt.Equal(c.now) {
+		t.Errorf("lastSegmentAt = %v, want the live segment's %v", m.lastSegmentAt, c.now)
+	}
+
+	c.advance(5 * time.Minute)
+	if got, _ := m.lastSegmentAnnotation(true); !strings.Contains(got, "last heard 5m ago") {
+		t.Errorf("annotation = %q", got)
+	}
+}
+
+func TestClockSchedulesTicks(t *testing.T) {
+	c := newManualClock()
+	m := New().WithClock(c)
+
+	if msg := m.statusTickCmd()(); msg != (StatusTickMsg{}) {
+		t.Errorf("status tick delivered %T", msg)
+	}
+	if msg := m.reconnectCmd(2)(); msg != (ReconnectTickMsg{}) {
+		t.Errorf("reconnect tick delivered %T", msg)
+	}
+	want := []time.Duration{time.Second, 4 * time.Second}
+	if len(c.ticks) != 2 || c.ticks[0] != want[0] || c.ticks[1] != want[1] {
+		t.Errorf("ticks = %v, want %v", c.ticks, want)
+	}
+}
+
+func TestClockStampsBoundariesAndErrors(t *testing.T) {
+	c := newManualClock()
+	m := New().WithClock(c)
+	m.connected = true
+
+	m, _ = applyUpdate(m, DemarcateResponseMsg{Response: daemon.Response{OK: true, SessionID: "s2"}})
+	m.pushError(SeverityError, "boom", false)
+	if len(m.entries) != 1 || !m.entries[0].Timestamp.Equal(c.now) {
+		t.Errorf("boundary = %+v, want stamped %v", m.entries, c.now)
+	}
+	if !m.errorHistory[0].Timestamp.Equal(c.now) {
+		t.Errorf("error stamped %v, want %v", m.errorHistory[0].Timestamp, c.now)
+	}
+}
+
+func TestReplayTicksOnModelClock(t *testing.T) {
+	c := newManualClock()
+	m := New().WithClock(c)
+	m.width, m.height = 120, 30
+
+	updated, cmd := m.Update(replayFixture())
+	m = updated.(Model)
+	msg := cmd().(ReplayTickMsg)
+	if !msg.At.Equal(c.now.Add(replayTickInterval)) {
+		t.Errorf("replay tick at %v, want clock + interval", msg.At)
+	}
+}
@@ -0,0 +1,80 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jwulff/steno/internal/daemon"
+)
+
+// frameInterval is how often a run of partial and level events reaches
+// Update: at most 15 times a second. The daemon sends dozens of level
+// events a second, and rendering each one kept a core busy on long
+// sessions.
+const frameInterval = time.Second / 15
+
+// coalesced reports whether ev only replaces what an earlier event of
+// its kind showed, so that only the latest of a run matters.
+func coalesced(ev daemon.Event) bool {
+	return ev.Event == "partial" || ev.Event == "level"
+}
+
+// eventBatch is a run of events read within one frame, with the partial
+// and level events folded into the latest of each.
+type eventBatch []daemon.Event
+
+// add folds ev into the batch: a partial replaces the same source's, and
+// a level event updates the meters of the last one.
+func (b eventBatch) add(ev daemon.Event) eventBatch {
+	for i, prev := range b {
+		switch {
+		case ev.Event == "partial" && prev.Event == "partial" && prev.Source == ev.Source:
+			b[i] = ev
+			return b
+		case ev.Event == "level" && prev.Event == "level":
+			if ev.Mic == nil {
+				ev.Mic = prev.Mic
+			}
+			if ev.Sys == nil {
+				ev.Sys = prev.Sys
+			}
+			b[i] = ev
+			return b
+		}
+	}
+	return append(b, ev)
+}
+
+// readEventCmd reads the next event from the event client. A partial
+// or level event waits out the rest of its frame for more, so a burst of
+// them arrives as one DaemonEventsMsg; any other event ends the batch,
+// after the ones before it.
+func readEventCmd(evClient *daemon.Client) tea.Cmd {
+	return func() tea.Msg {
+		ev, err := evClient.ReadEvent()
+		if err != nil {
+			return DaemonEventErrorMsg{Err: err}
+		}
+		if !coalesced(ev) {
+			return DaemonEventMsg{Event: ev}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), frameInterval)
+		defer cancel()
+		batch := eventBatch{ev}
+		for {
+			ev, err := evClient.ReadEventCtx(ctx)
+			if errors.Is(err, context.DeadlineExceeded) {
+				return DaemonEventsMsg{Events: batch}
+			}
+			if err != nil {
+				return DaemonEventsMsg{Events: batch, Err: err}
+			}
+			batch = batch.add(ev)
+			if !coalesced(ev) {
+				return DaemonEventsMsg{Events: batch}
+			}
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package app
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jwulff/steno/internal/daemon"
+)
+
+// streamEvents serves lines to one client on a fake daemon socket and
+// returns the client.
+func streamEvents(t *testing.T, lines string) *daemon.Client {
+	t.Helper()
+	sockPath := fmt.Sprintf("/tmp/steno-co-%d.sock", time.Now().UnixNano())
+	t.Cleanup(func() { os.Remove(sockPath) })
+	listener, err := net.Listen("unix", sockPath)
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		conn.Write([]byte(lines))
+		t.Cleanup(func() { conn.Close() })
+	}()
+	client, err := daemon.Connect(sockPath)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func TestReadEventCoalescesPartialsAndLevels(t *testing.T) {
+	var lines string
+	for i := range 40 {
+		lines += fmt.Sprintf(`{"event":"level","mic":0.%d}`+"\n", i%10)
+		lines += fmt.Sprintf(`{"event":"partial","source":"microphone","text":"word %d"}`+"\n", i)
+	}
+	lines += `{"event":"level","sys":0.5}` + "\n"
+	lines += `{"event":"partial","source":"systemAudio","text":"hello"}` + "\n"
+	lines += `{"event":"segment","source":"microphone","text":"word 39"}` + "\n"
+	lines += `{"event":"level","mic":0.2}` + "\n"
+	client := streamEvents(t, lines)
+
+	msg, ok := readEventCmd(client)().(DaemonEventsMsg)
+	if !ok {
+		t.Fatalf("msg = %T, want a batch", msg)
+	}
+	if len(msg.Events) != 4 || msg.Err != nil {
+		t.Fatalf("events = %+v, want a level, two partials and the segment", msg.Events)
+	}
+	level, mic, sys, seg := msg.Events[0], msg.Events[1], msg.Events[2], msg.Events[3]
+	if level.Mic == nil || *level.Mic != 0.9 || level.Sys == nil || *level.Sys != 0.5 {
+		t.Errorf("level = %+v, want the latest of each meter", level)
+	}
+	if mic.Text != "word 39" || sys.Text != "hello" {
+		t.Errorf("partials = %q, %q, want the latest of each source", mic.Text, sys.Text)
+	}
+	if seg.Event != "segment" {
+		t.Errorf("last = %+v, want the segment that ended the batch", seg)
+	}
+
+	// The lone level event after it waits out its frame.
+	start := time.Now()
+	msg, ok = readEventCmd(client)().(DaemonEventsMsg)
+	if !ok || len(msg.Events) != 1 || *msg.Events[0].Mic != 0.2 {
+		t.Fatalf("msg = %+v, want the last level event alone", msg)
+	}
+	if waited := time.Since(start); waited < frameInterval/2 {
+		t.Errorf("waited %v, want about a frame", waited)
+	}
+}
+
+func TestDaemonEventsMsgAppliesEachEvent(t *testing.T) {
+	m := New()
+	m.connected = true
+	mic := float32(0.4)
+	m, _ = applyUpdate(m, DaemonEventsMsg{Events: []daemon.Event{
+		{Event: "level", Mic: &mic},
+		{Event: "partial", Source: "microphone", Text: "so the plan"},
+		{Event: "segment", Source: "systemAudio", Text: "Sounds good."},
+	}})
+	if m.micLevel != mic || m.partials["microphone"] != "so the plan" {
+		t.Errorf("level = %v, partials = %v", m.micLevel, m.partials)
+	}
+	if got := entryTexts(m); got != "Sounds good." {
+		t.Errorf("entries = %q", got)
+	}
+}
@@ -0,0 +1,113 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jwulff/steno/internal/db"
+	"github.com/jwulff/steno/internal/ui"
+)
+
+// codesView is the `C` overlay: how often each code of the configured
+// codebook (coding.codes) has come up this session, for coding an
+// interview live. The counts are taken each time it's drawn, so they
+// keep up as segments arrive. Enter filters the transcript to the
+// selected code's terms; `steno codes` exports the counts as CSV.
+type codesView struct {
+	selected int
+}
+
+// openCodes opens the codebook counts.
+func (m Model) openCodes() (tea.Model, tea.Cmd) {
+	m.codes = &codesView{}
+	return m, nil
+}
+
+// handleCodesKey handles keys while the codebook counts are open:
+// up/down move, enter filters the transcript by the selected code, esc
+// or C closes.
+func (m Model) handleCodesKey(key string) (tea.Model, tea.Cmd) {
+	v := *m.codes
+	n := len(m.codebook.Names())
+	switch key {
+	case KeyQuit, KeyQuitUpper, KeyCtrlC:
+		return m.quit()
+	case KeyEsc, KeyCodes:
+		m.codes = nil
+		return m, nil
+	case KeyEnter:
+		if n == 0 {
+			return m, nil
+		}
+		m.codes = nil
+		m.filter = &transcriptFilter{word: m.codebook.Names()[v.selected], re: m.codebook.Pattern(v.selected)}
+		m.focusedPanel = FocusTranscript
+		m.transcriptLive = false
+		m.transcriptScroll = 0
+		return m, nil
+	case KeyUp, KeyK:
+		if v.selected > 0 {
+			v.selected--
+		}
+	case KeyDown, KeyJ:
+		if v.selected < n-1 {
+			v.selected++
+		}
+	}
+	m.codes = &v
+	return m, nil
+}
+
+// renderCodes renders each code's total, its count in the latest
+// interval and a sparkline of the intervals before, in place of the
+// main panels. Counts are of the scrubbed text, as shown.
+func (m Model) renderCodes() string {
+	width := max(20, m.width-6)
+	lines := []string{ui.PanelTitleActiveStyle.Render(fmt.Sprintf("Codes · this session · per %s", stepLabel(m.codingStep)))}
+	names := m.codebook.Names()
+	if len(names) == 0 {
+		lines = append(lines, ui.DimStyle.Render("No codes configured. Add them to coding.codes in config.json."), "",
+			ui.DimStyle.Render("esc close"))
+		return ui.SessionBrowserStyle.Render(strings.Join(lines, "\n"))
+	}
+
+	var segments []db.Segment
+	for _, e := range m.entries {
+		if !e.IsBoundary && (e.SessionID == "" || e.SessionID == m.sessionID) {
+			segments = append(segments, db.Segment{StartedAt: e.Timestamp, Text: m.scrubber.Apply(e.Text)})
+		}
+	}
+	series := m.codebook.Series(segments, m.codingStep)
+	totals := series.Totals()
+	nameW := 0
+	for _, name := range names {
+		nameW = max(nameW, len([]rune(name)))
+	}
+	for c, name := range names {
+		latest := 0
+		if len(series.Counts) > 0 {
+			latest = series.Counts[len(series.Counts)-1][c]
+		}
+		counts := fmt.Sprintf("%4d  +%-3d ", totals[c], latest)
+		label := ui.Marker(false) + padRight(name, nameW)
+		if c == m.codes.selected {
+			label = ui.SelectedStyle.Render(ui.Marker(true) + padRight(name, nameW))
+		}
+		spark := series.Sparkline(c, max(1, width-nameW-len(counts)-4))
+		lines = append(lines, truncateToWidth(label+"  "+counts+ui.FooterKeyStyle.Render(spark), width))
+	}
+
+	lines = append(lines, "", ui.DimStyle.Render("total · latest interval · over time  ↑/↓ move · enter show in transcript · esc close"))
+	return ui.SessionBrowserStyle.Render(strings.Join(lines, "\n"))
+}
+
+// stepLabel formats an interval as it would be written in the config,
+// "5m" rather than "5m0s".
+func stepLabel(d time.Duration) string {
+	if d >= time.Minute && d%time.Minute == 0 {
+		return fmt.Sprintf("%dm", d/time.Minute)
+	}
+	return d.String()
+}
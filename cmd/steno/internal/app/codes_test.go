@@ -0,0 +1,57 @@
+package app
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jwulff/steno/internal/config"
+)
+
+func TestCodesCountLive(t *testing.T) {
+	m := NewWithConfig(config.Config{Coding: config.CodingConfig{Codes: []config.CodeConfig{
+		{Name: "trust", Terms: []string{"trust*"}},
+		{Name: "cost", Terms: []string{"price"}},
+	}}})
+	m.width, m.height = 120, 30
+	start := time.Unix(1700000000, 0)
+	m.entries = []TranscriptEntry{
+		{Text: "I trust them.", Timestamp: start, SeqNum: 1},
+		{Text: "The price, though.", Timestamp: start.Add(30 * time.Second), SeqNum: 2},
+	}
+	m, _ = applyUpdate(m, runeKey('C'))
+	if m.codes == nil {
+		t.Fatal("C should open the codes view")
+	}
+	view := m.View()
+	if !strings.Contains(view, "per 1m") || !strings.Contains(view, "trust     1  +1") {
+		t.Errorf("codes view:\n%s", view)
+	}
+
+	// A segment arriving while the view is open is counted.
+	m.entries = append(m.entries, TranscriptEntry{Text: "They trusted us.", Timestamp: start.Add(90 * time.Second), SeqNum: 3})
+	if view := m.View(); !strings.Contains(view, "trust     2  +1") || !strings.Contains(view, "cost      1  +0") {
+		t.Errorf("codes view after a segment:\n%s", view)
+	}
+
+	m, _ = applyUpdate(m, runeKey('j'))
+	m, _ = applyUpdate(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.codes != nil || m.filter == nil || m.filter.word != "cost" || !m.filter.re.MatchString("the PRICE") {
+		t.Errorf("filter = %+v, want the transcript filtered to cost", m.filter)
+	}
+}
+
+func TestCodesWithoutCodebook(t *testing.T) {
+	m := New()
+	m.width, m.height = 120, 30
+	m, _ = applyUpdate(m, runeKey('C'))
+	if view := m.View(); !strings.Contains(view, "No codes configured") {
+		t.Errorf("codes view:\n%s", view)
+	}
+	m, _ = applyUpdate(m, tea.KeyMsg{Type: tea.KeyEnter})
+	m, _ = applyUpdate(m, runeKey('C'))
+	if m.codes != nil {
+		t.Error("C should close the codes view")
+	}
+}
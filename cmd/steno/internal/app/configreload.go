@@ -0,0 +1,217 @@
+package app
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jwulff/steno/internal/bugreport"
+	"github.com/jwulff/steno/internal/config"
+	"github.com/jwulff/steno/internal/scrub"
+	"github.com/jwulff/steno/internal/ui"
+)
+
+// configPollInterval is how often the config file is checked for edits.
+// Polling the file's size and modification time needs no watcher
+// dependency, and a couple of seconds is prompt enough for a settings
+// change.
+const configPollInterval = 2 * time.Second
+
+// configWatch follows the config file the TUI was started with, so edits
+// apply mid-meeting without a restart. See applyConfig for what reloads.
+type configWatch struct {
+	path    string
+	modTime time.Time
+	size    int64
+	// cfg is the config in effect.
+	cfg config.Config
+	// noColor and forceScrub are the command-line flags, which outrank
+	// the file.
+	noColor    bool
+	forceScrub bool
+}
+
+// WithConfigWatch returns m following the config file at path, which cfg
+// was loaded from. noColor and forceScrub are `--no-color` (or NO_COLOR)
+// and `--scrub`, which a reload can't turn off.
+func (m Model) WithConfigWatch(path string, cfg config.Config, noColor, forceScrub bool) Model {
+	w := &configWatch{path: path, cfg: cfg, noColor: noColor, forceScrub: forceScrub}
+	if fi, err := os.Stat(path); err == nil {
+		w.modTime, w.size = fi.ModTime(), fi.Size()
+	}
+	m.configWatch = w
+	return m
+}
+
+// configTickMsg schedules the next config file check.
+type configTickMsg struct{}
+
+// configWatchCmd schedules a config file check, or nil when not
+// watching.
+func (m Model) configWatchCmd() tea.Cmd {
+	if m.configWatch == nil {
+		return nil
+	}
+	return m.tick(configPollInterval, func(time.Time) tea.Msg { return configTickMsg{} })
+}
+
+// checkConfigCmd reloads the config file at path if its size or
+// modification time differ from those given. A removed file reloads as
+// the defaults.
+func checkConfigCmd(path string, modTime time.Time, size int64) tea.Cmd {
+	return func() tea.Msg {
+		msg := ConfigCheckedMsg{}
+		fi, err := os.Stat(path)
+		if err == nil {
+			msg.ModTime, msg.Size = fi.ModTime(), fi.Size()
+		} else if !os.IsNotExist(err) {
+			return msg
+		}
+		if msg.ModTime.Equal(modTime) && msg.Size == size {
+			return msg
+		}
+		msg.Changed = true
+		msg.Config, msg.Err = config.Load(path)
+		return msg
+	}
+}
+
+// handleConfigChecked applies a changed config file and schedules the
+// next check. An invalid file is reported and the config in effect
+// kept, so a half-saved edit doesn't undo the settings in use.
+func (m Model) handleConfigChecked(msg ConfigCheckedMsg) (tea.Model, tea.Cmd) {
+	w := *m.configWatch
+	next := m.configWatchCmd()
+	if !msg.Changed {
+		return m, next
+	}
+	w.modTime, w.size = msg.ModTime, msg.Size
+	m.configWatch = &w
+	if msg.Err != nil {
+		return m, tea.Batch(next, m.pushError(SeverityWarn, msg.Err.Error()+"; keeping the settings in use", true))
+	}
+	changed, cmd := m.applyConfig(msg.Config)
+	if len(changed) == 0 {
+		return m, next
+	}
+	m.notice = "Config reloaded: " + strings.Join(changed, ", ") + " changed"
+	return m, tea.Batch(next, cmd, m.clearNoticeCmd())
+}
+
+// applyConfig puts cfg in effect and returns the sections that changed,
+// in file order. The scrub patterns, palette, alerts, export defaults,
+// metadata, consent, dictation and coding settings apply at once, and
+// capture options from the next start, but for partial drafts, which
+// also apply at once. Density, reading mode and the
+// ticker are only where the TUI starts, and the database options are
+// fixed once the store is open, so those are marked as waiting for a
+// restart. `steno maintain` reads maintenance on each run.
+func (m *Model) applyConfig(cfg config.Config) ([]string, tea.Cmd) {
+	w := *m.configWatch
+	old := w.cfg
+	if w.forceScrub {
+		cfg.Scrub.Enabled = true
+	}
+	w.cfg = cfg
+	m.configWatch = &w
+
+	var changed []string
+	var cmd tea.Cmd
+	if !reflect.DeepEqual(old.Scrub, cfg.Scrub) {
+		changed = append(changed, "scrub")
+		scrubber, err := cfg.Scrub.Scrubber()
+		if err != nil {
+			scrubber, _ = scrub.New(scrub.DefaultPatterns, cfg.Scrub.Replacement)
+		}
+		m.scrubber = scrubber
+		if rs, err := bugreport.NewScrubber(cfg.Scrub.Patterns); err == nil {
+			m.reportScrubber = rs
+		}
+	}
+	if !reflect.DeepEqual(old.Capture, cfg.Capture) {
+		changed = append(changed, "capture")
+		m.capture = cfg.Capture
+		m.drafts.enabled = cfg.Capture.PartialDrafts
+		if !old.Capture.PartialDrafts && cfg.Capture.PartialDrafts {
+			if m.drafts.loaded {
+				cmd = m.draftTickCmd()
+			} else {
+				cmd = m.draftsCmd()
+			}
+		}
+	}
+	if !reflect.DeepEqual(old.Display, cfg.Display) {
+		if old.Display.Palette != cfg.Display.Palette || old.Display.NoColor != cfg.Display.NoColor ||
+			!reflect.DeepEqual(old.Display.Palettes, cfg.Display.Palettes) {
+			changed = append(changed, "display")
+			m.applyPalette(cfg.Display)
+		} else {
+			changed = append(changed, "display (on restart)")
+		}
+	}
+	if !reflect.DeepEqual(old.Metadata, cfg.Metadata) {
+		changed = append(changed, "metadata")
+		m.metadata = cfg.Metadata
+	}
+	if !reflect.DeepEqual(old.Export, cfg.Export) {
+		changed = append(changed, "export")
+		m.exportConfig = cfg.Export
+	}
+	if !reflect.DeepEqual(old.Maintenance, cfg.Maintenance) {
+		changed = append(changed, "maintenance")
+	}
+	if !reflect.DeepEqual(old.Alerts, cfg.Alerts) {
+		changed = append(changed, "alerts")
+		m.alerts = cfg.Alerts
+	}
+	if !reflect.DeepEqual(old.Consent, cfg.Consent) {
+		changed = append(changed, "consent")
+		m.consent = cfg.Consent
+	}
+	if !reflect.DeepEqual(old.Dictation, cfg.Dictation) {
+		changed = append(changed, "dictation")
+		m.dictationConfig = cfg.Dictation
+		if old.Dictation.ProfileName() != cfg.Dictation.ProfileName() {
+			cmd = tea.Batch(cmd, m.dictationCmd())
+		}
+	}
+	if !reflect.DeepEqual(old.Coding, cfg.Coding) {
+		changed = append(changed, "coding")
+		if cb, err := cfg.Coding.Codebook(); err == nil {
+			m.codebook = cb
+		}
+		if step, err := cfg.Coding.Step(); err == nil {
+			m.codingStep = step
+		}
+		if m.codes != nil {
+			m.codes.selected = 0
+		}
+	}
+	if !reflect.DeepEqual(old.Database, cfg.Database) {
+		changed = append(changed, "database (on restart)")
+	}
+	return changed, cmd
+}
+
+// applyPalette redefines the custom palettes and rebuilds the styles for
+// display, unless color is off on the command line. Lines already laid
+// out were styled with the old palette, so the layout cache starts over.
+func (m *Model) applyPalette(display config.DisplayConfig) {
+	if palettes, err := display.CustomPalettes(); err == nil { // validated by config.Load
+		ui.DefinePalettes(palettes)
+	}
+	if m.configWatch.noColor {
+		return
+	}
+	switch {
+	case display.NoColor:
+		ui.DisableColor()
+	case display.Palette != "":
+		ui.UsePalette(display.Palette) // validated by config.Load
+	default:
+		ui.UsePalette(ui.DefaultPalette)
+	}
+	m.segLines = newSegmentLines()
+}
@@ -0,0 +1,80 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jwulff/steno/internal/config"
+)
+
+// writeConfig writes body to path and moves its modification time on, so
+// a rewrite within the clock's resolution still reads as an edit.
+func writeConfig(t *testing.T, path, body string, at time.Time) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, at, at); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestConfigReloadAppliesChanges(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	start := time.Unix(1700000000, 0)
+	writeConfig(t, path, `{"alerts": {"bell": true}}`, start)
+	cfg, err := config.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := NewWithConfig(cfg).WithConfigWatch(path, cfg, true, false)
+	w := m.configWatch
+
+	if msg := checkConfigCmd(path, w.modTime, w.size)().(ConfigCheckedMsg); msg.Changed {
+		t.Error("an untouched file shouldn't reload")
+	}
+
+	writeConfig(t, path, `{"alerts": {"flash": true}, "export": {"chapters_by": "interval"}, "database": {"busy_timeout": "10s"}}`, start.Add(time.Second))
+	m, _ = applyUpdate(m, checkConfigCmd(path, w.modTime, w.size)())
+	if m.alerts.Bell || !m.alerts.Flash {
+		t.Errorf("alerts = %+v, want flash only", m.alerts)
+	}
+	if m.exportConfig.ChaptersBy != "interval" {
+		t.Errorf("export = %+v, want interval chapters", m.exportConfig)
+	}
+	if want := "Config reloaded: export, alerts, database (on restart) changed"; m.notice != want {
+		t.Errorf("notice = %q, want %q", m.notice, want)
+	}
+	if !m.configWatch.modTime.Equal(start.Add(time.Second)) {
+		t.Errorf("modTime = %v, want the rewrite's", m.configWatch.modTime)
+	}
+}
+
+func TestConfigReloadKeepsSettingsOnError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	start := time.Unix(1700000000, 0)
+	writeConfig(t, path, `{"alerts": {"bell": true}}`, start)
+	cfg, _ := config.Load(path)
+	m := NewWithConfig(cfg).WithConfigWatch(path, cfg, true, true)
+	w := m.configWatch
+
+	writeConfig(t, path, `{"alerts": {"bell": fal`, start.Add(time.Second))
+	m, _ = applyUpdate(m, checkConfigCmd(path, w.modTime, w.size)())
+	if !m.alerts.Bell {
+		t.Error("an invalid file shouldn't change the alerts in use")
+	}
+	if !strings.Contains(m.errorMessage(), "keeping the settings in use") {
+		t.Errorf("errorMessage = %q, want the invalid file reported", m.errorMessage())
+	}
+
+	// --scrub outranks a file that turns scrubbing off.
+	writeConfig(t, path, `{"scrub": {"enabled": false}}`, start.Add(2*time.Second))
+	w = m.configWatch
+	m, _ = applyUpdate(m, checkConfigCmd(path, w.modTime, w.size)())
+	if !m.configWatch.cfg.Scrub.Enabled {
+		t.Error("--scrub should still apply after a reload")
+	}
+}
@@ -0,0 +1,117 @@
+package app
+
+import (
+	"os/exec"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jwulff/steno/internal/db"
+	"github.com/jwulff/steno/internal/ui"
+)
+
+// consentState is what the TUI knows about the current session's
+// recording-consent acknowledgment (config `consent`). With the reminder
+// on, every new session shows a banner carrying the announcement until
+// the user presses c to confirm the others were told; the time is
+// recorded in session_metadata. The banner shows before the lookup
+// returns: when in doubt, over-disclose.
+type consentState struct {
+	// sessionID is the session the rest describes; stale once the
+	// current session changes.
+	sessionID    string
+	acknowledged bool
+	// copied is set once the announcement is on the clipboard.
+	copied bool
+}
+
+// copyToClipboard puts text on the system clipboard. A variable so tests
+// don't touch the real one.
+var copyToClipboard = func(text string) error {
+	cmd := exec.Command("pbcopy")
+	cmd.Stdin = strings.NewReader(text)
+	return cmd.Run()
+}
+
+// loadConsentCmd reads whether consent was already acknowledged for
+// sessionID, e.g. before the TUI restarted.
+func loadConsentCmd(store *db.Store, sessionID string) tea.Cmd {
+	return func() tea.Msg {
+		at, err := store.ConsentAcknowledgedAt(sessionID)
+		return ConsentLoadedMsg{SessionID: sessionID, Acknowledged: at != nil, Err: err}
+	}
+}
+
+// acknowledgeConsentCmd records the acknowledgment.
+func acknowledgeConsentCmd(store *db.Store, sessionID string, at time.Time) tea.Cmd {
+	return func() tea.Msg {
+		return ConsentAcknowledgedMsg{Err: store.AcknowledgeConsent(sessionID, at)}
+	}
+}
+
+// copyAnnouncementCmd copies the announcement for sessionID.
+func copyAnnouncementCmd(sessionID, text string) tea.Cmd {
+	return func() tea.Msg {
+		return AnnouncementCopiedMsg{SessionID: sessionID, Err: copyToClipboard(text)}
+	}
+}
+
+// consentCmd returns loadConsentCmd for the current session until its
+// state has loaded, or nil. Nothing is looked up with the reminder off.
+func (m Model) consentCmd() tea.Cmd {
+	if !m.consent.Reminder || m.store == nil || m.sessionID == "" || m.consentState.sessionID == m.sessionID {
+		return nil
+	}
+	return loadConsentCmd(m.store, m.sessionID)
+}
+
+// applyConsent records a lookup for the current session and, when the
+// banner stays up, copies the announcement if configured. Only the first
+// lookup for a session counts, so the copy happens once.
+func (m *Model) applyConsent(msg ConsentLoadedMsg) tea.Cmd {
+	if msg.SessionID != m.sessionID || m.consentState.sessionID == msg.SessionID {
+		return nil
+	}
+	m.consentState = consentState{sessionID: msg.SessionID, acknowledged: msg.Acknowledged}
+	if msg.Acknowledged || !m.consent.CopyAnnouncement {
+		return nil
+	}
+	return copyAnnouncementCmd(msg.SessionID, m.consent.Text())
+}
+
+// consentPending reports whether the banner is up for the current
+// session.
+func (m Model) consentPending() bool {
+	if !m.consent.Reminder || m.sessionID == "" {
+		return false
+	}
+	return m.consentState.sessionID != m.sessionID || !m.consentState.acknowledged
+}
+
+// acknowledgeConsent handles c: it dismisses the banner and records the
+// acknowledgment. The key does nothing while no banner is up.
+func (m Model) acknowledgeConsent() (tea.Model, tea.Cmd) {
+	if !m.consentPending() {
+		return m, nil
+	}
+	copied := m.consentState.sessionID == m.sessionID && m.consentState.copied
+	m.consentState = consentState{sessionID: m.sessionID, acknowledged: true, copied: copied}
+	if m.store == nil {
+		return m, m.pushError(SeverityWarn, "consent acknowledgment not saved: no database", true)
+	}
+	m.notice = "Consent acknowledged"
+	return m, tea.Batch(acknowledgeConsentCmd(m.store, m.sessionID, m.now()), m.clearNoticeCmd())
+}
+
+// renderConsentBanner shows the reminder and the announcement to read or
+// paste.
+func (m Model) renderConsentBanner() string {
+	w := max(20, m.width-4)
+	label := "Announcement"
+	if m.consentState.sessionID == m.sessionID && m.consentState.copied {
+		label += " (copied to clipboard)"
+	}
+	lines := wrapText("Recording consent: let everyone know this meeting is being transcribed. Press c once they have been told.", w)
+	lines = append(lines, wrapText(label+": “"+m.consent.Text()+"”", w)...)
+	return ui.ConsentBannerStyle.Render(strings.Join(lines, "\n"))
+}
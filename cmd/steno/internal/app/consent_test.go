@@ -0,0 +1,111 @@
+package app
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jwulff/steno/internal/config"
+)
+
+func consentModel(t *testing.T, cfg config.ConsentConfig) Model {
+	m := testModel(t, withSize(160, 30))
+	m.consent = cfg
+	m.sessionID = "sess-1"
+	return m
+}
+
+func TestConsentBannerUntilAcknowledged(t *testing.T) {
+	m := consentModel(t, config.ConsentConfig{Reminder: true, Announcement: "We transcribe this call."})
+	if !strings.Contains(m.View(), "We transcribe this call.") {
+		t.Fatal("banner should show before the lookup returns")
+	}
+	if m.consentCmd() == nil {
+		t.Error("expected a lookup for the new session")
+	}
+	m, cmd := applyUpdate(m, ConsentLoadedMsg{SessionID: "sess-1"})
+	if cmd != nil {
+		t.Error("nothing to copy without copy_announcement")
+	}
+	if m.consentCmd() != nil {
+		t.Error("loaded session shouldn't be looked up again")
+	}
+
+	m, cmd = applyUpdate(m, runeKey('c'))
+	if cmd == nil {
+		t.Error("acknowledging should record it")
+	}
+	if m.consentPending() || strings.Contains(m.View(), "Recording consent") {
+		t.Error("banner should close once acknowledged")
+	}
+	if _, cmd := applyUpdate(m, runeKey('c')); cmd != nil {
+		t.Error("c without a banner should do nothing")
+	}
+
+	// The next session asks again.
+	m.sessionID = "sess-2"
+	if !m.consentPending() {
+		t.Error("a new session should show the banner")
+	}
+}
+
+func TestConsentAlreadyAcknowledged(t *testing.T) {
+	m := consentModel(t, config.ConsentConfig{Reminder: true, CopyAnnouncement: true})
+	m, cmd := applyUpdate(m, ConsentLoadedMsg{SessionID: "sess-1", Acknowledged: true})
+	if cmd != nil || m.consentPending() {
+		t.Error("an acknowledged session shouldn't show the banner or copy")
+	}
+
+	// A lookup for a session that has since ended is dropped.
+	m.sessionID = "sess-2"
+	m, _ = applyUpdate(m, ConsentLoadedMsg{SessionID: "sess-1", Acknowledged: true})
+	if !m.consentPending() {
+		t.Error("stale lookup dismissed the banner")
+	}
+}
+
+func TestConsentCopiesAnnouncement(t *testing.T) {
+	var copied []string
+	orig := copyToClipboard
+	t.Cleanup(func() { copyToClipboard = orig })
+	copyToClipboard = func(text string) error {
+		copied = append(copied, text)
+		return nil
+	}
+
+	m := consentModel(t, config.ConsentConfig{Reminder: true, CopyAnnouncement: true})
+	m, cmd := applyUpdate(m, ConsentLoadedMsg{SessionID: "sess-1"})
+	if cmd == nil {
+		t.Fatal("expected the announcement to be copied")
+	}
+	m, _ = applyUpdate(m, cmd())
+	if len(copied) != 1 || copied[0] != config.DefaultConsentAnnouncement {
+		t.Errorf("copied %q, want the default announcement once", copied)
+	}
+	if !strings.Contains(m.renderConsentBanner(), "copied to clipboard") {
+		t.Error("banner should say the announcement was copied")
+	}
+
+	// A second lookup in flight for the same session doesn't copy again.
+	if _, cmd := applyUpdate(m, ConsentLoadedMsg{SessionID: "sess-1"}); cmd != nil {
+		t.Error("copied twice")
+	}
+}
+
+func TestConsentCopyFailureWarns(t *testing.T) {
+	m := consentModel(t, config.ConsentConfig{Reminder: true, CopyAnnouncement: true})
+	m, _ = applyUpdate(m, AnnouncementCopiedMsg{SessionID: "sess-1", Err: errors.New("pbcopy not found")})
+	if len(m.errorStack) != 1 || !strings.Contains(m.errorStack[0].Message, "pbcopy not found") {
+		t.Errorf("errorStack = %+v", m.errorStack)
+	}
+	if strings.Contains(m.renderConsentBanner(), "copied") {
+		t.Error("banner claims a failed copy")
+	}
+}
+
+func TestConsentReminderOff(t *testing.T) {
+	m := consentModel(t, config.ConsentConfig{})
+	if m.consentPending() || m.consentCmd() != nil {
+		t.Error("no banner or lookup with the reminder off")
+	}
+}
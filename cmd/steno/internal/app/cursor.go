@@ -0,0 +1,148 @@
+package app
+
+// transcriptCursor marks "the current segment" in the transcript panel:
+// the one that segment actions (copy, bookmark, permalink, ...) act on.
+// It is independent of the scroll position, except that moving it
+// scrolls it into view. It names the segment rather than an index into
+// m.entries, which shifts as late segments are backfilled above it.
+type transcriptCursor struct {
+	sessionID string
+	seq       int
+}
+
+// selectable reports whether the cursor may rest on e: a segment shown
+// under the current filter.
+func (m Model) selectable(e TranscriptEntry) bool {
+	return !e.IsBoundary && e.SeqNum != 0 && (m.filter == nil || m.filter.matches(e))
+}
+
+// cursorIndex is the cursor's index in m.entries, or -1 when there is no
+// cursor or its segment is no longer shown.
+func (m Model) cursorIndex() int {
+	if m.cursor == nil {
+		return -1
+	}
+	for i, e := range m.entries {
+		if e.SessionID == m.cursor.sessionID && e.SeqNum == m.cursor.seq {
+			if !m.selectable(e) {
+				return -1
+			}
+			return i
+		}
+	}
+	return -1
+}
+
+// cursorEntry returns the segment under the cursor, for the actions that
+// operate on it.
+func (m Model) cursorEntry() (TranscriptEntry, bool) {
+	if i := m.cursorIndex(); i >= 0 {
+		return m.entries[i], true
+	}
+	return TranscriptEntry{}, false
+}
+
+// moveCursor moves the cursor delta segments (j/k while the transcript
+// has focus) and scrolls it into view. Without a cursor, the first move
+// lands on the newest segment in view.
+func (m *Model) moveCursor(delta int) {
+	i := m.cursorIndex()
+	if i < 0 {
+		i = m.lastSegmentInView()
+	} else {
+		for step := delta; step != 0; {
+			next := i + sign(step)
+			for next >= 0 && next < len(m.entries) && !m.selectable(m.entries[next]) {
+				next += sign(step)
+			}
+			if next < 0 || next >= len(m.entries) {
+				break
+			}
+			i = next
+			step -= sign(step)
+		}
+	}
+	if i < 0 {
+		return
+	}
+	m.cursor = &transcriptCursor{sessionID: m.entries[i].SessionID, seq: m.entries[i].SeqNum}
+	m.revealCursor()
+}
+
+// cursorToNewest puts the cursor on the newest segment (G), when there
+// is a cursor to move.
+func (m *Model) cursorToNewest() {
+	if m.cursor == nil {
+		return
+	}
+	m.cursor = nil
+	for i := len(m.entries) - 1; i >= 0; i-- {
+		if m.selectable(m.entries[i]) {
+			m.cursor = &transcriptCursor{sessionID: m.entries[i].SessionID, seq: m.entries[i].SeqNum}
+			return
+		}
+	}
+}
+
+// transcriptViewport returns the laid-out transcript lines with their
+// entry spans, the first line in view and the number of lines in view.
+func (m Model) transcriptViewport() (lines []string, spans map[int][2]int, start, height int) {
+	lines, spans = m.transcriptDisplayLines(m.transcriptPanelWidth())
+	height = m.transcriptVisibleLines() - m.transcriptHeaderLines()
+	if m.transcriptLive {
+		start = max(0, len(lines)-height)
+	} else {
+		start = m.transcriptScroll
+	}
+	return lines, spans, start, height
+}
+
+// lastSegmentInView is the index of the newest selectable segment whose
+// first line is in view, else the newest selectable one; -1 for none.
+func (m Model) lastSegmentInView() int {
+	_, spans, start, height := m.transcriptViewport()
+	fallback := -1
+	for i := len(m.entries) - 1; i >= 0; i-- {
+		if !m.selectable(m.entries[i]) {
+			continue
+		}
+		if fallback < 0 {
+			fallback = i
+		}
+		if span, ok := spans[i]; ok && span[0] >= start && span[0] < start+height {
+			return i
+		}
+	}
+	return fallback
+}
+
+// revealCursor scrolls just far enough to show all of the cursor's
+// segment, leaving the live tail when it has to scroll up.
+func (m *Model) revealCursor() {
+	i := m.cursorIndex()
+	if i < 0 {
+		return
+	}
+	lines, spans, start, height := m.transcriptViewport()
+	span, ok := spans[i]
+	if !ok {
+		return
+	}
+	switch {
+	case span[0] < start:
+		start = span[0]
+	case span[1] >= start+height:
+		start = min(span[0], span[1]-height+1)
+	default:
+		return
+	}
+	m.transcriptScroll = start
+	m.transcriptLive = start >= max(0, len(lines)-height)
+}
+
+func sign(n int) int {
+	if n < 0 {
+		return -1
+	}
+	return 1
+}
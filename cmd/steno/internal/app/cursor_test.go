@@ -0,0 +1,124 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jwulff/steno/internal/daemon"
+)
+
+// cursorModel is a connected model, transcript focused, with n live
+// segments "segment 1" … "segment n" in s1.
+func cursorModel(n int) Model {
+	m := New()
+	m.width, m.height = 120, 30
+	m.connected = true
+	m.sessionID = "s1"
+	for seq := 1; seq <= n; seq++ {
+		s := seq
+		m.handleEvent(daemon.Event{Event: "segment", Text: fmt.Sprintf("segment %d", seq), Source: "microphone", SessionID: "s1", SequenceNumber: &s})
+	}
+	m.focusedPanel = FocusTranscript
+	return m
+}
+
+// cursorLine is the transcript line carrying the cursor bar.
+func cursorLine(t *testing.T, m Model) string {
+	t.Helper()
+	for _, line := range strings.Split(m.renderTranscriptPanel(m.transcriptPanelWidth(), m.transcriptVisibleLines()), "\n") {
+		if strings.Contains(line, "▌ ") {
+			return line
+		}
+	}
+	return ""
+}
+
+func TestTranscriptCursorMoves(t *testing.T) {
+	m := cursorModel(3)
+	if cursorLine(t, m) != "" {
+		t.Fatal("no cursor before j/k")
+	}
+
+	m, _ = applyUpdate(m, runeKey('k'))
+	if e, ok := m.cursorEntry(); !ok || e.SeqNum != 3 {
+		t.Fatalf("first move should land on the newest segment, got %+v", e)
+	}
+	m, _ = applyUpdate(m, runeKey('k'))
+	m, _ = applyUpdate(m, runeKey('k'))
+	m, _ = applyUpdate(m, runeKey('k'))
+	if e, _ := m.cursorEntry(); e.SeqNum != 1 {
+		t.Errorf("k should stop at the first segment, at %d", e.SeqNum)
+	}
+	if !strings.Contains(cursorLine(t, m), "segment 1") {
+		t.Errorf("cursor bar should mark segment 1, got %q", cursorLine(t, m))
+	}
+	m, _ = applyUpdate(m, runeKey('j'))
+	if e, _ := m.cursorEntry(); e.SeqNum != 2 {
+		t.Errorf("j should move down to 2, at %d", e.SeqNum)
+	}
+
+	m, _ = applyUpdate(m, runeKey('G'))
+	if e, _ := m.cursorEntry(); e.SeqNum != 3 || !m.transcriptLive {
+		t.Errorf("G should bring the cursor to the newest segment and resume live (at %d, live=%v)", e.SeqNum, m.transcriptLive)
+	}
+	m, _ = applyUpdate(m, tea.KeyMsg{Type: tea.KeyEsc})
+	if _, ok := m.cursorEntry(); ok {
+		t.Error("esc should drop the cursor")
+	}
+}
+
+func TestTranscriptCursorOnlyWhenTranscriptFocused(t *testing.T) {
+	m := cursorModel(3)
+	m.focusedPanel = FocusTopics
+	m, _ = applyUpdate(m, runeKey('k'))
+	if m.cursor != nil {
+		t.Error("j/k over the topics panel shouldn't place a cursor")
+	}
+}
+
+func TestTranscriptCursorScrollsIntoView(t *testing.T) {
+	m := cursorModel(60)
+	visible := m.transcriptVisibleLines() - 1
+
+	// Walk up past the top of the live tail; the view should follow.
+	for range visible + 5 {
+		m, _ = applyUpdate(m, runeKey('k'))
+	}
+	if m.transcriptLive {
+		t.Error("walking the cursor above the view should leave the live tail")
+	}
+	e, _ := m.cursorEntry()
+	if want := 60 - visible - 4; e.SeqNum != want {
+		t.Fatalf("cursor at %d, want %d", e.SeqNum, want)
+	}
+	if !strings.Contains(cursorLine(t, m), fmt.Sprintf("segment %d", e.SeqNum)) {
+		t.Error("cursor segment should be in view")
+	}
+
+	// Up/down scroll on their own, leaving the cursor where it is.
+	m, _ = applyUpdate(m, tea.KeyMsg{Type: tea.KeyUp})
+	if after, _ := m.cursorEntry(); after.SeqNum != e.SeqNum {
+		t.Errorf("up moved the cursor to %d", after.SeqNum)
+	}
+}
+
+func TestTranscriptCursorSkipsFilteredSegments(t *testing.T) {
+	m := cursorModel(0)
+	for i, text := range []string{"budget one", "nothing", "budget two"} {
+		seq := i + 1
+		m.handleEvent(daemon.Event{Event: "segment", Text: text, Source: "microphone", SessionID: "s1", SequenceNumber: &seq})
+	}
+	m.filter = newTranscriptFilter("budget")
+
+	m, _ = applyUpdate(m, runeKey('k'))
+	m, _ = applyUpdate(m, runeKey('k'))
+	if e, _ := m.cursorEntry(); e.SeqNum != 1 {
+		t.Errorf("k should skip the filtered-out segment, at %d", e.SeqNum)
+	}
+	m, _ = applyUpdate(m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.filter != nil || m.cursor == nil {
+		t.Error("esc should clear the filter first, keeping the cursor")
+	}
+}
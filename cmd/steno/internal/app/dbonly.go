@@ -0,0 +1,42 @@
+package app
+
+import (
+	"fmt"
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jwulff/steno/internal/db"
+)
+
+// WithDBOnly returns m as a browser over the database alone
+// (`steno --db-only`): it never starts or connects to the daemon, so
+// there is no reconnect loop and the recording controls are inert. It
+// opens on the session browser, from which sessions are replayed.
+func (m Model) WithDBOnly() Model {
+	m.dbOnly = true
+	m.statusText = "Database only"
+	m.browser.open = true
+	return m
+}
+
+// storeOpenFailedMsg reports that --db-only has no database to browse.
+type storeOpenFailedMsg struct{ err error }
+
+// openDBOnlyCmd opens the store like openStoreCmd, but as the only
+// source of data it reports a missing or unreadable database.
+func openDBOnlyCmd(opts db.Options) tea.Cmd {
+	return func() tea.Msg {
+		dbPath := storePath()
+		if _, err := os.Stat(dbPath); err != nil {
+			return storeOpenFailedMsg{err: fmt.Errorf("no steno database found at %s", dbPath)}
+		}
+		store, err := db.OpenClient(dbPath, opts)
+		if err != nil {
+			store, err = db.Open(dbPath, opts)
+		}
+		if err != nil {
+			return storeOpenFailedMsg{err: err}
+		}
+		return storeOpenedMsg{store: store}
+	}
+}
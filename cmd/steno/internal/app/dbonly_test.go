@@ -0,0 +1,51 @@
+package app
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestDBOnlyBrowsesWithoutDaemon(t *testing.T) {
+	m := New().WithDBOnly()
+	m.width, m.height = 120, 30
+	if !m.browser.open {
+		t.Fatal("--db-only should open on the session browser")
+	}
+	if label, _ := m.statusLabel(); !strings.Contains(label, "DATABASE ONLY") {
+		t.Errorf("status = %q", label)
+	}
+
+	m, _ = applyUpdate(m, tea.KeyMsg{Type: tea.KeyEsc})
+	view := m.View()
+	if !strings.Contains(view, "Browsing the database without the daemon") || strings.Contains(view, "Reconnecting") {
+		t.Errorf("main view:\n%s", view)
+	}
+	if !strings.Contains(view, "Sessions") || !strings.Contains(view, "Search all") {
+		t.Errorf("footer should offer the browsing keys:\n%s", view)
+	}
+
+	// The recording controls do nothing without a daemon.
+	if _, cmd := applyUpdate(m, runeKey(' ')); cmd != nil {
+		t.Error("space should be inert in --db-only")
+	}
+}
+
+func TestDBOnlyMissingDatabase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "none.sqlite")
+	t.Setenv("STENO_DB", path)
+	m := New().WithDBOnly()
+	m.width, m.height = 120, 30
+
+	msg := openDBOnlyCmd(m.dbOptions)()
+	if _, ok := msg.(storeOpenFailedMsg); !ok {
+		t.Fatalf("msg = %#v, want a failure", msg)
+	}
+	m, _ = applyUpdate(m, msg)
+	m, _ = applyUpdate(m, tea.KeyMsg{Type: tea.KeyEsc})
+	if view := m.View(); !strings.Contains(view, "no steno database found at "+path) {
+		t.Errorf("view should name the missing database:\n%s", view)
+	}
+}
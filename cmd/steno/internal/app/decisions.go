@@ -0,0 +1,129 @@
+package app
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jwulff/steno/internal/db"
+	"github.com/jwulff/steno/internal/decisions"
+	"github.com/jwulff/steno/internal/ui"
+)
+
+// decisionEntry is one segment in the decisions lane.
+type decisionEntry struct {
+	SessionID string
+	SeqNum    int
+	Text      string
+}
+
+// loadDecisionsCmd flags the decisions already in sessionID and loads
+// the lane. A read-only store can't record them, so the lane is
+// rebuilt from the segments instead.
+func loadDecisionsCmd(store *db.Store, sessionID string) tea.Cmd {
+	return func() tea.Msg {
+		found, err := decisions.Sync(store, sessionID)
+		if err == nil {
+			return DecisionsLoadedMsg{SessionID: sessionID, Decisions: found}
+		}
+		segments, err := store.SegmentsForSession(sessionID, -1, 0)
+		if err != nil {
+			return DecisionsLoadedMsg{SessionID: sessionID}
+		}
+		for _, s := range segments {
+			if decisions.Detect(s.Text) != "" {
+				found = append(found, db.Decision{SessionID: sessionID, SegmentSeq: s.SequenceNumber, SegmentText: s.Text})
+			}
+		}
+		return DecisionsLoadedMsg{SessionID: sessionID, Decisions: found}
+	}
+}
+
+// recordDecisionCmd persists a decision flagged live. Failures (a
+// read-only store, a segment not yet written) are dropped: the next
+// loadDecisionsCmd for the session flags it again.
+func recordDecisionCmd(store *db.Store, sessionID string, seq int, cue string) tea.Cmd {
+	return func() tea.Msg {
+		_ = store.RecordDecision(sessionID, seq, cue)
+		return nil
+	}
+}
+
+// decisionsCmd returns loadDecisionsCmd for the current session until
+// its lane has loaded, or nil.
+func (m Model) decisionsCmd() tea.Cmd {
+	if m.store == nil || m.sessionID == "" || m.decisionsFor == m.sessionID {
+		return nil
+	}
+	return loadDecisionsCmd(m.store, m.sessionID)
+}
+
+// applyDecisions fills the lane with a session's recorded decisions,
+// keeping any flagged live since the load started.
+func (m *Model) applyDecisions(msg DecisionsLoadedMsg) {
+	if msg.SessionID != m.sessionID {
+		return
+	}
+	m.decisionsFor = msg.SessionID
+	lane := make([]decisionEntry, 0, len(msg.Decisions))
+	for _, d := range msg.Decisions {
+		lane = append(lane, decisionEntry{SessionID: msg.SessionID, SeqNum: d.SegmentSeq, Text: d.SegmentText})
+	}
+	for _, d := range m.decisions {
+		if d.SessionID == msg.SessionID && !slices.ContainsFunc(lane, func(e decisionEntry) bool { return e.SeqNum == d.SeqNum }) {
+			lane = append(lane, d)
+		}
+	}
+	slices.SortFunc(lane, func(a, b decisionEntry) int { return a.SeqNum - b.SeqNum })
+	m.decisions = lane
+}
+
+// flagDecision runs the classifier on a live segment, adding it to the
+// lane and recording it when it reads as a decision.
+func (m *Model) flagDecision(entry TranscriptEntry) tea.Cmd {
+	cue := decisions.Detect(entry.Text)
+	if cue == "" || entry.SessionID != m.sessionID {
+		return nil
+	}
+	if slices.ContainsFunc(m.decisions, func(d decisionEntry) bool {
+		return d.SessionID == entry.SessionID && d.SeqNum == entry.SeqNum
+	}) {
+		return nil
+	}
+	m.decisions = append(m.decisions, decisionEntry{SessionID: entry.SessionID, SeqNum: entry.SeqNum, Text: entry.Text})
+	if m.store == nil || entry.SeqNum == 0 {
+		return nil
+	}
+	return recordDecisionCmd(m.store, entry.SessionID, entry.SeqNum, cue)
+}
+
+// currentDecisions is the lane for the current session.
+func (m Model) currentDecisions() []decisionEntry {
+	var out []decisionEntry
+	for _, d := range m.decisions {
+		if d.SessionID == m.sessionID {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+// renderDecisionLane renders the decisions lane under the topics, at
+// most maxLines tall with the newest kept when it overflows. Empty
+// until a decision is flagged.
+func (m Model) renderDecisionLane(width, maxLines int) []string {
+	lane := m.currentDecisions()
+	if len(lane) == 0 || maxLines < 2 {
+		return nil
+	}
+	lines := []string{ui.PanelTitleStyle.Render(fmt.Sprintf("DECISIONS (%d)", len(lane)))}
+	if len(lane) > maxLines-1 {
+		lane = lane[len(lane)-(maxLines-1):]
+	}
+	for _, d := range lane {
+		text := strings.Join(strings.Fields(m.scrubber.Apply(d.Text)), " ")
+		lines = append(lines, truncateToWidth(ui.DecisionStyle.Render("  ✓ ")+text, width))
+	}
+	return lines
+}
@@ -0,0 +1,65 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jwulff/steno/internal/daemon"
+	"github.com/jwulff/steno/internal/db"
+)
+
+func decisionSegment(seq int, text string) daemon.Event {
+	return daemon.Event{Event: "segment", SessionID: "sess-1", Text: text, Source: "microphone", SequenceNumber: &seq}
+}
+
+func TestDecisionLaneFlagsLiveSegments(t *testing.T) {
+	m := testModel(t, withSession("sess-1"))
+
+	cmd := m.handleEvent(decisionSegment(1, "Hi all."))
+	if cmd != nil || len(m.decisions) != 0 {
+		t.Fatalf("plain segment flagged: %+v", m.decisions)
+	}
+	cmd = m.handleEvent(decisionSegment(2, "OK, we agreed to ship Friday."))
+	if cmd == nil {
+		t.Error("a decision should be recorded")
+	}
+	// The daemon may resend a segment; it isn't flagged twice.
+	m.handleEvent(decisionSegment(2, "OK, we agreed to ship Friday."))
+	if len(m.decisions) != 1 || m.decisions[0].SeqNum != 2 {
+		t.Fatalf("decisions = %+v", m.decisions)
+	}
+	panel := m.renderTopicPanel(40, 20)
+	if !strings.Contains(panel, "DECISIONS (1)") || !strings.Contains(panel, "✓ OK, we agreed to ship") {
+		t.Errorf("topics panel:\n%s", panel)
+	}
+
+	// The load keeps live entries and adds recorded ones, in order.
+	m, _ = applyUpdate(m, DecisionsLoadedMsg{SessionID: "sess-1", Decisions: []db.Decision{{SegmentSeq: 1, SegmentText: "Let's go with Postgres."}}})
+	if len(m.decisions) != 2 || m.decisions[0].SeqNum != 1 || m.decisions[1].SeqNum != 2 {
+		t.Errorf("after load: %+v", m.decisions)
+	}
+	if m.decisionsCmd() != nil {
+		t.Error("a loaded session shouldn't load again")
+	}
+
+	// Another session's lane starts empty.
+	m.sessionID = "sess-2"
+	if panel := m.renderTopicPanel(40, 20); strings.Contains(panel, "DECISIONS") {
+		t.Errorf("lane outlived its session:\n%s", panel)
+	}
+	if m.decisionsCmd() == nil {
+		t.Error("new session should load its lane")
+	}
+}
+
+func TestDecisionLaneKeepsNewest(t *testing.T) {
+	m := New()
+	m.sessionID = "sess-1"
+	for i := range 10 {
+		m.decisions = append(m.decisions, decisionEntry{SessionID: "sess-1", SeqNum: i + 1, Text: "we decided " + string(rune('a'+i))})
+	}
+	lane := m.renderDecisionLane(40, 4)
+	if len(lane) != 4 || !strings.Contains(lane[0], "DECISIONS (10)") || !strings.Contains(lane[3], "we decided j") {
+		t.Errorf("lane = %q", lane)
+	}
+}
@@ -0,0 +1,125 @@
+package app
+
+import (
+	"strings"
+	"time"
+
+	"github.com/jwulff/steno/internal/ui"
+)
+
+// Density is the transcript layout mode, cycled at runtime with `v` and
+// defaulted from config `display.density`.
+type Density int
+
+const (
+	// DensityNormal: "[15:04:05] [MIC] text", one line per segment.
+	DensityNormal Density = iota
+	// DensityCompact: short "15:04" timestamps and a narrow hanging
+	// indent, so more text fits on small terminals.
+	DensityCompact
+	// DensityComfortable: normal layout plus a blank line wherever the
+	// speaker (mic vs system audio) changes.
+	DensityComfortable
+	// DensityCaptions: bold text with no timestamps, a source label only
+	// on speaker change, and a blank line between segments — for reading
+	// from a distance or on a projector.
+	DensityCaptions
+)
+
+var densityNames = [...]string{"normal", "compact", "comfortable", "captions"}
+
+func (d Density) String() string {
+	if d < 0 || int(d) >= len(densityNames) {
+		return "normal"
+	}
+	return densityNames[d]
+}
+
+// ParseDensity maps a config name to a Density. Empty is DensityNormal.
+func ParseDensity(name string) (Density, bool) {
+	if name == "" {
+		return DensityNormal, true
+	}
+	for i, n := range densityNames {
+		if strings.EqualFold(name, n) {
+			return Density(i), true
+		}
+	}
+	return DensityNormal, false
+}
+
+// next returns the density after d in the `v` cycle.
+func (d Density) next() Density {
+	return (d + 1) % Density(len(densityNames))
+}
+
+// badge is the transcript-header label for non-default densities.
+func (d Density) badge() string {
+	if d == DensityNormal {
+		return ""
+	}
+	return ui.DimStyle.Render(" " + strings.ToUpper(d.String()))
+}
+
+// densityLayout is the per-mode shape of one transcript line.
+type densityLayout struct {
+	// tsFormat is the time.Format layout for the timestamp; empty hides it.
+	tsFormat string
+	// labels shows the [MIC] / [SYS] source label on every segment.
+	labels bool
+	// prefixWidth is the hanging indent for wrapped continuation lines.
+	prefixWidth int
+	// turnGap inserts a blank line when the source changes.
+	turnGap bool
+	// segmentGap inserts a blank line between every segment.
+	segmentGap bool
+	// turnLabel prints the source on its own line when it changes.
+	turnLabel bool
+}
+
+func (d Density) layout() densityLayout {
+	switch d {
+	case DensityCompact:
+		// "15:04 [MIC] " = 12 visible chars.
+		return densityLayout{tsFormat: "15:04", labels: true, prefixWidth: 12}
+	case DensityComfortable:
+		return densityLayout{tsFormat: "[15:04:05]", labels: true, prefixWidth: 22, turnGap: true}
+	case DensityCaptions:
+		return densityLayout{segmentGap: true, turnLabel: true}
+	default:
+		// Prefix: "  [HH:MM:SS] [MIC] " = ~22 chars visible
+		return densityLayout{tsFormat: "[15:04:05]", labels: true, prefixWidth: 22}
+	}
+}
+
+// prefix renders the timestamp + source label that leads a segment line.
+// partial selects the in-progress styling.
+func (l densityLayout) prefix(ts time.Time, source string, partial bool) string {
+	var p string
+	if l.tsFormat != "" {
+		p = ui.TimestampStyle.Render(ts.Format(l.tsFormat)) + " "
+	}
+	if l.labels {
+		label := "[MIC] "
+		if source == "systemAudio" {
+			label = "[SYS] "
+		}
+		switch {
+		case partial:
+			p += ui.PartialTextStyle.Render(label)
+		case source == "systemAudio":
+			p += ui.SysLabelStyle.Render(label)
+		default:
+			p += ui.MicLabelStyle.Render(label)
+		}
+	}
+	return p
+}
+
+// turnLabel is the captions-mode speaker line.
+func turnLabel(source string) string {
+	if source == "systemAudio" {
+		return ui.SysLabelStyle.Render("System audio")
+	}
+	return ui.MicLabelStyle.Render("Microphone")
+}
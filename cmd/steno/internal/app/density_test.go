@@ -0,0 +1,98 @@
+package app
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jwulff/steno/internal/config"
+)
+
+// densityModel is a connected model with a mic → mic → sys exchange.
+func densityModel(d Density) Model {
+	m := New()
+	m.connected = true
+	m.width, m.height = 120, 30
+	m.density = d
+	base := time.Date(2026, 3, 1, 14, 5, 9, 0, time.Local)
+	m.entries = []TranscriptEntry{
+		{Text: "first from mic", Source: "microphone", Timestamp: base, SeqNum: 1},
+		{Text: "second from mic", Source: "microphone", Timestamp: base.Add(time.Second), SeqNum: 2},
+		{Text: "reply from call", Source: "systemAudio", Timestamp: base.Add(2 * time.Second), SeqNum: 3},
+	}
+	return m
+}
+
+// transcriptRows returns the rendered transcript panel lines from the
+// first segment on, with trailing padding trimmed.
+func transcriptRows(t *testing.T, m Model) []string {
+	t.Helper()
+	panel := m.renderTranscriptPanel(m.transcriptPanelWidth(), 20)
+	var rows []string
+	for _, l := range strings.Split(panel, "\n")[1:] {
+		rows = append(rows, strings.TrimRight(l, " "))
+	}
+	for len(rows) > 0 && rows[len(rows)-1] == "" {
+		rows = rows[:len(rows)-1]
+	}
+	return rows
+}
+
+func TestDensityCompactShortTimestamps(t *testing.T) {
+	rows := transcriptRows(t, densityModel(DensityCompact))
+	if len(rows) != 3 {
+		t.Fatalf("compact rows = %q, want 3", rows)
+	}
+	if !strings.HasPrefix(rows[0], "  14:05 [MIC] first") {
+		t.Errorf("rows[0] = %q, want short timestamp", rows[0])
+	}
+}
+
+func TestDensityComfortableGapsOnSpeakerTurn(t *testing.T) {
+	rows := transcriptRows(t, densityModel(DensityComfortable))
+	if len(rows) != 4 || strings.TrimSpace(rows[2]) != "" {
+		t.Fatalf("comfortable rows = %q, want blank line before the sys turn only", rows)
+	}
+	if !strings.Contains(rows[0], "[14:05:09]") {
+		t.Errorf("rows[0] = %q, want full timestamp", rows[0])
+	}
+}
+
+func TestDensityCaptionsDropsTimestamps(t *testing.T) {
+	rows := transcriptRows(t, densityModel(DensityCaptions))
+	joined := strings.Join(rows, "\n")
+	if strings.Contains(joined, "14:05") || strings.Contains(joined, "[MIC]") {
+		t.Errorf("captions should hide timestamps and labels:\n%s", joined)
+	}
+	if strings.Count(joined, "Microphone") != 1 || strings.Count(joined, "System audio") != 1 {
+		t.Errorf("captions should label each speaker turn once:\n%s", joined)
+	}
+}
+
+func TestDensityKeyCycles(t *testing.T) {
+	m := densityModel(DensityNormal)
+	seen := []Density{m.density}
+	for range 4 {
+		updated, _ := m.Update(runeKey('v'))
+		m = updated.(Model)
+		seen = append(seen, m.density)
+	}
+	want := []Density{DensityNormal, DensityCompact, DensityComfortable, DensityCaptions, DensityNormal}
+	for i := range want {
+		if seen[i] != want[i] {
+			t.Fatalf("cycle = %v, want %v", seen, want)
+		}
+	}
+}
+
+func TestDensityFromConfig(t *testing.T) {
+	m := NewWithConfig(config.Config{Display: config.DisplayConfig{Density: "captions"}})
+	if m.density != DensityCaptions {
+		t.Errorf("density = %v, want captions", m.density)
+	}
+	for _, name := range config.Densities {
+		if _, ok := ParseDensity(name); !ok {
+			t.Errorf("config density %q not understood by the TUI", name)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package app
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jwulff/steno/internal/db"
+	"github.com/jwulff/steno/internal/dictation"
+)
+
+// dictationState is dictation mode (config `dictation`, toggled with D):
+// the profile's macros (`steno macros`) applied to the mic segments and
+// partial the transcript shows. Display-time only, like scrubbing.
+type dictationState struct {
+	on bool
+	// macros starts as the built-in rules, until the profile's load.
+	macros *dictation.Macros
+}
+
+// loadDictationCmd reads profile's macro rules and compiles them with
+// the built-in ones. A nil store yields the built-ins alone.
+func loadDictationCmd(store *db.Store, profile string) tea.Cmd {
+	return func() tea.Msg {
+		var rules []dictation.Rule
+		if store != nil {
+			found, err := store.DictationMacros(profile)
+			if err != nil {
+				return DictationMacrosLoadedMsg{Err: err}
+			}
+			for _, r := range found {
+				rules = append(rules, dictation.Rule{Pattern: r.Pattern, Replacement: r.Replacement})
+			}
+		}
+		macros, err := dictation.Compile(rules)
+		return DictationMacrosLoadedMsg{Macros: macros, Err: err}
+	}
+}
+
+// dictationCmd reloads the macros while dictation mode is on; nil when
+// it's off.
+func (m Model) dictationCmd() tea.Cmd {
+	if !m.dictation.on {
+		return nil
+	}
+	return loadDictationCmd(m.store, m.dictationConfig.ProfileName())
+}
+
+// toggleDictation turns dictation mode on or off. Turning it on reloads
+// the profile, so rules added with `steno macros` apply without a
+// restart.
+func (m Model) toggleDictation() (tea.Model, tea.Cmd) {
+	m.dictation.on = !m.dictation.on
+	return m, m.dictationCmd()
+}
+
+// applyDictationMacros installs loaded macros. On a failed load the
+// macros already in use stay, and the failure is noted.
+func (m *Model) applyDictationMacros(msg DictationMacrosLoadedMsg) tea.Cmd {
+	if msg.Err != nil {
+		return m.pushError(SeverityWarn, "Couldn't load dictation macros: "+msg.Err.Error(), true)
+	}
+	m.dictation.macros = msg.Macros
+	return nil
+}
+
+// dictated is a segment's text as the transcript shows it: with the
+// dictation macros applied when dictation mode is on and it came from
+// the mic.
+func (m Model) dictated(text, source string) string {
+	if !m.dictation.on || source != "microphone" {
+		return text
+	}
+	return m.dictation.macros.Apply(text)
+}
@@ -0,0 +1,58 @@
+package app
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jwulff/steno/internal/config"
+	"github.com/jwulff/steno/internal/daemon"
+	"github.com/jwulff/steno/internal/dictation"
+)
+
+func TestDictationModeRewritesMicSegments(t *testing.T) {
+	m := New()
+	m.width, m.height = 120, 30
+	m.connected = true
+	m.sessionID = "s1"
+	for i, ev := range []struct{ text, source string }{
+		{"Dear team, new paragraph. Thanks all.", "microphone"},
+		{"they said new paragraph too", "systemAudio"},
+	} {
+		seq := i + 1
+		m.handleEvent(daemon.Event{Event: "segment", Text: ev.text, Source: ev.source, SessionID: "s1", SequenceNumber: &seq})
+	}
+	if !strings.Contains(m.View(), "Dear team, new paragraph.") {
+		t.Fatal("macros shouldn't apply outside dictation mode")
+	}
+
+	m, cmd := applyUpdate(m, runeKey('D'))
+	if !m.dictation.on || cmd == nil {
+		t.Fatal("D should turn dictation on and load the profile")
+	}
+	m, _ = applyUpdate(m, cmd())
+	view := m.View()
+	if strings.Contains(view, "Dear team, new paragraph") || !strings.Contains(view, "Thanks all.") || !strings.Contains(view, "DICTATION") {
+		t.Errorf("dictation view:\n%s", view)
+	}
+	if !strings.Contains(view, "they said new paragraph too") {
+		t.Error("system-audio segments aren't dictated and should be left alone")
+	}
+
+	if m, _ = applyUpdate(m, runeKey('D')); m.dictation.on || !strings.Contains(m.View(), "Dear team, new paragraph.") {
+		t.Error("D again should turn dictation off")
+	}
+}
+
+func TestDictationLoadFailureKeepsMacros(t *testing.T) {
+	m := NewWithConfig(config.Config{Dictation: config.DictationConfig{Enabled: true}})
+	custom, _ := dictation.Compile([]dictation.Rule{{Pattern: "x", Replacement: "y"}})
+	m, _ = applyUpdate(m, DictationMacrosLoadedMsg{Macros: custom})
+	m, _ = applyUpdate(m, DictationMacrosLoadedMsg{Err: errors.New("disk gone")})
+	if got := m.dictated("x new line z", "microphone"); got != "y\nz" {
+		t.Errorf("dictated = %q, want the last good macros kept", got)
+	}
+	if len(m.errorStack) != 1 || !strings.Contains(m.errorStack[0].Message, "disk gone") {
+		t.Errorf("error stack = %+v", m.errorStack)
+	}
+}
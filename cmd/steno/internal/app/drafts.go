@@ -0,0 +1,228 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jwulff/steno/internal/db"
+)
+
+// draftInterval is how often the partial drafts are written, when they
+// changed (config capture.partial_drafts).
+const draftInterval = 3 * time.Second
+
+// draftsFile is the sidecar beside the database the drafts go to.
+const draftsFile = "partial-drafts.json"
+
+// partialDraft is a source's unfinished words as last written: the
+// recognizer's tail, which the daemon only stores once it finalizes a
+// segment. A crash before then would lose it.
+type partialDraft struct {
+	SessionID string    `json:"session_id"`
+	Source    string    `json:"source"`
+	Text      string    `json:"text"`
+	At        time.Time `json:"at"`
+	// AfterSeq is the session's last segment when the draft was written.
+	// A later segment from the same source finalized it.
+	AfterSeq int `json:"after_seq"`
+}
+
+// draftState keeps the last few seconds of each source's partial in the
+// sidecar, so a TUI or daemon crash loses little of the unfinished tail.
+// The recovery screen shows the drafts a crash left behind.
+type draftState struct {
+	enabled bool
+	path    string
+	// loaded is set once the drafts of earlier runs were read: nothing
+	// is written before, which would overwrite them.
+	loaded bool
+	// kept are the drafts of sessions other than the current one, left
+	// by a crash and kept in the file until the recovery check finds
+	// them finalized.
+	kept []partialDraft
+	// written are the current session's drafts as last written.
+	written []partialDraft
+}
+
+// draftsPath is where the drafts are kept, beside the database.
+func draftsPath() string {
+	return filepath.Join(filepath.Dir(storePath()), draftsFile)
+}
+
+// loadDraftsCmd reads the sidecar. A missing one holds no drafts.
+func loadDraftsCmd(path string) tea.Cmd {
+	return func() tea.Msg {
+		data, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			return DraftsLoadedMsg{}
+		}
+		if err != nil {
+			return DraftsLoadedMsg{Err: err}
+		}
+		var drafts []partialDraft
+		if err := json.Unmarshal(data, &drafts); err != nil {
+			// A sidecar that can't be read is no help; start over.
+			return DraftsLoadedMsg{}
+		}
+		return DraftsLoadedMsg{Drafts: drafts}
+	}
+}
+
+// saveDraftsCmd replaces the sidecar with drafts. The file is written
+// beside it and renamed over it, so a crash mid-write leaves the last
+// drafts intact.
+func saveDraftsCmd(path string, drafts []partialDraft) tea.Cmd {
+	return func() tea.Msg {
+		data, err := json.Marshal(drafts)
+		if err != nil {
+			return DraftsSavedMsg{Err: err}
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return DraftsSavedMsg{Err: err}
+		}
+		tmp := path + ".tmp"
+		if err := os.WriteFile(tmp, data, 0o600); err != nil {
+			return DraftsSavedMsg{Err: err}
+		}
+		return DraftsSavedMsg{Err: os.Rename(tmp, path)}
+	}
+}
+
+// draftsCmd reads the earlier drafts, when drafts are on. They are for
+// the local daemon's sessions, so a viewer, --db-only and the tutorial
+// keep none.
+func (m Model) draftsCmd() tea.Cmd {
+	if !m.drafts.enabled || m.viewAddr != "" || m.dbOnly || m.tutorial != nil {
+		return nil
+	}
+	return loadDraftsCmd(m.drafts.path)
+}
+
+func (m Model) draftTickCmd() tea.Cmd {
+	return m.tick(draftInterval, func(time.Time) tea.Msg { return DraftTickMsg{} })
+}
+
+func (m Model) applyDraftsLoaded(msg DraftsLoadedMsg) (tea.Model, tea.Cmd) {
+	if msg.Err != nil {
+		// Without the earlier drafts, writing would replace them.
+		return m, m.pushError(SeverityWarn, "partial drafts: "+msg.Err.Error(), true)
+	}
+	m.drafts.loaded = true
+	m.drafts.kept = msg.Drafts
+	return m, m.draftTickCmd()
+}
+
+// currentDrafts are the current session's partials as drafts, by source.
+func (m Model) currentDrafts() []partialDraft {
+	var drafts []partialDraft
+	for source, text := range m.partials {
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		drafts = append(drafts, partialDraft{
+			SessionID: m.sessionID,
+			Source:    source,
+			Text:      text,
+			At:        m.now(),
+			AfterSeq:  m.segmentCount,
+		})
+	}
+	slices.SortFunc(drafts, func(a, b partialDraft) int { return strings.Compare(a.Source, b.Source) })
+	return drafts
+}
+
+// sameDrafts reports whether a and b hold the same words, whenever they
+// were written.
+func sameDrafts(a, b []partialDraft) bool {
+	return slices.EqualFunc(a, b, func(x, y partialDraft) bool {
+		return x.SessionID == y.SessionID && x.Source == y.Source && x.Text == y.Text && x.AfterSeq == y.AfterSeq
+	})
+}
+
+// handleDraftTick writes the drafts when the partials changed. While
+// disconnected there are no partials to write, and the file keeps the
+// last ones seen.
+func (m Model) handleDraftTick() (tea.Model, tea.Cmd) {
+	if !m.drafts.enabled {
+		// Turned off in the config; the ticks stop.
+		return m, nil
+	}
+	next := m.draftTickCmd()
+	if !m.connected || m.sessionID == "" {
+		return m, next
+	}
+	current := m.currentDrafts()
+	if sameDrafts(current, m.drafts.written) {
+		return m, next
+	}
+	m.drafts.written = current
+	return m, tea.Batch(saveDraftsCmd(m.drafts.path, m.draftsToWrite()), next)
+}
+
+// draftsToWrite is the kept drafts followed by the current session's.
+// A kept draft the session has since replaced is dropped.
+func (m Model) draftsToWrite() []partialDraft {
+	var all []partialDraft
+	for _, d := range m.drafts.kept {
+		if d.SessionID != m.sessionID {
+			all = append(all, d)
+		}
+	}
+	return append(all, m.drafts.written...)
+}
+
+// keepDrafts sets the current drafts aside when the daemon is lost: a
+// crash took their words with it, and the session that comes back may
+// be a new one.
+func (m *Model) keepDrafts() {
+	if len(m.drafts.written) == 0 {
+		return
+	}
+	m.drafts.kept = append(m.drafts.kept, m.drafts.written...)
+	m.drafts.written = nil
+}
+
+// unfinalizedDrafts returns, by session, the drafts among drafts whose
+// sessions are stranded and that no later segment from their source
+// finalized: the words a crash cut off.
+func unfinalizedDrafts(store *db.Store, stranded []db.StrandedSession, drafts []partialDraft) (map[string][]partialDraft, error) {
+	found := map[string][]partialDraft{}
+	for _, d := range drafts {
+		if !slices.ContainsFunc(stranded, func(s db.StrandedSession) bool { return s.Session.ID == d.SessionID }) {
+			continue
+		}
+		later, err := store.SegmentsAfter(d.SessionID, d.AfterSeq, 0)
+		if err != nil {
+			return nil, err
+		}
+		if !slices.ContainsFunc(later, func(s db.Segment) bool { return s.Source == d.Source }) {
+			found[d.SessionID] = append(found[d.SessionID], d)
+		}
+	}
+	return found, nil
+}
+
+// pruneDrafts drops the kept drafts the recovery check didn't find cut
+// off, and rewrites the sidecar without them.
+func (m *Model) pruneDrafts(found map[string][]partialDraft) tea.Cmd {
+	if !m.drafts.loaded || len(m.drafts.kept) == 0 {
+		return nil
+	}
+	var kept []partialDraft
+	for _, d := range m.drafts.kept {
+		if slices.ContainsFunc(found[d.SessionID], func(f partialDraft) bool { return f.Source == d.Source }) {
+			kept = append(kept, d)
+		}
+	}
+	if len(kept) == len(m.drafts.kept) {
+		return nil
+	}
+	m.drafts.kept = kept
+	return saveDraftsCmd(m.drafts.path, m.draftsToWrite())
+}
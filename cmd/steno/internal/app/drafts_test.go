@@ -0,0 +1,93 @@
+package app
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jwulff/steno/internal/daemon"
+)
+
+func TestPartialDraftsWrittenAndKept(t *testing.T) {
+	path := filepath.Join(t.TempDir(), draftsFile)
+	m := New()
+	m.drafts = draftState{enabled: true, path: path}
+	m.connected = true
+	m.sessionID = "s-live"
+	m.segmentCount = 7
+
+	// Nothing is written before the earlier drafts are read.
+	m, _ = applyUpdate(m, runCmd(m.draftsCmd())[0])
+	if !m.drafts.loaded || len(m.drafts.kept) != 0 {
+		t.Fatalf("drafts = %+v, want loaded and empty without a sidecar", m.drafts)
+	}
+
+	m.handleEvent(daemon.Event{Event: "partial", Source: "microphone", Text: "and the last thing"})
+	m, cmd := applyUpdate(m, DraftTickMsg{})
+	if cmd == nil || len(m.drafts.written) != 1 {
+		t.Fatalf("written = %+v, want the mic's partial", m.drafts.written)
+	}
+	// The batch also holds the next tick; write without waiting for it.
+	if saved := runCmd(saveDraftsCmd(path, m.draftsToWrite())); saved[0].(DraftsSavedMsg).Err != nil {
+		t.Fatalf("save: %v", saved[0])
+	}
+
+	// The daemon goes away: its session's drafts are kept for the
+	// recovery screen, and the next run reads them back.
+	m, _ = applyUpdate(m, DaemonEventErrorMsg{Err: errors.New("EOF")})
+	if len(m.drafts.written) != 0 || len(m.drafts.kept) != 1 {
+		t.Fatalf("drafts = %+v, want the written ones kept", m.drafts)
+	}
+	next := New()
+	next.drafts = draftState{enabled: true, path: path}
+	next, _ = applyUpdate(next, runCmd(next.draftsCmd())[0])
+	if len(next.drafts.kept) != 1 {
+		t.Fatalf("kept = %+v, want the draft read back", next.drafts.kept)
+	}
+	d := next.drafts.kept[0]
+	if d.SessionID != "s-live" || d.Source != "microphone" || d.Text != "and the last thing" || d.AfterSeq != 7 {
+		t.Errorf("draft = %+v", d)
+	}
+}
+
+func TestRecoveryShowsCutOffDrafts(t *testing.T) {
+	m := New()
+	m.width, m.height = 120, 30
+	m.drafts = draftState{enabled: true, loaded: true, path: filepath.Join(t.TempDir(), draftsFile), kept: []partialDraft{
+		{SessionID: "s-crashed", Source: "systemAudio", Text: "we ship on"},
+		{SessionID: "s-finished", Source: "microphone", Text: "finalized after all"},
+	}}
+
+	m, _ = applyUpdate(m, RecoveryLoadedMsg{Sessions: strandedFixture(), Check: true, Drafts: map[string][]partialDraft{
+		"s-crashed": {m.drafts.kept[0]},
+	}})
+	if !strings.Contains(m.notice, "with unfinished words") {
+		t.Errorf("notice = %q, want the drafts mentioned", m.notice)
+	}
+	if len(m.drafts.kept) != 1 || m.drafts.kept[0].SessionID != "s-crashed" {
+		t.Errorf("kept = %+v, want the finalized draft dropped", m.drafts.kept)
+	}
+
+	m.recovery = &recoveryScreen{}
+	m, _ = applyUpdate(m, RecoveryLoadedMsg{Sessions: strandedFixture(), Drafts: map[string][]partialDraft{
+		"s-crashed": {m.drafts.kept[0]},
+	}})
+	view := m.View()
+	for _, want := range []string{"Unfinished when it stopped:", "[SYS] we ship on…"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view missing %q:\n%s", want, view)
+		}
+	}
+}
+
+func TestPartialDraftsOff(t *testing.T) {
+	m := New()
+	if m.draftsCmd() != nil {
+		t.Error("drafts are off by default")
+	}
+	m.drafts = draftState{enabled: true, path: "unused"}
+	if m.WithViewer("host:1", "token").draftsCmd() != nil || m.WithDBOnly().draftsCmd() != nil {
+		t.Error("a viewer and --db-only keep no drafts")
+	}
+}
@@ -0,0 +1,112 @@
+package app
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jwulff/steno/internal/db"
+	"github.com/jwulff/steno/internal/entities"
+	"github.com/jwulff/steno/internal/ui"
+)
+
+// entityList is the `X` overlay: the dates, times, amounts and links
+// said in the current session, grouped by kind. Enter filters the
+// transcript to the segments mentioning the selection. The same list is
+// exported by `steno entities`.
+type entityList struct {
+	entities []entities.Entity
+	selected int
+}
+
+// entityKindLabels head each group of the list.
+var entityKindLabels = map[entities.Kind]string{
+	entities.Date:   "Dates",
+	entities.Time:   "Times",
+	entities.Amount: "Amounts",
+	entities.URL:    "Links",
+}
+
+// openEntities collects the entities from the current session's
+// segments, after scrubbing, so a masked figure isn't listed.
+func (m Model) openEntities() (tea.Model, tea.Cmd) {
+	var segments []db.Segment
+	for _, e := range m.entries {
+		if !e.IsBoundary && (e.SessionID == "" || e.SessionID == m.sessionID) {
+			segments = append(segments, db.Segment{SequenceNumber: e.SeqNum, StartedAt: e.Timestamp, Text: m.scrubber.Apply(e.Text)})
+		}
+	}
+	m.entityList = &entityList{entities: entities.Collect(segments)}
+	return m, nil
+}
+
+// handleEntitiesKey handles keys while the entity list is open: up/down
+// move, enter filters the transcript by the selection, esc or X closes.
+func (m Model) handleEntitiesKey(key string) (tea.Model, tea.Cmd) {
+	l := *m.entityList
+	switch key {
+	case KeyQuit, KeyQuitUpper, KeyCtrlC:
+		return m.quit()
+	case KeyEsc, KeyEntities:
+		m.entityList = nil
+		return m, nil
+	case KeyEnter:
+		if len(l.entities) == 0 {
+			return m, nil
+		}
+		text := l.entities[l.selected].Text
+		m.entityList = nil
+		m.filter = &transcriptFilter{word: text, re: regexp.MustCompile(`(?i)` + regexp.QuoteMeta(text))}
+		m.focusedPanel = FocusTranscript
+		m.transcriptLive = false
+		m.transcriptScroll = 0
+		return m, nil
+	case KeyUp, KeyK:
+		if l.selected > 0 {
+			l.selected--
+		}
+	case KeyDown, KeyJ:
+		if l.selected < len(l.entities)-1 {
+			l.selected++
+		}
+	}
+	m.entityList = &l
+	return m, nil
+}
+
+// renderEntities renders the entity list in place of the main panels,
+// scrolled to keep the selection on screen.
+func (m Model) renderEntities() string {
+	l := m.entityList
+	width := max(20, m.width-6)
+	lines := []string{ui.PanelTitleActiveStyle.Render("Dates, times, amounts and links · this session")}
+	if len(l.entities) == 0 {
+		lines = append(lines, ui.DimStyle.Render("None mentioned yet."))
+	}
+
+	var rows []string
+	selectedRow := 0
+	var kind entities.Kind
+	for i, e := range l.entities {
+		if e.Kind != kind {
+			kind = e.Kind
+			rows = append(rows, ui.PanelTitleStyle.Render(entityKindLabels[kind]))
+		}
+		detail := fmt.Sprintf("×%d · first at %s", e.Count, e.FirstAt.Local().Format("15:04:05"))
+		line := ui.Marker(false) + e.Text + "  " + ui.DimStyle.Render(detail)
+		if i == l.selected {
+			line = ui.SelectedStyle.Render(ui.Marker(true)+e.Text) + "  " + ui.DimStyle.Render(detail)
+			selectedRow = len(rows)
+		}
+		rows = append(rows, truncateToWidth(line, width))
+	}
+	if room := max(3, m.transcriptVisibleLines()-4); len(rows) > room {
+		start := min(max(0, selectedRow-room/2), len(rows)-room)
+		rows = rows[start : start+room]
+	}
+	lines = append(lines, rows...)
+
+	lines = append(lines, "", ui.DimStyle.Render("↑/↓ move · enter show in transcript · esc close"))
+	return ui.SessionBrowserStyle.Render(strings.Join(lines, "\n"))
+}
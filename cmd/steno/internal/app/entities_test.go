@@ -0,0 +1,53 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jwulff/steno/internal/daemon"
+)
+
+func TestEntityListFiltersTranscript(t *testing.T) {
+	m := New()
+	m.width, m.height = 120, 30
+	m.connected = true
+	m.sessionID = "s1"
+	for i, text := range []string{"the quote came in at $4,500", "then the launch plan", "sign by Friday, $4,500 total"} {
+		seq := i + 1
+		m.handleEvent(daemon.Event{Event: "segment", Text: text, Source: "microphone", SessionID: "s1", SequenceNumber: &seq})
+	}
+
+	m, _ = applyUpdate(m, runeKey('X'))
+	if m.entityList == nil || len(m.entityList.entities) != 2 {
+		t.Fatalf("entityList = %+v, want Friday and $4,500", m.entityList)
+	}
+	view := m.View()
+	for _, want := range []string{"Dates", "Friday", "Amounts", "$4,500  ×2"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("list view missing %q:\n%s", want, view)
+		}
+	}
+
+	m, _ = applyUpdate(m, runeKey('j'))
+	m, _ = applyUpdate(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.entityList != nil || m.filter == nil || m.filter.word != "$4,500" {
+		t.Fatalf("enter should close the list and filter by the amount (filter=%+v)", m.filter)
+	}
+	if view := m.View(); strings.Contains(view, "launch plan") || !strings.Contains(view, "quote came in") {
+		t.Errorf("filtered view:\n%s", view)
+	}
+}
+
+func TestEntityListEmptyAndClose(t *testing.T) {
+	m := New()
+	m.width, m.height = 120, 30
+	m, _ = applyUpdate(m, runeKey('X'))
+	if !strings.Contains(m.View(), "None mentioned yet.") {
+		t.Error("empty list should say so")
+	}
+	m, _ = applyUpdate(m, runeKey('X'))
+	if m.entityList != nil {
+		t.Error("X should close the list")
+	}
+}
@@ -0,0 +1,178 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jwulff/steno/internal/ui"
+)
+
+// Severity ranks an error-bar entry. The bar shows the most severe
+// active entry; the `e` notifications modal lists them all.
+type Severity int
+
+const (
+	SeverityInfo Severity = iota
+	SeverityWarn
+	SeverityError
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityWarn:
+		return "warn"
+	default:
+		return "error"
+	}
+}
+
+// label is the error-bar prefix for s, color-coded.
+func (s Severity) label() string {
+	switch s {
+	case SeverityInfo:
+		return ui.InfoStyle.Render("Info: ")
+	case SeverityWarn:
+		return ui.WarnStyle.Render("Warning: ")
+	default:
+		return ui.ErrorStyle.Render("Error: ")
+	}
+}
+
+func (s Severity) text(msg string) string {
+	switch s {
+	case SeverityInfo:
+		return ui.DimStyle.Render(msg)
+	case SeverityWarn:
+		return ui.LastSegWarnStyle.Render(msg)
+	default:
+		return ui.ErrorTextStyle.Render(msg)
+	}
+}
+
+const (
+	// errorStackCapacity bounds the active error stack. Older entries
+	// remain in the history ring.
+	errorStackCapacity = 5
+	// transientErrorTTL is how long a transient entry stays on the bar.
+	transientErrorTTL = 5 * time.Second
+)
+
+// errorGuidance maps the daemon's stable error tokens and message
+// prefixes to something the user can do about them. The wire protocol
+// has no error codes, so these strings are the contract; keep them in
+// sync with RecordingEngine / CommandDispatcher. First match wins.
+var errorGuidance = []struct {
+	match    string
+	guidance string
+}{
+	{MicOrScreenPermissionRevoked, "Grant Microphone and Screen Recording to steno-daemon in System Settings → Privacy & Security, then restart the daemon."},
+	{"System audio failed:", "Check Screen Recording permission for steno-daemon in System Settings → Privacy & Security."},
+	{"Audio source failed:", "Check the microphone is connected, or pick another input as the macOS default."},
+	{"Failed to create session:", "The database may be locked or the disk full; check free space in ~/Library/Application Support/Steno."},
+	{"Failed to open fresh session:", "The database may be locked or the disk full; check free space in ~/Library/Application Support/Steno."},
+	{"Failed to save segment:", "The database may be locked or the disk full; check free space in ~/Library/Application Support/Steno."},
+	{"press p to resume first", "Press p to resume, then mark the boundary."},
+	{"Unknown command:", "The daemon is older than this TUI; update both with make install."},
+	{"readonly database", "The database is open read-only; restart steno once the daemon has created it."},
+}
+
+// guidanceFor returns the user-fixable advice for message, or "".
+func guidanceFor(message string) string {
+	for _, g := range errorGuidance {
+		if strings.Contains(message, g.match) {
+			return g.guidance
+		}
+	}
+	return ""
+}
+
+// pushError puts an entry on the error bar and in the history ring.
+// Transient entries clear themselves after transientErrorTTL, and errors
+// fire the configured alert; the returned command does both and is nil
+// otherwise. Repeating the message of an active entry replaces it rather
+// than stacking.
+func (m *Model) pushError(sev Severity, message string, transient bool) tea.Cmd {
+	if message == "" {
+		return nil
+	}
+	entry := ErrorEntry{
+		Timestamp: m.now(),
+		Severity:  sev,
+		Message:   message,
+		Guidance:  guidanceFor(message),
+		Transient: transient,
+	}
+	stack := m.errorStack[:0:0]
+	for _, e := range m.errorStack {
+		if e.Message != message {
+			stack = append(stack, e)
+		}
+	}
+	stack = append(stack, entry)
+	if len(stack) > errorStackCapacity {
+		stack = stack[len(stack)-errorStackCapacity:]
+	}
+	m.errorStack = stack
+	m.recordErrorHistory(entry)
+	var cmd tea.Cmd
+	if transient {
+		cmd = m.clearTransientErrorCmd()
+	}
+	if sev == SeverityError {
+		cmd = tea.Batch(cmd, m.alert())
+	}
+	return cmd
+}
+
+// clearExpiredErrors drops transient entries older than transientErrorTTL.
+func (m *Model) clearExpiredErrors() {
+	stack := m.errorStack[:0:0]
+	for _, e := range m.errorStack {
+		if e.Transient && m.now().Sub(e.Timestamp) >= transientErrorTTL {
+			continue
+		}
+		stack = append(stack, e)
+	}
+	m.errorStack = stack
+}
+
+// topError returns the entry the error bar shows: the most severe,
+// newest first among equals.
+func (m Model) topError() (ErrorEntry, bool) {
+	if len(m.errorStack) == 0 {
+		return ErrorEntry{}, false
+	}
+	top := m.errorStack[len(m.errorStack)-1]
+	for i := len(m.errorStack) - 2; i >= 0; i-- {
+		if m.errorStack[i].Severity > top.Severity {
+			top = m.errorStack[i]
+		}
+	}
+	return top, true
+}
+
+// errorMessage is the text of the entry on the error bar, or "".
+func (m Model) errorMessage() string {
+	top, _ := m.topError()
+	return top.Message
+}
+
+func (m Model) renderErrorBar() string {
+	top, _ := m.topError()
+	line := top.Severity.label() + top.Severity.text(top.Message)
+	if more := len(m.errorStack) - 1; more > 0 {
+		line += ui.DimStyle.Render(fmt.Sprintf("  (+%d more · e)", more))
+	}
+	if top.Guidance != "" {
+		line += ui.DimStyle.Render("  → " + top.Guidance)
+	}
+	// One line: the layout reserves a single row for the bar.
+	if m.width > 0 {
+		line = truncateToWidth(line, m.width)
+	}
+	return line
+}
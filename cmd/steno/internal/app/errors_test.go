@@ -0,0 +1,91 @@
+package app
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jwulff/steno/internal/daemon"
+)
+
+func TestErrorBarShowsMostSevere(t *testing.T) {
+	m := New()
+	m.width, m.height = 200, 24
+	m.pushError(SeverityError, "Audio source failed: device gone", false)
+	m.pushError(SeverityWarn, "System audio failed: stream stopped", true)
+
+	top, ok := m.topError()
+	if !ok || top.Severity != SeverityError {
+		t.Fatalf("top = %+v, want the error over the newer warning", top)
+	}
+	bar := m.renderErrorBar()
+	for _, want := range []string{"Error: ", "device gone", "(+1 more · e)", "microphone is connected"} {
+		if !strings.Contains(bar, want) {
+			t.Errorf("error bar missing %q: %s", want, bar)
+		}
+	}
+
+	// Same message again replaces rather than stacks.
+	m.pushError(SeverityError, "Audio source failed: device gone", false)
+	if len(m.errorStack) != 2 {
+		t.Errorf("stack = %d entries, want 2", len(m.errorStack))
+	}
+}
+
+func TestTransientErrorsExpire(t *testing.T) {
+	c := newManualClock()
+	m := New().WithClock(c)
+	m.pushError(SeverityError, "sticky", false)
+	if cmd := m.pushError(SeverityWarn, "blip", true); cmd == nil {
+		t.Fatal("transient entries should schedule their clear")
+	}
+
+	c.advance(2 * time.Second)
+	m.pushError(SeverityWarn, "later blip", true)
+	c.advance(3 * time.Second)
+	m, _ = applyUpdate(m, ClearTransientErrorMsg{})
+
+	var left []string
+	for _, e := range m.errorStack {
+		left = append(left, e.Message)
+	}
+	if strings.Join(left, ",") != "sticky,later blip" {
+		t.Errorf("stack after 5s = %v, want the expired blip gone", left)
+	}
+}
+
+func TestErrorSeverityFromDaemonEvents(t *testing.T) {
+	m := New()
+	tr, fa := true, false
+	m.handleEvent(daemon.Event{Event: "error", Message: "Failed to save segment: disk I/O", Transient: &tr})
+	m.handleEvent(daemon.Event{Event: "error", Message: "recovery_exhausted: " + MicOrScreenPermissionRevoked, Transient: &fa})
+	m.handleEvent(daemon.Event{Event: "error", Message: "healed: gap=3s", Transient: &tr})
+
+	var got []string
+	for _, e := range m.errorHistory {
+		got = append(got, e.Severity.String())
+	}
+	if strings.Join(got, ",") != "warn,error,info" {
+		t.Errorf("severities = %v, want warn,error,info", got)
+	}
+	if g := m.errorHistory[1].Guidance; !strings.Contains(g, "System Settings") {
+		t.Errorf("permission guidance = %q", g)
+	}
+	if m.errorHistory[2].Message != "audio recovered (gap=3s)" {
+		t.Errorf("healed notice = %q", m.errorHistory[2].Message)
+	}
+}
+
+func TestNotificationsModalListsSeverityAndGuidance(t *testing.T) {
+	m := New()
+	m.width, m.height = 200, 30
+	m.pushError(SeverityWarn, "press p to resume first", true)
+	m.showErrorModal = true
+
+	view := m.renderErrorModal()
+	for _, want := range []string{"Notifications (1 / 10)", "Warning: press p to resume first", "→ Press p to resume"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("modal missing %q:\n%s", want, view)
+		}
+	}
+}
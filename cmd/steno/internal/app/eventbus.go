@@ -0,0 +1,174 @@
+package app
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jwulff/steno/internal/daemon"
+)
+
+// Daemon events reach the model through an eventBus: handlers registered
+// by event name (see events.go), wrapped in middleware for what cuts
+// across events, such as dropping redelivered segments or logging. A new
+// event-driven feature registers a handler rather than growing a switch,
+// and each handler and middleware can be tested alone against a Model.
+
+// eventHandler applies one daemon event to the model and returns any
+// command it needs run.
+type eventHandler func(m *Model, ev daemon.Event) tea.Cmd
+
+// eventMiddleware wraps the handlers: it sees every event before them,
+// and can rewrite or drop it, or act after they have run.
+type eventMiddleware func(next eventHandler) eventHandler
+
+// eventBus routes events to their handlers through its middleware.
+type eventBus struct {
+	handlers   map[string][]eventHandler
+	middleware []eventMiddleware
+	// chain is route wrapped in middleware, built as it is registered
+	// so dispatch costs no more than a switch.
+	chain eventHandler
+}
+
+func newEventBus() *eventBus {
+	b := &eventBus{handlers: map[string][]eventHandler{}}
+	b.chain = b.route
+	return b
+}
+
+// on registers h for each of events. Handlers of one event run in the
+// order registered, and their commands are batched.
+func (b *eventBus) on(h eventHandler, events ...string) *eventBus {
+	for _, e := range events {
+		b.handlers[e] = append(b.handlers[e], h)
+	}
+	return b
+}
+
+// use adds middleware; the first added sees events first.
+func (b *eventBus) use(mw ...eventMiddleware) *eventBus {
+	b.middleware = append(b.middleware, mw...)
+	b.chain = b.route
+	for i := len(b.middleware) - 1; i >= 0; i-- {
+		b.chain = b.middleware[i](b.chain)
+	}
+	return b
+}
+
+// dispatch applies ev to m.
+func (b *eventBus) dispatch(m *Model, ev daemon.Event) tea.Cmd {
+	return b.chain(m, ev)
+}
+
+// route runs the handlers registered for ev. Events with none, such as
+// those from a newer daemon, are ignored.
+func (b *eventBus) route(m *Model, ev daemon.Event) tea.Cmd {
+	hs := b.handlers[ev.Event]
+	if len(hs) == 1 {
+		return hs[0](m, ev)
+	}
+	var cmds []tea.Cmd
+	for _, h := range hs {
+		cmds = append(cmds, h(m, ev))
+	}
+	return tea.Batch(cmds...)
+}
+
+// dedupSegments drops a segment event the transcript already shows with
+// the same text, as a daemon may redeliver after a resubscribe, so it
+// isn't counted toward talk time or checked against alerts twice. A
+// redelivery with new text, such as a correction, still goes through.
+func dedupSegments(next eventHandler) eventHandler {
+	return func(m *Model, ev daemon.Event) tea.Cmd {
+		if ev.Event == "segment" && ev.SequenceNumber != nil && m.hasSegment(ev) {
+			return nil
+		}
+		return next(m, ev)
+	}
+}
+
+// hasSegment reports whether the entries hold ev's segment with ev's
+// text. It scans back only as far as the segment's place in its session.
+func (m *Model) hasSegment(ev daemon.Event) bool {
+	sessionID := ev.SessionID
+	if sessionID == "" {
+		sessionID = m.sessionID
+	}
+	for i := len(m.entries) - 1; i >= 0; i-- {
+		e := m.entries[i]
+		if e.IsBoundary || e.SessionID != sessionID || e.SeqNum == 0 {
+			continue
+		}
+		if e.SeqNum < *ev.SequenceNumber {
+			return false
+		}
+		if e.SeqNum == *ev.SequenceNumber {
+			return e.Text == ev.Text
+		}
+	}
+	return false
+}
+
+// recoveryEvents names the recovery events the daemon multiplexes onto
+// `error` (see EventBroadcaster.mapEvent), by message prefix.
+var recoveryEvents = []string{"recovering", "healed", "recovery_exhausted"}
+
+// demuxRecovery rewrites an `error` event carrying a recovery message
+// into its own event, so each recovery state has a handler of its own.
+// When the daemon sends dedicated events, this becomes a no-op.
+func demuxRecovery(next eventHandler) eventHandler {
+	return func(m *Model, ev daemon.Event) tea.Cmd {
+		if ev.Event == "error" {
+			for _, name := range recoveryEvents {
+				if strings.HasPrefix(ev.Message, name+":") {
+					ev.Event = name
+					break
+				}
+			}
+		}
+		return next(m, ev)
+	}
+}
+
+// eventLogEnv names a file to log daemon events to, for debugging what
+// the TUI was sent.
+const eventLogEnv = "STENO_EVENT_LOG"
+
+// logEvents writes a line per event to w: when, which event, and the
+// session, sequence number and source it concerns. Transcript text is
+// left out, so a log can be attached to an issue.
+func logEvents(w io.Writer) eventMiddleware {
+	return func(next eventHandler) eventHandler {
+		return func(m *Model, ev daemon.Event) tea.Cmd {
+			line := m.now().Format("15:04:05.000") + " " + ev.Event
+			if ev.SessionID != "" {
+				line += " session=" + ev.SessionID
+			}
+			if ev.SequenceNumber != nil {
+				line += fmt.Sprintf(" seq=%d", *ev.SequenceNumber)
+			}
+			if ev.Source != "" {
+				line += " source=" + ev.Source
+			}
+			io.WriteString(w, line+"\n")
+			return next(m, ev)
+		}
+	}
+}
+
+// eventLogFromEnv opens the eventLogEnv file for appending, or returns
+// nil when it is unset or can't be opened.
+func eventLogFromEnv() io.Writer {
+	path := os.Getenv(eventLogEnv)
+	if path == "" {
+		return nil
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil
+	}
+	return f
+}
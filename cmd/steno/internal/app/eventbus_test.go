@@ -0,0 +1,104 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jwulff/steno/internal/daemon"
+)
+
+func TestEventBusRoutesThroughMiddleware(t *testing.T) {
+	var trace []string
+	record := func(name string) eventHandler {
+		return func(m *Model, ev daemon.Event) tea.Cmd {
+			trace = append(trace, name+":"+ev.Event)
+			return nil
+		}
+	}
+	wrap := func(name string) eventMiddleware {
+		return func(next eventHandler) eventHandler {
+			return func(m *Model, ev daemon.Event) tea.Cmd {
+				trace = append(trace, name)
+				return next(m, ev)
+			}
+		}
+	}
+	drop := func(next eventHandler) eventHandler {
+		return func(m *Model, ev daemon.Event) tea.Cmd {
+			if ev.Event == "level" {
+				return nil
+			}
+			return next(m, ev)
+		}
+	}
+	b := newEventBus().use(wrap("outer"), wrap("inner"), drop)
+	b.on(record("a"), "segment", "status").on(record("b"), "segment")
+
+	m := New()
+	for _, name := range []string{"segment", "status", "level", "unknown"} {
+		b.dispatch(&m, daemon.Event{Event: name})
+	}
+	want := "outer inner a:segment b:segment outer inner a:status outer inner outer inner"
+	if got := strings.Join(trace, " "); got != want {
+		t.Errorf("trace = %q, want %q", got, want)
+	}
+}
+
+func TestDedupSegmentsDropsRedelivery(t *testing.T) {
+	m := New()
+	m.sessionID = "s1"
+	m.handleEvent(segmentEvent("s1", 1, "one two three"))
+	m.handleEvent(segmentEvent("s1", 2, "four"))
+	talk := m.talk.segments[-1].duration
+
+	m.handleEvent(segmentEvent("s1", 1, "one two three"))
+	if got := m.talk.segments[-1].duration; got != talk {
+		t.Errorf("talk time after a redelivered segment = %v, want %v", got, talk)
+	}
+	m.handleEvent(segmentEvent("s1", 1, "one two tree"))
+	if got := entryTexts(m); got != "one two tree four" {
+		t.Errorf("a corrected redelivery should replace the entry, got %q", got)
+	}
+	// The same number in another session is a different segment.
+	m.handleEvent(segmentEvent("s2", 1, "four"))
+	if got := entryTexts(m); got != "one two tree four four" {
+		t.Errorf("entries = %q", got)
+	}
+}
+
+func TestDemuxRecoveryRoutesByPrefix(t *testing.T) {
+	var got []string
+	b := newEventBus().use(demuxRecovery)
+	b.on(func(m *Model, ev daemon.Event) tea.Cmd {
+		got = append(got, ev.Event)
+		return nil
+	}, "error", "recovering", "healed", "recovery_exhausted")
+
+	m := New()
+	for _, msg := range []string{"recovering: mic", "healed: gap=2s", "recovery_exhausted: mic", "Mic busy", "note: recovering: later"} {
+		b.dispatch(&m, daemon.Event{Event: "error", Message: msg})
+	}
+	if want := "recovering healed recovery_exhausted error error"; strings.Join(got, " ") != want {
+		t.Errorf("routed to %v, want %s", got, want)
+	}
+}
+
+func TestLogEventsLeavesOutText(t *testing.T) {
+	var log strings.Builder
+	m := New().WithClock(newManualClock())
+	m.events = newDefaultEventBus(&log)
+	m.handleEvent(segmentEvent("s1", 7, "my bank password is hunter2"))
+	m.handleEvent(daemon.Event{Event: "status", Recording: daemon.BoolPtr(true)})
+
+	lines := strings.Split(strings.TrimSpace(log.String()), "\n")
+	if len(lines) != 2 || !strings.HasSuffix(lines[0], "segment session=s1 seq=7 source=microphone") || !strings.HasSuffix(lines[1], " status") {
+		t.Errorf("log =\n%s", log.String())
+	}
+	if strings.Contains(log.String(), "hunter2") {
+		t.Error("the event log must not include transcript text")
+	}
+	if len(m.entries) != 1 || !m.recording {
+		t.Errorf("logged events should still be applied: entries = %d, recording = %v", len(m.entries), m.recording)
+	}
+}
@@ -0,0 +1,266 @@
+package app
+
+import (
+	"io"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jwulff/steno/internal/daemon"
+)
+
+// newDefaultEventBus registers the TUI's handlers for the daemon's
+// events (mirrored from EventBroadcaster.swift). log, when not nil,
+// receives a line per event; see logEvents.
+func newDefaultEventBus(log io.Writer) *eventBus {
+	b := newEventBus()
+	if log != nil {
+		b.use(logEvents(log))
+	}
+	b.use(dedupSegments, demuxRecovery)
+
+	b.on(onPartial, "partial")
+	// A segment goes into the transcript first, then is checked for a
+	// decision and against alerting saved searches.
+	b.on(onSegment, "segment")
+	b.on(onSegmentDecision, "segment")
+	b.on(onSegmentSearchAlert, "segment")
+	b.on(onSpeaker, "speaker")
+	b.on(onLevel, "level")
+	b.on(onStatus, "status")
+	b.on(onLocale, "locale")
+	b.on(onArmed, "armed")
+	b.on(func(m *Model, _ daemon.Event) tea.Cmd { return m.autoStarted() }, "auto_started")
+	b.on((*Model).callEvent, "call_started", "call_ended")
+	b.on(onPauseState, "pause_state")
+	b.on(onModelProcessing, "model_processing")
+	b.on(onPressure, "pressure")
+	b.on(onTopics, "topics")
+	b.on(onRecovering, "recovering")
+	b.on(onHealed, "healed")
+	b.on(onRecoveryExhausted, "recovery_exhausted")
+	b.on(onError, "error")
+	return b
+}
+
+// stockEvents is the bus of a Model built without NewWithConfig.
+var stockEvents = newDefaultEventBus(nil)
+
+func onPartial(m *Model, ev daemon.Event) tea.Cmd {
+	if ev.Text == "" {
+		delete(m.partials, ev.Source)
+	} else {
+		m.partials[ev.Source] = ev.Text
+		m.qualityMonitor().Transcribed()
+	}
+	return nil
+}
+
+// segmentEntry is the transcript entry for a segment event.
+func (m *Model) segmentEntry(ev daemon.Event) TranscriptEntry {
+	ts := m.now()
+	if ev.StartedAt != nil {
+		ts = timeFromUnix(*ev.StartedAt)
+	}
+	entry := TranscriptEntry{
+		Text:      ev.Text,
+		Source:    ev.Source,
+		Speaker:   ev.Speaker,
+		Timestamp: ts,
+		SessionID: ev.SessionID,
+	}
+	if entry.SessionID == "" {
+		entry.SessionID = m.sessionID
+	}
+	if ev.SequenceNumber != nil {
+		entry.SeqNum = *ev.SequenceNumber
+	}
+	return entry
+}
+
+func onSegment(m *Model, ev daemon.Event) tea.Cmd {
+	entry := m.segmentEntry(ev)
+	// A segment of a session the transcript hasn't shown means the daemon
+	// moved on: another client demarcated, or recording auto-started. A
+	// replayed segment of an earlier session is no such news, and a
+	// viewer follows its host's status instead.
+	var moved tea.Cmd
+	if m.sessionID != "" && entry.SessionID != m.sessionID && m.viewAddr == "" && !m.showsSession(entry.SessionID) {
+		moved = m.enterSession(entry.SessionID)
+	}
+	// Sequence numbers count the session's segments.
+	if entry.SessionID == m.sessionID && entry.SeqNum > m.segmentCount {
+		m.segmentCount = entry.SeqNum
+	}
+	late := m.insertSegment(entry)
+	m.countTalk(entry, segmentDuration(ev))
+	// Today's daemon sends no speaker events, so a live segment's label
+	// is the latest word on who is speaking.
+	if ev.Speaker != "" && !late && entry.SessionID == m.sessionID {
+		m.applySpeaker(ev)
+	}
+	delete(m.partials, ev.Source)
+	m.qualityMonitor().Transcribed()
+	if m.transcriptLive {
+		m.scrollToBottom()
+	}
+	m.lastSegmentAt = m.now()
+	if late {
+		// Re-render once the highlight has run its course.
+		return tea.Batch(moved, m.tick(backfillHighlightTTL, func(time.Time) tea.Msg { return BackfillHighlightMsg{} }))
+	}
+	return moved
+}
+
+// showsSession reports whether the transcript has a segment of sessionID.
+func (m *Model) showsSession(sessionID string) bool {
+	for _, e := range m.entries {
+		if e.SessionID == sessionID && !e.IsBoundary {
+			return true
+		}
+	}
+	return false
+}
+
+func onSegmentDecision(m *Model, ev daemon.Event) tea.Cmd {
+	return m.flagDecision(m.segmentEntry(ev))
+}
+
+func onSegmentSearchAlert(m *Model, ev daemon.Event) tea.Cmd {
+	return m.checkSearchAlerts(m.segmentEntry(ev))
+}
+
+func onSpeaker(m *Model, ev daemon.Event) tea.Cmd {
+	m.countSpeakerTurn(ev.Speaker)
+	m.applySpeaker(ev)
+	return nil
+}
+
+func onLevel(m *Model, ev daemon.Event) tea.Cmd {
+	if ev.Mic != nil {
+		m.micLevel = *ev.Mic
+	}
+	if ev.Sys != nil {
+		m.sysLevel = *ev.Sys
+	}
+	m.qualityMonitor().Level(float64(max(m.micLevel, m.sysLevel)), m.now())
+	return nil
+}
+
+// onStatus translates `recording` into engineStatus. Recording that stops
+// without a stop or pause from the TUI, e.g. a stop from another client
+// or a capture failure, alerts.
+func onStatus(m *Model, ev daemon.Event) tea.Cmd {
+	if ev.Recording == nil {
+		return nil
+	}
+	was := m.recording
+	m.recording = *ev.Recording
+	// The daemon's status events name no session; a sharing host's do.
+	if ev.SessionID != "" {
+		m.sessionID = ev.SessionID
+	}
+	if m.recording {
+		m.statusText = "Recording"
+		m.engineStatus = StatusRecording
+		// Successful recording resumes clear permission-revoked.
+		m.permissionRevoked = false
+		m.stopRequested = false
+		return nil
+	}
+	// Status with recording=false in the always-on world most likely
+	// means paused. Don't blindly write "Idle" over a known paused
+	// state.
+	var alert tea.Cmd
+	if m.engineStatus != StatusPaused {
+		if was && !m.stopRequested {
+			alert = m.alert()
+		}
+		m.statusText = "Idle"
+		m.engineStatus = StatusIdle
+	}
+	m.stopRequested = false
+	m.partials = make(map[string]string)
+	return alert
+}
+
+func onLocale(m *Model, ev daemon.Event) tea.Cmd {
+	if ev.Locale != "" {
+		m.locale = ev.Locale
+	}
+	return nil
+}
+
+func onArmed(m *Model, ev daemon.Event) tea.Cmd {
+	if ev.Armed != nil {
+		m.armed = *ev.Armed
+	}
+	return nil
+}
+
+// onPauseState handles U10's dedicated pause-state event;
+// applyPauseFields handles the indefinite / finite split and the resume
+// transition.
+func onPauseState(m *Model, ev daemon.Event) tea.Cmd {
+	m.applyPauseFields(ev.Paused, ev.PausedIndefinitely, ev.PauseExpiresAt)
+	return nil
+}
+
+func onModelProcessing(m *Model, ev daemon.Event) tea.Cmd {
+	if ev.ModelProcessing != nil {
+		m.modelProcessing = *ev.ModelProcessing
+	}
+	return nil
+}
+
+// onTopics applies a new topic list. Newer daemons send the list itself;
+// older ones only signal that it changed. The session may have just been
+// titled, which can match it to an earlier one for speaker names, and a
+// new summary was written in the same pass.
+func onTopics(m *Model, ev daemon.Event) tea.Cmd {
+	if len(ev.Topics) > 0 {
+		return tea.Batch(m.applyTopics(m.topicsFromEvent(ev.Topics)), m.speakerNamesCmd(), m.summaryCmd())
+	}
+	if m.store != nil && m.sessionID != "" {
+		m.topicsLoading = true
+		return tea.Batch(loadTopicsCmd(m.store, m.sessionID), m.speakerNamesCmd(), m.summaryCmd())
+	}
+	return nil
+}
+
+// onRecovering marks the pipeline as restarting. It stays off the error
+// bar: the status bar carries it.
+func onRecovering(m *Model, _ daemon.Event) tea.Cmd {
+	m.engineStatus = StatusRecovering
+	m.recoveringStartedAt = m.now()
+	return nil
+}
+
+// onHealed reports a successful pipeline restart. If we're still in
+// recovering, drop back to recording; the next `status` event will
+// reaffirm. The heal_marker column arrives with the segments read from
+// the DB on topic expansion.
+func onHealed(m *Model, ev daemon.Event) tea.Cmd {
+	if m.engineStatus == StatusRecovering {
+		m.engineStatus = StatusRecording
+	}
+	return m.pushError(SeverityInfo, "audio recovered ("+strings.TrimSpace(strings.TrimPrefix(ev.Message, "healed:"))+")", true)
+}
+
+// onRecoveryExhausted reports that the daemon gave up restarting.
+func onRecoveryExhausted(m *Model, ev daemon.Event) tea.Cmd {
+	m.engineStatus = StatusError
+	if strings.Contains(ev.Message, MicOrScreenPermissionRevoked) {
+		m.permissionRevoked = true
+	}
+	return m.pushError(SeverityError, ev.Message, false)
+}
+
+// onError shows a daemon error. Transient ones are warnings that
+// auto-clear; the rest stay on the bar as errors.
+func onError(m *Model, ev daemon.Event) tea.Cmd {
+	if ev.Transient != nil && *ev.Transient {
+		return m.pushError(SeverityWarn, ev.Message, true)
+	}
+	return m.pushError(SeverityError, ev.Message, false)
+}
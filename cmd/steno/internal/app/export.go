@@ -0,0 +1,123 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jwulff/steno/internal/config"
+	"github.com/jwulff/steno/internal/db"
+	"github.com/jwulff/steno/internal/export"
+	"github.com/jwulff/steno/internal/version"
+)
+
+// exportJob is a running `W` export of the current session's Markdown
+// transcript. It is written a step at a time, so the status bar can show
+// how far along it is; a quit mid-way leaves the file's progress
+// manifest, and the next W for the session picks up from it.
+type exportJob struct {
+	path        string
+	done, total int
+	resumed     int
+}
+
+// exportPath is where W exports a session, named by session, and by
+// segment range for part of one, so an interrupted export is found
+// again.
+func exportPath(cfg config.ExportConfig, sessionID string, r *export.SegmentRange) string {
+	name := "steno-" + sessionID
+	if r != nil {
+		name += fmt.Sprintf("-%d-%d", r.First, r.Last)
+	}
+	return filepath.Join(cfg.Directory(), name+".md")
+}
+
+// loadExportTranscriptCmd loads the session's transcript for the export
+// dialog.
+func loadExportTranscriptCmd(store *db.Store, sessionID string) tea.Cmd {
+	return func() tea.Msg {
+		t, err := export.LoadTranscript(store, sessionID)
+		return ExportTranscriptLoadedMsg{SessionID: sessionID, Transcript: t, Err: err}
+	}
+}
+
+// startExportCmd starts or resumes the export of t, the part r of its
+// session (all of it when nil), anonymized with the configured preset
+// as `steno export` would.
+func startExportCmd(t export.Transcript, r *export.SegmentRange, cfg config.ExportConfig, now time.Time) tea.Cmd {
+	return func() tea.Msg {
+		path := exportPath(cfg, t.SessionID, r)
+		manifest := export.Manifest{
+			Tool:       "steno",
+			Version:    version.Version,
+			ExportedAt: now.UTC().Truncate(time.Second),
+			Format:     export.TranscriptFormatMarkdown,
+			SessionID:  t.SessionID,
+			Segments:   len(t.Lines),
+			Range:      r,
+		}
+		if cfg.Anonymize != "" {
+			preset, err := export.LookupPreset(cfg.Anonymize)
+			if err != nil {
+				return ExportProgressMsg{Path: path, Err: err}
+			}
+			var rec export.Anonymization
+			if t, rec, err = export.Anonymize(t, preset); err != nil {
+				return ExportProgressMsg{Path: path, Err: err}
+			}
+			manifest.SessionID = t.SessionID
+			manifest.Anonymization = &rec
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return ExportProgressMsg{Path: path, Err: err}
+		}
+		e, err := export.ResumeExport(path, export.TranscriptFormatMarkdown, t, export.SubtitleOptions{})
+		if err != nil {
+			return ExportProgressMsg{Path: path, Err: err}
+		}
+		return ExportProgressMsg{Path: path, Export: e, Manifest: manifest, Done: e.Done(), Total: e.Total(), Resumed: e.Resumed()}
+	}
+}
+
+// exportStepCmd writes the next checkpoint's worth of lines, and the
+// manifest once the file is complete.
+func exportStepCmd(e *export.FileExport, manifest export.Manifest, resumed int) tea.Cmd {
+	return func() tea.Msg {
+		complete, err := e.Write(export.CheckpointLines)
+		if err != nil {
+			e.Close()
+		} else if complete {
+			err = export.WriteManifestFile(e.Path(), manifest)
+		}
+		return ExportProgressMsg{Path: e.Path(), Export: e, Manifest: manifest, Done: e.Done(), Total: e.Total(), Resumed: resumed, Complete: complete, Err: err}
+	}
+}
+
+// applyExportProgress records a step's progress and starts the next.
+func (m Model) applyExportProgress(msg ExportProgressMsg) (tea.Model, tea.Cmd) {
+	if msg.Err != nil {
+		m.exporting = nil
+		return m, m.pushError(SeverityWarn, "export: "+msg.Err.Error(), true)
+	}
+	if msg.Complete {
+		m.exporting = nil
+		m.notice = "Transcript exported to " + msg.Path
+		return m, m.clearNoticeCmd()
+	}
+	m.exporting = &exportJob{path: msg.Path, done: msg.Done, total: msg.Total, resumed: msg.Resumed}
+	return m, exportStepCmd(msg.Export, msg.Manifest, msg.Resumed)
+}
+
+// status is the status bar's progress line for a running export.
+func (j *exportJob) status() string {
+	if j.total == 0 {
+		return "Exporting transcript…"
+	}
+	s := fmt.Sprintf("Exporting transcript %d/%d segments (%d%%)", j.done, j.total, j.done*100/j.total)
+	if j.resumed > 0 {
+		s += fmt.Sprintf(", resumed at %d", j.resumed)
+	}
+	return s
+}
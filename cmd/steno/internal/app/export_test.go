@@ -0,0 +1,92 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jwulff/steno/internal/export"
+)
+
+func TestExportShowsProgressUntilComplete(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "steno-sess-1.md")
+	tr := export.Transcript{SessionID: "sess-1"}
+	for i := range 1200 {
+		tr.Lines = append(tr.Lines, export.TranscriptLine{Seq: i + 1, Offset: time.Duration(i) * time.Second, Speaker: "mic", Text: fmt.Sprintf("line %d", i+1)})
+	}
+	e, err := export.ResumeExport(path, export.TranscriptFormatMarkdown, tr, export.SubtitleOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := New()
+	m.width = 120
+	m.connected = true
+	m.exporting = &exportJob{path: path}
+	var msg tea.Msg = ExportProgressMsg{Path: path, Export: e, Manifest: export.Manifest{Format: "markdown"}, Total: e.Total()}
+	steps := 0
+	for {
+		var cmd tea.Cmd
+		m, cmd = applyUpdate(m, msg)
+		if m.exporting == nil {
+			break
+		}
+		if m.exporting.total != 1200 || !strings.Contains(m.renderStatusBar(), "Exporting transcript") {
+			t.Fatalf("exporting = %+v, status bar = %q", m.exporting, m.renderStatusBar())
+		}
+		msgs := runCmd(cmd)
+		if len(msgs) != 1 {
+			t.Fatalf("step msgs = %v", msgs)
+		}
+		msg = msgs[0]
+		steps++
+	}
+	if steps != 3 {
+		t.Errorf("steps = %d, want 3 of %d lines", steps, export.CheckpointLines)
+	}
+	if !strings.Contains(m.notice, "Transcript exported to "+path) {
+		t.Errorf("notice = %q", m.notice)
+	}
+	if _, err := os.Stat(export.ManifestPath(path)); err != nil {
+		t.Errorf("manifest: %v", err)
+	}
+	if _, err := os.Stat(export.ProgressPath(path)); !os.IsNotExist(err) {
+		t.Errorf("progress manifest left behind: %v", err)
+	}
+}
+
+func TestExportKeyAndFailure(t *testing.T) {
+	m := New()
+	m.connected = true
+	if _, cmd := applyUpdate(m, runeKey('W')); cmd != nil {
+		t.Error("W without a session should do nothing")
+	}
+
+	m.sessionID = "sess-1"
+	m.store = stubStore()
+	m.exportConfig.Dir = t.TempDir()
+	m, cmd := applyUpdate(m, runeKey('W'))
+	if cmd == nil || m.exportDialog == nil {
+		t.Fatalf("W should open the export dialog and load the session")
+	}
+	m, _ = applyUpdate(m, ExportTranscriptLoadedMsg{SessionID: "sess-1", Transcript: export.Transcript{
+		SessionID: "sess-1", Lines: []export.TranscriptLine{{Seq: 1, Text: "hello"}},
+	}})
+	m, cmd = applyUpdate(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil || m.exportDialog != nil || m.exporting == nil || m.exporting.path != filepath.Join(m.exportConfig.Dir, "steno-sess-1.md") {
+		t.Fatalf("enter: exporting = %+v, cmd = %v", m.exporting, cmd)
+	}
+	if _, cmd := applyUpdate(m, runeKey('W')); cmd != nil {
+		t.Error("W while an export runs should do nothing")
+	}
+
+	m, _ = applyUpdate(m, ExportProgressMsg{Path: m.exporting.path, Err: errors.New("disk full")})
+	if m.exporting != nil || len(m.errorStack) != 1 || !strings.Contains(m.errorStack[0].Message, "disk full") {
+		t.Errorf("after failure: exporting = %+v, errors = %+v", m.exporting, m.errorStack)
+	}
+}
@@ -0,0 +1,214 @@
+package app
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jwulff/steno/internal/export"
+	"github.com/jwulff/steno/internal/ui"
+)
+
+// exportDialog is the `W` dialog: which part of the current session to
+// export. From and To each take a segment number or an m:ss offset
+// (see export.ParseBound), or stay empty for the session's start or
+// end; ←/→ scrub the focused one a segment at a time. It previews the
+// slice before enter exports it.
+type exportDialog struct {
+	sessionID  string
+	from, to   textInput
+	toFocused  bool
+	transcript export.Transcript
+	loaded     bool
+	err        error
+}
+
+// exportSlice is what the dialog's bounds select.
+type exportSlice struct {
+	transcript export.Transcript
+	// r is the slice's segment range; nil when it is the whole session.
+	r   *export.SegmentRange
+	err error
+}
+
+// slice resolves the dialog's bounds against the loaded transcript.
+func (d *exportDialog) slice() exportSlice {
+	from, err := export.ParseBound(d.from.String())
+	if err != nil {
+		return exportSlice{err: fmt.Errorf("from: %w", err)}
+	}
+	to, err := export.ParseBound(d.to.String())
+	if err != nil {
+		return exportSlice{err: fmt.Errorf("to: %w", err)}
+	}
+	t := d.transcript.Slice(from, to)
+	if len(t.Lines) == 0 {
+		return exportSlice{transcript: t, err: fmt.Errorf("no segments in that range")}
+	}
+	var r *export.SegmentRange
+	if len(t.Lines) < len(d.transcript.Lines) {
+		r = &export.SegmentRange{First: t.Lines[0].Seq, Last: t.Lines[len(t.Lines)-1].Seq}
+	}
+	return exportSlice{transcript: t, r: r}
+}
+
+// scrub moves the focused bound delta segments, starting from the
+// slice's first or last segment.
+func (d *exportDialog) scrub(delta int) {
+	lines := d.transcript.Lines
+	if len(lines) == 0 {
+		return
+	}
+	i := 0
+	if d.toFocused {
+		i = len(lines) - 1
+	}
+	if sl := d.slice(); len(sl.transcript.Lines) > 0 {
+		edge := sl.transcript.Lines[0]
+		if d.toFocused {
+			edge = sl.transcript.Lines[len(sl.transcript.Lines)-1]
+		}
+		for j, l := range lines {
+			if l.Seq == edge.Seq {
+				i = j
+			}
+		}
+		i += delta
+	}
+	i = min(max(i, 0), len(lines)-1)
+	field := &d.from
+	if d.toFocused {
+		field = &d.to
+	}
+	field.value = []rune(strconv.Itoa(lines[i].Seq))
+}
+
+// openExportDialog opens the dialog for the current session (W) and
+// loads its transcript. It does nothing while an export is running.
+func (m Model) openExportDialog() (tea.Model, tea.Cmd) {
+	if m.store == nil || m.sessionID == "" || m.exporting != nil {
+		return m, nil
+	}
+	m.exportDialog = &exportDialog{sessionID: m.sessionID}
+	return m, loadExportTranscriptCmd(m.store, m.sessionID)
+}
+
+func (m Model) applyExportTranscript(msg ExportTranscriptLoadedMsg) (tea.Model, tea.Cmd) {
+	if m.exportDialog == nil || msg.SessionID != m.exportDialog.sessionID {
+		return m, nil
+	}
+	d := *m.exportDialog
+	d.transcript, d.err, d.loaded = msg.Transcript, msg.Err, true
+	m.exportDialog = &d
+	return m, nil
+}
+
+// handleExportDialogKey handles keys while the export dialog is open:
+// text edits the focused bound, tab switches bounds, ←/→ scrub, enter
+// exports the slice and esc cancels.
+func (m Model) handleExportDialogKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	d := *m.exportDialog
+	switch msg.String() {
+	case KeyCtrlC:
+		return m.quit()
+	case KeyEsc:
+		m.exportDialog = nil
+		return m, nil
+	case KeyTab, "shift+tab", KeyUp, KeyDown:
+		d.toFocused = !d.toFocused
+	case KeyLeft:
+		d.scrub(-1)
+	case KeyRight:
+		d.scrub(1)
+	case KeyEnter:
+		if !d.loaded || d.err != nil {
+			return m, nil
+		}
+		sl := d.slice()
+		if sl.err != nil {
+			return m, nil
+		}
+		m.exportDialog = nil
+		m.exporting = &exportJob{path: exportPath(m.exportConfig, d.sessionID, sl.r)}
+		return m, startExportCmd(sl.transcript, sl.r, m.exportConfig, m.now())
+	default:
+		if d.toFocused {
+			d.to.handle(msg)
+		} else {
+			d.from.handle(msg)
+		}
+	}
+	m.exportDialog = &d
+	return m, nil
+}
+
+// renderExportDialog renders the dialog in place of the main panels:
+// the two bounds, a bar placing the slice in the session, and what it
+// holds.
+func (m Model) renderExportDialog() string {
+	d := m.exportDialog
+	width := max(20, m.width-6)
+	field := func(label string, in textInput, focused bool, open string) string {
+		v := in.String() + " "
+		if focused {
+			v = in.view("")
+		}
+		line := fmt.Sprintf("%s%-5s %s", ui.Marker(focused), label, v)
+		if in.String() == "" && !focused {
+			line += ui.DimStyle.Render(open)
+		}
+		return line
+	}
+	lines := []string{
+		ui.PanelTitleActiveStyle.Render("Export transcript"),
+		field("From", d.from, !d.toFocused, "(session start)"),
+		field("To", d.to, d.toFocused, "(session end)"),
+		"",
+	}
+	switch {
+	case !d.loaded:
+		lines = append(lines, ui.DimStyle.Render("Loading the session…"))
+	case d.err != nil:
+		lines = append(lines, ui.ErrorStyle.Render("Can't export: "+d.err.Error()))
+	default:
+		sl := d.slice()
+		if sl.err != nil {
+			lines = append(lines, ui.WarnStyle.Render(sl.err.Error()))
+			break
+		}
+		lines = append(lines, exportSliceBar(d.transcript, sl.transcript, min(60, width)))
+		all, got := d.transcript.Lines, sl.transcript.Lines
+		first, last := got[0], got[len(got)-1]
+		lines = append(lines, fmt.Sprintf("Segments %d–%d · %s–%s · %d of %d segments",
+			first.Seq, last.Seq, formatReplayOffset(first.Offset), formatReplayOffset(last.End), len(got), len(all)))
+		if sl.r != nil {
+			lines = append(lines, ui.DimStyle.Render("To "+exportPath(m.exportConfig, d.sessionID, sl.r)))
+		} else {
+			lines = append(lines, ui.DimStyle.Render("The whole session, to "+exportPath(m.exportConfig, d.sessionID, nil)))
+		}
+	}
+	lines = append(lines, ui.DimStyle.Render("segment # or m:ss · tab switch · ←/→ scrub · enter export · esc cancel"))
+	return ui.SessionBrowserStyle.Render(strings.Join(lines, "\n"))
+}
+
+// exportSliceBar draws the session as a bar width wide, with the slice's
+// stretch of it filled in.
+func exportSliceBar(all, slice export.Transcript, width int) string {
+	total := all.Lines[len(all.Lines)-1].End
+	col := func(l export.TranscriptLine, end bool) int {
+		if total <= 0 {
+			return 0
+		}
+		at := l.Offset
+		if end {
+			at = l.End
+		}
+		return min(width-1, int(int64(width)*int64(at)/int64(total)))
+	}
+	lo := col(slice.Lines[0], false)
+	hi := max(lo, col(slice.Lines[len(slice.Lines)-1], true))
+	return ui.DimStyle.Render(strings.Repeat("─", lo)) +
+		ui.CursorStyle.Render(strings.Repeat("█", hi-lo+1)) +
+		ui.DimStyle.Render(strings.Repeat("─", width-hi-1))
+}
@@ -0,0 +1,95 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jwulff/steno/internal/export"
+)
+
+// exportDialogModel has the dialog open on a 10-segment session, a
+// segment a minute.
+func exportDialogModel(t *testing.T) Model {
+	t.Helper()
+	m := testModel(t, withSession("sess-1"))
+	m.exportConfig.Dir = t.TempDir()
+	m, _ = applyUpdate(m, runeKey('W'))
+	tr := export.Transcript{SessionID: "sess-1"}
+	for i := range 10 {
+		at := time.Duration(i) * time.Minute
+		tr.Lines = append(tr.Lines, export.TranscriptLine{Seq: i + 1, Offset: at, End: at + 50*time.Second, Speaker: "mic", Text: "line"})
+	}
+	m, _ = applyUpdate(m, ExportTranscriptLoadedMsg{SessionID: "sess-1", Transcript: tr})
+	return m
+}
+
+func TestExportDialogPreviewsSlice(t *testing.T) {
+	m := exportDialogModel(t)
+	if view := m.View(); !strings.Contains(view, "Segments 1–10 · 0:00–9:50 · 10 of 10 segments") || !strings.Contains(view, "The whole session") {
+		t.Fatalf("dialog without bounds should cover the session:\n%s", view)
+	}
+
+	m = typeQuery(m, "2:00")
+	m, _ = applyUpdate(m, tea.KeyMsg{Type: tea.KeyTab})
+	m = typeQuery(m, "#6")
+	if view := m.View(); !strings.Contains(view, "Segments 3–6 · 2:00–5:50 · 4 of 10 segments") || !strings.Contains(view, "steno-sess-1-3-6.md") {
+		t.Errorf("dialog view:\n%s", view)
+	}
+
+	// ←/→ scrub the focused bound a segment at a time.
+	m, _ = applyUpdate(m, tea.KeyMsg{Type: tea.KeyRight})
+	m, _ = applyUpdate(m, tea.KeyMsg{Type: tea.KeyRight})
+	if got := m.exportDialog.to.String(); got != "8" {
+		t.Errorf("to after scrubbing = %q, want 8", got)
+	}
+
+	m, cmd := applyUpdate(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.exportDialog != nil || m.exporting == nil {
+		t.Fatal("enter should start the export")
+	}
+	for m.exporting != nil {
+		msgs := runCmd(cmd)
+		if len(msgs) != 1 {
+			t.Fatalf("step msgs = %v", msgs)
+		}
+		m, cmd = applyUpdate(m, msgs[0])
+	}
+	path := filepath.Join(m.exportConfig.Dir, "steno-sess-1-3-8.md")
+	if !strings.Contains(m.notice, path) {
+		t.Fatalf("notice = %q, errors = %+v", m.notice, m.errorStack)
+	}
+	md, _ := os.ReadFile(path)
+	manifest, _ := os.ReadFile(export.ManifestPath(path))
+	if !strings.Contains(string(md), "6 segments.") || !strings.Contains(string(manifest), `"first": 3`) {
+		t.Errorf("export:\n%s\nmanifest:\n%s", md, manifest)
+	}
+}
+
+func TestExportDialogRejectsBadBounds(t *testing.T) {
+	m := exportDialogModel(t)
+	m = typeQuery(m, "9:75")
+	if !strings.Contains(m.View(), `from: "9:75" is not an m:ss or h:mm:ss offset`) {
+		t.Errorf("dialog should show the parse error:\n%s", m.View())
+	}
+	if m, _ = applyUpdate(m, tea.KeyMsg{Type: tea.KeyEnter}); m.exportDialog == nil || m.exporting != nil {
+		t.Error("enter with a bad bound should do nothing")
+	}
+	for range 4 {
+		m, _ = applyUpdate(m, tea.KeyMsg{Type: tea.KeyBackspace})
+	}
+	m = typeQuery(m, "30:00")
+	if !strings.Contains(m.View(), "no segments in that range") {
+		t.Errorf("an empty slice should say so:\n%s", m.View())
+	}
+	m, _ = applyUpdate(m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.exportDialog != nil {
+		t.Error("esc should close the dialog")
+	}
+	if _, err := os.Stat(filepath.Join(m.exportConfig.Dir, "steno-sess-1.md")); !os.IsNotExist(err) {
+		t.Error("cancelling shouldn't write anything")
+	}
+}
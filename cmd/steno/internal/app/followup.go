@@ -0,0 +1,58 @@
+package app
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jwulff/steno/internal/db"
+	"github.com/jwulff/steno/internal/export"
+)
+
+// openFollowUpFile hands a follow-up .ics to the default calendar app,
+// which offers to add it. A variable so tests don't open anything.
+var openFollowUpFile = func(path string) error {
+	return exec.Command("open", path).Run()
+}
+
+// followUpCmd writes a follow-up event for topic into dir and opens it.
+// The event is at export.NextFollowUpSlot; `steno followup` picks any
+// time, or a to-do instead.
+func followUpCmd(topic db.Topic, dir string, now time.Time) tea.Cmd {
+	return func() tea.Msg {
+		f := export.NewFollowUp(export.FollowUpEvent, topic, export.NextFollowUpSlot(now), 0)
+		path := filepath.Join(dir, "steno-followup-"+topic.ID+".ics")
+		file, err := os.Create(path)
+		if err != nil {
+			return FollowUpCreatedMsg{Title: topic.Title, Err: err}
+		}
+		err = export.FollowUpICS(file, f, now)
+		if cerr := file.Close(); err == nil {
+			err = cerr
+		}
+		if err == nil {
+			err = openFollowUpFile(path)
+		}
+		return FollowUpCreatedMsg{Title: topic.Title, Start: f.Start, Err: err}
+	}
+}
+
+// createFollowUp starts a follow-up for the selected topic (topics panel
+// focused).
+func (m Model) createFollowUp() (tea.Model, tea.Cmd) {
+	if m.focusedPanel != FocusTopics || m.selectedTopic >= len(m.topics) || m.sessionID == "" {
+		return m, nil
+	}
+	t := m.topics[m.selectedTopic]
+	topic := db.Topic{
+		ID:                t.ID,
+		SessionID:         m.sessionID,
+		Title:             t.Title,
+		Summary:           t.Summary,
+		SegmentRangeStart: t.SegmentRangeStart,
+		SegmentRangeEnd:   t.SegmentRangeEnd,
+	}
+	return m, followUpCmd(topic, os.TempDir(), m.now())
+}
@@ -0,0 +1,54 @@
+package app
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/jwulff/steno/internal/config"
+)
+
+func TestFollowUpOpensCalendarEvent(t *testing.T) {
+	var opened string
+	orig := openFollowUpFile
+	t.Cleanup(func() { openFollowUpFile = orig })
+	openFollowUpFile = func(path string) error {
+		opened = path
+		return nil
+	}
+
+	m, _, _ := alertModel(config.AlertsConfig{})
+	m.sessionID = "sess-1"
+	m.topics = []TopicDisplay{{ID: "top-1", Title: "Launch date", Summary: "Move it a week.", SegmentRangeStart: 4}}
+
+	// Only with the topics panel focused.
+	if _, cmd := applyUpdate(m, runeKey('F')); cmd != nil {
+		t.Error("F with the transcript focused should do nothing")
+	}
+	m.focusedPanel = FocusTopics
+	m, cmd := applyUpdate(m, runeKey('F'))
+	msgs := runCmd(cmd)
+	if len(msgs) != 1 {
+		t.Fatalf("msgs = %v", msgs)
+	}
+	defer os.Remove(opened)
+	data, err := os.ReadFile(opened)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"SUMMARY:Follow up: Launch date", "DESCRIPTION:Move it a week.\\n\\nsteno://session/sess-1#seg-4"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("ics missing %q:\n%s", want, data)
+		}
+	}
+
+	m, _ = applyUpdate(m, msgs[0])
+	if !strings.Contains(m.notice, "Follow-up for “Launch date” opened in Calendar") {
+		t.Errorf("notice = %q", m.notice)
+	}
+	m, _ = applyUpdate(m, FollowUpCreatedMsg{Title: "Launch date", Err: errors.New("no calendar app")})
+	if len(m.errorStack) != 1 || !strings.Contains(m.errorStack[0].Message, "no calendar app") {
+		t.Errorf("error stack = %+v", m.errorStack)
+	}
+}
@@ -0,0 +1,318 @@
+package app
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jwulff/steno/internal/ui"
+)
+
+// The footer shows the keys for what the user is doing: the focused
+// panel's keys in the main view, or an open editor's or overlay's. Every
+// key is one `?` away, in the key help overlay, grouped by panel, so the
+// footer fits a normal terminal instead of running off its edge.
+
+// keyHint is one key and what it does.
+type keyHint struct {
+	key, desc string
+}
+
+func (h keyHint) render() string {
+	return ui.FooterKeyStyle.Render(h.key) + ui.FooterDescStyle.Render(" "+h.desc)
+}
+
+// keyHints returns the hints for the current context: the ones the
+// footer shows, and the ones left for the key help overlay (none when an
+// editor or overlay is open, since `?` doesn't reach the main view then).
+func (m Model) keyHints() (primary, more []keyHint) {
+	switch {
+	case m.qualityReport != nil:
+		return []keyHint{{"any key", "Continue"}}, nil
+	case m.topicEdit != nil:
+		return []keyHint{{"Tab", "Field"}, {"Enter", "Next/Save"}, {"Esc", "Cancel"}}, nil
+	case m.notes != nil:
+		return []keyHint{{"Enter", "New line"}, {"Esc", "Save & close"}}, nil
+	case m.search != nil && m.search.editing:
+		return []keyHint{{"Enter", "Search"}, {"Tab", "Saved searches"}, {"^f", "All sessions"}, {"Esc", "Cancel"}}, nil
+	case m.keyHelp:
+		return []keyHint{{"?/Esc", "Close"}, {"q", "Quit"}}, nil
+	case m.showErrorModal:
+		return []keyHint{{"e/Esc", "Close"}, {"q", "Quit"}}, nil
+	case m.switcher != nil:
+		return []keyHint{{"↑↓", "Move"}, {"Enter", "Replay"}, {"Esc", "Close"}}, nil
+	case m.sessionSearch != nil:
+		return []keyHint{{"↑↓", "Move"}, {"Enter", "Search/Replay"}, {"Esc", "Close"}}, nil
+	case m.keywords != nil:
+		return []keyHint{{"←/→", "Move"}, {"Enter", "Filter"}, {"Esc", "Close"}, {"q", "Quit"}}, nil
+	case m.entityList != nil, m.codes != nil, m.searchMenu != nil:
+		return []keyHint{{"↑↓", "Move"}, {"Enter", "Filter"}, {"Esc", "Close"}, {"q", "Quit"}}, nil
+	case m.exportDialog != nil:
+		return []keyHint{{"Tab", "From/To"}, {"←/→", "Scrub"}, {"Enter", "Export"}, {"Esc", "Cancel"}}, nil
+	case m.recovery != nil:
+		return []keyHint{{"f", "Finalize"}, {"n", "Resume"}, {"x", "Discard"}, {"Esc", "Close"}, {"q", "Quit"}}, nil
+	case m.replay != nil:
+		return []keyHint{{"Space", "Pause"}, {"1/2/4/0", "Speed"}, {"←/→", "Seek"}, {"Esc", "Back"}, {"q", "Quit"}}, nil
+	case m.browser.open:
+		return []keyHint{{"j/k", "Move"}, {"Enter", "Replay"}, {"d", "Device"}, {"a", "System audio"}, {"Esc", "Close"}, {"q", "Quit"}}, nil
+	}
+
+	if m.dbOnly {
+		return []keyHint{{"b", "Sessions"}, {"^k", "Switch"}, {"^f", "Search all"}, {"q", "Quit"}}, nil
+	}
+	if m.viewAddr != "" && m.connected {
+		// The host's controls aren't a viewer's to use.
+		return []keyHint{{"Tab", "Topics"}, {"↑↓", "Scroll"}, {"/", "Search"}, {"R", "Reading"}, {"v", "Density"}, {"q", "Quit"}}, nil
+	}
+	if !m.connected {
+		return []keyHint{{"q", "Quit"}}, nil
+	}
+	// U9: spacebar = demarcate, p / shift-p = pause toggles.
+	primary = []keyHint{{"Space", "Boundary"}}
+	if m.engineStatus == StatusPaused {
+		primary = append(primary, keyHint{"p/P", "Resume"})
+	} else {
+		primary = append(primary, keyHint{"p", "Pause 30m"}, keyHint{"P", "Pause"})
+	}
+	if !m.transcriptLive {
+		primary = append(primary, keyHint{"G", "Live"})
+	}
+	switch {
+	case m.search != nil:
+		primary = append(primary, keyHint{"n/N", "Match"}, keyHint{"Esc", "Clear search"})
+	case m.filter != nil:
+		primary = append(primary, keyHint{"Esc", "Clear filter"})
+	}
+	switch {
+	case m.reading:
+		primary = append(primary, keyHint{"↑↓", "Scroll"}, keyHint{"R", "Exit reading"})
+	case m.focusedPanel == FocusTopics:
+		primary = append(primary, keyHint{"Tab", "Transcript"}, keyHint{"j/k", "Topic"},
+			keyHint{"Enter", "Expand"}, keyHint{"r", "Edit"}, keyHint{"F", "Follow-up"})
+	default:
+		primary = append(primary, keyHint{"Tab", "Topics"}, keyHint{"j/k", "Cursor"},
+			keyHint{"↑↓", "Scroll"}, keyHint{"K", "Keywords"}, keyHint{"/", "Search"})
+	}
+	switch {
+	case m.autoStartCancellable():
+		primary = append(primary, keyHint{"A", "Cancel start"})
+	case m.armed:
+		more = append(more, keyHint{"A", "Disarm"})
+	case m.idle():
+		more = append(more, keyHint{"A", "Auto-start"})
+	}
+
+	more = append(more,
+		keyHint{"N", "Notes"},
+		keyHint{"X", "Entities"},
+		keyHint{"C", "Codes"},
+		keyHint{"U", "Speakers"},
+		keyHint{"W", "Export"},
+		keyHint{"s", "Summary"},
+		keyHint{"T", "Ticker"},
+		keyHint{"v", "Density"},
+		keyHint{"H", "Theme"},
+		keyHint{"e", "Errors"},
+		keyHint{"b", "Sessions"},
+		keyHint{"^k", "Go to"},
+		keyHint{"^f", "Search all"},
+		keyHint{"I", "Recover"},
+		keyHint{"D", "Dictation"},
+	)
+	if !m.reading {
+		more = append(more, keyHint{"R", "Reading"})
+	}
+	if m.focusedPanel == FocusTopics && !m.reading {
+		more = append(more, keyHint{"K", "Keywords"}, keyHint{"/", "Search"})
+	} else {
+		more = append(more, keyHint{"PgUp/PgDn", "Page"})
+	}
+	if len(m.capture.Locales) > 1 {
+		more = append(more, keyHint{"L", "Language"})
+	}
+	more = append(more, keyHint{"!", "Report"}, keyHint{"q", "Quit"})
+	return primary, more
+}
+
+func (m Model) renderFooter() string {
+	if m.tutorial != nil {
+		return m.renderTutorialFooter()
+	}
+	primary, more := m.keyHints()
+	var lead, trail []string
+	if m.connected {
+		// The talk-time ratio leads the key hints, where a monologue is
+		// noticed at a glance.
+		if bar := m.renderTalkBar(); bar != "" {
+			lead = append(lead, bar)
+		}
+		// A skew warning leads the footer so it survives narrow terminals;
+		// the plain version trails. A viewer has no daemon to version.
+		if m.viewAddr == "" {
+			if v, skew := m.renderVersion(); skew {
+				lead = append([]string{v}, lead...)
+			} else {
+				trail = append(trail, v)
+			}
+		}
+		if s := m.renderShare(); s != "" {
+			trail = append(trail, s)
+		}
+	}
+
+	var tail []string
+	if len(more) > 0 {
+		tail = append(tail, ui.FooterKeyStyle.Render("?")+ui.FooterDescStyle.Render(" more"))
+	}
+	// Hints that don't fit are dropped from the end; the version goes
+	// first, and `? more` is kept for them.
+	fits := func(parts []string) bool {
+		return m.width <= 0 || lipgloss.Width(strings.Join(parts, "  ")) <= m.width
+	}
+	hints := make([]string, 0, len(primary))
+	for _, h := range primary {
+		hints = append(hints, h.render())
+	}
+	for len(hints) > 1 && !fits(concat(lead, hints, tail)) {
+		hints = hints[:len(hints)-1]
+	}
+	parts := concat(lead, hints, tail)
+	if with := concat(parts, trail); fits(with) {
+		parts = with
+	}
+	return strings.Join(parts, "  ")
+}
+
+func concat(lists ...[]string) []string {
+	var out []string
+	for _, l := range lists {
+		out = append(out, l...)
+	}
+	return out
+}
+
+// handleKeyHelpKey handles keys while the key help overlay is open: ? or
+// esc closes it, q quits.
+func (m Model) handleKeyHelpKey(key string) (tea.Model, tea.Cmd) {
+	switch key {
+	case KeyQuit, KeyQuitUpper, KeyCtrlC:
+		return m.quit()
+	case KeyHelp, KeyEsc:
+		m.keyHelp = false
+	}
+	return m, nil
+}
+
+// keyGroup is the keys of one panel or screen, for the key help overlay.
+type keyGroup struct {
+	title string
+	// focused is set for the main view panel that has focus.
+	focused bool
+	hints   []keyHint
+}
+
+// keyHelpGroups lists every key by the panel or screen it works in. A
+// viewer, a database-only TUI and one waiting for the daemon have only a
+// few keys, so they get their context's hints instead.
+func (m Model) keyHelpGroups() []keyGroup {
+	if m.dbOnly || m.viewAddr != "" || !m.connected {
+		m.keyHelp = false
+		primary, more := m.keyHints()
+		return []keyGroup{{title: "Keys", hints: append(primary, more...)}}
+	}
+	// Reading mode shows the transcript alone, with no panel to focus.
+	topics := m.focusedPanel == FocusTopics && !m.reading
+	transcript := m.focusedPanel == FocusTranscript && !m.reading
+	return []keyGroup{
+		{title: "Recording", hints: []keyHint{
+			{keys(KeySpace), "Boundary"}, {keys(KeyPause), "Pause 30m / resume"}, {keys(KeyPauseIndefinite), "Pause / resume"},
+			{keys(KeyArm), "Auto-start / cancel"}, {keys(KeyLocale), "Next language"}, {keys(KeyDictation), "Dictation"},
+			{keys(KeyConsent), "Acknowledge consent"}, {keys(KeyAccept, KeyDecline), "Reuse speaker names"},
+		}},
+		{title: "Transcript", focused: transcript, hints: []keyHint{
+			{keys(KeyTab), "Topics"}, {keys(KeyJ, KeyK), "Cursor"}, {keys(KeyUp, KeyDown), "Scroll"}, {keys(KeyPageUp, KeyPageDown), "Page"},
+			{keys(KeyHome), "Oldest"}, {keys(KeyEnd, KeyJumpLive), "Live"}, {keys(KeySearch), "Search"}, {keys(KeySearchNext, KeySearchPrev), "Next/prev match"},
+			{keys(KeyEsc), "Clear search/filter"}, {keys(KeyKeywords), "Keywords"}, {keys(KeyEntities), "Entities"}, {keys(KeyCodes), "Codes"}, {keys(KeySpeakers), "Speakers"},
+		}},
+		{title: "Topics", focused: topics, hints: []keyHint{
+			{keys(KeyTab), "Transcript"}, {keys(KeyJ, KeyK), "Topic"}, {keys(KeyEnter), "Expand"}, {keys(KeyEditTopic), "Edit"}, {keys(KeyFollowUp), "Follow-up"},
+		}},
+		{title: "View", hints: []keyHint{
+			{keys(KeyDensity), "Density"}, {keys(KeyReading), "Reading"}, {keys(KeyTicker), "Ticker"}, {keys(KeyTheme), "Theme"}, {keys(KeyErrorHistory, KeyErrorHistoryUp), "Errors"},
+			{keys(KeyHelp), "Keys"},
+		}},
+		{title: "Session", hints: []keyHint{
+			{keys(KeyNotes), "Notes (Esc a search first)"}, {keys(KeyExport), "Export (e is Errors)"}, {keys(KeySessionBrowser), "Sessions"}, {keys(KeyQuickSwitcher), "Go to"}, {keys(KeySearchSessions), "Search all"},
+			{keys(KeyRecovery), "Recover"}, {keys(KeyBugReport), "Report"}, {keys(KeyQuit, KeyQuitUpper, KeyCtrlC), "Quit"},
+		}},
+		{title: "Session browser", hints: []keyHint{
+			{keys(KeyJ, KeyK), "Move"}, {keys(KeyEnter), "Replay"}, {keys(KeyBrowserDeviceFilter), "Device"}, {keys(KeyBrowserSysAudioFilter), "System audio"}, {keys(KeyEsc), "Close"},
+		}},
+		{title: "Replay", hints: []keyHint{
+			{keys(KeySpace), "Pause"}, {keys(KeyReplaySpeed1, KeyReplaySpeed2, KeyReplaySpeed4, KeyReplaySpeedMax), "Speed"}, {keys(KeyLeft, KeyRight), "Seek 30s"}, {keys(KeyEsc), "Back"},
+		}},
+		{title: "Recovery", hints: []keyHint{
+			{keys(KeyRecoverFinalize), "Finalize"}, {keys(KeyRecoverResume), "Finalize & start"}, {keys(KeyRecoverDiscard) + " " + keys(KeyRecoverDiscard), "Discard"}, {keys(KeyEsc), "Close"},
+		}},
+	}
+}
+
+// keyLabels are how the key help shows the keys that aren't their own
+// character.
+var keyLabels = map[string]string{
+	KeySpace: "Space", KeyTab: "Tab", KeyEnter: "Enter", KeyEsc: "Esc",
+	KeyUp: "↑", KeyDown: "↓", KeyLeft: "←", KeyRight: "→",
+	KeyPageUp: "PgUp", KeyPageDown: "PgDn", KeyHome: "Home", KeyEnd: "End",
+	KeyCtrlC: "^c", KeyQuickSwitcher: "^k", KeySearchSessions: "^f",
+}
+
+// keys labels one or more Key* bindings for a hint, as "j/k".
+func keys(bindings ...string) string {
+	labels := make([]string, len(bindings))
+	for i, k := range bindings {
+		labels[i] = k
+		if l, ok := keyLabels[k]; ok {
+			labels[i] = l
+		}
+	}
+	return strings.Join(labels, "/")
+}
+
+// renderKeyHelp renders every key, grouped by panel or screen, with the
+// groups laid out in as many columns as fit. The focused panel's group
+// is marked.
+func (m Model) renderKeyHelp() string {
+	groups := m.keyHelpGroups()
+	blocks := make([]string, len(groups))
+	blockW := 0
+	for i, g := range groups {
+		keyW := 0
+		for _, h := range g.hints {
+			keyW = max(keyW, lipgloss.Width(h.key))
+		}
+		title := ui.PanelTitleStyle.Render(g.title)
+		if g.focused {
+			title = ui.PanelTitle(g.title, true)
+		}
+		lines := []string{title}
+		for _, h := range g.hints {
+			lines = append(lines, ui.FooterKeyStyle.Render(padRight(h.key, keyW))+"  "+h.desc)
+		}
+		blocks[i] = strings.Join(lines, "\n")
+		blockW = max(blockW, lipgloss.Width(blocks[i]))
+	}
+	cols := max(1, (m.width-6)/(blockW+4))
+	lines := []string{ui.PanelTitleActiveStyle.Render("Keys")}
+	for start := 0; start < len(blocks); start += cols {
+		row := make([]string, 0, 2*cols)
+		for i := start; i < min(start+cols, len(blocks)); i++ {
+			if i > start {
+				row = append(row, "    ")
+			}
+			row = append(row, lipgloss.NewStyle().Width(blockW).Render(blocks[i]))
+		}
+		lines = append(lines, "", lipgloss.JoinHorizontal(lipgloss.Top, row...))
+	}
+	lines = append(lines, "", ui.DimStyle.Render("? or esc to close"))
+	return ui.SessionBrowserStyle.Render(strings.Join(lines, "\n"))
+}
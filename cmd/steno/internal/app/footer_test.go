@@ -0,0 +1,184 @@
+package app
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestFooterFollowsFocus(t *testing.T) {
+	m := New()
+	m.connected = true
+	m.engineStatus = StatusRecording
+	m.width, m.height = 200, 24
+
+	m.focusedPanel = FocusTopics
+	footer := m.renderFooter()
+	for _, want := range []string{" Edit", " Follow-up", " Transcript", "? more"} {
+		if !strings.Contains(footer, want) {
+			t.Errorf("topics footer missing %q: %q", want, footer)
+		}
+	}
+	if strings.Contains(footer, " Search") {
+		t.Errorf("topics footer offers a transcript key: %q", footer)
+	}
+
+	m.focusedPanel = FocusTranscript
+	footer = m.renderFooter()
+	if !strings.Contains(footer, " Search") || strings.Contains(footer, " Edit") {
+		t.Errorf("transcript footer = %q, want its own keys", footer)
+	}
+
+	m.store = stubStore()
+	m.sessionID = "s1"
+	m, _ = applyUpdate(m, runeKey('N'))
+	footer = m.renderFooter()
+	if !strings.Contains(footer, " Save & close") || strings.Contains(footer, "Boundary") || strings.Contains(footer, "? more") {
+		t.Errorf("notes footer = %q, want only the pane's keys", footer)
+	}
+}
+
+func TestFooterFitsWidth(t *testing.T) {
+	m := New()
+	m.connected = true
+	m.engineStatus = StatusRecording
+	m.width, m.height = 60, 24
+	footer := m.renderFooter()
+	if w := lipgloss.Width(footer); w > m.width {
+		t.Errorf("footer is %d wide, want at most %d: %q", w, m.width, footer)
+	}
+	if !strings.Contains(footer, "Boundary") || !strings.HasSuffix(footer, "? more") {
+		t.Errorf("footer = %q, want the first hints and ? more", footer)
+	}
+}
+
+func TestKeyHelpOverlay(t *testing.T) {
+	m := New()
+	m.connected = true
+	m.engineStatus = StatusRecording
+	m.width, m.height = 120, 30
+	m, _ = applyUpdate(m, runeKey('?'))
+	if !m.keyHelp {
+		t.Fatal("? should open the key help")
+	}
+	view := m.View()
+	for _, want := range []string{"Keys", "Search", "Dictation", "Recover", "Quit"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("key help missing %q:\n%s", want, view)
+		}
+	}
+	m, _ = applyUpdate(m, runeKey('b'))
+	if !m.keyHelp || m.browser.open {
+		t.Error("other keys shouldn't reach the main view")
+	}
+	m, _ = applyUpdate(m, runeKey('?'))
+	if m.keyHelp {
+		t.Error("? should close the key help")
+	}
+}
+
+func TestKeyHelpGroupsEveryKey(t *testing.T) {
+	m := New()
+	m.connected = true
+	m.engineStatus = StatusRecording
+	m.width, m.height = 200, 40
+	m.focusedPanel = FocusTranscript
+	m, _ = applyUpdate(m, runeKey('?'))
+	view := m.View()
+	// The topics panel's and other screens' keys are listed too, not
+	// only the focused panel's.
+	for _, want := range []string{"Recording", "Transcript", "Topics", "Follow-up", "Session browser", "Seek 30s", "Recovery", "Speakers", "Export (e is Errors)"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("key help missing %q:\n%s", want, view)
+		}
+	}
+	m, _ = applyUpdate(m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.keyHelp {
+		t.Error("esc should close the key help")
+	}
+
+	m.dbOnly = true
+	groups := m.keyHelpGroups()
+	if len(groups) != 1 || groups[0].hints[0].key != "b" {
+		t.Errorf("groups = %+v, want the database-only keys", groups)
+	}
+}
+
+// TestKeyHelpListsHandledKeys checks that every binding handleKey acts on
+// is in the key help, by reading the Key* constants out of its source.
+func TestKeyHelpListsHandledKeys(t *testing.T) {
+	fset := token.NewFileSet()
+	keymap, err := parser.ParseFile(fset, "keymap.go", nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := map[string]string{}
+	ast.Inspect(keymap, func(n ast.Node) bool {
+		if spec, ok := n.(*ast.ValueSpec); ok {
+			for i, name := range spec.Names {
+				if lit, ok := spec.Values[i].(*ast.BasicLit); ok {
+					values[name.Name], _ = strconv.Unquote(lit.Value)
+				}
+			}
+		}
+		return true
+	})
+
+	model, err := parser.ParseFile(fset, "model.go", nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	handled := map[string]bool{}
+	for _, decl := range model.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Name.Name == "handleKey" {
+			ast.Inspect(fn.Body, func(n ast.Node) bool {
+				if id, ok := n.(*ast.Ident); ok {
+					if _, ok := values[id.Name]; ok {
+						handled[id.Name] = true
+					}
+				}
+				return true
+			})
+		}
+	}
+	if len(handled) == 0 {
+		t.Fatal("found no Key* bindings in handleKey")
+	}
+
+	m := New()
+	m.connected = true
+	listed := map[string]bool{}
+	for _, g := range m.keyHelpGroups() {
+		for _, h := range g.hints {
+			listed[h.key] = true
+			for _, k := range strings.FieldsFunc(h.key, func(r rune) bool { return r == '/' || r == ' ' }) {
+				listed[k] = true
+			}
+		}
+	}
+	for name := range handled {
+		if !listed[keys(values[name])] {
+			t.Errorf("handleKey acts on %s (%q), which the key help doesn't list", name, values[name])
+		}
+	}
+}
+
+func TestKeyHelpSaysSearchShadowsNotes(t *testing.T) {
+	m := testModel(t, withSize(200, 40), withSession("s1"))
+	m, _ = applyUpdate(m, runeKey('/'))
+	m = typeQuery(m, "budget")
+	m, _ = applyUpdate(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m, _ = applyUpdate(m, runeKey('N')); m.notes != nil {
+		t.Fatal("N should step to the previous match while a search is active")
+	}
+	m, _ = applyUpdate(m, runeKey('?'))
+	if view := m.View(); !strings.Contains(view, "Notes (Esc a search first)") {
+		t.Errorf("key help should say a search shadows notes:\n%s", view)
+	}
+}
@@ -0,0 +1,69 @@
+package app
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jwulff/steno/internal/entities"
+	"github.com/jwulff/steno/internal/ui"
+)
+
+// A transcript line is highlighted in one pass: entities, keyword filter
+// matches and search hits are all found on its plain text, then each
+// byte is styled once. Matching a later pattern against the styled line
+// would find "m" or "38" inside the escape codes and break them.
+
+// span is a run of a line to style, in byte offsets.
+type span struct {
+	start, end int
+	style      lipgloss.Style
+}
+
+// matchSpans returns re's matches in line as spans of style.
+func matchSpans(re *regexp.Regexp, line string, style lipgloss.Style) []span {
+	var spans []span
+	for _, loc := range re.FindAllStringIndex(line, -1) {
+		if loc[0] < loc[1] {
+			spans = append(spans, span{loc[0], loc[1], style})
+		}
+	}
+	return spans
+}
+
+// entitySpans returns the entities in line.
+func entitySpans(line string) []span {
+	var spans []span
+	for _, e := range entities.Find(line) {
+		spans = append(spans, span{e.Start, e.End, ui.EntityStyle})
+	}
+	return spans
+}
+
+// renderSpans styles line's spans. Where spans overlap, the later one in
+// spans wins, so callers list them from least to most important.
+func renderSpans(line string, spans []span) string {
+	if len(spans) == 0 {
+		return line
+	}
+	owner := make([]int, len(line))
+	for i, s := range spans {
+		for b := s.start; b < s.end; b++ {
+			owner[b] = i + 1
+		}
+	}
+	var out strings.Builder
+	for start := 0; start < len(line); {
+		end := start + 1
+		for end < len(line) && owner[end] == owner[start] {
+			end++
+		}
+		if o := owner[start]; o == 0 {
+			out.WriteString(line[start:end])
+		} else {
+			out.WriteString(spans[o-1].style.Render(line[start:end]))
+		}
+		start = end
+	}
+	return out.String()
+}
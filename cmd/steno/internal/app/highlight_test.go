@@ -0,0 +1,74 @@
+package app
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/muesli/termenv"
+)
+
+// withColor renders styles with escape codes for the rest of the test,
+// as a terminal would.
+func withColor(t *testing.T) {
+	t.Helper()
+	prev := lipgloss.ColorProfile()
+	lipgloss.SetColorProfile(termenv.ANSI256)
+	t.Cleanup(func() { lipgloss.SetColorProfile(prev) })
+}
+
+// highlightedRow lays out text as the only segment and returns its row.
+func highlightedRow(t *testing.T, m Model, text string) string {
+	t.Helper()
+	m.entries = []TranscriptEntry{{Text: text, Source: "microphone", Timestamp: time.Now(), SeqNum: 1}}
+	lines, _ := m.transcriptDisplayLines(120)
+	if len(lines) != 1 {
+		t.Fatalf("lines = %q, want the one row", lines)
+	}
+	return lines[0]
+}
+
+func TestFilterHighlightOverEntities(t *testing.T) {
+	withColor(t)
+	const text = "we owe them $38 by Friday"
+	for _, word := range []string{"38", "them", "Friday"} {
+		m := New()
+		m.connected = true
+		m.filter = newTranscriptFilter(word)
+		row := highlightedRow(t, m, text)
+		if got := ansi.Strip(row); !strings.HasSuffix(got, text) {
+			t.Errorf("filter %q: row reads %q, want the text intact", word, got)
+		}
+	}
+
+	m := New()
+	m.connected = true
+	m.filter = newTranscriptFilter("38")
+	row := highlightedRow(t, m, text)
+	if !strings.Contains(row, m.filter.highlight("38")) || !strings.Contains(row, renderSpans("Friday", entitySpans("Friday"))) {
+		t.Errorf("row = %q, want the match and the other entity styled", row)
+	}
+}
+
+func TestSearchHighlightOverEntities(t *testing.T) {
+	withColor(t)
+	const text = "meet me at 5pm for the team"
+	m := New()
+	m.connected = true
+	m.entries = []TranscriptEntry{{Text: text, Source: "microphone", Timestamp: time.Now(), SeqNum: 1}}
+	m, _ = applyUpdate(m, runeKey('/'))
+	m = typeQuery(m, "m")
+	m, _ = applyUpdate(m, tea.KeyMsg{Type: tea.KeyEnter})
+
+	row := highlightedRow(t, m, text)
+	if got := ansi.Strip(row); !strings.HasSuffix(got, text) {
+		t.Errorf("row reads %q, want the text intact", got)
+	}
+	// Every m is a search match, the one in 5pm too, and nothing else is.
+	if got := strings.Count(row, m.search.highlight("m")); got != strings.Count(text, "m") {
+		t.Errorf("row = %q, has %d highlighted m, want %d", row, got, strings.Count(text, "m"))
+	}
+}
@@ -0,0 +1,108 @@
+package app
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jwulff/steno/internal/db"
+)
+
+// The daemon streams only what happens after a client subscribes, so a
+// TUI started mid-meeting, or reconnected after losing the daemon, would
+// show the session from that moment on. Once status reports an active
+// session, the segments recorded before are read from SQLite and merged
+// into the transcript by sequence number. Segments that arrived live in
+// the meantime are kept rather than doubled.
+
+// historyState is how much of the current session's transcript has been
+// read back from SQLite.
+type historyState struct {
+	// sessionID is the session whose earlier segments are loaded; until
+	// it is the current one, each status response asks again.
+	sessionID string
+	// resumeSession and resumeAfter are where a dropped connection left
+	// off: the segments through resumeAfter were already shown, so only
+	// later ones are read back.
+	resumeSession string
+	resumeAfter   int
+}
+
+// loadHistoryCmd reads sessionID's segments after sequence number since.
+func loadHistoryCmd(store *db.Store, sessionID string, since int) tea.Cmd {
+	return func() tea.Msg {
+		segs, err := store.SegmentsAfter(sessionID, since, 0)
+		return HistoryLoadedMsg{SessionID: sessionID, Segments: segs, Err: err}
+	}
+}
+
+// historyCmd returns loadHistoryCmd for the current session while it is
+// recording and not yet read back, or nil. A viewer gets the session
+// from its host instead.
+func (m Model) historyCmd() tea.Cmd {
+	if m.store == nil || m.sessionID == "" || !m.recording || m.viewAddr != "" || m.history.sessionID == m.sessionID {
+		return nil
+	}
+	since := 0
+	if m.history.resumeSession == m.sessionID {
+		since = m.history.resumeAfter
+	}
+	return loadHistoryCmd(m.store, m.sessionID, since)
+}
+
+// loseHistory marks the current session as needing to be read back once
+// the daemon is reachable again, from the last segment shown.
+func (m *Model) loseHistory() {
+	if m.history.sessionID == "" {
+		return
+	}
+	after := 0
+	for _, e := range m.entries {
+		if e.SessionID == m.history.sessionID {
+			after = max(after, e.SeqNum)
+		}
+	}
+	m.history = historyState{resumeSession: m.history.sessionID, resumeAfter: after}
+}
+
+// applyHistory merges read-back segments into the transcript. Segments
+// already shown are skipped, and the rest aren't highlighted as late:
+// they are the session so far, not a correction to it.
+func (m *Model) applyHistory(msg HistoryLoadedMsg) {
+	if msg.SessionID != m.sessionID || msg.Err != nil {
+		return
+	}
+	m.history = historyState{sessionID: msg.SessionID}
+	shown := map[int]bool{}
+	for _, e := range m.entries {
+		if e.SessionID == msg.SessionID && e.SeqNum > 0 {
+			shown[e.SeqNum] = true
+		}
+	}
+	added := map[int]bool{}
+	for _, seg := range msg.Segments {
+		if shown[seg.SequenceNumber] {
+			continue
+		}
+		added[seg.SequenceNumber] = true
+		m.insertSegment(TranscriptEntry{
+			Text:      seg.Text,
+			Source:    seg.Source,
+			Speaker:   seg.Speaker,
+			Timestamp: seg.StartedAt,
+			SeqNum:    seg.SequenceNumber,
+			SessionID: seg.SessionID,
+		})
+		m.segmentCount = max(m.segmentCount, seg.SequenceNumber)
+	}
+	if len(added) == 0 {
+		return
+	}
+	for i, e := range m.entries {
+		if e.SessionID == msg.SessionID && added[e.SeqNum] {
+			m.entries[i].BackfilledAt = time.Time{}
+		}
+	}
+	if m.transcriptLive {
+		m.scrollToBottom()
+	}
+}
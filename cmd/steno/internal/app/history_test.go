@@ -0,0 +1,67 @@
+package app
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jwulff/steno/internal/daemon"
+	"github.com/jwulff/steno/internal/db"
+)
+
+func historySegment(seq int, text string) db.Segment {
+	return db.Segment{SessionID: "sess-1", SequenceNumber: seq, Text: text, Source: "microphone",
+		StartedAt: time.Unix(1700000000+int64(seq), 0)}
+}
+
+func TestHistoryBackfillsActiveSession(t *testing.T) {
+	m := testModel(t)
+	if m.historyCmd() != nil {
+		t.Error("nothing to read back before status names a session")
+	}
+	m, _ = applyUpdate(m, StatusResponseMsg{Response: daemon.Response{OK: true, Recording: daemon.BoolPtr(true), SessionID: "sess-1"}})
+	if m.historyCmd() == nil {
+		t.Fatal("an active session should be read back")
+	}
+
+	// A live segment lands before the read-back does.
+	seq := 3
+	m, _ = applyUpdate(m, DaemonEventMsg{Event: daemon.Event{Event: "segment", SessionID: "sess-1", SequenceNumber: &seq, Source: "microphone", Text: "three (live)"}})
+	m, _ = applyUpdate(m, HistoryLoadedMsg{SessionID: "sess-1", Segments: []db.Segment{
+		historySegment(1, "one"), historySegment(2, "two"), historySegment(3, "three"),
+	}})
+
+	var texts []string
+	for _, e := range m.entries {
+		texts = append(texts, e.Text)
+		if !e.BackfilledAt.IsZero() {
+			t.Errorf("%q shouldn't be highlighted as late", e.Text)
+		}
+	}
+	if len(texts) != 3 || texts[0] != "one" || texts[1] != "two" || texts[2] != "three (live)" {
+		t.Errorf("entries = %q, want the history before the live segment, once each", texts)
+	}
+	if m.historyCmd() != nil {
+		t.Error("a session read back shouldn't be read again")
+	}
+}
+
+func TestHistoryResumesAfterReconnect(t *testing.T) {
+	m := testModel(t, withSession("sess-1"))
+	m.recording = true
+	m, _ = applyUpdate(m, HistoryLoadedMsg{SessionID: "sess-1", Segments: []db.Segment{historySegment(1, "one"), historySegment(2, "two")}})
+
+	m, _ = applyUpdate(m, DaemonEventErrorMsg{Err: errors.New("daemon gone")})
+	if m.historyCmd() == nil || m.history.resumeAfter != 2 {
+		t.Errorf("history = %+v, want a read-back after segment 2", m.history)
+	}
+}
+
+func TestHistoryForEndedSessionDropped(t *testing.T) {
+	m := New()
+	m.sessionID = "sess-2"
+	m, _ = applyUpdate(m, HistoryLoadedMsg{SessionID: "sess-1", Segments: []db.Segment{historySegment(1, "one")}})
+	if len(m.entries) != 0 {
+		t.Errorf("entries = %+v, want the stale read-back dropped", m.entries)
+	}
+}
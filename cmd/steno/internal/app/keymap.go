@@ -6,7 +6,80 @@ package app
 //   - Space → demarcate (atomic session boundary), NOT start/stop.
 //   - p     → toggle pause with 30-min auto-resume.
 //   - P     → toggle pause indefinite (manual resume only).
-//   - e     → toggle the error-history modal (last 10 non-transient errors).
+//   - e     → toggle the notifications modal (last 10 errors, warnings and
+//     notices, with fix-it guidance where known; see errors.go).
+//   - b     → toggle the session browser (locale / device / system-audio
+//     per session, filterable by device and system-audio).
+//   - v     → cycle transcript density (normal → compact → comfortable →
+//     captions).
+//   - R     → toggle reading mode: the transcript alone, capped at ~90
+//     columns and centered, for reviewing a meeting. See reading.go.
+//   - T     → toggle the summary ticker: the latest rolling summary's
+//     last sentence under the transcript header. See ticker.go.
+//   - H     → switch to the next color theme: default, dark, light, the
+//     color-blind presets, then any custom palettes (config
+//     display.palettes). See theme.go.
+//   - ?     → key help: every key, grouped by panel and screen, beyond
+//     the footer's hints for the focused panel; ? or esc closes it. See
+//     footer.go.
+//   - !     → write a bug-report zip (see internal/bugreport).
+//   - r     → (topics panel focused) edit the selected topic's title and
+//     summary inline; saved edits survive LLM regeneration.
+//   - F     → (topics panel focused) open a follow-up calendar event for
+//     the selected topic, with its summary and a transcript permalink.
+//     See followup.go.
+//   - W     → export the current session's transcript as Markdown to
+//     export.dir (default ~/Downloads), with progress in the status
+//     bar; an export cut short resumes where it stopped. A dialog first
+//     picks the part to export, by segment number or m:ss offset, and
+//     previews it. See exportdialog.go and export.go.
+//   - N     → session notes: a freeform Markdown pane over the topic list,
+//     each new line stamped with the time, saved per session and merged
+//     into exports. See notes.go.
+//   - K     → keyword cloud for the current session; enter filters the
+//     transcript to segments mentioning the selected word, esc clears
+//     the filter. See keywords.go.
+//   - X     → dates, times, amounts and links said this session, which
+//     the transcript also highlights; enter filters the transcript to
+//     the selected one. See entities.go.
+//   - C     → running counts of the codebook (config coding.codes) this
+//     session, with a sparkline per code; enter filters the transcript
+//     to the selected code's terms. See codes.go.
+//   - L     → switch recognition to the next locale in capture.locales
+//     without ending the session (daemon `set_locale`). See locale.go.
+//   - A     → arm voice-activated start while idle: the daemon meters
+//     the mic and starts a session on sustained speech. Right after an
+//     auto-start, A cancels it (stop, then arm again). See autostart.go.
+//   - U     → speakers: rename the session's diarized speakers, whose
+//     tags lead their segments. See speakerlabels.go.
+//   - y / n → accept or decline speaker names from the previous session
+//     of the same recurring meeting, when offered. See speakernames.go.
+//   - /     → search the transcript: enter jumps to the newest match and
+//     highlights them all, n / N step to the next / previous, esc
+//     clears. See search.go. Tab in the prompt opens the saved searches
+//     (see `steno searches`) with their match counts instead; enter
+//     filters the transcript by one. Alert searches are also checked
+//     against each new segment. See searches.go.
+//   - D     → toggle dictation mode: the dictation profile's macros
+//     (`steno macros`) rewrite mic segments as shown, e.g. "new
+//     paragraph" into a paragraph break. See dictation.go.
+//   - I     → recover interrupted sessions: those still marked active
+//     that the daemon isn't recording, after a crash. f finalizes one as
+//     interrupted, n also starts a new session, x twice discards it. See
+//     recovery.go.
+//   - c     → acknowledge the recording-consent banner (config
+//     consent.reminder) once the others were told. See consent.go.
+//   - PgUp / PgDn / Home / End → page the transcript; End and G jump
+//     back to live. The header shows how far up you are. See scroll.go.
+//   - ctrl+k → quick switcher: fuzzy-find a session by title, date or
+//     context and replay it. See switcher.go.
+//   - ctrl+f → search every session's transcript for a phrase; enter on
+//     a match replays its session from there. From the / prompt, it
+//     searches for the query typed. See sessionsearch.go and
+//     db.SearchAllSegments.
+//   - enter → (in the session browser) replay the selected session;
+//     during replay, space pauses, 1/2/4/0 set the speed (0 = max) and
+//     ←/→ seek 30s. See replay.go.
 //
 // `start` and `stop` are still valid commands on the wire but no longer
 // have keybinds — the daemon is always recording in the always-on model.
@@ -46,4 +119,59 @@ const (
 	KeyErrorHistory    = "e"
 	KeyErrorHistoryUp  = "E"
 	KeyEsc             = "esc"
+	KeyDensity         = "v"
+	KeyReading         = "R"
+	KeyTicker          = "T"
+	KeyTheme           = "H"
+	KeyBugReport       = "!"
+	KeyEditTopic       = "r"
+	KeyFollowUp        = "F"
+	KeyExport          = "W"
+	KeyNotes           = "N"
+	KeyHelp            = "?"
+	// Session browser overlay and its filter keys (active only while the
+	// browser is open, so `d` / `a` don't collide with the removed
+	// device / system-audio toggles described above).
+	KeySessionBrowser        = "b"
+	KeyBrowserDeviceFilter   = "d"
+	KeyBrowserSysAudioFilter = "a"
+	KeyQuickSwitcher         = "ctrl+k"
+	KeySearchSessions        = "ctrl+f"
+	KeyKeywords              = "K"
+	KeyEntities              = "X"
+	KeyCodes                 = "C"
+	KeySearch                = "/"
+	KeyLocale                = "L"
+	KeyArm                   = "A"
+	KeyDictation             = "D"
+	KeyRecovery              = "I"
+	KeySpeakers              = "U"
+	// Step through transcript search matches (only while a search is
+	// active, when N shadows KeyNotes; the key help and README say so).
+	KeySearchNext = "n"
+	KeySearchPrev = "N"
+	// Answers to the speaker-names prompt (shown only while it is).
+	KeyAccept  = "y"
+	KeyDecline = "n"
+	// Acknowledges the consent banner (shown only while it is).
+	KeyConsent = "c"
+
+	// Recovery screen actions (see recovery.go).
+	KeyRecoverFinalize = "f"
+	KeyRecoverResume   = "n"
+	KeyRecoverDiscard  = "x"
+	// Transcript paging (transcript panel focused). G jumps back to the
+	// live tail explicitly, as does End. See scroll.go.
+	KeyPageUp   = "pgup"
+	KeyPageDown = "pgdown"
+	KeyHome     = "home"
+	KeyEnd      = "end"
+	KeyJumpLive = "G"
+	// Replay playback keys (active only while a replay is open).
+	KeyLeft           = "left"
+	KeyRight          = "right"
+	KeyReplaySpeed1   = "1"
+	KeyReplaySpeed2   = "2"
+	KeyReplaySpeed4   = "4"
+	KeyReplaySpeedMax = "0"
 )
@@ -0,0 +1,224 @@
+package app
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jwulff/steno/internal/scrub"
+	"github.com/jwulff/steno/internal/ui"
+)
+
+// keywordCloudSize caps how many keywords the cloud shows.
+const keywordCloudSize = 40
+
+// minKeywordLen drops short tokens, which are almost never topical.
+const minKeywordLen = 3
+
+// stopwords are dropped before counting: English function words plus the
+// filler ASR transcribes faithfully from conversation.
+var stopwords = func() map[string]bool {
+	words := strings.Fields(`
+		about above after again against all also and any are aren't because been before being
+		below between both but can can't cannot could couldn't did didn't does doesn't doing don't
+		down during each few for from further had hadn't has hasn't have haven't having her here
+		hers herself him himself his how i'd i'll i'm i've into isn't it's its itself let's more
+		most mustn't myself nor not off once only other ought our ours ourselves out over own same
+		she she'd she'll she's should shouldn't some such than that that's the their theirs them
+		themselves then there there's these they they'd they'll they're they've this those through
+		too under until very was wasn't we'd we'll we're we've were weren't what what's when when's
+		where where's which while who who's whom why why's will with won't would wouldn't you
+		you'd you'll you're you've your yours yourself yourselves one two three
+		yeah yes okay like just really actually basically literally know think mean going gonna
+		wanna gotta kind sort thing things stuff right well get got getting say said see lot
+		something anything everything nothing maybe probably sure want need make made way
+		let now still even much many back good great look come take thank thanks hmm uh-huh
+	`)
+	m := make(map[string]bool, len(words))
+	for _, w := range words {
+		m[w] = true
+	}
+	return m
+}()
+
+// keywordCount is one entry in the keyword cloud.
+type keywordCount struct {
+	Word  string
+	Count int
+}
+
+// sessionKeywords counts the terms in texts, most frequent first (ties
+// alphabetical), dropping stopwords, numbers and short tokens. Words the
+// scrubber would mask are left out rather than surfaced in the cloud.
+func sessionKeywords(texts []string, limit int, scrubber *scrub.Scrubber) []keywordCount {
+	counts := map[string]int{}
+	for _, text := range texts {
+		for _, tok := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\'' && r != '-'
+		}) {
+			tok = strings.Trim(tok, "'-")
+			tok = strings.TrimSuffix(tok, "'s")
+			if len([]rune(tok)) < minKeywordLen || stopwords[tok] || !strings.ContainsFunc(tok, unicode.IsLetter) {
+				continue
+			}
+			counts[tok]++
+		}
+	}
+	out := make([]keywordCount, 0, len(counts))
+	for w, n := range counts {
+		if scrubber.Apply(w) != w {
+			continue
+		}
+		out = append(out, keywordCount{Word: w, Count: n})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Count != out[j].Count {
+			return out[i].Count > out[j].Count
+		}
+		return out[i].Word < out[j].Word
+	})
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out
+}
+
+// keywordCloud is the `K` overlay: the current session's keywords.
+// Enter filters the transcript to segments mentioning the selection.
+type keywordCloud struct {
+	words    []keywordCount
+	selected int
+}
+
+// transcriptFilter narrows the transcript panel to segments matching a
+// word, highlighting each match. Esc clears it. A saved search also
+// narrows by source.
+type transcriptFilter struct {
+	word    string
+	re      *regexp.Regexp
+	sources []string
+}
+
+func newTranscriptFilter(word string) *transcriptFilter {
+	return &transcriptFilter{word: word, re: regexp.MustCompile(`(?i)\b` + regexp.QuoteMeta(word) + `\b`)}
+}
+
+// matches reports whether e is shown while the filter is active.
+// Boundaries are hidden: they separate segments that are no longer
+// adjacent.
+func (f *transcriptFilter) matches(e TranscriptEntry) bool {
+	if len(f.sources) > 0 && !slices.Contains(f.sources, e.Source) {
+		return false
+	}
+	return !e.IsBoundary && f.re.MatchString(e.Text)
+}
+
+// spans are the matches in an already-wrapped line, to highlight.
+func (f *transcriptFilter) spans(line string) []span {
+	return matchSpans(f.re, line, ui.SearchMatchStyle)
+}
+
+// highlight marks each match in an already-wrapped line.
+func (f *transcriptFilter) highlight(line string) string {
+	return renderSpans(line, f.spans(line))
+}
+
+// filteredEntryCount counts the entries the active filter shows.
+func (m Model) filteredEntryCount() int {
+	n := 0
+	for _, e := range m.entries {
+		if m.filter.matches(e) {
+			n++
+		}
+	}
+	return n
+}
+
+// openKeywords computes the cloud from the current session's segments.
+func (m Model) openKeywords() (tea.Model, tea.Cmd) {
+	var texts []string
+	for _, e := range m.entries {
+		if !e.IsBoundary && (e.SessionID == "" || e.SessionID == m.sessionID) {
+			texts = append(texts, e.Text)
+		}
+	}
+	m.keywords = &keywordCloud{words: sessionKeywords(texts, keywordCloudSize, m.scrubber)}
+	return m, nil
+}
+
+// handleKeywordsKey handles keys while the keyword cloud is open: arrows
+// or h/j/k/l move, enter filters the transcript by the selected word,
+// esc or K closes.
+func (m Model) handleKeywordsKey(key string) (tea.Model, tea.Cmd) {
+	c := *m.keywords
+	switch key {
+	case KeyQuit, KeyQuitUpper, KeyCtrlC:
+		return m.quit()
+	case KeyEsc, KeyKeywords:
+		m.keywords = nil
+		return m, nil
+	case KeyEnter:
+		if len(c.words) == 0 {
+			return m, nil
+		}
+		m.keywords = nil
+		m.filter = newTranscriptFilter(c.words[c.selected].Word)
+		m.focusedPanel = FocusTranscript
+		m.transcriptLive = false
+		m.transcriptScroll = 0
+		return m, nil
+	case KeyLeft, KeyUp, "h", KeyK:
+		if c.selected > 0 {
+			c.selected--
+		}
+	case KeyRight, KeyDown, "l", KeyJ:
+		if c.selected < len(c.words)-1 {
+			c.selected++
+		}
+	}
+	m.keywords = &c
+	return m, nil
+}
+
+// renderKeywords renders the keyword cloud in place of the main panels.
+// Words flow left to right, most frequent first; the top fifth is bold.
+func (m Model) renderKeywords() string {
+	c := m.keywords
+	width := max(20, m.width-6)
+	lines := []string{ui.PanelTitleActiveStyle.Render("Keywords · this session")}
+	if len(c.words) == 0 {
+		lines = append(lines, ui.DimStyle.Render("Not enough said yet."))
+	}
+
+	var row []string
+	rowWidth := 0
+	for i, kw := range c.words {
+		cell := fmt.Sprintf("%s %d", kw.Word, kw.Count)
+		switch {
+		case i == c.selected:
+			cell = ui.SelectedStyle.Render("[" + cell + "]")
+		case i < max(1, len(c.words)/5):
+			cell = ui.PanelTitleStyle.Render(" " + cell + " ")
+		default:
+			cell = ui.DimStyle.Render(" " + cell + " ")
+		}
+		w := lipgloss.Width(cell)
+		if rowWidth > 0 && rowWidth+w+1 > width {
+			lines = append(lines, strings.Join(row, " "))
+			row, rowWidth = nil, 0
+		}
+		row = append(row, cell)
+		rowWidth += w + 1
+	}
+	if len(row) > 0 {
+		lines = append(lines, strings.Join(row, " "))
+	}
+
+	lines = append(lines, "", ui.DimStyle.Render("←/→ move · enter show in transcript · esc close"))
+	return ui.SessionBrowserStyle.Render(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,88 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jwulff/steno/internal/daemon"
+	"github.com/jwulff/steno/internal/scrub"
+)
+
+func TestSessionKeywords(t *testing.T) {
+	texts := []string{
+		"So the budget for Q3 is, like, the budget we agreed on.",
+		"Yeah, the budget's fine. Let's talk about the launch.",
+		"The launch moves to 2026, I think.",
+		"Send it to sk-abcdefghijklmnopqrstuv please.",
+	}
+	s, _ := scrub.New(scrub.DefaultPatterns, "")
+	got := sessionKeywords(texts, 3, s)
+	want := []keywordCount{{"budget", 3}, {"launch", 2}, {"agreed", 1}}
+	if len(got) != len(want) {
+		t.Fatalf("keywords = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("keywords[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+
+	// Numbers, stopwords and masked tokens never appear.
+	for _, kw := range sessionKeywords(texts, 100, s) {
+		switch kw.Word {
+		case "2026", "the", "yeah", "think", "like", "sk-abcdefghijklmnopqrstuv":
+			t.Errorf("unexpected keyword %q", kw.Word)
+		}
+	}
+}
+
+func TestKeywordCloudFiltersTranscript(t *testing.T) {
+	m := New()
+	m.width, m.height = 120, 30
+	m.connected = true
+	m.sessionID = "s1"
+	for i, text := range []string{"budget review first", "then the launch plan", "budget again, the budget"} {
+		seq := i + 1
+		m.handleEvent(daemon.Event{Event: "segment", Text: text, Source: "microphone", SessionID: "s1", SequenceNumber: &seq})
+	}
+
+	m, _ = applyUpdate(m, runeKey('K'))
+	if m.keywords == nil || m.keywords.words[0].Word != "budget" {
+		t.Fatalf("keywords = %+v", m.keywords)
+	}
+	if view := m.View(); !strings.Contains(view, "budget 3") {
+		t.Errorf("cloud view:\n%s", view)
+	}
+
+	m, _ = applyUpdate(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.keywords != nil || m.filter == nil || m.filter.word != "budget" {
+		t.Fatalf("enter should close the cloud and filter by budget (filter=%+v)", m.filter)
+	}
+	view := m.View()
+	if strings.Contains(view, "launch plan") || !strings.Contains(view, "review first") || !strings.Contains(view, "×2") {
+		t.Errorf("filtered view:\n%s", view)
+	}
+
+	m, _ = applyUpdate(m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.filter != nil || !m.transcriptLive || !strings.Contains(m.View(), "launch plan") {
+		t.Error("esc should clear the filter and return to the live tail")
+	}
+}
+
+func TestKeywordCloudEmptyAndClose(t *testing.T) {
+	m := New()
+	m.width, m.height = 120, 30
+	m, _ = applyUpdate(m, runeKey('K'))
+	if !strings.Contains(m.View(), "Not enough said yet.") {
+		t.Error("empty cloud should say so")
+	}
+	m, _ = applyUpdate(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.keywords == nil || m.filter != nil {
+		t.Error("enter on an empty cloud is a no-op")
+	}
+	m, _ = applyUpdate(m, runeKey('K'))
+	if m.keywords != nil {
+		t.Error("K should close the cloud")
+	}
+}
@@ -0,0 +1,46 @@
+package app
+
+import (
+	"slices"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jwulff/steno/internal/daemon"
+)
+
+// setLocaleCmd sends a `set_locale` command: the daemon rebuilds its
+// recognizers in locale without ending the session.
+func setLocaleCmd(client *daemon.Client, locale string) tea.Cmd {
+	return func() tea.Msg {
+		resp, err := sendCommand(client, daemon.SetLocaleCmd(locale))
+		if err != nil {
+			return DaemonEventErrorMsg{Err: err}
+		}
+		return SetLocaleResponseMsg{Locale: locale, Response: resp}
+	}
+}
+
+// nextLocale returns the configured locale after current, wrapping
+// around; the first one when current isn't in the list.
+func nextLocale(locales []string, current string) string {
+	i := slices.Index(locales, current)
+	return locales[(i+1)%len(locales)]
+}
+
+// cycleLocale handles `L`: switch recognition to the next locale in
+// capture.locales, for meetings that alternate languages.
+func (m Model) cycleLocale() (tea.Model, tea.Cmd) {
+	if m.client == nil {
+		return m, nil
+	}
+	if len(m.capture.Locales) == 0 {
+		m.notice = `Add "capture": {"locales": [...]} to the config to switch language`
+		return m, m.clearNoticeCmd()
+	}
+	next := nextLocale(m.capture.Locales, m.locale)
+	if next == m.locale {
+		m.notice = "Already transcribing in " + next
+		return m, m.clearNoticeCmd()
+	}
+	m.notice = "Switching to " + next + "..."
+	return m, setLocaleCmd(m.client, next)
+}
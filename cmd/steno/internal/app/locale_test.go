@@ -0,0 +1,67 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jwulff/steno/internal/daemon"
+)
+
+func TestNextLocale(t *testing.T) {
+	locales := []string{"en_US", "fr_FR", "de_DE"}
+	for _, tc := range []struct{ current, want string }{
+		{"en_US", "fr_FR"},
+		{"de_DE", "en_US"},
+		{"", "en_US"},
+		{"ja_JP", "en_US"},
+	} {
+		if got := nextLocale(locales, tc.current); got != tc.want {
+			t.Errorf("nextLocale(%q) = %q, want %q", tc.current, got, tc.want)
+		}
+	}
+}
+
+func TestCycleLocale(t *testing.T) {
+	m := New()
+	m.client = &daemon.Client{} // zero-value client; won't be invoked
+	m.locale = "en_US"
+
+	// Nothing configured: explain how, don't send anything.
+	m, cmd := applyUpdate(m, runeKey('L'))
+	if !strings.Contains(m.notice, `"locales"`) || cmd == nil {
+		t.Errorf("notice = %q, want the config hint", m.notice)
+	}
+
+	m.capture.Locales = []string{"en_US"}
+	m, _ = applyUpdate(m, runeKey('L'))
+	if m.notice != "Already transcribing in en_US" {
+		t.Errorf("notice = %q, want already-in note", m.notice)
+	}
+
+	m.capture.Locales = []string{"en_US", "fr_FR"}
+	m, cmd = applyUpdate(m, runeKey('L'))
+	if m.notice != "Switching to fr_FR..." || cmd == nil {
+		t.Errorf("notice = %q, cmd = %v; want a switch", m.notice, cmd)
+	}
+}
+
+func TestSetLocaleResponse(t *testing.T) {
+	m := New()
+	m.locale = "en_US"
+
+	m, _ = applyUpdate(m, SetLocaleResponseMsg{Locale: "fr_FR", Response: daemon.Response{OK: false, Error: "Not recording"}})
+	if m.locale != "en_US" || len(m.errorStack) != 1 || !strings.Contains(m.errorStack[0].Message, "fr_FR") {
+		t.Errorf("failed switch: locale = %q, errors = %v", m.locale, m.errorStack)
+	}
+
+	m, _ = applyUpdate(m, SetLocaleResponseMsg{Locale: "fr_FR", Response: daemon.Response{OK: true, Locale: "fr_FR"}})
+	if m.locale != "fr_FR" || m.notice != "Transcribing in fr_FR" {
+		t.Errorf("locale = %q, notice = %q", m.locale, m.notice)
+	}
+
+	// Another client switching is picked up from the event stream.
+	m.handleEvent(daemon.Event{Event: "locale", Locale: "de_DE"})
+	if m.locale != "de_DE" {
+		t.Errorf("locale after event = %q, want de_DE", m.locale)
+	}
+}
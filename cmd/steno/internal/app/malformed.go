@@ -0,0 +1,58 @@
+package app
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jwulff/steno/internal/daemon"
+)
+
+// malformedBuffer is how many skipped lines wait to be reported. A burst
+// beyond it is dropped: the error stack shows each message once anyway,
+// and the message log has every line.
+const malformedBuffer = 8
+
+// malformedLineMsg reports a line from the daemon that the client
+// couldn't decode and skipped.
+type malformedLineMsg struct {
+	client *daemon.Client
+	found  <-chan error
+	err    error
+}
+
+// watchMalformed reports client's malformed lines to the TUI, which
+// shows them as warnings; the lines themselves are in the message log
+// for bug reports. Call before the client starts reading.
+func watchMalformed(client *daemon.Client) tea.Cmd {
+	found := make(chan error, malformedBuffer)
+	client.OnMalformed(func(_ []byte, err error) {
+		select {
+		case found <- err:
+		default:
+		}
+	})
+	return waitMalformedCmd(client, found)
+}
+
+// waitMalformedCmd waits for client's next malformed line, until the
+// connection ends.
+func waitMalformedCmd(client *daemon.Client, found <-chan error) tea.Cmd {
+	return func() tea.Msg {
+		select {
+		case err := <-found:
+			return malformedLineMsg{client: client, found: found, err: err}
+		case <-client.Done():
+			return nil
+		}
+	}
+}
+
+// handleMalformedLine warns about a skipped line and waits for the next.
+// A line from a connection since replaced is old news.
+func (m Model) handleMalformedLine(msg malformedLineMsg) (tea.Model, tea.Cmd) {
+	if msg.client != m.evClient {
+		return m, nil
+	}
+	return m, tea.Batch(
+		m.pushError(SeverityWarn, "daemon sent a line steno couldn't read: "+msg.err.Error(), true),
+		waitMalformedCmd(msg.client, msg.found),
+	)
+}
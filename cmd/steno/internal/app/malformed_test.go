@@ -0,0 +1,35 @@
+package app
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMalformedLineIsReported(t *testing.T) {
+	client := streamEvents(t, "not json\n")
+	wait := watchMalformed(client)
+	// Start the reader; nothing but the bad line is coming.
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	client.ReadEventCtx(ctx)
+
+	msg, ok := wait().(malformedLineMsg)
+	if !ok {
+		t.Fatalf("msg = %T, want malformedLineMsg", msg)
+	}
+	m := New()
+	m.evClient = client
+	m, _ = applyUpdate(m, msg)
+	if len(m.errorStack) != 1 || !strings.Contains(m.errorStack[0].Message, "couldn't read") {
+		t.Errorf("errors = %+v, want the skipped line reported", m.errorStack)
+	}
+
+	// One from a connection since replaced is dropped.
+	stale := New()
+	stale, _ = applyUpdate(stale, msg)
+	if len(stale.errorStack) != 0 {
+		t.Errorf("errors = %+v, want a stale connection's line ignored", stale.errorStack)
+	}
+}
@@ -1,11 +1,18 @@
 package app
 
-import "github.com/jwulff/steno/internal/daemon"
+import (
+	"time"
+
+	"github.com/jwulff/steno/internal/config"
+	"github.com/jwulff/steno/internal/daemon"
+	"github.com/jwulff/steno/internal/db"
+	"github.com/jwulff/steno/internal/dictation"
+	"github.com/jwulff/steno/internal/export"
+)
 
 // DaemonConnectedMsg is sent when both daemon connections are established.
 type DaemonConnectedMsg struct {
-	Client   *daemon.Client // for commands (start, stop, status, devices)
-	EvClient *daemon.Client // for event subscription
+	Client *daemon.Client // for commands and the event subscription
 }
 
 // DaemonConnectErrorMsg is sent when the daemon connection fails.
@@ -18,6 +25,14 @@ type DaemonEventMsg struct {
 	Event daemon.Event
 }
 
+// DaemonEventsMsg carries the events read within one frame, the partial
+// and level ones folded into the latest of each (see frameInterval). Err
+// is set when the stream failed after them.
+type DaemonEventsMsg struct {
+	Events []daemon.Event
+	Err    error
+}
+
 // DaemonEventErrorMsg is sent when the event stream encounters an error.
 type DaemonEventErrorMsg struct {
 	Err error
@@ -28,6 +43,52 @@ type StatusResponseMsg struct {
 	Response daemon.Response
 }
 
+// VersionResponseMsg carries the response to a version command. Err is
+// set when the command could not be sent; a daemon that predates the
+// command answers with OK=false instead.
+type VersionResponseMsg struct {
+	Response daemon.Response
+	Err      error
+}
+
+// BugReportSavedMsg reports the outcome of the `!` bug-report key.
+type BugReportSavedMsg struct {
+	Path string
+	Err  error
+}
+
+// FollowUpCreatedMsg reports the outcome of the F follow-up key: the
+// topic's title and when the event was booked.
+type FollowUpCreatedMsg struct {
+	Title string
+	Start time.Time
+	Err   error
+}
+
+// ExportTranscriptLoadedMsg carries the session transcript the export
+// dialog slices.
+type ExportTranscriptLoadedMsg struct {
+	SessionID  string
+	Transcript export.Transcript
+	Err        error
+}
+
+// ExportProgressMsg reports a step of the W transcript export: lines
+// written so far, and whether the file is complete. Export and Manifest
+// carry the export on to its next step.
+type ExportProgressMsg struct {
+	Path        string
+	Export      *export.FileExport
+	Manifest    export.Manifest
+	Done, Total int
+	Resumed     int
+	Complete    bool
+	Err         error
+}
+
+// ClearNoticeMsg clears the status-bar notice.
+type ClearNoticeMsg struct{}
+
 // DevicesResponseMsg carries the response to a devices command.
 type DevicesResponseMsg struct {
 	Response daemon.Response
@@ -86,9 +147,11 @@ type TopicsEventMsg struct {
 // ClearTransientErrorMsg clears a transient error after a timeout.
 type ClearTransientErrorMsg struct{}
 
-// TopicsLoadedMsg carries topics loaded from SQLite.
+// TopicsLoadedMsg carries topics loaded from SQLite, or the error
+// reading them.
 type TopicsLoadedMsg struct {
 	Topics []TopicLoaded
+	Err    error
 }
 
 // TopicLoaded carries a topic from the database.
@@ -98,6 +161,28 @@ type TopicLoaded struct {
 	Summary           string
 	SegmentRangeStart int
 	SegmentRangeEnd   int
+	UserEdited        bool
+}
+
+// TopicEditedMsg reports the outcome of saving a topic edit.
+type TopicEditedMsg struct {
+	ID      string
+	Title   string
+	Summary string
+	Err     error
+}
+
+// SessionNotesLoadedMsg carries a session's notes for the notes pane.
+type SessionNotesLoadedMsg struct {
+	SessionID string
+	Body      string
+	Err       error
+}
+
+// SessionNotesSavedMsg reports the outcome of saving a session's notes.
+type SessionNotesSavedMsg struct {
+	SessionID string
+	Err       error
 }
 
 // TopicSegmentsLoadedMsg carries segments for an expanded topic.
@@ -113,9 +198,11 @@ type TopicSegment struct {
 	SeqNum int
 }
 
-// SummaryLoadedMsg carries the latest session summary.
+// SummaryLoadedMsg carries the session's summaries, oldest first, and
+// the latest one's content.
 type SummaryLoadedMsg struct {
-	Content string
+	Content   string
+	Summaries []db.Summary
 }
 
 // ReconnectTickMsg triggers a reconnection attempt.
@@ -131,6 +218,158 @@ type DemarcateResponseMsg struct {
 	Response daemon.Response
 }
 
+// SetLocaleResponseMsg carries the response to a set_locale command.
+// Locale is the one requested.
+type SetLocaleResponseMsg struct {
+	Locale   string
+	Response daemon.Response
+}
+
+// DecisionsLoadedMsg carries a session's decisions for the lane.
+type DecisionsLoadedMsg struct {
+	SessionID string
+	Decisions []db.Decision
+}
+
+// TalkTimeLoadedMsg carries a session's stored segments for the footer's
+// talk-time ratio.
+type TalkTimeLoadedMsg struct {
+	SessionID string
+	Segments  []db.Segment
+}
+
+// SavedSearchesLoadedMsg carries the saved searches for the `/` menu,
+// with each one's match count (-1 when counting failed).
+type SavedSearchesLoadedMsg struct {
+	Searches []db.SavedSearch
+	Counts   []int
+	Err      error
+}
+
+// AlertSearchesLoadedMsg carries the saved searches for their alerts.
+type AlertSearchesLoadedMsg struct {
+	Searches []db.SavedSearch
+	Err      error
+}
+
+// DraftsLoadedMsg carries the drafts earlier runs left in the partial-drafts sidecar.
+type DraftsLoadedMsg struct {
+	Drafts []partialDraft
+	Err    error
+}
+
+// DraftTickMsg writes the partial drafts, if they changed.
+type DraftTickMsg struct{}
+
+// DraftsSavedMsg reports a write of the partial-drafts sidecar.
+type DraftsSavedMsg struct{ Err error }
+
+// RecoveryLoadedMsg carries the sessions left active that the daemon
+// isn't recording.
+type RecoveryLoadedMsg struct {
+	Sessions []db.StrandedSession
+	// Drafts are, by session, the partial drafts holding words the
+	// sessions lost.
+	Drafts map[string][]partialDraft
+	Err    error
+	// Check marks the lookup made once on connecting.
+	Check bool
+}
+
+// RecoveryActionMsg is the result of finalizing or discarding a stranded
+// session from the recovery screen.
+type RecoveryActionMsg struct {
+	Action    string
+	SessionID string
+	Err       error
+}
+
+// SessionQualityMsg carries the quality report of a session that just
+// ended.
+type SessionQualityMsg struct {
+	SessionID string
+	Report    db.SessionQuality
+	Err       error
+}
+
+// DictationMacrosLoadedMsg carries the dictation profile's macros,
+// compiled with the built-in rules.
+type DictationMacrosLoadedMsg struct {
+	Macros *dictation.Macros
+	Err    error
+}
+
+// SpeakerNamesLoadedMsg carries a session's speaker names or, when it has
+// none, an offer of the names from the previous session of the same
+// recurring meeting (Offer nil when there is none).
+type SpeakerNamesLoadedMsg struct {
+	SessionID  string
+	Names      map[string]string
+	Offer      *db.Session
+	OfferNames map[string]string
+	Err        error
+}
+
+// SpeakerNamesCopiedMsg reports the outcome of saving accepted names.
+type SpeakerNamesCopiedMsg struct {
+	Err error
+}
+
+// ViewPrefsLoadedMsg carries a session's saved view prefs, nil when it
+// has none.
+type ViewPrefsLoadedMsg struct {
+	SessionID string
+	Prefs     *db.ViewPrefs
+	Err       error
+}
+
+// ViewPrefsSavedMsg reports the outcome of saving view prefs.
+type ViewPrefsSavedMsg struct {
+	Err error
+}
+
+// SpeakerRenamedMsg reports the outcome of naming a speaker with `U`. An
+// empty Name removed the label's name.
+type SpeakerRenamedMsg struct {
+	SessionID string
+	Label     string
+	Name      string
+	Err       error
+}
+
+// ConsentLoadedMsg reports whether consent was already acknowledged for
+// a session.
+type ConsentLoadedMsg struct {
+	SessionID    string
+	Acknowledged bool
+	Err          error
+}
+
+// ConsentAcknowledgedMsg reports the outcome of recording a consent
+// acknowledgment.
+type ConsentAcknowledgedMsg struct {
+	Err error
+}
+
+// AnnouncementCopiedMsg reports the outcome of copying a session's
+// consent announcement to the clipboard.
+type AnnouncementCopiedMsg struct {
+	SessionID string
+	Err       error
+}
+
+// ArmResponseMsg carries the response to an arm or disarm command.
+type ArmResponseMsg struct {
+	Response daemon.Response
+}
+
+// AutoStartExpiredMsg ends the window in which an auto-started session
+// can be cancelled with one key. StartedAt identifies the auto-start, so
+// a stale expiry doesn't cut a later window short.
+type AutoStartExpiredMsg struct {
+	StartedAt time.Time
+}
+
 // PauseHintMsg flashes a "press p to resume first" hint at the bottom of
 // the status bar when the user presses spacebar while paused. Cleared
 // after ~2s by ClearPauseHintMsg.
@@ -142,3 +381,76 @@ type ClearPauseHintMsg struct{}
 // StatusTickMsg fires once per second so the status bar (countdown,
 // last-seg-ago) can re-render even when no upstream events arrive.
 type StatusTickMsg struct{}
+
+// SessionsLoadedMsg carries the session browser's list and the distinct
+// devices available for its device filter.
+type SessionsLoadedMsg struct {
+	Sessions []SessionRow
+	Devices  []string
+}
+
+// ConfigCheckedMsg reports a check of the config file: Changed when its
+// size or modification time moved, with the reloaded Config or Err.
+type ConfigCheckedMsg struct {
+	Changed bool
+	ModTime time.Time
+	Size    int64
+	Config  config.Config
+	Err     error
+}
+
+// HistoryLoadedMsg carries the segments of the active session recorded
+// before the TUI was connected.
+type HistoryLoadedMsg struct {
+	SessionID string
+	Segments  []db.Segment
+	Err       error
+}
+
+// ReplayLoadedMsg carries a recorded session's segments for replay.
+type ReplayLoadedMsg struct {
+	SessionID string
+	Title     string
+	Segments  []db.Segment
+	// Seq, when set, is the segment playback starts after: the one a
+	// search of every session matched.
+	Seq int
+	Err error
+}
+
+// SessionSearchResultsMsg carries the segments, across every session,
+// matching a ctrl+f search.
+type SessionSearchResultsMsg struct {
+	Query string
+	Hits  []db.SegmentHit
+	Err   error
+}
+
+// SwitcherLoadedMsg carries the sessions the quick switcher searches.
+type SwitcherLoadedMsg struct {
+	Sessions []SessionRow
+}
+
+// ReplayTickMsg advances an open replay. Gen identifies the replay that
+// scheduled it; At is the wall-clock time it fired.
+type ReplayTickMsg struct {
+	Gen int
+	At  time.Time
+}
+
+// SessionRow is one session in the browser.
+type SessionRow struct {
+	ID          string
+	Title       string
+	Status      string
+	Locale      string
+	Device      string
+	SystemAudio *bool
+	StartedAt   time.Time
+	Segments    int
+
+	// ForegroundApp and MeetingURL are optional session context; empty
+	// when not recorded.
+	ForegroundApp string
+	MeetingURL    string
+}
@@ -3,18 +3,26 @@ package app
 import (
 	"context"
 	"fmt"
+	"io"
 	"math"
 	"os"
 	"path/filepath"
-	"sort"
 	"strings"
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/x/ansi"
+	"github.com/jwulff/steno/internal/bugreport"
+	"github.com/jwulff/steno/internal/coding"
+	"github.com/jwulff/steno/internal/config"
 	"github.com/jwulff/steno/internal/daemon"
 	"github.com/jwulff/steno/internal/db"
+	"github.com/jwulff/steno/internal/dictation"
+	"github.com/jwulff/steno/internal/quality"
+	"github.com/jwulff/steno/internal/scrub"
+	"github.com/jwulff/steno/internal/share"
 	"github.com/jwulff/steno/internal/ui"
+	"github.com/jwulff/steno/internal/version"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -44,11 +52,15 @@ const (
 	StatusPaused     EngineStatus = "paused"
 )
 
-// ErrorEntry captures one non-transient error for the U9 ring buffer.
-// Errors are surfaced via the `e` keybind error-history modal.
+// ErrorEntry is one error-bar notification, kept in the U9 ring buffer
+// and surfaced via the `e` keybind notifications modal. See errors.go.
 type ErrorEntry struct {
 	Timestamp time.Time
+	Severity  Severity
 	Message   string
+	// Guidance is what the user can do about it, when known.
+	Guidance  string
+	Transient bool
 }
 
 // errorRingCapacity bounds the in-memory ring buffer. Errors past this
@@ -91,6 +103,16 @@ type TranscriptEntry struct {
 	Timestamp  time.Time
 	SeqNum     int
 	IsBoundary bool
+	// Speaker is the segment's diarized speaker label, "" when it has
+	// none. See speakerlabels.go.
+	Speaker string
+
+	// SessionID scopes SeqNum: entries are ordered by (session,
+	// sequence number). See insertSegment.
+	SessionID string
+	// BackfilledAt is set when the segment landed above the tail, for a
+	// brief highlight. Zero for segments that arrived in order.
+	BackfilledAt time.Time
 }
 
 // TopicDisplay holds a topic for display in the topic panel.
@@ -102,13 +124,14 @@ type TopicDisplay struct {
 	SegmentRangeEnd   int
 	Expanded          bool
 	Segments          []TopicSegment // loaded on expand
+	UserEdited        bool           // title/summary edited with `r`
 }
 
 // Model is the root bubbletea model for the steno TUI.
 type Model struct {
 	// Connection state
-	client    *daemon.Client // command connection
-	evClient  *daemon.Client // event subscription connection
+	client    *daemon.Client // daemon connection: commands and events
+	evClient  *daemon.Client // event source: client, or a shared view's stream
 	connected bool
 	connError string
 
@@ -117,13 +140,66 @@ type Model struct {
 	// `recording` is retained for legacy paths (StatusResponseMsg etc.)
 	// but the U9 status bar reads `engineStatus` first. The Swift daemon
 	// only emits `recording: bool` on `event:"status"` today, so the
-	// translation lives in handleEvent.
+	// translation lives in onStatus.
 	recording   bool
 	engineStatus EngineStatus
 	sessionID   string
 	deviceName  string
 	systemAudio bool
 	devices     []string
+	// stopRequested is set while a stop or pause the TUI sent is on its
+	// way, so onStatus alerts only when recording stops on its own.
+	stopRequested bool
+	// locale is the recognizer's locale, from status and `locale`
+	// events; `L` cycles it through capture.locales.
+	locale string
+	// speaker is the latest diarized speaker (event "speaker").
+	speaker activeSpeaker
+	// speakerNames maps diarized labels to names for the current
+	// session, or offers the previous recurring session's. See
+	// speakernames.go.
+	speakerNames speakerNameState
+	// configWatch follows the config file for edits; nil when not
+	// watching. See configreload.go.
+	configWatch *configWatch
+	// history tracks reading back the active session's earlier segments
+	// after a start or reconnect. See history.go.
+	history historyState
+	// viewPrefs tracks the session's saved density, filter and unfolded
+	// topics. See viewprefs.go.
+	viewPrefs viewPrefState
+	// speakerEdit is the `U` speaker list, nil when closed. See
+	// speakerlabels.go.
+	speakerEdit *speakerEditor
+	// decisions is the decisions lane under the topics: segments the
+	// classifier flagged, loaded for decisionsFor and extended live. See
+	// decisions.go.
+	decisions    []decisionEntry
+	decisionsFor string
+	// foregroundApp is the app the daemon saw in focus when the current
+	// session opened. Recorded only with config metadata.foreground_app.
+	foregroundApp string
+	// callApp is the conferencing app whose call the current session was
+	// opened during, per status; call is the app on a call right now,
+	// per call events. See calls.go.
+	callApp string
+	call    string
+	// monitor finds capture gaps in qualityFor, the current session;
+	// qualityReport is the end-of-session report screen, nil when
+	// closed. See quality.go.
+	monitor       quality.Monitor
+	qualityFor    string
+	qualityReport *qualityScreen
+
+	// recovery is the `I` interrupted-sessions screen; nil when closed.
+	// recoveryChecked is set once the sessions left active have been
+	// looked for on connecting. See recovery.go.
+	recovery        *recoveryScreen
+	recoveryChecked bool
+
+	// Partials kept in a sidecar for crash recovery (config
+	// capture.partial_drafts). See drafts.go.
+	drafts draftState
 
 	// Pause state (U9 / U10 wire)
 	pauseExpiresAt     *time.Time // nil for indefinite or not-paused
@@ -142,12 +218,21 @@ type Model struct {
 
 	// Last-segment indicator (R12). Tracks the wall-clock of the most
 	// recent finalized segment so the status bar can render
-	// "last segment Ns ago" with a yellow escalation at >=60s.
+	// "last heard Ns ago" with a yellow escalation at >=60s. Seeded from
+	// `status` so a freshly-attached TUI knows it before any segment.
 	lastSegmentAt time.Time
 
+	// segmentCount is the current session's segment count: the daemon's
+	// `status` figure, advanced by live segments. Shown in the transcript
+	// header; zero means unknown.
+	segmentCount int
+
 	// Transcript
 	entries  []TranscriptEntry
 	partials map[string]string // source -> partial text
+	// segLines caches each segment's laid-out lines across renders, so
+	// partials and meters don't re-wrap the whole transcript. render.go.
+	segLines *segmentLines
 
 	// Heal markers keyed by sequenceNumber (U9). The Swift
 	// EventBroadcaster's `event:"segment"` payload doesn't currently
@@ -164,10 +249,22 @@ type Model struct {
 	topics          []TopicDisplay
 	selectedTopic   int
 	modelProcessing bool
+	// pressure is the daemon's thermal / power pressure report.
+	pressure resourcePressure
+	// topicsLoading is set from a topics reload until TopicsLoadedMsg;
+	// the panel keeps the old list under a loading row meanwhile.
+	topicsLoading bool
 
 	// Summary
 	summaryText string
+	// summaries are the session's rows in the summaries table, oldest
+	// first, listed by the summary view (s). See summaries.go.
+	summaries   []db.Summary
 	showSummary bool
+	// ticker shows the summary's last sentence under the transcript
+	// header (config `display.summary_ticker`, toggled with T). See
+	// ticker.go.
+	ticker bool
 
 	// UI state
 	focusedPanel     PanelFocus
@@ -177,15 +274,81 @@ type Model struct {
 	transcriptLive   bool
 	topicScroll      int
 
-	// Errors
-	errorMessage   string
-	errorTransient bool
+	// Errors: the active stack behind the error bar (most severe shown,
+	// transient entries expire). See errors.go.
+	errorStack []ErrorEntry
 
-	// Error history ring buffer (U9 Refinements). Last 10 non-transient
-	// errors. `e` keybind toggles a modal that lists them with timestamps.
+	// Error history ring buffer (U9 Refinements). Last 10 notifications
+	// of any severity. `e` keybind toggles a modal that lists them with
+	// timestamps.
 	errorHistory   []ErrorEntry
 	showErrorModal bool
 
+	// Session browser (`b`): past sessions with locale / device /
+	// system-audio, filterable by the latter two. See browser.go.
+	browser sessionBrowser
+	// switcher is the ctrl+k quick switcher; nil when closed.
+	switcher *quickSwitcher
+	// keywords is the `K` keyword cloud; nil when closed. filter is the
+	// transcript filter it applies on enter; nil shows every segment.
+	keywords *keywordCloud
+	filter   *transcriptFilter
+	// search is the `/` transcript search; nil when none. See search.go.
+	search *transcriptSearch
+	// sessionSearch is the `ctrl+f` search of every session; nil when
+	// closed. See sessionsearch.go.
+	sessionSearch *sessionSearch
+	// entityList is the `X` list of dates, times, amounts and links;
+	// nil when closed. See entities.go.
+	entityList *entityList
+	// codes is the `C` view of the codebook's counts; nil when closed.
+	// codebook and codingStep are from coding in the config. See
+	// codes.go.
+	codes      *codesView
+	codebook   *coding.Codebook
+	codingStep time.Duration
+	// cursor is the transcript's current segment; nil until j/k is
+	// pressed with the transcript focused. See cursor.go.
+	cursor *transcriptCursor
+	// searchMenu is the `/` saved-searches menu; nil when closed.
+	// alertSearches are the saved searches checked against each new
+	// segment. See searches.go.
+	searchMenu    *savedSearchMenu
+	alertSearches []db.SavedSearch
+
+	// replay is the recorded session opened from the browser with enter,
+	// nil when none is open. See replay.go.
+	replay    *replaySession
+	replayGen int
+
+	// clock is the time source for timestamps, timers and ticks. See
+	// clock.go.
+	clock Clock
+
+	// topicEdit is the open topic editor (`r`), nil when not editing.
+	// See topicedit.go.
+	topicEdit *topicEditor
+
+	// notes is the open session notes pane (`N`), nil when closed. See
+	// notes.go.
+	notes *notesEditor
+
+	// keyHelp is set while the key help overlay (`?`) is open. See
+	// footer.go.
+	keyHelp bool
+
+	// exporting is the running transcript export (`W`), nil when none.
+	// exportConfig says where it goes and how it's anonymized. See
+	// export.go. exportDialog picks the part of the session to export;
+	// nil when closed. See exportdialog.go.
+	exporting    *exportJob
+	exportConfig config.ExportConfig
+	exportDialog *exportDialog
+
+	// events routes daemon events to their handlers (handleEvent). Nil
+	// means stockEvents.
+	events *eventBus
+
 	// Pause-hint flash (U9): "press p to resume first" shown after a
 	// spacebar press while paused. Set by the key handler, cleared by
 	// ClearPauseHintMsg after ~2s.
@@ -201,9 +364,83 @@ type Model struct {
 	// DB
 	store *db.Store
 
+	// Display-time scrubber for transcript text (config `scrub`). Nil
+	// when scrubbing is off; applied in the render path only, never to
+	// m.entries or the DB, so turning it off restores the real text.
+	scrubber *scrub.Scrubber
+
+	// Recent protocol traffic on both clients, for bug reports (`!`),
+	// and the scrubber those reports use (defaults + config patterns,
+	// independent of whether display scrubbing is on).
+	msgLog         *daemon.MessageLog
+	reportScrubber *scrub.Scrubber
+
+	// notice is a short-lived status-bar message (e.g. where a bug
+	// report was saved), cleared by ClearNoticeMsg.
+	notice string
+
+	// Daemon version / build from the `version` command; empty when the
+	// daemon predates it. Compared against version.Version in the footer.
+	daemonVersion string
+	daemonBuild   string
+	// daemonProtocol is the daemon's protocol revision, 0 when it
+	// predates versioning. Compared against daemon.ProtocolVersion.
+	daemonProtocol int
+
+	// Transcript layout (config `display.density`, cycled with `v`).
+	density Density
+	// reading is reading mode (config `display.reading_mode`, toggled
+	// with R): the transcript alone, narrow and centered. See reading.go.
+	reading bool
+
+	// Per-source capture preferences (config `capture`) sent with `start`.
+	capture config.CaptureConfig
+
+	// Opt-in session context (config `metadata`) recorded with the
+	// device in session_metadata.
+	metadata config.MetadataConfig
+
+	// SQLite connection tuning (config `database`) for every handle the
+	// TUI opens.
+	dbOptions db.Options
+
+	// Recording-consent reminder (config `consent`). See consent.go.
+	consent      config.ConsentConfig
+	consentState consentState
+
+	// Dictation mode (config `dictation`). See dictation.go.
+	dictationConfig config.DictationConfig
+	dictation       dictationState
+
+	// Speaking time per source and speaker in the current session, for
+	// the footer's ratio bar. See talktime.go.
+	talk talkTime
+
+	// Attention cues (config `alerts`) for critical events. See alert.go.
+	alerts      config.AlertsConfig
+	bellOut     io.Writer
+	lastAlertAt time.Time
+	flashing    bool
+
+	// Voice-activated start (daemon `arm`). See autostart.go.
+	armed         bool
+	autoStartedAt time.Time
+
 	// Reconnect
 	reconnecting     bool
 	reconnectAttempt int
+
+	// dbOnly never connects to the daemon; see WithDBOnly.
+	dbOnly bool
+
+	// Live co-viewing (see share.go): the hub this TUI shares its view
+	// on, or the host it views instead of a daemon and its token.
+	shareHub  *share.Hub
+	viewAddr  string
+	viewToken string
+
+	// tutorial plays `steno tutorial`'s canned meeting; see tutorial.go.
+	tutorial *tutorial
 }
 
 // New creates a new Model with default state.
@@ -212,21 +449,82 @@ type Model struct {
 // Support/Steno/.first_launch_seen). If it doesn't exist, the banner
 // is shown above the segment timeline until the user dismisses it.
 func New() Model {
+	return NewWithConfig(config.Config{})
+}
+
+// NewWithConfig creates a Model using the TUI settings file. cfg is
+// expected to have passed config.Load's validation. If an invalid scrub
+// pattern slips through anyway, fall back to the built-in patterns: the
+// user asked for masking, so failing open would leak what they meant
+// to hide.
+func NewWithConfig(cfg config.Config) Model {
+	scrubber, err := cfg.Scrub.Scrubber()
+	if err != nil {
+		scrubber, _ = scrub.New(scrub.DefaultPatterns, cfg.Scrub.Replacement)
+	}
+	density, _ := ParseDensity(cfg.Display.Density) // validated by config.Load
+	reportScrubber, err := bugreport.NewScrubber(cfg.Scrub.Patterns)
+	if err != nil {
+		reportScrubber, _ = bugreport.NewScrubber(nil)
+	}
+	builtinMacros, _ := dictation.Compile(nil)
+	dbOptions, _ := cfg.Database.Options() // validated by config.Load
+	codebook, _ := cfg.Coding.Codebook()   // validated by config.Load
+	codingStep, _ := cfg.Coding.Step()
 	m := Model{
+		clock:                 systemClock{},
+		scrubber:              scrubber,
+		density:               density,
+		reading:               cfg.Display.ReadingMode,
+		ticker:                cfg.Display.SummaryTicker,
+		msgLog:                daemon.NewMessageLog(bugreport.MessageCount),
+		reportScrubber:        reportScrubber,
+		capture:               cfg.Capture,
+		metadata:              cfg.Metadata,
+		dbOptions:             dbOptions,
+		consent:               cfg.Consent,
+		dictationConfig:       cfg.Dictation,
+		dictation:             dictationState{on: cfg.Dictation.Enabled, macros: builtinMacros},
+		alerts:                cfg.Alerts,
+		exportConfig:          cfg.Export,
+		codebook:              codebook,
+		codingStep:            codingStep,
+		events:                newDefaultEventBus(eventLogFromEnv()),
+		bellOut:               os.Stderr,
 		statusText:            "Connecting to steno-daemon...",
 		transcriptLive:        true,
 		focusedPanel:          FocusTranscript,
 		partials:              make(map[string]string),
+		segLines:              newSegmentLines(),
 		healMarkers:           make(map[int]string),
 		showFirstLaunchBanner: shouldShowFirstLaunchBanner(),
+		browser:               sessionBrowser{deviceIdx: -1},
+		drafts:                draftState{enabled: cfg.Capture.PartialDrafts, path: draftsPath()},
 	}
 	return m
 }
 
+// startOptions builds `start` options for device/sysAudio.
+func (m Model) startOptions(device string, sysAudio bool) daemon.StartOptions {
+	return daemon.StartOptions{
+		Device:      device,
+		SystemAudio: sysAudio,
+	}
+}
+
 // Init returns the initial command — connect to the daemon and start
 // the per-second tick for status-bar countdown / last-seg-ago redraw.
 func (m Model) Init() tea.Cmd {
-	return tea.Batch(connectCmd(), statusTickCmd())
+	if m.dbOnly {
+		return tea.Batch(openDBOnlyCmd(m.dbOptions), m.statusTickCmd(), m.configWatchCmd())
+	}
+	if m.viewAddr != "" {
+		return tea.Batch(viewCmd(m.viewAddr, m.viewToken), m.statusTickCmd(), m.configWatchCmd())
+	}
+	if m.tutorial != nil {
+		return tea.Batch(m.tutorialTickCmd(), m.statusTickCmd())
+	}
+	return tea.Batch(connectCmd(), m.statusTickCmd(), m.configWatchCmd(), m.draftsCmd())
 }
 
 // shouldShowFirstLaunchBanner returns true when the marker file CANNOT
@@ -286,8 +584,8 @@ func writeFirstLaunchMarker() {
 	_ = f.Close()
 }
 
-// connectCmd ensures the daemon is running and connects with two connections:
-// one for commands, one for event subscription.
+// connectCmd ensures the daemon is running and connects. One connection
+// carries both commands and the event subscription.
 func connectCmd() tea.Cmd {
 	return func() tea.Msg {
 		// Ensure daemon is running (auto-start if needed)
@@ -296,24 +594,20 @@ func connectCmd() tea.Cmd {
 			return DaemonConnectErrorMsg{Err: err}
 		}
 
-		sockPath := daemon.SocketPath()
-		client, err := daemon.Connect(sockPath)
+		client, err := daemon.Connect(daemon.SocketPath())
 		if err != nil {
 			return DaemonConnectErrorMsg{Err: err}
 		}
-		evClient, err := daemon.Connect(sockPath)
-		if err != nil {
-			client.Close()
-			return DaemonConnectErrorMsg{Err: err}
-		}
-		return DaemonConnectedMsg{Client: client, EvClient: evClient}
+		return DaemonConnectedMsg{Client: client}
 	}
 }
 
-// subscribeCmd sends a subscribe command on the event client and starts reading events.
-func subscribeCmd(evClient *daemon.Client) tea.Cmd {
+// subscribeCmd subscribes the connection to events and starts reading
+// them. After a reconnect, sessionID and seq name the last
+// segment seen, so the daemon replays any spoken in between.
+func subscribeCmd(evClient *daemon.Client, sessionID string, seq int) tea.Cmd {
 	return func() tea.Msg {
-		_, err := evClient.SendCommand(daemon.Command{Cmd: "subscribe"})
+		_, err := sendCommand(evClient, daemon.SubscribeSinceCmd(sessionID, seq))
 		if err != nil {
 			return DaemonEventErrorMsg{Err: err}
 		}
@@ -321,21 +615,22 @@ func subscribeCmd(evClient *daemon.Client) tea.Cmd {
 	}
 }
 
-// readEventCmd reads the next event from the event client.
-func readEventCmd(evClient *daemon.Client) tea.Cmd {
-	return func() tea.Msg {
-		ev, err := evClient.ReadEvent()
-		if err != nil {
-			return DaemonEventErrorMsg{Err: err}
-		}
-		return DaemonEventMsg{Event: ev}
-	}
+// commandTimeout bounds each command the TUI sends, well inside the
+// client's DefaultCommandTimeout: a key the daemon doesn't answer in
+// this long reports an error rather than leaving its command waiting.
+const commandTimeout = 10 * time.Second
+
+// sendCommand sends cmd on client within commandTimeout.
+func sendCommand(client *daemon.Client, cmd daemon.Command) (daemon.Response, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+	return client.SendCommandCtx(ctx, cmd)
 }
 
 // statusCmd fetches daemon status.
 func statusCmd(client *daemon.Client) tea.Cmd {
 	return func() tea.Msg {
-		resp, err := client.SendCommand(daemon.Command{Cmd: "status"})
+		resp, err := sendCommand(client, daemon.Command{Cmd: "status"})
 		if err != nil {
 			return DaemonEventErrorMsg{Err: err}
 		}
@@ -343,10 +638,19 @@ func statusCmd(client *daemon.Client) tea.Cmd {
 	}
 }
 
+// versionCmd asks the daemon for its version. Failures are not surfaced
+// as errors: older daemons simply have no `version` command.
+func versionCmd(client *daemon.Client) tea.Cmd {
+	return func() tea.Msg {
+		resp, err := sendCommand(client, daemon.VersionCmd())
+		return VersionResponseMsg{Response: resp, Err: err}
+	}
+}
+
 // devicesCmd fetches available devices.
 func devicesCmd(client *daemon.Client) tea.Cmd {
 	return func() tea.Msg {
-		resp, err := client.SendCommand(daemon.Command{Cmd: "devices"})
+		resp, err := sendCommand(client, daemon.Command{Cmd: "devices"})
 		if err != nil {
 			return DaemonEventErrorMsg{Err: err}
 		}
@@ -354,15 +658,12 @@ func devicesCmd(client *daemon.Client) tea.Cmd {
 	}
 }
 
-// startCmd sends a start recording command.
-func startCmd(client *daemon.Client, device string, sysAudio bool) tea.Cmd {
+// startCmd sends a start recording command. Starting can wait on
+// permission prompts and loading the speech model, so only the client's
+// own timeout bounds it.
+func startCmd(client *daemon.Client, opts daemon.StartOptions) tea.Cmd {
 	return func() tea.Msg {
-		cmd := daemon.Command{
-			Cmd:         "start",
-			Device:      device,
-			SystemAudio: daemon.BoolPtr(sysAudio),
-		}
-		resp, err := client.SendCommand(cmd)
+		resp, err := client.SendCommand(daemon.StartCmd(opts))
 		if err != nil {
 			return DaemonEventErrorMsg{Err: err}
 		}
@@ -376,7 +677,7 @@ func startCmd(client *daemon.Client, device string, sysAudio bool) tea.Cmd {
 // scripting / diagnostic use; the U9 keybind no longer reaches it.
 func stopCmd(client *daemon.Client) tea.Cmd {
 	return func() tea.Msg {
-		resp, err := client.SendCommand(daemon.Command{Cmd: "stop"})
+		resp, err := sendCommand(client, daemon.Command{Cmd: "stop"})
 		if err != nil {
 			return DaemonEventErrorMsg{Err: err}
 		}
@@ -387,7 +688,7 @@ func stopCmd(client *daemon.Client) tea.Cmd {
 // pauseCmd sends a `pause` command with a finite auto-resume window. (U9)
 func pauseCmd(client *daemon.Client, autoResumeSeconds float64) tea.Cmd {
 	return func() tea.Msg {
-		resp, err := client.SendCommand(daemon.PauseCmd(autoResumeSeconds))
+		resp, err := sendCommand(client, daemon.PauseCmd(autoResumeSeconds))
 		if err != nil {
 			return DaemonEventErrorMsg{Err: err}
 		}
@@ -398,7 +699,7 @@ func pauseCmd(client *daemon.Client, autoResumeSeconds float64) tea.Cmd {
 // pauseIndefiniteCmd sends a `pause` command with no auto-resume. (U9)
 func pauseIndefiniteCmd(client *daemon.Client) tea.Cmd {
 	return func() tea.Msg {
-		resp, err := client.SendCommand(daemon.PauseIndefiniteCmd())
+		resp, err := sendCommand(client, daemon.PauseIndefiniteCmd())
 		if err != nil {
 			return DaemonEventErrorMsg{Err: err}
 		}
@@ -409,7 +710,7 @@ func pauseIndefiniteCmd(client *daemon.Client) tea.Cmd {
 // resumeCmd sends a `resume` command. (U9)
 func resumeCmd(client *daemon.Client) tea.Cmd {
 	return func() tea.Msg {
-		resp, err := client.SendCommand(daemon.ResumeCmd())
+		resp, err := sendCommand(client, daemon.ResumeCmd())
 		if err != nil {
 			return DaemonEventErrorMsg{Err: err}
 		}
@@ -420,7 +721,7 @@ func resumeCmd(client *daemon.Client) tea.Cmd {
 // demarcateCmd sends a `demarcate` command (atomic session boundary). (U9)
 func demarcateCmd(client *daemon.Client) tea.Cmd {
 	return func() tea.Msg {
-		resp, err := client.SendCommand(daemon.DemarcateCmd())
+		resp, err := sendCommand(client, daemon.DemarcateCmd())
 		if err != nil {
 			return DaemonEventErrorMsg{Err: err}
 		}
@@ -428,9 +729,47 @@ func demarcateCmd(client *daemon.Client) tea.Cmd {
 	}
 }
 
+// enterSession moves the TUI to the daemon's fresh session id, after a
+// demarcate from this TUI or anywhere else.
+func (m *Model) enterSession(id string) tea.Cmd {
+	// The old session is over: report on how it was captured.
+	cmds := []tea.Cmd{m.endSessionQuality(m.sessionID)}
+	m.sessionID = id
+	m.foregroundApp = ""
+	m.callApp = ""
+	m.segmentCount = 0
+	// Reset right-hand panels for the fresh session and trigger
+	// reloads. Topics for a freshly-opened session are empty
+	// initially, so the load is mostly to clear the prior
+	// session's view; the daemon will emit a `topics` event when
+	// the LLM finishes the first extraction.
+	m.topics = m.topics[:0]
+	m.selectedTopic = 0
+	m.summaryText = ""
+	m.summaries = nil
+	if m.store != nil {
+		m.topicsLoading = true
+		cmds = append(cmds, loadTopicsCmd(m.store, m.sessionID), m.summaryCmd())
+	}
+	cmds = append(cmds, m.metadataCmd(), m.speakerNamesCmd(), m.viewPrefsCmd(), m.decisionsCmd(), m.consentCmd(), m.talkTimeCmd())
+	if (m.metadata.ForegroundApp || m.call != "") && m.client != nil {
+		// The fresh session's foreground app and call tag come
+		// with status.
+		cmds = append(cmds, statusCmd(m.client))
+	}
+	return tea.Batch(cmds...)
+}
+
+// clearNoticeCmd clears the status-bar notice after 5s.
+func (m Model) clearNoticeCmd() tea.Cmd {
+	return m.tick(5*time.Second, func(time.Time) tea.Msg {
+		return ClearNoticeMsg{}
+	})
+}
+
 // clearPauseHintCmd clears the "press p to resume first" flash after 2s.
-func clearPauseHintCmd() tea.Cmd {
-	return tea.Tick(2*time.Second, func(time.Time) tea.Msg {
+func (m Model) clearPauseHintCmd() tea.Cmd {
+	return m.tick(2*time.Second, func(time.Time) tea.Msg {
 		return ClearPauseHintMsg{}
 	})
 }
@@ -438,26 +777,26 @@ func clearPauseHintCmd() tea.Cmd {
 // statusTickCmd schedules the next per-second status-bar redraw. The
 // tick is what drives the pause-countdown and last-seg-ago UI updates
 // when no upstream events arrive.
-func statusTickCmd() tea.Cmd {
-	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+func (m Model) statusTickCmd() tea.Cmd {
+	return m.tick(time.Second, func(time.Time) tea.Msg {
 		return StatusTickMsg{}
 	})
 }
 
 // clearTransientErrorCmd fires after a delay to clear transient errors.
-func clearTransientErrorCmd() tea.Cmd {
-	return tea.Tick(5*time.Second, func(time.Time) tea.Msg {
+func (m Model) clearTransientErrorCmd() tea.Cmd {
+	return m.tick(5*time.Second, func(time.Time) tea.Msg {
 		return ClearTransientErrorMsg{}
 	})
 }
 
 // reconnectCmd schedules a reconnection attempt with exponential backoff.
-func reconnectCmd(attempt int) tea.Cmd {
+func (m Model) reconnectCmd(attempt int) tea.Cmd {
 	delay := time.Duration(1<<min(attempt, 4)) * time.Second // 1s, 2s, 4s, 8s, 16s cap
 	if delay > 30*time.Second {
 		delay = 30 * time.Second
 	}
-	return tea.Tick(delay, func(time.Time) tea.Msg {
+	return m.tick(delay, func(time.Time) tea.Msg {
 		return ReconnectTickMsg{}
 	})
 }
@@ -467,7 +806,7 @@ func loadTopicsCmd(store *db.Store, sessionID string) tea.Cmd {
 	return func() tea.Msg {
 		topics, err := store.TopicsForSession(sessionID)
 		if err != nil {
-			return TopicsLoadedMsg{} // silently ignore DB errors
+			return TopicsLoadedMsg{Err: err}
 		}
 		var loaded []TopicLoaded
 		for _, t := range topics {
@@ -477,6 +816,7 @@ func loadTopicsCmd(store *db.Store, sessionID string) tea.Cmd {
 				Summary:           t.Summary,
 				SegmentRangeStart: t.SegmentRangeStart,
 				SegmentRangeEnd:   t.SegmentRangeEnd,
+				UserEdited:        t.UserEdited,
 			})
 		}
 		return TopicsLoadedMsg{Topics: loaded}
@@ -502,27 +842,32 @@ func loadTopicSegmentsCmd(store *db.Store, sessionID, topicID string, start, end
 	}
 }
 
-// loadSummaryCmd reads the latest summary from SQLite.
+// loadSummaryCmd reads the session's summaries from SQLite.
 func loadSummaryCmd(store *db.Store, sessionID string) tea.Cmd {
 	return func() tea.Msg {
-		summary, err := store.LatestSummary(sessionID)
-		if err != nil || summary == nil {
+		summaries, err := store.SummariesForSession(sessionID)
+		if err != nil || len(summaries) == 0 {
 			return SummaryLoadedMsg{}
 		}
-		return SummaryLoadedMsg{Content: summary.Content}
+		return SummaryLoadedMsg{Content: summaries[len(summaries)-1].Content, Summaries: summaries}
 	}
 }
 
-// openStoreCmd opens the SQLite store.
-func openStoreCmd() tea.Cmd {
+// openStoreCmd opens the SQLite store. It prefers a writable handle so
+// the TUI can record client-owned session metadata, falling back to the
+// read-only open when the file isn't writable.
+func openStoreCmd(opts db.Options) tea.Cmd {
 	return func() tea.Msg {
-		dbPath := db.DefaultDBPath()
-		if p := os.Getenv("STENO_DB"); p != "" {
-			dbPath = p
+		dbPath := storePath()
+		if _, err := os.Stat(dbPath); err != nil {
+			return nil // silently ignore if DB not available yet
 		}
-		store, err := db.Open(dbPath)
+		store, err := db.OpenClient(dbPath, opts)
 		if err != nil {
-			return nil // silently ignore if DB not available yet
+			store, err = db.Open(dbPath, opts)
+		}
+		if err != nil {
+			return nil
 		}
 		return storeOpenedMsg{store: store}
 	}
@@ -530,12 +875,30 @@ func openStoreCmd() tea.Cmd {
 
 type storeOpenedMsg struct{ store *db.Store }
 
+// storePath is the database file: STENO_DB, or the default location.
+func storePath() string {
+	if p := os.Getenv("STENO_DB"); p != "" {
+		return p
+	}
+	return db.DefaultDBPath()
+}
+
 // Update processes messages and returns the updated model and any commands.
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 
 	case tea.KeyMsg:
-		return m.handleKey(msg)
+		if m.tutorial != nil {
+			return m.handleTutorialKey(msg)
+		}
+		before := m.currentViewPrefs()
+		next, cmd := m.handleKey(msg)
+		if nm, ok := next.(Model); ok {
+			if save := nm.keepViewPrefs(before); save != nil {
+				return nm, tea.Batch(cmd, save)
+			}
+		}
+		return next, cmd
 
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
@@ -544,20 +907,185 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case DaemonConnectedMsg:
 		m.client = msg.Client
-		m.evClient = msg.EvClient
+		m.evClient = msg.Client
+		var malformed tea.Cmd
+		if m.client != nil {
+			m.client.SetMessageLog(m.msgLog)
+			malformed = watchMalformed(m.client)
+		}
 		m.connected = true
 		m.connError = ""
 		m.reconnecting = false
 		m.reconnectAttempt = 0
 		m.statusText = "Connected"
-		// Subscribe on event client, fetch status/devices on command client
+		// Subscribe and fetch status/devices; the responses and events
+		// share the connection.
+		sessionID, seq := m.resumePoint()
 		return m, tea.Batch(
-			subscribeCmd(m.evClient),
+			malformed,
+			subscribeCmd(m.evClient, sessionID, seq),
 			statusCmd(m.client),
+			versionCmd(m.client),
 			devicesCmd(m.client),
-			openStoreCmd(),
+			openStoreCmd(m.dbOptions),
 		)
 
+	case BugReportSavedMsg:
+		if msg.Err != nil {
+			return m, m.pushError(SeverityError, "bug report: "+msg.Err.Error(), true)
+		}
+		m.notice = "Bug report saved to " + msg.Path
+		return m, m.clearNoticeCmd()
+
+	case ExportTranscriptLoadedMsg:
+		return m.applyExportTranscript(msg)
+
+	case ExportProgressMsg:
+		return m.applyExportProgress(msg)
+
+	case FollowUpCreatedMsg:
+		if msg.Err != nil {
+			return m, m.pushError(SeverityWarn, "follow-up: "+msg.Err.Error(), true)
+		}
+		m.notice = "Follow-up for “" + msg.Title + "” opened in Calendar for " + msg.Start.Format("Mon 15:04")
+		return m, m.clearNoticeCmd()
+
+	case ClearNoticeMsg:
+		m.notice = ""
+		return m, nil
+
+	case FlashDoneMsg:
+		m.flashing = false
+		return m, nil
+
+	case ReplayLoadedMsg:
+		return m.openReplay(msg)
+
+	case TopicEditedMsg:
+		return m.applyTopicEdit(msg)
+
+	case SessionNotesLoadedMsg:
+		return m.applySessionNotesLoaded(msg)
+
+	case SessionNotesSavedMsg:
+		return m.applySessionNotesSaved(msg)
+
+	case SpeakerNamesLoadedMsg:
+		m.applySpeakerNames(msg)
+		return m, nil
+
+	case DecisionsLoadedMsg:
+		m.applyDecisions(msg)
+		return m, nil
+
+	case TalkTimeLoadedMsg:
+		m.applyTalkTime(msg)
+		return m, nil
+
+	case SavedSearchesLoadedMsg:
+		m.applySavedSearches(msg)
+		return m, nil
+
+	case AlertSearchesLoadedMsg:
+		m.setAlertSearches(msg.Searches, msg.Err)
+		return m, nil
+
+	case SessionQualityMsg:
+		m.applySessionQuality(msg)
+		return m, nil
+
+	case RecoveryLoadedMsg:
+		return m.applyRecoveryLoaded(msg)
+
+	case RecoveryActionMsg:
+		return m.applyRecoveryAction(msg)
+
+	case DictationMacrosLoadedMsg:
+		return m, m.applyDictationMacros(msg)
+
+	case ConsentLoadedMsg:
+		return m, m.applyConsent(msg)
+
+	case ConsentAcknowledgedMsg:
+		if msg.Err != nil {
+			return m, m.pushError(SeverityWarn, "save consent acknowledgment: "+msg.Err.Error(), true)
+		}
+		return m, nil
+
+	case AnnouncementCopiedMsg:
+		if msg.Err != nil {
+			return m, m.pushError(SeverityWarn, "copy consent announcement: "+msg.Err.Error(), true)
+		}
+		if msg.SessionID == m.consentState.sessionID {
+			m.consentState.copied = true
+		}
+		return m, nil
+
+	case SpeakerNamesCopiedMsg:
+		if msg.Err != nil {
+			return m, m.pushError(SeverityWarn, "save speaker names: "+msg.Err.Error(), true)
+		}
+		return m, nil
+
+	case SpeakerRenamedMsg:
+		return m.applySpeakerRename(msg)
+
+	case configTickMsg:
+		if w := m.configWatch; w != nil {
+			return m, checkConfigCmd(w.path, w.modTime, w.size)
+		}
+		return m, nil
+
+	case ConfigCheckedMsg:
+		if m.configWatch == nil {
+			return m, nil
+		}
+		return m.handleConfigChecked(msg)
+
+	case HistoryLoadedMsg:
+		m.applyHistory(msg)
+		return m, nil
+
+	case ViewPrefsLoadedMsg:
+		return m, m.applyViewPrefs(msg)
+
+	case ViewPrefsSavedMsg:
+		if msg.Err != nil {
+			return m, m.pushError(SeverityWarn, "save view prefs: "+msg.Err.Error(), true)
+		}
+		return m, nil
+
+	case ReplayTickMsg:
+		return m.handleReplayTick(msg)
+
+	case tutorialTickMsg:
+		return m.handleTutorialTick(msg)
+
+	case DraftsLoadedMsg:
+		return m.applyDraftsLoaded(msg)
+
+	case DraftTickMsg:
+		return m.handleDraftTick()
+
+	case DraftsSavedMsg:
+		if msg.Err != nil {
+			return m, m.pushError(SeverityWarn, "save partial drafts: "+msg.Err.Error(), true)
+		}
+		return m, nil
+
+	case SwitcherLoadedMsg:
+		return m.applySwitcherLoaded(msg)
+
+	case SessionSearchResultsMsg:
+		return m.applySessionSearchResults(msg)
+
+	case BackfillHighlightMsg:
+		// Nothing to update: returning re-renders without the highlight.
+		return m, nil
+
+	case VersionResponseMsg:
+		return m.handleVersion(msg)
+
 	case DaemonConnectErrorMsg:
 		m.connected = false
 		m.connError = msg.Err.Error()
@@ -569,22 +1097,45 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		m.reconnecting = true
 		m.statusText = "Daemon not running. Reconnecting..."
-		return m, reconnectCmd(m.reconnectAttempt)
+		return m, m.reconnectCmd(m.reconnectAttempt)
 
 	case StatusResponseMsg:
 		r := msg.Response
 		if r.Recording != nil {
 			m.recording = *r.Recording
 		}
+		var ended tea.Cmd
 		if r.SessionID != "" {
+			// The session in hand ended while the TUI wasn't looking,
+			// e.g. across a reconnect.
+			if m.sessionID != "" && r.SessionID != m.sessionID {
+				ended = m.endSessionQuality(m.sessionID)
+			}
 			m.sessionID = r.SessionID
+			m.foregroundApp = r.ForegroundApp
+			m.callApp = r.CallApp
+		}
+		if r.Segments != nil {
+			m.segmentCount = *r.Segments
+		}
+		if r.LastSegmentAt != nil {
+			if at := timeFromUnix(*r.LastSegmentAt); at.After(m.lastSegmentAt) {
+				m.lastSegmentAt = at
+			}
 		}
 		if r.Device != "" {
 			m.deviceName = r.Device
 		}
+		if r.Locale != "" {
+			m.locale = r.Locale
+		}
 		if r.SystemAudio != nil {
 			m.systemAudio = *r.SystemAudio
 		}
+		if r.Armed != nil {
+			m.armed = *r.Armed
+		}
+		m.pressure.apply(r.ThermalState, r.LowPower, r.SummariesDeferred)
 		if r.Status != "" {
 			m.statusText = r.Status
 			m.engineStatus = EngineStatus(r.Status)
@@ -592,7 +1143,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// U10: status response carries pause-state on every status fetch
 		// so a freshly-connected TUI sees the truth immediately.
 		m.applyPauseFields(r.Paused, r.PausedIndefinitely, r.PauseExpiresAt)
-		return m, nil
+		m.shareStatus()
+		recovery := m.recoveryCheckCmd()
+		return m, tea.Batch(ended, m.historyCmd(), m.metadataCmd(), m.speakerNamesCmd(), m.viewPrefsCmd(), m.decisionsCmd(), m.consentCmd(), m.talkTimeCmd(), m.summaryCmd(), recovery)
 
 	case DevicesResponseMsg:
 		if msg.Response.Devices != nil {
@@ -604,14 +1157,13 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		r := msg.Response
 		if r.OK {
 			m.recording = true
-			if r.SessionID != "" {
+			if r.SessionID != "" && r.SessionID != m.sessionID {
 				m.sessionID = r.SessionID
+				m.segmentCount = 0
 			}
 			m.statusText = "Recording"
 		} else {
-			m.errorMessage = r.Error
-			m.errorTransient = true
-			return m, clearTransientErrorCmd()
+			return m, m.pushError(SeverityWarn, r.Error, true)
 		}
 		return m, nil
 
@@ -622,54 +1174,97 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.partials = make(map[string]string)
 			m.statusText = "Idle"
 		} else {
-			m.errorMessage = r.Error
+			return m, m.pushError(SeverityError, r.Error, false)
 		}
-		return m, nil
+		return m, m.endSessionQuality(m.sessionID)
 
 	case DaemonEventMsg:
 		cmd := m.handleEvent(msg.Event)
-		// Continue reading events on event client
+		m.publish(msg.Event)
+		// Continue reading events
 		return m, tea.Batch(cmd, readEventCmd(m.evClient))
 
+	case DaemonEventsMsg:
+		cmds := make([]tea.Cmd, 0, len(msg.Events)+1)
+		for _, ev := range msg.Events {
+			cmds = append(cmds, m.handleEvent(ev))
+			m.publish(ev)
+		}
+		if msg.Err != nil {
+			next, cmd := m.Update(DaemonEventErrorMsg{Err: msg.Err})
+			return next, tea.Batch(append(cmds, cmd)...)
+		}
+		return m, tea.Batch(append(cmds, readEventCmd(m.evClient))...)
+
 	case DaemonEventErrorMsg:
+		// Losing a live connection means the daemon crashed or quit;
+		// failed reconnects don't alert again.
+		var alert tea.Cmd
+		if m.connected {
+			alert = m.alert()
+		}
 		m.connected = false
 		m.connError = msg.Err.Error()
 		m.statusText = "Disconnected. Reconnecting..."
+		m.loseHistory()
+		m.keepDrafts()
 		m.reconnecting = true
-		if m.client != nil {
-			m.client.Close()
-			m.client = nil
-		}
-		if m.evClient != nil {
-			m.evClient.Close()
-			m.evClient = nil
-		}
-		return m, reconnectCmd(m.reconnectAttempt)
+		m.closeClients()
+		m.client, m.evClient = nil, nil
+		return m, tea.Batch(m.reconnectCmd(m.reconnectAttempt), alert)
 
 	case ReconnectTickMsg:
 		m.reconnectAttempt++
+		if m.viewAddr != "" {
+			return m, viewCmd(m.viewAddr, m.viewToken)
+		}
 		return m, connectCmd()
 
+	case viewerConnectedMsg:
+		// Only events: a viewer has no command connection to the host.
+		m.evClient = msg.client
+		m.connected = true
+		m.connError = ""
+		m.reconnecting = false
+		m.reconnectAttempt = 0
+		m.statusText = "Viewing " + m.viewAddr
+		m.evClient.SetMessageLog(m.msgLog)
+		return m, tea.Batch(watchMalformed(m.evClient), readEventCmd(m.evClient))
+
+	case malformedLineMsg:
+		return m.handleMalformedLine(msg)
+
 	case storeOpenedMsg:
 		m.store = msg.store
+		// The status response may have landed before the store opened.
+		recovery := m.recoveryCheckCmd()
+		var sessions tea.Cmd
+		if m.browser.open {
+			sessions = m.reloadSessionsCmd()
+		}
+		return m, tea.Batch(m.historyCmd(), m.metadataCmd(), m.speakerNamesCmd(), m.viewPrefsCmd(), m.decisionsCmd(), m.consentCmd(), m.talkTimeCmd(),
+			loadAlertSearchesCmd(m.store), m.dictationCmd(), m.summaryCmd(), recovery, sessions)
+
+	case storeOpenFailedMsg:
+		m.connError = msg.err.Error()
 		return m, nil
 
-	case TopicsLoadedMsg:
-		m.topics = m.topics[:0]
-		for _, t := range msg.Topics {
-			m.topics = append(m.topics, TopicDisplay{
-				ID:                t.ID,
-				Title:             t.Title,
-				Summary:           t.Summary,
-				SegmentRangeStart: t.SegmentRangeStart,
-				SegmentRangeEnd:   t.SegmentRangeEnd,
-			})
-		}
-		if m.selectedTopic >= len(m.topics) {
-			m.selectedTopic = max(0, len(m.topics)-1)
+	case SessionsLoadedMsg:
+		m.browser.sessions = msg.Sessions
+		m.browser.devices = msg.Devices
+		if m.browser.selected >= len(m.browser.sessions) {
+			m.browser.selected = max(0, len(m.browser.sessions)-1)
 		}
 		return m, nil
 
+	case TopicsLoadedMsg:
+		m.topicsLoading = false
+		if msg.Err != nil {
+			return m, m.pushError(SeverityWarn, "Couldn't load topics: "+msg.Err.Error(), true)
+		}
+		cmd := m.applyTopics(msg.Topics)
+		return m, cmd
+
 	case TopicSegmentsLoadedMsg:
 		for i := range m.topics {
 			if m.topics[i].ID == msg.TopicID {
@@ -681,32 +1276,46 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case SummaryLoadedMsg:
 		m.summaryText = msg.Content
+		m.summaries = msg.Summaries
 		return m, nil
 
 	case ClearTransientErrorMsg:
-		if m.errorTransient {
-			m.errorMessage = ""
-			m.errorTransient = false
-		}
+		m.clearExpiredErrors()
 		return m, nil
 
 	case PauseResponseMsg:
 		// Pause / resume responses primarily flow through the
 		// pause_state event; we only surface command-error feedback here.
 		if !msg.Response.OK {
-			m.appendErrorHistory(msg.Response.Error)
-			m.errorMessage = msg.Response.Error
-			m.errorTransient = true
-			return m, clearTransientErrorCmd()
+			m.stopRequested = false
+			return m, m.pushError(SeverityWarn, msg.Response.Error, true)
 		}
 		return m, nil
 
+	case ArmResponseMsg:
+		return m.applyArmResponse(msg.Response)
+
+	case AutoStartExpiredMsg:
+		if msg.StartedAt.Equal(m.autoStartedAt) {
+			m.autoStartedAt = time.Time{}
+		}
+		return m, nil
+
+	case SetLocaleResponseMsg:
+		if !msg.Response.OK {
+			m.notice = ""
+			return m, m.pushError(SeverityWarn, "switch to "+msg.Locale+": "+msg.Response.Error, true)
+		}
+		m.locale = msg.Locale
+		if msg.Response.Locale != "" {
+			m.locale = msg.Response.Locale
+		}
+		m.notice = "Transcribing in " + m.locale
+		return m, m.clearNoticeCmd()
+
 	case DemarcateResponseMsg:
 		if !msg.Response.OK {
-			m.appendErrorHistory(msg.Response.Error)
-			m.errorMessage = msg.Response.Error
-			m.errorTransient = true
-			return m, clearTransientErrorCmd()
+			return m, m.pushError(SeverityWarn, msg.Response.Error, true)
 		}
 		// Demarcate succeeded — the daemon opened a fresh active session.
 		// Insert a UI-only boundary marker into the transcript so the
@@ -727,7 +1336,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// applies, the marker is already in roughly the right place on
 		// the timeline. Acceptable approximation.
 		m.entries = append(m.entries, TranscriptEntry{
-			Timestamp:  time.Now(),
+			Timestamp:  m.now(),
 			IsBoundary: true,
 		})
 		if m.transcriptLive {
@@ -738,30 +1347,18 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// loading data for the NEW session instead of staying anchored to
 		// the old one. Mirror the StartResponseMsg pattern (line ~585):
 		// only overwrite when the response carries a non-empty sessionId.
-		var cmds []tea.Cmd
+		// A segment of the new session may have got here first.
+		var cmd tea.Cmd
 		if msg.Response.SessionID != "" && msg.Response.SessionID != m.sessionID {
-			m.sessionID = msg.Response.SessionID
-			// Reset right-hand panels for the fresh session and trigger
-			// reloads. Topics for a freshly-opened session are empty
-			// initially, so the load is mostly to clear the prior
-			// session's view; the daemon will emit a `topics` event when
-			// the LLM finishes the first extraction.
-			m.topics = m.topics[:0]
-			m.selectedTopic = 0
-			if m.store != nil {
-				cmds = append(cmds, loadTopicsCmd(m.store, m.sessionID))
-				if m.showSummary {
-					cmds = append(cmds, loadSummaryCmd(m.store, m.sessionID))
-				}
-			}
+			cmd = m.enterSession(msg.Response.SessionID)
 		}
 		// On success the daemon will also emit a fresh status / segment
 		// stream against the new session.
-		return m, tea.Batch(cmds...)
+		return m, cmd
 
 	case PauseHintMsg:
 		m.pauseHint = true
-		return m, clearPauseHintCmd()
+		return m, m.clearPauseHintCmd()
 
 	case ClearPauseHintMsg:
 		m.pauseHint = false
@@ -771,7 +1368,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Schedule the next tick. The render is implicit — the next
 		// view call recomputes the countdown / last-seg-ago against
 		// the current wall clock.
-		return m, statusTickCmd()
+		return m, m.statusTickCmd()
 	}
 
 	return m, nil
@@ -833,13 +1430,9 @@ func timeFromUnix(ts float64) time.Time {
 	return time.Unix(int64(sec), int64(frac*1e9))
 }
 
-// appendErrorHistory inserts an entry in the ring buffer, dropping the
-// oldest when at capacity. Empty messages are ignored.
-func (m *Model) appendErrorHistory(message string) {
-	if message == "" {
-		return
-	}
-	entry := ErrorEntry{Timestamp: time.Now(), Message: message}
+// recordErrorHistory inserts an entry in the ring buffer, dropping the
+// oldest when at capacity.
+func (m *Model) recordErrorHistory(entry ErrorEntry) {
 	if len(m.errorHistory) >= errorRingCapacity {
 		// Drop oldest by shifting; ring buffer with bounded slice.
 		copy(m.errorHistory, m.errorHistory[1:])
@@ -850,146 +1443,14 @@ func (m *Model) appendErrorHistory(message string) {
 }
 
 // handleEvent processes a daemon event and returns any resulting command.
-//
-// Event types (mirrored from EventBroadcaster.swift):
-//   - partial / level / segment / topics / model_processing — unchanged
-//   - status (recording: bool) — translated to engineStatus
-//   - pause_state (paused / pausedIndefinitely / pauseExpiresAt) — U10
-//   - error with message containing "recovering: ..." — sets engineStatus
-//     to StatusRecovering. The Swift side currently routes recovering /
-//     healed / recoveryExhausted onto the .error wire channel with the
-//     transient flag distinguishing surrender from in-progress recovery
-//     (see EventBroadcaster.mapEvent comment about "U9 will introduce
-//     dedicated wire-protocol fields"). Until that lands, we sniff the
-//     message prefix.
+// The handlers for each event type are registered on the model's event
+// bus; see events.go and eventbus.go.
 func (m *Model) handleEvent(ev daemon.Event) tea.Cmd {
-	switch ev.Event {
-	case "partial":
-		if ev.Text == "" {
-			delete(m.partials, ev.Source)
-		} else {
-			m.partials[ev.Source] = ev.Text
-		}
-
-	case "segment":
-		ts := time.Now()
-		if ev.StartedAt != nil {
-			ts = timeFromUnix(*ev.StartedAt)
-		}
-		entry := TranscriptEntry{
-			Text:      ev.Text,
-			Source:    ev.Source,
-			Timestamp: ts,
-		}
-		if ev.SequenceNumber != nil {
-			entry.SeqNum = *ev.SequenceNumber
-		}
-		// Insert in chronological order — segments may arrive out of speech order
-		// when dual sources are active
-		i := sort.Search(len(m.entries), func(j int) bool {
-			return m.entries[j].Timestamp.After(ts)
-		})
-		m.entries = append(m.entries, TranscriptEntry{})
-		copy(m.entries[i+1:], m.entries[i:])
-		m.entries[i] = entry
-		delete(m.partials, ev.Source)
-		if m.transcriptLive {
-			m.scrollToBottom()
-		}
-		m.lastSegmentAt = time.Now()
-
-	case "level":
-		if ev.Mic != nil {
-			m.micLevel = *ev.Mic
-		}
-		if ev.Sys != nil {
-			m.sysLevel = *ev.Sys
-		}
-
-	case "status":
-		if ev.Recording != nil {
-			m.recording = *ev.Recording
-			if m.recording {
-				m.statusText = "Recording"
-				m.engineStatus = StatusRecording
-				// Successful recording resumes clear permission-revoked.
-				m.permissionRevoked = false
-			} else {
-				// Status with recording=false in the always-on world
-				// most likely means paused. Don't blindly write "Idle"
-				// over a known paused state.
-				if m.engineStatus != StatusPaused {
-					m.statusText = "Idle"
-					m.engineStatus = StatusIdle
-				}
-				m.partials = make(map[string]string)
-			}
-		}
-
-	case "pause_state":
-		// U10's dedicated pause-state event — applyPauseFields handles
-		// the indefinite / finite split and the resume transition.
-		m.applyPauseFields(ev.Paused, ev.PausedIndefinitely, ev.PauseExpiresAt)
-
-	case "model_processing":
-		if ev.ModelProcessing != nil {
-			m.modelProcessing = *ev.ModelProcessing
-		}
-
-	case "topics":
-		if m.store != nil && m.sessionID != "" {
-			return loadTopicsCmd(m.store, m.sessionID)
-		}
-		return nil
-
-	case "error":
-		// U5 recovering / healed / recoveryExhausted are currently
-		// multiplexed onto the error wire channel. Sniff the message
-		// prefix to route to the right TUI state.
-		switch {
-		case strings.HasPrefix(ev.Message, "recovering:"):
-			m.engineStatus = StatusRecovering
-			m.recoveringStartedAt = time.Now()
-			// Treat as non-persistent — we don't show this in the
-			// error bar, the status bar carries it.
-			return nil
-
-		case strings.HasPrefix(ev.Message, "healed:"):
-			// The pipeline restart succeeded. If we're still in
-			// recovering, drop back to recording. The next `status`
-			// event will reaffirm.
-			if m.engineStatus == StatusRecovering {
-				m.engineStatus = StatusRecording
-			}
-			// Refresh segment list from the DB so heal_marker columns
-			// arrive via SegmentsForRange / DB refresh paths. For now
-			// the marker is just stamped on the next segment we see —
-			// the DB read happens on topic expansion.
-			return nil
-
-		case strings.HasPrefix(ev.Message, "recovery_exhausted:"):
-			m.engineStatus = StatusError
-			m.errorMessage = ev.Message
-			m.errorTransient = false
-			m.appendErrorHistory(ev.Message)
-			if strings.Contains(ev.Message, MicOrScreenPermissionRevoked) {
-				m.permissionRevoked = true
-			}
-			return nil
-		}
-
-		// Generic error. Persist transient errors with the auto-clear,
-		// add non-transient errors to the ring buffer.
-		m.errorMessage = ev.Message
-		if ev.Transient != nil && *ev.Transient {
-			m.errorTransient = true
-			return clearTransientErrorCmd()
-		}
-		m.errorTransient = false
-		m.appendErrorHistory(ev.Message)
+	events := m.events
+	if events == nil {
+		events = stockEvents
 	}
-
-	return nil
+	return events.dispatch(m, ev)
 }
 
 // handleKey processes key presses.
@@ -999,24 +1460,55 @@ func (m *Model) handleEvent(ev daemon.Event) tea.Cmd {
 // the dismissing keypress does NOT also fire its usual binding, so the
 // user can't accidentally start a recording action while still reading
 // the banner.
+// closeClients closes the daemon connections. The event connection is
+// usually the command one, which is closed once.
+func (m Model) closeClients() {
+	if m.client != nil {
+		m.client.Close()
+	}
+	if m.evClient != nil && m.evClient != m.client {
+		m.evClient.Close()
+	}
+}
+
+// quit closes the daemon connections and ends the program; every screen's
+// q and ctrl+c come here.
+func (m Model) quit() (tea.Model, tea.Cmd) {
+	m.closeClients()
+	return m, tea.Quit
+}
+
 func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	if m.showFirstLaunchBanner {
 		// q / ctrl+c still quit so the banner can't trap the user.
 		switch msg.String() {
 		case KeyQuit, KeyQuitUpper, KeyCtrlC:
-			if m.client != nil {
-				m.client.Close()
-			}
-			if m.evClient != nil {
-				m.evClient.Close()
-			}
-			return m, tea.Quit
+			return m.quit()
 		}
 		m.showFirstLaunchBanner = false
 		writeFirstLaunchMarker()
 		return m, nil
 	}
 
+	// The end-of-session report closes on any key.
+	if m.qualityReport != nil {
+		return m.handleQualityKey(msg.String())
+	}
+
+	// The topic editor takes all keys as text until saved or cancelled.
+	if m.topicEdit != nil {
+		return m.handleTopicEditKey(msg)
+	}
+	if m.speakerEdit != nil {
+		return m.handleSpeakerEditKey(msg)
+	}
+	if m.notes != nil {
+		return m.handleNotesKey(msg)
+	}
+	if m.search != nil && m.search.editing {
+		return m.handleSearchKey(msg)
+	}
+
 	// Error modal intercepts e / esc to close.
 	if m.showErrorModal {
 		switch msg.String() {
@@ -1024,27 +1516,77 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.showErrorModal = false
 			return m, nil
 		case KeyQuit, KeyQuitUpper, KeyCtrlC:
-			if m.client != nil {
-				m.client.Close()
-			}
-			if m.evClient != nil {
-				m.evClient.Close()
-			}
-			return m, tea.Quit
+			return m.quit()
 		}
 		// Other keys are no-ops while the modal is open.
 		return m, nil
 	}
 
+	if m.keyHelp {
+		return m.handleKeyHelpKey(msg.String())
+	}
+
+	// The quick switcher takes all keys as its query. It opens from the
+	// live view, the browser and replays alike.
+	if m.switcher != nil {
+		return m.handleSwitcherKey(msg)
+	}
+	if msg.String() == KeyQuickSwitcher {
+		return m.openSwitcher()
+	}
+	if m.sessionSearch != nil {
+		return m.handleSessionSearchKey(msg)
+	}
+	if msg.String() == KeySearchSessions {
+		return m.openSessionSearch("")
+	}
+
+	if m.keywords != nil {
+		return m.handleKeywordsKey(msg.String())
+	}
+
+	if m.entityList != nil {
+		return m.handleEntitiesKey(msg.String())
+	}
+
+	if m.codes != nil {
+		return m.handleCodesKey(msg.String())
+	}
+
+	if m.searchMenu != nil {
+		return m.handleSavedSearchesKey(msg.String())
+	}
+
+	if m.exportDialog != nil {
+		return m.handleExportDialogKey(msg)
+	}
+
+	if m.recovery != nil {
+		return m.handleRecoveryKey(msg.String())
+	}
+
+	if m.replay != nil {
+		return m.handleReplayKey(msg.String())
+	}
+
+	if m.browser.open {
+		return m.handleBrowserKey(msg.String())
+	}
+
+	// n / N step through search matches, unless a speaker-names offer
+	// is waiting on y / n.
+	if m.search != nil && m.pendingSpeakerOffer() == nil {
+		switch msg.String() {
+		case KeySearchNext:
+			return m.nextMatch(1)
+		case KeySearchPrev:
+			return m.nextMatch(-1)
+		}
+	}
+
 	switch msg.String() {
 	case KeyQuit, KeyQuitUpper, KeyCtrlC:
-		if m.client != nil {
-			m.client.Close()
-		}
-		if m.evClient != nil {
-			m.evClient.Close()
-		}
-		return m, tea.Quit
+		return m.quit()
 
 	case KeySpace:
 		// U9: spacebar = atomic session demarcate, never start/stop.
@@ -1069,6 +1611,7 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if m.engineStatus == StatusPaused {
 			return m, resumeCmd(m.client)
 		}
+		m.stopRequested = true
 		return m, pauseCmd(m.client, defaultPauseAutoResumeSeconds)
 
 	case KeyPauseIndefinite:
@@ -1079,6 +1622,7 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if m.engineStatus == StatusPaused {
 			return m, resumeCmd(m.client)
 		}
+		m.stopRequested = true
 		return m, pauseIndefiniteCmd(m.client)
 
 	case KeyErrorHistory, KeyErrorHistoryUp:
@@ -1086,7 +1630,62 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.showErrorModal = !m.showErrorModal
 		return m, nil
 
+	case KeyKeywords:
+		return m.openKeywords()
+
+	case KeyEntities:
+		return m.openEntities()
+
+	case KeyCodes:
+		return m.openCodes()
+
+	case KeySpeakers:
+		return m.openSpeakerEditor()
+
+	case KeySearch:
+		return m.openSearch()
+
+	case KeyLocale:
+		return m.cycleLocale()
+
+	case KeyDictation:
+		return m.toggleDictation()
+
+	case KeyAccept, KeyDecline:
+		return m.answerSpeakerOffer(msg.String() == KeyAccept)
+
+	case KeyRecovery:
+		return m.openRecovery()
+
+	case KeyConsent:
+		return m.acknowledgeConsent()
+
+	case KeyArm:
+		return m.toggleArm()
+
+	case KeyEsc:
+		// Clear the search, then the keyword filter and return to the
+		// live tail; without either, drop the transcript cursor.
+		if m.search != nil {
+			m.search = nil
+		} else if m.filter != nil {
+			m.filter = nil
+			m.transcriptLive = true
+			m.scrollToBottom()
+		} else {
+			m.cursor = nil
+		}
+		return m, nil
+
+	case KeySessionBrowser:
+		m.browser.open = true
+		m.browser.selected = 0
+		return m, m.reloadSessionsCmd()
+
 	case "tab":
+		if m.reading {
+			return m, nil
+		}
 		if m.focusedPanel == FocusTopics {
 			m.focusedPanel = FocusTranscript
 		} else {
@@ -1100,6 +1699,9 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				m.selectedTopic++
 			}
 		}
+		if m.focusedPanel == FocusTranscript {
+			m.moveCursor(1)
+		}
 		return m, nil
 
 	case "k":
@@ -1108,6 +1710,9 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				m.selectedTopic--
 			}
 		}
+		if m.focusedPanel == FocusTranscript {
+			m.moveCursor(-1)
+		}
 		return m, nil
 
 	case "enter":
@@ -1121,6 +1726,38 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case KeyDensity:
+		m.density = m.density.next()
+		return m, nil
+
+	case KeyReading:
+		return m.toggleReading()
+
+	case KeyTicker:
+		return m.toggleTicker()
+
+	case KeyTheme:
+		return m.cycleTheme()
+
+	case KeyEditTopic:
+		return m.openTopicEditor()
+
+	case KeyNotes:
+		return m.openNotes()
+
+	case KeyHelp:
+		m.keyHelp = true
+		return m, nil
+
+	case KeyFollowUp:
+		return m.createFollowUp()
+
+	case KeyExport:
+		return m.openExportDialog()
+
+	case KeyBugReport:
+		return m, bugReportCmd(m.bugReport(), m.store, bugreport.DefaultDir())
+
 	case "s", "S":
 		m.showSummary = !m.showSummary
 		if m.showSummary && m.store != nil && m.sessionID != "" {
@@ -1128,6 +1765,12 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case KeyPageUp, KeyPageDown, KeyHome, KeyEnd, KeyJumpLive:
+		if m.focusedPanel == FocusTranscript {
+			m.pageTranscript(msg.String())
+		}
+		return m, nil
+
 	case "up":
 		if m.focusedPanel == FocusTranscript {
 			m.transcriptLive = false
@@ -1169,6 +1812,9 @@ func (m *Model) scrollToBottom() {
 
 func (m Model) maxTranscriptScroll() int {
 	totalLines := len(m.entries) + len(m.partials)
+	if m.filter != nil {
+		totalLines = m.filteredEntryCount()
+	}
 	visible := m.transcriptVisibleLines()
 	if totalLines <= visible {
 		return 0
@@ -1196,6 +1842,10 @@ func (m Model) transcriptPanelWidth() int {
 	if m.width == 0 {
 		return 60
 	}
+	if m.reading {
+		_, width := m.readingLayout()
+		return width
+	}
 	return max(30, m.width-m.topicPanelWidth()-3)
 }
 
@@ -1214,7 +1864,7 @@ func (m Model) View() string {
 	sections = append(sections, m.renderStatusBar())
 
 	// Divider
-	sections = append(sections, ui.DividerStyle.Render(strings.Repeat("─", m.width)))
+	sections = append(sections, m.renderDivider())
 
 	// First-launch consent banner (above the segment timeline, per the
 	// U9 plan section).
@@ -1222,17 +1872,56 @@ func (m Model) View() string {
 		sections = append(sections, m.renderFirstLaunchBanner())
 	}
 
-	// Main content: topics | transcript
-	sections = append(sections, m.renderMainContent())
+	// Recording-consent reminder for the current session.
+	if m.consentPending() {
+		sections = append(sections, m.renderConsentBanner())
+	}
+
+	// Main content: topics | transcript (or the transcript alone, in
+	// reading mode), a replay, the session browser, the keyword cloud,
+	// the entity list, saved searches, the export dialog, the recovery
+	// screen, the quick switcher, the search of every session, the key
+	// help or a session's quality report.
+	if m.qualityReport != nil {
+		sections = append(sections, m.renderQualityReport())
+	} else if m.keyHelp {
+		sections = append(sections, m.renderKeyHelp())
+	} else if m.switcher != nil {
+		sections = append(sections, m.renderSwitcher())
+	} else if m.sessionSearch != nil {
+		sections = append(sections, m.renderSessionSearch())
+	} else if m.keywords != nil {
+		sections = append(sections, m.renderKeywords())
+	} else if m.entityList != nil {
+		sections = append(sections, m.renderEntities())
+	} else if m.codes != nil {
+		sections = append(sections, m.renderCodes())
+	} else if m.speakerEdit != nil {
+		sections = append(sections, m.renderSpeakerEditor())
+	} else if m.searchMenu != nil {
+		sections = append(sections, m.renderSavedSearches())
+	} else if m.exportDialog != nil {
+		sections = append(sections, m.renderExportDialog())
+	} else if m.recovery != nil {
+		sections = append(sections, m.renderRecovery())
+	} else if m.replay != nil {
+		sections = append(sections, m.renderReplay())
+	} else if m.browser.open {
+		sections = append(sections, m.renderSessionBrowser())
+	} else if m.reading && m.notes == nil {
+		sections = append(sections, m.renderReadingContent())
+	} else {
+		sections = append(sections, m.renderMainContent())
+	}
 
 	// Divider
-	sections = append(sections, ui.DividerStyle.Render(strings.Repeat("─", m.width)))
+	sections = append(sections, m.renderDivider())
 
 	// Error modal (overlays the error bar — when the modal is open the
 	// per-error message is visible inside it, not duplicated below).
 	if m.showErrorModal {
 		sections = append(sections, m.renderErrorModal())
-	} else if m.errorMessage != "" {
+	} else if len(m.errorStack) > 0 {
 		sections = append(sections, m.renderErrorBar())
 	}
 
@@ -1242,6 +1931,14 @@ func (m Model) View() string {
 	return strings.Join(sections, "\n")
 }
 
+// renderDivider renders a full-width rule, red while an alert flashes.
+func (m Model) renderDivider() string {
+	if m.flashing {
+		return ui.FlashStyle.Render(strings.Repeat(" ", m.width))
+	}
+	return ui.DividerStyle.Render(strings.Repeat("─", m.width))
+}
+
 // renderFirstLaunchBanner shows the always-on consent disclosure.
 func (m Model) renderFirstLaunchBanner() string {
 	// Wrap the message to fit width. Use lipgloss border styling for
@@ -1255,18 +1952,22 @@ func (m Model) renderFirstLaunchBanner() string {
 	return ui.FirstLaunchBannerStyle.Render(body)
 }
 
-// renderErrorModal renders the U9 error-history overlay.
+// renderErrorModal renders the U9 error-history overlay: every recent
+// notification with its severity and any guidance.
 func (m Model) renderErrorModal() string {
 	if len(m.errorHistory) == 0 {
-		body := ui.DimStyle.Render("No recent errors. (Press e or esc to close.)")
+		body := ui.DimStyle.Render("No recent notifications. (Press e or esc to close.)")
 		return ui.ErrorModalStyle.Render(body)
 	}
 	var lines []string
-	lines = append(lines, ui.ErrorStyle.Render(fmt.Sprintf("Error history (%d / %d)", len(m.errorHistory), errorRingCapacity)))
+	lines = append(lines, ui.ErrorStyle.Render(fmt.Sprintf("Notifications (%d / %d)", len(m.errorHistory), errorRingCapacity)))
 	for i := len(m.errorHistory) - 1; i >= 0; i-- {
 		entry := m.errorHistory[i]
 		ts := ui.TimestampStyle.Render(entry.Timestamp.Format("[15:04:05]"))
-		lines = append(lines, ts+" "+ui.ErrorTextStyle.Render(entry.Message))
+		lines = append(lines, ts+" "+entry.Severity.label()+entry.Severity.text(entry.Message))
+		if entry.Guidance != "" {
+			lines = append(lines, ui.DimStyle.Render("           → "+entry.Guidance))
+		}
 	}
 	lines = append(lines, ui.DimStyle.Render("Press e or esc to close."))
 	return ui.ErrorModalStyle.Render(strings.Join(lines, "\n"))
@@ -1285,7 +1986,12 @@ func (m Model) renderHeader() string {
 		audioMode = ui.DimStyle.Render(" [MIC + SYS]")
 	}
 
-	return title + deviceInfo + audioMode
+	var locale string
+	if m.locale != "" {
+		locale = ui.DimStyle.Render(" · " + m.locale)
+	}
+
+	return title + deviceInfo + audioMode + locale
 }
 
 // renderStatusBar produces the U9 health-surface status bar. State
@@ -1301,9 +2007,13 @@ func (m Model) renderStatusBar() string {
 	// Level meters. Only meaningful while recording or recovering.
 	var meters string
 	if isRecording {
-		meters = renderLevelMeter("MIC", m.micLevel)
+		speaker, source := m.currentSpeaker()
+		meters = renderLevelMeter("MIC", m.micLevel, speakerIf(source == "microphone", speaker))
 		if m.systemAudio {
-			meters += "  " + renderLevelMeter("SYS", m.sysLevel)
+			meters += "  " + renderLevelMeter("SYS", m.sysLevel, speakerIf(source == "systemAudio", speaker))
+		}
+		if speaker != "" {
+			meters += "  " + speakerStyle(speaker).Render("now speaking: "+speaker)
 		}
 	}
 
@@ -1312,6 +2022,12 @@ func (m Model) renderStatusBar() string {
 	if m.modelProcessing {
 		processing = ui.SpinnerStyle.Render("⟳ AI")
 	}
+	if mark := m.pressure.indicator(); mark != "" {
+		if processing != "" {
+			processing += "  "
+		}
+		processing += mark
+	}
 
 	// Pause hint flash (Spacebar-while-paused). Sits at the bottom of
 	// the status bar but rendered inline here for compactness; clears
@@ -1319,6 +2035,14 @@ func (m Model) renderStatusBar() string {
 	var hint string
 	if m.pauseHint {
 		hint = ui.DimStyle.Render("press p to resume first")
+	} else if m.autoStartCancellable() {
+		hint = ui.LastSegWarnStyle.Render("auto-started — A to cancel")
+	} else if prompt := m.speakerOfferPrompt(); prompt != "" {
+		hint = ui.LastSegWarnStyle.Render(prompt)
+	} else if m.exporting != nil {
+		hint = ui.DimStyle.Render(m.exporting.status())
+	} else if m.notice != "" {
+		hint = ui.DimStyle.Render(m.notice)
 	}
 
 	return composeStatusBar(state, lastSeg, lastSegPriority, meters, processing, hint, m.width)
@@ -1337,8 +2061,17 @@ func (m Model) renderStatusBar() string {
 //  7. ◌ DISCONNECTED — daemon socket lost, reconnecting → reconnecting=true
 //  8. (fallback) idle / connecting…
 func (m Model) statusLabel() (label string, recordingish bool) {
+	if m.dbOnly {
+		return ui.IdleDotStyle.Render("◇ DATABASE ONLY — not connected to the daemon"), false
+	}
+	if m.tutorial != nil && m.engineStatus == StatusRecording {
+		return ui.RecordingDotStyle.Render("● TUTORIAL — a canned meeting, nothing is recorded"), true
+	}
 	// DISCONNECTED takes priority over any stale daemon-side state when
 	// the TUI is in its reconnect backoff loop.
+	if m.viewAddr != "" && (m.reconnecting || (!m.connected && m.connError != "")) {
+		return ui.DisconnectedStyle.Render("◌ DISCONNECTED — host lost, reconnecting"), false
+	}
 	if m.reconnecting || (!m.connected && m.connError != "") {
 		return ui.DisconnectedStyle.Render("◌ DISCONNECTED — daemon socket lost, reconnecting"), false
 	}
@@ -1360,7 +2093,7 @@ func (m Model) statusLabel() (label string, recordingish bool) {
 			return ui.PausedStyle.Render("⏸ PAUSED — manual resume only"), false
 		}
 		if m.pauseExpiresAt != nil {
-			remaining := time.Until(*m.pauseExpiresAt)
+			remaining := m.pauseExpiresAt.Sub(m.now())
 			if remaining < 0 {
 				remaining = 0
 			}
@@ -1371,7 +2104,7 @@ func (m Model) statusLabel() (label string, recordingish bool) {
 		return ui.PausedStyle.Render("⏸ PAUSED"), false
 
 	case StatusRecovering:
-		gap := time.Since(m.recoveringStartedAt)
+		gap := m.now().Sub(m.recoveringStartedAt)
 		if m.recoveringStartedAt.IsZero() {
 			gap = 0
 		}
@@ -1393,6 +2126,10 @@ func (m Model) statusLabel() (label string, recordingish bool) {
 		if m.recording {
 			return ui.RecordingDotStyle.Render("● REC"), true
 		}
+		if m.armed {
+			// Meters stay up: they show what the detector is hearing.
+			return ui.IdleDotStyle.Render("◉ ARMED — starts on speech"), true
+		}
 		return ui.IdleDotStyle.Render("○ IDLE"), false
 	}
 
@@ -1414,7 +2151,7 @@ func formatPauseRemaining(d time.Duration) string {
 	return fmt.Sprintf("%02d:%02d", hours, minutes)
 }
 
-// lastSegmentAnnotation returns the "last segment Ns ago" suffix and a
+// lastSegmentAnnotation returns the "last heard Ns ago" suffix and a
 // priority-tier (low / high). Empty string means don't render. Per R12
 // the annotation only shows when ≥5s and turns yellow at ≥60s while
 // not paused. Hidden entirely while paused (no audio is being captured).
@@ -1425,18 +2162,30 @@ func (m Model) lastSegmentAnnotation(isRecording bool) (text string, highPriorit
 	if m.lastSegmentAt.IsZero() {
 		return "", false
 	}
-	delta := time.Since(m.lastSegmentAt)
+	delta := m.now().Sub(m.lastSegmentAt)
 	if delta < 5*time.Second {
 		return "", false
 	}
-	secs := int(delta.Seconds())
-	label := fmt.Sprintf("last segment %ds ago", secs)
+	label := "last heard " + formatAgo(delta)
 	if isRecording && delta >= 60*time.Second {
 		return ui.LastSegWarnStyle.Render(label), true
 	}
 	return ui.DimStyle.Render(label), false
 }
 
+// formatAgo renders an elapsed time coarsely enough to read at a
+// glance: seconds for the first two minutes, then minutes, then hours.
+func formatAgo(d time.Duration) string {
+	switch {
+	case d < 2*time.Minute:
+		return fmt.Sprintf("%ds ago", int(d.Seconds()))
+	case d < 2*time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	default:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	}
+}
+
 // composeStatusBar applies the status-bar overflow policy: state label
 // is sticky; drop low-priority last-seg → level meters → spinner in
 // that order if the assembled width exceeds the terminal width.
@@ -1537,7 +2286,9 @@ func conditionalString(cond bool, s string) string {
 	return ""
 }
 
-func renderLevelMeter(label string, level float32) string {
+// renderLevelMeter draws one source's meter. A non-empty speaker tints
+// the filled cells in that speaker's color.
+func renderLevelMeter(label string, level float32, speaker string) string {
 	const barLen = 8
 	filled := int(level * barLen)
 	if filled > barLen {
@@ -1548,7 +2299,9 @@ func renderLevelMeter(label string, level float32) string {
 	for i := 0; i < barLen; i++ {
 		if i < filled {
 			pct := float32(i) / float32(barLen)
-			if pct > 0.6 {
+			if speaker != "" {
+				bar += speakerStyle(speaker).Render(speakerGlyph(speaker))
+			} else if pct > 0.6 {
 				bar += ui.LevelYellowStyle.Render("█")
 			} else {
 				bar += ui.LevelGreenStyle.Render("█")
@@ -1580,6 +2333,12 @@ func (m Model) renderMainContent() string {
 	// Join panels side by side
 	topicLines := strings.Split(topicPanel, "\n")
 	transcriptLines := strings.Split(transcriptPanel, "\n")
+	// Dim whichever panel doesn't have focus.
+	if m.focusedPanel == FocusTopics || m.notes != nil {
+		transcriptLines = ui.Unfocused(transcriptLines)
+	} else {
+		topicLines = ui.Unfocused(topicLines)
+	}
 
 	// Pad to same height
 	for len(topicLines) < contentH {
@@ -1604,20 +2363,26 @@ func (m Model) renderMainContent() string {
 
 func (m Model) renderTopicPanel(width, height int) string {
 	// Header
-	var header string
-	if m.focusedPanel == FocusTopics {
-		header = ui.PanelTitleActiveStyle.Render(fmt.Sprintf("TOPICS (%d)", len(m.topics)))
-	} else {
-		header = ui.PanelTitleStyle.Render(fmt.Sprintf("TOPICS (%d)", len(m.topics)))
+	header := padRight(ui.PanelTitle(fmt.Sprintf("TOPICS (%d)", len(m.topics)), m.focusedPanel == FocusTopics), width)
+	if m.notes != nil {
+		header = padRight(ui.PanelTitle("NOTES", true), width)
 	}
-	header = padRight(header, width)
 
 	var lines []string
 	lines = append(lines, header)
+	if m.topicsLoading && m.notes == nil && m.topicEdit == nil {
+		lines = append(lines, ui.SpinnerStyle.Render("  ⟳ Loading topics…"))
+	}
 
-	if len(m.topics) == 0 {
-		lines = append(lines, ui.DimStyle.Render("  No topics yet..."))
-		lines = append(lines, ui.DimStyle.Render("  Topics appear as you speak"))
+	if m.notes != nil {
+		lines = append(lines, m.renderNotesPane(width, height-1)...)
+	} else if m.topicEdit != nil {
+		lines = append(lines, m.renderTopicEditor(width)...)
+	} else if len(m.topics) == 0 {
+		if !m.topicsLoading {
+			lines = append(lines, ui.DimStyle.Render("  No topics yet..."))
+			lines = append(lines, ui.DimStyle.Render("  Topics appear as you speak"))
+		}
 	} else {
 		for i, topic := range m.topics {
 			isSelected := i == m.selectedTopic
@@ -1632,6 +2397,9 @@ func (m Model) renderTopicPanel(width, height int) string {
 			} else {
 				line = "  " + expandMarker + " " + topic.Title
 			}
+			if topic.UserEdited {
+				line += ui.DimStyle.Render(" ✎")
+			}
 			lines = append(lines, truncateToWidth(line, width))
 
 			if topic.Expanded {
@@ -1651,7 +2419,7 @@ func (m Model) renderTopicPanel(width, height int) string {
 							srcLabel = "SYS"
 						}
 						prefix := fmt.Sprintf("      [%s] ", srcLabel)
-						segWrapped := wrapText(seg.Text, max(10, width-len(prefix)-2))
+						segWrapped := wrapText(m.scrubber.Apply(seg.Text), max(10, width-len(prefix)-2))
 						for j, sl := range segWrapped {
 							if j == 0 {
 								lines = append(lines, ui.DimStyle.Render(prefix+sl))
@@ -1665,13 +2433,17 @@ func (m Model) renderTopicPanel(width, height int) string {
 		}
 	}
 
+	// The decisions lane sits at the bottom; topics give way above it.
+	lane := m.renderDecisionLane(width, height/3)
+
 	// Pad to height
-	for len(lines) < height {
+	for len(lines) < height-len(lane) {
 		lines = append(lines, strings.Repeat(" ", width))
 	}
-	if len(lines) > height {
-		lines = lines[:height]
+	if len(lines) > height-len(lane) {
+		lines = lines[:height-len(lane)]
 	}
+	lines = append(lines, lane...)
 
 	// Ensure each line is padded to width
 	for i, l := range lines {
@@ -1683,42 +2455,56 @@ func (m Model) renderTopicPanel(width, height int) string {
 
 func (m Model) renderTranscriptPanel(width, height int) string {
 	// Header
-	var header string
 	var badge string
 	if m.transcriptLive {
 		badge = ui.LiveBadgeStyle.Render(" LIVE")
 	} else {
-		badge = ui.ScrollBadgeStyle.Render(" SCROLL")
+		badge = ui.ScrollBadgeStyle.Render(fmt.Sprintf(" SCROLL %d%%", m.scrollPercent()))
 	}
 
 	if m.showSummary {
 		badge = ui.MagentaStyle.Render(" SUMMARY")
 	}
+	badge += m.density.badge()
+	if m.reading {
+		badge += ui.DimStyle.Render(" READING")
+	}
+	if m.scrubber != nil {
+		// Tell the presenter masking is on before they rely on it.
+		badge += ui.DimStyle.Render(" SCRUBBED")
+	}
+	if m.dictation.on {
+		badge += ui.DimStyle.Render(" DICTATION")
+	}
+	if m.filter != nil {
+		badge += ui.SearchMatchStyle.Render(fmt.Sprintf(" %q ×%d", m.filter.word, m.filteredEntryCount())) +
+			ui.DimStyle.Render(" esc clears")
+	}
+	if m.search != nil {
+		badge += m.searchBadge()
+	}
 
-	if m.focusedPanel == FocusTranscript {
-		header = ui.PanelTitleActiveStyle.Render("TRANSCRIPT") + badge
-	} else {
-		header = ui.PanelTitleStyle.Render("TRANSCRIPT") + badge
+	title := "TRANSCRIPT"
+	if m.segmentCount > 0 {
+		title = fmt.Sprintf("TRANSCRIPT (%d)", m.segmentCount)
 	}
+	header := ui.PanelTitle(title, m.focusedPanel == FocusTranscript) + badge
 
 	var lines []string
 	lines = append(lines, header)
+	if m.showTicker() {
+		lines = append(lines, m.renderTicker(width))
+	}
+	if m.showSpeakerLegend() {
+		lines = append(lines, m.renderSpeakerLegend(width))
+	}
 
-	contentHeight := height - 1 // subtract header line
+	contentHeight := height - m.transcriptHeaderLines()
 
 	// Summary view overlay
 	if m.showSummary {
 		lines = append(lines, "")
-		if m.summaryText == "" {
-			lines = append(lines, ui.DimStyle.Render("  No summary yet."))
-			lines = append(lines, ui.DimStyle.Render("  Summaries are generated as you speak."))
-		} else {
-			textWidth := max(10, width-4)
-			wrapped := wrapText(m.summaryText, textWidth)
-			for _, wl := range wrapped {
-				lines = append(lines, "  "+wl)
-			}
-		}
+		lines = append(lines, m.renderSummaries(width, height-len(lines)-2)...)
 		lines = append(lines, "")
 		lines = append(lines, ui.DimStyle.Render("  Press s to return to transcript"))
 
@@ -1735,12 +2521,31 @@ func (m Model) renderTranscriptPanel(width, height int) string {
 	}
 
 	if !m.connected {
-		if m.reconnecting {
+		if m.dbOnly {
+			lines = append(lines, "")
+			if m.connError != "" {
+				lines = append(lines, ui.ErrorStyle.Render("  "+m.connError))
+			} else {
+				lines = append(lines, ui.DimStyle.Render("  Browsing the database without the daemon."))
+				lines = append(lines, ui.DimStyle.Render("  Press b for sessions, ctrl+k to switch to one, ctrl+f to search them all."))
+			}
+		} else if m.viewAddr != "" {
+			lines = append(lines, "")
+			if m.reconnecting {
+				lines = append(lines, ui.ErrorTextStyle.Render("  Host "+m.viewAddr+" disconnected. Reconnecting..."))
+			} else {
+				lines = append(lines, ui.DimStyle.Render("  Connecting to "+m.viewAddr+"..."))
+			}
+			if m.connError != "" {
+				lines = append(lines, ui.DimStyle.Render("  "+m.connError))
+			}
+		} else if m.reconnecting {
 			lines = append(lines, "")
 			lines = append(lines, ui.ErrorTextStyle.Render("  Daemon disconnected. Reconnecting..."))
 			if m.connError != "" {
 				lines = append(lines, ui.DimStyle.Render("  "+m.connError))
 			}
+			lines = append(lines, ui.DimStyle.Render("  steno --db-only browses recorded sessions without it."))
 		} else if m.connError != "" {
 			lines = append(lines, "")
 			lines = append(lines, ui.ErrorStyle.Render("  "+m.connError))
@@ -1748,6 +2553,9 @@ func (m Model) renderTranscriptPanel(width, height int) string {
 		} else {
 			lines = append(lines, ui.DimStyle.Render("  Connecting to steno-daemon..."))
 		}
+	} else if m.filter != nil && m.filteredEntryCount() == 0 {
+		lines = append(lines, "")
+		lines = append(lines, ui.DimStyle.Render(fmt.Sprintf("  No segments mention %q.", m.filter.word)))
 	} else if len(m.entries) == 0 && len(m.partials) == 0 {
 		lines = append(lines, "")
 		// U9: always-on — no longer prompt the user to "start recording".
@@ -1755,67 +2563,8 @@ func (m Model) renderTranscriptPanel(width, height int) string {
 		lines = append(lines, ui.DimStyle.Render("  Listening… speak to see segments here."))
 		lines = append(lines, ui.DimStyle.Render("  Press space to mark a session boundary, p to pause."))
 	} else {
-		// Build display lines from entries, wrapping long text
-		// Prefix: "  [HH:MM:SS] [MIC] " = ~22 chars visible
-		prefixWidth := 22
-		textWidth := max(10, width-prefixWidth-2) // -2 for leading indent
-		indentStr := strings.Repeat(" ", prefixWidth)
-
-		var displayLines []string
-		// Width budget for the boundary rule: the transcript panel is
-		// `width` wide and the renderer indents each line by 2 spaces
-		// in the wrapping pass below. Match that so the rule sits
-		// flush with segment text.
-		boundaryWidth := max(10, width-2)
-		for _, e := range m.entries {
-			// Synthetic session-boundary marker (UI-only, inserted on a
-			// successful DemarcateResponseMsg). Rendered as a horizontal
-			// rule with a timestamp. No source, no sequence number.
-			if e.IsBoundary {
-				displayLines = append(displayLines,
-					renderSessionBoundary(e.Timestamp, boundaryWidth))
-				continue
-			}
-			// U9: heal-marker annotation — rendered on its own line
-			// BEFORE the segment so the user sees "⚠ healed after Ns
-			// gap" between two adjacent segments. Marker is keyed by
-			// the seqNum of the FIRST post-recovery segment.
-			if marker, ok := m.healMarkers[e.SeqNum]; ok && marker != "" {
-				displayLines = append(displayLines, ui.HealMarkerStyle.Render("  ⚠ "+formatHealMarker(marker)))
-			}
-			ts := ui.TimestampStyle.Render(e.Timestamp.Format("[15:04:05]"))
-			var src string
-			if e.Source == "systemAudio" {
-				src = ui.SysLabelStyle.Render("[SYS] ")
-			} else {
-				src = ui.MicLabelStyle.Render("[MIC] ")
-			}
-			wrapped := wrapText(e.Text, textWidth)
-			displayLines = append(displayLines, ts+" "+src+wrapped[0])
-			for _, wl := range wrapped[1:] {
-				displayLines = append(displayLines, indentStr+wl)
-			}
-		}
-
-		// Partial text — render each source's partial as a separate line
-		// Deterministic order: microphone first, then systemAudio
-		for _, pSource := range []string{"microphone", "systemAudio"} {
-			pText, ok := m.partials[pSource]
-			if !ok {
-				continue
-			}
-			ts := ui.TimestampStyle.Render(time.Now().Format("[15:04:05]"))
-			src := ui.PartialTextStyle.Render("[MIC] ")
-			if pSource == "systemAudio" {
-				src = ui.PartialTextStyle.Render("[SYS] ")
-			}
-			wrapped := wrapText(pText+"▌", textWidth)
-			partial := ui.PartialTextStyle.Render(wrapped[0])
-			displayLines = append(displayLines, ts+" "+src+partial)
-			for _, wl := range wrapped[1:] {
-				displayLines = append(displayLines, indentStr+ui.PartialTextStyle.Render(wl))
-			}
-		}
+		displayLines, spans := m.transcriptDisplayLines(width)
+		cursorSpan, hasCursor := spans[m.cursorIndex()]
 
 		// Apply scroll
 		start := 0
@@ -1836,7 +2585,11 @@ func (m Model) renderTranscriptPanel(width, height int) string {
 		}
 
 		for i := start; i < end; i++ {
-			lines = append(lines, "  "+displayLines[i])
+			gutter := "  "
+			if hasCursor && i >= cursorSpan[0] && i <= cursorSpan[1] {
+				gutter = ui.CursorStyle.Render("▌") + " "
+			}
+			lines = append(lines, gutter+displayLines[i])
 		}
 	}
 
@@ -1851,32 +2604,149 @@ func (m Model) renderTranscriptPanel(width, height int) string {
 	return strings.Join(lines, "\n")
 }
 
-func (m Model) renderErrorBar() string {
-	return ui.ErrorStyle.Render("Error: ") + ui.ErrorTextStyle.Render(m.errorMessage)
-}
-
-func (m Model) renderFooter() string {
-	var parts []string
-
-	if m.connected {
-		// U9: spacebar = demarcate, p / shift-p = pause toggles.
-		parts = append(parts, ui.FooterKeyStyle.Render("Space")+ui.FooterDescStyle.Render(" Boundary"))
-		if m.engineStatus == StatusPaused {
-			parts = append(parts, ui.FooterKeyStyle.Render("p/P")+ui.FooterDescStyle.Render(" Resume"))
-		} else {
-			parts = append(parts, ui.FooterKeyStyle.Render("p")+ui.FooterDescStyle.Render(" Pause 30m"))
-			parts = append(parts, ui.FooterKeyStyle.Render("P")+ui.FooterDescStyle.Render(" Pause"))
+// transcriptDisplayLines lays out the transcript entries (those the
+// filter keeps) and partials at width, before scrolling and the panel's
+// leading indent. spans maps the index in m.entries of each segment laid
+// out to its first and last line.
+func (m Model) transcriptDisplayLines(width int) (displayLines []string, spans map[int][2]int) {
+	spans = map[int][2]int{}
+	// Build display lines from entries, wrapping long text. The
+	// prefix and spacing depend on the density mode (density.go).
+	layout := m.density.layout()
+	textWidth := max(10, width-layout.prefixWidth-2) // -2 for leading indent
+	indentStr := strings.Repeat(" ", layout.prefixWidth)
+	text := func(s string) string { return s }
+	partialStyle := ui.PartialTextStyle
+	if m.density == DensityCaptions {
+		text = func(s string) string { return ui.CaptionTextStyle.Render(s) }
+		partialStyle = ui.CaptionPartialStyle
+	}
+
+	// lastSource tracks speaker turns for the comfortable / captions
+	// spacing. Reset by a boundary so the next segment starts fresh.
+	lastSource := ""
+	separate := func(source string) {
+		turn := lastSource != "" && source != lastSource
+		if (layout.turnGap && turn) || (layout.segmentGap && lastSource != "") {
+			displayLines = append(displayLines, "")
+		}
+		if layout.turnLabel && source != lastSource {
+			displayLines = append(displayLines, turnLabel(source))
+		}
+		lastSource = source
+	}
+	// Width budget for the boundary rule: the transcript panel is
+	// `width` wide and the renderer indents each line by 2 spaces
+	// in the wrapping pass below. Match that so the rule sits
+	// flush with segment text.
+	boundaryWidth := max(10, width-2)
+	rules := topicRules{starts: m.topicStarts()}
+	m.segLines.begin()
+	laidOut := 0
+	for i, e := range m.entries {
+		// Synthetic session-boundary marker (UI-only, inserted on a
+		// successful DemarcateResponseMsg). Rendered as a horizontal
+		// rule with a timestamp. No source, no sequence number.
+		if m.filter != nil && !m.filter.matches(e) {
+			continue
+		}
+		if e.IsBoundary {
+			displayLines = append(displayLines,
+				renderSessionBoundary(e.Timestamp, boundaryWidth))
+			lastSource = ""
+			continue
+		}
+		if e.SessionID == "" || e.SessionID == m.sessionID {
+			if title, ok := rules.before(e.SeqNum); ok {
+				displayLines = append(displayLines, renderTopicRule(m.scrubber.Apply(title), boundaryWidth))
+				lastSource = ""
+			}
+		}
+		separate(e.Source)
+		// U9: heal-marker annotation — rendered on its own line
+		// BEFORE the segment so the user sees "⚠ healed after Ns
+		// gap" between two adjacent segments. Marker is keyed by
+		// the seqNum of the FIRST post-recovery segment.
+		marker := m.healMarkers[e.SeqNum]
+		if marker != "" {
+			displayLines = append(displayLines, ui.HealMarkerStyle.Render("  ⚠ "+formatHealMarker(marker)))
+		}
+		key := segmentKey{
+			text: e.Text, source: e.Source, speaker: e.Speaker, timestamp: e.Timestamp, marker: marker,
+			backfill: m.backfillHighlighted(e), width: width, density: m.density,
+			scrubber: m.scrubber, filter: m.filter,
+		}
+		if m.search != nil {
+			key.search = m.search.re
+		}
+		if m.dictation.on && e.Source == "microphone" {
+			key.macros = m.dictation.macros
+		}
+		first := len(displayLines)
+		displayLines = append(displayLines, m.segLines.get(key, func() []string {
+			// A speaker tag hangs ahead of the text, so the text wraps
+			// narrower and its continuation lines indent past the tag.
+			tag := renderSpeakerTag(e.Speaker)
+			tagIndent := strings.Repeat(" ", lipgloss.Width(tag))
+			wrapped := wrapText(m.scrubber.Apply(m.dictated(e.Text, e.Source)), max(10, textWidth-len(tagIndent)))
+			for i, wl := range wrapped {
+				spans := entitySpans(wl)
+				if m.filter != nil {
+					spans = append(spans, m.filter.spans(wl)...)
+				}
+				if key.search != nil {
+					spans = append(spans, matchSpans(key.search, wl, ui.SearchMatchStyle)...)
+				}
+				wrapped[i] = renderSpans(wl, spans)
+			}
+			segText := text
+			if key.backfill {
+				segText = func(s string) string { return ui.BackfillStyle.Render(s) }
+			}
+			seg := []string{layout.prefix(e.Timestamp, e.Source, false) + tag + segText(wrapped[0])}
+			for _, wl := range wrapped[1:] {
+				seg = append(seg, indentStr+tagIndent+segText(wl))
+			}
+			return seg
+		})...)
+		spans[i] = [2]int{first, len(displayLines) - 1}
+		laidOut++
+	}
+	m.segLines.sweep(laidOut)
+
+	// Partial text — render each source's partial as a separate line
+	// Deterministic order: microphone first, then systemAudio
+	for _, pSource := range []string{"microphone", "systemAudio"} {
+		pText, ok := m.partials[pSource]
+		if !ok || m.filter != nil {
+			continue
+		}
+		separate(pSource)
+		wrapped := wrapText(m.scrubber.Apply(m.dictated(pText, pSource))+"▌", textWidth)
+		displayLines = append(displayLines, layout.prefix(m.now(), pSource, true)+partialStyle.Render(wrapped[0]))
+		for _, wl := range wrapped[1:] {
+			displayLines = append(displayLines, indentStr+partialStyle.Render(wl))
 		}
-		parts = append(parts, ui.FooterKeyStyle.Render("e")+ui.FooterDescStyle.Render(" Errors"))
-		parts = append(parts, ui.FooterKeyStyle.Render("Tab")+ui.FooterDescStyle.Render(" Focus"))
-		parts = append(parts, ui.FooterKeyStyle.Render("j/k")+ui.FooterDescStyle.Render(" Nav"))
-		parts = append(parts, ui.FooterKeyStyle.Render("↑↓")+ui.FooterDescStyle.Render(" Scroll"))
-		parts = append(parts, ui.FooterKeyStyle.Render("s")+ui.FooterDescStyle.Render(" Summary"))
 	}
 
-	parts = append(parts, ui.FooterKeyStyle.Render("q")+ui.FooterDescStyle.Render(" Quit"))
+	return displayLines, spans
+}
 
-	return strings.Join(parts, "  ")
+// renderVersion shows the TUI and daemon versions, or a warning when they
+// speak different protocol revisions or differ in major/minor version
+// (protocol fields may be missing or ignored across that gap).
+func (m Model) renderVersion() (text string, skew bool) {
+	if version.ProtocolSkew(daemon.ProtocolVersion, m.daemonProtocol) {
+		return ui.LastSegWarnStyle.Render(fmt.Sprintf("⚠ protocol %d ≠ daemon %d", daemon.ProtocolVersion, m.daemonProtocol)), true
+	}
+	if version.MinorSkew(version.Version, m.daemonVersion) {
+		return ui.LastSegWarnStyle.Render(fmt.Sprintf("⚠ steno %s ≠ daemon %s", version.Version, m.daemonVersion)), true
+	}
+	daemonV := m.daemonVersion
+	if daemonV == "" {
+		daemonV = "?"
+	}
+	return ui.DimStyle.Render(fmt.Sprintf("v%s · daemon %s", version.Version, daemonV)), false
 }
 
 // Helpers
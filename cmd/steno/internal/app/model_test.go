@@ -11,7 +11,9 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/jwulff/steno/internal/config"
 	"github.com/jwulff/steno/internal/daemon"
+	"github.com/jwulff/steno/internal/version"
 )
 
 // TestMain suppresses the first-launch banner globally for the test
@@ -209,11 +211,13 @@ func TestSegmentUsesStartedAtTimestamp(t *testing.T) {
 	}
 }
 
-func TestSegmentsInsertedInChronologicalOrder(t *testing.T) {
+func TestSegmentsOrderedBySequenceNumber(t *testing.T) {
 	m := New()
 	m.connected = true
 
-	// Simulate dual-source: sys finishes first but mic started earlier
+	// Dual-source: the mic started earlier but sys finalized first. The
+	// daemon's sequence number is the order of record (it is what the
+	// database, the web viewer and exports use), so sys stays first.
 	sysStarted := float64(1700000001) // T+1
 	micStarted := float64(1700000000) // T+0
 
@@ -225,12 +229,8 @@ func TestSegmentsInsertedInChronologicalOrder(t *testing.T) {
 	if len(m.entries) != 2 {
 		t.Fatalf("entries = %d, want 2", len(m.entries))
 	}
-	// mic should be first (earlier startedAt) despite arriving second
-	if m.entries[0].Text != "mic first" {
-		t.Errorf("entries[0].Text = %q, want %q", m.entries[0].Text, "mic first")
-	}
-	if m.entries[1].Text != "sys first" {
-		t.Errorf("entries[1].Text = %q, want %q", m.entries[1].Text, "sys first")
+	if m.entries[0].Text != "sys first" || m.entries[1].Text != "mic first" {
+		t.Errorf("entries = %q, %q; want sequence order", m.entries[0].Text, m.entries[1].Text)
 	}
 }
 
@@ -280,8 +280,8 @@ func TestErrorEvent(t *testing.T) {
 
 	cmd := m.handleEvent(ev)
 
-	if m.errorMessage != "test error" {
-		t.Errorf("errorMessage = %q", m.errorMessage)
+	if m.errorMessage() != "test error" {
+		t.Errorf("errorMessage = %q", m.errorMessage())
 	}
 	if cmd == nil {
 		t.Error("transient error should return a clear command")
@@ -782,7 +782,7 @@ func TestHealedEventClearsRecovering(t *testing.T) {
 func TestErrorRingBufferOverflow(t *testing.T) {
 	m := New()
 	for i := 0; i < 15; i++ {
-		m.appendErrorHistory(fmt.Sprintf("err %d", i))
+		m.pushError(SeverityError, fmt.Sprintf("err %d", i), false)
 	}
 	if len(m.errorHistory) != errorRingCapacity {
 		t.Fatalf("errorHistory len = %d, want %d", len(m.errorHistory), errorRingCapacity)
@@ -1390,3 +1390,47 @@ func TestTruncateToWidthPassthroughWhenWithin(t *testing.T) {
 		t.Errorf("truncateToWidth = %q, want passthrough %q", out, s)
 	}
 }
+
+// TestScrubberMasksRenderedTextOnly checks that the display-time scrub
+// masks segments and partials in the view while m.entries keeps the
+// original text.
+func TestScrubberMasksRenderedTextOnly(t *testing.T) {
+	m := NewWithConfig(config.Config{Scrub: config.ScrubConfig{Enabled: true}})
+	m.connected = true
+	m.width, m.height = 120, 24
+	m.entries = []TranscriptEntry{
+		{Text: "card is 4111 1111 1111 1111", Source: "microphone", Timestamp: time.Now(), SeqNum: 1},
+	}
+	m.partials["systemAudio"] = "token sk-abcdefghijklmnopqrstuv"
+
+	view := m.View()
+	if strings.Contains(view, "4111") || strings.Contains(view, "sk-abcdef") {
+		t.Errorf("View() leaked scrubbed text:\n%s", view)
+	}
+	if !strings.Contains(view, "[redacted]") || !strings.Contains(view, "SCRUBBED") {
+		t.Errorf("View() missing redaction marker or badge:\n%s", view)
+	}
+	if m.entries[0].Text != "card is 4111 1111 1111 1111" {
+		t.Errorf("entries mutated: %q", m.entries[0].Text)
+	}
+}
+
+func TestFooterWarnsOnDaemonVersionSkew(t *testing.T) {
+	m := New()
+	m.connected = true
+	m, _ = applyUpdate(m, VersionResponseMsg{Response: daemon.Response{OK: true, Version: version.Version}})
+	if footer := m.renderFooter(); !strings.Contains(footer, "daemon "+version.Version) || strings.Contains(footer, "⚠") {
+		t.Errorf("matching versions footer = %q", footer)
+	}
+
+	m, _ = applyUpdate(m, VersionResponseMsg{Response: daemon.Response{OK: true, Version: "99.0.0"}})
+	if footer := m.renderFooter(); !strings.HasPrefix(footer, "⚠ steno") {
+		t.Errorf("skewed footer should lead with the warning: %q", footer)
+	}
+
+	// Older daemon: "Unknown command" → version unknown, no warning.
+	m, _ = applyUpdate(m, VersionResponseMsg{Response: daemon.Response{OK: false, Error: "Unknown command: version"}})
+	if footer := m.renderFooter(); !strings.Contains(footer, "daemon ?") {
+		t.Errorf("unknown-version footer = %q", footer)
+	}
+}
@@ -0,0 +1,153 @@
+package app
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jwulff/steno/internal/db"
+	"github.com/jwulff/steno/internal/ui"
+)
+
+// notesEditor is the `N` pane: the session's freeform Markdown notes,
+// edited in place of the topic list and kept in the client-owned
+// session_notes table. Typing on an empty line stamps it with the
+// wall-clock time, so notes line up with the transcript; exports merge
+// them in alongside it.
+type notesEditor struct {
+	sessionID string
+	body      []rune
+	loaded    bool
+	err       error
+	// dirty is set by edits not yet handed to a save.
+	dirty bool
+}
+
+// loadSessionNotesCmd loads a session's notes.
+func loadSessionNotesCmd(store *db.Store, sessionID string) tea.Cmd {
+	return func() tea.Msg {
+		body, err := store.SessionNotes(sessionID)
+		return SessionNotesLoadedMsg{SessionID: sessionID, Body: body, Err: err}
+	}
+}
+
+// saveSessionNotesCmd persists a session's notes.
+func saveSessionNotesCmd(store *db.Store, sessionID, body string) tea.Cmd {
+	return func() tea.Msg {
+		return SessionNotesSavedMsg{SessionID: sessionID, Err: store.SetSessionNotes(sessionID, body)}
+	}
+}
+
+// openNotes opens the notes pane for the current session. Without a
+// store or a session there is nowhere to save, so the key does nothing.
+func (m Model) openNotes() (tea.Model, tea.Cmd) {
+	if m.store == nil || m.sessionID == "" {
+		return m, nil
+	}
+	m.notes = &notesEditor{sessionID: m.sessionID}
+	return m, loadSessionNotesCmd(m.store, m.sessionID)
+}
+
+// applySessionNotesLoaded fills the pane once the notes arrive.
+func (m Model) applySessionNotesLoaded(msg SessionNotesLoadedMsg) (tea.Model, tea.Cmd) {
+	if m.notes == nil || m.notes.sessionID != msg.SessionID {
+		return m, nil
+	}
+	e := *m.notes
+	e.body, e.err, e.loaded = []rune(msg.Body), msg.Err, true
+	m.notes = &e
+	return m, nil
+}
+
+// applySessionNotesSaved reports a failed save; the text stays in the
+// pane so nothing typed is lost.
+func (m Model) applySessionNotesSaved(msg SessionNotesSavedMsg) (tea.Model, tea.Cmd) {
+	if msg.Err != nil {
+		return m, m.pushError(SeverityError, "save notes: "+msg.Err.Error(), true)
+	}
+	return m, nil
+}
+
+// handleNotesKey handles keys while the notes pane is open: text goes
+// into the document, enter ends the line and saves, esc saves and
+// closes, ctrl+c saves and quits. Nothing is typed until the stored notes have loaded, so a
+// failed load can't overwrite them.
+func (m Model) handleNotesKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	e := *m.notes
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		if !e.dirty {
+			return m.quit()
+		}
+		m.closeClients()
+		return m, tea.Sequence(saveSessionNotesCmd(m.store, e.sessionID, string(e.body)), tea.Quit)
+	case tea.KeyEsc:
+		m.notes = nil
+		if !e.dirty {
+			return m, nil
+		}
+		return m, saveSessionNotesCmd(m.store, e.sessionID, string(e.body))
+	}
+	if !e.loaded || e.err != nil {
+		return m, nil
+	}
+	switch msg.Type {
+	case tea.KeyEnter:
+		e.body = append(e.body, '\n')
+		e.dirty = false
+		m.notes = &e
+		return m, saveSessionNotesCmd(m.store, e.sessionID, string(e.body))
+	case tea.KeyBackspace:
+		if len(e.body) > 0 {
+			e.body = e.body[:len(e.body)-1]
+			e.dirty = true
+		}
+	case tea.KeyCtrlU:
+		line := strings.LastIndexByte(string(e.body), '\n')
+		e.body = []rune(string(e.body)[:line+1])
+		e.dirty = true
+	case tea.KeySpace:
+		e.insert(m.clock, ' ')
+	case tea.KeyRunes:
+		e.insert(m.clock, msg.Runes...)
+	}
+	m.notes = &e
+	return m, nil
+}
+
+// insert types runes at the end of the document, stamping a line that
+// was empty.
+func (e *notesEditor) insert(clock Clock, r ...rune) {
+	if len(e.body) == 0 || e.body[len(e.body)-1] == '\n' {
+		e.body = append(e.body, []rune(clock.Now().Format(db.NoteStampLayout))...)
+	}
+	e.body = append(e.body, r...)
+	e.dirty = true
+}
+
+// renderNotesPane renders the pane in place of the topic list: the end
+// of the document that fits in height lines, with the cursor after it.
+func (m Model) renderNotesPane(width, height int) []string {
+	e := m.notes
+	textWidth := max(10, width-4)
+	var lines []string
+	switch {
+	case !e.loaded:
+		lines = append(lines, ui.DimStyle.Render("  Loading notes…"))
+	case e.err != nil:
+		lines = append(lines, ui.ErrorTextStyle.Render(truncateToWidth("  Couldn't load notes: "+e.err.Error(), width)))
+	default:
+		for _, line := range strings.Split(string(e.body)+"▌", "\n") {
+			for _, wl := range wrapText(line, textWidth) {
+				lines = append(lines, "  "+wl)
+			}
+		}
+	}
+	hint := "  enter new line · esc save & close"
+	if e.dirty {
+		hint = "  unsaved · " + strings.TrimSpace(hint)
+	}
+	if room := max(1, height-2); len(lines) > room {
+		lines = lines[len(lines)-room:]
+	}
+	return append(lines, "", ui.DimStyle.Render(hint))
+}
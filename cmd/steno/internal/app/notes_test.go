@@ -0,0 +1,77 @@
+package app
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestNotesPaneStampsLines(t *testing.T) {
+	c := newManualClock()
+	m := densityModel(DensityCompact).WithClock(c)
+	m.store = stubStore()
+	m.sessionID = "s1"
+	m, cmd := applyUpdate(m, runeKey('N'))
+	if m.notes == nil || cmd == nil {
+		t.Fatal("N should open the notes pane and load the notes")
+	}
+
+	// Keys wait for the stored notes.
+	m, _ = applyUpdate(m, runeKey('x'))
+	m, _ = applyUpdate(m, SessionNotesLoadedMsg{SessionID: "s1", Body: "[13:55:00] Agenda\n"})
+	if got := string(m.notes.body); got != "[13:55:00] Agenda\n" {
+		t.Fatalf("body = %q, want the loaded notes untouched", got)
+	}
+
+	for _, r := range "Ship it" {
+		m, _ = applyUpdate(m, runeKey(r))
+	}
+	m, cmd = applyUpdate(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil || m.notes.dirty {
+		t.Error("enter should save the notes")
+	}
+	c.advance(90 * time.Second)
+	m, _ = applyUpdate(m, runeKey('Q'))
+	want := "[13:55:00] Agenda\n[14:00:00] Ship it\n[14:01:30] Q"
+	if got := string(m.notes.body); got != want {
+		t.Errorf("body = %q, want %q", got, want)
+	}
+	if !strings.Contains(m.View(), "NOTES") || !strings.Contains(m.View(), "[14:01:30] Q▌") {
+		t.Errorf("view should show the notes pane:\n%s", m.View())
+	}
+
+	m, cmd = applyUpdate(m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.notes != nil || cmd == nil {
+		t.Error("esc should save unsaved notes and close the pane")
+	}
+}
+
+func TestNotesNeedAStore(t *testing.T) {
+	m := New()
+	m.sessionID = "s1"
+	if m, cmd := applyUpdate(m, runeKey('N')); m.notes != nil || cmd != nil {
+		t.Error("without a store N should do nothing")
+	}
+}
+
+func TestNotesCtrlCSavesBeforeQuitting(t *testing.T) {
+	m := testModel(t, withSession("s1"))
+	m, _ = applyUpdate(m, runeKey('N'))
+	m, _ = applyUpdate(m, SessionNotesLoadedMsg{SessionID: "s1"})
+
+	_, cmd := applyUpdate(m, tea.KeyMsg{Type: tea.KeyCtrlC})
+	if _, ok := cmd().(tea.QuitMsg); !ok {
+		t.Error("ctrl+c with nothing unsaved should quit")
+	}
+
+	m, _ = applyUpdate(m, runeKey('x'))
+	_, cmd = applyUpdate(m, tea.KeyMsg{Type: tea.KeyCtrlC})
+	if cmd == nil {
+		t.Fatal("ctrl+c should save unsaved notes before quitting")
+	}
+	if _, ok := cmd().(tea.QuitMsg); ok {
+		t.Error("ctrl+c should save unsaved notes before quitting")
+	}
+}
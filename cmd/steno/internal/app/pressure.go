@@ -0,0 +1,69 @@
+package app
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jwulff/steno/internal/daemon"
+	"github.com/jwulff/steno/internal/ui"
+)
+
+// resourcePressure is the daemon's report of thermal and power pressure,
+// from `status` and `event:"pressure"`. GPU and Neural Engine load
+// aren't visible to the daemon; the thermal state rises with them.
+type resourcePressure struct {
+	thermalState string // "nominal", "fair", "serious", "critical"; empty when unknown
+	lowPower     bool
+	// summariesDeferred is true while the daemon holds rolling summaries
+	// back because of the pressure (its deferSummaries setting).
+	summariesDeferred bool
+}
+
+// apply takes the pressure fields of a status response or event. Older
+// daemons send none of them, which leaves p as it was.
+func (p *resourcePressure) apply(thermalState string, lowPower, summariesDeferred *bool) {
+	if thermalState != "" {
+		p.thermalState = thermalState
+	}
+	if lowPower != nil {
+		p.lowPower = *lowPower
+	}
+	if summariesDeferred != nil {
+		p.summariesDeferred = *summariesDeferred
+	}
+}
+
+func onPressure(m *Model, ev daemon.Event) tea.Cmd {
+	m.pressure.apply(ev.ThermalState, ev.LowPower, ev.SummariesDeferred)
+	return nil
+}
+
+// indicator is the status bar's pressure mark: empty while the machine
+// is comfortable, dim while it's warm or in Low Power Mode, and noting
+// when summaries are waiting for it to cool.
+func (p resourcePressure) indicator() string {
+	var label string
+	switch p.thermalState {
+	case "serious":
+		label = "♨ hot"
+	case "critical":
+		label = "♨ very hot"
+	}
+	if p.lowPower {
+		if label != "" {
+			label += " · "
+		}
+		label += "low power"
+	}
+	if p.summariesDeferred {
+		if label == "" {
+			label = "♨"
+		}
+		label += " · AI deferred"
+	}
+	if label == "" {
+		return ""
+	}
+	if p.thermalState == "critical" {
+		return ui.LastSegWarnStyle.Render(label)
+	}
+	return ui.DimStyle.Render(label)
+}
@@ -0,0 +1,51 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jwulff/steno/internal/daemon"
+)
+
+func TestPressureIndicator(t *testing.T) {
+	m := New()
+	m.width = 200
+	m, _ = applyUpdate(m, StatusResponseMsg{Response: daemon.Response{OK: true, ThermalState: "nominal", LowPower: daemon.BoolPtr(false)}})
+	if strings.Contains(m.renderStatusBar(), "♨") {
+		t.Fatalf("bar = %q, a comfortable machine shows no mark", m.renderStatusBar())
+	}
+
+	runCmd(m.handleEvent(daemon.Event{Event: "pressure", ThermalState: "serious", LowPower: daemon.BoolPtr(false), SummariesDeferred: daemon.BoolPtr(true)}))
+	if bar := m.renderStatusBar(); !strings.Contains(bar, "♨ hot · AI deferred") {
+		t.Errorf("bar = %q, want the hot mark with summaries deferred", bar)
+	}
+
+	// A status from a daemon without pressure reporting keeps the last
+	// report.
+	m, _ = applyUpdate(m, StatusResponseMsg{Response: daemon.Response{OK: true}})
+	if m.pressure.thermalState != "serious" || !m.pressure.summariesDeferred {
+		t.Errorf("pressure = %+v, want the event's kept", m.pressure)
+	}
+
+	runCmd(m.handleEvent(daemon.Event{Event: "pressure", ThermalState: "fair", LowPower: daemon.BoolPtr(false), SummariesDeferred: daemon.BoolPtr(false)}))
+	if strings.Contains(m.renderStatusBar(), "♨") {
+		t.Errorf("bar = %q, the mark should clear as the machine cools", m.renderStatusBar())
+	}
+}
+
+func TestPressureIndicatorLabels(t *testing.T) {
+	for _, tc := range []struct {
+		p    resourcePressure
+		want string
+	}{
+		{resourcePressure{thermalState: "fair"}, ""},
+		{resourcePressure{thermalState: "critical"}, "♨ very hot"},
+		{resourcePressure{thermalState: "nominal", lowPower: true}, "low power"},
+		{resourcePressure{thermalState: "serious", lowPower: true}, "♨ hot · low power"},
+		{resourcePressure{thermalState: "nominal", lowPower: true, summariesDeferred: true}, "low power · AI deferred"},
+	} {
+		if got := tc.p.indicator(); got != tc.want {
+			t.Errorf("%+v: indicator = %q, want %q", tc.p, got, tc.want)
+		}
+	}
+}
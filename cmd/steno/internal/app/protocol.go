@@ -0,0 +1,28 @@
+package app
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jwulff/steno/internal/daemon"
+	"github.com/jwulff/steno/internal/version"
+)
+
+// handleVersion records the daemon's version and protocol revision from
+// `version`. A daemon speaking another protocol is still used: the
+// client decodes leniently, so a newer daemon's unknown fields are
+// ignored, and an older one just lacks what came after it. The footer
+// flags the difference, and the notifications drawer says which side to
+// upgrade, once per daemon rather than on every reconnect.
+func (m Model) handleVersion(msg VersionResponseMsg) (tea.Model, tea.Cmd) {
+	prev := m.daemonProtocol
+	m.daemonVersion, m.daemonBuild, m.daemonProtocol = "", "", 0
+	if msg.Err != nil || !msg.Response.OK {
+		return m, nil
+	}
+	m.daemonVersion = msg.Response.Version
+	m.daemonBuild = msg.Response.Build
+	m.daemonProtocol = msg.Response.ProtocolVersion
+	if m.daemonProtocol == prev || !version.ProtocolSkew(daemon.ProtocolVersion, m.daemonProtocol) {
+		return m, nil
+	}
+	return m, m.pushError(SeverityWarn, version.ProtocolWarning(daemon.ProtocolVersion, m.daemonProtocol), false)
+}
@@ -0,0 +1,36 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jwulff/steno/internal/daemon"
+)
+
+func TestVersionProtocolSkew(t *testing.T) {
+	m := New()
+	m, _ = applyUpdate(m, VersionResponseMsg{Response: daemon.Response{OK: true, Version: "0.1.0", ProtocolVersion: daemon.ProtocolVersion}})
+	if _, skew := m.renderVersion(); skew || len(m.errorHistory) != 0 {
+		t.Errorf("matching protocols: skew %v, errors %v", skew, m.errorHistory)
+	}
+
+	newer := daemon.Response{OK: true, Version: "0.1.0", ProtocolVersion: daemon.ProtocolVersion + 1}
+	m, _ = applyUpdate(m, VersionResponseMsg{Response: newer})
+	if text, skew := m.renderVersion(); !skew || !strings.Contains(text, "protocol") {
+		t.Errorf("footer = %q, want a protocol warning", text)
+	}
+	if len(m.errorHistory) != 1 || !strings.Contains(m.errorHistory[0].Message, "newer than this steno's") {
+		t.Fatalf("errors = %v, want the upgrade hint", m.errorHistory)
+	}
+	// Reconnecting to the same daemon doesn't warn again.
+	m, _ = applyUpdate(m, VersionResponseMsg{Response: newer})
+	if len(m.errorHistory) != 1 {
+		t.Errorf("errors after reconnecting = %v", m.errorHistory)
+	}
+
+	// A daemon that predates versioning is unknown, not skewed.
+	m, _ = applyUpdate(m, VersionResponseMsg{Response: daemon.Response{OK: true, Version: "0.1.0"}})
+	if _, skew := m.renderVersion(); skew {
+		t.Error("an unversioned daemon shouldn't be flagged")
+	}
+}
@@ -0,0 +1,127 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jwulff/steno/internal/db"
+	"github.com/jwulff/steno/internal/quality"
+	"github.com/jwulff/steno/internal/ui"
+)
+
+// qualityScreen is the end-of-session report (see internal/quality),
+// shown in place of the main panels when a session ends until any key
+// is pressed.
+type qualityScreen struct {
+	sessionID string
+	report    db.SessionQuality
+	loaded    bool
+	err       error
+}
+
+// qualityMonitor returns the gap monitor for the current session,
+// starting a fresh one when the session changed.
+func (m *Model) qualityMonitor() *quality.Monitor {
+	if m.qualityFor != m.sessionID {
+		m.qualityFor = m.sessionID
+		m.monitor = quality.Monitor{}
+	}
+	return &m.monitor
+}
+
+// endSessionQuality opens the report screen for sessionID, which just
+// ended, and computes and stores its report. Nil without a store.
+func (m *Model) endSessionQuality(sessionID string) tea.Cmd {
+	if m.store == nil || sessionID == "" {
+		return nil
+	}
+	var gaps []quality.Gap
+	if m.qualityFor == sessionID {
+		gaps = m.monitor.Gaps()
+	}
+	m.qualityReport = &qualityScreen{sessionID: sessionID}
+	return sessionQualityCmd(m.store, sessionID, gaps, m.now())
+}
+
+// sessionQualityCmd computes a session's report and stores it for `steno
+// quality`. Sessions without segments aren't stored. Read-only stores
+// reject the write; the report is still shown.
+func sessionQualityCmd(store *db.Store, sessionID string, gaps []quality.Gap, now time.Time) tea.Cmd {
+	return func() tea.Msg {
+		report, err := quality.ForSession(store, sessionID, gaps, now)
+		if err == nil && report.Segments > 0 {
+			_ = store.SaveSessionQuality(report)
+		}
+		return SessionQualityMsg{SessionID: sessionID, Report: report, Err: err}
+	}
+}
+
+// applySessionQuality fills the report screen, or closes it when the
+// session recorded nothing worth reporting on.
+func (m *Model) applySessionQuality(msg SessionQualityMsg) {
+	if m.qualityReport == nil || m.qualityReport.sessionID != msg.SessionID {
+		return
+	}
+	if msg.Err == nil && msg.Report.Segments == 0 {
+		m.qualityReport = nil
+		return
+	}
+	m.qualityReport = &qualityScreen{sessionID: msg.SessionID, report: msg.Report, loaded: true, err: msg.Err}
+}
+
+// handleQualityKey closes the report screen on any key but quit.
+func (m Model) handleQualityKey(key string) (tea.Model, tea.Cmd) {
+	switch key {
+	case KeyQuit, KeyQuitUpper, KeyCtrlC:
+		return m.quit()
+	}
+	m.qualityReport = nil
+	return m, nil
+}
+
+// renderQualityReport renders the report screen in place of the main
+// panels.
+func (m Model) renderQualityReport() string {
+	s := m.qualityReport
+	q := s.report
+	width := max(20, m.width-6)
+	lines := []string{ui.PanelTitleActiveStyle.Render("Session quality")}
+	switch {
+	case !s.loaded:
+		lines = append(lines, ui.DimStyle.Render("Checking the session that just ended…"))
+	case s.err != nil:
+		lines = append(lines, ui.ErrorTextStyle.Render("Couldn't compute the report: "+s.err.Error()))
+	default:
+		lines = append(lines, ui.DimStyle.Render(fmt.Sprintf("%d segments over %v", q.Segments, q.Duration.Round(time.Second))), "")
+		row := func(label, value string, bad bool) {
+			if bad {
+				value = ui.WarnStyle.Render(value)
+			}
+			lines = append(lines, truncateToWidth(fmt.Sprintf("%-16s %s", label, value), width))
+		}
+		row("Low confidence", fmt.Sprintf("%d segments (%.0f%%)", q.LowConfidence, q.LowConfidencePercent()), q.LowConfidence > 0)
+		row("Clipping", fmt.Sprintf("%d mic segments at %.0f dBFS or louder", q.Clipped, quality.ClipDB), q.Clipped > 0)
+		row("Untranscribed", fmt.Sprintf("%d stretches of audio, %v", q.Gaps, q.GapTime.Round(time.Second)), q.Gaps > 0)
+		row("Device", qualityDevice(q), false)
+	}
+	lines = append(lines, "", ui.DimStyle.Render("steno quality for trends · any key to continue"))
+	return ui.SessionBrowserStyle.Render(strings.Join(lines, "\n"))
+}
+
+// qualityDevice describes a report's input device and system audio.
+func qualityDevice(q db.SessionQuality) string {
+	device := q.Device
+	if device == "" {
+		device = "unknown"
+	}
+	switch {
+	case q.SystemAudio == nil:
+		return device
+	case *q.SystemAudio:
+		return device + " · system audio on"
+	default:
+		return device + " · system audio off"
+	}
+}
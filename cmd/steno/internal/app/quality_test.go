@@ -0,0 +1,107 @@
+package app
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jwulff/steno/internal/config"
+	"github.com/jwulff/steno/internal/daemon"
+	"github.com/jwulff/steno/internal/db"
+)
+
+func TestQualityReportAfterDemarcate(t *testing.T) {
+	m, _, c := alertModel(config.AlertsConfig{})
+	m.width, m.height = 120, 30
+	m.store = stubStore()
+	m.sessionID = "s1"
+
+	// 12s of speech-level audio without a transcript, then some the ASR
+	// kept up with.
+	loud := float32(0.3)
+	for i := 0; i <= 120; i++ {
+		m.handleEvent(daemon.Event{Event: "level", Mic: &loud})
+		c.advance(100 * time.Millisecond)
+	}
+	m.handleEvent(daemon.Event{Event: "partial", Text: "and so", Source: "microphone"})
+	m.handleEvent(daemon.Event{Event: "level", Mic: &loud})
+	if gaps := m.monitor.Gaps(); len(gaps) != 1 || gaps[0].Duration() != 12*time.Second {
+		t.Fatalf("gaps = %+v, want one of 12s", gaps)
+	}
+
+	m, cmd := applyUpdate(m, DemarcateResponseMsg{Response: daemon.Response{OK: true, SessionID: "s2"}})
+	if m.qualityReport == nil || m.qualityReport.sessionID != "s1" || cmd == nil {
+		t.Fatal("demarcate should open the old session's report and compute it")
+	}
+	if !strings.Contains(m.View(), "Checking the session") {
+		t.Error("report should show it's loading")
+	}
+
+	on := true
+	m, _ = applyUpdate(m, SessionQualityMsg{SessionID: "s1", Report: db.SessionQuality{
+		SessionID: "s1", Segments: 40, LowConfidence: 4, Clipped: 2, Gaps: 1, GapTime: 12 * time.Second,
+		Duration: 10 * time.Minute, Device: "USB Mic", SystemAudio: &on,
+	}})
+	view := m.View()
+	for _, want := range []string{"40 segments over 10m0s", "4 segments (10%)", "2 mic segments", "1 stretches of audio, 12s", "USB Mic · system audio on"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view missing %q:\n%s", want, view)
+		}
+	}
+
+	// Any key closes it, and does nothing else.
+	m, _ = applyUpdate(m, runeKey('b'))
+	if m.qualityReport != nil || m.browser.open {
+		t.Error("a key should only close the report")
+	}
+	m.handleEvent(daemon.Event{Event: "level", Mic: &loud})
+	if m.qualityFor != "s2" || len(m.monitor.Gaps()) != 0 {
+		t.Error("the new session should get a fresh monitor")
+	}
+}
+
+func TestQualityReportSkipsEmptySessions(t *testing.T) {
+	m := testModel(t, withSession("s1"))
+
+	m, _ = applyUpdate(m, DemarcateResponseMsg{Response: daemon.Response{OK: true, SessionID: "s2"}})
+	m, _ = applyUpdate(m, SessionQualityMsg{SessionID: "s1", Report: db.SessionQuality{SessionID: "s1"}})
+	if m.qualityReport != nil {
+		t.Error("a session without segments shouldn't get a report")
+	}
+
+	m, _ = applyUpdate(m, DemarcateResponseMsg{Response: daemon.Response{OK: true, SessionID: "s3"}})
+	m, _ = applyUpdate(m, SessionQualityMsg{SessionID: "s2", Err: errors.New("disk gone")})
+	if !strings.Contains(m.View(), "disk gone") {
+		t.Error("report should show the error")
+	}
+	if _, cmd := applyUpdate(m, tea.KeyMsg{Type: tea.KeyCtrlC}); cmd == nil {
+		t.Error("ctrl+c should still quit")
+	}
+}
+
+func TestQualityReportWhenSessionEndsElsewhere(t *testing.T) {
+	m := testModel(t, withSession("s1"))
+	m.handleEvent(segmentEvent("s1", 1, "before"))
+
+	// Another client demarcated: the next segment names a new session.
+	m.handleEvent(segmentEvent("s2", 1, "after"))
+	if m.qualityReport == nil || m.qualityReport.sessionID != "s1" || m.sessionID != "s2" {
+		t.Fatalf("report = %+v, session %q, want s1's report and s2 current", m.qualityReport, m.sessionID)
+	}
+	m.qualityReport = nil
+
+	// A replayed segment of the old session isn't another change.
+	m.handleEvent(segmentEvent("s1", 2, "late"))
+	if m.qualityReport != nil || m.sessionID != "s2" {
+		t.Errorf("report = %+v, session %q, want s2 still current", m.qualityReport, m.sessionID)
+	}
+
+	// A status fetch naming another session, e.g. after a reconnect,
+	// ends s2 too.
+	m, _ = applyUpdate(m, StatusResponseMsg{Response: daemon.Response{OK: true, SessionID: "s3"}})
+	if m.qualityReport == nil || m.qualityReport.sessionID != "s2" {
+		t.Errorf("report = %+v, want s2's after the status", m.qualityReport)
+	}
+}
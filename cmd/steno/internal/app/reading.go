@@ -0,0 +1,47 @@
+package app
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Reading mode (R, config display.reading_mode) is for going back over
+// a meeting once it's done: the topics panel steps aside and the
+// transcript is capped at a comfortable line length, centered with wide
+// margins, rather than stretched across an ultrawide terminal.
+const (
+	// readingWidth is the widest the transcript panel gets, gutter and
+	// timestamps included.
+	readingWidth = 90
+	// readingMargin is the least space left either side.
+	readingMargin = 4
+)
+
+// toggleReading turns reading mode on or off. The transcript takes
+// focus, since the topics panel is hidden.
+func (m Model) toggleReading() (tea.Model, tea.Cmd) {
+	m.reading = !m.reading
+	if m.reading {
+		m.focusedPanel = FocusTranscript
+	}
+	return m, nil
+}
+
+// readingLayout is the transcript panel's left margin and width in
+// reading mode.
+func (m Model) readingLayout() (margin, width int) {
+	width = max(30, min(readingWidth, m.width-2*readingMargin))
+	return max(0, (m.width-width)/2), width
+}
+
+// renderReadingContent renders the transcript panel alone, centered.
+func (m Model) renderReadingContent() string {
+	margin, width := m.readingLayout()
+	pad := strings.Repeat(" ", margin)
+	lines := strings.Split(m.renderTranscriptPanel(width, m.transcriptVisibleLines()), "\n")
+	for i, l := range lines {
+		lines[i] = pad + l
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,77 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/jwulff/steno/internal/config"
+)
+
+func TestReadingModeCentersTranscript(t *testing.T) {
+	m := densityModel(DensityNormal)
+	m.width, m.height = 240, 30
+	m.entries[0].Text = strings.Repeat("a long line about the rollout plan ", 10)
+	m.focusedPanel = FocusTopics
+	m, _ = applyUpdate(m, runeKey('R'))
+	if !m.reading || m.focusedPanel != FocusTranscript {
+		t.Fatalf("reading = %v, focus = %v; want reading mode with the transcript focused", m.reading, m.focusedPanel)
+	}
+
+	content := m.renderReadingContent()
+	if strings.Contains(content, "TOPICS") {
+		t.Error("reading mode shows the topics panel")
+	}
+	margin, width := m.readingLayout()
+	if width != readingWidth || margin != (240-readingWidth)/2 {
+		t.Errorf("layout = margin %d width %d, want centered at %d", margin, width, readingWidth)
+	}
+	for _, l := range strings.Split(content, "\n") {
+		if strings.TrimSpace(l) == "" {
+			continue
+		}
+		if !strings.HasPrefix(l, strings.Repeat(" ", margin)) {
+			t.Errorf("line %q isn't indented by the margin", l)
+		}
+		if w := ansi.StringWidth(l); w > margin+width {
+			t.Errorf("line %q is %d wide, want at most %d", l, w, margin+width)
+		}
+	}
+	if !strings.Contains(m.View(), "READING") {
+		t.Error("header doesn't show the READING badge")
+	}
+
+	m, _ = applyUpdate(m, runeKey('R'))
+	if m.reading {
+		t.Error("R didn't leave reading mode")
+	}
+}
+
+func TestReadingModeNarrowTerminal(t *testing.T) {
+	m := densityModel(DensityNormal)
+	m.width = 60
+	m.reading = true
+	if margin, width := m.readingLayout(); margin != readingMargin || width != 60-2*readingMargin {
+		t.Errorf("layout = margin %d width %d, want the minimum margins", margin, width)
+	}
+	if got := m.transcriptPanelWidth(); got != 60-2*readingMargin {
+		t.Errorf("transcriptPanelWidth = %d, want the reading width", got)
+	}
+}
+
+func TestReadingModeTabKeepsTranscriptFocus(t *testing.T) {
+	m := densityModel(DensityNormal)
+	m.reading = true
+	m, _ = applyUpdate(m, tea.KeyMsg{Type: tea.KeyTab})
+	if m.focusedPanel != FocusTranscript {
+		t.Error("tab focused the hidden topics panel")
+	}
+}
+
+func TestReadingModeFromConfig(t *testing.T) {
+	m := NewWithConfig(config.Config{Display: config.DisplayConfig{ReadingMode: true}})
+	if !m.reading {
+		t.Error("display.reading_mode didn't start in reading mode")
+	}
+}
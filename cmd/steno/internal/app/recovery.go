@@ -0,0 +1,266 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jwulff/steno/internal/db"
+	"github.com/jwulff/steno/internal/ui"
+)
+
+// recoveryTail is how many of a stranded session's last segments the
+// recovery screen shows.
+const recoveryTail = 3
+
+// Recovery actions.
+const (
+	recoveryFinalize = "finalize"
+	recoveryResume   = "resume"
+	recoveryDiscard  = "discard"
+)
+
+// recoveryScreen is the `I` overlay: sessions still marked active that
+// the daemon isn't recording, left behind by a crash or force quit.
+// Each can be finalized (closed as interrupted, keeping what was
+// captured), finalized and resumed in a new session, or discarded.
+type recoveryScreen struct {
+	sessions []db.StrandedSession
+	// drafts are, by session, the unfinished words a crash cut off (see
+	// drafts.go).
+	drafts   map[string][]partialDraft
+	selected int
+	loaded   bool
+	err      error
+	// confirm is set after the first x, which must be pressed again.
+	confirm bool
+}
+
+// daemonSession is the session the daemon may be writing to, which is
+// never offered for recovery. While disconnected the last one seen is
+// kept out too: the daemon may still be recording it.
+func (m Model) daemonSession() string {
+	if m.connected && m.idle() {
+		return ""
+	}
+	return m.sessionID
+}
+
+// loadRecoveryCmd finds the stranded sessions, and which of drafts hold
+// words they lost. check marks the one-time lookup made on connecting,
+// which only mentions what it found.
+func loadRecoveryCmd(store *db.Store, recording string, check bool, drafts []partialDraft) tea.Cmd {
+	return func() tea.Msg {
+		sessions, err := store.StrandedSessions(recording, recoveryTail)
+		var found map[string][]partialDraft
+		if err == nil {
+			found, err = unfinalizedDrafts(store, sessions, drafts)
+		}
+		return RecoveryLoadedMsg{Sessions: sessions, Drafts: found, Err: err, Check: check}
+	}
+}
+
+// recoveryCheckCmd looks for stranded sessions once, after the first
+// status response says which session the daemon is on. Nil until then,
+// without a store, or once it has run.
+func (m *Model) recoveryCheckCmd() tea.Cmd {
+	if m.store == nil || !m.connected || m.recoveryChecked {
+		return nil
+	}
+	m.recoveryChecked = true
+	return loadRecoveryCmd(m.store, m.daemonSession(), true, m.drafts.kept)
+}
+
+func (m Model) openRecovery() (tea.Model, tea.Cmd) {
+	m.recovery = &recoveryScreen{}
+	if m.store == nil {
+		return m, nil
+	}
+	return m, loadRecoveryCmd(m.store, m.daemonSession(), false, m.drafts.kept)
+}
+
+// applyRecoveryLoaded fills the recovery screen, or after the check on
+// connecting notes how many sessions need attention.
+func (m Model) applyRecoveryLoaded(msg RecoveryLoadedMsg) (tea.Model, tea.Cmd) {
+	var prune tea.Cmd
+	if msg.Err == nil {
+		prune = m.pruneDrafts(msg.Drafts)
+	}
+	if msg.Check {
+		if msg.Err != nil || len(msg.Sessions) == 0 {
+			return m, prune
+		}
+		noun := "sessions were"
+		if len(msg.Sessions) == 1 {
+			noun = "session was"
+		}
+		m.notice = fmt.Sprintf("%d %s left active by a crash · I to recover", len(msg.Sessions), noun)
+		if len(msg.Drafts) > 0 {
+			m.notice = fmt.Sprintf("%d %s left active by a crash, with unfinished words · I to recover", len(msg.Sessions), noun)
+		}
+		return m, tea.Batch(m.clearNoticeCmd(), prune)
+	}
+	if m.recovery == nil {
+		return m, prune
+	}
+	s := *m.recovery
+	s.sessions, s.drafts, s.err, s.loaded, s.confirm = msg.Sessions, msg.Drafts, msg.Err, true, false
+	s.selected = min(s.selected, max(0, len(s.sessions)-1))
+	m.recovery = &s
+	return m, prune
+}
+
+// recoveryActionCmd finalizes or discards sessionID through a short-lived
+// maintenance handle: the TUI's own store must treat daemon-owned tables
+// as read-only.
+func recoveryActionCmd(action, sessionID string, opts db.Options) tea.Cmd {
+	return func() tea.Msg {
+		store, err := db.OpenMaintenance(storePath(), opts)
+		if err != nil {
+			return RecoveryActionMsg{Action: action, SessionID: sessionID, Err: err}
+		}
+		defer store.Close()
+		if action == recoveryDiscard {
+			err = store.DiscardSession(sessionID)
+		} else {
+			err = store.FinalizeSession(sessionID)
+		}
+		return RecoveryActionMsg{Action: action, SessionID: sessionID, Err: err}
+	}
+}
+
+// applyRecoveryAction reports an action and reloads the list. Resuming
+// asks an idle daemon to start a new session; one already recording has
+// one.
+func (m Model) applyRecoveryAction(msg RecoveryActionMsg) (tea.Model, tea.Cmd) {
+	if msg.Err != nil {
+		return m, m.pushError(SeverityWarn, "Couldn't "+msg.Action+" session "+msg.SessionID+": "+msg.Err.Error(), true)
+	}
+	var cmds []tea.Cmd
+	switch msg.Action {
+	case recoveryDiscard:
+		m.notice = "Discarded session " + msg.SessionID
+	case recoveryResume:
+		m.notice = "Finalized session " + msg.SessionID
+		if m.client != nil && m.connected && m.idle() {
+			m.notice += ", starting a new one"
+			cmds = append(cmds, startCmd(m.client, m.startOptions(m.deviceName, m.systemAudio)))
+		}
+	default:
+		m.notice = "Finalized session " + msg.SessionID + " as interrupted"
+	}
+	cmds = append(cmds, m.clearNoticeCmd())
+	if m.recovery != nil && m.store != nil {
+		cmds = append(cmds, loadRecoveryCmd(m.store, m.daemonSession(), false, m.drafts.kept))
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// handleRecoveryKey handles keys while the recovery screen is open:
+// up/down move, f finalizes, n finalizes and resumes in a new session,
+// x twice discards, esc or I closes.
+func (m Model) handleRecoveryKey(key string) (tea.Model, tea.Cmd) {
+	s := *m.recovery
+	confirm := s.confirm
+	s.confirm = false
+	switch key {
+	case KeyQuit, KeyQuitUpper, KeyCtrlC:
+		return m.quit()
+	case KeyEsc, KeyRecovery:
+		m.recovery = nil
+		return m, nil
+	case KeyUp, KeyK:
+		if s.selected > 0 {
+			s.selected--
+		}
+	case KeyDown, KeyJ:
+		if s.selected < len(s.sessions)-1 {
+			s.selected++
+		}
+	case KeyRecoverFinalize, KeyRecoverResume, KeyRecoverDiscard:
+		if s.selected >= len(s.sessions) {
+			return m, nil
+		}
+		id := s.sessions[s.selected].Session.ID
+		switch {
+		case key == KeyRecoverFinalize:
+			return m, recoveryActionCmd(recoveryFinalize, id, m.dbOptions)
+		case key == KeyRecoverResume:
+			return m, recoveryActionCmd(recoveryResume, id, m.dbOptions)
+		case confirm:
+			m.recovery = &s
+			return m, recoveryActionCmd(recoveryDiscard, id, m.dbOptions)
+		}
+		s.confirm = true
+	}
+	m.recovery = &s
+	return m, nil
+}
+
+// renderRecovery renders the recovery screen in place of the main
+// panels: the stranded sessions, and the selected one's last segments.
+func (m Model) renderRecovery() string {
+	s := m.recovery
+	width := max(20, m.width-6)
+	lines := []string{ui.PanelTitleActiveStyle.Render("Interrupted sessions")}
+	switch {
+	case m.store == nil:
+		lines = append(lines, ui.DimStyle.Render("No database available yet."))
+	case !s.loaded:
+		lines = append(lines, ui.DimStyle.Render("Looking for sessions left active…"))
+	case s.err != nil:
+		lines = append(lines, ui.ErrorTextStyle.Render("Couldn't load sessions: "+s.err.Error()))
+	case len(s.sessions) == 0:
+		lines = append(lines, ui.DimStyle.Render("None. Every session the daemon isn't recording was closed properly."))
+	}
+
+	for i, st := range s.sessions {
+		title := st.Session.Title
+		if title == "" {
+			title = st.Session.ID
+		}
+		detail := fmt.Sprintf("started %s · %d segments", st.Session.StartedAt.Local().Format("Jan 2 15:04"), st.Segments)
+		if st.LastSegmentAt != nil {
+			detail += " · last at " + st.LastSegmentAt.Local().Format("15:04")
+		}
+		line := ui.Marker(false) + title + "  " + ui.DimStyle.Render(detail)
+		if i == s.selected {
+			line = ui.SelectedStyle.Render(ui.Marker(true)+title) + "  " + ui.DimStyle.Render(detail)
+		}
+		lines = append(lines, truncateToWidth(line, width))
+	}
+
+	if s.selected < len(s.sessions) {
+		st := s.sessions[s.selected]
+		lines = append(lines, "")
+		if len(st.Tail) == 0 {
+			lines = append(lines, ui.DimStyle.Render("Nothing was captured."))
+		} else {
+			lines = append(lines, ui.DimStyle.Render("Last captured:"))
+			for _, seg := range st.Tail {
+				text := fmt.Sprintf("  %s %s", seg.StartedAt.Local().Format("15:04:05"), m.scrubber.Apply(seg.Text))
+				lines = append(lines, truncateToWidth(text, width))
+			}
+		}
+		if drafts := s.drafts[st.Session.ID]; len(drafts) > 0 {
+			lines = append(lines, ui.DimStyle.Render("Unfinished when it stopped:"))
+			for _, d := range drafts {
+				label := "[MIC]"
+				if d.Source == "systemAudio" {
+					label = "[SYS]"
+				}
+				text := fmt.Sprintf("  %s %s %s…", d.At.Local().Format("15:04:05"), label, m.scrubber.Apply(d.Text))
+				lines = append(lines, truncateToWidth(ui.PartialTextStyle.Render(text), width))
+			}
+		}
+	}
+
+	hint := "↑/↓ move · f finalize · n resume as new · x discard · esc close"
+	if s.confirm {
+		hint = ui.WarnStyle.Render("x again to delete this session and everything in it") + ui.DimStyle.Render(" · any other key keeps it")
+	} else {
+		hint = ui.DimStyle.Render(hint)
+	}
+	lines = append(lines, "", hint)
+	return ui.SessionBrowserStyle.Render(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,104 @@
+package app
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jwulff/steno/internal/daemon"
+	"github.com/jwulff/steno/internal/db"
+)
+
+func strandedFixture() []db.StrandedSession {
+	start := time.Date(2026, 3, 2, 9, 30, 0, 0, time.Local)
+	last := start.Add(20 * time.Minute)
+	return []db.StrandedSession{
+		{
+			Session:       db.Session{ID: "s-crashed", Title: "Design review", StartedAt: start, Status: "active"},
+			Segments:      42,
+			LastSegmentAt: &last,
+			Tail:          []db.Segment{{Text: "so the last thing I said was", StartedAt: last.Add(-5 * time.Second)}},
+		},
+		{Session: db.Session{ID: "s-empty", StartedAt: start.Add(-time.Hour), Status: "active"}},
+	}
+}
+
+func TestRecoveryCheckedOnceOnConnect(t *testing.T) {
+	m := testModel(t)
+	m, cmd := applyUpdate(m, StatusResponseMsg{Response: daemon.Response{OK: true, SessionID: "s-live", Status: "recording", Recording: daemon.BoolPtr(true)}})
+	if !m.recoveryChecked || cmd == nil {
+		t.Fatal("the first status response should look for stranded sessions")
+	}
+	if got := m.daemonSession(); got != "s-live" {
+		t.Errorf("daemonSession = %q, want the recording session kept out", got)
+	}
+	if m.recoveryCheckCmd() != nil {
+		t.Error("the check should run once")
+	}
+
+	m, _ = applyUpdate(m, RecoveryLoadedMsg{Sessions: strandedFixture(), Check: true})
+	if m.recovery != nil || !strings.Contains(m.notice, "2 sessions were left active") {
+		t.Errorf("notice = %q, recovery = %v; want a note and no screen", m.notice, m.recovery)
+	}
+}
+
+func TestDaemonSessionIdle(t *testing.T) {
+	m := New()
+	m.sessionID = "s-old"
+	if got := m.daemonSession(); got != "s-old" {
+		t.Errorf("disconnected daemonSession = %q, want the last session seen kept out", got)
+	}
+	m.connected = true
+	m.engineStatus = StatusIdle
+	if got := m.daemonSession(); got != "" {
+		t.Errorf("idle daemonSession = %q, want none", got)
+	}
+}
+
+func TestRecoveryScreenActions(t *testing.T) {
+	m := testModel(t)
+	m, cmd := applyUpdate(m, runeKey('I'))
+	if m.recovery == nil || cmd == nil {
+		t.Fatal("I should open the recovery screen and load it")
+	}
+	m, _ = applyUpdate(m, RecoveryLoadedMsg{Sessions: strandedFixture()})
+	view := m.View()
+	for _, want := range []string{"Interrupted sessions", "Design review", "42 segments", "last at 09:50", "so the last thing I said was", "s-empty"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view missing %q:\n%s", want, view)
+		}
+	}
+
+	// Discarding takes a second x; anything else cancels it.
+	m, cmd = applyUpdate(m, runeKey('x'))
+	if cmd != nil || !m.recovery.confirm || !strings.Contains(m.View(), "x again") {
+		t.Fatal("the first x should ask for confirmation")
+	}
+	m, _ = applyUpdate(m, runeKey('j'))
+	if m.recovery.confirm || m.recovery.selected != 1 {
+		t.Error("moving should cancel the confirmation")
+	}
+	m, _ = applyUpdate(m, runeKey('k'))
+	m, _ = applyUpdate(m, runeKey('x'))
+	if _, cmd = applyUpdate(m, runeKey('x')); cmd == nil {
+		t.Error("a second x should discard")
+	}
+	if _, cmd = applyUpdate(m, runeKey('f')); cmd == nil {
+		t.Error("f should finalize")
+	}
+
+	m, cmd = applyUpdate(m, RecoveryActionMsg{Action: recoveryFinalize, SessionID: "s-crashed"})
+	if !strings.Contains(m.notice, "Finalized session s-crashed") || cmd == nil {
+		t.Errorf("notice = %q; want the finalize noted and the list reloaded", m.notice)
+	}
+	m, _ = applyUpdate(m, RecoveryActionMsg{Action: recoveryDiscard, SessionID: "s-empty", Err: errors.New("database is locked")})
+	if !strings.Contains(m.errorMessage(), "database is locked") {
+		t.Error("a failed action should be reported")
+	}
+
+	m, _ = applyUpdate(m, runeKey('I'))
+	if m.recovery != nil {
+		t.Error("I should close the recovery screen")
+	}
+}
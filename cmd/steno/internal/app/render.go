@@ -0,0 +1,89 @@
+package app
+
+import (
+	"regexp"
+	"time"
+
+	"github.com/jwulff/steno/internal/dictation"
+	"github.com/jwulff/steno/internal/scrub"
+)
+
+// segmentLines caches the laid-out lines of finalized transcript
+// segments between renders. Most events (a partial growing, a level
+// meter tick, the clock) leave every segment as it was, so the
+// transcript panel only lays out what's dirty: the partial lines and
+// any segment whose text or display settings changed. Entries are keyed
+// by everything their lines depend on rather than by position, so
+// merges, edits and backfills need no explicit invalidation.
+//
+// Model is copied on every Update; the cache is shared by pointer, and
+// like the rest of the render path assumes the single Bubble Tea
+// goroutine.
+type segmentLines struct {
+	frame uint64
+	lines map[segmentKey]cachedSegment
+}
+
+// segmentKey is everything one segment's lines depend on.
+type segmentKey struct {
+	text      string
+	source    string
+	speaker   string
+	timestamp time.Time
+	marker    string
+	backfill  bool
+	width     int
+	density   Density
+	scrubber  *scrub.Scrubber
+	// macros is nil unless dictation rewrites this segment.
+	macros *dictation.Macros
+	filter *transcriptFilter
+	search *regexp.Regexp
+}
+
+type cachedSegment struct {
+	lines []string
+	// frame is the last layout that used these lines.
+	frame uint64
+}
+
+func newSegmentLines() *segmentLines {
+	return &segmentLines{lines: make(map[segmentKey]cachedSegment)}
+}
+
+// begin starts a layout pass.
+func (c *segmentLines) begin() {
+	if c != nil {
+		c.frame++
+	}
+}
+
+// get returns key's lines, laying them out with build on a miss. A nil
+// cache always builds.
+func (c *segmentLines) get(key segmentKey, build func() []string) []string {
+	if c == nil {
+		return build()
+	}
+	if s, ok := c.lines[key]; ok {
+		s.frame = c.frame
+		c.lines[key] = s
+		return s.lines
+	}
+	lines := build()
+	c.lines[key] = cachedSegment{lines: lines, frame: c.frame}
+	return lines
+}
+
+// sweep ends a layout pass that used n segments, dropping those it
+// didn't use once they outnumber the live ones, so a resize or a
+// density change doesn't keep the old layout around.
+func (c *segmentLines) sweep(n int) {
+	if c == nil || len(c.lines) <= 2*n+64 {
+		return
+	}
+	for k, s := range c.lines {
+		if s.frame != c.frame {
+			delete(c.lines, k)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package app
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSegmentLinesReusedAcrossPartials(t *testing.T) {
+	m := densityModel(DensityCompact)
+	want := transcriptRows(t, m)
+	if len(m.segLines.lines) != 3 {
+		t.Fatalf("cached segments = %d, want 3", len(m.segLines.lines))
+	}
+
+	// Poison the cache: a render that re-laid out the segments wouldn't
+	// show this.
+	for k, s := range m.segLines.lines {
+		s.lines = []string{"cached " + k.text}
+		m.segLines.lines[k] = s
+	}
+	m.partials["microphone"] = "and then"
+	rows := transcriptRows(t, m)
+	if rows[0] != "  cached first from mic" {
+		t.Errorf("rows[0] = %q, want the cached lines", rows[0])
+	}
+	if !strings.Contains(rows[len(rows)-1], "and then") {
+		t.Errorf("last row = %q, want the partial", rows[len(rows)-1])
+	}
+
+	// A fresh cache lays out the same lines as before.
+	m.segLines = newSegmentLines()
+	delete(m.partials, "microphone")
+	if got := transcriptRows(t, m); !slices.Equal(got, want) {
+		t.Errorf("rows = %q, want %q", got, want)
+	}
+}
+
+func TestSegmentLinesInvalidatedByChanges(t *testing.T) {
+	m := densityModel(DensityCompact)
+	transcriptRows(t, m)
+
+	m.entries[1].Text = "second from mic, merged with more"
+	if rows := transcriptRows(t, m); !strings.Contains(rows[1], "merged with more") {
+		t.Errorf("rows[1] = %q, want the edited text", rows[1])
+	}
+
+	m.density = DensityComfortable
+	if rows := transcriptRows(t, m); !strings.Contains(rows[0], "[14:05:09]") {
+		t.Errorf("rows[0] = %q, want the comfortable layout", rows[0])
+	}
+
+	m.filter = newTranscriptFilter("reply")
+	rows := transcriptRows(t, m)
+	if len(rows) != 1 || !strings.Contains(rows[0], m.filter.highlight("reply")) {
+		t.Errorf("filtered rows = %q, want the highlighted match only", rows)
+	}
+}
+
+func TestSegmentLinesSweepsStaleLayouts(t *testing.T) {
+	m := densityModel(DensityCompact)
+	for w := 60; w < 160; w++ {
+		m.width = w
+		transcriptRows(t, m)
+	}
+	if n := len(m.segLines.lines); n > 2*len(m.entries)+64 {
+		t.Errorf("cached segments = %d after resizing, want stale widths dropped", n)
+	}
+}
+
+func TestSegmentLinesNilCache(t *testing.T) {
+	m := densityModel(DensityCompact)
+	want := transcriptRows(t, m)
+	m.segLines = nil
+	if got := transcriptRows(t, m); !slices.Equal(got, want) {
+		t.Errorf("rows = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,352 @@
+package app
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jwulff/steno/internal/daemon"
+	"github.com/jwulff/steno/internal/db"
+	"github.com/jwulff/steno/internal/ui"
+)
+
+const (
+	// replaySegmentLimit caps how many segments a replay loads.
+	replaySegmentLimit = 20000
+	// replayTickInterval is the wall-clock redraw rate during replay.
+	replayTickInterval = 100 * time.Millisecond
+	// replaySeekStep is how far ← / → move the playhead.
+	replaySeekStep = 30 * time.Second
+)
+
+// replaySpeedMax plays as fast as the tick allows: one segment per tick,
+// no partials.
+const replaySpeedMax = 0
+
+// replaySpeedLabel formats a speed for the replay header.
+func replaySpeedLabel(speed float64) string {
+	if speed == replaySpeedMax {
+		return "max"
+	}
+	return fmt.Sprintf("%gx", speed)
+}
+
+// replaySession is a recorded session played back through the regular
+// transcript renderer: segments are fed to a private Model as synthetic
+// `partial` / `segment` events at their recorded offsets, scaled by the
+// playback speed, so density, scrubbing and wrapping all behave exactly
+// as they do live.
+type replaySession struct {
+	sessionID string
+	title     string
+	segments  []db.Segment // sequence order
+	origin    time.Time    // first segment's start; offset zero
+
+	pos      time.Duration // playhead, relative to origin
+	speed    float64       // 1, 2, 4 or replaySpeedMax
+	paused   bool
+	next     int       // index of the first segment not yet finalized
+	lastTick time.Time // wall clock of the previous tick; zero after a pause
+
+	// gen tags the tick chain so reopening a replay can't leave two
+	// chains advancing the same playhead.
+	gen int
+	// fromBrowser sends esc back to the session browser rather than the
+	// live view (replays opened from the quick switcher).
+	fromBrowser bool
+
+	view Model
+	// into, when set, receives the synthetic events in place of view:
+	// the tutorial plays its canned session through the live transcript.
+	into *Model
+}
+
+// target is the Model the synthetic events are fed to.
+func (r *replaySession) target() *Model {
+	if r.into != nil {
+		return r.into
+	}
+	return &r.view
+}
+
+func newReplaySession(m Model, msg ReplayLoadedMsg, gen int) *replaySession {
+	r := &replaySession{
+		sessionID: msg.SessionID,
+		title:     msg.Title,
+		segments:  msg.Segments,
+		speed:     1,
+		gen:       gen,
+	}
+	if len(r.segments) > 0 {
+		r.origin = r.segments[0].StartedAt
+		for _, seg := range r.segments {
+			if seg.StartedAt.Before(r.origin) {
+				r.origin = seg.StartedAt
+			}
+		}
+	}
+	r.reset(m)
+	return r
+}
+
+// reset clears the private transcript view and rewinds to offset zero.
+func (r *replaySession) reset(m Model) {
+	r.view = Model{
+		scrubber:       m.scrubber,
+		density:        m.density,
+		connected:      true,
+		transcriptLive: true,
+		focusedPanel:   FocusTranscript,
+		partials:       make(map[string]string),
+		healMarkers:    make(map[int]string),
+	}
+	r.pos = 0
+	r.next = 0
+}
+
+// duration is the offset of the last segment's end.
+func (r *replaySession) duration() time.Duration {
+	var d time.Duration
+	for _, seg := range r.segments {
+		d = max(d, seg.EndedAt.Sub(r.origin))
+	}
+	return d
+}
+
+func (r *replaySession) offset(t time.Time) time.Duration {
+	return t.Sub(r.origin)
+}
+
+// seek moves the playhead to `to`, clamped to the recording. Seeking
+// backwards replays from the start, since the view only appends.
+func (r *replaySession) seek(m Model, to time.Duration) {
+	to = min(max(to, 0), r.duration())
+	if to < r.pos {
+		r.reset(m)
+	}
+	for r.next < len(r.segments) && r.offset(r.segments[r.next].EndedAt) <= to {
+		r.finalize(r.segments[r.next])
+		r.next++
+	}
+	r.pos = to
+	r.updatePartials()
+}
+
+// step advances one segment at max speed.
+func (r *replaySession) step() {
+	if r.next >= len(r.segments) {
+		return
+	}
+	seg := r.segments[r.next]
+	r.finalize(seg)
+	r.next++
+	r.pos = max(r.pos, r.offset(seg.EndedAt))
+	r.updatePartials()
+}
+
+func (r *replaySession) finalize(seg db.Segment) {
+	seq := seg.SequenceNumber
+	startedAt := float64(seg.StartedAt.UnixNano()) / 1e9
+	r.target().handleEvent(daemon.Event{
+		Event:          "segment",
+		Text:           seg.Text,
+		Source:         seg.Source,
+		Speaker:        seg.Speaker,
+		SequenceNumber: &seq,
+		StartedAt:      &startedAt,
+	})
+}
+
+// updatePartials shows, per source, the in-progress segment's words
+// revealed in proportion to how far the playhead is through it.
+func (r *replaySession) updatePartials() {
+	for _, source := range []string{"microphone", "systemAudio"} {
+		text := ""
+		if r.speed != replaySpeedMax {
+			for _, seg := range r.segments[r.next:] {
+				if r.offset(seg.StartedAt) > r.pos {
+					break
+				}
+				if seg.Source == source {
+					text = partialWords(seg.Text, r.pos-r.offset(seg.StartedAt), seg.EndedAt.Sub(seg.StartedAt))
+					break
+				}
+			}
+		}
+		r.target().handleEvent(daemon.Event{Event: "partial", Source: source, Text: text})
+	}
+}
+
+// partialWords returns the leading words of text spoken `elapsed` into a
+// segment of length `total`, assuming an even speaking rate.
+func partialWords(text string, elapsed, total time.Duration) string {
+	words := strings.Fields(text)
+	if len(words) == 0 || total <= 0 {
+		return ""
+	}
+	n := int(math.Ceil(float64(len(words)) * float64(elapsed) / float64(total)))
+	n = min(max(n, 0), len(words))
+	return strings.Join(words[:n], " ")
+}
+
+// tick advances the playhead by the wall-clock time since the last tick,
+// scaled by speed. Playback pauses itself at the end.
+func (r *replaySession) tick(m Model, now time.Time) {
+	if r.paused {
+		return
+	}
+	if r.speed == replaySpeedMax {
+		r.step()
+	} else if !r.lastTick.IsZero() {
+		elapsed := now.Sub(r.lastTick)
+		r.seek(m, r.pos+time.Duration(float64(elapsed)*r.speed))
+	}
+	r.lastTick = now
+	if r.next >= len(r.segments) && r.pos >= r.duration() {
+		r.paused = true
+		r.lastTick = time.Time{}
+	}
+}
+
+// loadReplayCmd reads a session's segments for replay.
+func loadReplayCmd(store *db.Store, sessionID, title string) tea.Cmd {
+	return loadReplayAtCmd(store, sessionID, title, 0)
+}
+
+// loadReplayAtCmd reads a session's segments for a replay that starts
+// at segment seq (from the start when 0).
+func loadReplayAtCmd(store *db.Store, sessionID, title string, seq int) tea.Cmd {
+	return func() tea.Msg {
+		segs, err := store.SegmentsAfter(sessionID, 0, replaySegmentLimit)
+		return ReplayLoadedMsg{SessionID: sessionID, Title: title, Segments: segs, Seq: seq, Err: err}
+	}
+}
+
+func (m Model) replayTickCmd(gen int) tea.Cmd {
+	return m.tick(replayTickInterval, func(t time.Time) tea.Msg {
+		return ReplayTickMsg{Gen: gen, At: t}
+	})
+}
+
+// openReplay starts playback of a loaded session.
+func (m Model) openReplay(msg ReplayLoadedMsg) (tea.Model, tea.Cmd) {
+	if msg.Err != nil {
+		return m, m.pushError(SeverityError, "replay: "+msg.Err.Error(), true)
+	}
+	m.replayGen++
+	m.replay = newReplaySession(m, msg, m.replayGen)
+	for _, seg := range msg.Segments {
+		if msg.Seq > 0 && seg.SequenceNumber == msg.Seq {
+			m.replay.seek(m, m.replay.offset(seg.EndedAt))
+			break
+		}
+	}
+	m.replay.fromBrowser = m.browser.open
+	m.browser.open = false
+	return m, m.replayTickCmd(m.replayGen)
+}
+
+// handleReplayTick advances the playhead. Ticks from a closed or
+// superseded replay end their chain.
+func (m Model) handleReplayTick(msg ReplayTickMsg) (tea.Model, tea.Cmd) {
+	if m.replay == nil || msg.Gen != m.replay.gen {
+		return m, nil
+	}
+	r := *m.replay
+	r.tick(m, msg.At)
+	m.replay = &r
+	return m, m.replayTickCmd(r.gen)
+}
+
+// handleReplayKey handles keys while a replay is open.
+func (m Model) handleReplayKey(key string) (tea.Model, tea.Cmd) {
+	r := *m.replay
+	switch key {
+	case KeyEsc:
+		// Back to where the replay was opened from.
+		m.replay = nil
+		m.browser.open = r.fromBrowser
+		return m, nil
+	case KeyQuit, KeyQuitUpper, KeyCtrlC:
+		return m.quit()
+	case KeySpace:
+		if r.paused && r.next >= len(r.segments) {
+			// Play again from the top once finished.
+			r.seek(m, 0)
+		}
+		r.paused = !r.paused
+		r.lastTick = time.Time{}
+	case KeyReplaySpeed1:
+		r.speed = 1
+	case KeyReplaySpeed2:
+		r.speed = 2
+	case KeyReplaySpeed4:
+		r.speed = 4
+	case KeyReplaySpeedMax:
+		r.speed = replaySpeedMax
+	case KeyLeft:
+		r.seek(m, r.pos-replaySeekStep)
+	case KeyRight:
+		r.seek(m, r.pos+replaySeekStep)
+	case KeyDensity:
+		m.density = m.density.next()
+	default:
+		// Other keys are no-ops during replay.
+		return m, nil
+	}
+	r.updatePartials()
+	m.replay = &r
+	return m, nil
+}
+
+// renderReplay renders an open replay in place of the main content
+// panels: a playhead line, the transcript, and the replay keys.
+func (m Model) renderReplay() string {
+	r := m.replay
+	contentH := m.transcriptVisibleLines()
+
+	state := "▶"
+	if r.paused {
+		state = "⏸"
+	}
+	title := r.title
+	if title == "" {
+		title = "(untitled)"
+	}
+	head := ui.PanelTitleActiveStyle.Render("REPLAY") + " " +
+		fmt.Sprintf("%s %s  %s / %s  %s", state, replaySpeedLabel(r.speed),
+			formatReplayOffset(r.pos), formatReplayOffset(r.duration()), title)
+
+	view := r.view
+	view.density = m.density
+	view.width = m.width
+	// Partials are stamped with the playhead's recorded time, not now.
+	view.clock = playheadClock{r.origin.Add(r.pos)}
+	panel := view.renderTranscriptPanel(m.width, max(2, contentH-2))
+
+	hint := ui.DimStyle.Render("space pause · 1/2/4/0 speed (0 = max) · ←/→ seek 30s · v density · esc back")
+	return strings.Join([]string{truncateToWidth(head, m.width), panel, hint}, "\n")
+}
+
+// playheadClock reads as a replay's recorded time at the playhead.
+type playheadClock struct{ at time.Time }
+
+func (c playheadClock) Now() time.Time { return c.at }
+
+func (c playheadClock) Tick(d time.Duration, fn func(time.Time) tea.Msg) tea.Cmd {
+	return tea.Tick(d, fn)
+}
+
+// formatReplayOffset renders a playhead offset as h:mm:ss or m:ss.
+func formatReplayOffset(d time.Duration) string {
+	d = d.Truncate(time.Second)
+	h := int(d / time.Hour)
+	mnt := int(d/time.Minute) % 60
+	s := int(d/time.Second) % 60
+	if h > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", h, mnt, s)
+	}
+	return fmt.Sprintf("%d:%02d", mnt, s)
+}
@@ -0,0 +1,191 @@
+package app
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jwulff/steno/internal/db"
+)
+
+// replayFixture is a 30s recording: a mic segment over 0-10s, a
+// system-audio segment over 12-20s, and a mic segment over 22-30s.
+func replayFixture() ReplayLoadedMsg {
+	base := time.Unix(1710000000, 0)
+	seg := func(seq int, text, source string, from, to int) db.Segment {
+		return db.Segment{
+			Text:           text,
+			Source:         source,
+			SequenceNumber: seq,
+			StartedAt:      base.Add(time.Duration(from) * time.Second),
+			EndedAt:        base.Add(time.Duration(to) * time.Second),
+		}
+	}
+	return ReplayLoadedMsg{
+		SessionID: "s1",
+		Title:     "Design review",
+		Segments: []db.Segment{
+			seg(1, "one two three four", "microphone", 0, 10),
+			seg(2, "remote side talking", "systemAudio", 12, 20),
+			seg(3, "wrapping up now", "microphone", 22, 30),
+		},
+	}
+}
+
+func openTestReplay(t *testing.T) Model {
+	t.Helper()
+	m := New()
+	m.width, m.height = 120, 30
+	m.browser.open = true
+	updated, cmd := m.Update(replayFixture())
+	if cmd == nil {
+		t.Fatal("opening a replay should schedule a tick")
+	}
+	got := updated.(Model)
+	if got.replay == nil || got.browser.open {
+		t.Fatal("replay should open in place of the browser")
+	}
+	return got
+}
+
+func replayTick(m Model, at time.Time) Model {
+	updated, _ := m.Update(ReplayTickMsg{Gen: m.replay.gen, At: at})
+	return updated.(Model)
+}
+
+func TestReplayAnimatesPartials(t *testing.T) {
+	m := openTestReplay(t)
+	now := time.Unix(0, 0)
+	m = replayTick(m, now) // first tick only anchors the wall clock
+	m = replayTick(m, now.Add(5*time.Second))
+
+	if m.replay.pos != 5*time.Second {
+		t.Fatalf("pos = %v, want 5s", m.replay.pos)
+	}
+	if got := m.replay.view.partials["microphone"]; got != "one two" {
+		t.Errorf("mic partial halfway = %q, want %q", got, "one two")
+	}
+	if len(m.replay.view.entries) != 0 {
+		t.Errorf("no segment should be final yet, got %d", len(m.replay.view.entries))
+	}
+
+	m = replayTick(m, now.Add(14*time.Second))
+	if len(m.replay.view.entries) != 1 || m.replay.view.entries[0].Text != "one two three four" {
+		t.Errorf("entries = %+v, want segment 1 final", m.replay.view.entries)
+	}
+	if _, ok := m.replay.view.partials["microphone"]; ok {
+		t.Error("mic partial should clear once its segment is final")
+	}
+	if got := m.replay.view.partials["systemAudio"]; got != "remote" {
+		t.Errorf("sys partial = %q, want %q", got, "remote")
+	}
+}
+
+func TestReplaySpeedScalesPlayhead(t *testing.T) {
+	m := openTestReplay(t)
+	updated, _ := m.Update(runeKey('4'))
+	m = updated.(Model)
+
+	now := time.Unix(0, 0)
+	m = replayTick(m, now)
+	m = replayTick(m, now.Add(time.Second))
+	if m.replay.pos != 4*time.Second {
+		t.Errorf("pos at 4x after 1s = %v, want 4s", m.replay.pos)
+	}
+	if !strings.Contains(m.View(), "4x") {
+		t.Error("header should show the speed")
+	}
+}
+
+func TestReplayPauseHoldsPlayhead(t *testing.T) {
+	m := openTestReplay(t)
+	now := time.Unix(0, 0)
+	m = replayTick(m, now)
+	m = replayTick(m, now.Add(2*time.Second))
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeySpace})
+	m = updated.(Model)
+	m = replayTick(m, now.Add(10*time.Second))
+	if m.replay.pos != 2*time.Second {
+		t.Errorf("pos while paused = %v, want 2s", m.replay.pos)
+	}
+
+	// Resuming doesn't count the paused time.
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeySpace})
+	m = updated.(Model)
+	m = replayTick(m, now.Add(20*time.Second))
+	m = replayTick(m, now.Add(21*time.Second))
+	if m.replay.pos != 3*time.Second {
+		t.Errorf("pos after resume = %v, want 3s", m.replay.pos)
+	}
+}
+
+func TestReplayMaxSpeedStepsSegments(t *testing.T) {
+	m := openTestReplay(t)
+	updated, _ := m.Update(runeKey('0'))
+	m = updated.(Model)
+
+	now := time.Unix(0, 0)
+	for i := 1; i <= 3; i++ {
+		m = replayTick(m, now.Add(time.Duration(i)*time.Millisecond))
+		if len(m.replay.view.entries) != i {
+			t.Fatalf("after %d ticks: %d entries", i, len(m.replay.view.entries))
+		}
+		if len(m.replay.view.partials) != 0 {
+			t.Errorf("max speed should show no partials, got %v", m.replay.view.partials)
+		}
+	}
+	if !m.replay.paused {
+		t.Error("replay should pause itself at the end")
+	}
+}
+
+func TestReplaySeek(t *testing.T) {
+	m := openTestReplay(t)
+	right := tea.KeyMsg{Type: tea.KeyRight}
+	left := tea.KeyMsg{Type: tea.KeyLeft}
+
+	updated, _ := m.Update(right)
+	m = updated.(Model)
+	if m.replay.pos != 30*time.Second || len(m.replay.view.entries) != 3 {
+		t.Errorf("seek past the end: pos %v, %d entries", m.replay.pos, len(m.replay.view.entries))
+	}
+
+	updated, _ = m.Update(left)
+	m = updated.(Model)
+	if m.replay.pos != 0 || len(m.replay.view.entries) != 0 {
+		t.Errorf("seek back to start: pos %v, %d entries", m.replay.pos, len(m.replay.view.entries))
+	}
+}
+
+func TestReplayEscReturnsToBrowser(t *testing.T) {
+	m := openTestReplay(t)
+	gen := m.replay.gen
+
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+	if m.replay != nil || !m.browser.open {
+		t.Fatal("esc should close the replay and reopen the browser")
+	}
+	if _, cmd := m.Update(ReplayTickMsg{Gen: gen}); cmd != nil {
+		t.Error("a closed replay's tick should end its chain")
+	}
+}
+
+func TestPartialWords(t *testing.T) {
+	for _, tc := range []struct {
+		elapsed time.Duration
+		want    string
+	}{
+		{0, ""},
+		{time.Second, "a"},
+		{2 * time.Second, "a b"},
+		{4 * time.Second, "a b c d"},
+		{5 * time.Second, "a b c d"},
+	} {
+		if got := partialWords("a b c d", tc.elapsed, 4*time.Second); got != tc.want {
+			t.Errorf("partialWords(%v) = %q, want %q", tc.elapsed, got, tc.want)
+		}
+	}
+}
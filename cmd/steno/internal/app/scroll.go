@@ -0,0 +1,42 @@
+package app
+
+// pageTranscript handles the transcript paging keys. A page is one
+// screenful less a line, so the last line of one page stays in view at
+// the top of the next. Reaching the bottom, End, and G all resume the
+// live tail; anything else freezes the view where it lands. G also
+// brings the transcript cursor, if any, to the newest segment.
+func (m *Model) pageTranscript(key string) {
+	page := max(1, m.transcriptVisibleLines()-1)
+	maxScroll := m.maxTranscriptScroll()
+	if m.transcriptLive {
+		// The tail may have grown since the last scrollToBottom.
+		m.transcriptScroll = maxScroll
+	}
+	switch key {
+	case KeyPageUp:
+		m.transcriptLive = false
+		m.transcriptScroll = max(0, m.transcriptScroll-page)
+	case KeyPageDown:
+		m.transcriptScroll = min(maxScroll, m.transcriptScroll+page)
+		m.transcriptLive = m.transcriptScroll == maxScroll
+	case KeyHome:
+		m.transcriptLive = false
+		m.transcriptScroll = 0
+	case KeyEnd, KeyJumpLive:
+		m.transcriptLive = true
+		m.scrollToBottom()
+		if key == KeyJumpLive {
+			m.cursorToNewest()
+		}
+	}
+}
+
+// scrollPercent is how far down the transcript the view sits while
+// scrolled back: 0 at the top, 100 at the live tail.
+func (m Model) scrollPercent() int {
+	maxScroll := m.maxTranscriptScroll()
+	if maxScroll == 0 || m.transcriptScroll >= maxScroll {
+		return 100
+	}
+	return m.transcriptScroll * 100 / maxScroll
+}
@@ -0,0 +1,89 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// scrollModel has 100 one-line segments in a 20-line transcript (height
+// 28 leaves 20 visible), so maxTranscriptScroll is 80 and a page is 19.
+func scrollModel() Model {
+	m := New()
+	m.width, m.height = 120, 28
+	for i := range 100 {
+		m.entries = append(m.entries, TranscriptEntry{Text: fmt.Sprintf("line %d", i), Source: "microphone", Timestamp: time.Now(), SeqNum: i + 1})
+	}
+	m.scrollToBottom()
+	return m
+}
+
+func TestPageTranscript(t *testing.T) {
+	m := scrollModel()
+	for _, step := range []struct {
+		key    tea.KeyType
+		scroll int
+		live   bool
+	}{
+		{tea.KeyPgUp, 61, false},
+		{tea.KeyPgUp, 42, false},
+		{tea.KeyPgDown, 61, false},
+		{tea.KeyHome, 0, false},
+		{tea.KeyPgUp, 0, false},
+		{tea.KeyPgDown, 19, false},
+		{tea.KeyEnd, 80, true},
+		{tea.KeyHome, 0, false},
+	} {
+		m, _ = applyUpdate(m, tea.KeyMsg{Type: step.key})
+		if m.transcriptScroll != step.scroll || m.transcriptLive != step.live {
+			t.Fatalf("after %v: scroll %d live %v, want %d %v", step.key, m.transcriptScroll, m.transcriptLive, step.scroll, step.live)
+		}
+	}
+
+	// Paging down onto the bottom resumes live, like ↓ does.
+	for range 5 {
+		m, _ = applyUpdate(m, tea.KeyMsg{Type: tea.KeyPgDown})
+	}
+	if m.transcriptScroll != 80 || !m.transcriptLive {
+		t.Errorf("scroll %d live %v, want the live tail", m.transcriptScroll, m.transcriptLive)
+	}
+}
+
+func TestJumpToLive(t *testing.T) {
+	m := scrollModel()
+	m.connected = true
+	m, _ = applyUpdate(m, tea.KeyMsg{Type: tea.KeyHome})
+	if !strings.Contains(m.renderFooter(), " Live") {
+		t.Error("footer should offer G while scrolled back")
+	}
+	m, _ = applyUpdate(m, runeKey('G'))
+	if !m.transcriptLive || m.transcriptScroll != 80 {
+		t.Errorf("G: scroll %d live %v", m.transcriptScroll, m.transcriptLive)
+	}
+
+	// Paging needs the transcript focused.
+	m.focusedPanel = FocusTopics
+	m, _ = applyUpdate(m, tea.KeyMsg{Type: tea.KeyHome})
+	if !m.transcriptLive {
+		t.Error("Home on the topics panel moved the transcript")
+	}
+}
+
+func TestScrollPercentInHeader(t *testing.T) {
+	m := scrollModel()
+	if head := m.renderTranscriptPanel(80, 20); !strings.Contains(head, "LIVE") {
+		t.Errorf("live header = %q", strings.SplitN(head, "\n", 2)[0])
+	}
+	for _, tc := range []struct {
+		scroll int
+		want   string
+	}{{0, "SCROLL 0%"}, {20, "SCROLL 25%"}, {60, "SCROLL 75%"}} {
+		m.transcriptLive, m.transcriptScroll = false, tc.scroll
+		if head := strings.SplitN(m.renderTranscriptPanel(80, 20), "\n", 2)[0]; !strings.Contains(head, tc.want) {
+			t.Errorf("scroll %d: header = %q, want %s", tc.scroll, head, tc.want)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package app
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jwulff/steno/internal/ui"
+)
+
+// transcriptSearch is the `/` search over the loaded transcript. While
+// editing, keys go to the prompt; enter jumps the cursor to the newest
+// match and leaves the search active, with every match highlighted and
+// n / N stepping between them, until esc clears it. Unlike the keyword
+// filter, it hides nothing.
+type transcriptSearch struct {
+	input   textInput
+	editing bool
+	re      *regexp.Regexp
+}
+
+// query is the committed search text.
+func (s *transcriptSearch) query() string {
+	return s.input.String()
+}
+
+// matches reports whether text, as displayed, contains the query.
+func (s *transcriptSearch) matches(text string) bool {
+	return s.re != nil && s.re.MatchString(text)
+}
+
+// spans are the matches in an already-wrapped line, to highlight.
+func (s *transcriptSearch) spans(line string) []span {
+	if s.re == nil {
+		return nil
+	}
+	return matchSpans(s.re, line, ui.SearchMatchStyle)
+}
+
+// highlight marks each match in an already-wrapped line.
+func (s *transcriptSearch) highlight(line string) string {
+	return renderSpans(line, s.spans(line))
+}
+
+func (m Model) openSearch() (tea.Model, tea.Cmd) {
+	m.search = &transcriptSearch{editing: true}
+	return m, nil
+}
+
+// handleSearchKey handles keys while the search prompt is open: text
+// edits the query, enter searches (or closes, when empty), tab opens the
+// saved searches instead, ctrl+f searches every session for the query,
+// esc cancels.
+func (m Model) handleSearchKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	s := *m.search
+	switch msg.String() {
+	case KeyCtrlC:
+		return m.quit()
+	case KeyEsc:
+		m.search = nil
+		return m, nil
+	case KeyTab:
+		m.search = nil
+		return m.openSavedSearches()
+	case KeySearchSessions:
+		m.search = nil
+		return m.openSessionSearch(s.query())
+	case KeyEnter:
+		query := strings.TrimSpace(s.query())
+		if query == "" {
+			m.search = nil
+			return m, nil
+		}
+		s.editing = false
+		s.re = regexp.MustCompile(`(?i)` + regexp.QuoteMeta(query))
+		m.search = &s
+		m.focusedPanel = FocusTranscript
+		m.jumpToMatch(len(m.entries), -1)
+		return m, nil
+	default:
+		s.input.handle(msg)
+	}
+	m.search = &s
+	return m, nil
+}
+
+// searchMatches returns the indexes in m.entries of the segments the
+// active search matches, oldest first.
+func (m Model) searchMatches() []int {
+	if m.search == nil || m.search.re == nil {
+		return nil
+	}
+	var hits []int
+	for i, e := range m.entries {
+		if m.selectable(e) && m.search.matches(m.scrubber.Apply(m.dictated(e.Text, e.Source))) {
+			hits = append(hits, i)
+		}
+	}
+	return hits
+}
+
+// jumpToMatch moves the cursor to the first match past entry from in
+// direction dir (1 is newer), wrapping around the transcript.
+func (m *Model) jumpToMatch(from, dir int) {
+	hits := m.searchMatches()
+	if len(hits) == 0 {
+		return
+	}
+	next := hits[len(hits)-1]
+	if dir > 0 {
+		next = hits[0]
+	}
+	for k := range hits {
+		i := hits[k]
+		if dir < 0 {
+			i = hits[len(hits)-1-k]
+		}
+		if (dir > 0 && i > from) || (dir < 0 && i < from) {
+			next = i
+			break
+		}
+	}
+	m.cursor = &transcriptCursor{sessionID: m.entries[next].SessionID, seq: m.entries[next].SeqNum}
+	m.revealCursor()
+}
+
+// nextMatch handles n (newer) and N (older) while a search is active.
+func (m Model) nextMatch(dir int) (tea.Model, tea.Cmd) {
+	m.jumpToMatch(m.cursorIndex(), dir)
+	return m, nil
+}
+
+// searchBadge is the transcript header's account of the search: the
+// prompt while editing, then which match the cursor is on.
+func (m Model) searchBadge() string {
+	if m.search.editing {
+		return " " + m.search.input.view("/")
+	}
+	hits := m.searchMatches()
+	if len(hits) == 0 {
+		return ui.SearchMatchStyle.Render(fmt.Sprintf(" /%s", m.search.query())) +
+			ui.DimStyle.Render(" no matches · esc clears")
+	}
+	pos := "-"
+	for k, i := range hits {
+		if i == m.cursorIndex() {
+			pos = fmt.Sprint(k + 1)
+		}
+	}
+	return ui.SearchMatchStyle.Render(fmt.Sprintf(" /%s %s/%d", m.search.query(), pos, len(hits))) +
+		ui.DimStyle.Render(" n/N · esc clears")
+}
@@ -0,0 +1,109 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func searchModel() Model {
+	m := New()
+	m.connected = true
+	m.width, m.height = 120, 30
+	m.sessionID = "s1"
+	for i, text := range []string{"the Budget is due", "then the launch plan", "a budget for launch", "wrap up"} {
+		m.handleEvent(segmentEvent("s1", i+1, text))
+	}
+	return m
+}
+
+func TestTranscriptSearchJumpsBetweenMatches(t *testing.T) {
+	m := searchModel()
+	m, _ = applyUpdate(m, runeKey('/'))
+	m = typeQuery(m, "budgex")
+	m, _ = applyUpdate(m, tea.KeyMsg{Type: tea.KeyBackspace})
+	m = typeQuery(m, "t")
+	if m.search == nil || !m.search.editing || !strings.Contains(m.View(), "/budget▌") {
+		t.Fatalf("prompt should show the query:\n%s", m.View())
+	}
+	if m.cursor != nil {
+		t.Error("typing shouldn't move the cursor")
+	}
+
+	m, _ = applyUpdate(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if e, ok := m.cursorEntry(); !ok || e.SeqNum != 3 {
+		t.Fatalf("enter should land on the newest match, cursor = %+v", m.cursor)
+	}
+	if !strings.Contains(m.View(), "/budget 2/2") {
+		t.Errorf("header should count the matches:\n%s", m.View())
+	}
+
+	for _, step := range []struct {
+		key  rune
+		want int
+	}{{'n', 1}, {'n', 3}, {'N', 1}, {'N', 3}} {
+		m, _ = applyUpdate(m, runeKey(step.key))
+		if e, _ := m.cursorEntry(); e.SeqNum != step.want {
+			t.Errorf("%c: cursor on segment %d, want %d", step.key, e.SeqNum, step.want)
+		}
+	}
+	if m.notes != nil {
+		t.Error("N should step back while a search is active, not open notes")
+	}
+
+	m, _ = applyUpdate(m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.search != nil || m.cursor == nil {
+		t.Error("esc should clear the search first and keep the cursor")
+	}
+}
+
+func TestTranscriptSearchHighlightsMatches(t *testing.T) {
+	m := searchModel()
+	m.segLines = newSegmentLines()
+	m.transcriptDisplayLines(80) // cache the lines laid out before the search
+	m, _ = applyUpdate(m, runeKey('/'))
+	m = typeQuery(m, "LAUNCH")
+	m, _ = applyUpdate(m, tea.KeyMsg{Type: tea.KeyEnter})
+
+	lines, spans := m.transcriptDisplayLines(80)
+	if len(spans) != 4 {
+		t.Errorf("searching shouldn't hide segments: %q", lines)
+	}
+	hits := 0
+	for _, l := range lines {
+		if strings.Contains(l, m.search.highlight("launch")) {
+			hits++
+		}
+	}
+	if hits != 2 || len(m.searchMatches()) != 2 {
+		t.Errorf("lines = %q, matches = %v, want both launch segments", lines, m.searchMatches())
+	}
+}
+
+func TestTranscriptSearchCancelAndEmpty(t *testing.T) {
+	m := searchModel()
+	m, _ = applyUpdate(m, runeKey('/'))
+	m = typeQuery(m, "wrap")
+	m, _ = applyUpdate(m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.search != nil || m.cursor != nil {
+		t.Error("esc in the prompt should cancel without moving the cursor")
+	}
+
+	m, _ = applyUpdate(m, runeKey('/'))
+	m, _ = applyUpdate(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.search != nil {
+		t.Error("enter with no query should close the prompt")
+	}
+
+	m, _ = applyUpdate(m, runeKey('/'))
+	m = typeQuery(m, "nowhere said")
+	m, _ = applyUpdate(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.cursor != nil || !strings.Contains(m.View(), "no matches") {
+		t.Errorf("a search with no matches should say so:\n%s", m.View())
+	}
+	m, _ = applyUpdate(m, runeKey('N'))
+	if m.notes != nil {
+		t.Error("N should stay a search key while the search is active")
+	}
+}
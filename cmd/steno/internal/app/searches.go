@@ -0,0 +1,202 @@
+package app
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jwulff/steno/internal/db"
+	"github.com/jwulff/steno/internal/ui"
+)
+
+// searchAlertSnippet caps the segment text quoted in a search alert.
+const searchAlertSnippet = 60
+
+// savedSearchMenu is the `/` overlay: the saved searches (client-owned
+// saved_searches table, managed with `steno searches`), each with its
+// match count within its scope. Enter filters the transcript by the
+// selected search.
+type savedSearchMenu struct {
+	searches []db.SavedSearch
+	// counts holds each search's matches; -1 when the count failed.
+	counts   []int
+	selected int
+	loaded   bool
+	err      error
+}
+
+// loadSavedSearchesCmd reads the saved searches and, for the menu,
+// counts their matches as of now against sessionID.
+func loadSavedSearchesCmd(store *db.Store, sessionID string, now time.Time) tea.Cmd {
+	return func() tea.Msg {
+		searches, err := store.SavedSearches()
+		if err != nil {
+			return SavedSearchesLoadedMsg{Err: err}
+		}
+		counts := make([]int, len(searches))
+		for i, ss := range searches {
+			counts[i] = -1
+			if q, err := ss.Query(sessionID, now); err == nil {
+				if n, err := store.CountSegments(q); err == nil {
+					counts[i] = n
+				}
+			}
+		}
+		return SavedSearchesLoadedMsg{Searches: searches, Counts: counts}
+	}
+}
+
+// loadAlertSearchesCmd reads the saved searches for their alerts alone.
+func loadAlertSearchesCmd(store *db.Store) tea.Cmd {
+	return func() tea.Msg {
+		searches, err := store.SavedSearches()
+		return AlertSearchesLoadedMsg{Searches: searches, Err: err}
+	}
+}
+
+// openSavedSearches opens the menu and (re)loads the searches, which
+// also picks up alert searches saved since the TUI started.
+func (m Model) openSavedSearches() (tea.Model, tea.Cmd) {
+	m.searchMenu = &savedSearchMenu{}
+	if m.store == nil {
+		return m, nil
+	}
+	return m, loadSavedSearchesCmd(m.store, m.sessionID, m.now())
+}
+
+// applySavedSearches fills the open menu and replaces the alert
+// searches.
+func (m *Model) applySavedSearches(msg SavedSearchesLoadedMsg) {
+	m.setAlertSearches(msg.Searches, msg.Err)
+	if m.searchMenu != nil {
+		m.searchMenu = &savedSearchMenu{searches: msg.Searches, counts: msg.Counts, loaded: true, err: msg.Err}
+	}
+}
+
+// setAlertSearches keeps the alert searches among searches. A failed
+// load keeps the alerts already in place.
+func (m *Model) setAlertSearches(searches []db.SavedSearch, err error) {
+	if err != nil {
+		return
+	}
+	m.alertSearches = m.alertSearches[:0:0]
+	for _, ss := range searches {
+		if ss.Alert {
+			m.alertSearches = append(m.alertSearches, ss)
+		}
+	}
+}
+
+// handleSavedSearchesKey handles keys while the menu is open: up/down or
+// j/k move, enter filters the transcript by the selection, esc or /
+// closes.
+func (m Model) handleSavedSearchesKey(key string) (tea.Model, tea.Cmd) {
+	s := *m.searchMenu
+	switch key {
+	case KeyQuit, KeyQuitUpper, KeyCtrlC:
+		return m.quit()
+	case KeyEsc, KeySearch:
+		m.searchMenu = nil
+		return m, nil
+	case KeyEnter:
+		if s.selected >= len(s.searches) {
+			return m, nil
+		}
+		m.searchMenu = nil
+		m.filter = newSavedSearchFilter(s.searches[s.selected])
+		m.focusedPanel = FocusTranscript
+		m.transcriptLive = false
+		m.transcriptScroll = 0
+		return m, nil
+	case KeyUp, KeyK:
+		if s.selected > 0 {
+			s.selected--
+		}
+	case KeyDown, KeyJ:
+		if s.selected < len(s.searches)-1 {
+			s.selected++
+		}
+	}
+	m.searchMenu = &s
+	return m, nil
+}
+
+// newSavedSearchFilter filters the transcript as ss would, matching its
+// text anywhere in a segment rather than as a whole word.
+func newSavedSearchFilter(ss db.SavedSearch) *transcriptFilter {
+	return &transcriptFilter{word: ss.Text, re: regexp.MustCompile(`(?i)` + regexp.QuoteMeta(ss.Text)), sources: ss.Sources}
+}
+
+// checkSearchAlerts runs the alert searches against a live segment,
+// noting each match and cueing it as `alerts` configures. Nil when
+// nothing matched.
+func (m *Model) checkSearchAlerts(entry TranscriptEntry) tea.Cmd {
+	if entry.SessionID != m.sessionID || len(m.alertSearches) == 0 {
+		return nil
+	}
+	speaker := ""
+	if m.speaker.name != "" && m.now().Sub(m.speaker.at) < speakerTTL {
+		speaker = m.speaker.name
+	}
+	var cmds []tea.Cmd
+	for _, ss := range m.alertSearches {
+		if !ss.Matches(entry.Text, entry.Source, speaker) {
+			continue
+		}
+		text := strings.Join(strings.Fields(m.scrubber.Apply(entry.Text)), " ")
+		if r := []rune(text); len(r) > searchAlertSnippet {
+			text = string(r[:searchAlertSnippet-1]) + "…"
+		}
+		cmds = append(cmds, m.pushError(SeverityInfo, fmt.Sprintf("saved search “%s”: %s", ss.Name, text), true))
+	}
+	if len(cmds) == 0 {
+		return nil
+	}
+	return tea.Batch(append(cmds, m.alert())...)
+}
+
+// renderSavedSearches renders the menu in place of the main panels.
+func (m Model) renderSavedSearches() string {
+	s := m.searchMenu
+	width := max(20, m.width-6)
+	lines := []string{ui.PanelTitleActiveStyle.Render("Saved searches")}
+	switch {
+	case m.store == nil:
+		lines = append(lines, ui.DimStyle.Render("No database available yet."))
+	case !s.loaded:
+		lines = append(lines, ui.DimStyle.Render("Loading saved searches…"))
+	case s.err != nil:
+		lines = append(lines, ui.ErrorTextStyle.Render("Couldn't load saved searches: "+s.err.Error()))
+	case len(s.searches) == 0:
+		lines = append(lines, ui.DimStyle.Render("None yet. Save one with: steno searches --save NAME TEXT"))
+	}
+
+	rows := max(1, m.transcriptVisibleLines()-4)
+	start := 0
+	if s.selected >= rows {
+		start = s.selected - rows + 1
+	}
+	for i := start; i < len(s.searches) && i < start+rows; i++ {
+		ss := s.searches[i]
+		count := "?"
+		if i < len(s.counts) && s.counts[i] >= 0 {
+			count = fmt.Sprint(s.counts[i])
+		}
+		detail := fmt.Sprintf("%q · %s · %s", ss.Text, ss.Scope, count)
+		if ss.Alert {
+			detail += " · alert"
+		}
+		line := ss.Name + "  " + ui.DimStyle.Render(detail)
+		if i == s.selected {
+			line = ui.SelectedStyle.Render(ui.Marker(true)+ss.Name) + "  " + ui.DimStyle.Render(detail)
+		} else {
+			line = ui.Marker(false) + line
+		}
+		lines = append(lines, truncateToWidth(line, width))
+	}
+
+	lines = append(lines, "", ui.DimStyle.Render("↑/↓ move · enter show in transcript · esc close"))
+	return ui.SessionBrowserStyle.Render(strings.Join(lines, "\n"))
+}
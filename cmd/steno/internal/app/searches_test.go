@@ -0,0 +1,107 @@
+package app
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jwulff/steno/internal/config"
+	"github.com/jwulff/steno/internal/daemon"
+	"github.com/jwulff/steno/internal/db"
+)
+
+func TestSavedSearchMenuFiltersTranscript(t *testing.T) {
+	m := testModel(t)
+	m.sessionID = "s1"
+	for i, ev := range []struct{ text, source string }{
+		{"the budget is due", "microphone"},
+		{"budget numbers from them", "systemAudio"},
+		{"then the launch plan", "microphone"},
+	} {
+		seq := i + 1
+		m.handleEvent(daemon.Event{Event: "segment", Text: ev.text, Source: ev.source, SessionID: "s1", SequenceNumber: &seq})
+	}
+
+	m, _ = applyUpdate(m, runeKey('/'))
+	m, cmd := applyUpdate(m, tea.KeyMsg{Type: tea.KeyTab})
+	if m.searchMenu == nil || m.search != nil || cmd == nil {
+		t.Fatal("tab in the search prompt should open the menu and load the searches")
+	}
+	if !strings.Contains(m.View(), "Loading saved searches") {
+		t.Error("menu should show it's loading")
+	}
+	m, _ = applyUpdate(m, SavedSearchesLoadedMsg{
+		Searches: []db.SavedSearch{
+			{Name: "launch", Text: "launch", Scope: db.ScopeAll},
+			{Name: "my budget", Text: "Budget", Sources: []string{"microphone"}, Scope: db.ScopeSession, Alert: true},
+		},
+		Counts: []int{4, -1},
+	})
+	view := m.View()
+	if !strings.Contains(view, `"launch" · all · 4`) || !strings.Contains(view, `"Budget" · session · ? · alert`) {
+		t.Errorf("menu view:\n%s", view)
+	}
+	if len(m.alertSearches) != 1 || m.alertSearches[0].Name != "my budget" {
+		t.Errorf("alert searches = %+v", m.alertSearches)
+	}
+
+	m, _ = applyUpdate(m, runeKey('j'))
+	m, _ = applyUpdate(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.searchMenu != nil || m.filter == nil || m.filter.word != "Budget" {
+		t.Fatalf("enter should close the menu and filter by the search (filter=%+v)", m.filter)
+	}
+	view = m.View()
+	if !strings.Contains(view, "is due") || strings.Contains(view, "numbers from them") || strings.Contains(view, "launch plan") {
+		t.Errorf("filtered view:\n%s", view)
+	}
+}
+
+func TestSavedSearchMenuEmptyAndClose(t *testing.T) {
+	m := testModel(t)
+	m.alertSearches = []db.SavedSearch{{Name: "kept", Text: "x", Alert: true}}
+
+	m, _ = applyUpdate(m, runeKey('/'))
+	m, _ = applyUpdate(m, tea.KeyMsg{Type: tea.KeyTab})
+	m, _ = applyUpdate(m, SavedSearchesLoadedMsg{Err: errors.New("disk gone")})
+	if !strings.Contains(m.View(), "disk gone") {
+		t.Error("menu should show the load error")
+	}
+	if len(m.alertSearches) != 1 {
+		t.Error("a failed load shouldn't drop the alert searches")
+	}
+	m, _ = applyUpdate(m, SavedSearchesLoadedMsg{})
+	if !strings.Contains(m.View(), "steno searches --save") {
+		t.Error("empty menu should say how to save one")
+	}
+	if m, _ = applyUpdate(m, tea.KeyMsg{Type: tea.KeyEnter}); m.searchMenu == nil || m.filter != nil {
+		t.Error("enter with nothing selected should do nothing")
+	}
+	if m, _ = applyUpdate(m, runeKey('/')); m.searchMenu != nil {
+		t.Error("/ should close the menu")
+	}
+}
+
+func TestSavedSearchAlerts(t *testing.T) {
+	m, bell, _ := alertModel(config.AlertsConfig{Bell: true})
+	m.sessionID = "s1"
+	m, _ = applyUpdate(m, AlertSearchesLoadedMsg{Searches: []db.SavedSearch{
+		{Name: "pricing", Text: "price", Alert: true},
+		{Name: "quiet", Text: "price"},
+		{Name: "theirs", Text: "price", Sources: []string{"systemAudio"}, Alert: true},
+	}})
+
+	seq := 1
+	runCmd(m.handleEvent(daemon.Event{Event: "segment", Text: "what is the price", Source: "microphone", SessionID: "s1", SequenceNumber: &seq}))
+	if len(m.errorStack) != 1 || m.errorStack[0].Message != "saved search “pricing”: what is the price" || m.errorStack[0].Severity != SeverityInfo {
+		t.Fatalf("error stack = %+v", m.errorStack)
+	}
+	if bell.String() != "\a" {
+		t.Errorf("bell = %q, want one ring", bell)
+	}
+
+	seq = 2
+	if cmd := m.handleEvent(daemon.Event{Event: "segment", Text: "no match here", Source: "microphone", SessionID: "s1", SequenceNumber: &seq}); len(runCmd(cmd)) != 0 || len(m.errorStack) != 1 {
+		t.Error("a segment matching no search shouldn't alert")
+	}
+}
@@ -0,0 +1,151 @@
+package app
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jwulff/steno/internal/db"
+	"github.com/jwulff/steno/internal/ui"
+)
+
+// sessionSearchLimit caps the segments a search of every session lists.
+const sessionSearchLimit = 200
+
+// sessionSearch is the `ctrl+f` search of every session's transcript,
+// for a phrase remembered without the meeting it was said in. Enter
+// searches for the query; with the results in, enter replays the
+// selected segment's session from that segment.
+type sessionSearch struct {
+	input    textInput
+	searched string // the query hits are for
+	loading  bool
+	hits     []db.SegmentHit // newest first
+	err      error
+	selected int
+}
+
+// sessionSearchCmd searches every session for query.
+func sessionSearchCmd(store *db.Store, query string) tea.Cmd {
+	return func() tea.Msg {
+		hits, err := store.SearchAllSegments(query, sessionSearchLimit)
+		return SessionSearchResultsMsg{Query: query, Hits: hits, Err: err}
+	}
+}
+
+// openSessionSearch opens the search, running query right away when it
+// isn't empty (as when it comes from the `/` prompt).
+func (m Model) openSessionSearch(query string) (tea.Model, tea.Cmd) {
+	m.sessionSearch = &sessionSearch{input: textInput{value: []rune(query)}}
+	return m.runSessionSearch()
+}
+
+func (m Model) runSessionSearch() (tea.Model, tea.Cmd) {
+	s := *m.sessionSearch
+	query := strings.TrimSpace(s.input.String())
+	if query == "" || m.store == nil {
+		return m, nil
+	}
+	s.searched, s.loading, s.err = query, true, nil
+	m.sessionSearch = &s
+	return m, sessionSearchCmd(m.store, query)
+}
+
+func (m Model) applySessionSearchResults(msg SessionSearchResultsMsg) (tea.Model, tea.Cmd) {
+	// Results for a query since replaced are dropped.
+	if m.sessionSearch == nil || msg.Query != m.sessionSearch.searched {
+		return m, nil
+	}
+	s := *m.sessionSearch
+	s.loading = false
+	s.hits, s.err = msg.Hits, msg.Err
+	s.selected = 0
+	m.sessionSearch = &s
+	return m, nil
+}
+
+// handleSessionSearchKey handles keys while the search is open: text
+// edits the query, enter searches (or, once the results for the query
+// are in, replays the selection), up/down move, esc or ctrl+f closes.
+func (m Model) handleSessionSearchKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	s := *m.sessionSearch
+	switch msg.String() {
+	case KeyCtrlC:
+		return m.quit()
+	case KeyEsc, KeySearchSessions:
+		m.sessionSearch = nil
+		return m, nil
+	case KeyEnter:
+		if strings.TrimSpace(s.input.String()) != s.searched {
+			return m.runSessionSearch()
+		}
+		if m.store == nil || s.loading || s.selected >= len(s.hits) {
+			return m, nil
+		}
+		hit := s.hits[s.selected]
+		m.sessionSearch = nil
+		m.browser.open = false
+		return m, loadReplayAtCmd(m.store, hit.SessionID, hit.SessionTitle, hit.SequenceNumber)
+	case KeyUp, "ctrl+p":
+		if s.selected > 0 {
+			s.selected--
+		}
+	case KeyDown, "ctrl+n":
+		if s.selected < len(s.hits)-1 {
+			s.selected++
+		}
+	default:
+		s.input.handle(msg)
+	}
+	m.sessionSearch = &s
+	return m, nil
+}
+
+// renderSessionSearch renders the search in place of the main panels:
+// a line per matching segment, with its session and the match marked.
+func (m Model) renderSessionSearch() string {
+	s := m.sessionSearch
+	width := max(20, m.width-6)
+	lines := []string{
+		ui.PanelTitleActiveStyle.Render("Search all sessions"),
+		s.input.view("> "),
+	}
+	switch {
+	case m.store == nil:
+		lines = append(lines, ui.DimStyle.Render("No database available yet."))
+	case s.searched == "":
+		lines = append(lines, ui.DimStyle.Render("Type a phrase and press enter."))
+	case s.loading:
+		lines = append(lines, ui.DimStyle.Render("Searching…"))
+	case s.err != nil:
+		lines = append(lines, ui.ErrorStyle.Render("Search failed: "+s.err.Error()))
+	case len(s.hits) == 0:
+		lines = append(lines, ui.DimStyle.Render(fmt.Sprintf("No session mentions %q.", s.searched)))
+	}
+
+	if !s.loading && s.searched != "" {
+		re := regexp.MustCompile(`(?i)` + regexp.QuoteMeta(s.searched))
+		rows := max(1, m.transcriptVisibleLines()-4)
+		start := 0
+		if s.selected >= rows {
+			start = s.selected - rows + 1
+		}
+		for i := start; i < len(s.hits) && i < start+rows; i++ {
+			hit := s.hits[i]
+			title := hit.SessionTitle
+			if title == "" {
+				title = "(untitled)"
+			}
+			lead := fmt.Sprintf("%s%s  %s · ", ui.Marker(i == s.selected), hit.StartedAt.Format("2006-01-02 15:04"), title)
+			text := truncateToWidth(m.scrubber.Apply(hit.Text), max(10, width-len([]rune(lead))))
+			if i == s.selected {
+				lead = ui.SelectedStyle.Render(lead)
+			}
+			lines = append(lines, lead+re.ReplaceAllStringFunc(text, func(t string) string { return ui.SearchMatchStyle.Render(t) }))
+		}
+	}
+
+	lines = append(lines, ui.DimStyle.Render(fmt.Sprintf("%d found · ↑/↓ move · enter search / replay · esc close", len(s.hits))))
+	return ui.SessionBrowserStyle.Render(strings.Join(lines, "\n"))
+}
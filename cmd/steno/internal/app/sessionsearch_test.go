@@ -0,0 +1,77 @@
+package app
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jwulff/steno/internal/db"
+)
+
+func TestSessionSearchListsMatches(t *testing.T) {
+	m := testModel(t)
+
+	m, _ = applyUpdate(m, tea.KeyMsg{Type: tea.KeyCtrlF})
+	if m.sessionSearch == nil || !strings.Contains(m.View(), "Search all sessions") {
+		t.Fatal("ctrl+f should open the search")
+	}
+	m = typeQuery(m, "ship it")
+	m, cmd := applyUpdate(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil || !m.sessionSearch.loading || !strings.Contains(m.View(), "Searching") {
+		t.Fatal("enter should run the search")
+	}
+
+	at := time.Date(2026, 3, 10, 14, 5, 0, 0, time.Local)
+	m, _ = applyUpdate(m, SessionSearchResultsMsg{Query: "ship", Hits: []db.SegmentHit{{Segment: db.Segment{Text: "stale"}}}})
+	if !m.sessionSearch.loading {
+		t.Error("results for an earlier query should be dropped")
+	}
+	m, _ = applyUpdate(m, SessionSearchResultsMsg{Query: "ship it", Hits: []db.SegmentHit{
+		{Segment: db.Segment{SessionID: "s2", SequenceNumber: 7, Text: "we ship it Friday", StartedAt: at}, SessionTitle: "Launch sync"},
+		{Segment: db.Segment{SessionID: "s1", SequenceNumber: 3, Text: "ship it or not", StartedAt: at.AddDate(0, 0, -7)}},
+	}})
+	view := m.View()
+	if !strings.Contains(view, "2026-03-10 14:05  Launch sync · we ship it Friday") || !strings.Contains(view, "(untitled) · ship it or not") {
+		t.Errorf("results view:\n%s", view)
+	}
+
+	m, _ = applyUpdate(m, tea.KeyMsg{Type: tea.KeyDown})
+	m, cmd = applyUpdate(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.sessionSearch != nil || cmd == nil {
+		t.Fatal("enter on a result should close the search and load its session")
+	}
+}
+
+func TestSessionSearchFromPromptAndFailure(t *testing.T) {
+	m := testModel(t)
+	m, _ = applyUpdate(m, runeKey('/'))
+	m = typeQuery(m, "budget")
+	m, cmd := applyUpdate(m, tea.KeyMsg{Type: tea.KeyCtrlF})
+	if m.search != nil || m.sessionSearch == nil || m.sessionSearch.searched != "budget" || cmd == nil {
+		t.Fatalf("ctrl+f from the prompt should search every session for its query: %+v", m.sessionSearch)
+	}
+	m, _ = applyUpdate(m, SessionSearchResultsMsg{Query: "budget", Err: errors.New("disk gone")})
+	if !strings.Contains(m.View(), "Search failed: disk gone") {
+		t.Errorf("view should show the failure:\n%s", m.View())
+	}
+	m, _ = applyUpdate(m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.sessionSearch != nil {
+		t.Error("esc should close the search")
+	}
+}
+
+func TestReplayStartsAtMatchedSegment(t *testing.T) {
+	m := New()
+	m.width, m.height = 120, 30
+	msg := replayFixture()
+	msg.Seq = 2
+	m, _ = applyUpdate(m, msg)
+	if m.replay == nil || m.replay.next != 2 || m.replay.pos != 20*time.Second {
+		t.Fatalf("replay should start after segment 2: %+v", m.replay)
+	}
+	if !strings.Contains(m.View(), "remote side talking") {
+		t.Errorf("the matched segment should be shown:\n%s", m.View())
+	}
+}
@@ -0,0 +1,102 @@
+package app
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jwulff/steno/internal/daemon"
+	"github.com/jwulff/steno/internal/share"
+	"github.com/jwulff/steno/internal/ui"
+)
+
+// WithShare returns m as the host of a live co-viewing session
+// (`steno --share ADDR`): the daemon events it renders are published to
+// h's viewers, scrubbed as this TUI would show them.
+func (m Model) WithShare(h *share.Hub) Model {
+	m.shareHub = h
+	return m
+}
+
+// WithViewer returns m as a read-only viewer of the host at addr
+// (`steno --view HOST:PORT --token TOKEN`), let in with the host's share
+// token. It renders the host's events in place of the daemon's and never
+// connects to a daemon, so the recording controls are inert; a lost host
+// is redialed like a lost daemon.
+func (m Model) WithViewer(addr, token string) Model {
+	m.viewAddr = addr
+	m.viewToken = token
+	m.statusText = "Connecting to " + addr + "..."
+	return m
+}
+
+// viewerConnectedMsg reports a connection to the host being viewed.
+type viewerConnectedMsg struct{ client *daemon.Client }
+
+// viewCmd connects to the sharing host at addr with token. A failure is
+// handled like a dropped event connection: the model reconnects.
+func viewCmd(addr, token string) tea.Cmd {
+	return func() tea.Msg {
+		c, err := daemon.ConnectTCP(addr, token)
+		if err != nil {
+			return DaemonEventErrorMsg{Err: err}
+		}
+		return viewerConnectedMsg{client: c}
+	}
+}
+
+// publish sends ev to the viewers, if sharing. Text is scrubbed first, as
+// viewers are another screen the transcript is shown on. Topics go out
+// from shareTopics instead, with the host's edits applied.
+func (m Model) publish(ev daemon.Event) {
+	if m.shareHub == nil || ev.Event == "topics" {
+		return
+	}
+	ev.Text = m.scrubber.Apply(ev.Text)
+	ev.Message = m.scrubber.Apply(ev.Message)
+	ev.Title = m.scrubber.Apply(ev.Title)
+	m.shareHub.Publish(ev)
+}
+
+// shareTopics sends the viewers the topic panel's list.
+func (m Model) shareTopics() {
+	if m.shareHub == nil {
+		return
+	}
+	topics := make([]daemon.EventTopic, 0, len(m.topics))
+	for _, t := range m.topics {
+		topics = append(topics, daemon.EventTopic{
+			ID:                t.ID,
+			Title:             m.scrubber.Apply(t.Title),
+			Summary:           m.scrubber.Apply(t.Summary),
+			SegmentRangeStart: t.SegmentRangeStart,
+			SegmentRangeEnd:   t.SegmentRangeEnd,
+		})
+	}
+	m.shareHub.Publish(daemon.Event{Event: "topics", SessionID: m.sessionID, Topics: topics})
+}
+
+// shareStatus sends the viewers the recording state and session from a
+// status response, which the daemon's own `status` events don't name.
+func (m Model) shareStatus() {
+	if m.shareHub == nil {
+		return
+	}
+	m.shareHub.Publish(daemon.Event{Event: "status", Recording: daemon.BoolPtr(m.recording), SessionID: m.sessionID})
+}
+
+// renderShare is the footer's note of a shared or viewed session. The
+// host's names the token to hand viewers.
+func (m Model) renderShare() string {
+	switch {
+	case m.viewAddr != "":
+		return ui.DimStyle.Render("viewing " + m.viewAddr)
+	case m.shareHub != nil:
+		n := m.shareHub.Viewers()
+		noun := "viewers"
+		if n == 1 {
+			noun = "viewer"
+		}
+		return ui.DimStyle.Render(fmt.Sprintf("sharing on %s · token %s · %d %s", m.shareHub.Addr(), m.shareHub.Token(), n, noun))
+	}
+	return ""
+}
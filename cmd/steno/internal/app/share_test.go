@@ -0,0 +1,63 @@
+package app
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jwulff/steno/internal/daemon"
+	"github.com/jwulff/steno/internal/scrub"
+	"github.com/jwulff/steno/internal/share"
+)
+
+func TestViewerSeesHostsScrubbedTranscript(t *testing.T) {
+	hub, err := share.Listen("127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer hub.Close()
+
+	host := New().WithShare(hub)
+	host.connected = true
+	host.scrubber, _ = scrub.New(scrub.DefaultPatterns, "")
+	seq := 1
+	host, _ = applyUpdate(host, DaemonEventMsg{Event: daemon.Event{Event: "segment", SessionID: "sess-1", SequenceNumber: &seq, Source: "microphone", Text: "my key is sk-abcdefghijklmnopqrstuv"}})
+	host, _ = applyUpdate(host, TopicsLoadedMsg{Topics: []TopicLoaded{{ID: "t-1", Title: "Keys", Summary: "sk-abcdefghijklmnopqrstuv", SegmentRangeStart: 1, SegmentRangeEnd: 1}}})
+
+	viewer := New().WithViewer(hub.Addr().String(), hub.Token())
+	viewer.width, viewer.height = 120, 30
+	msg := viewCmd(viewer.viewAddr, viewer.viewToken)()
+	if _, ok := msg.(viewerConnectedMsg); !ok {
+		t.Fatalf("msg = %#v, want a connection", msg)
+	}
+	viewer, _ = applyUpdate(viewer, msg)
+	defer viewer.evClient.Close()
+	for range 2 {
+		viewer, _ = applyUpdate(viewer, readEventCmd(viewer.evClient)())
+	}
+
+	if len(viewer.entries) != 1 || strings.Contains(viewer.entries[0].Text, "sk-") {
+		t.Errorf("entries = %+v, want the host's segment, scrubbed", viewer.entries)
+	}
+	if len(viewer.topics) != 1 || viewer.topics[0].Title != "Keys" || strings.Contains(viewer.topics[0].Summary, "sk-") {
+		t.Errorf("topics = %+v, want the host's topic, scrubbed", viewer.topics)
+	}
+	if view := viewer.View(); !strings.Contains(view, "viewing "+hub.Addr().String()) {
+		t.Errorf("footer should name the host:\n%s", view)
+	}
+	if _, cmd := applyUpdate(viewer, runeKey(' ')); cmd != nil {
+		t.Error("a viewer's recording controls should be inert")
+	}
+}
+
+func TestViewerReconnectsToHost(t *testing.T) {
+	m := New().WithViewer("127.0.0.1:1", "token")
+	m.width, m.height = 120, 30
+	m, _ = applyUpdate(m, DaemonEventErrorMsg{Err: errors.New("connection refused")})
+	if label, _ := m.statusLabel(); !strings.Contains(label, "host lost") {
+		t.Errorf("status = %q", label)
+	}
+	if view := m.View(); !strings.Contains(view, "Host 127.0.0.1:1 disconnected") {
+		t.Errorf("view should name the host:\n%s", view)
+	}
+}
@@ -0,0 +1,71 @@
+package app
+
+import (
+	"hash/fnv"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jwulff/steno/internal/daemon"
+	"github.com/jwulff/steno/internal/ui"
+)
+
+// speakerTTL is how long a speaker stays "now speaking" without a fresh
+// speaker event or diarized segment, so a diarizer that goes quiet
+// doesn't leave a stale name on the status bar.
+const speakerTTL = 3 * time.Second
+
+// activeSpeaker is the latest diarized speaker and the stream they were
+// heard on.
+type activeSpeaker struct {
+	name   string
+	source string
+	at     time.Time
+}
+
+// applySpeaker records a `speaker` event or a live segment's label. An
+// empty label means nobody is speaking.
+func (m *Model) applySpeaker(ev daemon.Event) {
+	if ev.Speaker == "" {
+		m.speaker = activeSpeaker{}
+		return
+	}
+	m.speaker = activeSpeaker{name: ev.Speaker, source: ev.Source, at: m.now()}
+}
+
+// currentSpeaker returns the speaker to show, by name when the label has
+// one, and their source, or "" once the last speaker is older than
+// speakerTTL.
+func (m Model) currentSpeaker() (name, source string) {
+	if m.speaker.name == "" || m.now().Sub(m.speaker.at) >= speakerTTL {
+		return "", ""
+	}
+	return m.speakerName(m.speaker.name), m.speaker.source
+}
+
+func speakerIf(ok bool, speaker string) string {
+	if ok {
+		return speaker
+	}
+	return ""
+}
+
+// speakerStyle is the stable color for speaker.
+func speakerStyle(speaker string) lipgloss.Style {
+	return ui.SpeakerStyles[speakerIndex(speaker)]
+}
+
+// speakerGlyph is the meter cell for speaker: a stable shape under
+// ui.SymbolCues, so speakers differ by more than color; a solid block
+// otherwise.
+func speakerGlyph(speaker string) string {
+	if !ui.SymbolCues {
+		return "█"
+	}
+	return ui.SpeakerGlyphs[speakerIndex(speaker)]
+}
+
+func speakerIndex(speaker string) int {
+	h := fnv.New32a()
+	h.Write([]byte(speaker))
+	return int(h.Sum32() % uint32(len(ui.SpeakerStyles)))
+}
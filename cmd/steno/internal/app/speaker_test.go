@@ -0,0 +1,111 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jwulff/steno/internal/daemon"
+	"github.com/jwulff/steno/internal/ui"
+)
+
+func TestSpeakerEventShowsNowSpeaking(t *testing.T) {
+	c := newManualClock()
+	m := New().WithClock(c)
+	m.connected = true
+	m.engineStatus = StatusRecording
+	m.systemAudio = true
+	m.width, m.height = 200, 24
+
+	m.handleEvent(daemon.Event{Event: "speaker", Speaker: "Dana", Source: "systemAudio"})
+	if name, source := m.currentSpeaker(); name != "Dana" || source != "systemAudio" {
+		t.Fatalf("currentSpeaker = %q, %q", name, source)
+	}
+	if bar := m.renderStatusBar(); !strings.Contains(bar, "now speaking: Dana") {
+		t.Errorf("status bar = %q", bar)
+	}
+
+	c.advance(speakerTTL)
+	if name, _ := m.currentSpeaker(); name != "" {
+		t.Errorf("speaker %q should expire after %v", name, speakerTTL)
+	}
+
+	m.handleEvent(daemon.Event{Event: "speaker", Speaker: "Dana", Source: "microphone"})
+	m.handleEvent(daemon.Event{Event: "speaker", Source: "microphone"})
+	if bar := m.renderStatusBar(); strings.Contains(bar, "now speaking") {
+		t.Errorf("an empty speaker label should clear the status: %q", bar)
+	}
+}
+
+func TestSegmentSpeakerShowsNowSpeaking(t *testing.T) {
+	c := newManualClock()
+	m := New().WithClock(c)
+	m.sessionID = "sess-1"
+
+	ev := segmentEvent("sess-1", 1, "hello")
+	ev.Speaker = "Speaker 2"
+	m.handleEvent(ev)
+	if name, source := m.currentSpeaker(); name != "Speaker 2" || source != "microphone" {
+		t.Fatalf("currentSpeaker = %q, %q", name, source)
+	}
+	c.advance(speakerTTL)
+	if name, _ := m.currentSpeaker(); name != "" {
+		t.Errorf("speaker %q should expire after %v", name, speakerTTL)
+	}
+
+	// A segment backfilled behind later ones says nothing about now.
+	m.handleEvent(segmentEvent("sess-1", 3, "later"))
+	ev = segmentEvent("sess-1", 2, "earlier")
+	ev.Speaker = "Speaker 1"
+	m.handleEvent(ev)
+	if name, _ := m.currentSpeaker(); name != "" {
+		t.Errorf("backfilled segment set the speaker to %q", name)
+	}
+}
+
+func TestSpeakerStyleIsStable(t *testing.T) {
+	a := speakerStyle("Dana").Render("x")
+	if b := speakerStyle("Dana").Render("x"); a != b {
+		t.Error("a speaker's color should not change")
+	}
+}
+
+func TestSymbolCuesMarkSpeakersAndFocus(t *testing.T) {
+	if !ui.UsePalette(ui.DeuteranopiaPalette) {
+		t.Fatal("deuteranopia palette missing")
+	}
+	t.Cleanup(func() { ui.UsePalette(ui.DefaultPalette) })
+
+	// Each speaker keeps the glyph at their color index.
+	glyphs := map[string]bool{}
+	for _, s := range []string{"Alice", "Bob", "Carol", "Dan", "Erin", "Frank"} {
+		if got := speakerGlyph(s); got != ui.SpeakerGlyphs[speakerIndex(s)] {
+			t.Errorf("glyph(%s) = %q", s, got)
+		}
+		glyphs[speakerGlyph(s)] = true
+	}
+	if len(glyphs) < 2 {
+		t.Errorf("speakers all drawn as %v", glyphs)
+	}
+	if meter := renderLevelMeter("MIC", 1, "Alice"); !strings.Contains(meter, speakerGlyph("Alice")) {
+		t.Errorf("meter = %q", meter)
+	}
+
+	m := New()
+	m.width, m.height = 120, 30
+	m.focusedPanel = FocusTopics
+	if head := m.renderTopicPanel(40, 10); !strings.Contains(head, "▸ TOPICS") {
+		t.Errorf("focused topics panel has no marker: %q", head)
+	}
+
+	ui.DisableColor()
+	if !ui.SymbolCues || speakerGlyph("Alice") == "" {
+		t.Error("no-color should keep symbol cues")
+	}
+}
+
+func TestDefaultPaletteHasNoMarkers(t *testing.T) {
+	ui.UsePalette(ui.DefaultPalette)
+	if ui.Marker(true) != "" || speakerGlyph("Alice") != "█" {
+		t.Errorf("marker %q, glyph %q; want color-only cues", ui.Marker(true), speakerGlyph("Alice"))
+	}
+}
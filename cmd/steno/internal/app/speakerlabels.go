@@ -0,0 +1,209 @@
+package app
+
+import (
+	"regexp"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jwulff/steno/internal/db"
+	"github.com/jwulff/steno/internal/ui"
+)
+
+// Diarized segments are tagged in the transcript with their speaker's
+// label, shortened ("Speaker 1" → [S1]) and colored by speakerStyle. A
+// legend under the transcript header names each tag, and `U` renames
+// the session's speakers in the speaker_names table `steno speakers`
+// also writes.
+
+// speakerLabelNumber matches the numbered labels a diarizer gives.
+var speakerLabelNumber = regexp.MustCompile(`(?i)^(?:speaker|spk|s)[ _-]?(\d+)$`)
+
+// speakerTag is the short form of a diarized label: "S1" for "Speaker 1",
+// the label itself otherwise.
+func speakerTag(label string) string {
+	if m := speakerLabelNumber.FindStringSubmatch(label); m != nil {
+		return "S" + m[1]
+	}
+	return label
+}
+
+// renderSpeakerTag is label's tag as shown ahead of a segment, with the
+// space after it; "" for a segment nobody diarized.
+func renderSpeakerTag(label string) string {
+	if label == "" {
+		return ""
+	}
+	return speakerStyle(label).Render("["+speakerTag(label)+"]") + " "
+}
+
+// sessionSpeakers is the current session's diarized labels, in order of
+// first appearance.
+func (m Model) sessionSpeakers() []string {
+	var labels []string
+	seen := map[string]bool{}
+	for _, e := range m.entries {
+		if e.Speaker == "" || seen[e.Speaker] || (e.SessionID != "" && e.SessionID != m.sessionID) {
+			continue
+		}
+		seen[e.Speaker] = true
+		labels = append(labels, e.Speaker)
+	}
+	return labels
+}
+
+// showSpeakerLegend reports whether the transcript panel has a legend
+// line: the session has diarized segments and the transcript is shown.
+func (m Model) showSpeakerLegend() bool {
+	return !m.showSummary && len(m.sessionSpeakers()) > 0
+}
+
+// renderSpeakerLegend renders the legend line for a panel width wide.
+func (m Model) renderSpeakerLegend(width int) string {
+	var parts []string
+	for _, label := range m.sessionSpeakers() {
+		parts = append(parts, renderSpeakerTag(label)+m.scrubber.Apply(m.speakerName(label)))
+	}
+	return truncateToWidth("  "+strings.Join(parts, ui.DimStyle.Render(" · ")), width)
+}
+
+// speakerEditor is the `U` overlay listing the session's speakers. Enter
+// renames the selection; an empty name goes back to the label.
+type speakerEditor struct {
+	labels   []string
+	selected int
+	// name is the new name while editing; nil while choosing.
+	name []rune
+}
+
+// renameSpeakerCmd saves a speaker's name for sessionID.
+func renameSpeakerCmd(store *db.Store, sessionID, label, name string) tea.Cmd {
+	return func() tea.Msg {
+		name = strings.TrimSpace(name)
+		err := store.SetSpeakerName(sessionID, label, name)
+		return SpeakerRenamedMsg{SessionID: sessionID, Label: label, Name: name, Err: err}
+	}
+}
+
+// openSpeakerEditor lists the session's speakers. Without a store there
+// is nowhere to save, and a viewer's session isn't theirs to rename, so
+// the key does nothing.
+func (m Model) openSpeakerEditor() (tea.Model, tea.Cmd) {
+	if m.store == nil || m.sessionID == "" || m.viewAddr != "" {
+		return m, nil
+	}
+	m.speakerEdit = &speakerEditor{labels: m.sessionSpeakers()}
+	return m, nil
+}
+
+// handleSpeakerEditKey handles keys while the speaker list is open:
+// up/down move and enter starts renaming; while renaming, text goes into
+// the name, enter saves and esc goes back to the list.
+func (m Model) handleSpeakerEditKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	e := *m.speakerEdit
+	if msg.Type == tea.KeyCtrlC {
+		return m.quit()
+	}
+	if e.name == nil {
+		switch msg.String() {
+		case KeyEsc, KeySpeakers:
+			m.speakerEdit = nil
+			return m, nil
+		case KeyEnter:
+			if len(e.labels) > 0 {
+				label := e.labels[e.selected]
+				e.name = []rune{}
+				if name := m.speakerName(label); name != label {
+					e.name = []rune(name)
+				}
+			}
+		case KeyUp, KeyK:
+			if e.selected > 0 {
+				e.selected--
+			}
+		case KeyDown, KeyJ:
+			if e.selected < len(e.labels)-1 {
+				e.selected++
+			}
+		}
+		m.speakerEdit = &e
+		return m, nil
+	}
+	switch msg.Type {
+	case tea.KeyEsc:
+		e.name = nil
+	case tea.KeyEnter:
+		m.speakerEdit = nil
+		return m, renameSpeakerCmd(m.store, m.sessionID, e.labels[e.selected], string(e.name))
+	case tea.KeyBackspace:
+		if len(e.name) > 0 {
+			e.name = e.name[:len(e.name)-1]
+		}
+	case tea.KeyCtrlU:
+		e.name = []rune{}
+	case tea.KeySpace:
+		e.name = append(e.name, ' ')
+	case tea.KeyRunes:
+		e.name = append(e.name, msg.Runes...)
+	}
+	m.speakerEdit = &e
+	return m, nil
+}
+
+// applySpeakerRename reflects a saved name in the legend and status bar.
+// Naming a speaker answers any pending offer of last meeting's names.
+func (m Model) applySpeakerRename(msg SpeakerRenamedMsg) (tea.Model, tea.Cmd) {
+	if msg.Err != nil {
+		return m, m.pushError(SeverityError, "rename speaker: "+msg.Err.Error(), true)
+	}
+	if msg.SessionID != m.sessionID {
+		return m, nil
+	}
+	s := m.speakerNames
+	if s.sessionID != msg.SessionID {
+		s = speakerNameState{sessionID: msg.SessionID}
+	}
+	names := map[string]string{}
+	for label, name := range s.names {
+		names[label] = name
+	}
+	if msg.Name == "" {
+		delete(names, msg.Label)
+	} else {
+		names[msg.Label] = msg.Name
+	}
+	s.names, s.offer, s.settled = names, nil, true
+	m.speakerNames = s
+	return m, nil
+}
+
+// renderSpeakerEditor renders the speaker list in place of the main
+// panels.
+func (m Model) renderSpeakerEditor() string {
+	e := m.speakerEdit
+	width := max(20, m.width-6)
+	lines := []string{ui.PanelTitleActiveStyle.Render("Speakers · this session")}
+	if len(e.labels) == 0 {
+		lines = append(lines, ui.DimStyle.Render("No diarized speakers yet."))
+	}
+	for i, label := range e.labels {
+		name := m.scrubber.Apply(m.speakerName(label))
+		detail := ""
+		if name != label {
+			detail = "  " + ui.DimStyle.Render(label)
+		}
+		line := ui.Marker(false) + renderSpeakerTag(label) + name + detail
+		if i == e.selected {
+			if e.name != nil {
+				name = string(e.name) + "▌"
+			}
+			line = ui.SelectedStyle.Render(ui.Marker(true)) + renderSpeakerTag(label) + ui.SelectedStyle.Render(name) + detail
+		}
+		lines = append(lines, truncateToWidth(line, width))
+	}
+	hint := "↑/↓ move · enter rename · esc close"
+	if e.name != nil {
+		hint = "enter save · esc cancel · an empty name goes back to the label"
+	}
+	lines = append(lines, "", ui.DimStyle.Render(hint))
+	return ui.SessionBrowserStyle.Render(strings.Join(lines, "\n"))
+}
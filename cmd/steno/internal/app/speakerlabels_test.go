@@ -0,0 +1,100 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jwulff/steno/internal/daemon"
+)
+
+func diarizedModel(t *testing.T) Model {
+	m := testModel(t, withSession("sess-1"))
+	for i, seg := range []struct{ speaker, text string }{
+		{"Speaker 1", "shall we start"},
+		{"Speaker 2", "sure"},
+		{"", "undiarized"},
+		{"Speaker 1", "first item"},
+	} {
+		seq := i + 1
+		m, _ = applyUpdate(m, DaemonEventMsg{Event: daemon.Event{Event: "segment", SessionID: "sess-1", SequenceNumber: &seq, Source: "systemAudio", Speaker: seg.speaker, Text: seg.text}})
+	}
+	return m
+}
+
+func TestSpeakerTag(t *testing.T) {
+	for label, want := range map[string]string{
+		"Speaker 1":  "S1",
+		"speaker_12": "S12",
+		"SPK3":       "S3",
+		"Host":       "Host",
+	} {
+		if got := speakerTag(label); got != want {
+			t.Errorf("speakerTag(%q) = %q, want %q", label, got, want)
+		}
+	}
+}
+
+func TestTranscriptTagsSpeakersWithLegend(t *testing.T) {
+	m := diarizedModel(t)
+	if got := m.sessionSpeakers(); len(got) != 2 || got[0] != "Speaker 1" || got[1] != "Speaker 2" {
+		t.Fatalf("sessionSpeakers = %q", got)
+	}
+	view := m.View()
+	for _, want := range []string{"[S1] shall we start", "[S2] sure", "[S1] Speaker 1 · [S2] Speaker 2"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view should contain %q:\n%s", want, view)
+		}
+	}
+	if strings.Contains(view, "[S1] undiarized") || strings.Contains(view, "[S2] undiarized") {
+		t.Error("a segment without a speaker shouldn't be tagged")
+	}
+	if m.transcriptHeaderLines() != 2 {
+		t.Errorf("header lines = %d, want the legend counted", m.transcriptHeaderLines())
+	}
+
+	m, _ = applyUpdate(m, SpeakerNamesLoadedMsg{SessionID: "sess-1", Names: map[string]string{"Speaker 2": "Ana"}})
+	if view := m.View(); !strings.Contains(view, "[S2] Ana") {
+		t.Errorf("legend should name the speaker:\n%s", view)
+	}
+}
+
+func TestRenameSpeaker(t *testing.T) {
+	m := diarizedModel(t)
+	m, _ = applyUpdate(m, runeKey('U'))
+	if m.speakerEdit == nil || len(m.speakerEdit.labels) != 2 {
+		t.Fatalf("U should list the session's speakers, got %+v", m.speakerEdit)
+	}
+	m, _ = applyUpdate(m, runeKey('j'))
+	m, _ = applyUpdate(m, tea.KeyMsg{Type: tea.KeyEnter})
+	// Keys that are bindings elsewhere are text here.
+	m, _ = applyUpdate(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("Ja")})
+	m, _ = applyUpdate(m, runeKey('q'))
+	if !strings.Contains(m.View(), "Jaq▌") {
+		t.Errorf("editor should render the name being typed:\n%s", m.View())
+	}
+	m, _ = applyUpdate(m, tea.KeyMsg{Type: tea.KeyBackspace})
+	m, _ = applyUpdate(m, runeKey('y'))
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if m.speakerEdit != nil || cmd == nil {
+		t.Fatal("enter should close the editor and save")
+	}
+
+	m, _ = applyUpdate(m, SpeakerRenamedMsg{SessionID: "sess-1", Label: "Speaker 2", Name: "Jay"})
+	if got := m.speakerName("Speaker 2"); got != "Jay" {
+		t.Errorf("speakerName = %q, want Jay", got)
+	}
+	m, _ = applyUpdate(m, SpeakerRenamedMsg{SessionID: "sess-1", Label: "Speaker 2"})
+	if got := m.speakerName("Speaker 2"); got != "Speaker 2" {
+		t.Errorf("an empty name should restore the label, got %q", got)
+	}
+}
+
+func TestRenameSpeakerNeedsStore(t *testing.T) {
+	m := diarizedModel(t)
+	m.store = nil
+	if m, _ = applyUpdate(m, runeKey('U')); m.speakerEdit != nil {
+		t.Error("without a store there is nowhere to save names")
+	}
+}
@@ -0,0 +1,151 @@
+package app
+
+import (
+	"fmt"
+	"maps"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jwulff/steno/internal/db"
+)
+
+// speakerNameState is what the TUI knows about the current session's
+// speaker names (client-owned speaker_names table). A session without
+// names may be offered the names from the previous session of the same
+// recurring meeting — same meeting link, else same title — which the
+// user accepts with y or declines with n. Nothing is copied unasked:
+// the same title doesn't guarantee the same people.
+type speakerNameState struct {
+	// sessionID is the session the rest describes; stale once the
+	// current session changes.
+	sessionID string
+	names     map[string]string
+	offer     *speakerOffer
+	// settled is set once the session has names or the offer was
+	// answered, so the store isn't asked again.
+	settled bool
+}
+
+type speakerOffer struct {
+	from  db.Session
+	names map[string]string
+}
+
+// loadSpeakerNamesCmd reads sessionID's speaker names and, when there
+// are none, looks for an earlier session of the same meeting to offer.
+// Lookup errors leave the labels unnamed rather than raising a notice.
+func loadSpeakerNamesCmd(store *db.Store, sessionID string) tea.Cmd {
+	return func() tea.Msg {
+		msg := SpeakerNamesLoadedMsg{SessionID: sessionID}
+		names, err := store.SpeakerNames(sessionID)
+		if err != nil {
+			msg.Err = err
+			return msg
+		}
+		if len(names) > 0 {
+			msg.Names = names
+			return msg
+		}
+		prev, err := store.PreviousRecurringSession(sessionID)
+		if err != nil || prev == nil {
+			msg.Err = err
+			return msg
+		}
+		offered, err := store.SpeakerNames(prev.ID)
+		if err != nil {
+			msg.Err = err
+			return msg
+		}
+		msg.Offer, msg.OfferNames = prev, offered
+		return msg
+	}
+}
+
+// copySpeakerNamesCmd saves accepted names on the current session.
+func copySpeakerNamesCmd(store *db.Store, fromID, toID string) tea.Cmd {
+	return func() tea.Msg {
+		_, err := store.CopySpeakerNames(fromID, toID)
+		return SpeakerNamesCopiedMsg{Err: err}
+	}
+}
+
+// speakerNamesCmd returns loadSpeakerNamesCmd for the current session
+// until it is settled or an offer is pending, or nil.
+func (m Model) speakerNamesCmd() tea.Cmd {
+	s := m.speakerNames
+	if m.store == nil || m.sessionID == "" {
+		return nil
+	}
+	if s.sessionID == m.sessionID && (s.settled || s.offer != nil) {
+		return nil
+	}
+	return loadSpeakerNamesCmd(m.store, m.sessionID)
+}
+
+// applySpeakerNames records a lookup for the current session; one for a
+// session that has since ended is dropped.
+func (m *Model) applySpeakerNames(msg SpeakerNamesLoadedMsg) {
+	if msg.SessionID != m.sessionID || msg.Err != nil {
+		return
+	}
+	s := speakerNameState{sessionID: msg.SessionID}
+	switch {
+	case len(msg.Names) > 0:
+		s.names, s.settled = msg.Names, true
+	case msg.Offer != nil && len(msg.OfferNames) > 0:
+		s.offer = &speakerOffer{from: *msg.Offer, names: msg.OfferNames}
+	}
+	m.speakerNames = s
+}
+
+// pendingSpeakerOffer is the offer awaiting y/n for the current session.
+func (m Model) pendingSpeakerOffer() *speakerOffer {
+	if m.speakerNames.sessionID != m.sessionID {
+		return nil
+	}
+	return m.speakerNames.offer
+}
+
+// answerSpeakerOffer handles y / n. Accepting names the labels now and
+// saves the copy; declining keeps the labels for this session. Both keys
+// do nothing while no offer is pending.
+func (m Model) answerSpeakerOffer(accept bool) (tea.Model, tea.Cmd) {
+	offer := m.pendingSpeakerOffer()
+	if offer == nil {
+		return m, nil
+	}
+	m.speakerNames.offer = nil
+	m.speakerNames.settled = true
+	if !accept {
+		return m, nil
+	}
+	m.speakerNames.names = maps.Clone(offer.names)
+	m.notice = "Using speaker names from " + offerTitle(offer.from)
+	return m, tea.Batch(copySpeakerNamesCmd(m.store, offer.from.ID, m.sessionID), m.clearNoticeCmd())
+}
+
+// speakerOfferPrompt is the status-bar question for a pending offer, or
+// "".
+func (m Model) speakerOfferPrompt() string {
+	offer := m.pendingSpeakerOffer()
+	if offer == nil {
+		return ""
+	}
+	return fmt.Sprintf("Use speaker names from %s (%s)? y/n", offerTitle(offer.from), offer.from.StartedAt.Local().Format("Jan 2"))
+}
+
+// speakerName is label's name in the current session, or label itself.
+func (m Model) speakerName(label string) string {
+	if m.speakerNames.sessionID == m.sessionID {
+		if name := m.speakerNames.names[label]; name != "" {
+			return name
+		}
+	}
+	return label
+}
+
+func offerTitle(s db.Session) string {
+	if s.Title == "" {
+		return "the last meeting"
+	}
+	return "“" + s.Title + "”"
+}
@@ -0,0 +1,88 @@
+package app
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jwulff/steno/internal/db"
+)
+
+func speakerOfferModel(t *testing.T) Model {
+	m := testModel(t, withSize(160, 30), withSession("standup-3"))
+	m, _ = applyUpdate(m, SpeakerNamesLoadedMsg{
+		SessionID:  "standup-3",
+		Offer:      &db.Session{ID: "standup-2", Title: "Team Standup", StartedAt: time.Now()},
+		OfferNames: map[string]string{"Speaker 1": "Ana"},
+	})
+	return m
+}
+
+func TestSpeakerOfferAccepted(t *testing.T) {
+	m := speakerOfferModel(t)
+	if bar := m.renderStatusBar(); !strings.Contains(bar, "Use speaker names from “Team Standup”") {
+		t.Fatalf("status bar = %q, want the offer", bar)
+	}
+	if m.speakerNamesCmd() != nil {
+		t.Error("a pending offer shouldn't be looked up again")
+	}
+
+	m, cmd := applyUpdate(m, runeKey('y'))
+	if cmd == nil {
+		t.Error("accepting should save the names")
+	}
+	if strings.Contains(m.renderStatusBar(), "y/n") {
+		t.Error("prompt should close once answered")
+	}
+	m.speaker = activeSpeaker{name: "Speaker 1", source: "microphone", at: m.now()}
+	if name, _ := m.currentSpeaker(); name != "Ana" {
+		t.Errorf("currentSpeaker = %q, want Ana", name)
+	}
+	m.speaker.name = "Speaker 2"
+	if name, _ := m.currentSpeaker(); name != "Speaker 2" {
+		t.Errorf("unnamed label shown as %q", name)
+	}
+	if m.speakerNamesCmd() != nil {
+		t.Error("settled session shouldn't be looked up again")
+	}
+}
+
+func TestSpeakerOfferDeclined(t *testing.T) {
+	m := speakerOfferModel(t)
+	m, cmd := applyUpdate(m, runeKey('n'))
+	if cmd != nil || m.pendingSpeakerOffer() != nil {
+		t.Fatal("declining should close the offer without saving")
+	}
+	m.speaker = activeSpeaker{name: "Speaker 1", source: "microphone", at: m.now()}
+	if name, _ := m.currentSpeaker(); name != "Speaker 1" {
+		t.Errorf("currentSpeaker = %q after declining", name)
+	}
+
+	// y / n are inert without an offer.
+	if _, cmd := applyUpdate(m, runeKey('y')); cmd != nil {
+		t.Error("y without an offer should do nothing")
+	}
+}
+
+func TestSpeakerNamesFollowSession(t *testing.T) {
+	m := speakerOfferModel(t)
+
+	// A lookup for a session that has since ended is dropped.
+	m, _ = applyUpdate(m, SpeakerNamesLoadedMsg{SessionID: "old", Names: map[string]string{"Speaker 1": "Zed"}})
+	if m.pendingSpeakerOffer() == nil {
+		t.Fatal("stale lookup replaced the offer")
+	}
+
+	// The offer and names belong to their session.
+	m.sessionID = "standup-4"
+	if m.pendingSpeakerOffer() != nil || m.speakerOfferPrompt() != "" {
+		t.Error("offer outlived its session")
+	}
+	if m.speakerNamesCmd() == nil {
+		t.Error("new session should be looked up")
+	}
+	m, _ = applyUpdate(m, SpeakerNamesLoadedMsg{SessionID: "standup-4", Names: map[string]string{"Speaker 1": "Ana"}})
+	if got := m.speakerName("Speaker 1"); got != "Ana" {
+		t.Errorf("speakerName = %q", got)
+	}
+}
@@ -0,0 +1,68 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/jwulff/steno/internal/db"
+	"github.com/jwulff/steno/internal/ui"
+)
+
+// The summary view (s) lists the session's rows in the summaries table,
+// newest first: the daemon writes a rolling summary each time it
+// regroups topics, and a final one when the session ends. Each shows its
+// type and the segments it covers. They reload on every `topics` event,
+// which the daemon emits in the same pass that writes a summary.
+
+// renderSummaries lays out the summaries for a panel width wide, in at
+// most height lines. Older summaries that don't fit are counted on the
+// last line instead.
+func (m Model) renderSummaries(width, height int) []string {
+	if len(m.summaries) == 0 {
+		return []string{
+			ui.DimStyle.Render("  No summary yet."),
+			ui.DimStyle.Render("  Summaries are generated as you speak."),
+		}
+	}
+	textWidth := max(10, width-4)
+	var lines []string
+	for i := len(m.summaries) - 1; i >= 0; i-- {
+		block := []string{"  " + summaryHeading(m.summaries[i], i == len(m.summaries)-1)}
+		for _, wl := range wrapText(m.scrubber.Apply(m.summaries[i].Content), textWidth) {
+			block = append(block, "  "+wl)
+		}
+		if i < len(m.summaries)-1 {
+			block = append([]string{""}, block...)
+		}
+		need := len(block)
+		if i > 0 {
+			need++ // room to count those left out
+		}
+		if len(lines)+need > height {
+			if len(lines) == 0 {
+				// Too short for even the latest: show what fits.
+				return block[:min(len(block), max(1, height))]
+			}
+			lines = append(lines, ui.DimStyle.Render(fmt.Sprintf("  … %d earlier", i+1)))
+			break
+		}
+		lines = append(lines, block...)
+	}
+	return lines
+}
+
+// summaryHeading is a summary's type, the segments it covers and when it
+// was written, like `rolling · segments 12–40 · 14:02`.
+func summaryHeading(s db.Summary, latest bool) string {
+	kind := s.SummaryType
+	if kind == "" {
+		kind = "summary"
+	}
+	detail := fmt.Sprintf(" · segments %d–%d", s.SegmentRangeStart, s.SegmentRangeEnd)
+	if !s.CreatedAt.IsZero() {
+		detail += " · " + s.CreatedAt.Local().Format("15:04")
+	}
+	if latest {
+		detail += " · latest"
+	}
+	return ui.MagentaStyle.Render(kind) + ui.DimStyle.Render(detail)
+}
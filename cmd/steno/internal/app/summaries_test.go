@@ -0,0 +1,33 @@
+package app
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jwulff/steno/internal/db"
+)
+
+func TestSummaryViewListsSummaries(t *testing.T) {
+	m := New()
+	at := time.Date(2026, 3, 2, 14, 0, 0, 0, time.Local)
+	m, _ = applyUpdate(m, SummaryLoadedMsg{Content: "Agreed on the Q3 budget.", Summaries: []db.Summary{
+		{Content: "Opened with the roadmap.", SummaryType: "rolling", SegmentRangeStart: 1, SegmentRangeEnd: 10, CreatedAt: at},
+		{Content: "Agreed on the Q3 budget.", SummaryType: "rolling", SegmentRangeStart: 11, SegmentRangeEnd: 24, CreatedAt: at.Add(5 * time.Minute)},
+	}})
+	if m.summaryText != "Agreed on the Q3 budget." {
+		t.Errorf("summaryText = %q, want the latest for the ticker", m.summaryText)
+	}
+
+	text := strings.Join(m.renderSummaries(80, 20), "\n")
+	latest, older := strings.Index(text, "segments 11–24 · 14:05 · latest"), strings.Index(text, "segments 1–10 · 14:00")
+	if latest < 0 || older < latest || !strings.Contains(text, "rolling") || !strings.Contains(text, "Opened with the roadmap.") {
+		t.Errorf("summaries, newest first:\n%s", text)
+	}
+
+	// Without room for both, the older one is counted instead.
+	lines := m.renderSummaries(80, 3)
+	if text := strings.Join(lines, "\n"); len(lines) != 3 || !strings.Contains(text, "Q3 budget") || !strings.Contains(text, "… 1 earlier") {
+		t.Errorf("clipped summaries:\n%s", text)
+	}
+}
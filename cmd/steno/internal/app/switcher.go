@@ -0,0 +1,213 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jwulff/steno/internal/db"
+	"github.com/jwulff/steno/internal/ui"
+)
+
+// quickSwitcherLimit caps how many sessions the quick switcher searches.
+// Far more than the browser shows: the switcher is for finding one
+// session among hundreds.
+const quickSwitcherLimit = 500
+
+// quickSwitcher is the `ctrl+k` fuzzy finder over sessions. Enter replays
+// the selected session, as enter does in the session browser.
+type quickSwitcher struct {
+	query    textInput
+	sessions []SessionRow // newest first
+	matches  []int        // indexes into sessions, best first
+	selected int
+	loaded   bool
+}
+
+// loadSwitcherCmd reads the sessions the quick switcher searches.
+func loadSwitcherCmd(store *db.Store) tea.Cmd {
+	return func() tea.Msg {
+		sessions, err := store.FilterSessions(db.SessionFilter{Limit: quickSwitcherLimit})
+		if err != nil {
+			return SwitcherLoadedMsg{} // silently ignore DB errors
+		}
+		return SwitcherLoadedMsg{Sessions: sessionRows(sessions)}
+	}
+}
+
+func (m Model) openSwitcher() (tea.Model, tea.Cmd) {
+	m.switcher = &quickSwitcher{}
+	if m.store == nil {
+		return m, nil
+	}
+	return m, loadSwitcherCmd(m.store)
+}
+
+func (m Model) applySwitcherLoaded(msg SwitcherLoadedMsg) (tea.Model, tea.Cmd) {
+	if m.switcher == nil {
+		return m, nil
+	}
+	s := *m.switcher
+	s.sessions = msg.Sessions
+	s.loaded = true
+	s.filter()
+	m.switcher = &s
+	return m, nil
+}
+
+// handleSwitcherKey handles keys while the quick switcher is open: text
+// narrows the list, up/down (or ctrl+p/ctrl+n) move, enter replays the
+// selection, esc or ctrl+k closes.
+func (m Model) handleSwitcherKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	s := *m.switcher
+	switch msg.String() {
+	case KeyCtrlC:
+		return m.quit()
+	case KeyEsc, KeyQuickSwitcher:
+		m.switcher = nil
+		return m, nil
+	case KeyEnter:
+		if m.store == nil || s.selected >= len(s.matches) {
+			return m, nil
+		}
+		row := s.sessions[s.matches[s.selected]]
+		m.switcher = nil
+		m.browser.open = false
+		return m, loadReplayCmd(m.store, row.ID, row.Title)
+	case KeyUp, "ctrl+p":
+		if s.selected > 0 {
+			s.selected--
+		}
+	case KeyDown, "ctrl+n":
+		if s.selected < len(s.matches)-1 {
+			s.selected++
+		}
+	default:
+		if s.query.handle(msg) {
+			s.filter()
+		}
+	}
+	m.switcher = &s
+	return m, nil
+}
+
+// filter recomputes matches for the current query, best first; ties keep
+// the newest-first order.
+func (s *quickSwitcher) filter() {
+	type scored struct{ idx, score int }
+	var hits []scored
+	query := strings.Fields(strings.ToLower(s.query.String()))
+	for i, row := range s.sessions {
+		haystack := strings.ToLower(switcherHaystack(row))
+		total, ok := 0, true
+		// Every word must match somewhere, in any order.
+		for _, word := range query {
+			score, found := fuzzyScore(word, haystack)
+			if !found {
+				ok = false
+				break
+			}
+			total += score
+		}
+		if ok {
+			hits = append(hits, scored{i, total})
+		}
+	}
+	sort.SliceStable(hits, func(a, b int) bool { return hits[a].score > hits[b].score })
+	s.matches = make([]int, 0, len(hits))
+	for _, h := range hits {
+		s.matches = append(s.matches, h.idx)
+	}
+	s.selected = 0
+}
+
+// switcherHaystack is the text a session is matched against: its title,
+// start date (as digits and as weekday / month names) and context.
+// Sessions have no tags; device, foreground app and meeting link fill
+// that role.
+func switcherHaystack(row SessionRow) string {
+	title := row.Title
+	if title == "" {
+		title = "untitled"
+	}
+	return strings.Join([]string{
+		title,
+		row.StartedAt.Format("2006-01-02 15:04 Monday January"),
+		row.Device,
+		row.ForegroundApp,
+		row.MeetingURL,
+	}, " ")
+}
+
+// fuzzyScore reports whether pattern's runes appear in text in order,
+// and how well: consecutive runes and runes at the start of a word score
+// higher, so "dr" prefers "Design Review" over "standard".
+func fuzzyScore(pattern, text string) (int, bool) {
+	p := []rune(pattern)
+	if len(p) == 0 {
+		return 0, true
+	}
+	score, pi, prevMatch := 0, 0, -2
+	prev := ' '
+	for ti, r := range []rune(text) {
+		if pi < len(p) && r == p[pi] {
+			switch {
+			case prevMatch == ti-1:
+				score += 3
+			case !unicode.IsLetter(prev) && !unicode.IsDigit(prev):
+				score += 2
+			default:
+				score++
+			}
+			prevMatch = ti
+			pi++
+		}
+		prev = r
+	}
+	return score, pi == len(p)
+}
+
+// renderSwitcher renders the quick switcher in place of the main panels.
+func (m Model) renderSwitcher() string {
+	s := m.switcher
+	width := max(20, m.width-6)
+	lines := []string{
+		ui.PanelTitleActiveStyle.Render("Go to session"),
+		s.query.view("> "),
+	}
+	switch {
+	case m.store == nil:
+		lines = append(lines, ui.DimStyle.Render("No database available yet."))
+	case !s.loaded:
+		lines = append(lines, ui.DimStyle.Render("Loading sessions…"))
+	case len(s.matches) == 0:
+		lines = append(lines, ui.DimStyle.Render("No sessions match."))
+	}
+
+	// Keep the selection visible within the rows the layout leaves us.
+	rows := max(1, m.transcriptVisibleLines()-4)
+	start := 0
+	if s.selected >= rows {
+		start = s.selected - rows + 1
+	}
+	for i := start; i < len(s.matches) && i < start+rows; i++ {
+		row := s.sessions[s.matches[i]]
+		title := row.Title
+		if title == "" {
+			title = "(untitled)"
+		}
+		if ctx := sessionContext(row); ctx != "" {
+			title += " · " + ctx
+		}
+		line := truncateToWidth(fmt.Sprintf("%s%s  %s", ui.Marker(i == s.selected), row.StartedAt.Format("2006-01-02 15:04"), title), width)
+		if i == s.selected {
+			line = ui.SelectedStyle.Render(line)
+		}
+		lines = append(lines, line)
+	}
+
+	lines = append(lines, ui.DimStyle.Render(fmt.Sprintf("%d of %d · ↑/↓ move · enter replay · esc close", len(s.matches), len(s.sessions))))
+	return ui.SessionBrowserStyle.Render(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,108 @@
+package app
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func switcherFixture(t *testing.T) Model {
+	t.Helper()
+	m := testModel(t)
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlK})
+	m = updated.(Model)
+	if m.switcher == nil || cmd == nil {
+		t.Fatal("ctrl+k should open the switcher and load sessions")
+	}
+	m, _ = applyUpdate(m, SwitcherLoadedMsg{Sessions: []SessionRow{
+		{ID: "s3", Title: "Standard update", StartedAt: time.Date(2026, 3, 11, 9, 0, 0, 0, time.Local)},
+		{ID: "s2", Title: "Design Review", StartedAt: time.Date(2026, 3, 10, 14, 0, 0, 0, time.Local), ForegroundApp: "zoom.us"},
+		{ID: "s1", StartedAt: time.Date(2026, 2, 2, 10, 0, 0, 0, time.Local)},
+	}})
+	return m
+}
+
+func typeQuery(m Model, q string) Model {
+	for _, r := range q {
+		if r == ' ' {
+			m, _ = applyUpdate(m, tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}})
+			continue
+		}
+		m, _ = applyUpdate(m, runeKey(r))
+	}
+	return m
+}
+
+func switcherIDs(m Model) string {
+	var ids []string
+	for _, i := range m.switcher.matches {
+		ids = append(ids, m.switcher.sessions[i].ID)
+	}
+	return strings.Join(ids, " ")
+}
+
+func TestSwitcherFuzzyMatches(t *testing.T) {
+	m := switcherFixture(t)
+	if got := switcherIDs(m); got != "s3 s2 s1" {
+		t.Errorf("empty query = %q, want all newest first", got)
+	}
+
+	// Word starts beat scattered letters.
+	m = typeQuery(m, "dr")
+	if got := switcherIDs(m); !strings.HasPrefix(got, "s2") {
+		t.Errorf("\"dr\" = %q, want Design Review first", got)
+	}
+
+	// Words match independently: title and foreground app.
+	m, _ = applyUpdate(m, tea.KeyMsg{Type: tea.KeyCtrlU})
+	m = typeQuery(m, "zoom design")
+	if got := switcherIDs(m); got != "s2" {
+		t.Errorf("\"zoom design\" = %q", got)
+	}
+
+	// Dates match by digits and by name.
+	m, _ = applyUpdate(m, tea.KeyMsg{Type: tea.KeyCtrlU})
+	m = typeQuery(m, "february")
+	if got := switcherIDs(m); got != "s1" {
+		t.Errorf("\"february\" = %q", got)
+	}
+	if view := m.View(); !strings.Contains(view, "(untitled)") || !strings.Contains(view, "1 of 3") {
+		t.Errorf("view:\n%s", view)
+	}
+
+	m, _ = applyUpdate(m, tea.KeyMsg{Type: tea.KeyBackspace})
+	if m.switcher.query.String() != "februar" {
+		t.Errorf("query after backspace = %q", m.switcher.query.String())
+	}
+}
+
+func TestSwitcherEnterReplaysSelection(t *testing.T) {
+	m := switcherFixture(t)
+	m, _ = applyUpdate(m, tea.KeyMsg{Type: tea.KeyDown})
+	m, cmd := applyUpdate(m, tea.KeyMsg{Type: tea.KeyEnter})
+	if m.switcher != nil || cmd == nil {
+		t.Fatal("enter should close the switcher and load the replay")
+	}
+
+	// A replay opened from the switcher returns to the live view.
+	m, _ = applyUpdate(m, replayFixture())
+	m, _ = applyUpdate(m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.replay != nil || m.browser.open {
+		t.Errorf("esc should return to the live view (replay=%v browser=%v)", m.replay != nil, m.browser.open)
+	}
+}
+
+func TestSwitcherSwallowsGlobalKeys(t *testing.T) {
+	m := switcherFixture(t)
+	m, cmd := applyUpdate(m, runeKey('q'))
+	if m.switcher == nil || cmd != nil || m.switcher.query.String() != "q" {
+		t.Fatal("q should narrow the switcher, not quit")
+	}
+	m, _ = applyUpdate(m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.switcher != nil {
+		t.Error("esc should close the switcher")
+	}
+}
@@ -0,0 +1,258 @@
+package app
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jwulff/steno/internal/daemon"
+	"github.com/jwulff/steno/internal/db"
+	"github.com/jwulff/steno/internal/ui"
+)
+
+// talkBarWidth is the footer ratio bar's width in cells.
+const talkBarWidth = 10
+
+// monologueShare is the share of the talking above which the footer
+// ratio warns, once monologueAfter has been spoken; a minute in, one
+// voice dominating is normal.
+const (
+	monologueShare = 0.8
+	monologueAfter = 2 * time.Minute
+)
+
+// wordDuration estimates a segment's length from its words (about 150
+// per minute) when the daemon predates segment end times.
+const wordDuration = 400 * time.Millisecond
+
+// talkTime is how long each source and diarized speaker has spoken in
+// the current session, for the footer's ratio bar. Both come from
+// segment lengths, keyed by sequence number so a segment counted live
+// and again by the store load isn't counted twice. A daemon that sends
+// speaker events times turns instead: a turn lasts until the next event,
+// as long as that arrives within speakerTTL.
+type talkTime struct {
+	sessionID string
+	segments  map[int]talkSegment
+	speakers  map[string]time.Duration
+	// unnumbered counts segments without a sequence number, which get
+	// keys below zero.
+	unnumbered int
+	// loaded is set once the store's segments have been counted.
+	loaded bool
+
+	// turn is the speaker whose turn is open, since turnAt.
+	turn   string
+	turnAt time.Time
+}
+
+type talkSegment struct {
+	source   string
+	speaker  string
+	duration time.Duration
+}
+
+// segmentDuration is how long the segment on ev was spoken for.
+func segmentDuration(ev daemon.Event) time.Duration {
+	if ev.StartedAt != nil && ev.EndedAt != nil && *ev.EndedAt > *ev.StartedAt {
+		return timeFromUnix(*ev.EndedAt).Sub(timeFromUnix(*ev.StartedAt))
+	}
+	return time.Duration(len(strings.Fields(ev.Text))) * wordDuration
+}
+
+// forSession returns t for sessionID, or a fresh talkTime when t
+// describes another session.
+func (t talkTime) forSession(sessionID string) talkTime {
+	if t.sessionID == sessionID && t.segments != nil {
+		return t
+	}
+	return talkTime{sessionID: sessionID, segments: map[int]talkSegment{}, speakers: map[string]time.Duration{}}
+}
+
+// countTalk adds a live segment to the current session's talk time.
+func (m *Model) countTalk(entry TranscriptEntry, d time.Duration) {
+	if m.sessionID == "" || entry.SessionID != m.sessionID || entry.Source == "" {
+		return
+	}
+	t := m.talk.forSession(m.sessionID)
+	key := entry.SeqNum
+	if key == 0 {
+		t.unnumbered++
+		key = -t.unnumbered
+	}
+	t.segments[key] = talkSegment{source: entry.Source, speaker: entry.Speaker, duration: d}
+	m.talk = t
+}
+
+// countSpeakerTurn closes the open speaker turn on a speaker event and
+// opens the next one. An empty label means nobody is speaking.
+func (m *Model) countSpeakerTurn(label string) {
+	if m.sessionID == "" {
+		return
+	}
+	t := m.talk.forSession(m.sessionID)
+	now := m.now()
+	if t.turn != "" {
+		if d := now.Sub(t.turnAt); d > 0 && d < speakerTTL {
+			t.speakers[t.turn] += d
+		}
+	}
+	t.turn, t.turnAt = label, now
+	m.talk = t
+}
+
+// loadTalkTimeCmd reads the lengths of the segments already in
+// sessionID, so a TUI opened mid-meeting starts from the session's real
+// ratio.
+func loadTalkTimeCmd(store *db.Store, sessionID string) tea.Cmd {
+	return func() tea.Msg {
+		segments, err := store.SegmentsForSession(sessionID, -1, 0)
+		if err != nil {
+			return TalkTimeLoadedMsg{SessionID: sessionID}
+		}
+		return TalkTimeLoadedMsg{SessionID: sessionID, Segments: segments}
+	}
+}
+
+// talkTimeCmd returns loadTalkTimeCmd for the current session until it
+// has loaded, or nil.
+func (m Model) talkTimeCmd() tea.Cmd {
+	if m.store == nil || m.sessionID == "" || (m.talk.sessionID == m.sessionID && m.talk.loaded) {
+		return nil
+	}
+	return loadTalkTimeCmd(m.store, m.sessionID)
+}
+
+// applyTalkTime merges the stored segments into the current session's
+// talk time; a load for a session that has since ended is dropped.
+func (m *Model) applyTalkTime(msg TalkTimeLoadedMsg) {
+	if msg.SessionID != m.sessionID {
+		return
+	}
+	t := m.talk.forSession(m.sessionID)
+	for _, s := range msg.Segments {
+		if s.Source == "" || !s.EndedAt.After(s.StartedAt) {
+			continue
+		}
+		t.segments[s.SequenceNumber] = talkSegment{source: s.Source, speaker: s.Speaker, duration: s.EndedAt.Sub(s.StartedAt)}
+	}
+	t.loaded = true
+	m.talk = t
+}
+
+// talkShare is one voice's part of the talking.
+type talkShare struct {
+	label    string
+	duration time.Duration
+	style    lipgloss.Style
+}
+
+// talkShares returns the current session's talk time by speaker once
+// any speaker time is known, else by source; largest first.
+func (m Model) talkShares() []talkShare {
+	if m.talk.sessionID != m.sessionID || m.sessionID == "" {
+		return nil
+	}
+	speakers := m.talk.speakers
+	if len(speakers) == 0 {
+		speakers = map[string]time.Duration{}
+		for _, s := range m.talk.segments {
+			if s.speaker != "" {
+				speakers[s.speaker] += s.duration
+			}
+		}
+	}
+	var shares []talkShare
+	for label, d := range speakers {
+		shares = append(shares, talkShare{label: m.speakerName(label), duration: d, style: speakerStyle(label)})
+	}
+	if len(shares) == 0 {
+		var mic, sys time.Duration
+		for _, s := range m.talk.segments {
+			if s.source == "microphone" {
+				mic += s.duration
+			} else {
+				sys += s.duration
+			}
+		}
+		shares = []talkShare{
+			{label: "MIC", duration: mic, style: ui.MicLabelStyle},
+			{label: "SYS", duration: sys, style: ui.SysLabelStyle},
+		}
+	}
+	shares = slices.DeleteFunc(shares, func(s talkShare) bool { return s.duration <= 0 })
+	slices.SortStableFunc(shares, func(a, b talkShare) int {
+		if c := cmp.Compare(b.duration, a.duration); c != 0 {
+			return c
+		}
+		return strings.Compare(a.label, b.label)
+	})
+	return shares
+}
+
+// renderTalkBar renders the footer's ratio bar: each voice's share of
+// the cells in its color, then the leading voice's percentage, warning
+// once it has held the floor for a monologue. Empty until anyone has
+// spoken.
+func (m Model) renderTalkBar() string {
+	shares := m.talkShares()
+	if len(shares) == 0 {
+		return ""
+	}
+	var total time.Duration
+	durations := make([]time.Duration, len(shares))
+	for i, s := range shares {
+		total += s.duration
+		durations[i] = s.duration
+	}
+	var bar strings.Builder
+	for i, n := range ratioCells(durations, talkBarWidth) {
+		if n > 0 {
+			bar.WriteString(shares[i].style.Render(strings.Repeat("█", n)))
+		}
+	}
+	lead := shares[0]
+	share := float64(lead.duration) / float64(total)
+	label := fmt.Sprintf(" %s %d%%", lead.label, int(share*100+0.5))
+	if share >= monologueShare && total >= monologueAfter {
+		label = ui.LastSegWarnStyle.Render(label)
+	} else {
+		label = ui.FooterDescStyle.Render(label)
+	}
+	return bar.String() + label
+}
+
+// ratioCells splits width cells among values in proportion, by largest
+// remainder, so the cells always add up to width.
+func ratioCells(values []time.Duration, width int) []int {
+	var total time.Duration
+	for _, v := range values {
+		total += v
+	}
+	cells := make([]int, len(values))
+	if total <= 0 {
+		return cells
+	}
+	type rem struct {
+		i int
+		r float64
+	}
+	rems := make([]rem, len(values))
+	used := 0
+	for i, v := range values {
+		exact := float64(v) / float64(total) * float64(width)
+		cells[i] = int(exact)
+		used += cells[i]
+		rems[i] = rem{i, exact - float64(cells[i])}
+	}
+	slices.SortStableFunc(rems, func(a, b rem) int { return cmp.Compare(b.r, a.r) })
+	for k := 0; used < width; k++ {
+		cells[rems[k%len(rems)].i]++
+		used++
+	}
+	return cells
+}
@@ -0,0 +1,142 @@
+package app
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jwulff/steno/internal/daemon"
+	"github.com/jwulff/steno/internal/db"
+)
+
+func talkSegmentEvent(source string, seq int, start, length float64) daemon.Event {
+	end := start + length
+	return daemon.Event{Event: "segment", Text: "words", Source: source, SessionID: "sess-1",
+		SequenceNumber: &seq, StartedAt: &start, EndedAt: &end}
+}
+
+func TestTalkBarBySource(t *testing.T) {
+	m := New()
+	m.connected = true
+	m.width, m.height = 200, 24
+	m.sessionID = "sess-1"
+	if strings.Contains(m.renderFooter(), "MIC") {
+		t.Fatal("no bar before anyone has spoken")
+	}
+
+	m.handleEvent(talkSegmentEvent("microphone", 1, 1710000000, 30))
+	m.handleEvent(talkSegmentEvent("systemAudio", 2, 1710000030, 10))
+	if got := m.renderTalkBar(); !strings.Contains(got, "MIC 75%") || strings.Count(got, "█") != talkBarWidth {
+		t.Errorf("bar = %q, want MIC leading with 75%% over %d cells", got, talkBarWidth)
+	}
+	if !strings.Contains(m.renderFooter(), "MIC 75%") {
+		t.Error("footer should carry the bar")
+	}
+
+	// The store load adds what was said before the TUI opened, without
+	// counting live segments twice.
+	m.applyTalkTime(TalkTimeLoadedMsg{SessionID: "sess-1", Segments: []db.Segment{
+		{SequenceNumber: 1, Source: "microphone", StartedAt: time.Unix(1710000000, 0), EndedAt: time.Unix(1710000030, 0)},
+		{SequenceNumber: 3, Source: "systemAudio", StartedAt: time.Unix(1710000040, 0), EndedAt: time.Unix(1710000060, 0)},
+	}})
+	if got := m.renderTalkBar(); !strings.Contains(got, "MIC 50%") {
+		t.Errorf("after load = %q, want MIC 50%%", got)
+	}
+	if m.talkTimeCmd() != nil {
+		t.Error("loaded session shouldn't be loaded again")
+	}
+
+	// A new session starts from nothing.
+	m.sessionID = "sess-2"
+	if m.renderTalkBar() != "" {
+		t.Error("bar should reset with the session")
+	}
+}
+
+func TestTalkBarFallsBackToWords(t *testing.T) {
+	m := New()
+	m.sessionID = "sess-1"
+	// Daemons that predate endedAt: length estimated from words.
+	m.handleEvent(daemon.Event{Event: "segment", Text: "one two three", Source: "microphone", SessionID: "sess-1"})
+	if got := m.talk.segments[-1].duration; got != 3*wordDuration {
+		t.Errorf("estimated duration = %v, want %v", got, 3*wordDuration)
+	}
+}
+
+func TestTalkBarWarnsOnMonologue(t *testing.T) {
+	m := New()
+	m.sessionID = "sess-1"
+	m.handleEvent(talkSegmentEvent("microphone", 1, 1710000000, 170))
+	m.handleEvent(talkSegmentEvent("systemAudio", 2, 1710000170, 10))
+	if got, want := m.renderTalkBar(), "MIC 94%"; !strings.Contains(got, want) {
+		t.Errorf("bar = %q, want %q", got, want)
+	}
+	if share := m.talkShares()[0]; share.label != "MIC" || share.duration != 170*time.Second {
+		t.Errorf("lead = %+v", share)
+	}
+}
+
+func TestTalkBarBySpeaker(t *testing.T) {
+	c := newManualClock()
+	m := New().WithClock(c)
+	m.sessionID = "sess-1"
+	m.speakerNames = speakerNameState{sessionID: "sess-1", names: map[string]string{"Speaker 1": "Ana"}, settled: true}
+	m.handleEvent(talkSegmentEvent("microphone", 1, 1710000000, 30))
+
+	speak := func(label string, d time.Duration) {
+		m.handleEvent(daemon.Event{Event: "speaker", Speaker: label, Source: "systemAudio"})
+		c.advance(d)
+	}
+	speak("Speaker 1", 2*time.Second)
+	speak("Speaker 1", 2*time.Second)
+	speak("Speaker 2", 2*time.Second)
+	// A gap longer than speakerTTL isn't counted as talking.
+	speak("Speaker 2", time.Minute)
+	speak("", 0)
+
+	shares := m.talkShares()
+	labels := make([]string, len(shares))
+	for i, s := range shares {
+		labels[i] = s.label
+	}
+	if !slices.Equal(labels, []string{"Ana", "Speaker 2"}) || shares[0].duration != 4*time.Second || shares[1].duration != 2*time.Second {
+		t.Errorf("shares = %+v, want Ana 4s then Speaker 2 2s", shares)
+	}
+	if got := m.renderTalkBar(); !strings.Contains(got, "Ana 67%") {
+		t.Errorf("bar = %q, want Ana 67%%", got)
+	}
+}
+
+func TestTalkBarBySegmentSpeakers(t *testing.T) {
+	m := New()
+	m.sessionID = "sess-1"
+	for i, label := range []string{"Speaker 1", "Speaker 2", "Speaker 1"} {
+		ev := talkSegmentEvent("systemAudio", i+1, 1710000000+float64(i)*10, 10)
+		ev.Speaker = label
+		m.handleEvent(ev)
+	}
+	m.applyTalkTime(TalkTimeLoadedMsg{SessionID: "sess-1", Segments: []db.Segment{
+		{SequenceNumber: 1, Source: "systemAudio", Speaker: "Speaker 1", StartedAt: time.Unix(1710000000, 0), EndedAt: time.Unix(1710000010, 0)},
+		{SequenceNumber: 4, Source: "systemAudio", Speaker: "Speaker 2", StartedAt: time.Unix(1710000030, 0), EndedAt: time.Unix(1710000040, 0)},
+	}})
+	if got := m.renderTalkBar(); !strings.Contains(got, "Speaker 1 50%") {
+		t.Errorf("bar = %q, want Speaker 1 50%%", got)
+	}
+}
+
+func TestRatioCells(t *testing.T) {
+	for _, tc := range []struct {
+		values []time.Duration
+		want   []int
+	}{
+		{[]time.Duration{3, 1}, []int{8, 2}},
+		{[]time.Duration{1, 1, 1}, []int{4, 3, 3}},
+		{[]time.Duration{100, 1}, []int{10, 0}},
+		{[]time.Duration{0, 0}, []int{0, 0}},
+	} {
+		if got := ratioCells(tc.values, 10); !slices.Equal(got, tc.want) {
+			t.Errorf("ratioCells(%v) = %v, want %v", tc.values, got, tc.want)
+		}
+	}
+}
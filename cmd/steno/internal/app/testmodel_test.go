@@ -0,0 +1,38 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/jwulff/steno/internal/db"
+)
+
+// testOption adjusts the Model testModel returns.
+type testOption func(*Model)
+
+// testModel returns a Model to drive through Update: 120x30, connected,
+// and with a stubStore. opts adjust it further.
+func testModel(t *testing.T, opts ...testOption) Model {
+	t.Helper()
+	m := New()
+	m.width, m.height = 120, 30
+	m.connected = true
+	m.store = stubStore()
+	for _, opt := range opts {
+		opt(&m)
+	}
+	return m
+}
+
+// stubStore is a store for tests that never query it: they check the
+// cmds an update returns without running them.
+func stubStore() *db.Store {
+	return &db.Store{}
+}
+
+func withSize(width, height int) testOption {
+	return func(m *Model) { m.width, m.height = width, height }
+}
+
+func withSession(id string) testOption {
+	return func(m *Model) { m.sessionID = id }
+}
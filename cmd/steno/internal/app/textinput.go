@@ -0,0 +1,42 @@
+package app
+
+import tea "github.com/charmbracelet/bubbletea"
+
+// textInput is a one-line query being typed: the quick switcher's and
+// the transcript search prompt's. It takes text, space, backspace and
+// ctrl+u; every other key is left to the caller.
+type textInput struct {
+	value []rune
+}
+
+// handle applies msg and reports whether it was an editing key.
+func (t *textInput) handle(msg tea.KeyMsg) bool {
+	switch msg.String() {
+	case "backspace":
+		if len(t.value) > 0 {
+			t.value = t.value[:len(t.value)-1]
+		}
+		return true
+	case "ctrl+u":
+		t.value = nil
+		return true
+	}
+	switch msg.Type {
+	case tea.KeySpace:
+		t.value = append(t.value, ' ')
+		return true
+	case tea.KeyRunes:
+		t.value = append(t.value, msg.Runes...)
+		return true
+	}
+	return false
+}
+
+func (t textInput) String() string {
+	return string(t.value)
+}
+
+// view renders the input after prompt, with a cursor.
+func (t textInput) view(prompt string) string {
+	return prompt + string(t.value) + "▌"
+}
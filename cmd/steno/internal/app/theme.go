@@ -0,0 +1,27 @@
+package app
+
+import (
+	"slices"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jwulff/steno/internal/ui"
+)
+
+// cycleTheme switches to the next palette (H): the presets, then the
+// custom ones in config display.palettes. It lasts until the TUI exits
+// or the config's palette changes; display.palette sets the one to start
+// with. With color off there is nothing to switch.
+func (m Model) cycleTheme() (tea.Model, tea.Cmd) {
+	current := ui.PaletteName()
+	if current == "" {
+		m.notice = "Color is off (--no-color, NO_COLOR or display.no_color)"
+		return m, m.clearNoticeCmd()
+	}
+	names := ui.PaletteNames()
+	next := names[(slices.Index(names, current)+1)%len(names)]
+	ui.UsePalette(next)
+	// Lines already laid out carry the old palette's colors.
+	m.segLines = newSegmentLines()
+	m.notice = "Theme: " + next
+	return m, m.clearNoticeCmd()
+}
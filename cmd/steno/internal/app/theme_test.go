@@ -0,0 +1,45 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jwulff/steno/internal/ui"
+)
+
+func TestCycleTheme(t *testing.T) {
+	ui.DefinePalettes(map[string]ui.Palette{"mine": {Red: "#AA0000", Speakers: make([]lipgloss.Color, len(ui.SpeakerGlyphs))}})
+	t.Cleanup(func() {
+		ui.DefinePalettes(nil)
+		ui.UsePalette(ui.DefaultPalette)
+	})
+	ui.UsePalette(ui.DefaultPalette)
+
+	m := New()
+	var seen []string
+	for range ui.PaletteNames() {
+		m, _ = applyUpdate(m, runeKey('H'))
+		seen = append(seen, ui.PaletteName())
+		if m.notice != "Theme: "+ui.PaletteName() {
+			t.Errorf("notice = %q", m.notice)
+		}
+	}
+	want := []string{ui.DarkPalette, ui.LightPalette, ui.DeuteranopiaPalette, ui.ProtanopiaPalette, "mine", ui.DefaultPalette}
+	if len(seen) != len(want) {
+		t.Fatalf("cycled %v, want %v", seen, want)
+	}
+	for i := range want {
+		if seen[i] != want[i] {
+			t.Fatalf("cycled %v, want %v", seen, want)
+		}
+	}
+	if ui.SymbolCues {
+		t.Error("the default palette needs no symbol cues")
+	}
+
+	ui.DisableColor()
+	m, _ = applyUpdate(m, runeKey('H'))
+	if ui.PaletteName() != "" || m.notice == "" {
+		t.Errorf("with color off, H should only explain: palette %q, notice %q", ui.PaletteName(), m.notice)
+	}
+}
@@ -0,0 +1,71 @@
+package app
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jwulff/steno/internal/ui"
+)
+
+// The summary ticker (T, config display.summary_ticker) is a line under
+// the transcript header holding the last sentence of the session's
+// latest rolling summary, so a glance at the screen says what the
+// meeting is on without reading back. The daemon writes a summary in the
+// same pass that emits `topics`, so each topics event reloads it.
+
+// toggleTicker shows or hides the ticker, loading the summary to show.
+func (m Model) toggleTicker() (tea.Model, tea.Cmd) {
+	m.ticker = !m.ticker
+	return m, m.summaryCmd()
+}
+
+// summaryCmd reloads the latest summary while the summary view or the
+// ticker shows it; nil otherwise or without a store.
+func (m Model) summaryCmd() tea.Cmd {
+	if (!m.showSummary && !m.ticker) || m.store == nil || m.sessionID == "" {
+		return nil
+	}
+	return loadSummaryCmd(m.store, m.sessionID)
+}
+
+// showTicker reports whether the transcript panel has a ticker line: on,
+// with a summary, and not already showing the whole summary.
+func (m Model) showTicker() bool {
+	return m.ticker && !m.showSummary && tickerSentence(m.summaryText) != ""
+}
+
+// transcriptHeaderLines is how many lines the transcript panel's header
+// takes from its height.
+func (m Model) transcriptHeaderLines() int {
+	n := 1
+	if m.showTicker() {
+		n++
+	}
+	if m.showSpeakerLegend() {
+		n++
+	}
+	return n
+}
+
+// renderTicker renders the ticker line for a panel width wide.
+func (m Model) renderTicker(width int) string {
+	return truncateToWidth("  "+ui.MagentaStyle.Render("» ")+ui.DimStyle.Render(m.scrubber.Apply(tickerSentence(m.summaryText))), width)
+}
+
+// tickerSentence is the last sentence of a summary: its newest point.
+// List markers are dropped, and a summary without sentence punctuation
+// is taken whole.
+func tickerSentence(summary string) string {
+	lines := strings.Split(strings.TrimSpace(summary), "\n")
+	last := strings.TrimSpace(lines[len(lines)-1])
+	last = strings.TrimSpace(strings.TrimLeft(last, "-*•"))
+	body := strings.TrimRight(last, ".!?")
+	cut := -1
+	for _, sep := range []string{". ", "! ", "? "} {
+		cut = max(cut, strings.LastIndex(body, sep))
+	}
+	if cut >= 0 {
+		last = strings.TrimSpace(last[cut+2:])
+	}
+	return last
+}
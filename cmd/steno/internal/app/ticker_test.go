@@ -0,0 +1,72 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jwulff/steno/internal/config"
+	"github.com/jwulff/steno/internal/daemon"
+)
+
+func TestTickerSentence(t *testing.T) {
+	for _, tc := range []struct{ summary, want string }{
+		{"", ""},
+		{"The team reviewed Q3. Hiring is paused until March.", "Hiring is paused until March."},
+		{"Budget is up 3.5% on last year", "Budget is up 3.5% on last year"},
+		{"Who owns the rollout? Ana does!", "Ana does!"},
+		{"- Reviewed the launch plan.\n- Agreed to ship Friday.\n", "Agreed to ship Friday."},
+	} {
+		if got := tickerSentence(tc.summary); got != tc.want {
+			t.Errorf("tickerSentence(%q) = %q, want %q", tc.summary, got, tc.want)
+		}
+	}
+}
+
+func TestTickerUnderTranscriptHeader(t *testing.T) {
+	m := densityModel(DensityCompact)
+	m.summaryText = "We covered the roadmap. Next up is the hiring plan."
+	if strings.Contains(m.View(), "hiring plan") {
+		t.Fatal("ticker should be off by default")
+	}
+
+	m.store = stubStore()
+	m.sessionID = "s1"
+	m, cmd := applyUpdate(m, runeKey('T'))
+	if !m.ticker || cmd == nil {
+		t.Fatal("T should turn the ticker on and load the summary")
+	}
+	panel := strings.Split(m.renderTranscriptPanel(m.transcriptPanelWidth(), 20), "\n")
+	if !strings.Contains(panel[1], "Next up is the hiring plan.") || strings.Contains(panel[1], "roadmap") {
+		t.Errorf("ticker line = %q, want the last sentence only", panel[1])
+	}
+	if len(panel) != 20 || !strings.Contains(panel[2], "first from mic") {
+		t.Errorf("panel = %q, want the transcript below the ticker", panel)
+	}
+
+	// The full summary view replaces it.
+	m.showSummary = true
+	if m.showTicker() {
+		t.Error("ticker should hide while the summary view is open")
+	}
+}
+
+func TestTickerReloadsOnTopics(t *testing.T) {
+	m := NewWithConfig(config.Config{Display: config.DisplayConfig{SummaryTicker: true}})
+	if cmd := m.summaryCmd(); cmd != nil {
+		t.Error("no summary to load without a store")
+	}
+	m.store = stubStore()
+	m.sessionID = "s1"
+	if m.summaryCmd() == nil {
+		t.Fatal("display.summary_ticker should load the summary")
+	}
+	if cmd := m.handleEvent(daemon.Event{Event: "topics", Topics: []daemon.EventTopic{{ID: "t1", Title: "Hiring"}}}); cmd == nil {
+		t.Error("a topics event should reload the summary")
+	}
+
+	m.summaryText = "Old session's summary."
+	m, _ = applyUpdate(m, DemarcateResponseMsg{Response: daemon.Response{OK: true, SessionID: "s2"}})
+	if m.summaryText != "" {
+		t.Errorf("summaryText = %q after a boundary, want it cleared", m.summaryText)
+	}
+}
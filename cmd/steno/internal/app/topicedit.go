@@ -0,0 +1,127 @@
+package app
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jwulff/steno/internal/db"
+	"github.com/jwulff/steno/internal/ui"
+)
+
+// topicEditor is the inline title/summary editor opened with `r` on the
+// selected topic. Edits are saved to the client-owned topic_edits table,
+// which marks the topic user-edited so LLM regeneration leaves it alone.
+type topicEditor struct {
+	topicID string
+	title   []rune
+	summary []rune
+	// onSummary is true while the summary field has the cursor.
+	onSummary bool
+}
+
+func (e *topicEditor) field() *[]rune {
+	if e.onSummary {
+		return &e.summary
+	}
+	return &e.title
+}
+
+// editTopicCmd persists a topic edit.
+func editTopicCmd(store *db.Store, topicID, title, summary string) tea.Cmd {
+	return func() tea.Msg {
+		err := store.EditTopic(topicID, title, summary)
+		return TopicEditedMsg{ID: topicID, Title: strings.TrimSpace(title), Summary: strings.TrimSpace(summary), Err: err}
+	}
+}
+
+// openTopicEditor starts editing the selected topic. Without a store
+// there is nowhere to save, so the key does nothing.
+func (m Model) openTopicEditor() (tea.Model, tea.Cmd) {
+	if m.focusedPanel != FocusTopics || m.selectedTopic >= len(m.topics) || m.store == nil {
+		return m, nil
+	}
+	t := m.topics[m.selectedTopic]
+	m.topicEdit = &topicEditor{
+		topicID: t.ID,
+		title:   []rune(t.Title),
+		summary: []rune(t.Summary),
+	}
+	return m, nil
+}
+
+// handleTopicEditKey handles keys while the topic editor is open: text
+// goes into the focused field, tab switches fields, enter moves from
+// title to summary and then saves, esc cancels.
+func (m Model) handleTopicEditKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	e := *m.topicEdit
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return m.quit()
+	case tea.KeyEsc:
+		m.topicEdit = nil
+		return m, nil
+	case tea.KeyTab:
+		e.onSummary = !e.onSummary
+	case tea.KeyEnter:
+		if !e.onSummary {
+			e.onSummary = true
+			break
+		}
+		m.topicEdit = nil
+		return m, editTopicCmd(m.store, e.topicID, string(e.title), string(e.summary))
+	case tea.KeyBackspace:
+		f := e.field()
+		if len(*f) > 0 {
+			*f = (*f)[:len(*f)-1]
+		}
+	case tea.KeyCtrlU:
+		*e.field() = nil
+	case tea.KeySpace:
+		*e.field() = append(*e.field(), ' ')
+	case tea.KeyRunes:
+		*e.field() = append(*e.field(), msg.Runes...)
+	}
+	m.topicEdit = &e
+	return m, nil
+}
+
+// applyTopicEdit reflects a saved edit in the topics panel.
+func (m Model) applyTopicEdit(msg TopicEditedMsg) (tea.Model, tea.Cmd) {
+	if msg.Err != nil {
+		return m, m.pushError(SeverityError, "edit topic: "+msg.Err.Error(), true)
+	}
+	for i := range m.topics {
+		if m.topics[i].ID == msg.ID {
+			m.topics[i].Title = msg.Title
+			m.topics[i].Summary = msg.Summary
+			m.topics[i].UserEdited = true
+		}
+	}
+	m.shareTopics()
+	return m, nil
+}
+
+// renderTopicEditor renders the editor in place of the topic list.
+func (m Model) renderTopicEditor(width int) []string {
+	e := m.topicEdit
+	textWidth := max(10, width-4)
+	field := func(label string, value []rune, active bool) []string {
+		text := string(value)
+		if active {
+			text += "▌"
+		}
+		style := ui.DimStyle
+		if active {
+			style = ui.SelectedStyle
+		}
+		lines := []string{style.Render("  " + label)}
+		for _, wl := range wrapText(text, textWidth) {
+			lines = append(lines, "    "+wl)
+		}
+		return lines
+	}
+	lines := field("Title", e.title, !e.onSummary)
+	lines = append(lines, field("Summary", e.summary, e.onSummary)...)
+	lines = append(lines, "", ui.DimStyle.Render("  tab field · enter next/save · esc cancel"))
+	return lines
+}
@@ -0,0 +1,94 @@
+package app
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func topicEditModel(t *testing.T) Model {
+	m := testModel(t)
+	m.focusedPanel = FocusTopics
+	m.topics = []TopicDisplay{
+		{ID: "t1", Title: "Planning", Summary: "Sprint goals"},
+		{ID: "t2", Title: "Review", Summary: "Auth module"},
+	}
+	m.selectedTopic = 1
+	return m
+}
+
+func TestTopicEditorTypesAndSaves(t *testing.T) {
+	m := topicEditModel(t)
+	m, _ = applyUpdate(m, runeKey('r'))
+	if m.topicEdit == nil || m.topicEdit.topicID != "t2" {
+		t.Fatalf("r should open the editor on the selected topic, got %+v", m.topicEdit)
+	}
+
+	// Keys that are bindings elsewhere are text here.
+	m, _ = applyUpdate(m, tea.KeyMsg{Type: tea.KeyBackspace})
+	m, _ = applyUpdate(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("ws")})
+	m, _ = applyUpdate(m, tea.KeyMsg{Type: tea.KeySpace})
+	m, _ = applyUpdate(m, runeKey('q'))
+	if got := string(m.topicEdit.title); got != "Reviews q" {
+		t.Errorf("title = %q", got)
+	}
+	if !strings.Contains(m.View(), "Reviews q▌") {
+		t.Error("editor should render the title being typed")
+	}
+
+	// enter moves to the summary, ctrl+u clears it, enter saves.
+	m, _ = applyUpdate(m, tea.KeyMsg{Type: tea.KeyEnter})
+	m, _ = applyUpdate(m, tea.KeyMsg{Type: tea.KeyCtrlU})
+	m, _ = applyUpdate(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("Token refresh")})
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(Model)
+	if m.topicEdit != nil || cmd == nil {
+		t.Fatal("enter on the summary should close the editor and save")
+	}
+
+	m, _ = applyUpdate(m, TopicEditedMsg{ID: "t2", Title: "Reviews q", Summary: "Token refresh"})
+	got := m.topics[1]
+	if got.Title != "Reviews q" || got.Summary != "Token refresh" || !got.UserEdited {
+		t.Errorf("topic after save = %+v", got)
+	}
+	if !strings.Contains(m.View(), "Reviews q ✎") {
+		t.Error("edited topics should be marked")
+	}
+}
+
+func TestTopicEditorCancel(t *testing.T) {
+	m := topicEditModel(t)
+	m, _ = applyUpdate(m, runeKey('r'))
+	m, _ = applyUpdate(m, runeKey('x'))
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = updated.(Model)
+	if m.topicEdit != nil || cmd != nil {
+		t.Error("esc should close the editor without saving")
+	}
+	if m.topics[1].Title != "Review" {
+		t.Errorf("title = %q after cancel", m.topics[1].Title)
+	}
+}
+
+func TestTopicEditorNeedsTopicsFocusAndStore(t *testing.T) {
+	m := topicEditModel(t)
+	m.focusedPanel = FocusTranscript
+	if m, _ = applyUpdate(m, runeKey('r')); m.topicEdit != nil {
+		t.Error("r with the transcript focused should do nothing")
+	}
+	m = topicEditModel(t)
+	m.store = nil
+	if m, _ = applyUpdate(m, runeKey('r')); m.topicEdit != nil {
+		t.Error("r without a store should do nothing")
+	}
+}
+
+func TestTopicEditedErrorSurfaces(t *testing.T) {
+	m := topicEditModel(t)
+	m, _ = applyUpdate(m, TopicEditedMsg{ID: "t2", Err: errors.New("attempt to write a readonly database")})
+	if !strings.Contains(m.errorMessage(), "readonly") || m.topics[1].UserEdited {
+		t.Errorf("errorMessage = %q, topic = %+v", m.errorMessage(), m.topics[1])
+	}
+}
@@ -0,0 +1,62 @@
+package app
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jwulff/steno/internal/ui"
+)
+
+// Topic rules mark where each of the current session's topics begins in
+// the transcript, a dim rule carrying the topic's title ahead of its
+// first segment, so the meeting's structure shows without looking at the
+// topics panel. They come from the topics' segment ranges, so they move
+// as the daemon regroups topics on each `topics` event.
+
+// topicStart is a topic's first sequence number and its title.
+type topicStart struct {
+	seq   int
+	title string
+}
+
+// topicStarts lists the topics by their first segment.
+func (m Model) topicStarts() []topicStart {
+	starts := make([]topicStart, 0, len(m.topics))
+	for _, t := range m.topics {
+		starts = append(starts, topicStart{seq: t.SegmentRangeStart, title: t.Title})
+	}
+	sort.SliceStable(starts, func(i, j int) bool { return starts[i].seq < starts[j].seq })
+	return starts
+}
+
+// topicRules hands out the rules for one layout pass, in transcript
+// order.
+type topicRules struct {
+	starts []topicStart
+	next   int
+}
+
+// before returns the title of the topic beginning at or before seq that
+// has no rule yet, if any. A topic whose first segments aren't laid out
+// (filtered away, or not loaded) gets its rule at the next one; of two
+// topics passed at once, only the later one, which the segment is in.
+func (r *topicRules) before(seq int) (string, bool) {
+	title, ok := "", false
+	for r.next < len(r.starts) && r.starts[r.next].seq <= seq {
+		title, ok = r.starts[r.next].title, true
+		r.next++
+	}
+	return title, ok
+}
+
+// renderTopicRule draws a topic rule width cells wide, as
+// `── Title ─────`: left-aligned, where the session boundary's label is
+// centered, so the two read apart.
+func renderTopicRule(title string, width int) string {
+	rule := "── " + title + " "
+	if pad := width - lipgloss.Width(rule); pad > 0 {
+		rule += strings.Repeat("─", pad)
+	}
+	return ui.DimStyle.Render(truncateToWidth(rule, width))
+}
@@ -0,0 +1,41 @@
+package app
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTopicRulesInTranscript(t *testing.T) {
+	m := New()
+	m.sessionID = "s1"
+	start := time.Unix(1700000000, 0)
+	m.entries = []TranscriptEntry{
+		{Text: "earlier meeting", Timestamp: start, SeqNum: 1, SessionID: "s0"},
+		{Text: "hello all", Timestamp: start, SeqNum: 1, SessionID: "s1"},
+		{Text: "budget is tight", Timestamp: start.Add(time.Minute), SeqNum: 2, SessionID: "s1"},
+		{Text: "hiring plan", Timestamp: start.Add(2 * time.Minute), SeqNum: 3, SessionID: "s1"},
+	}
+	// Topics arrive out of order; rules follow the segment ranges.
+	m.topics = []TopicDisplay{
+		{ID: "b", Title: "Hiring", SegmentRangeStart: 3, SegmentRangeEnd: 3},
+		{ID: "a", Title: "Budget", SegmentRangeStart: 1, SegmentRangeEnd: 2},
+	}
+	lines, _ := m.transcriptDisplayLines(80)
+	text := strings.Join(lines, "\n")
+	budget, hiring := strings.Index(text, "── Budget ─"), strings.Index(text, "── Hiring ─")
+	if budget < strings.Index(text, "earlier meeting") || budget > strings.Index(text, "hello all") {
+		t.Errorf("the Budget rule should lead the session's first segment, not the earlier session's:\n%s", text)
+	}
+	if hiring < strings.Index(text, "budget is tight") || hiring > strings.Index(text, "hiring plan") {
+		t.Errorf("the Hiring rule should lead segment 3:\n%s", text)
+	}
+
+	// A regrouped topic list moves the rules.
+	m.topics = []TopicDisplay{{ID: "a", Title: "Budget and hiring", SegmentRangeStart: 2, SegmentRangeEnd: 3}}
+	lines, _ = m.transcriptDisplayLines(80)
+	text = strings.Join(lines, "\n")
+	if strings.Contains(text, "Hiring ─") || strings.Index(text, "── Budget and hiring ─") < strings.Index(text, "hello all") {
+		t.Errorf("after regrouping:\n%s", text)
+	}
+}
@@ -0,0 +1,118 @@
+package app
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jwulff/steno/internal/daemon"
+)
+
+// reconcileTopics merges incoming, the session's full topic list in
+// order, into old. Topics are matched by ID: a match keeps its expansion
+// and, if its segment range is unchanged, its loaded segments. Everything
+// else comes from incoming, so the result has exactly incoming's topics
+// in incoming's order.
+func reconcileTopics(old []TopicDisplay, incoming []TopicLoaded) []TopicDisplay {
+	prev := make(map[string]TopicDisplay, len(old))
+	for _, t := range old {
+		prev[t.ID] = t
+	}
+	out := make([]TopicDisplay, 0, len(incoming))
+	for _, in := range incoming {
+		t := TopicDisplay{
+			ID:                in.ID,
+			Title:             in.Title,
+			Summary:           in.Summary,
+			SegmentRangeStart: in.SegmentRangeStart,
+			SegmentRangeEnd:   in.SegmentRangeEnd,
+			UserEdited:        in.UserEdited,
+		}
+		if p, ok := prev[in.ID]; ok {
+			t.Expanded = p.Expanded
+			if p.SegmentRangeStart == in.SegmentRangeStart && p.SegmentRangeEnd == in.SegmentRangeEnd {
+				t.Segments = p.Segments
+			}
+		}
+		out = append(out, t)
+	}
+	return out
+}
+
+// retargetTopic finds where the topic at index i of old sits in next: the
+// same ID if it survived, else the topic covering its first segment, else
+// i clamped to next. Used to keep the selection and scroll anchor on the
+// same topic across a reload.
+func retargetTopic(old, next []TopicDisplay, i int) int {
+	if len(next) == 0 {
+		return 0
+	}
+	if i < 0 || i >= len(old) {
+		return min(max(i, 0), len(next)-1)
+	}
+	want := old[i]
+	for j, t := range next {
+		if t.ID == want.ID {
+			return j
+		}
+	}
+	for j, t := range next {
+		if t.SegmentRangeStart <= want.SegmentRangeStart && want.SegmentRangeStart <= t.SegmentRangeEnd {
+			return j
+		}
+	}
+	return min(i, len(next)-1)
+}
+
+// applyTopics replaces the topic panel with incoming without disturbing
+// it: selection, scroll and expanded topics carry over, and topics the
+// session's view prefs left unfolded unfold. Expanded topics whose
+// segments were dropped (their range changed) are reloaded.
+func (m *Model) applyTopics(incoming []TopicLoaded) tea.Cmd {
+	next := reconcileTopics(m.topics, incoming)
+	m.selectedTopic = retargetTopic(m.topics, next, m.selectedTopic)
+	m.topicScroll = retargetTopic(m.topics, next, m.topicScroll)
+	m.topics = next
+	m.expandSavedTopics()
+	m.shareTopics()
+	return m.expandedTopicSegmentsCmd()
+}
+
+// expandedTopicSegmentsCmd loads the segments of expanded topics that
+// have none loaded.
+func (m Model) expandedTopicSegmentsCmd() tea.Cmd {
+	if m.store == nil || m.sessionID == "" {
+		return nil
+	}
+	var cmds []tea.Cmd
+	for _, t := range m.topics {
+		if t.Expanded && t.Segments == nil {
+			cmds = append(cmds, loadTopicSegmentsCmd(m.store, m.sessionID, t.ID, t.SegmentRangeStart, t.SegmentRangeEnd))
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+// topicsFromEvent converts a `topics` event payload. The daemon doesn't
+// know about client-side edits, so topics the user edited keep their
+// edited title and summary.
+func (m Model) topicsFromEvent(topics []daemon.EventTopic) []TopicLoaded {
+	edited := map[string]TopicDisplay{}
+	for _, t := range m.topics {
+		if t.UserEdited {
+			edited[t.ID] = t
+		}
+	}
+	out := make([]TopicLoaded, 0, len(topics))
+	for _, t := range topics {
+		in := TopicLoaded{
+			ID:                t.ID,
+			Title:             t.Title,
+			Summary:           t.Summary,
+			SegmentRangeStart: t.SegmentRangeStart,
+			SegmentRangeEnd:   t.SegmentRangeEnd,
+		}
+		if e, ok := edited[t.ID]; ok {
+			in.Title, in.Summary, in.UserEdited = e.Title, e.Summary, true
+		}
+		out = append(out, in)
+	}
+	return out
+}
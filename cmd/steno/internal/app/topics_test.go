@@ -0,0 +1,168 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/quick"
+
+	"github.com/jwulff/steno/internal/daemon"
+)
+
+// topicScenario is a topic panel before a reload and the list the reload
+// brings: some old topics survive (possibly with a changed range), some
+// are gone, some are new.
+type topicScenario struct {
+	Old      []TopicDisplay
+	Selected int
+	Incoming []TopicLoaded
+}
+
+func (topicScenario) Generate(r *rand.Rand, size int) reflect.Value {
+	var s topicScenario
+	n := r.Intn(size + 1)
+	for i := range n {
+		t := TopicDisplay{
+			ID:                fmt.Sprintf("old-%d", i),
+			Title:             fmt.Sprintf("Topic %d", i),
+			SegmentRangeStart: i * 10,
+			SegmentRangeEnd:   i*10 + 9,
+			Expanded:          r.Intn(2) == 0,
+		}
+		if t.Expanded && r.Intn(2) == 0 {
+			t.Segments = []TopicSegment{{Text: "said", SeqNum: i * 10}}
+		}
+		s.Old = append(s.Old, t)
+	}
+	s.Selected = r.Intn(n + 1)
+	for _, t := range s.Old {
+		if r.Intn(4) == 0 {
+			continue // dropped
+		}
+		in := TopicLoaded{ID: t.ID, Title: t.Title + "'", SegmentRangeStart: t.SegmentRangeStart, SegmentRangeEnd: t.SegmentRangeEnd}
+		if r.Intn(4) == 0 {
+			in.SegmentRangeEnd += 5
+		}
+		s.Incoming = append(s.Incoming, in)
+	}
+	for i := range r.Intn(3) {
+		start := (n + i) * 10
+		s.Incoming = append(s.Incoming, TopicLoaded{ID: fmt.Sprintf("new-%d", i), Title: "New", SegmentRangeStart: start, SegmentRangeEnd: start + 9})
+	}
+	return reflect.ValueOf(s)
+}
+
+func (s topicScenario) apply() Model {
+	m := New()
+	m.topics = append([]TopicDisplay(nil), s.Old...)
+	m.selectedTopic = s.Selected
+	m.applyTopics(s.Incoming)
+	return m
+}
+
+func TestReconcileTopicsProperties(t *testing.T) {
+	prop := func(s topicScenario) bool {
+		m := s.apply()
+		old := map[string]TopicDisplay{}
+		for _, t := range s.Old {
+			old[t.ID] = t
+		}
+
+		// The result is exactly the incoming list.
+		if len(m.topics) != len(s.Incoming) {
+			return false
+		}
+		for i, in := range s.Incoming {
+			got := m.topics[i]
+			if got.ID != in.ID || got.Title != in.Title || got.SegmentRangeStart != in.SegmentRangeStart || got.SegmentRangeEnd != in.SegmentRangeEnd {
+				return false
+			}
+			// Expansion survives; segments only while still accurate.
+			prev, ok := old[in.ID]
+			if got.Expanded != (ok && prev.Expanded) {
+				return false
+			}
+			if got.Segments != nil && (!ok || prev.SegmentRangeEnd != in.SegmentRangeEnd) {
+				return false
+			}
+		}
+
+		// Selection stays in range and on the same topic if it survived.
+		if m.selectedTopic < 0 || m.selectedTopic > max(0, len(m.topics)-1) {
+			return false
+		}
+		if s.Selected < len(s.Old) {
+			want := s.Old[s.Selected].ID
+			for _, in := range s.Incoming {
+				if in.ID == want && m.topics[m.selectedTopic].ID != want {
+					return false
+				}
+			}
+		}
+
+		// Reapplying the same list changes nothing.
+		again := m
+		again.topics = append([]TopicDisplay(nil), m.topics...)
+		again.applyTopics(s.Incoming)
+		return reflect.DeepEqual(again.topics, m.topics) && again.selectedTopic == m.selectedTopic
+	}
+	if err := quick.Check(prop, &quick.Config{MaxCount: 500}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestTopicsLoadedKeepsExpansion(t *testing.T) {
+	m := New()
+	m.focusedPanel = FocusTopics
+	m, _ = applyUpdate(m, TopicsLoadedMsg{Topics: []TopicLoaded{{ID: "a", Title: "Budget"}, {ID: "b", Title: "Hiring"}}})
+	m, _ = applyUpdate(m, runeKey('j'))
+	m.topics[1].Expanded = true // as enter does; no store to load segments
+
+	// A new topic arrives ahead of the selection.
+	m, _ = applyUpdate(m, TopicsLoadedMsg{Topics: []TopicLoaded{{ID: "z", Title: "Intro"}, {ID: "a", Title: "Budget"}, {ID: "b", Title: "Hiring"}}})
+	if m.topics[m.selectedTopic].ID != "b" || !m.topics[2].Expanded {
+		t.Errorf("selected %d (%s), expanded %v; want b still selected and expanded", m.selectedTopic, m.topics[m.selectedTopic].ID, m.topics[2].Expanded)
+	}
+}
+
+func TestTopicsReloadKeepsOldTopics(t *testing.T) {
+	m := testModel(t, withSession("s1"))
+	m, _ = applyUpdate(m, TopicsLoadedMsg{Topics: []TopicLoaded{{ID: "a", Title: "Budget"}}})
+
+	m.handleEvent(daemon.Event{Event: "topics"})
+	if !m.topicsLoading {
+		t.Fatal("a topics signal should mark the panel loading")
+	}
+	panel := m.renderTopicPanel(40, 10)
+	if !strings.Contains(panel, "Loading topics") || !strings.Contains(panel, "Budget") {
+		t.Errorf("panel while loading:\n%s", panel)
+	}
+
+	// A failed load keeps the old list and says so.
+	m, _ = applyUpdate(m, TopicsLoadedMsg{Err: errors.New("database is locked")})
+	if m.topicsLoading || len(m.topics) != 1 || m.topics[0].Title != "Budget" {
+		t.Errorf("after failed load: loading %v, topics %+v", m.topicsLoading, m.topics)
+	}
+	if len(m.errorHistory) != 1 || !strings.Contains(m.errorHistory[0].Message, "database is locked") {
+		t.Errorf("errorHistory = %+v", m.errorHistory)
+	}
+}
+
+func TestTopicsEventAppliesPayload(t *testing.T) {
+	m := New()
+	m.topics = []TopicDisplay{{ID: "a", Title: "My title", Summary: "Mine.", UserEdited: true, Expanded: true}}
+
+	m.handleEvent(daemon.Event{Event: "topics", Topics: []daemon.EventTopic{
+		{ID: "a", Title: "LLM title", Summary: "LLM.", SegmentRangeStart: 1, SegmentRangeEnd: 3},
+		{ID: "b", Title: "Hiring", SegmentRangeStart: 4, SegmentRangeEnd: 6},
+	}})
+	if len(m.topics) != 2 || m.topics[1].Title != "Hiring" {
+		t.Fatalf("topics = %+v", m.topics)
+	}
+	if a := m.topics[0]; a.Title != "My title" || !a.UserEdited || !a.Expanded || a.SegmentRangeEnd != 3 {
+		t.Errorf("edited topic = %+v, want the edit and expansion kept", a)
+	}
+}
@@ -0,0 +1,270 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jwulff/steno/internal/daemon"
+	"github.com/jwulff/steno/internal/db"
+	"github.com/jwulff/steno/internal/export"
+	"github.com/jwulff/steno/internal/ui"
+)
+
+// tutorialSessionID stands in for the daemon's session id.
+const tutorialSessionID = "tutorial"
+
+// tutorialLine is one segment of the canned meeting, at an offset from
+// its start.
+type tutorialLine struct {
+	at, length time.Duration
+	source     string
+	speaker    string
+	text       string
+}
+
+// tutorialScript is the canned meeting the tutorial plays.
+var tutorialScript = []tutorialLine{
+	{0, 4 * time.Second, "microphone", "", "Okay, let's get started. This is the launch check-in for the beta."},
+	{5 * time.Second, 4 * time.Second, "systemAudio", "Speaker 1", "Thanks. The build went out to the first fifty testers on Monday."},
+	{10 * time.Second, 4 * time.Second, "systemAudio", "Speaker 1", "Crash reports are down to two, both on the same older laptop."},
+	{15 * time.Second, 3 * time.Second, "microphone", "", "Good. Can we fix that before we widen the beta?"},
+	{19 * time.Second, 4 * time.Second, "systemAudio", "Speaker 2", "I can take it. I think it's the audio device switching on wake."},
+	{24 * time.Second, 5 * time.Second, "microphone", "", "Let's say Thursday for the fix, then open the beta to everyone on Monday."},
+	{30 * time.Second, 4 * time.Second, "systemAudio", "Speaker 1", "Works for me. I'll draft the announcement email this week."},
+	{35 * time.Second, 4 * time.Second, "microphone", "", "Next, pricing. We still haven't settled between the two plans."},
+	{40 * time.Second, 4 * time.Second, "systemAudio", "Speaker 2", "The survey leaned toward one plan at nine dollars a month."},
+	{45 * time.Second, 4 * time.Second, "systemAudio", "Speaker 1", "Annual billing at ninety a year would settle the discount question."},
+	{50 * time.Second, 4 * time.Second, "microphone", "", "Then it's nine a month or ninety a year. That's decided."},
+	{55 * time.Second, 3 * time.Second, "systemAudio", "Speaker 2", "I'll update the pricing page and the checkout copy."},
+	{59 * time.Second, 4 * time.Second, "microphone", "", "Last thing, the docs. Who owns the getting-started guide?"},
+	{64 * time.Second, 4 * time.Second, "systemAudio", "Speaker 1", "I will, and I'll add a section on exporting transcripts."},
+	{69 * time.Second, 3 * time.Second, "microphone", "", "Great. Same time next week. Thanks, everyone."},
+}
+
+// tutorialTopics are the canned meeting's topics, shown once the
+// tutorial reaches the topics panel.
+var tutorialTopics = []daemon.EventTopic{
+	{ID: "tutorial-1", Title: "Beta rollout", Summary: "The crash on older laptops gets a fix by Thursday; the beta opens to everyone on Monday.", SegmentRangeStart: 1, SegmentRangeEnd: 7},
+	{ID: "tutorial-2", Title: "Pricing", Summary: "Settled on nine dollars a month or ninety a year.", SegmentRangeStart: 8, SegmentRangeEnd: 12},
+	{ID: "tutorial-3", Title: "Docs", Summary: "Speaker 1 owns the getting-started guide, with a section on exports.", SegmentRangeStart: 13, SegmentRangeEnd: 15},
+}
+
+// tutorialSegments stamps the script as a session that started at origin.
+func tutorialSegments(origin time.Time) []db.Segment {
+	segs := make([]db.Segment, len(tutorialScript))
+	for i, l := range tutorialScript {
+		segs[i] = db.Segment{
+			SessionID:      tutorialSessionID,
+			Text:           l.text,
+			StartedAt:      origin.Add(l.at),
+			EndedAt:        origin.Add(l.at + l.length),
+			SequenceNumber: i + 1,
+			Source:         l.source,
+			Speaker:        l.speaker,
+		}
+	}
+	return segs
+}
+
+// tutorialStep is one prompt of the tour. It is done once the key it
+// teaches has been pressed, judged from the model afterwards.
+type tutorialStep struct {
+	prompt string
+	done   func(m Model) bool
+	// enter, if set, sets the step up as it's reached.
+	enter func(m *Model) tea.Cmd
+}
+
+var tutorialSteps = []tutorialStep{
+	{
+		prompt: "Steno records all the time; the meeting fills in as people talk. Press p to pause.",
+		done:   func(m Model) bool { return m.engineStatus == StatusPaused },
+	},
+	{
+		prompt: "Paused: nothing is heard (p pauses for 30 minutes, P until resumed). Press p to resume.",
+		done:   func(m Model) bool { return m.engineStatus == StatusRecording },
+	},
+	{
+		prompt: "Space ends the session and starts the next, between back-to-back meetings. Press space.",
+		done:   func(m Model) bool { return m.tutorial.marked },
+	},
+	{
+		prompt: "Press PgUp to scroll back through what was said.",
+		done:   func(m Model) bool { return !m.transcriptLive },
+	},
+	{
+		prompt: "The header shows how far up you are. Press End to jump back to live.",
+		done:   func(m Model) bool { return m.transcriptLive },
+	},
+	{
+		prompt: "Topics group the meeting as it goes. Press tab to focus the topics panel.",
+		done:   func(m Model) bool { return m.focusedPanel == FocusTopics },
+		enter: func(m *Model) tea.Cmd {
+			return m.handleEvent(daemon.Event{Event: "topics", Topics: tutorialTopics})
+		},
+	},
+	{
+		prompt: "j / k pick a topic. Press enter to expand one.",
+		done: func(m Model) bool {
+			for _, t := range m.topics {
+				if t.Expanded {
+					return true
+				}
+			}
+			return false
+		},
+	},
+	{
+		prompt: "Press W to export the transcript as Markdown.",
+		done:   func(m Model) bool { return m.exportDialog != nil },
+	},
+	{
+		prompt: "Give a segment number or m:ss to export part of it, or leave both empty. Press enter.",
+		done:   func(m Model) bool { return m.tutorial.exported },
+	},
+	{
+		prompt: "That's the tour. ? lists every key and q quits; run steno to record for real.",
+		done:   func(Model) bool { return false },
+	},
+}
+
+// tutorial is `steno tutorial`'s state: a canned meeting played through
+// the replay harness into the live view, and the step being taught.
+type tutorial struct {
+	player   *replaySession
+	segments []db.Segment
+	step     int
+	marked   bool // space was pressed
+	exported bool // the export dialog's enter was pressed
+}
+
+// tutorialTickMsg advances the tutorial's meeting.
+type tutorialTickMsg struct{ at time.Time }
+
+// WithTutorial returns m as the interactive tour (`steno tutorial`): it
+// never starts or connects to the daemon, plays a canned meeting
+// through the live transcript as if it were being recorded, and steps
+// through the main keys in the footer. Recording keys act locally and
+// nothing is written; view preferences aren't saved either.
+func (m Model) WithTutorial() Model {
+	segs := tutorialSegments(m.now())
+	r := newReplaySession(m, ReplayLoadedMsg{SessionID: tutorialSessionID, Segments: segs}, 0)
+	m.tutorial = &tutorial{player: r, segments: segs}
+	m.connected = true
+	m.engineStatus = StatusRecording
+	m.sessionID = tutorialSessionID
+	m.statusText = "Tutorial"
+	m.showFirstLaunchBanner = false
+	return m
+}
+
+func (m Model) tutorialTickCmd() tea.Cmd {
+	return m.tick(replayTickInterval, func(t time.Time) tea.Msg {
+		return tutorialTickMsg{at: t}
+	})
+}
+
+// handleTutorialTick plays the meeting on into the transcript.
+func (m Model) handleTutorialTick(msg tutorialTickMsg) (tea.Model, tea.Cmd) {
+	t := *m.tutorial
+	p := *t.player
+	p.into = &m
+	p.tick(m, msg.at)
+	p.into = nil
+	t.player = &p
+	m.tutorial = &t
+	return m, m.tutorialTickCmd()
+}
+
+// handleTutorialKey handles a key during the tutorial. The keys that
+// would reach the daemon or write a file are played out locally; the
+// rest work as usual. Either way a key that completes the current step
+// moves on to the next.
+func (m Model) handleTutorialKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	t := *m.tutorial
+	p := *t.player
+	var cmd tea.Cmd
+	switch key := msg.String(); {
+	case m.overlayOpen():
+		next, c := m.handleKey(msg)
+		m, cmd = next.(Model), c
+	case m.exportDialog != nil && key == KeyEnter:
+		sl := m.exportDialog.slice()
+		if sl.err != nil {
+			return m, nil
+		}
+		path := exportPath(m.exportConfig, m.exportDialog.sessionID, sl.r)
+		m.exportDialog = nil
+		t.exported = true
+		m.notice = fmt.Sprintf("Tutorial: %d segments would be written to %s", len(sl.transcript.Lines), path)
+		cmd = m.clearNoticeCmd()
+	case m.exportDialog != nil:
+		next, c := m.handleKey(msg)
+		m, cmd = next.(Model), c
+	case key == KeyPause || key == KeyPauseIndefinite:
+		if m.engineStatus == StatusPaused {
+			m.engineStatus = StatusRecording
+			m.pausedIndefinitely = false
+			m.pauseExpiresAt = nil
+			p.paused, p.lastTick = false, time.Time{}
+		} else {
+			m.engineStatus = StatusPaused
+			m.pausedIndefinitely = key == KeyPauseIndefinite
+			if !m.pausedIndefinitely {
+				at := m.now().Add(defaultPauseAutoResumeSeconds * time.Second)
+				m.pauseExpiresAt = &at
+			}
+			p.paused = true
+		}
+	case key == KeySpace:
+		if m.engineStatus == StatusPaused {
+			return m, func() tea.Msg { return PauseHintMsg{} }
+		}
+		t.marked = true
+		m.notice = "Tutorial: a new session would start here"
+		cmd = m.clearNoticeCmd()
+	case key == KeyExport:
+		sess := db.Session{ID: tutorialSessionID, Title: "Beta launch check-in", StartedAt: p.origin}
+		m.exportDialog = &exportDialog{
+			sessionID:  tutorialSessionID,
+			transcript: export.NewTranscript(sess, t.segments),
+			loaded:     true,
+		}
+	default:
+		next, c := m.handleKey(msg)
+		m, cmd = next.(Model), c
+	}
+	t.player = &p
+	m.tutorial = &t
+	return m, tea.Batch(cmd, m.advanceTutorial())
+}
+
+// overlayOpen reports whether something over the live view takes the
+// keys ahead of the export dialog, as handleKey checks them.
+func (m Model) overlayOpen() bool {
+	return m.showFirstLaunchBanner || m.qualityReport != nil || m.topicEdit != nil ||
+		m.speakerEdit != nil || m.notes != nil || (m.search != nil && m.search.editing) ||
+		m.showErrorModal || m.keyHelp || m.switcher != nil || m.sessionSearch != nil ||
+		m.keywords != nil || m.entityList != nil || m.codes != nil || m.searchMenu != nil
+}
+
+// advanceTutorial moves past every step that's done, setting up each
+// step it reaches.
+func (m *Model) advanceTutorial() tea.Cmd {
+	var cmds []tea.Cmd
+	for m.tutorial.step < len(tutorialSteps)-1 && tutorialSteps[m.tutorial.step].done(*m) {
+		m.tutorial.step++
+		if enter := tutorialSteps[m.tutorial.step].enter; enter != nil {
+			cmds = append(cmds, enter(m))
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+// renderTutorialFooter replaces the key hints with the current step.
+func (m Model) renderTutorialFooter() string {
+	label := fmt.Sprintf("Tutorial %d/%d", m.tutorial.step+1, len(tutorialSteps))
+	line := ui.FooterKeyStyle.Render(label) + ui.FooterDescStyle.Render("  "+tutorialSteps[m.tutorial.step].prompt)
+	return truncateToWidth(line, m.width)
+}
@@ -0,0 +1,74 @@
+package app
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestTutorialPlaysTheMeetingIntoTheTranscript(t *testing.T) {
+	m := New().WithTutorial()
+	m.width, m.height = 140, 40
+	if m.Init() == nil {
+		t.Fatal("Init = nil, want the tutorial's tick")
+	}
+	origin := m.tutorial.player.origin
+	m, _ = applyUpdate(m, tutorialTickMsg{at: origin})
+	m, _ = applyUpdate(m, tutorialTickMsg{at: origin.Add(12 * time.Second)})
+	if got := entryTexts(m); !strings.Contains(got, "launch check-in") || strings.Contains(got, "older laptop") {
+		t.Errorf("entries = %q, want the first two segments", got)
+	}
+	if m.partials["systemAudio"] == "" {
+		t.Errorf("partials = %v, want the third segment in progress", m.partials)
+	}
+	if bar := m.renderStatusBar(); !strings.Contains(bar, "TUTORIAL") {
+		t.Errorf("status bar = %q, want the tutorial label", bar)
+	}
+}
+
+func TestTutorialSteps(t *testing.T) {
+	m := New().WithTutorial()
+	m.width, m.height = 140, 40
+	origin := m.tutorial.player.origin
+	m, _ = applyUpdate(m, tutorialTickMsg{at: origin})
+	m, _ = applyUpdate(m, tutorialTickMsg{at: origin.Add(time.Minute)})
+
+	press := func(msg tea.KeyMsg, wantStep int) {
+		t.Helper()
+		m, _ = applyUpdate(m, msg)
+		if m.tutorial.step != wantStep {
+			t.Fatalf("after %q: step = %d, want %d (%s)", msg.String(), m.tutorial.step, wantStep, tutorialSteps[m.tutorial.step].prompt)
+		}
+	}
+
+	press(runeKey('x'), 0) // not the key being taught
+	press(runeKey('p'), 1)
+	if m.engineStatus != StatusPaused || m.pauseExpiresAt == nil || !m.tutorial.player.paused {
+		t.Fatalf("status = %v, want paused locally with playback held", m.engineStatus)
+	}
+	press(runeKey('p'), 2)
+	press(tea.KeyMsg{Type: tea.KeySpace}, 3)
+	press(tea.KeyMsg{Type: tea.KeyPgUp}, 4)
+	press(tea.KeyMsg{Type: tea.KeyEnd}, 5)
+	if len(m.topics) != len(tutorialTopics) {
+		t.Fatalf("topics = %d, want the canned ones once the step is reached", len(m.topics))
+	}
+	press(tea.KeyMsg{Type: tea.KeyTab}, 6)
+	press(tea.KeyMsg{Type: tea.KeyEnter}, 7)
+	press(runeKey('W'), 8)
+	if d := m.exportDialog; d == nil || len(d.transcript.Lines) != len(tutorialScript) {
+		t.Fatalf("export dialog = %+v, want the canned transcript loaded", d)
+	}
+	press(tea.KeyMsg{Type: tea.KeyEnter}, 9)
+	if m.exportDialog != nil || m.exporting != nil {
+		t.Errorf("enter should close the dialog without exporting")
+	}
+	if !strings.Contains(m.notice, "15 segments would be written") {
+		t.Errorf("notice = %q", m.notice)
+	}
+	if footer := m.renderFooter(); !strings.Contains(footer, "Tutorial 10/10") {
+		t.Errorf("footer = %q, want the last step", footer)
+	}
+}
@@ -0,0 +1,123 @@
+package app
+
+import (
+	"regexp"
+	"slices"
+	"sort"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jwulff/steno/internal/db"
+)
+
+// The live session's density, transcript filter and unfolded topics are
+// saved as they change (client-owned session_view_prefs table) and
+// restored when the TUI shows that session again, say after a restart
+// mid-meeting. Speaker names are saved on their own; see
+// speakerlabels.go.
+
+// viewPrefState tracks the current session's saved view.
+type viewPrefState struct {
+	// sessionID is the session whose prefs have been loaded. Nothing is
+	// saved before they are, so an early key can't overwrite them.
+	sessionID string
+	// expand holds saved unfolded topics not yet in the topic panel.
+	expand map[string]bool
+}
+
+// loadViewPrefsCmd reads sessionID's view prefs.
+func loadViewPrefsCmd(store *db.Store, sessionID string) tea.Cmd {
+	return func() tea.Msg {
+		p, err := store.ViewPrefs(sessionID)
+		return ViewPrefsLoadedMsg{SessionID: sessionID, Prefs: p, Err: err}
+	}
+}
+
+// saveViewPrefsCmd saves sessionID's view prefs.
+func saveViewPrefsCmd(store *db.Store, sessionID string, p db.ViewPrefs) tea.Cmd {
+	return func() tea.Msg {
+		return ViewPrefsSavedMsg{Err: store.SetViewPrefs(sessionID, p)}
+	}
+}
+
+// viewPrefsCmd returns loadViewPrefsCmd for the current session until
+// its prefs are loaded, or nil. A viewer's session is the host's, so it
+// has none.
+func (m Model) viewPrefsCmd() tea.Cmd {
+	if m.store == nil || m.sessionID == "" || m.viewAddr != "" || m.viewPrefs.sessionID == m.sessionID {
+		return nil
+	}
+	return loadViewPrefsCmd(m.store, m.sessionID)
+}
+
+// currentViewPrefs is what would be saved of the view now.
+func (m Model) currentViewPrefs() db.ViewPrefs {
+	p := db.ViewPrefs{Density: m.density.String()}
+	if m.filter != nil {
+		p.FilterWord, p.FilterPattern, p.FilterSources = m.filter.word, m.filter.re.String(), m.filter.sources
+	}
+	for _, t := range m.topics {
+		if t.Expanded {
+			p.ExpandedTopics = append(p.ExpandedTopics, t.ID)
+		}
+	}
+	var pending []string
+	for id := range m.viewPrefs.expand {
+		pending = append(pending, id)
+	}
+	sort.Strings(pending)
+	p.ExpandedTopics = append(p.ExpandedTopics, pending...)
+	return p
+}
+
+// applyViewPrefs restores a loaded session's view; prefs for a session
+// that has since ended are dropped. Saved topics not loaded yet unfold
+// when they arrive.
+func (m *Model) applyViewPrefs(msg ViewPrefsLoadedMsg) tea.Cmd {
+	if msg.SessionID != m.sessionID || msg.Err != nil {
+		return nil
+	}
+	m.viewPrefs = viewPrefState{sessionID: msg.SessionID}
+	p := msg.Prefs
+	if p == nil {
+		return nil
+	}
+	if d, ok := ParseDensity(p.Density); ok && p.Density != "" {
+		m.density = d
+	}
+	if re, err := regexp.Compile(p.FilterPattern); err == nil && p.FilterPattern != "" {
+		m.filter = &transcriptFilter{word: p.FilterWord, re: re, sources: p.FilterSources}
+		m.transcriptLive = false
+		m.transcriptScroll = 0
+	}
+	m.viewPrefs.expand = map[string]bool{}
+	for _, id := range p.ExpandedTopics {
+		m.viewPrefs.expand[id] = true
+	}
+	m.expandSavedTopics()
+	return m.expandedTopicSegmentsCmd()
+}
+
+// expandSavedTopics unfolds the saved topics now in the topic panel.
+func (m *Model) expandSavedTopics() {
+	for i := range m.topics {
+		if m.viewPrefs.expand[m.topics[i].ID] {
+			m.topics[i].Expanded = true
+			delete(m.viewPrefs.expand, m.topics[i].ID)
+		}
+	}
+}
+
+// keepViewPrefs saves the current session's view prefs if they differ
+// from before, once they have been loaded; nil otherwise. A replay has
+// its own view, not the live session's.
+func (m Model) keepViewPrefs(before db.ViewPrefs) tea.Cmd {
+	if m.store == nil || m.sessionID == "" || m.viewPrefs.sessionID != m.sessionID || m.replay != nil {
+		return nil
+	}
+	p := m.currentViewPrefs()
+	if p.Density == before.Density && p.FilterWord == before.FilterWord && p.FilterPattern == before.FilterPattern &&
+		slices.Equal(p.FilterSources, before.FilterSources) && slices.Equal(p.ExpandedTopics, before.ExpandedTopics) {
+		return nil
+	}
+	return saveViewPrefsCmd(m.store, m.sessionID, p)
+}
@@ -0,0 +1,70 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/jwulff/steno/internal/db"
+)
+
+func viewPrefsModel(t *testing.T) Model {
+	return testModel(t, withSession("sess-1"))
+}
+
+func TestViewPrefsRestored(t *testing.T) {
+	m := viewPrefsModel(t)
+	m, _ = applyUpdate(m, TopicsLoadedMsg{Topics: []TopicLoaded{
+		{ID: "t-1", Title: "Budget", SegmentRangeStart: 1, SegmentRangeEnd: 2},
+		{ID: "t-2", Title: "Hiring", SegmentRangeStart: 3, SegmentRangeEnd: 4},
+	}})
+	m, cmd := applyUpdate(m, ViewPrefsLoadedMsg{SessionID: "sess-1", Prefs: &db.ViewPrefs{
+		Density:        "compact",
+		FilterWord:     "budget",
+		FilterPattern:  `(?i)\bbudget\b`,
+		FilterSources:  []string{"microphone"},
+		ExpandedTopics: []string{"t-2", "t-3"},
+	}})
+	if m.density != DensityCompact {
+		t.Errorf("density = %v, want compact", m.density)
+	}
+	if m.filter == nil || m.filter.word != "budget" || len(m.filter.sources) != 1 || !m.filter.re.MatchString("the Budget") {
+		t.Errorf("filter = %+v, want the saved one", m.filter)
+	}
+	if m.topics[0].Expanded || !m.topics[1].Expanded || cmd == nil {
+		t.Errorf("topics = %+v, want t-2 unfolded and its segments loading", m.topics)
+	}
+
+	// A saved topic that arrives later unfolds then.
+	m, _ = applyUpdate(m, TopicsLoadedMsg{Topics: []TopicLoaded{
+		{ID: "t-1"}, {ID: "t-2"}, {ID: "t-3", SegmentRangeStart: 5, SegmentRangeEnd: 6},
+	}})
+	if !m.topics[2].Expanded {
+		t.Error("t-3 should unfold once loaded")
+	}
+}
+
+func TestViewPrefsSavedOnChange(t *testing.T) {
+	m := viewPrefsModel(t)
+	if _, cmd := applyUpdate(m, runeKey('v')); cmd != nil {
+		t.Error("nothing should be saved before the session's prefs load")
+	}
+
+	m, _ = applyUpdate(m, ViewPrefsLoadedMsg{SessionID: "sess-1"})
+	m, cmd := applyUpdate(m, runeKey('v'))
+	if cmd == nil {
+		t.Fatal("a density change should be saved")
+	}
+	if p := m.currentViewPrefs(); p.Density != m.density.String() {
+		t.Errorf("prefs = %+v", p)
+	}
+	if _, cmd := applyUpdate(m, runeKey('?')); cmd != nil {
+		t.Error("a key that leaves the view alone shouldn't save")
+	}
+}
+
+func TestViewPrefsForEndedSessionDropped(t *testing.T) {
+	m := viewPrefsModel(t)
+	m, _ = applyUpdate(m, ViewPrefsLoadedMsg{SessionID: "sess-0", Prefs: &db.ViewPrefs{Density: "captions"}})
+	if m.density == DensityCaptions || m.viewPrefsCmd() == nil {
+		t.Error("prefs for another session shouldn't apply")
+	}
+}
@@ -0,0 +1,234 @@
+// Package bugreport assembles the zip bundle attached to GitHub issues:
+// versions, DB schema version, recent daemon log, recent protocol
+// messages, and (from the TUI) a state summary. Transcript content never
+// leaves the machine: protocol text fields are replaced by their length,
+// and everything else passes through the scrub patterns.
+package bugreport
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/jwulff/steno/internal/daemon"
+	"github.com/jwulff/steno/internal/db"
+	"github.com/jwulff/steno/internal/scrub"
+)
+
+// LogLines is how much of the daemon log a report includes.
+const LogLines = 500
+
+// MessageCount is how many protocol messages the TUI keeps for reports.
+const MessageCount = 200
+
+// Versions are the steno and daemon versions at report time.
+type Versions struct {
+	Steno       string `json:"steno"`
+	StenoBuild  string `json:"steno_build"`
+	Daemon      string `json:"daemon,omitempty"`
+	DaemonBuild string `json:"daemon_build,omitempty"`
+	Skew        bool   `json:"skew"`
+	// Protocol and DaemonProtocol are the protocol revisions; see
+	// daemon.ProtocolVersion.
+	Protocol       int `json:"protocol,omitempty"`
+	DaemonProtocol int `json:"daemon_protocol,omitempty"`
+}
+
+// Report is everything that goes into a bundle. Zero fields are omitted.
+type Report struct {
+	CreatedAt time.Time
+	Versions  Versions
+
+	Schema      *db.SchemaInfo
+	SchemaError string
+
+	DaemonLog string
+	Messages  []daemon.LoggedMessage
+
+	// TUIState is a JSON-serializable summary of the TUI; nil from the CLI.
+	TUIState any
+
+	// Scrubber masks the log and messages. Nil means NewScrubber(nil).
+	Scrubber *scrub.Scrubber
+}
+
+// NewScrubber returns a scrubber with scrub.DefaultPatterns plus extra
+// (the user's configured patterns), so reports are always masked even
+// when display scrubbing is off.
+func NewScrubber(extra []string) (*scrub.Scrubber, error) {
+	patterns := append(append([]string(nil), scrub.DefaultPatterns...), extra...)
+	return scrub.New(patterns, "")
+}
+
+// DefaultDir is where Save puts bundles:
+// ~/Library/Application Support/Steno/bugreports.
+func DefaultDir() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, "Library", "Application Support", "Steno", "bugreports")
+}
+
+// Save writes r as a timestamped zip under dir and returns its path.
+func Save(dir string, r Report) (string, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", fmt.Errorf("create bug report dir: %w", err)
+	}
+	path := filepath.Join(dir, "steno-bugreport-"+r.CreatedAt.Format("20060102-150405")+".zip")
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return "", fmt.Errorf("create bug report: %w", err)
+	}
+	if err := Write(f, r); err != nil {
+		f.Close()
+		os.Remove(path)
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", fmt.Errorf("close bug report: %w", err)
+	}
+	return path, nil
+}
+
+// Write encodes r as a zip archive to w.
+func Write(w io.Writer, r Report) error {
+	s := r.Scrubber
+	if s == nil {
+		var err error
+		if s, err = NewScrubber(nil); err != nil {
+			return err
+		}
+	}
+
+	zw := zip.NewWriter(w)
+	add := func(name string, data []byte) error {
+		fw, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: r.CreatedAt})
+		if err != nil {
+			return fmt.Errorf("bug report %s: %w", name, err)
+		}
+		_, err = fw.Write(data)
+		return err
+	}
+	addJSON := func(name string, v any) error {
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return fmt.Errorf("bug report %s: %w", name, err)
+		}
+		return add(name, append(data, '\n'))
+	}
+
+	if err := add("README.txt", []byte(readme)); err != nil {
+		return err
+	}
+	if err := addJSON("versions.json", r.Versions); err != nil {
+		return err
+	}
+	schema := map[string]any{}
+	if r.Schema != nil {
+		schema["latest_migration"] = r.Schema.Latest()
+		schema["migrations"] = r.Schema.Migrations
+		schema["client_tables"] = r.Schema.ClientTables
+	}
+	if r.SchemaError != "" {
+		schema["error"] = s.Apply(r.SchemaError)
+	}
+	if err := addJSON("schema.json", schema); err != nil {
+		return err
+	}
+	if r.DaemonLog != "" {
+		if err := add("daemon.log", []byte(s.Apply(r.DaemonLog)+"\n")); err != nil {
+			return err
+		}
+	}
+	if len(r.Messages) > 0 {
+		var buf []byte
+		for _, m := range r.Messages {
+			line, _ := json.Marshal(struct {
+				Time    string          `json:"time"`
+				Dir     string          `json:"dir"`
+				Message json.RawMessage `json:"message"`
+			}{m.Time.UTC().Format(time.RFC3339Nano), direction(m.Sent), RedactMessage(m.Line, s)})
+			buf = append(append(buf, line...), '\n')
+		}
+		if err := add("protocol.ndjson", buf); err != nil {
+			return err
+		}
+	}
+	if r.TUIState != nil {
+		if err := addJSON("tui-state.json", r.TUIState); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// contentKeys are protocol members that carry transcript or summary text.
+// texts is clean_verbatim's segments, sent and returned as a list.
+var contentKeys = []string{"text", "texts", "title", "summary", "content"}
+
+// RedactMessage replaces transcript-bearing string members of one NDJSON
+// protocol line with "[N chars]", at any depth (a topics event's titles
+// and summaries too), and scrubs the rest. The result is always valid
+// JSON: a line that isn't an object comes back as a scrubbed JSON string.
+func RedactMessage(line []byte, s *scrub.Scrubber) json.RawMessage {
+	dec := json.NewDecoder(bytes.NewReader(line))
+	dec.UseNumber()
+	var obj map[string]any
+	if err := dec.Decode(&obj); err != nil || obj == nil {
+		quoted, _ := json.Marshal(s.Apply(string(line)))
+		return quoted
+	}
+	redactValue("", obj)
+	out, _ := json.Marshal(obj)
+	scrubbed := s.Apply(string(out))
+	if !json.Valid([]byte(scrubbed)) {
+		quoted, _ := json.Marshal(scrubbed)
+		return quoted
+	}
+	return json.RawMessage(scrubbed)
+}
+
+// redactValue redacts v, the value of member key: objects member by
+// member, and the elements of an array as if each were key's.
+func redactValue(key string, v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, member := range v {
+			v[k] = redactValue(k, member)
+		}
+	case []any:
+		for i, elem := range v {
+			v[i] = redactValue(key, elem)
+		}
+	case string:
+		if slices.Contains(contentKeys, key) {
+			return fmt.Sprintf("[%d chars]", len([]rune(v)))
+		}
+	}
+	return v
+}
+
+func direction(sent bool) string {
+	if sent {
+		return "sent"
+	}
+	return "received"
+}
+
+const readme = `steno bug report
+
+Attach this zip to a GitHub issue. It contains:
+
+  versions.json    steno and steno-daemon versions and builds
+  schema.json      applied database migrations (no rows)
+  daemon.log       the last lines of the daemon log
+  protocol.ndjson  recent socket messages; transcript text replaced by its length
+  tui-state.json   TUI state summary (when created from the TUI)
+
+Card numbers, API tokens, and your configured scrub patterns are masked.
+Review the files before posting.
+`
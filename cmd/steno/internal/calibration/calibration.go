@@ -0,0 +1,154 @@
+// Package calibration measures how far the ASR's reported confidence can
+// be trusted. Segments the user has corrected (`steno correct`) are
+// scored by word error rate against the correction, and the rates are
+// grouped by recording device, locale and confidence, so a device that
+// mishears more, or confidence that doesn't track accuracy, shows up.
+package calibration
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/jwulff/steno/internal/db"
+)
+
+// Bucket is a range of reported confidence, [Min, Max).
+type Bucket struct {
+	Label    string
+	Min, Max float64
+}
+
+// Buckets are the confidence ranges reported on, lowest first. Segments
+// without a confidence fall in NoConfidence.
+var Buckets = []Bucket{
+	{"<0.50", 0, 0.5},
+	{"0.50-0.70", 0.5, 0.7},
+	{"0.70-0.80", 0.7, 0.8},
+	{"0.80-0.90", 0.8, 0.9},
+	{"0.90-1.00", 0.9, 1.01},
+}
+
+// NoConfidence labels segments the daemon recorded no confidence for.
+const NoConfidence = "none"
+
+// Row is one device, locale and confidence bucket of the report.
+type Row struct {
+	Device string
+	Locale string
+	Bucket string
+	// Segments counts the samples, Edited those the user corrected.
+	Segments int
+	Edited   int
+	// Words is the corrected text's word count; Errors the substitutions,
+	// insertions and deletions turning the ASR text into it.
+	Words  int
+	Errors int
+}
+
+// WER is the row's word error rate; 0 without words.
+func (r Row) WER() float64 {
+	if r.Words == 0 {
+		return 0
+	}
+	return float64(r.Errors) / float64(r.Words)
+}
+
+// Report scores samples and groups them by device, locale and bucket,
+// sorted in that order with buckets lowest first and NoConfidence last.
+// Empty groups are left out.
+func Report(samples []db.CalibrationSample) []Row {
+	type key struct{ device, locale, bucket string }
+	rows := map[key]*Row{}
+	for _, s := range samples {
+		k := key{s.Device, s.Locale, bucketFor(s.Confidence)}
+		r := rows[k]
+		if r == nil {
+			r = &Row{Device: k.device, Locale: k.locale, Bucket: k.bucket}
+			rows[k] = r
+		}
+		errs, words := WordErrors(s.Text, s.Corrected)
+		r.Segments++
+		if s.Edited {
+			r.Edited++
+		}
+		r.Words += words
+		r.Errors += errs
+	}
+
+	out := make([]Row, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, *r)
+	}
+	slices.SortFunc(out, func(a, b Row) int {
+		return cmp.Or(
+			cmp.Compare(a.Device, b.Device),
+			cmp.Compare(a.Locale, b.Locale),
+			cmp.Compare(bucketOrder(a.Bucket), bucketOrder(b.Bucket)),
+		)
+	})
+	return out
+}
+
+func bucketFor(confidence *float64) string {
+	if confidence == nil {
+		return NoConfidence
+	}
+	for _, b := range Buckets {
+		if *confidence >= b.Min && *confidence < b.Max {
+			return b.Label
+		}
+	}
+	// Out of range: clamp to the nearest end.
+	if *confidence < 0 {
+		return Buckets[0].Label
+	}
+	return Buckets[len(Buckets)-1].Label
+}
+
+func bucketOrder(label string) int {
+	for i, b := range Buckets {
+		if b.Label == label {
+			return i
+		}
+	}
+	return len(Buckets)
+}
+
+// WordErrors returns the word-level edit distance from heard to said,
+// and said's word count. Words are compared case-insensitively, ignoring
+// punctuation, so a correction that only fixes capitalization or a comma
+// isn't counted as an ASR error.
+func WordErrors(heard, said string) (errs, words int) {
+	h, s := normalizeWords(heard), normalizeWords(said)
+	// One row of the Levenshtein table at a time.
+	prev := make([]int, len(s)+1)
+	cur := make([]int, len(s)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(h); i++ {
+		cur[0] = i
+		for j := 1; j <= len(s); j++ {
+			sub := prev[j-1]
+			if h[i-1] != s[j-1] {
+				sub++
+			}
+			cur[j] = min(sub, prev[j]+1, cur[j-1]+1)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(s)], len(s)
+}
+
+func normalizeWords(text string) []string {
+	var words []string
+	for _, w := range strings.Fields(strings.ToLower(text)) {
+		w = strings.TrimFunc(w, func(r rune) bool { return unicode.IsPunct(r) || unicode.IsSymbol(r) })
+		if w != "" {
+			words = append(words, w)
+		}
+	}
+	return words
+}
@@ -0,0 +1,60 @@
+package calibration
+
+import (
+	"math"
+	"testing"
+
+	"github.com/jwulff/steno/internal/db"
+)
+
+func TestWordErrors(t *testing.T) {
+	for _, tc := range []struct {
+		heard, said string
+		errs, words int
+	}{
+		{"ship it friday", "Ship it, Friday.", 0, 3},
+		{"ship it friday", "ship it on friday", 1, 4},  // deletion
+		{"ship the it friday", "ship it friday", 1, 3}, // insertion
+		{"sip it friday", "ship it monday", 2, 3},      // substitutions
+		{"", "hello there", 2, 2},
+		{"hello", "", 1, 0},
+	} {
+		errs, words := WordErrors(tc.heard, tc.said)
+		if errs != tc.errs || words != tc.words {
+			t.Errorf("WordErrors(%q, %q) = %d, %d; want %d, %d", tc.heard, tc.said, errs, words, tc.errs, tc.words)
+		}
+	}
+}
+
+func TestReport(t *testing.T) {
+	conf := func(v float64) *float64 { return &v }
+	samples := []db.CalibrationSample{
+		{Device: "USB Mic", Locale: "en_US", Confidence: conf(0.95), Text: "one two three four", Corrected: "one two three four"},
+		{Device: "USB Mic", Locale: "en_US", Confidence: conf(0.92), Text: "won two", Corrected: "one two", Edited: true},
+		{Device: "USB Mic", Locale: "en_US", Confidence: conf(0.4), Text: "a b", Corrected: "c d", Edited: true},
+		{Device: "USB Mic", Locale: "en_US", Text: "x", Corrected: "x"},
+		{Device: "", Locale: "en_US", Confidence: conf(0.85), Text: "x", Corrected: "y", Edited: true},
+	}
+	rows := Report(samples)
+	if len(rows) != 4 {
+		t.Fatalf("rows = %+v", rows)
+	}
+	// Unknown device first, then USB Mic's buckets lowest first, none last.
+	want := []struct {
+		device, bucket          string
+		segments, edited, words int
+		wer                     float64
+	}{
+		{"", "0.80-0.90", 1, 1, 1, 1},
+		{"USB Mic", "<0.50", 1, 1, 2, 1},
+		{"USB Mic", "0.90-1.00", 2, 1, 6, 1.0 / 6},
+		{"USB Mic", NoConfidence, 1, 0, 1, 0},
+	}
+	for i, w := range want {
+		r := rows[i]
+		if r.Device != w.device || r.Bucket != w.bucket || r.Segments != w.segments || r.Edited != w.edited ||
+			r.Words != w.words || math.Abs(r.WER()-w.wer) > 1e-9 {
+			t.Errorf("row %d = %+v (WER %.3f), want %+v", i, r, r.WER(), w)
+		}
+	}
+}
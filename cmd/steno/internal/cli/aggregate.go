@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/jwulff/steno/internal/daemon"
+)
+
+// aggregateLine is one `steno aggregate --json` line: an event, or the
+// error that ended a daemon's stream.
+type aggregateLine struct {
+	Daemon string        `json:"daemon"`
+	Event  *daemon.Event `json:"event,omitempty"`
+	Error  string        `json:"error,omitempty"`
+}
+
+// runAggregate follows several daemons at once — say, two Macs capturing
+// different rooms, their sockets forwarded over ssh — and prints their
+// events merged into one stream, each tagged with the name given on the
+// command line. Every daemon keeps recording its own sessions in its own
+// database; the tags and timestamps are what line them up afterwards.
+func runAggregate(env Env, args []string) int {
+	fs, jsonOut := newFlagSet(env, "aggregate")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fmt.Fprintln(env.Stderr, "usage: steno aggregate [--json] NAME=SOCKET...")
+		return 2
+	}
+	var endpoints []daemon.Endpoint
+	for _, arg := range fs.Args() {
+		ep, err := daemon.ParseEndpoint(arg)
+		if err != nil {
+			return fail(env, *jsonOut, err)
+		}
+		endpoints = append(endpoints, ep)
+	}
+
+	// NDJSON, one line per event, rather than writeJSON's indented
+	// documents: the stream has no end to wait for.
+	enc := json.NewEncoder(env.Stdout)
+	err := daemon.Aggregate(endpoints, func(te daemon.TaggedEvent) error {
+		if *jsonOut {
+			line := aggregateLine{Daemon: te.Daemon}
+			if te.Err != nil {
+				line.Error = te.Err.Error()
+			} else {
+				line.Event = &te.Event
+			}
+			return enc.Encode(line)
+		}
+		if te.Err != nil {
+			fmt.Fprintf(env.Stderr, "steno: %s: %s\n", te.Daemon, te.Err.Error())
+			return nil
+		}
+		return writeAggregateEvent(env.Stdout, te)
+	})
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	return 0
+}
+
+// writeAggregateEvent prints the events worth reading as they scroll by:
+// finished segments, the latest topic and daemon errors.
+func writeAggregateEvent(w io.Writer, te daemon.TaggedEvent) error {
+	ev := te.Event
+	var err error
+	switch ev.Event {
+	case "segment":
+		at := "--:--:--"
+		if ev.StartedAt != nil {
+			at = time.Unix(0, int64(*ev.StartedAt*float64(time.Second))).Local().Format("15:04:05")
+		}
+		_, err = fmt.Fprintf(w, "[%s] %s %s: %s\n", te.Daemon, at, aggregateSource(ev.Source), ev.Text)
+	case "topics":
+		// Older daemons send only the changed title, newer ones the list.
+		title := ev.Title
+		if len(ev.Topics) > 0 {
+			title = ev.Topics[len(ev.Topics)-1].Title
+		}
+		if title != "" {
+			_, err = fmt.Fprintf(w, "[%s] topic: %s\n", te.Daemon, title)
+		}
+	case "error":
+		_, err = fmt.Fprintf(w, "[%s] error: %s\n", te.Daemon, ev.Message)
+	}
+	return err
+}
+
+// aggregateSource labels a segment's audio source.
+func aggregateSource(source string) string {
+	if source == "systemAudio" {
+		return "system audio"
+	}
+	return "mic"
+}
@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/jwulff/steno/internal/db"
+	"github.com/jwulff/steno/internal/export"
+)
+
+// ankiOutput is the `steno anki --json` shape.
+type ankiOutput struct {
+	File     string `json:"file"`
+	Sessions int    `json:"sessions"`
+	Cards    int    `json:"cards"`
+}
+
+// runAnki exports the annotated segments of one or more sessions as Anki
+// flashcards (tab-separated, for File → Import): bookmark or note the
+// lines worth learning with `steno annotate`, then import them as cards.
+func runAnki(env Env, args []string) int {
+	fs, jsonOut := newFlagSet(env, "anki")
+	out := fs.String("o", "", "Write the cards to this file instead of stdout (required with --json)")
+	deck := fs.String("deck", "", "Anki deck to import the cards into (default: chosen in Anki's import dialog)")
+	fs.Usage = func() {
+		fmt.Fprintln(env.Stderr, "Usage: steno anki [--deck NAME] [--json] [-o FILE] <session-id>...")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 || (*jsonOut && *out == "") {
+		fs.Usage()
+		return 2
+	}
+
+	store, err := env.openStore()
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	defer store.Close()
+
+	var sessions []db.Session
+	var anns []db.Annotation
+	for _, id := range fs.Args() {
+		sess, err := store.GetSession(id)
+		if err != nil {
+			return fail(env, *jsonOut, err)
+		}
+		if sess == nil {
+			return fail(env, *jsonOut, fmt.Errorf("session %s not found", id))
+		}
+		a, err := store.AnnotationsForSession(sess.ID)
+		if err != nil {
+			return fail(env, *jsonOut, err)
+		}
+		sessions = append(sessions, *sess)
+		anns = append(anns, a...)
+	}
+
+	if *out == "" {
+		if _, err := export.AnkiTSV(env.Stdout, sessions, anns, *deck); err != nil {
+			return fail(env, false, err)
+		}
+		return 0
+	}
+	f, err := os.Create(*out)
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	n, werr := export.AnkiTSV(f, sessions, anns, *deck)
+	if err := errors.Join(werr, f.Close()); err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	if *jsonOut {
+		if err := writeJSON(env.Stdout, ankiOutput{File: *out, Sessions: len(sessions), Cards: n}); err != nil {
+			return fail(env, false, err)
+		}
+		return 0
+	}
+	fmt.Fprintf(env.Stderr, "Wrote %d cards to %s\n", n, *out)
+	return 0
+}
@@ -0,0 +1,156 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/jwulff/steno/internal/db"
+	"github.com/jwulff/steno/internal/export"
+)
+
+// annotationOutput is one entry of the `steno annotations --json` shape,
+// and the whole `steno annotate --json` result.
+type annotationOutput struct {
+	ID         string `json:"id"`
+	SessionID  string `json:"session_id"`
+	SegmentSeq int    `json:"segment_seq"`
+	Kind       string `json:"kind"`
+	Body       string `json:"body,omitempty"`
+	Quote      string `json:"quote"`
+	Permalink  string `json:"permalink"`
+	CreatedAt  string `json:"created_at"`
+}
+
+type annotationsOutput struct {
+	SessionID   string             `json:"session_id"`
+	Annotations []annotationOutput `json:"annotations"`
+}
+
+func formatAnnotation(a db.Annotation) annotationOutput {
+	return annotationOutput{
+		ID:         a.ID,
+		SessionID:  a.SessionID,
+		SegmentSeq: a.SegmentSeq,
+		Kind:       a.Kind,
+		Body:       a.Body,
+		Quote:      a.SegmentText,
+		Permalink:  export.SegmentPermalink(a.SessionID, a.SegmentSeq),
+		CreatedAt:  a.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// runAnnotate attaches a bookmark (default), note, or reaction to one
+// segment.
+func runAnnotate(env Env, args []string) int {
+	fs, jsonOut := newFlagSet(env, "annotate")
+	note := fs.String("note", "", "Attach a note instead of a bookmark")
+	react := fs.String("react", "", "Attach a reaction (e.g. an emoji) instead of a bookmark")
+	fs.Usage = func() {
+		fmt.Fprintln(env.Stderr, "Usage: steno annotate [--note TEXT | --react EMOJI] [--json] <session-id> <segment-seq>")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 2 || (*note != "" && *react != "") {
+		fs.Usage()
+		return 2
+	}
+	seq, err := strconv.Atoi(fs.Arg(1))
+	if err != nil {
+		return fail(env, *jsonOut, fmt.Errorf("invalid segment sequence %q", fs.Arg(1)))
+	}
+	kind, body := db.AnnotationBookmark, ""
+	switch {
+	case *note != "":
+		kind, body = db.AnnotationNote, *note
+	case *react != "":
+		kind, body = db.AnnotationReaction, *react
+	}
+
+	store, err := env.openClientStore()
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	defer store.Close()
+
+	a, err := store.AddAnnotation(fs.Arg(0), seq, kind, body)
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	if *jsonOut {
+		if err := writeJSON(env.Stdout, formatAnnotation(*a)); err != nil {
+			return fail(env, false, err)
+		}
+		return 0
+	}
+	fmt.Fprintf(env.Stdout, "Added %s on #%d: %s\n", a.Kind, a.SegmentSeq, export.SegmentPermalink(a.SessionID, a.SegmentSeq))
+	return 0
+}
+
+// runAnnotations exports a session's annotations as review-style Markdown
+// (or JSON with --json).
+func runAnnotations(env Env, args []string) int {
+	fs, jsonOut := newFlagSet(env, "annotations")
+	out := fs.String("o", "", "Write the Markdown to this file instead of stdout")
+	fs.Usage = func() {
+		fmt.Fprintln(env.Stderr, "Usage: steno annotations [--json] [-o FILE] <session-id>")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+
+	store, err := env.openStore()
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	defer store.Close()
+
+	sess, err := store.GetSession(fs.Arg(0))
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	if sess == nil {
+		return fail(env, *jsonOut, fmt.Errorf("session %s not found", fs.Arg(0)))
+	}
+	anns, err := store.AnnotationsForSession(sess.ID)
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+
+	if *jsonOut {
+		result := annotationsOutput{SessionID: sess.ID, Annotations: make([]annotationOutput, 0, len(anns))}
+		for _, a := range anns {
+			result.Annotations = append(result.Annotations, formatAnnotation(a))
+		}
+		if err := writeJSON(env.Stdout, result); err != nil {
+			return fail(env, false, err)
+		}
+		return 0
+	}
+
+	if *out == "" {
+		if err := export.AnnotationsMarkdown(env.Stdout, *sess, anns); err != nil {
+			return fail(env, false, err)
+		}
+		return 0
+	}
+	f, err := os.Create(*out)
+	if err != nil {
+		return fail(env, false, err)
+	}
+	werr := export.AnnotationsMarkdown(f, *sess, anns)
+	if err := errors.Join(werr, f.Close()); err != nil {
+		return fail(env, false, err)
+	}
+	fmt.Fprintf(env.Stderr, "Wrote %d annotations to %s\n", len(anns), *out)
+	return 0
+}
@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/jwulff/steno/internal/bugreport"
+	"github.com/jwulff/steno/internal/config"
+	"github.com/jwulff/steno/internal/daemon"
+)
+
+type bugReportOutput struct {
+	Path string `json:"path"`
+}
+
+// runBugReport writes a bug-report zip (versions, schema, daemon log, the
+// status/version exchange) and prints its path. The TUI's `!` key builds
+// the same bundle plus its own state and recent protocol traffic.
+func runBugReport(env Env, args []string) int {
+	fs, jsonOut := newFlagSet(env, "bugreport")
+	out := fs.String("o", "", "Write the zip here instead of "+bugreport.DefaultDir())
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	cfg, err := config.Load(config.Path())
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	scrubber, err := bugreport.NewScrubber(cfg.Scrub.Patterns)
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+
+	log := daemon.NewMessageLog(bugreport.MessageCount)
+	v := queryVersions(env, log)
+	r := bugreport.Report{
+		CreatedAt: time.Now(),
+		Versions: bugreport.Versions{
+			Steno:          v.Version,
+			StenoBuild:     v.Build,
+			Daemon:         v.DaemonVersion,
+			DaemonBuild:    v.DaemonBuild,
+			Skew:           v.Skew,
+			Protocol:       v.Protocol,
+			DaemonProtocol: v.DaemonProtocol,
+		},
+		DaemonLog: daemon.NewManager().LogTail(bugreport.LogLines),
+		Scrubber:  scrubber,
+	}
+	if v.DaemonRunning {
+		if client, err := daemon.Connect(env.socketPath()); err == nil {
+			client.SetMessageLog(log)
+			_, _ = client.SendCommand(daemon.Command{Cmd: "status"})
+			client.Close()
+		}
+	}
+	r.Messages = log.Messages()
+
+	if store, err := env.openStore(); err != nil {
+		r.SchemaError = err.Error()
+	} else {
+		if info, err := store.SchemaInfo(); err != nil {
+			r.SchemaError = err.Error()
+		} else {
+			r.Schema = &info
+		}
+		store.Close()
+	}
+
+	var path string
+	if *out != "" {
+		path = *out
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+		if err != nil {
+			return fail(env, *jsonOut, err)
+		}
+		if err := bugreport.Write(f, r); err != nil {
+			f.Close()
+			return fail(env, *jsonOut, err)
+		}
+		if err := f.Close(); err != nil {
+			return fail(env, *jsonOut, err)
+		}
+	} else if path, err = bugreport.Save(bugreport.DefaultDir(), r); err != nil {
+		return fail(env, *jsonOut, err)
+	}
+
+	if *jsonOut {
+		if err := writeJSON(env.Stdout, bugReportOutput{Path: path}); err != nil {
+			return fail(env, false, err)
+		}
+		return 0
+	}
+	fmt.Fprintf(env.Stdout, "Bug report written to %s\n", path)
+	fmt.Fprintln(env.Stdout, "Review it, then attach it to your GitHub issue.")
+	return 0
+}
@@ -0,0 +1,142 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jwulff/steno/internal/calibration"
+)
+
+// correctOutput is the `steno correct --json` shape.
+type correctOutput struct {
+	SessionID  string `json:"session_id"`
+	SegmentSeq int    `json:"segment_seq"`
+	Text       string `json:"text"`
+}
+
+// calibrationRowOutput is one entry of the `steno calibration --json`
+// shape.
+type calibrationRowOutput struct {
+	Device   string  `json:"device"`
+	Locale   string  `json:"locale"`
+	Bucket   string  `json:"confidence"`
+	Segments int     `json:"segments"`
+	Edited   int     `json:"edited"`
+	Words    int     `json:"words"`
+	Errors   int     `json:"errors"`
+	WER      float64 `json:"wer"`
+}
+
+type calibrationOutput struct {
+	Rows []calibrationRowOutput `json:"rows"`
+}
+
+// runCorrect records what was actually said in a segment the ASR misheard.
+// Corrections are kept beside the daemon's text for `steno calibration`;
+// transcripts and exports still show what the ASR heard.
+func runCorrect(env Env, args []string) int {
+	fs, jsonOut := newFlagSet(env, "correct")
+	fs.Usage = func() {
+		fmt.Fprintln(env.Stderr, "Usage: steno correct [--json] <session-id> <segment-seq> <text>...")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() < 3 {
+		fs.Usage()
+		return 2
+	}
+	seq, err := strconv.Atoi(fs.Arg(1))
+	if err != nil {
+		return fail(env, *jsonOut, fmt.Errorf("invalid segment sequence %q", fs.Arg(1)))
+	}
+	text := strings.Join(fs.Args()[2:], " ")
+
+	store, err := env.openClientStore()
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	defer store.Close()
+
+	if err := store.EditSegment(fs.Arg(0), seq, text); err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	if *jsonOut {
+		if err := writeJSON(env.Stdout, correctOutput{SessionID: fs.Arg(0), SegmentSeq: seq, Text: strings.TrimSpace(text)}); err != nil {
+			return fail(env, false, err)
+		}
+		return 0
+	}
+	fmt.Fprintf(env.Stdout, "Recorded correction for #%d\n", seq)
+	return 0
+}
+
+// runCalibration reports word error rates by device, locale and reported
+// confidence, over the sessions the user has corrected with `steno
+// correct`. Every segment of a corrected session counts, so the
+// uncorrected ones are taken as heard right: correct a session fully
+// before relying on its numbers.
+func runCalibration(env Env, args []string) int {
+	fs, jsonOut := newFlagSet(env, "calibration")
+	fs.Usage = func() {
+		fmt.Fprintln(env.Stderr, "Usage: steno calibration [--json]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return 2
+	}
+
+	store, err := env.openStore()
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	defer store.Close()
+
+	samples, err := store.CalibrationSamples()
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	rows := calibration.Report(samples)
+
+	if *jsonOut {
+		out := calibrationOutput{Rows: make([]calibrationRowOutput, 0, len(rows))}
+		for _, r := range rows {
+			out.Rows = append(out.Rows, calibrationRowOutput{
+				Device:   r.Device,
+				Locale:   r.Locale,
+				Bucket:   r.Bucket,
+				Segments: r.Segments,
+				Edited:   r.Edited,
+				Words:    r.Words,
+				Errors:   r.Errors,
+				WER:      r.WER(),
+			})
+		}
+		if err := writeJSON(env.Stdout, out); err != nil {
+			return fail(env, false, err)
+		}
+		return 0
+	}
+	if len(rows) == 0 {
+		fmt.Fprintln(env.Stderr, "No corrected sessions yet. Record corrections with: steno correct <session-id> <seq> <text>")
+		return 0
+	}
+	tw := tabwriter.NewWriter(env.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "DEVICE\tLOCALE\tCONFIDENCE\tSEGMENTS\tEDITED\tWORDS\tWER")
+	for _, r := range rows {
+		device := r.Device
+		if device == "" {
+			device = "(unknown)"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%d\t%d\t%.1f%%\n", device, r.Locale, r.Bucket, r.Segments, r.Edited, r.Words, r.WER()*100)
+	}
+	tw.Flush()
+	return 0
+}
@@ -0,0 +1,126 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/jwulff/steno/internal/config"
+	"github.com/jwulff/steno/internal/export"
+)
+
+// chaptersOutput is the `steno chapters --json` shape.
+type chaptersOutput struct {
+	SessionID string          `json:"session_id"`
+	By        string          `json:"by"`
+	Chapters  []chapterOutput `json:"chapters"`
+}
+
+type chapterOutput struct {
+	StartSeconds float64 `json:"start_seconds"`
+	EndSeconds   float64 `json:"end_seconds"`
+	Title        string  `json:"title"`
+}
+
+// runChapters exports a session's chapter markers as SRT, WebVTT,
+// YouTube description timestamps, or Podcasting 2.0 chapter JSON.
+// Chapters follow the session's topics or a fixed interval; the config's
+// `export` section sets the defaults and flags override them.
+func runChapters(env Env, args []string) int {
+	fs, jsonOut := newFlagSet(env, "chapters")
+	format := fs.String("format", export.ChapterFormatYouTube, "Output format: "+strings.Join(export.ChapterFormats, ", "))
+	by := fs.String("by", "", "Chapter boundaries: topics or interval (default from config, else topics)")
+	interval := fs.Duration("interval", 0, "Chapter length with --by interval (default from config, else 5m)")
+	out := fs.String("o", "", "Write the chapters to this file instead of stdout")
+	fs.Usage = func() {
+		fmt.Fprintln(env.Stderr, "Usage: steno chapters [--format F] [--by topics|interval] [--interval D] [--json] [-o FILE] <session-id>")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+	if !slices.Contains(export.ChapterFormats, *format) {
+		return fail(env, *jsonOut, fmt.Errorf("unknown --format %q: want one of %s", *format, strings.Join(export.ChapterFormats, ", ")))
+	}
+
+	cfg, err := config.Load(config.Path())
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	opts := export.ChapterOptions{By: *by, Interval: *interval}
+	if opts.By == "" {
+		opts.By = cfg.Export.ChaptersBy
+	}
+	if opts.Interval == 0 {
+		if opts.Interval, err = cfg.Export.Interval(); err != nil {
+			return fail(env, *jsonOut, err)
+		}
+	}
+
+	store, err := env.openStore()
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	defer store.Close()
+
+	sess, err := store.GetSession(fs.Arg(0))
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	if sess == nil {
+		return fail(env, *jsonOut, fmt.Errorf("session %s not found", fs.Arg(0)))
+	}
+	segments, err := store.SegmentsForSession(sess.ID, -1, 0)
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	topics, err := store.TopicsForSession(sess.ID)
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	chapters, err := export.Chapters(segments, topics, opts)
+	if err != nil {
+		return fail(env, *jsonOut, fmt.Errorf("chapters for %s: %w", sess.ID, err))
+	}
+
+	if *jsonOut {
+		result := chaptersOutput{SessionID: sess.ID, By: opts.By, Chapters: make([]chapterOutput, 0, len(chapters))}
+		if result.By == "" {
+			result.By = export.ChaptersByTopic
+		}
+		for _, c := range chapters {
+			result.Chapters = append(result.Chapters, chapterOutput{
+				StartSeconds: c.Start.Seconds(),
+				EndSeconds:   c.End.Seconds(),
+				Title:        c.Title,
+			})
+		}
+		if err := writeJSON(env.Stdout, result); err != nil {
+			return fail(env, false, err)
+		}
+		return 0
+	}
+
+	if *out == "" {
+		if err := export.WriteChapters(env.Stdout, *format, chapters); err != nil {
+			return fail(env, false, err)
+		}
+		return 0
+	}
+	f, err := os.Create(*out)
+	if err != nil {
+		return fail(env, false, err)
+	}
+	werr := export.WriteChapters(f, *format, chapters)
+	if err := errors.Join(werr, f.Close()); err != nil {
+		return fail(env, false, err)
+	}
+	fmt.Fprintf(env.Stderr, "Wrote %d chapters to %s\n", len(chapters), *out)
+	return 0
+}
@@ -0,0 +1,173 @@
+// Package cli implements the non-interactive `steno <subcommand>` surface
+// (status, devices, sessions, ...). Every subcommand accepts `--json` and
+// emits a stable, snake_case structure on stdout so shell scripts and
+// integration tests can consume it without scraping the human format.
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/jwulff/steno/internal/config"
+	"github.com/jwulff/steno/internal/daemon"
+	"github.com/jwulff/steno/internal/db"
+)
+
+// Env carries the dependencies a subcommand needs. Injected so tests can
+// point at a fake daemon socket and a scratch database.
+type Env struct {
+	Stdout io.Writer
+	Stderr io.Writer
+
+	// SocketPath is the daemon Unix socket. Empty means daemon.SocketPath().
+	SocketPath string
+
+	// DBPath is the steno SQLite database. Empty means $STENO_DB, falling
+	// back to db.DefaultDBPath().
+	DBPath string
+}
+
+// DefaultEnv returns an Env wired to the real stdout/stderr and paths.
+func DefaultEnv() Env {
+	return Env{Stdout: os.Stdout, Stderr: os.Stderr}
+}
+
+func (e Env) socketPath() string {
+	if e.SocketPath != "" {
+		return e.SocketPath
+	}
+	return daemon.SocketPath()
+}
+
+func (e Env) dbPath() string {
+	if e.DBPath != "" {
+		return e.DBPath
+	}
+	if p := os.Getenv("STENO_DB"); p != "" {
+		return p
+	}
+	return db.DefaultDBPath()
+}
+
+// dbOptions are the connection options from the config file's database
+// section. A config file that doesn't load is warned about rather than
+// failing commands that only read the database.
+func (e Env) dbOptions() db.Options {
+	opts, err := config.DatabaseOptions(config.Path())
+	if err != nil {
+		fmt.Fprintf(e.Stderr, "warning: %v; opening the database anyway\n", err)
+	}
+	return opts
+}
+
+// openDB opens the database with open, with the same "no database yet"
+// message the MCP server prints.
+func (e Env) openDB(open func(string, db.Options) (*db.Store, error)) (*db.Store, error) {
+	path := e.dbPath()
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil, fmt.Errorf("no steno database found at %s", path)
+	}
+	return open(path, e.dbOptions())
+}
+
+// openStore opens the database read-only.
+func (e Env) openStore() (*db.Store, error) {
+	return e.openDB(db.Open)
+}
+
+// openClientStore opens the database read-write for commands that only
+// touch client-owned tables (annotate).
+func (e Env) openClientStore() (*db.Store, error) {
+	return e.openDB(db.OpenClient)
+}
+
+// openMaintenanceStore opens the database read-write for commands that
+// modify daemon-owned rows (merge).
+func (e Env) openMaintenanceStore() (*db.Store, error) {
+	return e.openDB(db.OpenMaintenance)
+}
+
+// command is one `steno <name>` entry.
+type command struct {
+	summary string
+	run     func(env Env, args []string) int
+}
+
+var commands = map[string]command{
+	"status":      {summary: "Show daemon recording status", run: runStatus},
+	"devices":     {summary: "List audio input devices known to the daemon", run: runDevices},
+	"sessions":    {summary: "List recorded sessions", run: runSessions},
+	"merge":       {summary: "Merge two sessions split by a crash (--dry-run to preview)", run: runMerge},
+	"serve":       {summary: "Serve a read-only web viewer of sessions (--web), or a start/stop/mark/note endpoint (--control)", run: runServe},
+	"annotate":    {summary: "Bookmark, note, or react to a segment", run: runAnnotate},
+	"annotations": {summary: "Export a session's annotations as review-style Markdown", run: runAnnotations},
+	"version":     {summary: "Show steno and daemon versions; exit 1 on skew", run: runVersion},
+	"bugreport":   {summary: "Write a scrubbed diagnostics zip to attach to an issue", run: runBugReport},
+	"toggle":      {summary: "Pause recording, or resume it if paused", run: runToggle},
+	"hotkey":      {summary: "Print an skhd binding for a global pause/resume shortcut", run: runHotkey},
+	"chapters":    {summary: "Export chapter markers (SRT, WebVTT, YouTube, podcast JSON)", run: runChapters},
+	"meeting":     {summary: "Record a session's meeting link for the session browser", run: runMeeting},
+	"followup":    {summary: "Write a calendar event or to-do following up on a session's topic", run: runFollowUp},
+	"maintain":    {summary: "Vacuum, analyze and checkpoint the database while idle", run: runMaintain},
+	"export":      {summary: "Export a session's transcript as Markdown, Whisper JSON or subtitles, optionally anonymized", run: runExport},
+	"csv":         {summary: "Export sessions' segments and topics as CSV for spreadsheets", run: runCSV},
+	"anki":        {summary: "Export sessions' annotated segments as Anki flashcards", run: runAnki},
+	"notes":       {summary: "Maintain a git-friendly directory of per-session Markdown notes", run: runNotes},
+	"speakers":    {summary: "List or set the names of a session's diarized speakers", run: runSpeakers},
+	"relabel":     {summary: "Move a session's segments from one diarized speaker to another (--dry-run, --undo)", run: runRelabel},
+	"decisions":   {summary: "Flag and list the decisions recorded in a session", run: runDecisions},
+	"entities":    {summary: "List the dates, times, amounts and links said in a session", run: runEntities},
+	"codes":       {summary: "Count the configured research codes in a session, as a CSV time series", run: runCodes},
+	"consent":     {summary: "Show or record a session's recording-consent acknowledgment", run: runConsent},
+	"searches":    {summary: "List, save, run or delete saved searches (keyword alerts in the TUI)", run: runSearches},
+	"correct":     {summary: "Record what was actually said in a misheard segment", run: runCorrect},
+	"calibration": {summary: "Report word error rates by device, locale and ASR confidence", run: runCalibration},
+	"quality":     {summary: "Show a session's capture-quality report, or the trend across sessions", run: runQuality},
+	"macros":      {summary: "List, add, delete or test dictation macros", run: runMacros},
+	"dictate":     {summary: "Append what the mic hears to a file until Ctrl-C, with dictation macros", run: runDictate},
+	"aggregate":   {summary: "Follow several daemons at once, tagging each one's events", run: runAggregate},
+}
+
+// Run dispatches args[0] to its subcommand and returns the process exit
+// code. args excludes the program name.
+func Run(env Env, args []string) int {
+	if len(args) == 0 || args[0] == "help" {
+		printUsage(env.Stdout)
+		return 0
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(env.Stderr, "steno: unknown command %q\n\n", args[0])
+		printUsage(env.Stderr)
+		return 2
+	}
+	return cmd.run(env, args[1:])
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: steno [--mcp] [command] [--json]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "With no command, steno opens the TUI; `steno tutorial` opens it on a")
+	fmt.Fprintln(w, "guided tour of a canned meeting. Commands:")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-12s %s\n", name, commands[name].summary)
+	}
+}
+
+// newFlagSet builds a subcommand FlagSet with the shared `--json` flag.
+// Parse errors are written to env.Stderr and reported via ContinueOnError
+// so Run can return an exit code instead of calling os.Exit.
+func newFlagSet(env Env, name string) (*flag.FlagSet, *bool) {
+	fs := flag.NewFlagSet("steno "+name, flag.ContinueOnError)
+	fs.SetOutput(env.Stderr)
+	jsonOut := fs.Bool("json", false, "Emit machine-readable JSON on stdout")
+	return fs, jsonOut
+}
@@ -0,0 +1,1847 @@
+package cli
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/jwulff/steno/internal/config"
+	"github.com/jwulff/steno/internal/daemon"
+	"github.com/jwulff/steno/internal/db"
+	"github.com/jwulff/steno/internal/export"
+	"github.com/jwulff/steno/internal/version"
+	_ "modernc.org/sqlite"
+)
+
+// mockDaemon serves canned responses keyed by command name on a Unix
+// socket and returns its path. Unknown commands get {"ok":false}.
+func mockDaemon(t *testing.T, responses map[string]daemon.Response) string {
+	t.Helper()
+
+	// Short /tmp path: macOS caps sun_path at 104 bytes.
+	sockPath := fmt.Sprintf("/tmp/steno-cli-%d.sock", time.Now().UnixNano())
+	ln, err := net.Listen("unix", sockPath)
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() {
+		ln.Close()
+		os.Remove(sockPath)
+	})
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				scanner := bufio.NewScanner(conn)
+				for scanner.Scan() {
+					var cmd daemon.Command
+					_ = json.Unmarshal(scanner.Bytes(), &cmd)
+					resp, ok := responses[cmd.Cmd]
+					if !ok {
+						resp = daemon.Response{OK: false, Error: "Unknown command: " + cmd.Cmd}
+					}
+					data, _ := json.Marshal(resp)
+					conn.Write(append(data, '\n'))
+				}
+			}(conn)
+		}
+	}()
+	return sockPath
+}
+
+// testDBFile creates an on-disk database with the subset of the schema the
+// CLI reads, seeded with two sessions.
+func testDBFile(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "steno.sqlite")
+	d, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer d.Close()
+
+	stmts := []string{
+		`CREATE TABLE sessions (id TEXT PRIMARY KEY, locale TEXT NOT NULL, startedAt REAL NOT NULL, endedAt REAL, title TEXT, status TEXT NOT NULL DEFAULT 'active', createdAt REAL NOT NULL)`,
+		`CREATE TABLE segments (id TEXT PRIMARY KEY, sessionId TEXT NOT NULL, text TEXT NOT NULL, startedAt REAL NOT NULL, endedAt REAL NOT NULL, confidence REAL, sequenceNumber INTEGER NOT NULL, createdAt REAL NOT NULL, source TEXT NOT NULL DEFAULT 'microphone', duplicate_of TEXT, dedup_method TEXT, heal_marker TEXT, mic_peak_db REAL)`,
+		`CREATE TABLE topics (id TEXT PRIMARY KEY, sessionId TEXT NOT NULL, title TEXT NOT NULL, summary TEXT NOT NULL, segmentRangeStart INTEGER NOT NULL, segmentRangeEnd INTEGER NOT NULL, createdAt REAL NOT NULL)`,
+		`CREATE TABLE summaries (id TEXT PRIMARY KEY, sessionId TEXT NOT NULL, content TEXT NOT NULL, summaryType TEXT NOT NULL, segmentRangeStart INTEGER NOT NULL, segmentRangeEnd INTEGER NOT NULL, modelId TEXT NOT NULL, createdAt REAL NOT NULL)`,
+		`INSERT INTO sessions (id, locale, startedAt, endedAt, title, status, createdAt) VALUES ('sess-1', 'en_US', 1710000000, 1710003600, 'Team Standup', 'completed', 1710000000)`,
+		`INSERT INTO sessions (id, locale, startedAt, status, createdAt) VALUES ('sess-2', 'en_US', 1710007200, 'active', 1710007200)`,
+		`INSERT INTO segments (id, sessionId, text, startedAt, endedAt, sequenceNumber, createdAt) VALUES ('seg-1', 'sess-1', 'hello', 1710000010, 1710000019, 1, 1710000010)`,
+	}
+	for _, s := range stmts {
+		if _, err := d.Exec(s); err != nil {
+			t.Fatalf("exec %q: %v", s, err)
+		}
+	}
+	return path
+}
+
+func testEnv(sock, dbPath string) (Env, *bytes.Buffer, *bytes.Buffer) {
+	var stdout, stderr bytes.Buffer
+	return Env{Stdout: &stdout, Stderr: &stderr, SocketPath: sock, DBPath: dbPath}, &stdout, &stderr
+}
+
+func TestStatusJSON(t *testing.T) {
+	expires := 1710000000.0
+	sock := mockDaemon(t, map[string]daemon.Response{
+		"status": {
+			OK:                true,
+			SessionID:         "sess-1",
+			Recording:         daemon.BoolPtr(false),
+			Status:            "paused",
+			Device:            "MacBook Pro Microphone",
+			SystemAudio:       daemon.BoolPtr(true),
+			Paused:            daemon.BoolPtr(true),
+			PauseExpiresAt:    &expires,
+			LastSegmentAt:     &expires,
+			CallApp:           "Zoom",
+			ThermalState:      "serious",
+			LowPower:          daemon.BoolPtr(false),
+			SummariesDeferred: daemon.BoolPtr(true),
+		},
+	})
+	env, stdout, _ := testEnv(sock, "")
+
+	if code := Run(env, []string{"status", "--json"}); code != 0 {
+		t.Fatalf("exit = %d, want 0", code)
+	}
+
+	var got statusOutput
+	if err := json.Unmarshal(stdout.Bytes(), &got); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, stdout.String())
+	}
+	if !got.Running || got.Status != "paused" || !got.Paused || !got.SystemAudio || got.CallApp != "Zoom" {
+		t.Errorf("unexpected status: %+v", got)
+	}
+	if got.PauseExpiresAt == nil {
+		t.Error("expected pause_expires_at")
+	}
+	if got.ThermalState != "serious" || got.LowPower || !got.SummariesDeferred {
+		t.Errorf("pressure = %q, low power %v, deferred %v", got.ThermalState, got.LowPower, got.SummariesDeferred)
+	}
+	if got.LastSegmentAt == nil || *got.LastSegmentAt != *got.PauseExpiresAt {
+		t.Errorf("last_segment_at = %v", got.LastSegmentAt)
+	}
+}
+
+func TestStatusNotRunning(t *testing.T) {
+	env, stdout, _ := testEnv("/nonexistent/steno.sock", "")
+
+	if code := Run(env, []string{"status", "--json"}); code != 1 {
+		t.Errorf("exit = %d, want 1", code)
+	}
+	var raw map[string]any
+	if err := json.Unmarshal(stdout.Bytes(), &raw); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, stdout.String())
+	}
+	if raw["running"] != false {
+		t.Errorf("running = %v, want false", raw["running"])
+	}
+}
+
+func TestDevicesJSONEmptyIsArray(t *testing.T) {
+	sock := mockDaemon(t, map[string]daemon.Response{"devices": {OK: true}})
+	env, stdout, _ := testEnv(sock, "")
+
+	if code := Run(env, []string{"devices", "--json"}); code != 0 {
+		t.Fatalf("exit = %d, want 0", code)
+	}
+	if got := bytes.TrimSpace(stdout.Bytes()); !bytes.Contains(got, []byte(`"devices": []`)) {
+		t.Errorf("expected empty devices array, got %s", got)
+	}
+}
+
+func TestDevicesText(t *testing.T) {
+	sock := mockDaemon(t, map[string]daemon.Response{
+		"devices": {OK: true, Devices: []string{"Mic A", "Mic B"}},
+	})
+	env, stdout, _ := testEnv(sock, "")
+
+	if code := Run(env, []string{"devices"}); code != 0 {
+		t.Fatalf("exit = %d, want 0", code)
+	}
+	if stdout.String() != "Mic A\nMic B\n" {
+		t.Errorf("stdout = %q", stdout.String())
+	}
+}
+
+func TestSessionsJSON(t *testing.T) {
+	env, stdout, _ := testEnv("", testDBFile(t))
+
+	if code := Run(env, []string{"sessions", "--json"}); code != 0 {
+		t.Fatalf("exit = %d, want 0", code)
+	}
+	var got sessionsOutput
+	if err := json.Unmarshal(stdout.Bytes(), &got); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, stdout.String())
+	}
+	if len(got.Sessions) != 2 {
+		t.Fatalf("sessions = %d, want 2", len(got.Sessions))
+	}
+	// Newest first.
+	if got.Sessions[0].ID != "sess-2" {
+		t.Errorf("sessions[0] = %q, want sess-2", got.Sessions[0].ID)
+	}
+	if got.Sessions[1].SegmentCount != 1 {
+		t.Errorf("sess-1 segment_count = %d, want 1", got.Sessions[1].SegmentCount)
+	}
+}
+
+func TestSessionsDespiteBadConfig(t *testing.T) {
+	cfgPath := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(cfgPath, []byte(`{"display": {"density": "huge"}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("STENO_CONFIG", cfgPath)
+
+	env, _, stderr := testEnv("", testDBFile(t))
+	if code := Run(env, []string{"sessions"}); code != 0 {
+		t.Fatalf("exit = %d, stderr = %s", code, stderr.String())
+	}
+	if !strings.Contains(stderr.String(), "warning: ") || !strings.Contains(stderr.String(), "display.density") {
+		t.Errorf("stderr = %q, want a warning about the config", stderr.String())
+	}
+}
+
+func TestSessionsStatusFilter(t *testing.T) {
+	env, stdout, _ := testEnv("", testDBFile(t))
+
+	if code := Run(env, []string{"sessions", "--json", "--status", "completed"}); code != 0 {
+		t.Fatalf("exit = %d, want 0", code)
+	}
+	var got sessionsOutput
+	json.Unmarshal(stdout.Bytes(), &got)
+	if len(got.Sessions) != 1 || got.Sessions[0].ID != "sess-1" {
+		t.Errorf("got %+v, want only sess-1", got.Sessions)
+	}
+}
+
+func TestSessionsMissingDBJSONError(t *testing.T) {
+	env, stdout, _ := testEnv("", filepath.Join(t.TempDir(), "missing.sqlite"))
+
+	if code := Run(env, []string{"sessions", "--json"}); code != 1 {
+		t.Errorf("exit = %d, want 1", code)
+	}
+	var got errorOutput
+	if err := json.Unmarshal(stdout.Bytes(), &got); err != nil || got.Error == "" {
+		t.Errorf("expected JSON error object, got %q", stdout.String())
+	}
+}
+
+func TestUnknownCommand(t *testing.T) {
+	env, _, stderr := testEnv("", "")
+	if code := Run(env, []string{"bogus"}); code != 2 {
+		t.Errorf("exit = %d, want 2", code)
+	}
+	if !bytes.Contains(stderr.Bytes(), []byte("unknown command")) {
+		t.Errorf("stderr = %q", stderr.String())
+	}
+}
+
+func TestSessionsDeviceFilter(t *testing.T) {
+	path := testDBFile(t)
+	d, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	for _, s := range []string{
+		`CREATE TABLE session_metadata (session_id TEXT PRIMARY KEY, device TEXT, system_audio INTEGER, updated_at REAL NOT NULL)`,
+		`INSERT INTO session_metadata VALUES ('sess-1', 'Conference Room', 0, 1710000000)`,
+		`INSERT INTO session_metadata VALUES ('sess-2', 'MacBook Pro Microphone', 1, 1710007200)`,
+	} {
+		if _, err := d.Exec(s); err != nil {
+			t.Fatalf("exec %q: %v", s, err)
+		}
+	}
+	d.Close()
+
+	env, stdout, _ := testEnv("", path)
+	if code := Run(env, []string{"sessions", "--json", "--device", "Conference Room"}); code != 0 {
+		t.Fatalf("exit = %d, want 0", code)
+	}
+	var got sessionsOutput
+	json.Unmarshal(stdout.Bytes(), &got)
+	if len(got.Sessions) != 1 || got.Sessions[0].ID != "sess-1" {
+		t.Fatalf("got %+v, want only sess-1", got.Sessions)
+	}
+	if got.Sessions[0].Device != "Conference Room" || got.Sessions[0].SystemAudio == nil || *got.Sessions[0].SystemAudio {
+		t.Errorf("unexpected metadata: %+v", got.Sessions[0])
+	}
+
+	stdout.Reset()
+	if code := Run(env, []string{"sessions", "--json", "--system-audio", "true"}); code != 0 {
+		t.Fatalf("exit = %d, want 0", code)
+	}
+	json.Unmarshal(stdout.Bytes(), &got)
+	if len(got.Sessions) != 1 || got.Sessions[0].ID != "sess-2" {
+		t.Errorf("got %+v, want only sess-2", got.Sessions)
+	}
+}
+
+func TestMergeDryRunThenApply(t *testing.T) {
+	path := testDBFile(t)
+	d, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	// Finish sess-2 so it can be merged; give it one later segment.
+	for _, s := range []string{
+		`UPDATE sessions SET status = 'completed', endedAt = 1710010000 WHERE id = 'sess-2'`,
+		`INSERT INTO segments (id, sessionId, text, startedAt, endedAt, sequenceNumber, createdAt) VALUES ('seg-2', 'sess-2', 'later', 1710007300, 1710007309, 1, 1710007300)`,
+	} {
+		if _, err := d.Exec(s); err != nil {
+			t.Fatalf("exec %q: %v", s, err)
+		}
+	}
+	d.Close()
+
+	env, stdout, _ := testEnv("", path)
+	if code := Run(env, []string{"merge", "--dry-run", "--json", "sess-1", "sess-2"}); code != 0 {
+		t.Fatalf("dry-run exit = %d, want 0: %s", code, stdout.String())
+	}
+	var got mergeOutput
+	if err := json.Unmarshal(stdout.Bytes(), &got); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, stdout.String())
+	}
+	if !got.DryRun || got.Segments != 2 || got.Timeline[1].ID != "seg-2" || got.Timeline[1].NewSeq != 2 {
+		t.Errorf("unexpected plan: %+v", got)
+	}
+
+	stdout.Reset()
+	if code := Run(env, []string{"sessions", "--json"}); code != 0 {
+		t.Fatalf("sessions exit = %d", code)
+	}
+	var listed sessionsOutput
+	json.Unmarshal(stdout.Bytes(), &listed)
+	if len(listed.Sessions) != 2 {
+		t.Fatalf("dry run changed the database: %d sessions", len(listed.Sessions))
+	}
+
+	stdout.Reset()
+	if code := Run(env, []string{"merge", "sess-1", "sess-2"}); code != 0 {
+		t.Fatalf("merge exit = %d", code)
+	}
+	stdout.Reset()
+	Run(env, []string{"sessions", "--json"})
+	json.Unmarshal(stdout.Bytes(), &listed)
+	if len(listed.Sessions) != 1 || listed.Sessions[0].SegmentCount != 2 {
+		t.Errorf("after merge: %+v", listed.Sessions)
+	}
+}
+
+func TestMergeRequiresTwoIDs(t *testing.T) {
+	env, _, _ := testEnv("", testDBFile(t))
+	if code := Run(env, []string{"merge", "sess-1"}); code != 2 {
+		t.Errorf("exit = %d, want 2", code)
+	}
+}
+
+func TestServeRequiresWebFlag(t *testing.T) {
+	env, _, stderr := testEnv("", testDBFile(t))
+	if code := Run(env, []string{"serve"}); code != 2 {
+		t.Errorf("exit = %d, want 2", code)
+	}
+	if !strings.Contains(stderr.String(), "--web") {
+		t.Errorf("stderr = %q, want usage", stderr.String())
+	}
+}
+
+func TestAnnotateThenExport(t *testing.T) {
+	dbPath := testDBFile(t)
+	env, stdout, stderr := testEnv("", dbPath)
+	if code := Run(env, []string{"annotate", "--note", "send the deck", "sess-1", "1"}); code != 0 {
+		t.Fatalf("annotate exit = %d, stderr = %s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "steno://session/sess-1#seg-1") {
+		t.Errorf("annotate stdout = %q, want permalink", stdout.String())
+	}
+
+	env, stdout, _ = testEnv("", dbPath)
+	if code := Run(env, []string{"annotations", "sess-1"}); code != 0 {
+		t.Fatalf("annotations exit = %d", code)
+	}
+	md := stdout.String()
+	if !strings.Contains(md, "> hello") || !strings.Contains(md, "send the deck") {
+		t.Errorf("markdown = %q", md)
+	}
+
+	env, stdout, _ = testEnv("", dbPath)
+	if code := Run(env, []string{"annotations", "--json", "sess-1"}); code != 0 {
+		t.Fatalf("annotations --json exit = %d", code)
+	}
+	var out annotationsOutput
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, stdout.String())
+	}
+	if len(out.Annotations) != 1 || out.Annotations[0].Kind != "note" || out.Annotations[0].Quote != "hello" {
+		t.Errorf("json = %+v", out)
+	}
+}
+
+func TestVersionSkew(t *testing.T) {
+	sock := mockDaemon(t, map[string]daemon.Response{
+		"version": {OK: true, Version: "9.9.0", Build: "release macOS 26.0"},
+	})
+	env, stdout, stderr := testEnv(sock, "")
+	if code := Run(env, []string{"version", "--json"}); code != 1 {
+		t.Errorf("exit = %d, want 1 on skew", code)
+	}
+	var out versionOutput
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, stdout.String())
+	}
+	if !out.DaemonRunning || out.DaemonVersion != "9.9.0" || !out.Skew || out.Version == "" {
+		t.Errorf("out = %+v", out)
+	}
+
+	// Same release, different protocol.
+	sock = mockDaemon(t, map[string]daemon.Response{
+		"version": {OK: true, Version: version.Version, ProtocolVersion: daemon.ProtocolVersion + 1},
+	})
+	env, stdout, stderr = testEnv(sock, "")
+	if code := Run(env, []string{"version"}); code != 1 {
+		t.Errorf("exit = %d, want 1 on protocol skew", code)
+	}
+	if !strings.Contains(stdout.String(), fmt.Sprintf("(daemon %d)", daemon.ProtocolVersion+1)) || !strings.Contains(stderr.String(), "newer than this steno's") {
+		t.Errorf("stdout = %q, stderr = %q", stdout.String(), stderr.String())
+	}
+
+	// A daemon without the command is "unknown", not skewed.
+	sock = mockDaemon(t, map[string]daemon.Response{})
+	env, stdout, stderr = testEnv(sock, "")
+	if code := Run(env, []string{"version"}); code != 0 {
+		t.Errorf("exit = %d, want 0 for pre-version daemon; stderr = %s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "predates the version command") {
+		t.Errorf("stdout = %q", stdout.String())
+	}
+}
+
+func TestBugReportBundle(t *testing.T) {
+	t.Setenv("STENO_CONFIG", filepath.Join(t.TempDir(), "none.json"))
+	sock := mockDaemon(t, map[string]daemon.Response{
+		"version": {OK: true, Version: "0.1.0", Build: "release"},
+		"status":  {OK: true, Status: "recording", SessionID: "sess-1"},
+	})
+	out := filepath.Join(t.TempDir(), "report.zip")
+	env, stdout, stderr := testEnv(sock, testDBFile(t))
+	if code := Run(env, []string{"bugreport", "--json", "-o", out}); code != 0 {
+		t.Fatalf("exit = %d, stderr = %s", code, stderr.String())
+	}
+	var res bugReportOutput
+	if err := json.Unmarshal(stdout.Bytes(), &res); err != nil || res.Path != out {
+		t.Fatalf("output = %s (%v)", stdout.String(), err)
+	}
+	zr, err := zip.OpenReader(out)
+	if err != nil {
+		t.Fatalf("open zip: %v", err)
+	}
+	defer zr.Close()
+	names := map[string]bool{}
+	for _, f := range zr.File {
+		names[f.Name] = true
+	}
+	if !names["versions.json"] || !names["schema.json"] || !names["protocol.ndjson"] {
+		t.Errorf("bundle files = %v", names)
+	}
+}
+
+func TestTogglePausesThenResumes(t *testing.T) {
+	expires := 1710001800.0
+	sock := mockDaemon(t, map[string]daemon.Response{
+		"status": {OK: true, Status: "recording", Paused: daemon.BoolPtr(false)},
+		"pause":  {OK: true, Paused: daemon.BoolPtr(true), PauseExpiresAt: &expires},
+	})
+	env, stdout, stderr := testEnv(sock, "")
+	if code := Run(env, []string{"toggle", "--json"}); code != 0 {
+		t.Fatalf("exit = %d, stderr = %s", code, stderr.String())
+	}
+	var out toggleOutput
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		t.Fatalf("parse: %v (%s)", err, stdout.String())
+	}
+	if out.Action != "paused" || !out.Paused || out.PauseExpiresAt == nil {
+		t.Errorf("toggle from recording = %+v", out)
+	}
+
+	sock = mockDaemon(t, map[string]daemon.Response{
+		"status": {OK: true, Status: "paused", Paused: daemon.BoolPtr(true)},
+		"resume": {OK: true, Paused: daemon.BoolPtr(false)},
+	})
+	env, stdout, _ = testEnv(sock, "")
+	if code := Run(env, []string{"toggle"}); code != 0 || strings.TrimSpace(stdout.String()) != "resumed" {
+		t.Errorf("toggle from paused: exit %d, %q", code, stdout.String())
+	}
+}
+
+func TestToggleWithoutDaemon(t *testing.T) {
+	env, _, stderr := testEnv("/tmp/steno-cli-missing.sock", "")
+	if code := Run(env, []string{"toggle"}); code != 1 || !strings.Contains(stderr.String(), "daemon not running") {
+		t.Errorf("exit = %d, stderr = %q", code, stderr.String())
+	}
+}
+
+func TestHotkeyPrintsSkhdBinding(t *testing.T) {
+	env, stdout, _ := testEnv("", "")
+	if code := Run(env, []string{"hotkey", "--json", "--key", "hyper - p", "--indefinite"}); code != 0 {
+		t.Fatalf("exit = %d", code)
+	}
+	var out hotkeyOutput
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if !strings.HasPrefix(out.Skhd, "hyper - p : ") || !strings.HasSuffix(out.Skhd, " toggle --indefinite") {
+		t.Errorf("skhd = %q", out.Skhd)
+	}
+
+	env, _, stderr := testEnv("", "")
+	if code := Run(env, []string{"hotkey", "--key", "a : b"}); code != 1 || stderr.Len() == 0 {
+		t.Errorf("bad key: exit %d", code)
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	for in, want := range map[string]string{
+		"/usr/local/bin/steno": "/usr/local/bin/steno",
+		"/Users/a b/bin/steno": "'/Users/a b/bin/steno'",
+		"/tmp/it's/steno":      `'/tmp/it'\''s/steno'`,
+	} {
+		if got := shellQuote(in); got != want {
+			t.Errorf("shellQuote(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestMeetingRecordsLink(t *testing.T) {
+	dbPath := testDBFile(t)
+	env, _, stderr := testEnv("", dbPath)
+	if code := Run(env, []string{"meeting", "sess-1", "https://meet.example.com/abc-defg"}); code != 0 {
+		t.Fatalf("meeting exit = %d, stderr = %s", code, stderr.String())
+	}
+
+	env, stdout, _ := testEnv("", dbPath)
+	if code := Run(env, []string{"sessions", "--json"}); code != 0 {
+		t.Fatalf("sessions exit = %d", code)
+	}
+	var out sessionsOutput
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, stdout.String())
+	}
+	found := false
+	for _, s := range out.Sessions {
+		if s.ID == "sess-1" {
+			found = s.MeetingURL == "https://meet.example.com/abc-defg"
+		}
+	}
+	if !found {
+		t.Errorf("sessions = %+v, want sess-1 with its meeting link", out.Sessions)
+	}
+
+	for _, args := range [][]string{
+		{"meeting", "sess-1", "zoom"},
+		{"meeting", "sess-1", "javascript:alert(1)"},
+		{"meeting", "no-such-session", "https://meet.example.com/x"},
+	} {
+		env, _, _ := testEnv("", dbPath)
+		if code := Run(env, args); code != 1 {
+			t.Errorf("%v exit = %d, want 1", args, code)
+		}
+	}
+}
+
+func TestExportAnonymized(t *testing.T) {
+	t.Setenv("STENO_CONFIG", filepath.Join(t.TempDir(), "none.json"))
+	dbPath := testDBFile(t)
+	d, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.Exec(`INSERT INTO segments (id, sessionId, text, startedAt, endedAt, sequenceNumber, createdAt, source) VALUES ('seg-2', 'sess-1', 'mail me at jane@example.com', 1710000030, 1710000035, 2, 1710000030, 'systemAudio')`); err != nil {
+		t.Fatal(err)
+	}
+	d.Close()
+
+	env, stdout, stderr := testEnv("", dbPath)
+	if code := Run(env, []string{"export", "sess-1"}); code != 0 {
+		t.Fatalf("export exit = %d, stderr = %s", code, stderr.String())
+	}
+	if got := stdout.String(); !strings.Contains(got, "# Team Standup") || !strings.Contains(got, "jane@example.com") {
+		t.Errorf("plain export = %q", got)
+	}
+
+	out := filepath.Join(t.TempDir(), "standup.md")
+	env, _, stderr = testEnv("", dbPath)
+	if code := Run(env, []string{"export", "--anonymize", "full", "-o", out, "sess-1"}); code != 0 {
+		t.Fatalf("export exit = %d, stderr = %s", code, stderr.String())
+	}
+	md, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, leak := range []string{"Team Standup", "sess-1", "jane@example.com", "mic:"} {
+		if strings.Contains(string(md), leak) {
+			t.Errorf("anonymized export leaks %q:\n%s", leak, md)
+		}
+	}
+	if !strings.Contains(string(md), "**[00:00:20] Speaker 2:** mail me at [redacted]") {
+		t.Errorf("anonymized export:\n%s", md)
+	}
+	data, err := os.ReadFile(out + ".manifest.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var manifest export.Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		t.Fatalf("unmarshal manifest: %v\n%s", err, data)
+	}
+	a := manifest.Anonymization
+	if manifest.SessionID != "" || manifest.Segments != 2 || a == nil || a.Preset != "full" || a.Redactions != 1 || a.PseudonymizedSpeakers != 2 {
+		t.Errorf("manifest = %s", data)
+	}
+
+	// The configured preset applies unless overridden with none.
+	cfgPath := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(cfgPath, []byte(`{"export": {"anonymize": "gdpr-minimal"}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("STENO_CONFIG", cfgPath)
+	env, stdout, _ = testEnv("", dbPath)
+	if code := Run(env, []string{"export", "--json", "sess-1"}); code != 0 {
+		t.Fatalf("export --json exit = %d", code)
+	}
+	var got exportOutput
+	if err := json.Unmarshal(stdout.Bytes(), &got); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, stdout.String())
+	}
+	if got.Manifest.Anonymization == nil || got.Manifest.Anonymization.Preset != "gdpr-minimal" || got.Segments[1].Text != "mail me at [redacted]" {
+		t.Errorf("json = %+v", got)
+	}
+	env, stdout, _ = testEnv("", dbPath)
+	if code := Run(env, []string{"export", "--anonymize", "none", "sess-1"}); code != 0 || !strings.Contains(stdout.String(), "jane@example.com") {
+		t.Errorf("--anonymize none exit = %d, out = %q", code, stdout.String())
+	}
+
+	env, _, _ = testEnv("", dbPath)
+	if code := Run(env, []string{"export", "--anonymize", "hipaa", "sess-1"}); code != 1 {
+		t.Errorf("unknown preset exit = %d, want 1", code)
+	}
+}
+
+func TestExportWhisper(t *testing.T) {
+	t.Setenv("STENO_CONFIG", filepath.Join(t.TempDir(), "none.json"))
+	dbPath := testDBFile(t)
+
+	out := filepath.Join(t.TempDir(), "standup.json")
+	env, _, stderr := testEnv("", dbPath)
+	if code := Run(env, []string{"export", "--format", "whisper", "-o", out, "sess-1"}); code != 0 {
+		t.Fatalf("export exit = %d, stderr = %s", code, stderr.String())
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Task     string `json:"task"`
+		Segments []struct {
+			Start float64 `json:"start"`
+			End   float64 `json:"end"`
+			Text  string  `json:"text"`
+		} `json:"segments"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, data)
+	}
+	if doc.Task != "transcribe" || len(doc.Segments) == 0 || doc.Segments[0].Start != 0 || doc.Segments[0].End <= 0 {
+		t.Errorf("whisper export = %s", data)
+	}
+	manifest, err := os.ReadFile(out + ".manifest.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(manifest), `"format": "whisper"`) {
+		t.Errorf("manifest = %s", manifest)
+	}
+
+	env, _, _ = testEnv("", dbPath)
+	if code := Run(env, []string{"export", "--format", "docx", "sess-1"}); code != 1 {
+		t.Errorf("unknown format exit = %d, want 1", code)
+	}
+}
+
+func TestExportResumesInterrupted(t *testing.T) {
+	t.Setenv("STENO_CONFIG", filepath.Join(t.TempDir(), "none.json"))
+	dbPath := testDBFile(t)
+	d, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.Exec(`INSERT INTO segments (id, sessionId, text, startedAt, endedAt, sequenceNumber, createdAt) VALUES ('seg-2', 'sess-1', 'see you tomorrow', 1710000030, 1710000035, 2, 1710000030)`); err != nil {
+		t.Fatal(err)
+	}
+	d.Close()
+	out := filepath.Join(t.TempDir(), "standup.md")
+
+	// An export stopped after its first line, as a killed run leaves it.
+	interrupt := func() {
+		store, err := db.Open(dbPath, db.Options{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer store.Close()
+		tr, err := export.LoadTranscript(store, "sess-1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		e, err := export.ResumeExport(out, export.TranscriptFormatMarkdown, tr, export.SubtitleOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		e.Write(1)
+		e.Close()
+	}
+
+	env, want, _ := testEnv("", dbPath)
+	if code := Run(env, []string{"export", "sess-1"}); code != 0 {
+		t.Fatalf("export exit = %d", code)
+	}
+
+	interrupt()
+	env, _, stderr := testEnv("", dbPath)
+	if code := Run(env, []string{"export", "-o", out, "sess-1"}); code != 0 {
+		t.Fatalf("export exit = %d, stderr = %s", code, stderr.String())
+	}
+	if !strings.Contains(stderr.String(), "after segment 1 of") {
+		t.Errorf("stderr = %q, want a resume note", stderr.String())
+	}
+	if got, _ := os.ReadFile(out); string(got) != want.String() {
+		t.Errorf("resumed export =\n%s\nwant\n%s", got, want.String())
+	}
+	if _, err := os.Stat(export.ProgressPath(out)); !os.IsNotExist(err) {
+		t.Errorf("progress manifest left behind: %v", err)
+	}
+
+	interrupt()
+	env, _, stderr = testEnv("", dbPath)
+	if code := Run(env, []string{"export", "-o", out, "--restart", "sess-1"}); code != 0 || strings.Contains(stderr.String(), "Resuming") {
+		t.Errorf("--restart exit = %d, stderr = %q", code, stderr.String())
+	}
+	if got, _ := os.ReadFile(out); string(got) != want.String() {
+		t.Errorf("restarted export =\n%s\nwant\n%s", got, want.String())
+	}
+}
+
+func TestExportClean(t *testing.T) {
+	t.Setenv("STENO_CONFIG", filepath.Join(t.TempDir(), "none.json"))
+	dbPath := testDBFile(t)
+	out := filepath.Join(t.TempDir(), "standup.md")
+	sock := mockDaemon(t, map[string]daemon.Response{
+		"clean_verbatim": {OK: true, Texts: []string{"Hello."}},
+	})
+
+	env, _, stderr := testEnv(sock, dbPath)
+	if code := Run(env, []string{"export", "--clean", "-o", out, "sess-1"}); code != 0 {
+		t.Fatalf("export exit = %d, stderr = %s", code, stderr.String())
+	}
+	verbatim, _ := os.ReadFile(out)
+	clean, _ := os.ReadFile(export.CleanPath(out))
+	if !strings.Contains(string(verbatim), "mic:** hello") || !strings.Contains(string(clean), "mic:** Hello.") {
+		t.Errorf("verbatim =\n%s\nclean =\n%s", verbatim, clean)
+	}
+	if m, _ := os.ReadFile(export.ManifestPath(export.CleanPath(out))); !strings.Contains(string(m), `"clean": true`) {
+		t.Errorf("clean manifest = %s", m)
+	}
+
+	// The rewrite is stored, so a later export needs no daemon.
+	env, stdout, stderr := testEnv("/tmp/steno-cli-none.sock", dbPath)
+	if code := Run(env, []string{"export", "--clean", "--json", "sess-1"}); code != 0 {
+		t.Fatalf("cached export exit = %d, stderr = %s", code, stderr.String())
+	}
+	var got exportOutput
+	if err := json.Unmarshal(stdout.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if !got.Manifest.Clean || len(got.Segments) != 1 || got.Segments[0].Text != "hello" || got.Segments[0].CleanText != "Hello." {
+		t.Errorf("json = %+v", got)
+	}
+
+	// Without a language model the export fails rather than passing the
+	// verbatim text off as clean.
+	d, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.Exec(`DELETE FROM segment_cleanups`); err != nil {
+		t.Fatal(err)
+	}
+	d.Close()
+	sock = mockDaemon(t, map[string]daemon.Response{
+		"clean_verbatim": {OK: false, Error: "clean_verbatim requires an available language model"},
+	})
+	env, _, stderr = testEnv(sock, dbPath)
+	if code := Run(env, []string{"export", "--clean", "sess-1"}); code == 0 || !strings.Contains(stderr.String(), "requires an available language model") {
+		t.Errorf("exit = %d, stderr = %q", code, stderr.String())
+	}
+}
+
+func TestExportSubtitles(t *testing.T) {
+	t.Setenv("STENO_CONFIG", filepath.Join(t.TempDir(), "none.json"))
+	dbPath := testDBFile(t)
+	d, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.Exec(`INSERT INTO segments (id, sessionId, text, startedAt, endedAt, sequenceNumber, createdAt, source) VALUES ('seg-2', 'sess-1', 'so the plan is to ship on Friday and then regroup on Monday', 1710000030, 1710000042, 2, 1710000030, 'systemAudio')`); err != nil {
+		t.Fatal(err)
+	}
+	d.Close()
+
+	env, stdout, stderr := testEnv("", dbPath)
+	if code := Run(env, []string{"export", "--format", "vtt", "--max-duration", "6s", "sess-1"}); code != 0 {
+		t.Fatalf("export exit = %d, stderr = %s", code, stderr.String())
+	}
+	got := stdout.String()
+	for _, want := range []string{"WEBVTT\n", "00:00:00.000 --> 00:00:09.000\n<v mic>hello", "00:00:20.000 --> 00:00:25.", "<v system audio>so the plan"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("vtt missing %q:\n%s", want, got)
+		}
+	}
+
+	env, _, _ = testEnv("", dbPath)
+	if code := Run(env, []string{"export", "--format", "srt", "--max-chars", "0", "sess-1"}); code != 1 {
+		t.Errorf("--max-chars 0 exit = %d, want 1", code)
+	}
+}
+
+func TestCSV(t *testing.T) {
+	dbPath := testDBFile(t)
+	dir := filepath.Join(t.TempDir(), "out")
+
+	env, stdout, stderr := testEnv("", dbPath)
+	if code := Run(env, []string{"csv", "--json", "--bom", "-o", dir, "sess-1", "sess-2"}); code != 0 {
+		t.Fatalf("csv exit = %d, stderr = %s", code, stderr.String())
+	}
+	var out csvOutput
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, stdout.String())
+	}
+	if out.Sessions != 2 || out.Segments != 1 || len(out.Files) != 2 {
+		t.Errorf("output = %+v", out)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "segments.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimPrefix(string(data), "\ufeff"), "\r\n")
+	if !strings.HasPrefix(string(data), "\ufeff") || lines[0] != "session_id,seq,start,end,source,speaker,confidence,text" ||
+		lines[1] != "sess-1,1,2024-03-09 16:00:10.000,2024-03-09 16:00:19.000,microphone,mic,,hello" {
+		t.Errorf("segments.csv = %q", data)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "topics.csv")); err != nil {
+		t.Errorf("topics.csv: %v", err)
+	}
+
+	env, _, _ = testEnv("", dbPath)
+	if code := Run(env, []string{"csv", "-o", dir, "missing"}); code != 1 {
+		t.Errorf("missing session exit = %d, want 1", code)
+	}
+}
+
+func TestAnki(t *testing.T) {
+	dbPath := testDBFile(t)
+	env, _, stderr := testEnv("", dbPath)
+	if code := Run(env, []string{"annotate", "--note", "a greeting", "sess-1", "1"}); code != 0 {
+		t.Fatalf("annotate exit = %d, stderr = %s", code, stderr.String())
+	}
+
+	env, stdout, _ := testEnv("", dbPath)
+	if code := Run(env, []string{"anki", "--deck", "Mining", "sess-1", "sess-2"}); code != 0 {
+		t.Fatalf("anki exit = %d", code)
+	}
+	if tsv := stdout.String(); !strings.Contains(tsv, "#deck:Mining\n") || !strings.Contains(tsv, "\thello\ta greeting<br>") {
+		t.Errorf("tsv = %q", tsv)
+	}
+
+	file := filepath.Join(t.TempDir(), "cards.txt")
+	env, stdout, _ = testEnv("", dbPath)
+	if code := Run(env, []string{"anki", "--json", "-o", file, "sess-1"}); code != 0 {
+		t.Fatalf("anki --json exit = %d", code)
+	}
+	var out ankiOutput
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, stdout.String())
+	}
+	if out.Cards != 1 || out.Sessions != 1 || out.File != file {
+		t.Errorf("output = %+v", out)
+	}
+	if data, err := os.ReadFile(file); err != nil || !strings.HasPrefix(string(data), "#separator:tab\n") {
+		t.Errorf("cards file = %q, %v", data, err)
+	}
+
+	env, _, _ = testEnv("", dbPath)
+	if code := Run(env, []string{"anki", "--json", "sess-1"}); code != 2 {
+		t.Errorf("--json without -o exit = %d, want 2", code)
+	}
+}
+
+func TestCorrectThenCalibration(t *testing.T) {
+	dbPath := testDBFile(t)
+	env, stdout, stderr := testEnv("", dbPath)
+	if code := Run(env, []string{"calibration"}); code != 0 || !strings.Contains(stderr.String(), "No corrected sessions") {
+		t.Errorf("empty calibration exit = %d, stderr = %q", code, stderr.String())
+	}
+
+	env, stdout, stderr = testEnv("", dbPath)
+	if code := Run(env, []string{"correct", "sess-1", "1", "hello", "world"}); code != 0 {
+		t.Fatalf("correct exit = %d, stderr = %s", code, stderr.String())
+	}
+	env, _, _ = testEnv("", dbPath)
+	if code := Run(env, []string{"correct", "sess-1", "99", "x"}); code != 1 {
+		t.Errorf("unknown segment exit = %d, want 1", code)
+	}
+
+	env, stdout, _ = testEnv("", dbPath)
+	if code := Run(env, []string{"calibration", "--json"}); code != 0 {
+		t.Fatalf("calibration exit = %d", code)
+	}
+	var out calibrationOutput
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, stdout.String())
+	}
+	// "hello" heard, "hello world" said: one missed word of two.
+	if len(out.Rows) != 1 || out.Rows[0].Bucket != "none" || out.Rows[0].Words != 2 || out.Rows[0].WER != 0.5 {
+		t.Errorf("rows = %+v", out.Rows)
+	}
+
+	env, stdout, _ = testEnv("", dbPath)
+	if code := Run(env, []string{"calibration"}); code != 0 || !strings.Contains(stdout.String(), "50.0%") {
+		t.Errorf("table = %q", stdout.String())
+	}
+}
+
+func TestMacros(t *testing.T) {
+	dbPath := testDBFile(t)
+	cfgPath := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(cfgPath, []byte(`{"dictation": {"profile": "email"}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("STENO_CONFIG", cfgPath)
+
+	env, _, stderr := testEnv("", dbPath)
+	if code := Run(env, []string{"macros", "--add", `\bsign off\b`, `Best,\nJo`}); code != 0 {
+		t.Fatalf("add exit = %d, stderr = %s", code, stderr.String())
+	}
+	env, _, _ = testEnv("", dbPath)
+	if code := Run(env, []string{"macros", "--profile", "chat", "--add", `\bsmiley\b`, ":)"}); code != 0 {
+		t.Fatalf("add to chat exit = %d", code)
+	}
+	env, _, _ = testEnv("", dbPath)
+	if code := Run(env, []string{"macros", "--add", "(unclosed", "x"}); code != 1 {
+		t.Errorf("invalid pattern exit = %d, want 1", code)
+	}
+
+	env, stdout, _ := testEnv("", dbPath)
+	if code := Run(env, []string{"macros", "--test", "thanks new paragraph sign off smiley"}); code != 0 {
+		t.Fatalf("test exit = %d", code)
+	}
+	if got := stdout.String(); got != "thanks\n\nBest,\nJo smiley\n" {
+		t.Errorf("test output = %q, want the email profile and built-ins applied", got)
+	}
+
+	env, stdout, _ = testEnv("", dbPath)
+	if code := Run(env, []string{"macros", "--json"}); code != 0 {
+		t.Fatalf("list exit = %d", code)
+	}
+	var out macrosOutput
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, stdout.String())
+	}
+	if len(out.Macros) != 2 || out.Macros[0].Profile != "chat" || out.Macros[1].Replacement != "Best,\nJo" || len(out.Builtin) == 0 {
+		t.Fatalf("macros = %+v", out)
+	}
+
+	env, _, _ = testEnv("", dbPath)
+	if code := Run(env, []string{"macros", "--delete", strconv.FormatInt(out.Macros[0].ID, 10)}); code != 0 {
+		t.Errorf("delete exit = %d", code)
+	}
+	env, stdout, _ = testEnv("", dbPath)
+	if code := Run(env, []string{"macros"}); code != 0 || strings.Contains(stdout.String(), "chat") || !strings.Contains(stdout.String(), "email") {
+		t.Errorf("list after delete = %q", stdout.String())
+	}
+}
+
+func TestNotes(t *testing.T) {
+	dbPath := testDBFile(t)
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "sessions"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	stale := filepath.Join(dir, "sessions", "2020-01-01-gone.md")
+	if err := os.WriteFile(stale, []byte("# Merged away\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	run := func(args ...string) notesOutput {
+		t.Helper()
+		env, stdout, stderr := testEnv("", dbPath)
+		if code := Run(env, append([]string{"notes", "--json"}, args...)); code != 0 {
+			t.Fatalf("notes exit = %d, stderr = %s", code, stderr.String())
+		}
+		var out notesOutput
+		if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+			t.Fatalf("unmarshal: %v\n%s", err, stdout.String())
+		}
+		return out
+	}
+
+	// Only the completed session is exported; the active one is still
+	// changing.
+	out := run(dir)
+	if out.Sessions != 1 || len(out.Written) != 2 || out.Unchanged != 0 || len(out.Removed) != 0 {
+		t.Errorf("first run = %+v", out)
+	}
+	index, err := os.ReadFile(filepath.Join(dir, "README.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(index), "- 2024-03-09 16:00 [Team Standup](sessions/2024-03-09-sess-1.md), 1h00m, 1 segment\n") {
+		t.Errorf("index = %s", index)
+	}
+	note, err := os.ReadFile(filepath.Join(dir, "sessions", "2024-03-09-sess-1.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(note), "**[16:00:10] mic:** hello") {
+		t.Errorf("note = %s", note)
+	}
+
+	// A second run rewrites nothing; --prune drops the stale note.
+	out = run("--prune", dir)
+	if len(out.Written) != 0 || out.Unchanged != 2 || len(out.Removed) != 1 || out.Removed[0] != filepath.Join("sessions", "2020-01-01-gone.md") {
+		t.Errorf("second run = %+v", out)
+	}
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Errorf("stale note still there: %v", err)
+	}
+}
+
+func TestConfigPresetsMatchExport(t *testing.T) {
+	if !slices.Equal(config.AnonymizePresets, export.PresetNames()) {
+		t.Errorf("config.AnonymizePresets = %v, export presets = %v", config.AnonymizePresets, export.PresetNames())
+	}
+}
+
+func TestMaintain(t *testing.T) {
+	t.Setenv("STENO_CONFIG", filepath.Join(t.TempDir(), "none.json"))
+	dbPath := testDBFile(t)
+
+	justNow := float64(time.Now().Unix())
+	recording := mockDaemon(t, map[string]daemon.Response{
+		"status": {OK: true, Recording: daemon.BoolPtr(true), Status: "recording", LastSegmentAt: &justNow},
+	})
+	env, stdout, _ := testEnv(recording, dbPath)
+	if code := Run(env, []string{"maintain"}); code != 0 || !strings.Contains(stdout.String(), "Deferred vacuum: daemon is recording") {
+		t.Errorf("maintain while recording exit = %d, out = %s", code, stdout.String())
+	}
+	// The rest runs while recording; VACUUM waits, and the run stays due.
+	env, stdout, _ = testEnv(recording, dbPath)
+	if code := Run(env, []string{"maintain", "--if-due", "--json"}); code != 0 {
+		t.Fatalf("--if-due while recording exit = %d", code)
+	}
+	var out maintainOutput
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, stdout.String())
+	}
+	if out.Skipped != "" || len(out.Steps) != 2 || out.Steps[0].Step != "analyze" || !slices.Equal(out.Deferred, []string{"vacuum"}) || out.LastRun != nil {
+		t.Errorf("run while recording = %+v", out)
+	}
+
+	// A session silent for vacuumQuiet is a gap between meetings.
+	longAgo := float64(time.Now().Add(-vacuumQuiet - time.Minute).Unix())
+	quiet := mockDaemon(t, map[string]daemon.Response{
+		"status": {OK: true, Recording: daemon.BoolPtr(true), Status: "recording", LastSegmentAt: &longAgo},
+	})
+	env, stdout, _ = testEnv(quiet, dbPath)
+	if code := Run(env, []string{"maintain", "--json"}); code != 0 {
+		t.Fatalf("maintain while quiet exit = %d", code)
+	}
+	out = maintainOutput{}
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, stdout.String())
+	}
+	if len(out.Steps) != 3 || len(out.Deferred) != 0 || out.NextDue == nil {
+		t.Errorf("run while quiet = %+v", out)
+	}
+	dbPath = testDBFile(t)
+
+	// No daemon running counts as idle.
+	noDaemon := filepath.Join(t.TempDir(), "none.sock")
+	env, stdout, stderr := testEnv(noDaemon, dbPath)
+	if code := Run(env, []string{"maintain", "--if-due", "--json"}); code != 0 {
+		t.Fatalf("maintain exit = %d, out = %s, stderr = %s", code, stdout.String(), stderr.String())
+	}
+	out = maintainOutput{}
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, stdout.String())
+	}
+	if out.Skipped != "" || len(out.Steps) != 3 || out.Steps[1].Step != "vacuum" || out.SizeBefore == 0 || out.NextDue == nil {
+		t.Errorf("run = %+v", out)
+	}
+
+	// Just ran, so not due again.
+	env, stdout, _ = testEnv(noDaemon, dbPath)
+	if code := Run(env, []string{"maintain", "--if-due"}); code != 0 || !strings.Contains(stdout.String(), "not due until") {
+		t.Errorf("second --if-due exit = %d, out = %q", code, stdout.String())
+	}
+	// A manual run ignores the schedule and reports progress.
+	env, stdout, stderr = testEnv(noDaemon, dbPath)
+	if code := Run(env, []string{"maintain"}); code != 0 || !strings.Contains(stderr.String(), "vacuum...") || !strings.Contains(stdout.String(), "reclaimed") {
+		t.Errorf("manual exit = %d, out = %q, stderr = %q", code, stdout.String(), stderr.String())
+	}
+
+	env, stdout, _ = testEnv(noDaemon, dbPath)
+	if code := Run(env, []string{"maintain", "--launchd"}); code != 0 || !strings.Contains(stdout.String(), "<string>--if-due</string>") {
+		t.Errorf("--launchd exit = %d, out = %s", code, stdout.String())
+	}
+}
+
+func TestByteSize(t *testing.T) {
+	for n, want := range map[int64]string{0: "0 B", 1023: "1023 B", 1536: "1.5 KiB", 5 << 20: "5.0 MiB"} {
+		if got := byteSize(n); got != want {
+			t.Errorf("byteSize(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
+
+func TestChaptersExport(t *testing.T) {
+	t.Setenv("STENO_CONFIG", filepath.Join(t.TempDir(), "none.json"))
+	dbPath := testDBFile(t)
+
+	// sess-1 has no topics, so the default source has nothing to use.
+	env, _, _ := testEnv("", dbPath)
+	if code := Run(env, []string{"chapters", "sess-1"}); code != 1 {
+		t.Errorf("topic chapters without topics exit = %d, want 1", code)
+	}
+
+	env, stdout, stderr := testEnv("", dbPath)
+	if code := Run(env, []string{"chapters", "--by", "interval", "--format", "vtt", "sess-1"}); code != 0 {
+		t.Fatalf("chapters exit = %d, stderr = %s", code, stderr.String())
+	}
+	if got := stdout.String(); !strings.HasPrefix(got, "WEBVTT\n\n1\n00:00:00.000 --> 00:00:09.000\nhello\n") {
+		t.Errorf("vtt = %q", got)
+	}
+
+	env, stdout, _ = testEnv("", dbPath)
+	if code := Run(env, []string{"chapters", "--json", "--by", "interval", "sess-1"}); code != 0 {
+		t.Fatalf("chapters --json exit = %d", code)
+	}
+	var out chaptersOutput
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, stdout.String())
+	}
+	if out.By != "interval" || len(out.Chapters) != 1 || out.Chapters[0].EndSeconds != 9 {
+		t.Errorf("json = %+v", out)
+	}
+
+	env, _, _ = testEnv("", dbPath)
+	if code := Run(env, []string{"chapters", "--format", "mp4", "sess-1"}); code != 1 {
+		t.Errorf("unknown format exit = %d, want 1", code)
+	}
+}
+
+func TestSpeakers(t *testing.T) {
+	dbPath := testDBFile(t)
+	env, _, stderr := testEnv("", dbPath)
+	if code := Run(env, []string{"speakers", "sess-1", "Speaker 1=Ana", "Speaker 2=Bo"}); code != 0 {
+		t.Fatalf("speakers exit = %d, stderr = %s", code, stderr.String())
+	}
+	env, _, _ = testEnv("", dbPath)
+	if code := Run(env, []string{"speakers", "sess-1", "Speaker 2="}); code != 0 {
+		t.Fatalf("unset exit = %d", code)
+	}
+
+	env, stdout, _ := testEnv("", dbPath)
+	if code := Run(env, []string{"speakers", "--json", "sess-1"}); code != 0 {
+		t.Fatalf("list exit = %d", code)
+	}
+	var out speakersOutput
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, stdout.String())
+	}
+	if len(out.Speakers) != 1 || out.Speakers["Speaker 1"] != "Ana" {
+		t.Errorf("speakers = %v, want Speaker 1=Ana only", out.Speakers)
+	}
+
+	env, stdout, _ = testEnv("", dbPath)
+	if code := Run(env, []string{"speakers", "sess-1"}); code != 0 || stdout.String() != "Speaker 1\tAna\n" {
+		t.Errorf("plain list exit = %d, stdout = %q", code, stdout.String())
+	}
+
+	for _, args := range [][]string{
+		{"speakers", "sess-1", "Ana"},
+		{"speakers", "sess-1", "=Ana"},
+		{"speakers", "no-such-session", "Speaker 1=Ana"},
+	} {
+		env, _, _ := testEnv("", dbPath)
+		if code := Run(env, args); code != 1 {
+			t.Errorf("%v exit = %d, want 1", args, code)
+		}
+	}
+}
+
+func TestRelabelPreviewApplyUndo(t *testing.T) {
+	dbPath := testDBFile(t)
+	d, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	for _, s := range []string{
+		`ALTER TABLE segments ADD COLUMN speaker TEXT`,
+		`UPDATE segments SET speaker = 'Speaker 2' WHERE id = 'seg-1'`,
+		`INSERT INTO segments (id, sessionId, text, startedAt, endedAt, sequenceNumber, createdAt, speaker) VALUES ('seg-1b', 'sess-1', 'hi', 1710000020, 1710000029, 2, 1710000020, 'Speaker 1')`,
+	} {
+		if _, err := d.Exec(s); err != nil {
+			t.Fatalf("exec %q: %v", s, err)
+		}
+	}
+	d.Close()
+	speakerOf := func(id string) string {
+		t.Helper()
+		d, err := sql.Open("sqlite", dbPath)
+		if err != nil {
+			t.Fatalf("open: %v", err)
+		}
+		defer d.Close()
+		var speaker string
+		if err := d.QueryRow(`SELECT speaker FROM segments WHERE id = ?`, id).Scan(&speaker); err != nil {
+			t.Fatalf("speaker of %s: %v", id, err)
+		}
+		return speaker
+	}
+
+	env, stdout, _ := testEnv("", dbPath)
+	if code := Run(env, []string{"relabel", "--dry-run", "--json", "sess-1", "Speaker 2", "Speaker 1"}); code != 0 {
+		t.Fatalf("dry-run exit = %d: %s", code, stdout.String())
+	}
+	var out relabelOutput
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, stdout.String())
+	}
+	if !out.DryRun || out.Moved != 1 || out.Joined != 1 || speakerOf("seg-1") != "Speaker 2" {
+		t.Errorf("dry run = %+v, speaker %q", out, speakerOf("seg-1"))
+	}
+
+	env, stdout, stderr := testEnv("", dbPath)
+	if code := Run(env, []string{"relabel", "sess-1", "Speaker 2", "Speaker 1"}); code != 0 {
+		t.Fatalf("relabel exit = %d, stderr = %s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "Moved 1 segments from Speaker 2 to Speaker 1") || speakerOf("seg-1") != "Speaker 1" {
+		t.Errorf("stdout = %q, speaker %q", stdout.String(), speakerOf("seg-1"))
+	}
+
+	env, stdout, _ = testEnv("", dbPath)
+	if code := Run(env, []string{"relabel", "--undo", "sess-1"}); code != 0 || speakerOf("seg-1") != "Speaker 2" || speakerOf("seg-1b") != "Speaker 1" {
+		t.Errorf("undo exit = %d, stdout = %q", code, stdout.String())
+	}
+	env, _, _ = testEnv("", dbPath)
+	if code := Run(env, []string{"relabel", "--undo", "sess-1"}); code != 1 {
+		t.Errorf("second undo exit = %d, want 1 with nothing left", code)
+	}
+
+	for _, c := range []struct {
+		args []string
+		code int
+	}{
+		{[]string{"relabel", "sess-1", "Speaker 2"}, 2},
+		{[]string{"relabel", "--undo", "--dry-run", "sess-1"}, 2},
+		{[]string{"relabel", "sess-2", "Speaker 1", "Speaker 2"}, 1}, // active
+	} {
+		env, _, _ := testEnv("", dbPath)
+		if code := Run(env, c.args); code != c.code {
+			t.Errorf("%v exit = %d, want %d", c.args, code, c.code)
+		}
+	}
+}
+
+func TestConsent(t *testing.T) {
+	dbPath := testDBFile(t)
+	env, stdout, stderr := testEnv("", dbPath)
+	if code := Run(env, []string{"consent", "sess-1"}); code != 0 || stdout.String() != "sess-1: consent not acknowledged\n" {
+		t.Fatalf("consent exit = %d, stdout = %q, stderr = %s", code, stdout.String(), stderr.String())
+	}
+
+	env, _, stderr = testEnv("", dbPath)
+	if code := Run(env, []string{"consent", "--ack", "sess-1"}); code != 0 {
+		t.Fatalf("ack exit = %d, stderr = %s", code, stderr.String())
+	}
+	env, stdout, _ = testEnv("", dbPath)
+	if code := Run(env, []string{"consent", "--json", "sess-1"}); code != 0 {
+		t.Fatalf("json exit = %d", code)
+	}
+	var out consentOutput
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, stdout.String())
+	}
+	if !out.Acknowledged || out.AcknowledgedAt == "" {
+		t.Errorf("consent = %+v, want acknowledged", out)
+	}
+
+	for _, args := range [][]string{
+		{"consent"},
+		{"consent", "no-such-session"},
+	} {
+		env, _, _ := testEnv("", dbPath)
+		if code := Run(env, args); code == 0 {
+			t.Errorf("%v exit = 0, want failure", args)
+		}
+	}
+}
+
+func TestSearches(t *testing.T) {
+	dbPath := testDBFile(t)
+	env, _, stderr := testEnv("", dbPath)
+	if code := Run(env, []string{"searches", "--save", "greeting", "--alert", "--scope", "session", "HEL"}); code != 0 {
+		t.Fatalf("save exit = %d, stderr = %s", code, stderr.String())
+	}
+	env, _, _ = testEnv("", dbPath)
+	if code := Run(env, []string{"searches", "--save", "rust", "--source", "systemAudio", "rust"}); code != 0 {
+		t.Fatalf("save exit = %d", code)
+	}
+
+	env, stdout, _ := testEnv("", dbPath)
+	if code := Run(env, []string{"searches", "--json"}); code != 0 {
+		t.Fatalf("list exit = %d", code)
+	}
+	var list savedSearchesOutput
+	if err := json.Unmarshal(stdout.Bytes(), &list); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, stdout.String())
+	}
+	if len(list.Searches) != 2 || list.Searches[0].Name != "greeting" || !list.Searches[0].Alert || list.Searches[0].Scope != "session" {
+		t.Errorf("searches = %+v", list.Searches)
+	}
+
+	env, stdout, _ = testEnv("", dbPath)
+	if code := Run(env, []string{"searches", "--run", "greeting", "--session", "sess-1", "--json"}); code != 0 {
+		t.Fatalf("run exit = %d", code)
+	}
+	var run searchRunOutput
+	if err := json.Unmarshal(stdout.Bytes(), &run); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, stdout.String())
+	}
+	if run.Total != 1 || len(run.Matches) != 1 || run.Matches[0].Text != "hello" || run.Matches[0].SegmentSeq != 1 {
+		t.Errorf("run = %+v", run)
+	}
+
+	env, _, _ = testEnv("", dbPath)
+	if code := Run(env, []string{"searches", "--delete", "rust"}); code != 0 {
+		t.Fatalf("delete exit = %d", code)
+	}
+	env, stdout, _ = testEnv("", dbPath)
+	if code := Run(env, []string{"searches"}); code != 0 || strings.Contains(stdout.String(), "rust") || !strings.Contains(stdout.String(), "greeting") {
+		t.Errorf("list after delete exit = %d, stdout = %q", code, stdout.String())
+	}
+
+	for _, tc := range []struct {
+		args []string
+		code int
+	}{
+		{[]string{"searches", "--save", "x"}, 2},
+		{[]string{"searches", "--run", "greeting", "--delete", "greeting"}, 2},
+		{[]string{"searches", "--save", "x", "--scope", "yesterday", "hello"}, 1},
+		{[]string{"searches", "--run", "missing"}, 1},
+		{[]string{"searches", "--delete", "missing"}, 1},
+	} {
+		env, _, _ := testEnv("", dbPath)
+		if code := Run(env, tc.args); code != tc.code {
+			t.Errorf("%v exit = %d, want %d", tc.args, code, tc.code)
+		}
+	}
+}
+
+func TestCodes(t *testing.T) {
+	cfgPath := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(cfgPath, []byte(`{"coding": {"codes": [{"name": "greeting", "terms": ["hello", "hi"]}, {"name": "trust", "terms": ["trust*"]}]}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("STENO_CONFIG", cfgPath)
+	dbPath := testDBFile(t)
+	d, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.Exec(`INSERT INTO segments (id, sessionId, text, startedAt, endedAt, sequenceNumber, createdAt) VALUES ('seg-2', 'sess-1', 'Hi again, I trusted it.', 1710000130, 1710000135, 2, 1710000130)`); err != nil {
+		t.Fatal(err)
+	}
+	d.Close()
+
+	env, stdout, stderr := testEnv("", dbPath)
+	if code := Run(env, []string{"codes", "--json", "sess-1"}); code != 0 {
+		t.Fatalf("codes exit = %d, stderr = %s", code, stderr.String())
+	}
+	var out codesOutput
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, stdout.String())
+	}
+	if out.IntervalSeconds != 60 || len(out.Codes) != 2 || out.Codes[0].Total != 2 || !slices.Equal(out.Codes[0].Counts, []int{1, 0, 1}) || out.Codes[1].Total != 1 {
+		t.Errorf("codes = %+v", out)
+	}
+
+	env, stdout, _ = testEnv("", dbPath)
+	if code := Run(env, []string{"codes", "--csv", "--interval", "5m", "sess-1"}); code != 0 {
+		t.Fatalf("codes --csv exit = %d", code)
+	}
+	if rows := strings.Split(strings.TrimSpace(stdout.String()), "\r\n"); len(rows) != 3 || rows[1] != "sess-1,2024-03-09 16:00:10.000,0,greeting,2,2" {
+		t.Errorf("csv = %q", stdout.String())
+	}
+
+	t.Setenv("STENO_CONFIG", filepath.Join(t.TempDir(), "none.json"))
+	env, _, stderr = testEnv("", dbPath)
+	if code := Run(env, []string{"codes", "sess-1"}); code != 1 || !strings.Contains(stderr.String(), "no codes configured") {
+		t.Errorf("exit = %d, stderr = %q, want a pointer to coding.codes", code, stderr.String())
+	}
+}
+
+func TestEntities(t *testing.T) {
+	t.Setenv("STENO_CONFIG", filepath.Join(t.TempDir(), "none.json"))
+	dbPath := testDBFile(t)
+	d, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.Exec(`INSERT INTO segments (id, sessionId, text, startedAt, endedAt, sequenceNumber, createdAt) VALUES ('seg-2', 'sess-1', 'It comes to $1,200, due by Friday at 5 pm.', 1710000030, 1710000035, 2, 1710000030)`); err != nil {
+		t.Fatal(err)
+	}
+	d.Close()
+
+	env, stdout, stderr := testEnv("", dbPath)
+	if code := Run(env, []string{"entities", "--json", "sess-1"}); code != 0 {
+		t.Fatalf("entities exit = %d, stderr = %s", code, stderr.String())
+	}
+	var out entitiesOutput
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, stdout.String())
+	}
+	var got []string
+	for _, e := range out.Entities {
+		got = append(got, e.Kind+":"+e.Text)
+	}
+	if strings.Join(got, ",") != "date:Friday,time:5 pm,amount:$1,200" || out.Entities[0].Permalink != "steno://session/sess-1#seg-2" {
+		t.Errorf("entities = %+v", out.Entities)
+	}
+
+	env, stdout, _ = testEnv("", dbPath)
+	if code := Run(env, []string{"entities", "--csv", "--kind", "amount", "sess-1"}); code != 0 {
+		t.Fatalf("entities --csv exit = %d", code)
+	}
+	if rows := strings.Split(strings.TrimSpace(stdout.String()), "\r\n"); len(rows) != 2 || !strings.Contains(rows[1], `amount,"$1,200",1,2`) {
+		t.Errorf("csv = %q", stdout.String())
+	}
+
+	env, _, _ = testEnv("", dbPath)
+	if code := Run(env, []string{"entities", "--kind", "person", "sess-1"}); code == 0 {
+		t.Error("an unknown --kind should fail")
+	}
+}
+
+func TestDecisions(t *testing.T) {
+	t.Setenv("STENO_CONFIG", filepath.Join(t.TempDir(), "none.json"))
+	dbPath := testDBFile(t)
+	d, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.Exec(`INSERT INTO segments (id, sessionId, text, startedAt, endedAt, sequenceNumber, createdAt) VALUES ('seg-2', 'sess-1', 'Fine, we agreed to ship Friday.', 1710000030, 1710000035, 2, 1710000030)`); err != nil {
+		t.Fatal(err)
+	}
+	d.Close()
+
+	// Exports list decisions even before anything recorded them.
+	env, stdout, stderr := testEnv("", dbPath)
+	if code := Run(env, []string{"export", "sess-1"}); code != 0 {
+		t.Fatalf("export exit = %d, stderr = %s", code, stderr.String())
+	}
+	if md := stdout.String(); !strings.Contains(md, "## Decisions\n\n- ") || !strings.Contains(md, "we agreed to ship Friday.\n\n## Transcript") {
+		t.Errorf("export:\n%s", md)
+	}
+
+	env, stdout, stderr = testEnv("", dbPath)
+	if code := Run(env, []string{"decisions", "--json", "sess-1"}); code != 0 {
+		t.Fatalf("decisions exit = %d, stderr = %s", code, stderr.String())
+	}
+	var out decisionsOutput
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, stdout.String())
+	}
+	if len(out.Decisions) != 1 || out.Decisions[0].SegmentSeq != 2 || out.Decisions[0].Cue != "we agreed" {
+		t.Errorf("decisions = %+v", out.Decisions)
+	}
+
+	env, stdout, _ = testEnv("", dbPath)
+	if code := Run(env, []string{"export", "--format", "markdown", "--json", "sess-1"}); code != 0 {
+		t.Fatalf("export --json exit = %d", code)
+	}
+	var exp exportOutput
+	if err := json.Unmarshal(stdout.Bytes(), &exp); err != nil {
+		t.Fatal(err)
+	}
+	if len(exp.Segments) != 2 || exp.Segments[0].Decision || !exp.Segments[1].Decision {
+		t.Errorf("export segments = %+v, want #2 flagged", exp.Segments)
+	}
+
+	env, _, _ = testEnv("", dbPath)
+	if code := Run(env, []string{"decisions", "no-such-session"}); code != 1 {
+		t.Errorf("missing session exit = %d, want 1", code)
+	}
+}
+
+// mockEventDaemon accepts one subscriber, streams events to it and
+// hangs up, like a daemon that stops.
+func mockEventDaemon(t *testing.T, events []daemon.Event) string {
+	t.Helper()
+
+	sockPath := fmt.Sprintf("/tmp/steno-cli-ev-%d.sock", time.Now().UnixNano())
+	ln, err := net.Listen("unix", sockPath)
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() {
+		ln.Close()
+		os.Remove(sockPath)
+	})
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		bufio.NewReader(conn).ReadBytes('\n')
+		resp, _ := json.Marshal(daemon.Response{OK: true})
+		conn.Write(append(resp, '\n'))
+		for _, ev := range events {
+			data, _ := json.Marshal(ev)
+			conn.Write(append(data, '\n'))
+		}
+	}()
+	return sockPath
+}
+
+func TestAggregate(t *testing.T) {
+	seq, started := 1, float64(time.Date(2026, 3, 2, 9, 30, 0, 0, time.Local).Unix())
+	roomA := mockEventDaemon(t, []daemon.Event{
+		{Event: "level"},
+		{Event: "segment", Text: "kickoff in room A", Source: "microphone", SessionID: "sess-a", SequenceNumber: &seq, StartedAt: &started},
+	})
+	roomB := mockEventDaemon(t, []daemon.Event{
+		{Event: "topics", Topics: []daemon.EventTopic{{Title: "Budget"}}},
+	})
+
+	env, stdout, stderr := testEnv("", "")
+	if code := Run(env, []string{"aggregate", "a=" + roomA, "b=" + roomB}); code != 0 {
+		t.Fatalf("exit = %d, stderr = %s", code, stderr.String())
+	}
+	out := stdout.String()
+	if !strings.Contains(out, "[a] 09:30:00 mic: kickoff in room A\n") || !strings.Contains(out, "[b] topic: Budget\n") || strings.Count(out, "\n") != 2 {
+		t.Errorf("stdout:\n%s", out)
+	}
+	if !strings.Contains(stderr.String(), "a: read event: connection closed") || !strings.Contains(stderr.String(), "b: read event: connection closed") {
+		t.Errorf("stderr = %q", stderr.String())
+	}
+
+	roomA = mockEventDaemon(t, []daemon.Event{{Event: "segment", Text: "hi", SessionID: "sess-a"}})
+	env, stdout, _ = testEnv("", "")
+	if code := Run(env, []string{"aggregate", "--json", "a=" + roomA}); code != 0 {
+		t.Fatalf("--json exit = %d", code)
+	}
+	dec := json.NewDecoder(stdout)
+	var first, last aggregateLine
+	if err := dec.Decode(&first); err != nil {
+		t.Fatal(err)
+	}
+	if err := dec.Decode(&last); err != nil {
+		t.Fatal(err)
+	}
+	if first.Daemon != "a" || first.Event == nil || first.Event.SessionID != "sess-a" || last.Error == "" || last.Event != nil {
+		t.Errorf("lines = %+v, %+v", first, last)
+	}
+
+	env, _, _ = testEnv("", "")
+	if code := Run(env, []string{"aggregate", "no-socket"}); code != 1 {
+		t.Errorf("bad endpoint exit = %d, want 1", code)
+	}
+	env, _, _ = testEnv("", "")
+	if code := Run(env, []string{"aggregate"}); code != 2 {
+		t.Errorf("no endpoints exit = %d, want 2", code)
+	}
+}
+
+func TestQuality(t *testing.T) {
+	dbPath := testDBFile(t)
+	env, stdout, stderr := testEnv("", dbPath)
+	if code := Run(env, []string{"quality"}); code != 0 || !strings.Contains(stderr.String(), "No quality reports") {
+		t.Errorf("empty history exit = %d, stderr = %q", code, stderr.String())
+	}
+
+	// Not seen ending by the TUI: computed, gaps unmeasured.
+	env, stdout, _ = testEnv("", dbPath)
+	if code := Run(env, []string{"quality", "--json", "sess-1"}); code != 0 {
+		t.Fatalf("quality exit = %d", code)
+	}
+	var one qualityOutput
+	if err := json.Unmarshal(stdout.Bytes(), &one); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, stdout.String())
+	}
+	if one.Segments != 1 || one.GapsMeasured || one.DurationSeconds != 9 {
+		t.Errorf("report = %+v", one)
+	}
+
+	store, err := db.OpenClient(dbPath, db.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SaveSessionQuality(db.SessionQuality{SessionID: "sess-1", Segments: 1, Gaps: 2, GapTime: 30 * time.Second, Device: "USB Mic"}); err != nil {
+		t.Fatal(err)
+	}
+	store.Close()
+
+	env, stdout, _ = testEnv("", dbPath)
+	if code := Run(env, []string{"quality", "sess-1"}); code != 0 || !strings.Contains(stdout.String(), "2 stretches, 30s") {
+		t.Errorf("report = %q", stdout.String())
+	}
+	env, stdout, _ = testEnv("", dbPath)
+	if code := Run(env, []string{"quality", "--json"}); code != 0 {
+		t.Fatalf("history exit = %d", code)
+	}
+	var history qualityHistoryOutput
+	if err := json.Unmarshal(stdout.Bytes(), &history); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, stdout.String())
+	}
+	if len(history.Reports) != 1 || history.Reports[0].Device != "USB Mic" || !history.Reports[0].GapsMeasured {
+		t.Errorf("history = %+v", history.Reports)
+	}
+
+	env, _, _ = testEnv("", dbPath)
+	if code := Run(env, []string{"quality", "nope"}); code != 1 {
+		t.Errorf("unknown session exit = %d, want 1", code)
+	}
+}
+
+func TestFollowUp(t *testing.T) {
+	dbPath := testDBFile(t)
+	d, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.Exec(`INSERT INTO topics (id, sessionId, title, summary, segmentRangeStart, segmentRangeEnd, createdAt) VALUES ('top-1', 'sess-1', 'Launch date', 'Move the launch a week.', 1, 1, 1710000100)`); err != nil {
+		t.Fatal(err)
+	}
+	d.Close()
+
+	env, stdout, _ := testEnv("", dbPath)
+	if code := Run(env, []string{"followup", "sess-1"}); code != 0 || stdout.String() != "1  Launch date\n" {
+		t.Errorf("topic list exit = %d, stdout = %q", code, stdout.String())
+	}
+
+	env, stdout, stderr := testEnv("", dbPath)
+	if code := Run(env, []string{"followup", "--kind", "todo", "--at", "2026-03-11 09:00", "--json", "sess-1", "1"}); code != 0 {
+		t.Fatalf("followup exit = %d, stderr = %s", code, stderr.String())
+	}
+	var out followUpOutput
+	if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, stdout.String())
+	}
+	if out.Title != "Launch date" || out.Permalink != "steno://session/sess-1#seg-1" || !strings.Contains(out.ICS, "BEGIN:VTODO") ||
+		!strings.Contains(out.ICS, "SUMMARY:Follow up: Launch date") {
+		t.Errorf("followup = %+v", out)
+	}
+
+	file := filepath.Join(t.TempDir(), "followup.ics")
+	env, _, stderr = testEnv("", dbPath)
+	if code := Run(env, []string{"followup", "-o", file, "sess-1", "1"}); code != 0 {
+		t.Fatalf("followup -o exit = %d, stderr = %s", code, stderr.String())
+	}
+	if data, err := os.ReadFile(file); err != nil || !strings.Contains(string(data), "BEGIN:VEVENT") {
+		t.Errorf("ics file = %q, %v", data, err)
+	}
+
+	for _, args := range [][]string{{"followup", "sess-1", "2"}, {"followup", "--at", "tomorrow", "sess-1", "1"}, {"followup", "nope", "1"}} {
+		env, _, _ = testEnv("", dbPath)
+		if code := Run(env, args); code != 1 {
+			t.Errorf("%v exit = %d, want 1", args, code)
+		}
+	}
+}
+
+func TestExportTerms(t *testing.T) {
+	dbPath := testDBFile(t)
+	cfgPath := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(cfgPath, []byte(`{"export": {"terms": [{"term": "Hello", "variants": ["hallo"]}]}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("STENO_CONFIG", cfgPath)
+
+	env, stdout, stderr := testEnv("", dbPath)
+	if code := Run(env, []string{"export", "sess-1"}); code != 0 {
+		t.Fatalf("export exit = %d: %s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "mic:** hello") {
+		t.Errorf("without --fix-terms the text should be kept:\n%s", stdout.String())
+	}
+	if got := stderr.String(); !strings.Contains(got, `Hello: "hello" (segment 1) → replace with "Hello"`) || !strings.Contains(got, "--fix-terms") {
+		t.Errorf("stderr should flag the spelling with its fix:\n%s", got)
+	}
+
+	env, stdout, stderr = testEnv("", dbPath)
+	if code := Run(env, []string{"export", "--fix-terms", "sess-1"}); code != 0 {
+		t.Fatalf("export --fix-terms exit = %d: %s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "mic:** Hello") || !strings.Contains(stderr.String(), "Fixed 1 spellings") {
+		t.Errorf("stdout:\n%s\nstderr:\n%s", stdout.String(), stderr.String())
+	}
+
+	env, stdout, _ = testEnv("", dbPath)
+	if code := Run(env, []string{"export", "--json", "--fix-terms", "sess-1"}); code != 0 {
+		t.Fatalf("export --json exit = %d", code)
+	}
+	var got exportOutput
+	if err := json.Unmarshal(stdout.Bytes(), &got); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, stdout.String())
+	}
+	if len(got.TermIssues) != 1 || got.Manifest.TermFixes != 1 || got.Segments[0].Text != "Hello" {
+		t.Errorf("json = %+v", got)
+	}
+}
+
+// mockDictateDaemon answers status, start and stop, and streams events
+// to a subscriber, hanging up after them when hangUp is set. It returns
+// the socket path and the commands received, in order.
+func mockDictateDaemon(t *testing.T, recording bool, events []daemon.Event, hangUp bool) (string, func() []string) {
+	t.Helper()
+
+	sockPath := fmt.Sprintf("/tmp/steno-cli-dict-%d.sock", time.Now().UnixNano())
+	ln, err := net.Listen("unix", sockPath)
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() {
+		ln.Close()
+		os.Remove(sockPath)
+	})
+
+	var mu sync.Mutex
+	var cmds []string
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				scanner := bufio.NewScanner(conn)
+				for scanner.Scan() {
+					var cmd daemon.Command
+					_ = json.Unmarshal(scanner.Bytes(), &cmd)
+					mu.Lock()
+					cmds = append(cmds, cmd.Cmd)
+					mu.Unlock()
+					resp := daemon.Response{OK: true, Recording: daemon.BoolPtr(recording), SessionID: "sess-d"}
+					data, _ := json.Marshal(resp)
+					conn.Write(append(data, '\n'))
+					if cmd.Cmd != "subscribe" {
+						continue
+					}
+					for _, ev := range events {
+						data, _ := json.Marshal(ev)
+						conn.Write(append(data, '\n'))
+					}
+					if hangUp {
+						return
+					}
+				}
+			}(conn)
+		}
+	}()
+	return sockPath, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return slices.Clone(cmds)
+	}
+}
+
+func TestDictate(t *testing.T) {
+	t.Setenv("STENO_CONFIG", filepath.Join(t.TempDir(), "none.json"))
+	started := float64(time.Date(2026, 3, 2, 9, 30, 0, 0, time.Local).Unix())
+	events := []daemon.Event{
+		{Event: "partial", Text: "remember to", Source: "microphone"},
+		{Event: "segment", Text: "Remember to call Ana. New paragraph. Then the budget", Source: "microphone", StartedAt: &started},
+		{Event: "segment", Text: "music in the background", Source: "systemAudio"},
+		{Event: "segment", Text: "open quote draft close quote", Source: "microphone", StartedAt: &started},
+	}
+	sock, cmds := mockDictateDaemon(t, false, events, false)
+	out := filepath.Join(t.TempDir(), "notes.md")
+	if err := os.WriteFile(out, []byte("# Notes\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	env, stdout, stderr := testEnv(sock, filepath.Join(t.TempDir(), "none.sqlite"))
+	done := make(chan int)
+	go func() { done <- Run(env, []string{"dictate", "--out", out, "--stamp"}) }()
+	want := "# Notes\n[09:30:00] Remember to call Ana\n\nThen the budget\n[09:30:00] \"draft\"\n"
+	for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(10 * time.Millisecond) {
+		if got, _ := os.ReadFile(out); string(got) == want {
+			break
+		}
+		if time.Now().After(deadline) {
+			got, _ := os.ReadFile(out)
+			t.Fatalf("file = %q, want %q", got, want)
+		}
+	}
+	syscall.Kill(os.Getpid(), syscall.SIGINT)
+	if code := <-done; code != 0 {
+		t.Fatalf("exit = %d, stderr = %s", code, stderr.String())
+	}
+	if got := cmds(); !slices.Equal(got, []string{"status", "subscribe", "start", "stop"}) {
+		t.Errorf("commands = %q, want a started session stopped", got)
+	}
+	if !strings.Contains(stdout.String(), "Wrote 2 segments to "+out) {
+		t.Errorf("stdout = %q", stdout.String())
+	}
+
+	// A session already recording is left running; a daemon that goes
+	// away ends dictation with what was written.
+	sock, cmds = mockDictateDaemon(t, true, events[:2], true)
+	env, stdout, _ = testEnv(sock, filepath.Join(t.TempDir(), "none.sqlite"))
+	if code := Run(env, []string{"dictate", "--json", "--raw", "--out", out}); code != 1 {
+		t.Fatalf("exit = %d, want 1 when the daemon hangs up", code)
+	}
+	if got := cmds(); !slices.Equal(got, []string{"status", "subscribe"}) {
+		t.Errorf("commands = %q, want the session left alone", got)
+	}
+	if got, _ := os.ReadFile(out); !strings.HasSuffix(string(got), "\"draft\"\nRemember to call Ana. New paragraph. Then the budget\n") {
+		t.Errorf("file = %q, want the raw segment appended", got)
+	}
+
+	env, _, _ = testEnv(sock, "")
+	if code := Run(env, []string{"dictate"}); code != 2 {
+		t.Errorf("no --out exit = %d, want 2", code)
+	}
+}
@@ -0,0 +1,115 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jwulff/steno/internal/config"
+	"github.com/jwulff/steno/internal/export"
+)
+
+// codeOutput is one code of the `steno codes --json` shape: its total
+// and its count in each interval.
+type codeOutput struct {
+	Code   string `json:"code"`
+	Total  int    `json:"total"`
+	Counts []int  `json:"counts"`
+}
+
+type codesOutput struct {
+	SessionID       string       `json:"session_id"`
+	Start           string       `json:"start,omitempty"`
+	IntervalSeconds int          `json:"interval_seconds"`
+	Codes           []codeOutput `json:"codes"`
+}
+
+// runCodes counts the configured codebook (coding.codes) in a session,
+// the counts the TUI's C view shows live, as text, JSON, or a CSV time
+// series for analysis elsewhere.
+func runCodes(env Env, args []string) int {
+	fs, jsonOut := newFlagSet(env, "codes")
+	interval := fs.Duration("interval", 0, "Length of each interval (default coding.interval from config, else 1m)")
+	csvOut := fs.Bool("csv", false, "Write a CSV time series instead of text")
+	bom := fs.Bool("bom", false, "With --csv, start with a UTF-8 byte-order mark for Excel")
+	fs.Usage = func() {
+		fmt.Fprintln(env.Stderr, "Usage: steno codes [--interval D] [--csv [--bom]] [--json] <session-id>")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+	if *interval < 0 {
+		return fail(env, *jsonOut, fmt.Errorf("--interval %v: want a positive duration", *interval))
+	}
+
+	cfg, err := config.Load(config.Path())
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	if len(cfg.Coding.Codes) == 0 {
+		return fail(env, *jsonOut, fmt.Errorf("no codes configured: add them to coding.codes in %s", config.Path()))
+	}
+	codebook, err := cfg.Coding.Codebook()
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	step := *interval
+	if step == 0 {
+		step, _ = cfg.Coding.Step() // validated by config.Load
+	}
+
+	store, err := env.openStore()
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	defer store.Close()
+
+	sess, err := store.GetSession(fs.Arg(0))
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	if sess == nil {
+		return fail(env, *jsonOut, fmt.Errorf("session %s not found", fs.Arg(0)))
+	}
+	segments, err := store.SegmentsForSession(sess.ID, -1, 0)
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	series := codebook.Series(segments, step)
+	totals := series.Totals()
+
+	switch {
+	case *jsonOut:
+		out := codesOutput{SessionID: sess.ID, IntervalSeconds: int(step.Seconds()), Codes: make([]codeOutput, 0, len(series.Codes))}
+		if !series.Start.IsZero() {
+			out.Start = series.Start.UTC().Format(time.RFC3339)
+		}
+		for c, name := range series.Codes {
+			counts := make([]int, 0, len(series.Counts))
+			for _, row := range series.Counts {
+				counts = append(counts, row[c])
+			}
+			out.Codes = append(out.Codes, codeOutput{Code: name, Total: totals[c], Counts: counts})
+		}
+		if err := writeJSON(env.Stdout, out); err != nil {
+			return fail(env, false, err)
+		}
+	case *csvOut:
+		if err := export.CodesCSV(env.Stdout, sess.ID, series, *bom); err != nil {
+			return fail(env, false, err)
+		}
+	default:
+		width := 0
+		for _, name := range series.Codes {
+			width = max(width, len(name))
+		}
+		for c, name := range series.Codes {
+			fmt.Fprintf(env.Stdout, "%-*s  %4d  %s\n", width, name, totals[c], series.Sparkline(c, 60))
+		}
+	}
+	return 0
+}
@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jwulff/steno/internal/db"
+)
+
+// consentOutput is the `steno consent --json` shape. AcknowledgedAt is
+// empty when consent was never acknowledged for the session.
+type consentOutput struct {
+	SessionID      string `json:"session_id"`
+	Acknowledged   bool   `json:"acknowledged"`
+	AcknowledgedAt string `json:"acknowledged_at,omitempty"`
+}
+
+// runConsent shows whether recording consent was acknowledged for a
+// session (the TUI's consent banner, `c`), or records it now with --ack
+// for sessions recorded without the TUI open. An earlier acknowledgment
+// is kept.
+func runConsent(env Env, args []string) int {
+	fs, jsonOut := newFlagSet(env, "consent")
+	ack := fs.Bool("ack", false, "Record that consent was acknowledged now")
+	fs.Usage = func() {
+		fmt.Fprintln(env.Stderr, "Usage: steno consent [--ack] [--json] <session-id>")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+	sessionID := fs.Arg(0)
+
+	var store *db.Store
+	var err error
+	if *ack {
+		store, err = env.openClientStore()
+	} else {
+		store, err = env.openStore()
+	}
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	defer store.Close()
+
+	sess, err := store.GetSession(sessionID)
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	if sess == nil {
+		return fail(env, *jsonOut, fmt.Errorf("session %s not found", sessionID))
+	}
+	if *ack {
+		if err := store.AcknowledgeConsent(sessionID, time.Now()); err != nil {
+			return fail(env, *jsonOut, err)
+		}
+	}
+	at, err := store.ConsentAcknowledgedAt(sessionID)
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+
+	out := consentOutput{SessionID: sessionID, Acknowledged: at != nil}
+	if at != nil {
+		out.AcknowledgedAt = at.UTC().Format(time.RFC3339)
+	}
+	if *jsonOut {
+		if err := writeJSON(env.Stdout, out); err != nil {
+			return fail(env, false, err)
+		}
+		return 0
+	}
+	if at == nil {
+		fmt.Fprintf(env.Stdout, "%s: consent not acknowledged\n", sessionID)
+		return 0
+	}
+	fmt.Fprintf(env.Stdout, "%s: consent acknowledged %s\n", sessionID, out.AcknowledgedAt)
+	return 0
+}
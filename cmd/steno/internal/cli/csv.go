@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jwulff/steno/internal/db"
+	"github.com/jwulff/steno/internal/export"
+)
+
+// csvOutput is the `steno csv --json` shape.
+type csvOutput struct {
+	Files    []string `json:"files"`
+	Sessions int      `json:"sessions"`
+	Segments int      `json:"segments"`
+	Topics   int      `json:"topics"`
+}
+
+// runCSV writes segments.csv and topics.csv for one or more sessions into
+// a directory, for analysis in a spreadsheet. Rows from every session go
+// in the same two files, keyed by session_id.
+func runCSV(env Env, args []string) int {
+	fs, jsonOut := newFlagSet(env, "csv")
+	dir := fs.String("o", ".", "Directory to write segments.csv and topics.csv into")
+	bom := fs.Bool("bom", false, "Start each file with a UTF-8 byte-order mark, so Excel opens non-ASCII text correctly")
+	fs.Usage = func() {
+		fmt.Fprintln(env.Stderr, "Usage: steno csv [--bom] [--json] [-o DIR] <session-id>...")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+
+	store, err := env.openStore()
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	defer store.Close()
+
+	var segments []db.Segment
+	var topics []db.Topic
+	for _, id := range fs.Args() {
+		sess, err := store.GetSession(id)
+		if err != nil {
+			return fail(env, *jsonOut, err)
+		}
+		if sess == nil {
+			return fail(env, *jsonOut, fmt.Errorf("session %s not found", id))
+		}
+		segs, err := store.SegmentsForSession(sess.ID, -1, 0)
+		if err != nil {
+			return fail(env, *jsonOut, err)
+		}
+		tops, err := store.TopicsForSession(sess.ID)
+		if err != nil {
+			return fail(env, *jsonOut, err)
+		}
+		segments = append(segments, segs...)
+		topics = append(topics, tops...)
+	}
+
+	if err := os.MkdirAll(*dir, 0o755); err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	segPath, topicPath := filepath.Join(*dir, "segments.csv"), filepath.Join(*dir, "topics.csv")
+	if err := writeCSVFile(segPath, func(f *os.File) error { return export.SegmentsCSV(f, segments, *bom) }); err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	if err := writeCSVFile(topicPath, func(f *os.File) error { return export.TopicsCSV(f, topics, *bom) }); err != nil {
+		return fail(env, *jsonOut, err)
+	}
+
+	if *jsonOut {
+		out := csvOutput{Files: []string{segPath, topicPath}, Sessions: fs.NArg(), Segments: len(segments), Topics: len(topics)}
+		if err := writeJSON(env.Stdout, out); err != nil {
+			return fail(env, false, err)
+		}
+		return 0
+	}
+	fmt.Fprintf(env.Stderr, "Wrote %d segments to %s and %d topics to %s\n", len(segments), segPath, len(topics), topicPath)
+	return 0
+}
+
+func writeCSVFile(path string, write func(*os.File) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	return errors.Join(write(f), f.Close())
+}
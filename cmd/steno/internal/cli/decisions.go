@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jwulff/steno/internal/decisions"
+	"github.com/jwulff/steno/internal/export"
+)
+
+// decisionOutput is one entry of the `steno decisions --json` shape.
+type decisionOutput struct {
+	SegmentSeq int    `json:"segment_seq"`
+	Cue        string `json:"cue"`
+	Quote      string `json:"quote"`
+	StartedAt  string `json:"started_at"`
+	Permalink  string `json:"permalink"`
+}
+
+type decisionsOutput struct {
+	SessionID string           `json:"session_id"`
+	Decisions []decisionOutput `json:"decisions"`
+}
+
+// runDecisions flags the decisions in a session's segments, the same
+// pass the TUI runs live, and lists every decision recorded for it.
+func runDecisions(env Env, args []string) int {
+	fs, jsonOut := newFlagSet(env, "decisions")
+	fs.Usage = func() {
+		fmt.Fprintln(env.Stderr, "Usage: steno decisions [--json] <session-id>")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+
+	store, err := env.openClientStore()
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	defer store.Close()
+
+	sess, err := store.GetSession(fs.Arg(0))
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	if sess == nil {
+		return fail(env, *jsonOut, fmt.Errorf("session %s not found", fs.Arg(0)))
+	}
+	found, err := decisions.Sync(store, sess.ID)
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+
+	if *jsonOut {
+		out := decisionsOutput{SessionID: sess.ID, Decisions: make([]decisionOutput, 0, len(found))}
+		for _, d := range found {
+			out.Decisions = append(out.Decisions, decisionOutput{
+				SegmentSeq: d.SegmentSeq,
+				Cue:        d.Cue,
+				Quote:      d.SegmentText,
+				StartedAt:  d.SegmentStartedAt.UTC().Format(time.RFC3339),
+				Permalink:  export.SegmentPermalink(sess.ID, d.SegmentSeq),
+			})
+		}
+		if err := writeJSON(env.Stdout, out); err != nil {
+			return fail(env, false, err)
+		}
+		return 0
+	}
+	if len(found) == 0 {
+		fmt.Fprintf(env.Stderr, "No decisions found in %s\n", sess.ID)
+		return 0
+	}
+	for _, d := range found {
+		fmt.Fprintf(env.Stdout, "#%d [%s] %s\n", d.SegmentSeq, d.SegmentStartedAt.Local().Format("15:04:05"), d.SegmentText)
+	}
+	return 0
+}
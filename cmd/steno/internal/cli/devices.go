@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/jwulff/steno/internal/daemon"
+)
+
+// devicesOutput is the `steno devices --json` shape. Devices is always a
+// (possibly empty) array, never null.
+type devicesOutput struct {
+	Devices []string `json:"devices"`
+}
+
+// runDevices lists the audio input devices the daemon can capture from.
+func runDevices(env Env, args []string) int {
+	fs, jsonOut := newFlagSet(env, "devices")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	client, err := daemon.Connect(env.socketPath())
+	if err != nil {
+		return fail(env, *jsonOut, fmt.Errorf("daemon not running: %w", err))
+	}
+	defer client.Close()
+
+	resp, err := client.SendCommand(daemon.Command{Cmd: "devices"})
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	if !resp.OK {
+		return fail(env, *jsonOut, fmt.Errorf("devices: %s", resp.Error))
+	}
+
+	out := devicesOutput{Devices: resp.Devices}
+	if out.Devices == nil {
+		out.Devices = []string{}
+	}
+	if *jsonOut {
+		if err := writeJSON(env.Stdout, out); err != nil {
+			return fail(env, false, err)
+		}
+		return 0
+	}
+
+	if len(out.Devices) == 0 {
+		fmt.Fprintln(env.Stdout, "No input devices reported.")
+		return 0
+	}
+	for _, d := range out.Devices {
+		fmt.Fprintln(env.Stdout, d)
+	}
+	return 0
+}
@@ -0,0 +1,206 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/jwulff/steno/internal/config"
+	"github.com/jwulff/steno/internal/daemon"
+	"github.com/jwulff/steno/internal/dictation"
+)
+
+// dictateOutput is the `steno dictate --json` shape, printed once
+// dictation stops.
+type dictateOutput struct {
+	Out       string `json:"out"`
+	SessionID string `json:"session_id,omitempty"`
+	Segments  int    `json:"segments"`
+	Started   bool   `json:"started"`
+}
+
+// runDictate appends the mic's finished segments to a file as they are
+// spoken, until Ctrl-C: voice notes without the TUI. An idle daemon is
+// started and stopped again on the way out; a session already recording
+// is followed and left running. As in the TUI's dictation mode, the
+// profile's macros (`steno macros`) and the built-in rules rewrite each
+// segment, unless --raw.
+func runDictate(env Env, args []string) int {
+	fs, jsonOut := newFlagSet(env, "dictate")
+	out := fs.String("out", "", "Append the dictated text to this file")
+	profile := fs.String("profile", "", "Dictation profile whose macros apply (default dictation.profile from config, else default)")
+	raw := fs.Bool("raw", false, "Write segments as heard, without dictation macros")
+	stamp := fs.Bool("stamp", false, "Start each segment's line with the time it was said, like [14:02:10]")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *out == "" || fs.NArg() > 0 {
+		fmt.Fprintln(env.Stderr, "usage: steno dictate --out FILE [--profile NAME] [--raw] [--stamp] [--json]")
+		return 2
+	}
+
+	cfg, err := config.Load(config.Path())
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	var macros *dictation.Macros
+	if !*raw {
+		name := *profile
+		if name == "" {
+			name = cfg.Dictation.ProfileName()
+		}
+		if macros, err = env.dictationMacros(name); err != nil {
+			return fail(env, *jsonOut, err)
+		}
+	}
+
+	f, err := os.OpenFile(*out, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	defer f.Close()
+
+	client, err := daemon.Connect(env.socketPath())
+	if err != nil {
+		return fail(env, *jsonOut, fmt.Errorf("daemon not running: %w", err))
+	}
+	defer client.Close()
+	status, err := client.SendCommand(daemon.Command{Cmd: "status"})
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	if !status.OK {
+		return fail(env, *jsonOut, fmt.Errorf("status: %s", status.Error))
+	}
+	if status.Paused != nil && *status.Paused {
+		return fail(env, *jsonOut, errors.New("recording is paused; resume it with `steno toggle` first"))
+	}
+
+	// Subscribe before starting, so the first words aren't missed.
+	evClient, err := daemon.Connect(env.socketPath())
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	defer evClient.Close()
+	resp, err := evClient.SendCommand(daemon.Command{Cmd: "subscribe"})
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	if !resp.OK {
+		return fail(env, *jsonOut, fmt.Errorf("subscribe: %s", resp.Error))
+	}
+
+	result := dictateOutput{Out: *out, SessionID: status.SessionID}
+	if status.Recording == nil || !*status.Recording {
+		resp, err := client.SendCommand(daemon.StartCmd(daemon.StartOptions{}))
+		if err != nil {
+			return fail(env, *jsonOut, err)
+		}
+		if !resp.OK {
+			return fail(env, *jsonOut, fmt.Errorf("start: %s", resp.Error))
+		}
+		result.Started, result.SessionID = true, resp.SessionID
+	}
+	if !*jsonOut {
+		fmt.Fprintf(env.Stdout, "Dictating to %s (Ctrl-C to stop)\n", *out)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	errc := make(chan error, 1)
+	go func() {
+		for {
+			ev, err := evClient.ReadEvent()
+			if err != nil {
+				errc <- err
+				return
+			}
+			if ev.Event != "segment" || ev.Source == "systemAudio" {
+				continue
+			}
+			ok, err := writeDictated(f, ev, macros, *stamp)
+			if err != nil {
+				errc <- err
+				return
+			}
+			if ok {
+				result.Segments++
+			}
+		}
+	}()
+	var streamErr error
+	select {
+	case <-ctx.Done():
+		// Closing the connection ends the blocked read.
+		evClient.Close()
+		<-errc
+	case streamErr = <-errc:
+	}
+	if streamErr != nil {
+		return fail(env, *jsonOut, fmt.Errorf("stopped after %d segments: %w", result.Segments, streamErr))
+	}
+
+	if result.Started {
+		resp, err := client.SendCommand(daemon.Command{Cmd: "stop"})
+		if err != nil {
+			return fail(env, *jsonOut, err)
+		}
+		if !resp.OK {
+			return fail(env, *jsonOut, fmt.Errorf("stop: %s", resp.Error))
+		}
+	}
+	if *jsonOut {
+		if err := writeJSON(env.Stdout, result); err != nil {
+			return fail(env, false, err)
+		}
+		return 0
+	}
+	fmt.Fprintf(env.Stdout, "Wrote %d segments to %s\n", result.Segments, *out)
+	return 0
+}
+
+// writeDictated appends a segment's text to w as one line, rewritten by
+// macros, and reports whether there was any text to write.
+func writeDictated(w io.Writer, ev daemon.Event, macros *dictation.Macros, stamp bool) (bool, error) {
+	text := strings.TrimSpace(macros.Apply(ev.Text))
+	if text == "" {
+		return false, nil
+	}
+	if stamp {
+		at := time.Now()
+		if ev.StartedAt != nil {
+			at = time.Unix(0, int64(*ev.StartedAt*float64(time.Second)))
+		}
+		text = "[" + at.Local().Format("15:04:05") + "] " + text
+	}
+	_, err := io.WriteString(w, text+"\n")
+	return err == nil, err
+}
+
+// dictationMacros compiles profile's macros ahead of the built-in rules.
+// Without a database yet there are only the built-in ones.
+func (e Env) dictationMacros(profile string) (*dictation.Macros, error) {
+	if _, err := os.Stat(e.dbPath()); os.IsNotExist(err) {
+		return dictation.Compile(nil)
+	}
+	store, err := e.openStore()
+	if err != nil {
+		return nil, err
+	}
+	defer store.Close()
+	found, err := store.DictationMacros(profile)
+	if err != nil {
+		return nil, err
+	}
+	var rules []dictation.Rule
+	for _, m := range found {
+		rules = append(rules, dictation.Rule{Pattern: m.Pattern, Replacement: m.Replacement})
+	}
+	return dictation.Compile(rules)
+}
@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jwulff/steno/internal/entities"
+	"github.com/jwulff/steno/internal/export"
+)
+
+// entityOutput is one entry of the `steno entities --json` shape.
+type entityOutput struct {
+	Kind      string `json:"kind"`
+	Text      string `json:"text"`
+	Count     int    `json:"count"`
+	FirstSeq  int    `json:"first_seq"`
+	FirstAt   string `json:"first_at"`
+	Permalink string `json:"permalink"`
+}
+
+type entitiesOutput struct {
+	SessionID string         `json:"session_id"`
+	Entities  []entityOutput `json:"entities"`
+}
+
+// runEntities lists the dates, times, amounts and links said in a
+// session, the same list the TUI's X key shows, as text, JSON or CSV.
+func runEntities(env Env, args []string) int {
+	fs, jsonOut := newFlagSet(env, "entities")
+	kinds := fs.String("kind", "", "Comma-separated kinds to list: date, time, amount, url (default all)")
+	csvOut := fs.Bool("csv", false, "Write CSV instead of text")
+	bom := fs.Bool("bom", false, "With --csv, start with a UTF-8 byte-order mark for Excel")
+	fs.Usage = func() {
+		fmt.Fprintln(env.Stderr, "Usage: steno entities [--kind K,...] [--csv [--bom]] [--json] <session-id>")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+	var want []entities.Kind
+	for _, k := range strings.Split(*kinds, ",") {
+		if k = strings.TrimSpace(k); k == "" {
+			continue
+		}
+		if !slices.Contains(entities.Kinds, entities.Kind(k)) {
+			return fail(env, *jsonOut, fmt.Errorf("unknown --kind %q: want date, time, amount or url", k))
+		}
+		want = append(want, entities.Kind(k))
+	}
+
+	store, err := env.openStore()
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	defer store.Close()
+
+	sess, err := store.GetSession(fs.Arg(0))
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	if sess == nil {
+		return fail(env, *jsonOut, fmt.Errorf("session %s not found", fs.Arg(0)))
+	}
+	segments, err := store.SegmentsForSession(sess.ID, -1, 0)
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	found := entities.Collect(segments)
+	if len(want) > 0 {
+		found = slices.DeleteFunc(found, func(e entities.Entity) bool { return !slices.Contains(want, e.Kind) })
+	}
+
+	switch {
+	case *jsonOut:
+		out := entitiesOutput{SessionID: sess.ID, Entities: make([]entityOutput, 0, len(found))}
+		for _, e := range found {
+			out.Entities = append(out.Entities, entityOutput{
+				Kind:      string(e.Kind),
+				Text:      e.Text,
+				Count:     e.Count,
+				FirstSeq:  e.FirstSeq,
+				FirstAt:   e.FirstAt.UTC().Format(time.RFC3339),
+				Permalink: export.SegmentPermalink(sess.ID, e.FirstSeq),
+			})
+		}
+		if err := writeJSON(env.Stdout, out); err != nil {
+			return fail(env, false, err)
+		}
+	case *csvOut:
+		if err := export.EntitiesCSV(env.Stdout, sess.ID, found, *bom); err != nil {
+			return fail(env, false, err)
+		}
+	case len(found) == 0:
+		fmt.Fprintf(env.Stderr, "No dates, times, amounts or links found in %s\n", sess.ID)
+	default:
+		for _, e := range found {
+			fmt.Fprintf(env.Stdout, "%-7s %s  ×%d  first #%d [%s]\n", e.Kind, e.Text, e.Count, e.FirstSeq, e.FirstAt.Local().Format("15:04:05"))
+		}
+	}
+	return 0
+}
@@ -0,0 +1,283 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jwulff/steno/internal/config"
+	"github.com/jwulff/steno/internal/daemon"
+	"github.com/jwulff/steno/internal/db"
+	"github.com/jwulff/steno/internal/export"
+	"github.com/jwulff/steno/internal/version"
+)
+
+// exportOutput is the `steno export --json` shape: the manifest and the
+// exported lines, after anonymization.
+type exportOutput struct {
+	Manifest export.Manifest `json:"manifest"`
+	Title    string          `json:"title,omitempty"`
+	Summary  string          `json:"summary,omitempty"`
+	Notes    string          `json:"notes,omitempty"`
+	Segments []exportSegment `json:"segments"`
+	// TermIssues are the custom dictionary terms spelled inconsistently,
+	// before any --fix-terms.
+	TermIssues []export.TermIssue `json:"term_issues,omitempty"`
+}
+
+type exportSegment struct {
+	Seq           int     `json:"seq"`
+	OffsetSeconds float64 `json:"offset_seconds"`
+	StartedAt     string  `json:"started_at,omitempty"`
+	Speaker       string  `json:"speaker"`
+	Text          string  `json:"text"`
+	CleanText     string  `json:"clean_text,omitempty"`
+	Decision      bool    `json:"decision,omitempty"`
+}
+
+// noAnonymize is the --anonymize value that overrides a configured preset.
+const noAnonymize = "none"
+
+// runExport exports a session's transcript as Markdown, Whisper JSON or
+// SRT / WebVTT subtitles, optionally anonymized with one of export.Presets. With -o, a manifest recording
+// the preset and what it removed is written next to the file as
+// FILE.manifest.json, and an interrupted export is resumed from its
+// FILE.progress.json unless --restart is given. --clean adds a clean
+// verbatim rewrite from the daemon's language model (see cleanTranscript).
+// Spellings of the custom dictionary's terms (config export.terms) other
+// than its own are reported, and replaced with --fix-terms.
+func runExport(env Env, args []string) int {
+	fs, jsonOut := newFlagSet(env, "export")
+	anonymize := fs.String("anonymize", "", "Anonymization preset: "+strings.Join(export.PresetNames(), ", ")+", or none (default from config, else none)")
+	format := fs.String("format", export.TranscriptFormatMarkdown, "Output format: "+strings.Join(export.TranscriptFormats, ", "))
+	out := fs.String("o", "", "Write the transcript to this file, and its manifest to FILE.manifest.json")
+	restart := fs.Bool("restart", false, "With -o, start over instead of resuming an interrupted export")
+	maxChars := fs.Int("max-chars", export.DefaultSubtitleChars, "Subtitle formats: longest line, in characters")
+	maxDuration := fs.Duration("max-duration", export.DefaultSubtitleDuration, "Subtitle formats: longest a cue stays on screen")
+	clean := fs.Bool("clean", false, "Also rewrite the transcript as clean verbatim with the daemon's language model: printed instead of the verbatim one, or with -o written beside it, with .clean ahead of the extension")
+	fixTerms := fs.Bool("fix-terms", false, "Replace the flagged spellings of custom dictionary terms (config export.terms) with the dictionary's")
+	fs.Usage = func() {
+		fmt.Fprintln(env.Stderr, "Usage: steno export [--format F] [--anonymize PRESET] [--clean] [--fix-terms] [--max-chars N] [--max-duration D] [--json] [-o FILE [--restart]] <session-id>")
+		fmt.Fprintln(env.Stderr, "\nPresets:")
+		for _, p := range export.Presets {
+			fmt.Fprintf(env.Stderr, "  %-15s %s\n", p.Name, p.Summary)
+		}
+		fmt.Fprintln(env.Stderr)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+	if !slices.Contains(export.TranscriptFormats, *format) {
+		return fail(env, *jsonOut, fmt.Errorf("unknown --format %q: want one of %s", *format, strings.Join(export.TranscriptFormats, ", ")))
+	}
+	if *maxChars <= 0 || *maxDuration <= 0 {
+		return fail(env, *jsonOut, errors.New("--max-chars and --max-duration must be positive"))
+	}
+	sub := export.SubtitleOptions{MaxChars: *maxChars, MaxDuration: *maxDuration}
+
+	cfg, err := config.Load(config.Path())
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	presetName := *anonymize
+	if presetName == "" {
+		presetName = cfg.Export.Anonymize
+	}
+	var preset *export.Preset
+	if presetName != "" && presetName != noAnonymize {
+		p, err := export.LookupPreset(presetName)
+		if err != nil {
+			return fail(env, *jsonOut, err)
+		}
+		preset = &p
+	}
+
+	// The clean verbatim rewrites are kept in a client-owned table.
+	openStore := env.openStore
+	if *clean {
+		openStore = env.openClientStore
+	}
+	store, err := openStore()
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	defer store.Close()
+
+	transcript, err := export.LoadTranscript(store, fs.Arg(0))
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	terms := make([]export.Term, len(cfg.Export.Terms))
+	for i, t := range cfg.Export.Terms {
+		terms[i] = export.Term{Term: t.Term, Variants: t.Variants}
+	}
+	termIssues := export.LintTerms(transcript, terms)
+	termFixes := 0
+	if *fixTerms {
+		transcript, termFixes = export.FixTerms(transcript, terms)
+	}
+	var cleaned *export.Transcript
+	if *clean {
+		c, err := cleanTranscript(env, store, transcript)
+		if err != nil {
+			return fail(env, *jsonOut, err)
+		}
+		cleaned = &c
+	}
+	manifest := export.Manifest{
+		Tool:       "steno",
+		Version:    version.Version,
+		ExportedAt: time.Now().UTC().Truncate(time.Second),
+		Format:     *format,
+		SessionID:  transcript.SessionID,
+		Segments:   len(transcript.Lines),
+		TermFixes:  termFixes,
+	}
+	if preset != nil {
+		var rec export.Anonymization
+		if transcript, rec, err = export.Anonymize(transcript, *preset); err != nil {
+			return fail(env, *jsonOut, err)
+		}
+		manifest.SessionID = transcript.SessionID
+		manifest.Anonymization = &rec
+		if cleaned != nil {
+			c, _, err := export.Anonymize(*cleaned, *preset)
+			if err != nil {
+				return fail(env, *jsonOut, err)
+			}
+			cleaned = &c
+		}
+	}
+
+	if *jsonOut {
+		manifest.Format = "json"
+		manifest.Clean = cleaned != nil
+		result := exportOutput{Manifest: manifest, Title: transcript.Title, Summary: transcript.Summary, Notes: transcript.Notes, Segments: make([]exportSegment, 0, len(transcript.Lines)), TermIssues: termIssues}
+		for i, l := range transcript.Lines {
+			seg := exportSegment{Seq: l.Seq, OffsetSeconds: l.Offset.Seconds(), Speaker: l.Speaker, Text: l.Text, Decision: slices.Contains(transcript.Decisions, l.Seq)}
+			if cleaned != nil {
+				seg.CleanText = cleaned.Lines[i].Text
+			}
+			if !l.At.IsZero() {
+				seg.StartedAt = l.At.UTC().Format(time.RFC3339)
+			}
+			result.Segments = append(result.Segments, seg)
+		}
+		if err := writeJSON(env.Stdout, result); err != nil {
+			return fail(env, false, err)
+		}
+		return 0
+	}
+
+	reportTerms(env, termIssues, termFixes)
+	if *out == "" {
+		if cleaned != nil {
+			transcript = *cleaned
+		}
+		if err := export.WriteTranscript(env.Stdout, *format, transcript, sub); err != nil {
+			return fail(env, false, err)
+		}
+		return 0
+	}
+	if err := writeExportFile(env, *out, *format, transcript, sub, manifest, *restart); err != nil {
+		return fail(env, false, err)
+	}
+	if cleaned != nil {
+		manifest.Clean = true
+		if err := writeExportFile(env, export.CleanPath(*out), *format, *cleaned, sub, manifest, *restart); err != nil {
+			return fail(env, false, err)
+		}
+	}
+	return 0
+}
+
+// reportTerms lists the custom dictionary terms spelled inconsistently
+// on stderr, or how many spellings --fix-terms replaced, so the report
+// stays out of an export on stdout.
+func reportTerms(env Env, issues []export.TermIssue, fixed int) {
+	if len(issues) == 0 {
+		return
+	}
+	if fixed > 0 {
+		fmt.Fprintf(env.Stderr, "Fixed %d spellings of %d custom dictionary terms\n", fixed, len(issues))
+		return
+	}
+	fmt.Fprintln(env.Stderr, "Inconsistent spellings of custom dictionary terms:")
+	for _, i := range issues {
+		fmt.Fprintf(env.Stderr, "  %s\n", i)
+	}
+	fmt.Fprintln(env.Stderr, "Run with --fix-terms to apply these fixes.")
+}
+
+// writeExportFile writes t to path, resuming an interrupted export of it
+// unless restart is set, then writes its manifest.
+func writeExportFile(env Env, path, format string, t export.Transcript, sub export.SubtitleOptions, manifest export.Manifest, restart bool) error {
+	if restart {
+		if err := os.Remove(export.ProgressPath(path)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	exp, err := export.ResumeExport(path, format, t, sub)
+	if err != nil {
+		return err
+	}
+	if n := exp.Resumed(); n > 0 {
+		fmt.Fprintf(env.Stderr, "Resuming an interrupted export of %s after segment %d of %d\n", path, n, exp.Total())
+	}
+	if _, err := exp.Write(0); err != nil {
+		exp.Close()
+		return err
+	}
+	if err := export.WriteManifestFile(path, manifest); err != nil {
+		return err
+	}
+	fmt.Fprintf(env.Stderr, "Wrote %d segments to %s (manifest: %s)\n", len(t.Lines), path, export.ManifestPath(path))
+	return nil
+}
+
+// cleanTranscript rewrites t as clean verbatim through the daemon's
+// `clean_verbatim` command. Rewrites are stored beside the verbatim
+// segments, so a later export only sends the segments changed since, and
+// a session cleaned before needs no daemon at all. Rewrites made before
+// a failure are stored too.
+func cleanTranscript(env Env, store *db.Store, t export.Transcript) (export.Transcript, error) {
+	cached, err := store.SegmentCleanups(t.SessionID)
+	if err != nil {
+		return export.Transcript{}, err
+	}
+	var client *daemon.Client
+	defer func() {
+		if client != nil {
+			client.Close()
+		}
+	}()
+	cleaned, fresh, err := export.CleanVerbatim(t, cached, func(texts []string) ([]string, error) {
+		if client == nil {
+			c, err := daemon.Connect(env.socketPath())
+			if err != nil {
+				return nil, fmt.Errorf("daemon not running: %w", err)
+			}
+			client = c
+		}
+		resp, err := client.SendCommand(daemon.CleanVerbatimCmd(texts))
+		if err != nil {
+			return nil, err
+		}
+		if !resp.OK {
+			return nil, errors.New(resp.Error)
+		}
+		return resp.Texts, nil
+	})
+	if saveErr := store.SaveSegmentCleanups(t.SessionID, fresh); saveErr != nil && err == nil {
+		err = saveErr
+	}
+	return cleaned, err
+}
@@ -0,0 +1,139 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jwulff/steno/internal/export"
+)
+
+// followUpOutput is the `steno followup --json` shape.
+type followUpOutput struct {
+	SessionID string `json:"session_id"`
+	Topic     int    `json:"topic"`
+	Title     string `json:"title"`
+	Kind      string `json:"kind"`
+	Start     string `json:"start"`
+	Permalink string `json:"permalink"`
+	// ICS is the iCalendar text, unless it was written to a file.
+	ICS  string `json:"ics,omitempty"`
+	File string `json:"file,omitempty"`
+}
+
+// followUpTimeLayout is the --at format, in local time.
+const followUpTimeLayout = "2006-01-02 15:04"
+
+// runFollowUp writes an iCalendar follow-up for one of a session's
+// topics: a calendar event, or with --kind todo a to-do for Reminders,
+// carrying the topic's summary and a permalink to its first segment.
+// With no topic number it lists the session's topics, numbered. The TUI
+// does the same for the selected topic with F.
+func runFollowUp(env Env, args []string) int {
+	fs, jsonOut := newFlagSet(env, "followup")
+	kind := fs.String("kind", export.FollowUpEvent, "Entry to create: "+strings.Join(export.FollowUpKinds, ", "))
+	at := fs.String("at", "", `When, as "`+followUpTimeLayout+`" local time (default 9:00 on the next weekday)`)
+	duration := fs.Duration("duration", export.DefaultFollowUpDuration, "Event length")
+	out := fs.String("o", "", "Write the .ics to this file instead of stdout")
+	fs.Usage = func() {
+		fmt.Fprintln(env.Stderr, "Usage: steno followup [--kind event|todo] [--at TIME] [--duration D] [--json] [-o FILE] <session-id> [topic-number]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() < 1 || fs.NArg() > 2 {
+		fs.Usage()
+		return 2
+	}
+	if !slices.Contains(export.FollowUpKinds, *kind) {
+		return fail(env, *jsonOut, fmt.Errorf("unknown --kind %q: want one of %s", *kind, strings.Join(export.FollowUpKinds, ", ")))
+	}
+	if *duration <= 0 {
+		return fail(env, *jsonOut, errors.New("--duration must be positive"))
+	}
+	now := time.Now()
+	start := export.NextFollowUpSlot(now)
+	if *at != "" {
+		t, err := time.ParseInLocation(followUpTimeLayout, *at, time.Local)
+		if err != nil {
+			return fail(env, *jsonOut, fmt.Errorf("invalid --at %q: want %q", *at, followUpTimeLayout))
+		}
+		start = t
+	}
+
+	store, err := env.openStore()
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	defer store.Close()
+
+	sessionID := fs.Arg(0)
+	sess, err := store.GetSession(sessionID)
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	if sess == nil {
+		return fail(env, *jsonOut, fmt.Errorf("session %s not found", sessionID))
+	}
+	topics, err := store.TopicsForSession(sessionID)
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+
+	if fs.NArg() == 1 {
+		if len(topics) == 0 {
+			fmt.Fprintln(env.Stderr, "No topics in this session yet.")
+			return 0
+		}
+		for i, t := range topics {
+			fmt.Fprintf(env.Stdout, "%d  %s\n", i+1, t.Title)
+		}
+		return 0
+	}
+	n, err := strconv.Atoi(fs.Arg(1))
+	if err != nil || n < 1 || n > len(topics) {
+		return fail(env, *jsonOut, fmt.Errorf("session %s has no topic %s (it has %d)", sessionID, fs.Arg(1), len(topics)))
+	}
+	topic := topics[n-1]
+	followUp := export.NewFollowUp(*kind, topic, start, *duration)
+
+	var ics strings.Builder
+	if err := export.FollowUpICS(&ics, followUp, now); err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	if *out != "" {
+		if err := os.WriteFile(*out, []byte(ics.String()), 0o644); err != nil {
+			return fail(env, *jsonOut, err)
+		}
+	}
+
+	if *jsonOut {
+		result := followUpOutput{
+			SessionID: sessionID,
+			Topic:     n,
+			Title:     topic.Title,
+			Kind:      *kind,
+			Start:     start.UTC().Format(time.RFC3339),
+			Permalink: followUp.Permalink,
+			File:      *out,
+		}
+		if *out == "" {
+			result.ICS = ics.String()
+		}
+		if err := writeJSON(env.Stdout, result); err != nil {
+			return fail(env, false, err)
+		}
+		return 0
+	}
+	if *out != "" {
+		fmt.Fprintf(env.Stderr, "Wrote a follow-up for %q on %s to %s\n", topic.Title, start.Format(followUpTimeLayout), *out)
+		return 0
+	}
+	fmt.Fprint(env.Stdout, ics.String())
+	return 0
+}
@@ -0,0 +1,134 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jwulff/steno/internal/daemon"
+)
+
+// togglePauseSeconds matches the TUI's `p`: a 30-minute pause that
+// auto-resumes.
+const togglePauseSeconds = 1800
+
+// defaultHotkey is the skhd binding `steno hotkey` suggests.
+const defaultHotkey = "ctrl + alt + cmd - r"
+
+// toggleOutput is the `steno toggle --json` shape.
+type toggleOutput struct {
+	Action string `json:"action"` // "paused" or "resumed"
+	statusOutput
+}
+
+// runToggle pauses a recording daemon or resumes a paused one: the
+// always-on equivalent of start/stop, meant for a global hotkey (see
+// runHotkey). A TUI that is open sees the change through its
+// pause_state event.
+func runToggle(env Env, args []string) int {
+	fs, jsonOut := newFlagSet(env, "toggle")
+	indefinite := fs.Bool("indefinite", false, "Pause with no auto-resume (like P in the TUI)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	client, err := daemon.Connect(env.socketPath())
+	if err != nil {
+		return fail(env, *jsonOut, fmt.Errorf("daemon not running: %w", err))
+	}
+	defer client.Close()
+
+	status, err := client.SendCommand(daemon.Command{Cmd: "status"})
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	if !status.OK {
+		return fail(env, *jsonOut, fmt.Errorf("status: %s", status.Error))
+	}
+
+	cmd, action := daemon.PauseCmd(togglePauseSeconds), "paused"
+	if *indefinite {
+		cmd = daemon.PauseIndefiniteCmd()
+	}
+	if status.Paused != nil && *status.Paused {
+		cmd, action = daemon.ResumeCmd(), "resumed"
+	}
+	resp, err := client.SendCommand(cmd)
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	if !resp.OK {
+		return fail(env, *jsonOut, fmt.Errorf("%s: %s", cmd.Cmd, resp.Error))
+	}
+
+	out := toggleOutput{Action: action, statusOutput: statusFromResponse(resp)}
+	if *jsonOut {
+		if err := writeJSON(env.Stdout, out); err != nil {
+			return fail(env, false, err)
+		}
+		return 0
+	}
+	switch {
+	case action == "resumed":
+		fmt.Fprintln(env.Stdout, "resumed")
+	case out.PauseExpiresAt != nil:
+		fmt.Fprintf(env.Stdout, "paused until %s\n", *out.PauseExpiresAt)
+	default:
+		fmt.Fprintln(env.Stdout, "paused")
+	}
+	return 0
+}
+
+// hotkeyOutput is the `steno hotkey --json` shape.
+type hotkeyOutput struct {
+	Key     string `json:"key"`
+	Command string `json:"command"`
+	Skhd    string `json:"skhd"`
+}
+
+// runHotkey prints an skhd (https://github.com/koekeishiya/skhd) binding
+// that runs `steno toggle`, so recording can be paused and resumed while
+// the TUI isn't focused. macOS has no way to register a global shortcut
+// from a terminal process, so steno delegates to a hotkey daemon rather
+// than running its own. Append the line to ~/.skhdrc.
+func runHotkey(env Env, args []string) int {
+	fs, jsonOut := newFlagSet(env, "hotkey")
+	key := fs.String("key", defaultHotkey, "skhd key combination")
+	indefinite := fs.Bool("indefinite", false, "Bind an indefinite pause instead of 30 minutes")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if strings.TrimSpace(*key) == "" || strings.Contains(*key, ":") {
+		return fail(env, *jsonOut, errors.New("--key must be an skhd key combination such as \""+defaultHotkey+"\""))
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		exe = "steno"
+	}
+	command := shellQuote(exe) + " toggle"
+	if *indefinite {
+		command += " --indefinite"
+	}
+	out := hotkeyOutput{Key: *key, Command: command, Skhd: *key + " : " + command}
+
+	if *jsonOut {
+		if err := writeJSON(env.Stdout, out); err != nil {
+			return fail(env, false, err)
+		}
+		return 0
+	}
+	fmt.Fprintln(env.Stdout, "# steno: pause/resume recording (append to ~/.skhdrc, then `skhd --reload`)")
+	fmt.Fprintln(env.Stdout, out.Skhd)
+	return 0
+}
+
+// shellQuote single-quotes s for sh when it contains anything beyond a
+// plain path.
+func shellQuote(s string) string {
+	if s != "" && strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789/._-") == "" {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,168 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jwulff/steno/internal/config"
+	"github.com/jwulff/steno/internal/db"
+	"github.com/jwulff/steno/internal/dictation"
+)
+
+// macroOutput is one rule of the `steno macros --json` shape.
+type macroOutput struct {
+	ID          int64  `json:"id,omitempty"`
+	Profile     string `json:"profile,omitempty"`
+	Pattern     string `json:"pattern"`
+	Replacement string `json:"replacement"`
+	CreatedAt   string `json:"created_at,omitempty"`
+}
+
+type macrosOutput struct {
+	Macros  []macroOutput `json:"macros"`
+	Builtin []macroOutput `json:"builtin"`
+}
+
+// macroTestOutput is the `steno macros --test --json` shape.
+type macroTestOutput struct {
+	Profile string `json:"profile"`
+	Text    string `json:"text"`
+	Result  string `json:"result"`
+}
+
+// replacementEscapes lets a replacement typed on the command line carry
+// line breaks.
+var replacementEscapes = strings.NewReplacer(`\\`, `\`, `\n`, "\n", `\t`, "\t")
+
+// runMacros manages dictation macros: per-profile regular expressions
+// the TUI applies to mic segments in dictation mode (D), ahead of the
+// built-in rules. With no action it lists the rules.
+func runMacros(env Env, args []string) int {
+	fs, jsonOut := newFlagSet(env, "macros")
+	profile := fs.String("profile", "", "Profile to add to or test (default dictation.profile from config, else default); with no action, list only this profile")
+	add := fs.String("add", "", `Add a rule rewriting this pattern (a case-insensitive Go regexp) to the REPLACEMENT that follows; \n is a line break`)
+	del := fs.Int64("delete", 0, "Delete the rule with this ID")
+	test := fs.Bool("test", false, "Apply the profile's rules to TEXT and print the result")
+	fs.Usage = func() {
+		fmt.Fprintln(env.Stderr, "Usage: steno macros [--profile NAME] [--json] [--add PATTERN [REPLACEMENT...] | --delete ID | --test TEXT...]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	actions := 0
+	for _, set := range []bool{*add != "", *del != 0, *test} {
+		if set {
+			actions++
+		}
+	}
+	if actions > 1 || (*test && fs.NArg() == 0) || (!*test && *add == "" && fs.NArg() > 0) {
+		fs.Usage()
+		return 2
+	}
+
+	name := *profile
+	if name == "" && (*add != "" || *test) {
+		cfg, err := config.Load(config.Path())
+		if err != nil {
+			return fail(env, *jsonOut, err)
+		}
+		name = cfg.Dictation.ProfileName()
+	}
+
+	var store *db.Store
+	var err error
+	if *add != "" || *del != 0 {
+		store, err = env.openClientStore()
+	} else {
+		store, err = env.openStore()
+	}
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	defer store.Close()
+
+	switch {
+	case *add != "":
+		rule := dictation.Rule{Pattern: *add, Replacement: replacementEscapes.Replace(strings.Join(fs.Args(), " "))}
+		if err := dictation.Validate(rule); err != nil {
+			return fail(env, *jsonOut, err)
+		}
+		if _, err := store.AddDictationMacro(name, rule.Pattern, rule.Replacement); err != nil {
+			return fail(env, *jsonOut, err)
+		}
+	case *del != 0:
+		ok, err := store.DeleteDictationMacro(*del)
+		if err != nil {
+			return fail(env, *jsonOut, err)
+		}
+		if !ok {
+			return fail(env, *jsonOut, fmt.Errorf("no dictation macro with ID %d", *del))
+		}
+	case *test:
+		return testMacros(env, store, name, strings.Join(fs.Args(), " "), *jsonOut)
+	}
+
+	macros, err := store.DictationMacros(*profile)
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	if *jsonOut {
+		out := macrosOutput{Macros: make([]macroOutput, 0, len(macros))}
+		for _, m := range macros {
+			out.Macros = append(out.Macros, macroOutput{
+				ID:          m.ID,
+				Profile:     m.Profile,
+				Pattern:     m.Pattern,
+				Replacement: m.Replacement,
+				CreatedAt:   m.CreatedAt.UTC().Format(time.RFC3339),
+			})
+		}
+		for _, r := range dictation.BuiltinRules {
+			out.Builtin = append(out.Builtin, macroOutput{Pattern: r.Pattern, Replacement: r.Replacement})
+		}
+		if err := writeJSON(env.Stdout, out); err != nil {
+			return fail(env, false, err)
+		}
+		return 0
+	}
+	if len(macros) == 0 {
+		fmt.Fprintln(env.Stderr, "No dictation macros; only the built-in rules apply")
+		return 0
+	}
+	tw := tabwriter.NewWriter(env.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tPROFILE\tPATTERN\tREPLACEMENT")
+	for _, m := range macros {
+		fmt.Fprintf(tw, "%d\t%s\t%q\t%q\n", m.ID, m.Profile, m.Pattern, m.Replacement)
+	}
+	tw.Flush()
+	fmt.Fprintln(env.Stderr, "Built-in rules (new paragraph, new line, brackets, parentheses, quotes) run after these")
+	return 0
+}
+
+// testMacros prints text as dictation mode would show it under profile.
+func testMacros(env Env, store *db.Store, profile, text string, jsonOut bool) int {
+	found, err := store.DictationMacros(profile)
+	if err != nil {
+		return fail(env, jsonOut, err)
+	}
+	var rules []dictation.Rule
+	for _, m := range found {
+		rules = append(rules, dictation.Rule{Pattern: m.Pattern, Replacement: m.Replacement})
+	}
+	macros, err := dictation.Compile(rules)
+	if err != nil {
+		return fail(env, jsonOut, err)
+	}
+	result := macros.Apply(text)
+	if jsonOut {
+		if err := writeJSON(env.Stdout, macroTestOutput{Profile: profile, Text: text, Result: result}); err != nil {
+			return fail(env, false, err)
+		}
+		return 0
+	}
+	fmt.Fprintln(env.Stdout, result)
+	return 0
+}
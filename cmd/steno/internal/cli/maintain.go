@@ -0,0 +1,268 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jwulff/steno/internal/config"
+	"github.com/jwulff/steno/internal/daemon"
+	"github.com/jwulff/steno/internal/db"
+)
+
+// defaultMaintenanceInterval is how often `steno maintain --if-due` runs
+// when the config doesn't say.
+const defaultMaintenanceInterval = 7 * 24 * time.Hour
+
+// maintainLaunchdLabel names the launchd agent printed by --launchd.
+const maintainLaunchdLabel = "com.steno.maintain"
+
+// vacuumQuiet is how long a recording daemon must have gone without a
+// segment for VACUUM to run: the always-on daemon is rarely idle, but a
+// silence this long is a gap between meetings.
+const vacuumQuiet = 30 * time.Minute
+
+// maintainOutput is the `steno maintain --json` shape. Skipped is set,
+// and Steps empty, when nothing ran. Deferred lists the steps left for a
+// quieter time, and DeferredBecause says why.
+type maintainOutput struct {
+	Skipped         string        `json:"skipped,omitempty"`
+	Steps           []maintainRun `json:"steps"`
+	Deferred        []string      `json:"deferred,omitempty"`
+	DeferredBecause string        `json:"deferred_because,omitempty"`
+	SizeBefore      int64         `json:"size_before"`
+	SizeAfter       int64         `json:"size_after"`
+	Reclaimed       int64         `json:"reclaimed"`
+	LastRun         *string       `json:"last_run,omitempty"`
+	NextDue         *string       `json:"next_due,omitempty"`
+}
+
+type maintainRun struct {
+	Step    string  `json:"step"`
+	Seconds float64 `json:"seconds"`
+}
+
+// runMaintain compacts and tunes the database: ANALYZE, VACUUM, then a
+// truncating WAL checkpoint. ANALYZE and the checkpoint run while the
+// daemon records. VACUUM holds the write lock for as long as it takes,
+// so it waits for the daemon to be down, idle, or without a segment for
+// vacuumQuiet; until it has run, the run isn't recorded and stays due.
+// --if-due is for schedulers: it runs only when the last recorded run is
+// older than maintenance.interval.
+func runMaintain(env Env, args []string) int {
+	fs, jsonOut := newFlagSet(env, "maintain")
+	ifDue := fs.Bool("if-due", false, "Run only if the last run is older than maintenance.interval (default 7 days)")
+	launchd := fs.Bool("launchd", false, "Print a launchd agent that runs `steno maintain --if-due` hourly")
+	fs.Usage = func() {
+		fmt.Fprintln(env.Stderr, "Usage: steno maintain [--if-due] [--json]")
+		fmt.Fprintln(env.Stderr, "       steno maintain --launchd > ~/Library/LaunchAgents/"+maintainLaunchdLabel+".plist")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return 2
+	}
+	if *launchd {
+		exe, err := os.Executable()
+		if err != nil {
+			exe = "steno"
+		}
+		fmt.Fprint(env.Stdout, maintainPlist(exe))
+		return 0
+	}
+
+	cfg, err := config.Load(config.Path())
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	interval, err := cfg.Maintenance.Every()
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	if interval == 0 {
+		interval = defaultMaintenanceInterval
+	}
+
+	out := maintainOutput{Steps: make([]maintainRun, 0, len(db.MaintenanceSteps))}
+	skip := func(reason string) int {
+		out.Skipped = reason
+		if *jsonOut {
+			if err := writeJSON(env.Stdout, out); err != nil {
+				return fail(env, false, err)
+			}
+		} else {
+			fmt.Fprintf(env.Stdout, "Skipped: %s\n", reason)
+		}
+		return 0
+	}
+	store, err := env.openMaintenanceStore()
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	defer store.Close()
+	if err := store.EnsureClientSchema(); err != nil {
+		return fail(env, *jsonOut, err)
+	}
+
+	last, err := store.LastMaintenance()
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	if last != nil {
+		ran := last.RanAt.UTC().Format(time.RFC3339)
+		out.LastRun = &ran
+		if due := last.RanAt.Add(interval); *ifDue && time.Now().Before(due) {
+			return skip("not due until " + due.Local().Format("2006-01-02 15:04"))
+		}
+	}
+
+	path := env.dbPath()
+	if out.SizeBefore, err = db.DatabaseSize(path); err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	for _, step := range db.MaintenanceSteps {
+		// Recording may have started since the last step.
+		busy, err := daemonBusy(env)
+		if err != nil {
+			return fail(env, *jsonOut, err)
+		}
+		if step == db.StepVacuum && busy != "" {
+			out.Deferred, out.DeferredBecause = append(out.Deferred, step), busy
+			continue
+		}
+		if !*jsonOut {
+			fmt.Fprintf(env.Stderr, "%-15s ", step+"...")
+		}
+		start := time.Now()
+		if err := store.RunMaintenanceStep(step); err != nil {
+			// The daemon's writes can hold the WAL open; the next run
+			// truncates it.
+			if errors.Is(err, db.ErrCheckpointBusy) && busy != "" {
+				if !*jsonOut {
+					fmt.Fprintln(env.Stderr, "busy")
+				}
+				out.Deferred, out.DeferredBecause = append(out.Deferred, step), busy
+				continue
+			}
+			if !*jsonOut {
+				fmt.Fprintln(env.Stderr, "failed")
+			}
+			return fail(env, *jsonOut, err)
+		}
+		elapsed := time.Since(start)
+		out.Steps = append(out.Steps, maintainRun{Step: step, Seconds: elapsed.Seconds()})
+		if !*jsonOut {
+			fmt.Fprintf(env.Stderr, "done in %s\n", elapsed.Round(time.Millisecond))
+		}
+	}
+	if out.SizeAfter, err = db.DatabaseSize(path); err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	out.Reclaimed = out.SizeBefore - out.SizeAfter
+
+	if len(out.Deferred) > 0 {
+		if *jsonOut {
+			if err := writeJSON(env.Stdout, out); err != nil {
+				return fail(env, false, err)
+			}
+			return 0
+		}
+		fmt.Fprintf(env.Stdout, "Deferred %s: %s\n", strings.Join(out.Deferred, ", "), out.DeferredBecause)
+		return 0
+	}
+
+	ranAt := time.Now()
+	if err := store.RecordMaintenance(db.MaintenanceRun{RanAt: ranAt, SizeBefore: out.SizeBefore, SizeAfter: out.SizeAfter}); err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	ran, next := ranAt.UTC().Format(time.RFC3339), ranAt.Add(interval).UTC().Format(time.RFC3339)
+	out.LastRun, out.NextDue = &ran, &next
+
+	if *jsonOut {
+		if err := writeJSON(env.Stdout, out); err != nil {
+			return fail(env, false, err)
+		}
+		return 0
+	}
+	fmt.Fprintf(env.Stdout, "Database: %s -> %s (%s reclaimed)\n", byteSize(out.SizeBefore), byteSize(out.SizeAfter), byteSize(max(out.Reclaimed, 0)))
+	return 0
+}
+
+// daemonBusy says why the daemon may write to the database soon, or is
+// empty when it won't: it isn't running, it's idle, or its session has
+// been silent for vacuumQuiet.
+func daemonBusy(env Env) (string, error) {
+	client, err := daemon.Connect(env.socketPath())
+	if err != nil {
+		return "", nil
+	}
+	defer client.Close()
+	resp, err := client.SendCommand(daemon.Command{Cmd: "status"})
+	if err != nil {
+		return "", fmt.Errorf("daemon status: %w", err)
+	}
+	if !resp.OK {
+		return "", fmt.Errorf("daemon status: %s", resp.Error)
+	}
+	status := statusFromResponse(resp)
+	if !status.Recording && !status.Paused {
+		return "", nil
+	}
+	if resp.LastSegmentAt != nil {
+		if quiet := time.Since(time.Unix(int64(*resp.LastSegmentAt), 0)); quiet >= vacuumQuiet {
+			return "", nil
+		}
+	}
+	if status.Paused {
+		return "daemon is paused mid-session; VACUUM waits until the session ends", nil
+	}
+	return fmt.Sprintf("daemon is recording; VACUUM waits for %d minutes without a segment", int(vacuumQuiet/time.Minute)), nil
+}
+
+// byteSize formats n with a binary unit, e.g. "12.3 MiB".
+func byteSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// maintainPlist is a launchd agent that runs `steno maintain --if-due`
+// every hour, so maintenance happens on the first quiet hour after it
+// falls due.
+func maintainPlist(exe string) string {
+	return `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+    <key>Label</key>
+    <string>` + maintainLaunchdLabel + `</string>
+    <key>ProgramArguments</key>
+    <array>
+        <string>` + xmlEscape(exe) + `</string>
+        <string>maintain</string>
+        <string>--if-due</string>
+    </array>
+    <key>StartInterval</key>
+    <integer>3600</integer>
+    <key>ProcessType</key>
+    <string>Background</string>
+</dict>
+</plist>
+`
+}
+
+func xmlEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/jwulff/steno/internal/db"
+)
+
+// meetingOutput is the `steno meeting --json` shape.
+type meetingOutput struct {
+	SessionID  string `json:"session_id"`
+	MeetingURL string `json:"meeting_url"`
+}
+
+// runMeeting records a session's meeting link (Zoom, Meet, ...) in
+// session_metadata, where the session browser shows it next to the title
+// to tell similar meetings apart. steno has no calendar integration, so
+// the link is supplied by hand or by a script.
+func runMeeting(env Env, args []string) int {
+	fs, jsonOut := newFlagSet(env, "meeting")
+	fs.Usage = func() {
+		fmt.Fprintln(env.Stderr, "Usage: steno meeting [--json] <session-id> <url>")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return 2
+	}
+	sessionID, link := fs.Arg(0), fs.Arg(1)
+	if u, err := url.Parse(link); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return fail(env, *jsonOut, fmt.Errorf("invalid meeting URL %q: want an http(s) link", link))
+	}
+
+	store, err := env.openClientStore()
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	defer store.Close()
+
+	sess, err := store.GetSession(sessionID)
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	if sess == nil {
+		return fail(env, *jsonOut, fmt.Errorf("session %s not found", sessionID))
+	}
+	if err := store.UpsertSessionMetadata(db.SessionMetadata{SessionID: sessionID, MeetingURL: link}); err != nil {
+		return fail(env, *jsonOut, err)
+	}
+
+	if *jsonOut {
+		if err := writeJSON(env.Stdout, meetingOutput{SessionID: sessionID, MeetingURL: link}); err != nil {
+			return fail(env, false, err)
+		}
+		return 0
+	}
+	fmt.Fprintf(env.Stdout, "Recorded meeting link for %s\n", sessionID)
+	return 0
+}
@@ -0,0 +1,191 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jwulff/steno/internal/db"
+)
+
+// mergePreviewLines caps how much of the merged timeline the text-mode
+// preview prints.
+const mergePreviewLines = 20
+
+// mergeOutput is the `steno merge --json` shape.
+type mergeOutput struct {
+	DryRun      bool          `json:"dry_run"`
+	TargetID    string        `json:"target_id"`
+	SourceID    string        `json:"source_id"`
+	StartedAt   string        `json:"started_at"`
+	EndedAt     *string       `json:"ended_at,omitempty"`
+	Segments    int           `json:"segments"`
+	Interleaved int           `json:"interleaved"`
+	Topics      []rangeOutput `json:"topics"`
+	Summaries   []rangeOutput `json:"summaries"`
+	// TimeAdjustments are the runs of segments retimed to undo a clock
+	// jump.
+	TimeAdjustments []timeAdjustmentOutput `json:"time_adjustments"`
+	Timeline        []segmentRemap         `json:"timeline"`
+}
+
+type timeAdjustmentOutput struct {
+	SessionID    string  `json:"session_id"`
+	FromSeq      int     `json:"from_seq"`
+	ToSeq        int     `json:"to_seq"`
+	ShiftSeconds float64 `json:"shift_seconds"`
+	Reason       string  `json:"reason"`
+}
+
+type rangeOutput struct {
+	ID       string `json:"id"`
+	Label    string `json:"label"`
+	From     string `json:"from_session_id"`
+	OldStart int    `json:"old_start"`
+	OldEnd   int    `json:"old_end"`
+	NewStart int    `json:"new_start"`
+	NewEnd   int    `json:"new_end"`
+}
+
+type segmentRemap struct {
+	ID        string `json:"id"`
+	From      string `json:"from_session_id"`
+	OldSeq    int    `json:"old_seq"`
+	NewSeq    int    `json:"new_seq"`
+	StartedAt string `json:"started_at"`
+	// ShiftSeconds is how far the segment's times move, when they do.
+	ShiftSeconds float64 `json:"shift_seconds,omitempty"`
+}
+
+// runMerge merges the second session into the first. Both must be
+// finished; the daemon never sees the change because it only appends to
+// the active session.
+func runMerge(env Env, args []string) int {
+	fs, jsonOut := newFlagSet(env, "merge")
+	dryRun := fs.Bool("dry-run", false, "Show the merged order without writing")
+	fs.Usage = func() {
+		fmt.Fprintln(env.Stderr, "Usage: steno merge [--dry-run] [--json] <target-session-id> <source-session-id>")
+		fmt.Fprintln(env.Stderr, "\nThe source session's segments, topics, and summaries move into the target, interleaved by time; the source is deleted.")
+		fmt.Fprintln(env.Stderr, "Segments of one source that overlap are taken for a clock jump and retimed; the report lists each run moved.")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return 2
+	}
+	targetID, sourceID := fs.Arg(0), fs.Arg(1)
+
+	store, err := env.openMaintenanceStore()
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	defer store.Close()
+
+	var plan *db.MergePlan
+	if *dryRun {
+		plan, err = store.PlanMerge(targetID, sourceID)
+	} else {
+		plan, err = store.MergeSessions(targetID, sourceID)
+	}
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+
+	out := formatMerge(plan, *dryRun)
+	if *jsonOut {
+		if err := writeJSON(env.Stdout, out); err != nil {
+			return fail(env, false, err)
+		}
+		return 0
+	}
+
+	verb := "Merged"
+	if *dryRun {
+		verb = "Would merge"
+	}
+	fmt.Fprintf(env.Stdout, "%s %s into %s: %d segments (%d interleaved), %d topics, %d summaries\n",
+		verb, sourceID, targetID, out.Segments, out.Interleaved, len(out.Topics), len(out.Summaries))
+	for _, a := range plan.TimeAdjustments {
+		fmt.Fprintf(env.Stdout, "retimed %s #%d-%d by +%s (%s)\n", a.SessionID, a.FromSeq, a.ToSeq, a.Shift, a.Reason)
+	}
+	if !*dryRun {
+		return 0
+	}
+
+	for i, seg := range plan.Segments {
+		if i == mergePreviewLines {
+			fmt.Fprintf(env.Stdout, "  … %d more\n", len(plan.Segments)-i)
+			break
+		}
+		marker := " "
+		if seg.FromSessionID == sourceID {
+			marker = "+"
+		}
+		fmt.Fprintf(env.Stdout, "%s %4d  %s  %s\n", marker, seg.NewSeq, seg.StartedAt.Format("15:04:05"), truncate(seg.Text, 60))
+	}
+	for _, r := range plan.Topics {
+		fmt.Fprintf(env.Stdout, "topic %q: %d-%d → %d-%d\n", r.Label, r.OldStart, r.OldEnd, r.NewStart, r.NewEnd)
+	}
+	return 0
+}
+
+func formatMerge(plan *db.MergePlan, dryRun bool) mergeOutput {
+	out := mergeOutput{
+		DryRun:          dryRun,
+		TargetID:        plan.Target.ID,
+		SourceID:        plan.Source.ID,
+		StartedAt:       plan.StartedAt.Format(time.RFC3339),
+		Segments:        len(plan.Segments),
+		Interleaved:     plan.Interleaved,
+		Topics:          formatRanges(plan.Topics),
+		Summaries:       formatRanges(plan.Summaries),
+		TimeAdjustments: make([]timeAdjustmentOutput, 0, len(plan.TimeAdjustments)),
+		Timeline:        make([]segmentRemap, 0, len(plan.Segments)),
+	}
+	for _, a := range plan.TimeAdjustments {
+		out.TimeAdjustments = append(out.TimeAdjustments, timeAdjustmentOutput{
+			SessionID:    a.SessionID,
+			FromSeq:      a.FromSeq,
+			ToSeq:        a.ToSeq,
+			ShiftSeconds: a.Shift.Seconds(),
+			Reason:       a.Reason,
+		})
+	}
+	if plan.EndedAt != nil {
+		e := plan.EndedAt.Format(time.RFC3339)
+		out.EndedAt = &e
+	}
+	for _, seg := range plan.Segments {
+		out.Timeline = append(out.Timeline, segmentRemap{
+			ID:           seg.ID,
+			From:         seg.FromSessionID,
+			OldSeq:       seg.OldSeq,
+			NewSeq:       seg.NewSeq,
+			StartedAt:    seg.StartedAt.Format(time.RFC3339),
+			ShiftSeconds: seg.Shift.Seconds(),
+		})
+	}
+	return out
+}
+
+func formatRanges(remaps []db.RangeRemap) []rangeOutput {
+	out := make([]rangeOutput, 0, len(remaps))
+	for _, r := range remaps {
+		out = append(out, rangeOutput{
+			ID: r.ID, Label: r.Label, From: r.FromSessionID,
+			OldStart: r.OldStart, OldEnd: r.OldEnd, NewStart: r.NewStart, NewEnd: r.NewEnd,
+		})
+	}
+	return out
+}
+
+// truncate shortens s to at most n runes, adding an ellipsis.
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
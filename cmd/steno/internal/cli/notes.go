@@ -0,0 +1,173 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jwulff/steno/internal/decisions"
+	"github.com/jwulff/steno/internal/export"
+)
+
+// notesOutput is the `steno notes --json` shape. Paths are relative to
+// the repository directory.
+type notesOutput struct {
+	Dir       string   `json:"dir"`
+	Sessions  int      `json:"sessions"`
+	Written   []string `json:"written"`
+	Unchanged int      `json:"unchanged"`
+	Removed   []string `json:"removed"`
+}
+
+// runNotes maintains a git-friendly directory of Markdown notes, one per
+// completed session plus an index (see export/notes.go). Files whose
+// content hasn't changed are left untouched, so a re-run in a clean
+// checkout leaves `git status` clean. With --prune, notes for sessions
+// no longer in the database (deleted, merged) are removed.
+func runNotes(env Env, args []string) int {
+	fs, jsonOut := newFlagSet(env, "notes")
+	prune := fs.Bool("prune", false, "Remove notes for sessions no longer in the database")
+	fs.Usage = func() {
+		fmt.Fprintln(env.Stderr, "Usage: steno notes [--prune] [--json] <dir>")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+	dir := fs.Arg(0)
+
+	store, err := env.openStore()
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	defer store.Close()
+
+	listed, err := store.ListSessions(0, nil, nil, "completed")
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	// Oldest first with the ID as tie-break: the index must not reorder
+	// between runs.
+	sort.Slice(listed, func(i, j int) bool {
+		a, b := listed[i].Session, listed[j].Session
+		if !a.StartedAt.Equal(b.StartedAt) {
+			return a.StartedAt.Before(b.StartedAt)
+		}
+		return a.ID < b.ID
+	})
+
+	if err := os.MkdirAll(filepath.Join(dir, export.NotesDir), 0o755); err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	out := notesOutput{Dir: dir, Sessions: len(listed), Written: []string{}, Removed: []string{}}
+	write := func(rel string, content []byte) error {
+		changed, err := writeIfChanged(filepath.Join(dir, rel), content)
+		if changed {
+			out.Written = append(out.Written, rel)
+		} else if err == nil {
+			out.Unchanged++
+		}
+		return err
+	}
+
+	keep := map[string]bool{}
+	var index []export.NotesIndexEntry
+	for _, sc := range listed {
+		sess := sc.Session
+		segs, err := store.SegmentsForSession(sess.ID, -1, 0)
+		if err != nil {
+			return fail(env, *jsonOut, err)
+		}
+		topics, err := store.TopicsForSession(sess.ID)
+		if err != nil {
+			return fail(env, *jsonOut, err)
+		}
+		recorded, err := store.DecisionsForSession(sess.ID)
+		if err != nil {
+			return fail(env, *jsonOut, err)
+		}
+		notes, err := store.SessionNotes(sess.ID)
+		if err != nil {
+			return fail(env, *jsonOut, err)
+		}
+		var b bytes.Buffer
+		if err := export.SessionNote(&b, sess, segs, topics, decisions.Sequences(recorded, segs), notes); err != nil {
+			return fail(env, *jsonOut, err)
+		}
+		name := export.NoteFilename(sess)
+		keep[name] = true
+		if err := write(filepath.Join(export.NotesDir, name), b.Bytes()); err != nil {
+			return fail(env, *jsonOut, err)
+		}
+		index = append(index, export.NotesIndexEntry{Session: sess, Segments: len(segs)})
+	}
+
+	var b bytes.Buffer
+	if err := export.NotesIndex(&b, index); err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	if err := write(export.NotesIndexFile, b.Bytes()); err != nil {
+		return fail(env, *jsonOut, err)
+	}
+
+	if *prune {
+		removed, err := pruneNotes(filepath.Join(dir, export.NotesDir), keep)
+		if err != nil {
+			return fail(env, *jsonOut, err)
+		}
+		for _, name := range removed {
+			out.Removed = append(out.Removed, filepath.Join(export.NotesDir, name))
+		}
+	}
+
+	if *jsonOut {
+		if err := writeJSON(env.Stdout, out); err != nil {
+			return fail(env, false, err)
+		}
+		return 0
+	}
+	fmt.Fprintf(env.Stderr, "%d sessions in %s: %d files written, %d unchanged, %d removed\n",
+		out.Sessions, dir, len(out.Written), out.Unchanged, len(out.Removed))
+	return 0
+}
+
+// writeIfChanged writes content to path unless the file already holds
+// exactly that, so unchanged notes keep their mtime. It reports whether
+// it wrote.
+func writeIfChanged(path string, content []byte) (bool, error) {
+	if old, err := os.ReadFile(path); err == nil && bytes.Equal(old, content) {
+		return false, nil
+	}
+	if err := os.WriteFile(path, content, 0o644); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// pruneNotes removes the .md files in dir not named in keep, returning
+// their names sorted. Other files (a .gitattributes, say) are left alone.
+func pruneNotes(dir string, keep map[string]bool) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var removed []string
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, ".md") || keep[name] {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, name)); err != nil {
+			return removed, err
+		}
+		removed = append(removed, name)
+	}
+	return removed, nil
+}
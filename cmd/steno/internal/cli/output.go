@@ -0,0 +1,32 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// errorOutput is the `--json` shape for a failed command. Scripts can
+// branch on the presence of `error` as well as on the exit code.
+type errorOutput struct {
+	Error string `json:"error"`
+}
+
+// writeJSON writes v as indented JSON followed by a newline.
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// fail reports err in the requested format and returns exit code 1. In
+// JSON mode the error object goes to stdout so a pipeline always has a
+// parseable document to read.
+func fail(env Env, jsonOut bool, err error) int {
+	if jsonOut {
+		_ = writeJSON(env.Stdout, errorOutput{Error: err.Error()})
+	} else {
+		fmt.Fprintf(env.Stderr, "steno: %v\n", err)
+	}
+	return 1
+}
@@ -0,0 +1,164 @@
+package cli
+
+import (
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jwulff/steno/internal/db"
+	"github.com/jwulff/steno/internal/quality"
+)
+
+// qualityOutput is one report in the `steno quality --json` shapes.
+type qualityOutput struct {
+	SessionID        string  `json:"session_id"`
+	Segments         int     `json:"segments"`
+	LowConfidence    int     `json:"low_confidence"`
+	LowConfidencePct float64 `json:"low_confidence_pct"`
+	Clipped          int     `json:"clipped"`
+	// Gaps and GapSeconds are measured live by the TUI; GapsMeasured is
+	// false for a session it didn't see end.
+	GapsMeasured    bool    `json:"gaps_measured"`
+	Gaps            int     `json:"gaps"`
+	GapSeconds      float64 `json:"gap_seconds"`
+	DurationSeconds float64 `json:"duration_seconds"`
+	Device          string  `json:"device,omitempty"`
+	SystemAudio     *bool   `json:"system_audio,omitempty"`
+	CreatedAt       string  `json:"created_at,omitempty"`
+}
+
+type qualityHistoryOutput struct {
+	Reports []qualityOutput `json:"reports"`
+}
+
+func newQualityOutput(q db.SessionQuality, measured bool) qualityOutput {
+	out := qualityOutput{
+		SessionID:        q.SessionID,
+		Segments:         q.Segments,
+		LowConfidence:    q.LowConfidence,
+		LowConfidencePct: q.LowConfidencePercent(),
+		Clipped:          q.Clipped,
+		GapsMeasured:     measured,
+		Gaps:             q.Gaps,
+		GapSeconds:       q.GapTime.Seconds(),
+		DurationSeconds:  q.Duration.Seconds(),
+		Device:           q.Device,
+		SystemAudio:      q.SystemAudio,
+	}
+	if measured {
+		out.CreatedAt = q.CreatedAt.UTC().Format(time.RFC3339)
+	}
+	return out
+}
+
+// runQuality shows a session's capture-quality report, or with no session
+// the reports the TUI stored as sessions ended, newest first, to spot
+// trends. A session's report is recomputed from the database, so it
+// reflects later merges; its gaps come from the stored report.
+func runQuality(env Env, args []string) int {
+	fs, jsonOut := newFlagSet(env, "quality")
+	limit := fs.Int("limit", 20, "Reports to list when no session is given (0 for all)")
+	fs.Usage = func() {
+		fmt.Fprintln(env.Stderr, "Usage: steno quality [--json] [--limit N] [session-id]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() > 1 {
+		fs.Usage()
+		return 2
+	}
+
+	store, err := env.openStore()
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	defer store.Close()
+
+	if fs.NArg() == 0 {
+		return qualityHistory(env, store, *limit, *jsonOut)
+	}
+
+	sessionID := fs.Arg(0)
+	sess, err := store.GetSession(sessionID)
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	if sess == nil {
+		return fail(env, *jsonOut, fmt.Errorf("session %s not found", sessionID))
+	}
+	stored, err := store.SessionQualityFor(sessionID)
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	report, err := quality.ForSession(store, sessionID, nil, time.Now())
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	if stored != nil {
+		report.Gaps, report.GapTime, report.CreatedAt = stored.Gaps, stored.GapTime, stored.CreatedAt
+	}
+
+	if *jsonOut {
+		if err := writeJSON(env.Stdout, newQualityOutput(report, stored != nil)); err != nil {
+			return fail(env, false, err)
+		}
+		return 0
+	}
+	tw := tabwriter.NewWriter(env.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "Session:\t%s\n", sessionID)
+	fmt.Fprintf(tw, "Segments:\t%d over %v\n", report.Segments, report.Duration.Round(time.Second))
+	fmt.Fprintf(tw, "Low confidence:\t%d (%.1f%%)\n", report.LowConfidence, report.LowConfidencePercent())
+	fmt.Fprintf(tw, "Clipped:\t%d mic segments at %.0f dBFS or louder\n", report.Clipped, quality.ClipDB)
+	if stored != nil {
+		fmt.Fprintf(tw, "Untranscribed:\t%d stretches, %v\n", report.Gaps, report.GapTime.Round(time.Second))
+	} else {
+		fmt.Fprintf(tw, "Untranscribed:\tnot measured (the TUI measures it as a session ends)\n")
+	}
+	fmt.Fprintf(tw, "Device:\t%s\n", qualityDevice(report))
+	tw.Flush()
+	return 0
+}
+
+// qualityHistory lists stored reports newest first.
+func qualityHistory(env Env, store *db.Store, limit int, jsonOut bool) int {
+	reports, err := store.SessionQualityHistory(limit)
+	if err != nil {
+		return fail(env, jsonOut, err)
+	}
+	if jsonOut {
+		out := qualityHistoryOutput{Reports: make([]qualityOutput, 0, len(reports))}
+		for _, q := range reports {
+			out.Reports = append(out.Reports, newQualityOutput(q, true))
+		}
+		if err := writeJSON(env.Stdout, out); err != nil {
+			return fail(env, false, err)
+		}
+		return 0
+	}
+	if len(reports) == 0 {
+		fmt.Fprintln(env.Stderr, "No quality reports yet. The TUI stores one each time a session ends.")
+		return 0
+	}
+	tw := tabwriter.NewWriter(env.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ENDED\tSESSION\tDEVICE\tSEGMENTS\tLOW CONF\tCLIPPED\tGAPS")
+	for _, q := range reports {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%.1f%%\t%d\t%d (%v)\n", q.CreatedAt.Local().Format("2006-01-02 15:04"),
+			q.SessionID, qualityDevice(q), q.Segments, q.LowConfidencePercent(), q.Clipped, q.Gaps, q.GapTime.Round(time.Second))
+	}
+	tw.Flush()
+	return 0
+}
+
+// qualityDevice names a report's device, noting system audio capture.
+func qualityDevice(q db.SessionQuality) string {
+	device := q.Device
+	if device == "" {
+		device = "(unknown)"
+	}
+	if q.SystemAudio != nil && *q.SystemAudio {
+		device += " + system audio"
+	}
+	return device
+}
@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/jwulff/steno/internal/db"
+)
+
+// relabelOutput is the `steno relabel --json` shape. With --undo, From
+// and To are the relabeling undone and Moved how many segments it put
+// back.
+type relabelOutput struct {
+	SessionID string `json:"session_id"`
+	DryRun    bool   `json:"dry_run,omitempty"`
+	Undo      bool   `json:"undo,omitempty"`
+	From      string `json:"from"`
+	To        string `json:"to"`
+	Moved     int    `json:"moved"`
+	Joined    int    `json:"joined,omitempty"`
+}
+
+// runRelabel reassigns every segment of a finished session from one
+// diarized speaker to another, for when the diarizer confused two
+// similar voices. --dry-run shows the counts without writing; --undo
+// takes back the latest relabeling, one at a time.
+func runRelabel(env Env, args []string) int {
+	fs, jsonOut := newFlagSet(env, "relabel")
+	dryRun := fs.Bool("dry-run", false, "Show how many segments would move without writing")
+	undo := fs.Bool("undo", false, "Undo the session's latest relabeling")
+	fs.Usage = func() {
+		fmt.Fprintln(env.Stderr, "Usage: steno relabel [--dry-run] [--json] <session-id> <from-label> <to-label>")
+		fmt.Fprintln(env.Stderr, "       steno relabel --undo [--json] <session-id>")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if (*undo && (fs.NArg() != 1 || *dryRun)) || (!*undo && fs.NArg() != 3) {
+		fs.Usage()
+		return 2
+	}
+	sessionID := fs.Arg(0)
+
+	store, err := env.openMaintenanceStore()
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	defer store.Close()
+
+	var out relabelOutput
+	if *undo {
+		r, err := store.UndoRelabel(sessionID)
+		if err != nil {
+			return fail(env, *jsonOut, err)
+		}
+		if r == nil {
+			return fail(env, *jsonOut, fmt.Errorf("session %s has no relabeling to undo", sessionID))
+		}
+		out = relabelOutput{SessionID: sessionID, Undo: true, From: r.From, To: r.To, Moved: len(r.SegmentIDs)}
+	} else {
+		var plan *db.RelabelPlan
+		if *dryRun {
+			plan, err = store.PlanRelabel(sessionID, fs.Arg(1), fs.Arg(2))
+		} else {
+			plan, err = store.RelabelSpeaker(sessionID, fs.Arg(1), fs.Arg(2))
+		}
+		if err != nil {
+			return fail(env, *jsonOut, err)
+		}
+		out = relabelOutput{SessionID: sessionID, DryRun: *dryRun, From: plan.From, To: plan.To, Moved: plan.Moved, Joined: plan.Joined}
+	}
+
+	if *jsonOut {
+		if err := writeJSON(env.Stdout, out); err != nil {
+			return fail(env, false, err)
+		}
+		return 0
+	}
+	switch {
+	case out.Undo:
+		fmt.Fprintf(env.Stdout, "Put %d segments back from %s to %s\n", out.Moved, out.To, out.From)
+	case out.DryRun:
+		fmt.Fprintf(env.Stdout, "Would move %d segments from %s to %s, joining its %d\n", out.Moved, out.From, out.To, out.Joined)
+	default:
+		fmt.Fprintf(env.Stdout, "Moved %d segments from %s to %s (undo with steno relabel --undo %s)\n", out.Moved, out.From, out.To, sessionID)
+	}
+	return 0
+}
@@ -0,0 +1,225 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jwulff/steno/internal/db"
+	"github.com/jwulff/steno/internal/export"
+)
+
+// savedSearchOutput is one entry of the `steno searches --json` shape.
+type savedSearchOutput struct {
+	Name          string   `json:"name"`
+	Text          string   `json:"text"`
+	Sources       []string `json:"sources,omitempty"`
+	Speakers      []string `json:"speakers,omitempty"`
+	MinConfidence float64  `json:"min_confidence,omitempty"`
+	Scope         string   `json:"scope"`
+	Alert         bool     `json:"alert"`
+	CreatedAt     string   `json:"created_at"`
+}
+
+type savedSearchesOutput struct {
+	Searches []savedSearchOutput `json:"searches"`
+}
+
+// searchMatchOutput is one segment of the `steno searches --run --json`
+// shape.
+type searchMatchOutput struct {
+	SessionID  string `json:"session_id"`
+	SegmentSeq int    `json:"segment_seq"`
+	Source     string `json:"source"`
+	Text       string `json:"text"`
+	StartedAt  string `json:"started_at"`
+	Permalink  string `json:"permalink"`
+}
+
+type searchRunOutput struct {
+	Search  string              `json:"search"`
+	Total   int                 `json:"total"`
+	Matches []searchMatchOutput `json:"matches"`
+}
+
+// runSearches manages saved searches: named queries with filters and a
+// scope, kept in the database. With no action it lists them. The TUI
+// lists them under `/`, and runs the --alert ones against each new
+// segment as keyword alerts.
+func runSearches(env Env, args []string) int {
+	fs, jsonOut := newFlagSet(env, "searches")
+	save := fs.String("save", "", "Save the search TEXT under this name, replacing any search of that name")
+	del := fs.String("delete", "", "Delete the saved search with this name")
+	run := fs.String("run", "", "Run the saved search with this name, newest matches first")
+	source := fs.String("source", "", "With --save: only segments from this source (microphone or systemAudio)")
+	speaker := fs.String("speaker", "", "With --save: only segments from this diarized speaker label")
+	minConfidence := fs.Float64("min-confidence", 0, "With --save: drop segments below this confidence")
+	scope := fs.String("scope", db.ScopeAll, "With --save: all, session (the current session), or a duration such as 168h")
+	alert := fs.Bool("alert", false, "With --save: alert in the TUI when a new segment matches")
+	session := fs.String("session", "", "With --run: the session a session-scoped search runs against (default the latest)")
+	limit := fs.Int("limit", 50, "With --run: maximum number of matches to list")
+	fs.Usage = func() {
+		fmt.Fprintln(env.Stderr, "Usage: steno searches [--json] [--save NAME [options] TEXT | --delete NAME | --run NAME]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	actions := 0
+	for _, a := range []string{*save, *del, *run} {
+		if a != "" {
+			actions++
+		}
+	}
+	if actions > 1 || (*save == "") != (fs.NArg() == 0) {
+		fs.Usage()
+		return 2
+	}
+
+	var store *db.Store
+	var err error
+	if *save != "" || *del != "" {
+		store, err = env.openClientStore()
+	} else {
+		store, err = env.openStore()
+	}
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	defer store.Close()
+
+	switch {
+	case *save != "":
+		ss := db.SavedSearch{
+			Name:          *save,
+			Text:          strings.Join(fs.Args(), " "),
+			MinConfidence: *minConfidence,
+			Scope:         *scope,
+			Alert:         *alert,
+		}
+		if *source != "" {
+			ss.Sources = []string{*source}
+		}
+		if *speaker != "" {
+			ss.Speakers = []string{*speaker}
+		}
+		if err := store.SaveSearch(ss); err != nil {
+			return fail(env, *jsonOut, err)
+		}
+	case *del != "":
+		ok, err := store.DeleteSavedSearch(*del)
+		if err != nil {
+			return fail(env, *jsonOut, err)
+		}
+		if !ok {
+			return fail(env, *jsonOut, fmt.Errorf("no saved search named %q", *del))
+		}
+	case *run != "":
+		return runSavedSearch(env, store, *run, *session, *limit, *jsonOut)
+	}
+
+	searches, err := store.SavedSearches()
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	if *jsonOut {
+		out := savedSearchesOutput{Searches: make([]savedSearchOutput, 0, len(searches))}
+		for _, ss := range searches {
+			out.Searches = append(out.Searches, savedSearchOutput{
+				Name:          ss.Name,
+				Text:          ss.Text,
+				Sources:       ss.Sources,
+				Speakers:      ss.Speakers,
+				MinConfidence: ss.MinConfidence,
+				Scope:         ss.Scope,
+				Alert:         ss.Alert,
+				CreatedAt:     ss.CreatedAt.UTC().Format(time.RFC3339),
+			})
+		}
+		if err := writeJSON(env.Stdout, out); err != nil {
+			return fail(env, false, err)
+		}
+		return 0
+	}
+	if len(searches) == 0 {
+		fmt.Fprintln(env.Stderr, "No saved searches")
+		return 0
+	}
+	tw := tabwriter.NewWriter(env.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tQUERY\tSCOPE\tALERT")
+	for _, ss := range searches {
+		alert := ""
+		if ss.Alert {
+			alert = "yes"
+		}
+		fmt.Fprintf(tw, "%s\t%q\t%s\t%s\n", ss.Name, ss.Text, ss.Scope, alert)
+	}
+	tw.Flush()
+	return 0
+}
+
+// runSavedSearch prints the newest matches of the saved search name.
+func runSavedSearch(env Env, store *db.Store, name, sessionID string, limit int, jsonOut bool) int {
+	ss, err := store.SavedSearch(name)
+	if err != nil {
+		return fail(env, jsonOut, err)
+	}
+	if ss == nil {
+		return fail(env, jsonOut, fmt.Errorf("no saved search named %q", name))
+	}
+	if ss.Scope == db.ScopeSession && sessionID == "" {
+		latest, err := store.ListSessions(1, nil, nil, "")
+		if err != nil {
+			return fail(env, jsonOut, err)
+		}
+		if len(latest) > 0 {
+			sessionID = latest[0].Session.ID
+		}
+	}
+	q, err := ss.Query(sessionID, time.Now())
+	if err != nil {
+		return fail(env, jsonOut, err)
+	}
+	total, err := store.CountSegments(q)
+	if err != nil {
+		return fail(env, jsonOut, err)
+	}
+	if limit <= 0 {
+		limit = 50
+	}
+	q.Limit = limit
+	segs, err := store.QuerySegments(q)
+	if err != nil {
+		return fail(env, jsonOut, err)
+	}
+
+	if jsonOut {
+		out := searchRunOutput{Search: ss.Name, Total: total, Matches: make([]searchMatchOutput, 0, len(segs))}
+		for _, s := range segs {
+			out.Matches = append(out.Matches, searchMatchOutput{
+				SessionID:  s.SessionID,
+				SegmentSeq: s.SequenceNumber,
+				Source:     s.Source,
+				Text:       s.Text,
+				StartedAt:  s.StartedAt.UTC().Format(time.RFC3339),
+				Permalink:  export.SegmentPermalink(s.SessionID, s.SequenceNumber),
+			})
+		}
+		if err := writeJSON(env.Stdout, out); err != nil {
+			return fail(env, false, err)
+		}
+		return 0
+	}
+	if total == 0 {
+		fmt.Fprintf(env.Stderr, "No segments match %q\n", ss.Name)
+		return 0
+	}
+	for _, s := range segs {
+		fmt.Fprintf(env.Stdout, "%s #%d [%s] %s\n", s.SessionID, s.SequenceNumber, s.StartedAt.Local().Format("2006-01-02 15:04"), s.Text)
+	}
+	if total > len(segs) {
+		fmt.Fprintf(env.Stderr, "%d of %d matches shown\n", len(segs), total)
+	}
+	return 0
+}
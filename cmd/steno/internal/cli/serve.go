@@ -0,0 +1,132 @@
+package cli
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/jwulff/steno/internal/config"
+	"github.com/jwulff/steno/internal/daemon"
+	"github.com/jwulff/steno/internal/remote"
+	"github.com/jwulff/steno/internal/web"
+)
+
+// defaultServeAddr binds loopback only. Sharing with teammates on the LAN
+// is an explicit opt-in (--addr :8765), since transcripts are private.
+const defaultServeAddr = "127.0.0.1:8765"
+
+// defaultControlAddr is where `steno serve --control` listens, loopback
+// only for the same reason.
+const defaultControlAddr = "127.0.0.1:8766"
+
+// controlTokenEnv names the variable holding the control endpoint's
+// token. Without it a fresh token is made and printed at startup.
+const controlTokenEnv = "STENO_CONTROL_TOKEN"
+
+// runServe starts the read-only web viewer, or the control endpoint, and
+// blocks until interrupted.
+func runServe(env Env, args []string) int {
+	fs, jsonOut := newFlagSet(env, "serve")
+	webMode := fs.Bool("web", false, "Serve the read-only HTML session viewer")
+	controlMode := fs.Bool("control", false, "Serve the token-protected start/stop/mark/note endpoint (token from $"+controlTokenEnv+")")
+	addr := fs.String("addr", "", "Listen address (default "+defaultServeAddr+", or "+defaultControlAddr+" with --control; use :8765 to share on the LAN)")
+	scrubMode := fs.Bool("scrub", false, "Mask sensitive text, as `steno --scrub` does in the TUI")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *webMode == *controlMode {
+		fmt.Fprintln(env.Stderr, "Usage: steno serve --web [--addr host:port] [--scrub]")
+		fmt.Fprintln(env.Stderr, "       steno serve --control [--addr host:port]")
+		return 2
+	}
+
+	cfg, err := config.Load(config.Path())
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	if *controlMode {
+		if *addr == "" {
+			*addr = defaultControlAddr
+		}
+		return serveControl(env, *jsonOut, *addr)
+	}
+	if *addr == "" {
+		*addr = defaultServeAddr
+	}
+	if *scrubMode {
+		cfg.Scrub.Enabled = true
+	}
+	scrubber, err := cfg.Scrub.Scrubber()
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+
+	store, err := env.openStore()
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	defer store.Close()
+
+	ln, err := net.Listen("tcp", *addr)
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	fmt.Fprintf(env.Stdout, "Serving steno web viewer at http://%s/ (Ctrl-C to stop)\n", ln.Addr())
+	return serveHTTP(env, *jsonOut, ln, web.New(store, scrubber))
+}
+
+// serveControl runs the control endpoint on addr until interrupted.
+func serveControl(env Env, jsonOut bool, addr string) int {
+	token := os.Getenv(controlTokenEnv)
+	if token == "" {
+		b := make([]byte, 16)
+		if _, err := rand.Read(b); err != nil {
+			return fail(env, jsonOut, err)
+		}
+		token = hex.EncodeToString(b)
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fail(env, jsonOut, err)
+	}
+	h := remote.New(env.socketPath(), env.openClientStore, token, daemon.StartOptions{})
+	fmt.Fprintf(env.Stdout, "Serving steno control endpoint at http://%s/ (Ctrl-C to stop)\n", ln.Addr())
+	if os.Getenv(controlTokenEnv) == "" {
+		fmt.Fprintf(env.Stdout, "Token: %s (set $%s to keep one across restarts)\n", token, controlTokenEnv)
+	}
+	return serveHTTP(env, jsonOut, ln, h)
+}
+
+// serveHTTP serves h on ln until the server fails or the process is
+// interrupted.
+func serveHTTP(env Env, jsonOut bool, ln net.Listener, h http.Handler) int {
+	srv := &http.Server{
+		Handler:           h,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	errc := make(chan error, 1)
+	go func() { errc <- srv.Serve(ln) }()
+
+	select {
+	case err := <-errc:
+		if !errors.Is(err, http.ErrServerClosed) {
+			return fail(env, jsonOut, err)
+		}
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdownCtx)
+	}
+	return 0
+}
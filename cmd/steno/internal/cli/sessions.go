@@ -0,0 +1,149 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jwulff/steno/internal/db"
+)
+
+// sessionsOutput is the `steno sessions --json` shape.
+type sessionsOutput struct {
+	Sessions []sessionOutput `json:"sessions"`
+}
+
+type sessionOutput struct {
+	ID           string  `json:"id"`
+	Title        string  `json:"title,omitempty"`
+	Status       string  `json:"status"`
+	Locale       string  `json:"locale"`
+	Device       string  `json:"device,omitempty"`
+	SystemAudio  *bool   `json:"system_audio,omitempty"`
+	StartedAt    string  `json:"started_at"`
+	EndedAt      *string `json:"ended_at,omitempty"`
+	SegmentCount int     `json:"segment_count"`
+	TopicCount   int     `json:"topic_count"`
+	SummaryCount int     `json:"summary_count"`
+	// ForegroundApp and MeetingURL are optional session context.
+	ForegroundApp string `json:"foreground_app,omitempty"`
+	MeetingURL    string `json:"meeting_url,omitempty"`
+}
+
+// runSessions lists sessions newest first, mirroring the MCP
+// `list_sessions` filters plus the client-recorded device metadata.
+func runSessions(env Env, args []string) int {
+	fs, jsonOut := newFlagSet(env, "sessions")
+	limit := fs.Int("limit", 20, "Maximum number of sessions to list")
+	status := fs.String("status", "", "Filter by status (active, completed, interrupted)")
+	after := fs.String("after", "", "Only sessions started after this time (RFC3339)")
+	before := fs.String("before", "", "Only sessions started before this time (RFC3339)")
+	locale := fs.String("locale", "", "Filter by locale (e.g. en_US)")
+	device := fs.String("device", "", "Filter by recording input device")
+	sysAudio := fs.String("system-audio", "", "Filter by system-audio capture (true or false)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	afterT, err := parseOptionalTime(*after)
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	beforeT, err := parseOptionalTime(*before)
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	if *limit <= 0 {
+		*limit = 20
+	}
+	var sysAudioFilter *bool
+	if *sysAudio != "" {
+		v, err := strconv.ParseBool(*sysAudio)
+		if err != nil {
+			return fail(env, *jsonOut, fmt.Errorf("invalid --system-audio %q: expected true or false", *sysAudio))
+		}
+		sysAudioFilter = &v
+	}
+
+	store, err := env.openStore()
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	defer store.Close()
+
+	sessions, err := store.FilterSessions(db.SessionFilter{
+		Limit:       *limit,
+		Before:      beforeT,
+		After:       afterT,
+		Status:      *status,
+		Locale:      *locale,
+		Device:      *device,
+		SystemAudio: sysAudioFilter,
+	})
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+
+	out := sessionsOutput{Sessions: make([]sessionOutput, 0, len(sessions))}
+	for _, swc := range sessions {
+		out.Sessions = append(out.Sessions, formatSession(swc))
+	}
+
+	if *jsonOut {
+		if err := writeJSON(env.Stdout, out); err != nil {
+			return fail(env, false, err)
+		}
+		return 0
+	}
+
+	if len(out.Sessions) == 0 {
+		fmt.Fprintln(env.Stdout, "No sessions found.")
+		return 0
+	}
+	tw := tabwriter.NewWriter(env.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tSTARTED\tSTATUS\tSEGMENTS\tDEVICE\tTITLE")
+	for _, swc := range sessions {
+		s := swc.Session
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%s\t%s\n",
+			s.ID, s.StartedAt.Format("2006-01-02 15:04"), s.Status, swc.Counts.Segments, s.Device, s.Title)
+	}
+	tw.Flush()
+	return 0
+}
+
+func formatSession(swc db.SessionWithCounts) sessionOutput {
+	s := swc.Session
+	out := sessionOutput{
+		ID:           s.ID,
+		Title:        s.Title,
+		Status:       s.Status,
+		Locale:       s.Locale,
+		Device:       s.Device,
+		SystemAudio:  s.SystemAudio,
+		StartedAt:    s.StartedAt.Format(time.RFC3339),
+		SegmentCount: swc.Counts.Segments,
+		TopicCount:   swc.Counts.Topics,
+		SummaryCount: swc.Counts.Summaries,
+
+		ForegroundApp: s.ForegroundApp,
+		MeetingURL:    s.MeetingURL,
+	}
+	if s.EndedAt != nil {
+		e := s.EndedAt.Format(time.RFC3339)
+		out.EndedAt = &e
+	}
+	return out
+}
+
+// parseOptionalTime parses an RFC3339 flag value, or returns nil when empty.
+func parseOptionalTime(s string) (*time.Time, error) {
+	if s == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timestamp %q: expected RFC3339", s)
+	}
+	return &t, nil
+}
@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/jwulff/steno/internal/db"
+)
+
+// speakersOutput is the `steno speakers --json` shape.
+type speakersOutput struct {
+	SessionID string            `json:"session_id"`
+	Speakers  map[string]string `json:"speakers"`
+}
+
+// runSpeakers lists a session's speaker names, or sets them from
+// LABEL=NAME arguments ("Speaker 1=Ana"). An empty NAME removes the
+// mapping. The TUI offers these names to the next session of the same
+// meeting.
+func runSpeakers(env Env, args []string) int {
+	fs, jsonOut := newFlagSet(env, "speakers")
+	fs.Usage = func() {
+		fmt.Fprintln(env.Stderr, "Usage: steno speakers [--json] <session-id> [LABEL=NAME ...]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() < 1 {
+		fs.Usage()
+		return 2
+	}
+	sessionID, assignments := fs.Arg(0), fs.Args()[1:]
+	type assignment struct{ label, name string }
+	var set []assignment
+	for _, a := range assignments {
+		label, name, ok := strings.Cut(a, "=")
+		if !ok || strings.TrimSpace(label) == "" {
+			return fail(env, *jsonOut, fmt.Errorf("invalid speaker %q: want LABEL=NAME", a))
+		}
+		set = append(set, assignment{label, name})
+	}
+
+	var store *db.Store
+	var err error
+	if len(set) > 0 {
+		store, err = env.openClientStore()
+	} else {
+		store, err = env.openStore()
+	}
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	defer store.Close()
+
+	sess, err := store.GetSession(sessionID)
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	if sess == nil {
+		return fail(env, *jsonOut, fmt.Errorf("session %s not found", sessionID))
+	}
+	for _, a := range set {
+		if err := store.SetSpeakerName(sessionID, a.label, a.name); err != nil {
+			return fail(env, *jsonOut, err)
+		}
+	}
+	names, err := store.SpeakerNames(sessionID)
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	if names == nil {
+		names = map[string]string{}
+	}
+
+	if *jsonOut {
+		if err := writeJSON(env.Stdout, speakersOutput{SessionID: sessionID, Speakers: names}); err != nil {
+			return fail(env, false, err)
+		}
+		return 0
+	}
+	if len(names) == 0 {
+		fmt.Fprintf(env.Stderr, "No speaker names for %s\n", sessionID)
+		return 0
+	}
+	labels := make([]string, 0, len(names))
+	for label := range names {
+		labels = append(labels, label)
+	}
+	slices.Sort(labels)
+	for _, label := range labels {
+		fmt.Fprintf(env.Stdout, "%s\t%s\n", label, names[label])
+	}
+	return 0
+}
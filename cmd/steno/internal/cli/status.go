@@ -0,0 +1,169 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jwulff/steno/internal/daemon"
+)
+
+// statusOutput is the `steno status --json` shape.
+type statusOutput struct {
+	Running            bool    `json:"running"`
+	Recording          bool    `json:"recording"`
+	Status             string  `json:"status,omitempty"`
+	SessionID          string  `json:"session_id,omitempty"`
+	Device             string  `json:"device,omitempty"`
+	CallApp            string  `json:"call_app,omitempty"`
+	SystemAudio        bool    `json:"system_audio"`
+	Segments           *int    `json:"segments,omitempty"`
+	LastSegmentAt      *string `json:"last_segment_at,omitempty"`
+	Paused             bool    `json:"paused"`
+	PausedIndefinitely bool    `json:"paused_indefinitely"`
+	PauseExpiresAt     *string `json:"pause_expires_at,omitempty"`
+	ThermalState       string  `json:"thermal_state,omitempty"`
+	LowPower           bool    `json:"low_power"`
+	SummariesDeferred  bool    `json:"summaries_deferred"`
+}
+
+// runStatus queries the daemon's `status` command. It never auto-starts
+// the daemon: a stopped daemon is reported as `running: false` with exit
+// code 1 so `steno status && ...` works as a liveness check.
+func runStatus(env Env, args []string) int {
+	fs, jsonOut := newFlagSet(env, "status")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	client, err := daemon.Connect(env.socketPath())
+	if err != nil {
+		out := statusOutput{Running: false}
+		if *jsonOut {
+			_ = writeJSON(env.Stdout, out)
+		} else {
+			fmt.Fprintln(env.Stdout, "daemon: not running")
+		}
+		return 1
+	}
+	defer client.Close()
+
+	resp, err := client.SendCommand(daemon.Command{Cmd: "status"})
+	if err != nil {
+		return fail(env, *jsonOut, err)
+	}
+	if !resp.OK {
+		return fail(env, *jsonOut, fmt.Errorf("status: %s", resp.Error))
+	}
+
+	out := statusFromResponse(resp)
+	if *jsonOut {
+		if err := writeJSON(env.Stdout, out); err != nil {
+			return fail(env, false, err)
+		}
+		return 0
+	}
+
+	fmt.Fprintln(env.Stdout, "daemon:    running")
+	fmt.Fprintf(env.Stdout, "status:    %s\n", valueOr(out.Status, "unknown"))
+	fmt.Fprintf(env.Stdout, "recording: %t\n", out.Recording)
+	if out.SessionID != "" {
+		fmt.Fprintf(env.Stdout, "session:   %s\n", out.SessionID)
+	}
+	if out.Device != "" {
+		fmt.Fprintf(env.Stdout, "device:    %s\n", out.Device)
+	}
+	if out.CallApp != "" {
+		fmt.Fprintf(env.Stdout, "call:      %s\n", out.CallApp)
+	}
+	fmt.Fprintf(env.Stdout, "sysaudio:  %t\n", out.SystemAudio)
+	if out.Segments != nil {
+		fmt.Fprintf(env.Stdout, "segments:  %d\n", *out.Segments)
+	}
+	if out.LastSegmentAt != nil {
+		fmt.Fprintf(env.Stdout, "last seg:  %s\n", *out.LastSegmentAt)
+	}
+	if pressure := pressureLine(out); pressure != "" {
+		fmt.Fprintf(env.Stdout, "pressure:  %s\n", pressure)
+	}
+	if out.Paused {
+		switch {
+		case out.PausedIndefinitely:
+			fmt.Fprintln(env.Stdout, "paused:    indefinitely")
+		case out.PauseExpiresAt != nil:
+			fmt.Fprintf(env.Stdout, "paused:    until %s\n", *out.PauseExpiresAt)
+		default:
+			fmt.Fprintln(env.Stdout, "paused:    yes")
+		}
+	}
+	return 0
+}
+
+// statusFromResponse flattens the pointer-heavy wire Response into the
+// stable CLI shape.
+func statusFromResponse(resp daemon.Response) statusOutput {
+	out := statusOutput{
+		Running:      true,
+		Status:       resp.Status,
+		SessionID:    resp.SessionID,
+		Device:       resp.Device,
+		CallApp:      resp.CallApp,
+		Segments:     resp.Segments,
+		ThermalState: resp.ThermalState,
+	}
+	if resp.Recording != nil {
+		out.Recording = *resp.Recording
+	}
+	if resp.SystemAudio != nil {
+		out.SystemAudio = *resp.SystemAudio
+	}
+	if resp.Paused != nil {
+		out.Paused = *resp.Paused
+	}
+	if resp.PausedIndefinitely != nil {
+		out.PausedIndefinitely = *resp.PausedIndefinitely
+	}
+	if resp.PauseExpiresAt != nil {
+		ts := unixToRFC3339(*resp.PauseExpiresAt)
+		out.PauseExpiresAt = &ts
+	}
+	if resp.LastSegmentAt != nil {
+		ts := unixToRFC3339(*resp.LastSegmentAt)
+		out.LastSegmentAt = &ts
+	}
+	if resp.LowPower != nil {
+		out.LowPower = *resp.LowPower
+	}
+	if resp.SummariesDeferred != nil {
+		out.SummariesDeferred = *resp.SummariesDeferred
+	}
+	return out
+}
+
+// pressureLine describes the daemon's resource pressure, or is empty
+// when the daemon doesn't report it.
+func pressureLine(out statusOutput) string {
+	if out.ThermalState == "" {
+		return ""
+	}
+	line := "thermal " + out.ThermalState
+	if out.LowPower {
+		line += ", low power"
+	}
+	if out.SummariesDeferred {
+		line += ", summaries deferred"
+	}
+	return line
+}
+
+func unixToRFC3339(ts float64) string {
+	sec := int64(ts)
+	nsec := int64((ts - float64(sec)) * 1e9)
+	return time.Unix(sec, nsec).Format(time.RFC3339)
+}
+
+func valueOr(s, fallback string) string {
+	if s == "" {
+		return fallback
+	}
+	return s
+}
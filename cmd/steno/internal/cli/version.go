@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/jwulff/steno/internal/daemon"
+	"github.com/jwulff/steno/internal/version"
+)
+
+// versionOutput is the `steno version --json` shape.
+type versionOutput struct {
+	Version       string `json:"version"`
+	Build         string `json:"build"`
+	DaemonRunning bool   `json:"daemon_running"`
+	DaemonVersion string `json:"daemon_version,omitempty"`
+	DaemonBuild   string `json:"daemon_build,omitempty"`
+	Skew          bool   `json:"skew"`
+	// Protocol and DaemonProtocol are the protocol revisions each side
+	// speaks (daemon.ProtocolVersion); ProtocolSkew flags a difference.
+	Protocol       int  `json:"protocol"`
+	DaemonProtocol int  `json:"daemon_protocol,omitempty"`
+	ProtocolSkew   bool `json:"protocol_skew"`
+}
+
+// queryVersions reports this binary's version and, if the daemon is
+// reachable, its version. Shared with the bug-report bundle, which passes
+// log to capture the exchange; log may be nil.
+func queryVersions(env Env, log *daemon.MessageLog) versionOutput {
+	out := versionOutput{Version: version.Version, Build: version.Build(), Protocol: daemon.ProtocolVersion}
+	client, err := daemon.Connect(env.socketPath())
+	if err != nil {
+		return out
+	}
+	defer client.Close()
+	client.SetMessageLog(log)
+	out.DaemonRunning = true
+	resp, err := client.SendCommand(daemon.VersionCmd())
+	if err == nil && resp.OK {
+		out.DaemonVersion = resp.Version
+		out.DaemonBuild = resp.Build
+		out.Skew = version.MinorSkew(out.Version, out.DaemonVersion)
+		out.DaemonProtocol = resp.ProtocolVersion
+		out.ProtocolSkew = version.ProtocolSkew(out.Protocol, out.DaemonProtocol)
+	}
+	return out
+}
+
+// runVersion prints the steno and daemon versions. Exit code 1 flags a
+// major/minor or protocol skew so scripts can catch a half-upgraded
+// install.
+func runVersion(env Env, args []string) int {
+	fs, jsonOut := newFlagSet(env, "version")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	out := queryVersions(env, nil)
+	code := 0
+	if out.Skew || out.ProtocolSkew {
+		code = 1
+	}
+	if *jsonOut {
+		if err := writeJSON(env.Stdout, out); err != nil {
+			return fail(env, false, err)
+		}
+		return code
+	}
+
+	fmt.Fprintf(env.Stdout, "steno:        %s (%s)\n", out.Version, out.Build)
+	switch {
+	case !out.DaemonRunning:
+		fmt.Fprintln(env.Stdout, "steno-daemon: not running")
+	case out.DaemonVersion == "":
+		fmt.Fprintln(env.Stdout, "steno-daemon: unknown (predates the version command)")
+	default:
+		fmt.Fprintf(env.Stdout, "steno-daemon: %s (%s)\n", out.DaemonVersion, valueOr(out.DaemonBuild, "unknown build"))
+	}
+	if out.DaemonProtocol != 0 {
+		fmt.Fprintf(env.Stdout, "protocol:     %d (daemon %d)\n", out.Protocol, out.DaemonProtocol)
+	}
+	if out.Skew {
+		fmt.Fprintf(env.Stderr, "warning: %s\n", version.SkewWarning(out.Version, out.DaemonVersion))
+	}
+	if out.ProtocolSkew {
+		fmt.Fprintf(env.Stderr, "warning: %s\n", version.ProtocolWarning(out.Protocol, out.DaemonProtocol))
+	}
+	return code
+}
@@ -0,0 +1,154 @@
+// Package coding counts a researcher's codebook in transcript text, for
+// live qualitative coding of interviews: each code is a list of terms,
+// and every mention of one of them counts toward the code. The TUI's C
+// view shows the running counts, and `steno codes` exports them as a CSV
+// time series. Codes are configured (coding.codes), not learned.
+package coding
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jwulff/steno/internal/db"
+)
+
+// Code is one code of a codebook. A term matches as whole words,
+// ignoring case and spacing; a trailing * matches any word ending, so
+// "trust*" also counts "trusted" and "trusting".
+type Code struct {
+	Name  string
+	Terms []string
+}
+
+// Codebook matches a set of codes.
+type Codebook struct {
+	names []string
+	res   []*regexp.Regexp
+}
+
+// Compile builds a codebook from codes, in their order.
+func Compile(codes []Code) (*Codebook, error) {
+	b := &Codebook{}
+	for _, c := range codes {
+		re, err := termsPattern(c.Terms)
+		if err != nil {
+			return nil, fmt.Errorf("code %q: %w", c.Name, err)
+		}
+		b.names = append(b.names, c.Name)
+		b.res = append(b.res, re)
+	}
+	return b, nil
+}
+
+// termsPattern matches any of terms, longest first so a longer phrase
+// wins over a term it starts with.
+func termsPattern(terms []string) (*regexp.Regexp, error) {
+	var alts []string
+	for _, t := range terms {
+		stem := strings.HasSuffix(t, "*")
+		words := strings.Fields(strings.TrimSuffix(t, "*"))
+		if len(words) == 0 {
+			return nil, fmt.Errorf("empty term")
+		}
+		for i, w := range words {
+			words[i] = regexp.QuoteMeta(w)
+		}
+		alt := strings.Join(words, `\s+`)
+		if stem {
+			alt += `\w*`
+		}
+		alts = append(alts, alt)
+	}
+	sort.SliceStable(alts, func(i, j int) bool { return len(alts[i]) > len(alts[j]) })
+	return regexp.Compile(`(?i)\b(?:` + strings.Join(alts, "|") + `)\b`)
+}
+
+// Names returns the codes' names, in codebook order.
+func (b *Codebook) Names() []string {
+	if b == nil {
+		return nil
+	}
+	return b.names
+}
+
+// Pattern returns the expression matching code i's terms, for filtering
+// the transcript by it.
+func (b *Codebook) Pattern(i int) *regexp.Regexp {
+	return b.res[i]
+}
+
+// Count returns how often each code is mentioned in text, in codebook
+// order.
+func (b *Codebook) Count(text string) []int {
+	counts := make([]int, len(b.Names()))
+	for i := range counts {
+		counts[i] = len(b.res[i].FindAllStringIndex(text, -1))
+	}
+	return counts
+}
+
+// Series is a codebook's counts over a session, in fixed-length
+// intervals from its first segment.
+type Series struct {
+	Codes    []string
+	Start    time.Time
+	Interval time.Duration
+	// Counts[i][c] is how often code c came up in interval i.
+	Counts [][]int
+}
+
+// Series counts the codes in segments by interval. Intervals without
+// mentions are kept, so the series reads as a timeline.
+func (b *Codebook) Series(segments []db.Segment, interval time.Duration) Series {
+	s := Series{Codes: b.Names(), Interval: interval}
+	for _, seg := range segments {
+		if s.Start.IsZero() || seg.StartedAt.Before(s.Start) {
+			s.Start = seg.StartedAt
+		}
+	}
+	for _, seg := range segments {
+		i := int(seg.StartedAt.Sub(s.Start) / interval)
+		for len(s.Counts) <= i {
+			s.Counts = append(s.Counts, make([]int, len(s.Codes)))
+		}
+		for c, n := range b.Count(seg.Text) {
+			s.Counts[i][c] += n
+		}
+	}
+	return s
+}
+
+// Totals returns each code's count over the whole series.
+func (s Series) Totals() []int {
+	totals := make([]int, len(s.Codes))
+	for _, row := range s.Counts {
+		for c, n := range row {
+			totals[c] += n
+		}
+	}
+	return totals
+}
+
+// sparks are the sparkline's levels, from no mentions to the most.
+var sparks = []rune(" ▁▂▃▄▅▆▇█")
+
+// Sparkline draws code c's counts over the last n intervals, one
+// character each, scaled to its busiest interval.
+func (s Series) Sparkline(c, n int) string {
+	counts := s.Counts[max(0, len(s.Counts)-n):]
+	peak := 0
+	for _, row := range counts {
+		peak = max(peak, row[c])
+	}
+	out := make([]rune, len(counts))
+	for i, row := range counts {
+		out[i] = sparks[0]
+		if peak > 0 && row[c] > 0 {
+			out[i] = sparks[max(1, row[c]*(len(sparks)-1)/peak)]
+		}
+	}
+	return string(out)
+}
@@ -0,0 +1,60 @@
+package coding
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jwulff/steno/internal/db"
+)
+
+func TestCount(t *testing.T) {
+	b, err := Compile([]Code{
+		{Name: "trust", Terms: []string{"trust*", "rely on"}},
+		{Name: "cost", Terms: []string{"price", "too expensive"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		text string
+		want []int
+	}{
+		{"I trusted them, trust is everything, and I RELY  ON them.", []int{3, 0}},
+		{"The price was too  expensive for us.", []int{0, 2}},
+		{"Entrust isn't trust-worthy; the prices are reliable.", []int{1, 0}},
+		{"", []int{0, 0}},
+	} {
+		if got := b.Count(tc.text); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("Count(%q) = %v, want %v", tc.text, got, tc.want)
+		}
+	}
+	if _, err := Compile([]Code{{Name: "empty", Terms: []string{"*"}}}); err == nil {
+		t.Error("an empty term should be rejected")
+	}
+}
+
+func TestSeries(t *testing.T) {
+	b, _ := Compile([]Code{{Name: "trust", Terms: []string{"trust"}}, {Name: "cost", Terms: []string{"price"}}})
+	start := time.Unix(1700000000, 0)
+	s := b.Series([]db.Segment{
+		{StartedAt: start.Add(10 * time.Second), Text: "trust and price"},
+		{StartedAt: start, Text: "trust"},
+		{StartedAt: start.Add(150 * time.Second), Text: "the price, the price"},
+	}, time.Minute)
+	if !s.Start.Equal(start) || s.Interval != time.Minute {
+		t.Errorf("series starts %v every %v", s.Start, s.Interval)
+	}
+	if want := [][]int{{2, 1}, {0, 0}, {0, 2}}; !reflect.DeepEqual(s.Counts, want) {
+		t.Errorf("Counts = %v, want %v", s.Counts, want)
+	}
+	if got := s.Totals(); !reflect.DeepEqual(got, []int{2, 3}) {
+		t.Errorf("Totals = %v", got)
+	}
+	if got := s.Sparkline(1, 10); got != "▄ █" {
+		t.Errorf("Sparkline(cost) = %q", got)
+	}
+	if got := s.Sparkline(0, 2); got != "  " {
+		t.Errorf("Sparkline(trust, 2) = %q, want the last two intervals", got)
+	}
+}
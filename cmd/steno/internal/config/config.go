@@ -0,0 +1,557 @@
+// Package config loads the steno TUI's own settings file. It is separate
+// from the daemon's settings.json (StenoSettings), which the Go side never
+// writes.
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jwulff/steno/internal/coding"
+	"github.com/jwulff/steno/internal/db"
+	"github.com/jwulff/steno/internal/scrub"
+	"github.com/jwulff/steno/internal/ui"
+)
+
+// Config is the TUI configuration. The zero value is the default, so a
+// missing file or an omitted section means "stock behavior".
+type Config struct {
+	Scrub       ScrubConfig       `json:"scrub"`
+	Capture     CaptureConfig     `json:"capture"`
+	Display     DisplayConfig     `json:"display"`
+	Metadata    MetadataConfig    `json:"metadata"`
+	Export      ExportConfig      `json:"export"`
+	Maintenance MaintenanceConfig `json:"maintenance"`
+	Alerts      AlertsConfig      `json:"alerts"`
+	Consent     ConsentConfig     `json:"consent"`
+	Dictation   DictationConfig   `json:"dictation"`
+	Coding      CodingConfig      `json:"coding"`
+	Database    DatabaseConfig    `json:"database"`
+}
+
+// DatabaseConfig tunes the TUI's and CLI's SQLite connections to the
+// daemon's database. Omitted fields keep the defaults in db.Options.
+type DatabaseConfig struct {
+	// BusyTimeout is how long a query waits on a lock the daemon holds,
+	// as a Go duration, e.g. "10s". Empty means db.DefaultBusyTimeout.
+	BusyTimeout string `json:"busy_timeout,omitempty"`
+
+	// CacheSizeMB is each connection's page cache in MiB. Zero keeps
+	// SQLite's default.
+	CacheSizeMB int `json:"cache_size_mb,omitempty"`
+
+	// MmapSizeMB is how much of the database reads may memory-map, in
+	// MiB. Zero keeps SQLite's default (off).
+	MmapSizeMB int `json:"mmap_size_mb,omitempty"`
+
+	// QueryOnly makes every connection refuse writes, so steno never
+	// modifies the database. Saving topic edits, notes, annotations and
+	// other client-owned data then fails.
+	QueryOnly bool `json:"query_only,omitempty"`
+}
+
+// Options converts the section to connection options.
+func (c DatabaseConfig) Options() (db.Options, error) {
+	opts := db.Options{
+		CacheSize: int64(c.CacheSizeMB) << 20,
+		MmapSize:  int64(c.MmapSizeMB) << 20,
+		QueryOnly: c.QueryOnly,
+	}
+	if c.CacheSizeMB < 0 {
+		return opts, fmt.Errorf("database.cache_size_mb %d: want 0 or more", c.CacheSizeMB)
+	}
+	if c.MmapSizeMB < 0 {
+		return opts, fmt.Errorf("database.mmap_size_mb %d: want 0 or more", c.MmapSizeMB)
+	}
+	if c.BusyTimeout != "" {
+		d, err := time.ParseDuration(c.BusyTimeout)
+		if err != nil || d <= 0 {
+			return opts, fmt.Errorf("database.busy_timeout %q: want a positive duration such as \"10s\"", c.BusyTimeout)
+		}
+		opts.BusyTimeout = d
+	}
+	return opts, nil
+}
+
+// DatabaseOptions loads the config file at path for its database
+// options alone, for commands that only need to open the database. A
+// file that doesn't load doesn't stop them: err says why, and opts are
+// still the database section's when that section is valid (keeping
+// query_only), else the defaults.
+func DatabaseOptions(path string) (opts db.Options, err error) {
+	cfg, err := Load(path)
+	opts, derr := cfg.Database.Options()
+	if derr != nil {
+		opts = db.Options{}
+	}
+	return opts, err
+}
+
+// DictationConfig controls dictation mode, in which the TUI applies a
+// profile's dictation macros (`steno macros`) to mic segments, turning
+// "new paragraph" into a paragraph break. Toggled at runtime with `D`.
+type DictationConfig struct {
+	// Enabled starts the TUI in dictation mode.
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Profile names the macro set to apply. Empty means
+	// DefaultDictationProfile.
+	Profile string `json:"profile,omitempty"`
+}
+
+// DefaultDictationProfile is the macro profile used when none is
+// configured, and the one `steno macros` edits by default.
+const DefaultDictationProfile = "default"
+
+// ProfileName returns the configured profile, or the default.
+func (c DictationConfig) ProfileName() string {
+	if p := strings.TrimSpace(c.Profile); p != "" {
+		return p
+	}
+	return DefaultDictationProfile
+}
+
+// CodingConfig is the codebook for live qualitative coding: the codes
+// the TUI's C view counts as they are said, and `steno codes` exports.
+type CodingConfig struct {
+	Codes []CodeConfig `json:"codes,omitempty"`
+
+	// Interval is the length of each step of the time series, as a Go
+	// duration. Empty means DefaultCodingInterval.
+	Interval string `json:"interval,omitempty"`
+}
+
+// CodeConfig is one code, e.g. {"name": "trust", "terms": ["trust*",
+// "rely on"]}. A trailing * on a term matches any word ending.
+type CodeConfig struct {
+	Name  string   `json:"name"`
+	Terms []string `json:"terms"`
+}
+
+// DefaultCodingInterval is the time-series step when none is configured.
+const DefaultCodingInterval = time.Minute
+
+// Codebook compiles the configured codes.
+func (c CodingConfig) Codebook() (*coding.Codebook, error) {
+	codes := make([]coding.Code, 0, len(c.Codes))
+	for _, code := range c.Codes {
+		codes = append(codes, coding.Code{Name: code.Name, Terms: code.Terms})
+	}
+	cb, err := coding.Compile(codes)
+	if err != nil {
+		return nil, fmt.Errorf("coding.codes: %w", err)
+	}
+	return cb, nil
+}
+
+// Step parses Interval, or returns DefaultCodingInterval when unset.
+func (c CodingConfig) Step() (time.Duration, error) {
+	if c.Interval == "" {
+		return DefaultCodingInterval, nil
+	}
+	d, err := time.ParseDuration(c.Interval)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("coding.interval %q: want a positive duration such as \"5m\"", c.Interval)
+	}
+	return d, nil
+}
+
+// ConsentConfig controls the recording-consent reminder shown when a
+// session starts. Acknowledging it (`c` in the TUI) records when in
+// session_metadata.
+type ConsentConfig struct {
+	// Reminder shows the banner for every new session until it is
+	// acknowledged.
+	Reminder bool `json:"reminder,omitempty"`
+
+	// Announcement is the text to read or paste to the other
+	// participants. Empty means DefaultConsentAnnouncement.
+	Announcement string `json:"announcement,omitempty"`
+
+	// CopyAnnouncement copies the announcement to the clipboard when the
+	// banner appears, ready to paste into the meeting chat.
+	CopyAnnouncement bool `json:"copy_announcement,omitempty"`
+}
+
+// DefaultConsentAnnouncement is the announcement used when none is
+// configured.
+const DefaultConsentAnnouncement = "Heads up: I'm transcribing this meeting for my notes. Let me know if you'd rather I didn't."
+
+// Text returns the configured announcement, or the default.
+func (c ConsentConfig) Text() string {
+	if strings.TrimSpace(c.Announcement) == "" {
+		return DefaultConsentAnnouncement
+	}
+	return strings.TrimSpace(c.Announcement)
+}
+
+// AlertsConfig controls attention cues for critical events: an error on
+// the bar, the daemon going away, or recording stopping on its own. The
+// error bar is color-only, which is easy to miss in a background pane.
+type AlertsConfig struct {
+	// Bell rings the terminal bell, which tmux and most terminals turn
+	// into a notification when the pane isn't visible.
+	Bell bool `json:"bell,omitempty"`
+
+	// Flash briefly turns the TUI's dividers into a red bar.
+	Flash bool `json:"flash,omitempty"`
+}
+
+// MaintenanceConfig controls `steno maintain --if-due`, the scheduled
+// form of database maintenance.
+type MaintenanceConfig struct {
+	// Interval is the minimum time between scheduled runs as a Go
+	// duration, e.g. "72h". Empty means the command's default.
+	Interval string `json:"interval,omitempty"`
+}
+
+// Every parses Interval; zero when unset.
+func (c MaintenanceConfig) Every() (time.Duration, error) {
+	if c.Interval == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(c.Interval)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("maintenance.interval %q: want a positive duration such as \"168h\"", c.Interval)
+	}
+	return d, nil
+}
+
+// ExportConfig holds defaults for `steno chapters` and `steno export`,
+// whose flags override them per export, and for the TUI's W export.
+type ExportConfig struct {
+	// ChaptersBy is "topics" (default, one chapter per topic) or
+	// "interval" (fixed-length chapters).
+	ChaptersBy string `json:"chapters_by,omitempty"`
+
+	// ChapterInterval is the interval chapter length as a Go duration,
+	// e.g. "5m". Empty means the exporter's default.
+	ChapterInterval string `json:"chapter_interval,omitempty"`
+
+	// Anonymize is the anonymization preset `steno export` applies when
+	// --anonymize is not given: "internal-share", "gdpr-minimal" or
+	// "full". Empty exports the transcript as recorded.
+	Anonymize string `json:"anonymize,omitempty"`
+
+	// Dir is where the TUI's W key writes the current session's
+	// transcript. Empty means ~/Downloads.
+	Dir string `json:"dir,omitempty"`
+
+	// Terms is the custom dictionary `steno export` checks spellings
+	// against: names and terms as they should be written, with the ways
+	// the recognizer mishears them.
+	Terms []TermConfig `json:"terms,omitempty"`
+}
+
+// TermConfig is one custom dictionary entry, e.g.
+// {"term": "Kubernetes", "variants": ["cube or netties"]}.
+type TermConfig struct {
+	Term     string   `json:"term"`
+	Variants []string `json:"variants,omitempty"`
+}
+
+// Directory returns Dir, or ~/Downloads when unset.
+func (c ExportConfig) Directory() string {
+	if c.Dir != "" {
+		return c.Dir
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, "Downloads")
+}
+
+// ChapterSources lists the accepted export.chapters_by values.
+var ChapterSources = []string{"topics", "interval"}
+
+// AnonymizePresets lists the accepted export.anonymize values.
+var AnonymizePresets = []string{"internal-share", "gdpr-minimal", "full"}
+
+// Interval parses ChapterInterval; zero when unset.
+func (c ExportConfig) Interval() (time.Duration, error) {
+	if c.ChapterInterval == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(c.ChapterInterval)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("export.chapter_interval %q: want a positive duration such as \"5m\"", c.ChapterInterval)
+	}
+	return d, nil
+}
+
+// MetadataConfig controls optional context recorded alongside each
+// session in session_metadata.
+type MetadataConfig struct {
+	// ForegroundApp records which app had focus when the session opened,
+	// shown in the session browser. Off by default: it reveals what you
+	// were doing when each session began.
+	ForegroundApp bool `json:"foreground_app,omitempty"`
+}
+
+// DisplayConfig holds TUI layout defaults.
+type DisplayConfig struct {
+	// Density is the initial transcript density: "normal" (default),
+	// "compact", "comfortable" or "captions". Cycled at runtime with `v`.
+	Density string `json:"density,omitempty"`
+
+	// ReadingMode starts the TUI in reading mode: the transcript alone,
+	// capped at ~90 columns and centered. Toggled at runtime with `R`.
+	ReadingMode bool `json:"reading_mode,omitempty"`
+
+	// SummaryTicker shows the latest rolling summary's last sentence
+	// under the transcript header. Toggled at runtime with `T`.
+	SummaryTicker bool `json:"summary_ticker,omitempty"`
+
+	// Palette is the color scheme: "default"; "dark" or "light" for dark
+	// and light terminal backgrounds; "deuteranopia" or "protanopia" for
+	// red-green color blindness; or a name from Palettes. The color-blind
+	// presets also mark focus, selection and speakers with symbols.
+	// Cycled at runtime with `H`.
+	Palette string `json:"palette,omitempty"`
+
+	// Palettes defines custom color schemes by name, for Palette and the
+	// `H` cycle.
+	Palettes map[string]PaletteConfig `json:"palettes,omitempty"`
+
+	// NoColor renders without color, as `--no-color` and NO_COLOR do.
+	NoColor bool `json:"no_color,omitempty"`
+}
+
+// Densities lists the accepted display.density values.
+var Densities = []string{"normal", "compact", "comfortable", "captions"}
+
+// PaletteConfig is a custom color scheme: a preset to start from (Base,
+// default "default") with any of its colors replaced, each written
+// "#RRGGBB". The names are roles rather than hues: Red marks recording
+// and errors, Green the mic and healthy levels, Yellow warnings, Cyan
+// the system audio, Gray and DimGray secondary text and borders, White
+// titles and Magenta highlights. Speakers, when set, replaces the six
+// speaker colors.
+type PaletteConfig struct {
+	Base     string   `json:"base,omitempty"`
+	Red      string   `json:"red,omitempty"`
+	Green    string   `json:"green,omitempty"`
+	Yellow   string   `json:"yellow,omitempty"`
+	Cyan     string   `json:"cyan,omitempty"`
+	Gray     string   `json:"gray,omitempty"`
+	DimGray  string   `json:"dim_gray,omitempty"`
+	White    string   `json:"white,omitempty"`
+	Magenta  string   `json:"magenta,omitempty"`
+	Speakers []string `json:"speakers,omitempty"`
+}
+
+// hexColor matches a PaletteConfig color.
+var hexColor = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
+
+// CustomPalettes builds the palettes defined in Palettes, for
+// ui.DefinePalettes.
+func (d DisplayConfig) CustomPalettes() (map[string]ui.Palette, error) {
+	out := make(map[string]ui.Palette, len(d.Palettes))
+	for name, pc := range d.Palettes {
+		if strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("display.palettes: empty name")
+		}
+		if _, ok := ui.Preset(name); ok {
+			return nil, fmt.Errorf("display.palettes.%s: %q is a preset name", name, name)
+		}
+		base := pc.Base
+		if base == "" {
+			base = ui.DefaultPalette
+		}
+		p, ok := ui.Preset(base)
+		if !ok {
+			return nil, fmt.Errorf("display.palettes.%s.base %q: want one of %s", name, base, strings.Join(ui.Presets, ", "))
+		}
+		p.Speakers = slices.Clone(p.Speakers)
+		for _, c := range []struct {
+			field string
+			value string
+			dst   *lipgloss.Color
+		}{
+			{"red", pc.Red, &p.Red}, {"green", pc.Green, &p.Green},
+			{"yellow", pc.Yellow, &p.Yellow}, {"cyan", pc.Cyan, &p.Cyan},
+			{"gray", pc.Gray, &p.Gray}, {"dim_gray", pc.DimGray, &p.DimGray},
+			{"white", pc.White, &p.White}, {"magenta", pc.Magenta, &p.Magenta},
+		} {
+			if c.value == "" {
+				continue
+			}
+			if !hexColor.MatchString(c.value) {
+				return nil, fmt.Errorf("display.palettes.%s.%s %q: want a color like \"#FF8700\"", name, c.field, c.value)
+			}
+			*c.dst = lipgloss.Color(c.value)
+		}
+		if len(pc.Speakers) > 0 {
+			if len(pc.Speakers) != len(ui.SpeakerGlyphs) {
+				return nil, fmt.Errorf("display.palettes.%s.speakers: want %d colors, got %d", name, len(ui.SpeakerGlyphs), len(pc.Speakers))
+			}
+			p.Speakers = nil
+			for _, s := range pc.Speakers {
+				if !hexColor.MatchString(s) {
+					return nil, fmt.Errorf("display.palettes.%s.speakers %q: want a color like \"#FF8700\"", name, s)
+				}
+				p.Speakers = append(p.Speakers, lipgloss.Color(s))
+			}
+		}
+		out[name] = p
+	}
+	return out, nil
+}
+
+// CaptureConfig holds recording preferences.
+type CaptureConfig struct {
+	// Locales are the recognition locales the TUI's `L` key cycles
+	// through mid-recording, e.g. ["en_US", "fr_FR"].
+	Locales []string `json:"locales,omitempty"`
+
+	// PartialDrafts has the TUI write each source's unfinished words to
+	// a file beside the database every few seconds, so a crash loses at
+	// most those seconds of them; the recovery screen (`I`) shows what
+	// was cut off.
+	PartialDrafts bool `json:"partial_drafts,omitempty"`
+}
+
+// ScrubConfig controls display-time masking of sensitive text in partials
+// and segments, for when the TUI is on a shared screen.
+type ScrubConfig struct {
+	Enabled bool `json:"enabled"`
+
+	// Patterns are Go regular expressions. Empty means scrub.DefaultPatterns.
+	Patterns []string `json:"patterns,omitempty"`
+
+	// Replacement is substituted for each match. Empty means
+	// scrub.DefaultReplacement.
+	Replacement string `json:"replacement,omitempty"`
+}
+
+// Scrubber builds the configured scrubber, or nil when scrubbing is off.
+func (c ScrubConfig) Scrubber() (*scrub.Scrubber, error) {
+	if !c.Enabled {
+		return nil, nil
+	}
+	patterns := c.Patterns
+	if len(patterns) == 0 {
+		patterns = scrub.DefaultPatterns
+	}
+	return scrub.New(patterns, c.Replacement)
+}
+
+// DefaultPath returns ~/Library/Application Support/Steno/config.json.
+func DefaultPath() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, "Library", "Application Support", "Steno", "config.json")
+}
+
+// Path returns $STENO_CONFIG, falling back to DefaultPath().
+func Path() string {
+	if p := os.Getenv("STENO_CONFIG"); p != "" {
+		return p
+	}
+	return DefaultPath()
+}
+
+// Load reads and validates the config at path. A missing file is not an
+// error and yields the zero Config.
+func Load(path string) (Config, error) {
+	var cfg Config
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, fmt.Errorf("read config: %w", err)
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("parse config %s: %w", path, err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return cfg, fmt.Errorf("config %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Validate reports configuration errors that would otherwise surface
+// mid-session (e.g. an invalid scrub regex or mic gain).
+func (c Config) Validate() error {
+	if c.Scrub.Enabled {
+		if _, err := c.Scrub.Scrubber(); err != nil {
+			return err
+		}
+	}
+	if d := c.Display.Density; d != "" && !slices.Contains(Densities, d) {
+		return fmt.Errorf("display.density %q: want one of %s", d, strings.Join(Densities, ", "))
+	}
+	if _, err := c.Display.CustomPalettes(); err != nil {
+		return err
+	}
+	if p := c.Display.Palette; p != "" && !slices.Contains(ui.Presets, p) {
+		if _, ok := c.Display.Palettes[p]; !ok {
+			return fmt.Errorf("display.palette %q: want one of %s, or a name from display.palettes", p, strings.Join(ui.Presets, ", "))
+		}
+	}
+	for i, l := range c.Capture.Locales {
+		if strings.TrimSpace(l) == "" {
+			return fmt.Errorf("capture.locales: empty locale")
+		}
+		if slices.Contains(c.Capture.Locales[:i], l) {
+			return fmt.Errorf("capture.locales: %q listed twice", l)
+		}
+	}
+	if by := c.Export.ChaptersBy; by != "" && !slices.Contains(ChapterSources, by) {
+		return fmt.Errorf("export.chapters_by %q: want one of %s", by, strings.Join(ChapterSources, ", "))
+	}
+	if _, err := c.Export.Interval(); err != nil {
+		return err
+	}
+	if _, err := c.Maintenance.Every(); err != nil {
+		return err
+	}
+	if _, err := c.Database.Options(); err != nil {
+		return err
+	}
+	if a := c.Export.Anonymize; a != "" && !slices.Contains(AnonymizePresets, a) {
+		return fmt.Errorf("export.anonymize %q: want one of %s", a, strings.Join(AnonymizePresets, ", "))
+	}
+	terms := map[string]bool{}
+	for _, t := range c.Export.Terms {
+		key := strings.ToLower(strings.TrimSpace(t.Term))
+		if key == "" {
+			return fmt.Errorf("export.terms: empty term")
+		}
+		if terms[key] {
+			return fmt.Errorf("export.terms: %q listed twice", t.Term)
+		}
+		terms[key] = true
+		for _, v := range t.Variants {
+			if strings.TrimSpace(v) == "" {
+				return fmt.Errorf("export.terms: %q has an empty variant", t.Term)
+			}
+		}
+	}
+	codes := map[string]bool{}
+	for _, code := range c.Coding.Codes {
+		key := strings.ToLower(strings.TrimSpace(code.Name))
+		if key == "" {
+			return fmt.Errorf("coding.codes: empty name")
+		}
+		if codes[key] {
+			return fmt.Errorf("coding.codes: %q listed twice", code.Name)
+		}
+		codes[key] = true
+		if len(code.Terms) == 0 {
+			return fmt.Errorf("coding.codes: %q has no terms", code.Name)
+		}
+	}
+	if _, err := c.Coding.Codebook(); err != nil {
+		return err
+	}
+	if _, err := c.Coding.Step(); err != nil {
+		return err
+	}
+	return nil
+}
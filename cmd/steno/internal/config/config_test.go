@@ -0,0 +1,222 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jwulff/steno/internal/db"
+	"github.com/jwulff/steno/internal/ui"
+)
+
+func writeConfig(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	return path
+}
+
+func TestLoadMissingIsDefault(t *testing.T) {
+	cfg, err := Load(filepath.Join(t.TempDir(), "nope.json"))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Scrub.Enabled {
+		t.Error("scrub should default to disabled")
+	}
+	if cfg.Metadata.ForegroundApp {
+		t.Error("foreground-app recording should be opt-in")
+	}
+}
+
+func TestLoadScrub(t *testing.T) {
+	path := writeConfig(t, `{"scrub": {"enabled": true, "patterns": ["secret-\\d+"], "replacement": "***"}}`)
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	s, err := cfg.Scrub.Scrubber()
+	if err != nil {
+		t.Fatalf("Scrubber: %v", err)
+	}
+	if got := s.Apply("it is secret-42"); got != "it is ***" {
+		t.Errorf("Apply = %q", got)
+	}
+}
+
+func TestLoadRejectsInvalidPattern(t *testing.T) {
+	path := writeConfig(t, `{"scrub": {"enabled": true, "patterns": ["("]}}`)
+	if _, err := Load(path); err == nil {
+		t.Error("expected error for invalid pattern")
+	}
+}
+
+func TestScrubberDisabledIsNil(t *testing.T) {
+	s, err := ScrubConfig{Patterns: []string{"x"}}.Scrubber()
+	if err != nil || s != nil {
+		t.Errorf("disabled scrubber = %v, %v; want nil, nil", s, err)
+	}
+}
+
+func TestLoadCapture(t *testing.T) {
+	path := writeConfig(t, `{"capture": {"locales": ["en_US", "fr_FR"], "partial_drafts": true}}`)
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	c := cfg.Capture
+	if len(c.Locales) != 2 || !c.PartialDrafts {
+		t.Errorf("Capture = %+v", c)
+	}
+}
+
+func TestLoadExport(t *testing.T) {
+	cfg, err := Load(writeConfig(t, `{"export": {"chapters_by": "interval", "chapter_interval": "90s", "anonymize": "full"}}`))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if d, err := cfg.Export.Interval(); err != nil || d != 90*time.Second || cfg.Export.ChaptersBy != "interval" || cfg.Export.Anonymize != "full" {
+		t.Errorf("Export = %+v (%v, %v)", cfg.Export, d, err)
+	}
+
+	cfg, err = Load(writeConfig(t, `{"export": {"terms": [{"term": "Kubernetes", "variants": ["cube or netties"]}]}}`))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if terms := cfg.Export.Terms; len(terms) != 1 || terms[0].Term != "Kubernetes" || len(terms[0].Variants) != 1 {
+		t.Errorf("Terms = %+v", terms)
+	}
+}
+
+func TestLoadMaintenance(t *testing.T) {
+	cfg, err := Load(writeConfig(t, `{"maintenance": {"interval": "72h"}}`))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if d, err := cfg.Maintenance.Every(); err != nil || d != 72*time.Hour {
+		t.Errorf("Every = %v, %v", d, err)
+	}
+}
+
+func TestLoadDatabase(t *testing.T) {
+	cfg, err := Load(writeConfig(t, `{"database": {"busy_timeout": "10s", "cache_size_mb": 16, "mmap_size_mb": 256, "query_only": true}}`))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	opts, err := cfg.Database.Options()
+	if err != nil || opts != (db.Options{BusyTimeout: 10 * time.Second, CacheSize: 16 << 20, MmapSize: 256 << 20, QueryOnly: true}) {
+		t.Errorf("Options = %+v, %v", opts, err)
+	}
+	if opts, err := (DatabaseConfig{}).Options(); err != nil || opts != (db.Options{}) {
+		t.Errorf("empty section = %+v, %v; want the db defaults", opts, err)
+	}
+}
+
+func TestDatabaseOptionsDespiteErrors(t *testing.T) {
+	// Another section's mistake keeps the database section.
+	opts, err := DatabaseOptions(writeConfig(t, `{"database": {"query_only": true}, "display": {"density": "huge"}}`))
+	if err == nil || opts != (db.Options{QueryOnly: true}) {
+		t.Errorf("DatabaseOptions = %+v, %v; want query_only and the density error", opts, err)
+	}
+	for _, body := range []string{`{"database": {"busy_timeout": "soon"}}`, `{not json`} {
+		if opts, err := DatabaseOptions(writeConfig(t, body)); err == nil || opts != (db.Options{}) {
+			t.Errorf("DatabaseOptions(%s) = %+v, %v; want the defaults and an error", body, opts, err)
+		}
+	}
+}
+
+func TestLoadAlerts(t *testing.T) {
+	cfg, err := Load(writeConfig(t, `{"alerts": {"bell": true}}`))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if !cfg.Alerts.Bell || cfg.Alerts.Flash {
+		t.Errorf("Alerts = %+v", cfg.Alerts)
+	}
+}
+
+func TestLoadConsent(t *testing.T) {
+	cfg, err := Load(writeConfig(t, `{"consent": {"reminder": true, "copy_announcement": true}}`))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if !cfg.Consent.Reminder || !cfg.Consent.CopyAnnouncement {
+		t.Errorf("Consent = %+v", cfg.Consent)
+	}
+	if got := cfg.Consent.Text(); got != DefaultConsentAnnouncement {
+		t.Errorf("Text() = %q, want the default", got)
+	}
+	if got := (ConsentConfig{Announcement: " We record this call. "}).Text(); got != "We record this call." {
+		t.Errorf("Text() = %q", got)
+	}
+}
+
+func TestLoadDictation(t *testing.T) {
+	cfg, err := Load(writeConfig(t, `{"dictation": {"enabled": true, "profile": "email"}}`))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if !cfg.Dictation.Enabled || cfg.Dictation.ProfileName() != "email" {
+		t.Errorf("Dictation = %+v", cfg.Dictation)
+	}
+	if got := (DictationConfig{}).ProfileName(); got != DefaultDictationProfile {
+		t.Errorf("ProfileName() = %q, want the default", got)
+	}
+}
+
+func TestLoadCoding(t *testing.T) {
+	cfg, err := Load(writeConfig(t, `{"coding": {"codes": [{"name": "trust", "terms": ["trust*", "rely on"]}], "interval": "5m"}}`))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	cb, err := cfg.Coding.Codebook()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := cb.Count("We trusted them, and we rely on them."); len(got) != 1 || got[0] != 2 {
+		t.Errorf("Count = %v, want 2 mentions of trust", got)
+	}
+	if d, _ := cfg.Coding.Step(); d != 5*time.Minute {
+		t.Errorf("Step() = %v", d)
+	}
+	if d, _ := (CodingConfig{}).Step(); d != DefaultCodingInterval {
+		t.Errorf("Step() = %v, want the default", d)
+	}
+}
+
+func TestLoadPalettes(t *testing.T) {
+	cfg, err := Load(writeConfig(t, `{"display": {"palette": "solarized", "palettes": {"solarized": {"base": "light", "red": "#DC322F", "white": "#073642"}}}}`))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	palettes, err := cfg.Display.CustomPalettes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	light, _ := ui.Preset(ui.LightPalette)
+	p := palettes["solarized"]
+	if p.Red != "#DC322F" || p.White != "#073642" {
+		t.Errorf("overrides = %q, %q", p.Red, p.White)
+	}
+	if p.Green != light.Green || len(p.Speakers) != len(light.Speakers) {
+		t.Errorf("colors not overridden should come from the base: %+v", p)
+	}
+}
+
+func TestLoadRejectsBadValues(t *testing.T) {
+	for _, body := range []string{`{"capture": {"locales": [""]}}`, `{"capture": {"locales": ["en_US", "en_US"]}}`, `{"display": {"density": "huge"}}`, `{"display": {"palette": "sepia"}}`,
+		`{"export": {"chapters_by": "speaker"}}`, `{"export": {"chapter_interval": "5"}}`, `{"export": {"chapter_interval": "-1m"}}`, `{"export": {"anonymize": "gdpr"}}`,
+		`{"export": {"terms": [{"term": " "}]}}`, `{"export": {"terms": [{"term": "Go"}, {"term": "go"}]}}`, `{"export": {"terms": [{"term": "Go", "variants": [""]}]}}`, `{"maintenance": {"interval": "weekly"}}`,
+		`{"database": {"busy_timeout": "5"}}`, `{"database": {"busy_timeout": "0s"}}`, `{"database": {"cache_size_mb": -1}}`, `{"database": {"mmap_size_mb": -64}}`,
+		`{"coding": {"codes": [{"name": "", "terms": ["x"]}]}}`, `{"coding": {"codes": [{"name": "Trust", "terms": ["x"]}, {"name": "trust", "terms": ["y"]}]}}`, `{"coding": {"codes": [{"name": "trust"}]}}`,
+		`{"coding": {"codes": [{"name": "trust", "terms": [" "]}]}}`, `{"coding": {"interval": "0s"}}`,
+		`{"display": {"palettes": {"light": {"red": "#FF0000"}}}}`, `{"display": {"palettes": {"mine": {"base": "sepia"}}}}`, `{"display": {"palettes": {"mine": {"red": "red"}}}}`,
+		`{"display": {"palettes": {"mine": {"speakers": ["#FF0000"]}}}}`} {
+		if _, err := Load(writeConfig(t, body)); err == nil {
+			t.Errorf("expected error for %s", body)
+		}
+	}
+}
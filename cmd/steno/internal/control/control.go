@@ -0,0 +1,150 @@
+// Package control runs steno headless as a subprocess bridge: NDJSON
+// daemon commands in on stdin, responses and events out on stdout. It is
+// what `steno --control-stdin` runs, so editors and other apps can embed
+// steno without speaking the Unix socket protocol themselves.
+//
+// The wire format is the daemon protocol unchanged (see
+// daemon/protocol.go). Each stdin line is a Command; each stdout line is
+// either a Response (it has "ok") or an Event (it has "event").
+// Responses are written in command order. A command may carry an "id"
+// member, which is not forwarded to the daemon but is echoed on its
+// response so callers can match them up.
+package control
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/jwulff/steno/internal/daemon"
+)
+
+// idKey is the command member echoed back on the matching response.
+const idKey = "id"
+
+// Run bridges in and out to the daemon at socketPath until in reaches EOF
+// or the daemon goes away. It subscribes to events itself, on the same
+// connection, so callers should not send "subscribe".
+func Run(in io.Reader, out io.Writer, socketPath string) error {
+	client, err := daemon.Connect(socketPath)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	resp, err := client.SendCommand(daemon.Command{Cmd: "subscribe"})
+	if err != nil {
+		return fmt.Errorf("subscribe: %w", err)
+	}
+	if !resp.OK {
+		return fmt.Errorf("subscribe: %s", resp.Error)
+	}
+
+	w := &writer{enc: json.NewEncoder(out)}
+
+	// Events are forwarded until the connection closes. Closing client
+	// on the way out unblocks the read.
+	evDone := make(chan error, 1)
+	go func() {
+		for {
+			ev, err := client.ReadEvent()
+			if err != nil {
+				evDone <- err
+				return
+			}
+			if err := w.write(ev); err != nil {
+				evDone <- err
+				return
+			}
+		}
+	}()
+
+	cmdDone := make(chan error, 1)
+	go func() { cmdDone <- forwardCommands(in, w, client) }()
+
+	select {
+	case err := <-cmdDone:
+		return err
+	case err := <-evDone:
+		return fmt.Errorf("daemon event stream: %w", err)
+	}
+}
+
+// forwardCommands sends each stdin line to the daemon and writes back the
+// response. Lines that aren't commands get an error response rather than
+// ending the session.
+func forwardCommands(in io.Reader, w *writer, client *daemon.Client) error {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 1024*1024), 1024*1024) // 1MB, as the client
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var cmd daemon.Command
+		if err := json.Unmarshal([]byte(line), &cmd); err != nil {
+			if err := w.write(daemon.Response{Error: fmt.Sprintf("invalid command: %v", err)}); err != nil {
+				return err
+			}
+			continue
+		}
+		// The caller's id may be any JSON value, and the daemon client
+		// numbers commands itself (Command.ID), so it's kept raw.
+		var caller struct {
+			ID json.RawMessage `json:"id"`
+		}
+		json.Unmarshal([]byte(line), &caller)
+		id, hasID := caller.ID, caller.ID != nil
+		cmd.ID = 0
+		delete(cmd.Extra, idKey)
+
+		var resp daemon.Response
+		switch cmd.Cmd {
+		case "":
+			resp = daemon.Response{Error: `invalid command: missing "cmd"`}
+		case "subscribe":
+			// The bridge is already subscribed; subscribing again would
+			// double the event stream.
+			resp = daemon.Response{OK: true}
+		default:
+			var err error
+			if resp, err = client.SendCommand(cmd); err != nil {
+				return err
+			}
+		}
+		resp.ID = 0
+		if hasID {
+			if resp.Extra == nil {
+				resp.Extra = map[string]json.RawMessage{}
+			}
+			resp.Extra[idKey] = id
+		}
+		if err := w.write(resp); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("read stdin: %w", err)
+	}
+	return nil
+}
+
+// writer serializes lines from the command and event goroutines.
+type writer struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+func (w *writer) write(v any) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err := w.enc.Encode(v); err != nil {
+		return fmt.Errorf("write stdout: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package control
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jwulff/steno/internal/daemon"
+)
+
+// mockDaemon answers commands from responses; a subscribed connection
+// gets events after its ok.
+func mockDaemon(t *testing.T, responses map[string]daemon.Response, events []daemon.Event) string {
+	t.Helper()
+
+	// Short /tmp path: macOS caps sun_path at 104 bytes.
+	sockPath := fmt.Sprintf("/tmp/steno-control-%d.sock", time.Now().UnixNano())
+	ln, err := net.Listen("unix", sockPath)
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() {
+		ln.Close()
+		os.Remove(sockPath)
+	})
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				scanner := bufio.NewScanner(conn)
+				for scanner.Scan() {
+					var cmd daemon.Command
+					_ = json.Unmarshal(scanner.Bytes(), &cmd)
+					if cmd.Cmd == "subscribe" {
+						data, _ := json.Marshal(daemon.Response{OK: true})
+						conn.Write(append(data, '\n'))
+						for _, ev := range events {
+							data, _ := json.Marshal(ev)
+							conn.Write(append(data, '\n'))
+						}
+						continue
+					}
+					resp, ok := responses[cmd.Cmd]
+					if !ok {
+						resp = daemon.Response{OK: false, Error: "Unknown command: " + cmd.Cmd}
+					}
+					if len(cmd.Extra) > 0 {
+						resp = daemon.Response{OK: false, Error: "unexpected members forwarded"}
+					}
+					data, _ := json.Marshal(resp)
+					conn.Write(append(data, '\n'))
+				}
+			}(conn)
+		}
+	}()
+	return sockPath
+}
+
+// bridge runs Run against sock and returns a stdin writer, a stdout line
+// reader, and a channel with Run's result.
+func bridge(t *testing.T, sock string) (io.WriteCloser, func() map[string]any, <-chan error) {
+	t.Helper()
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		err := Run(inR, outW, sock)
+		outW.Close()
+		done <- err
+	}()
+	scanner := bufio.NewScanner(outR)
+	next := func() map[string]any {
+		t.Helper()
+		if !scanner.Scan() {
+			t.Fatalf("stdout closed: %v", scanner.Err())
+		}
+		var m map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &m); err != nil {
+			t.Fatalf("stdout line %q: %v", scanner.Text(), err)
+		}
+		return m
+	}
+	t.Cleanup(func() {
+		inW.Close()
+		go io.Copy(io.Discard, outR)
+	})
+	return inW, next, done
+}
+
+func TestRunForwardsEventsAndCommands(t *testing.T) {
+	recording := true
+	sock := mockDaemon(t,
+		map[string]daemon.Response{"status": {OK: true, Recording: &recording}},
+		[]daemon.Event{{Event: "partial", Text: "hello", Source: "microphone"}},
+	)
+	in, next, done := bridge(t, sock)
+
+	if ev := next(); ev["event"] != "partial" || ev["text"] != "hello" {
+		t.Fatalf("first line = %v, want the partial event", ev)
+	}
+
+	fmt.Fprintln(in, `{"cmd":"status","id":7}`)
+	resp := next()
+	if resp["ok"] != true || resp["recording"] != true {
+		t.Errorf("status response = %v", resp)
+	}
+	if resp["id"] != float64(7) {
+		t.Errorf("id = %v, want 7 echoed", resp["id"])
+	}
+
+	fmt.Fprintln(in, `{"cmd":"bogus"}`)
+	if resp := next(); resp["ok"] != false || resp["error"] != "Unknown command: bogus" {
+		t.Errorf("bogus response = %v, want the daemon's error", resp)
+	}
+
+	in.Close()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Run: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Run did not return at stdin EOF")
+	}
+}
+
+func TestRunRejectsMalformedLines(t *testing.T) {
+	sock := mockDaemon(t, map[string]daemon.Response{"status": {OK: true}}, nil)
+	in, next, _ := bridge(t, sock)
+
+	fmt.Fprintln(in, `not json`)
+	if resp := next(); resp["ok"] != false || !strings.HasPrefix(resp["error"].(string), "invalid command") {
+		t.Errorf("malformed response = %v", resp)
+	}
+	fmt.Fprintln(in, `{"device":"x"}`)
+	if resp := next(); resp["ok"] != false || !strings.Contains(resp["error"].(string), `missing "cmd"`) {
+		t.Errorf("missing cmd response = %v", resp)
+	}
+	// A subscribe is answered locally; the bridge is already subscribed.
+	fmt.Fprintln(in, `{"cmd":"subscribe"}`)
+	if resp := next(); resp["ok"] != true {
+		t.Errorf("subscribe response = %v", resp)
+	}
+	// The session survives all of the above.
+	fmt.Fprintln(in, `{"cmd":"status"}`)
+	if resp := next(); resp["ok"] != true {
+		t.Errorf("status response = %v", resp)
+	}
+}
+
+func TestRunNoDaemon(t *testing.T) {
+	if err := Run(strings.NewReader(""), io.Discard, "/tmp/steno-control-missing.sock"); err == nil {
+		t.Error("Run with no daemon: want error")
+	}
+}
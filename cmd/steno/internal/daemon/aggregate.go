@@ -0,0 +1,122 @@
+package daemon
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Endpoint names one of several daemons to aggregate: a label that tags
+// its events, and its socket. A daemon on another Mac can be reached by
+// forwarding its socket, e.g. `ssh -L /tmp/room-b.sock:<remote socket>`.
+type Endpoint struct {
+	Name       string
+	SocketPath string
+}
+
+// ParseEndpoint parses NAME=SOCKET.
+func ParseEndpoint(s string) (Endpoint, error) {
+	name, path, ok := strings.Cut(s, "=")
+	name, path = strings.TrimSpace(name), strings.TrimSpace(path)
+	if !ok || name == "" || path == "" {
+		return Endpoint{}, fmt.Errorf("daemon %q: want NAME=SOCKET", s)
+	}
+	return Endpoint{Name: name, SocketPath: path}, nil
+}
+
+// TaggedEvent is an event from one of several aggregated daemons.
+type TaggedEvent struct {
+	// Daemon is the Endpoint name the event came from.
+	Daemon string
+	Event  Event
+	// Err is set, with a zero Event, when this daemon's stream ended. It
+	// is the last TaggedEvent from that daemon.
+	Err error
+}
+
+// Aggregate subscribes to every endpoint and calls fn with each event
+// from any of them as it arrives, one call at a time, so fn sees a single
+// stream merged in arrival order. Each daemon records its own sessions
+// as usual; the tags keep their events apart.
+//
+// Connecting is all or nothing: if an endpoint can't be reached or
+// refuses to subscribe, no connection is kept. After that, one daemon
+// going away doesn't stop the others. Aggregate returns nil once every
+// stream has ended, or fn's first error.
+func Aggregate(endpoints []Endpoint, fn func(TaggedEvent) error) error {
+	if len(endpoints) == 0 {
+		return errors.New("aggregate: no daemons")
+	}
+	seen := map[string]bool{}
+	for _, ep := range endpoints {
+		if seen[ep.Name] {
+			return fmt.Errorf("aggregate: daemon %q named twice", ep.Name)
+		}
+		seen[ep.Name] = true
+	}
+
+	clients := make([]*Client, 0, len(endpoints))
+	closeAll := func() {
+		for _, c := range clients {
+			c.Close()
+		}
+	}
+	for _, ep := range endpoints {
+		c, err := Connect(ep.SocketPath)
+		if err != nil {
+			closeAll()
+			return fmt.Errorf("daemon %s: %w", ep.Name, err)
+		}
+		clients = append(clients, c)
+		resp, err := c.SendCommand(Command{Cmd: "subscribe"})
+		if err == nil && !resp.OK {
+			err = errors.New(resp.Error)
+		}
+		if err != nil {
+			closeAll()
+			return fmt.Errorf("daemon %s: subscribe: %w", ep.Name, err)
+		}
+	}
+	defer closeAll()
+
+	// done releases the readers once fn fails; closing their clients
+	// unblocks the reads.
+	events := make(chan TaggedEvent)
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	for i, c := range clients {
+		name := endpoints[i].Name
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				ev, err := c.ReadEvent()
+				te := TaggedEvent{Daemon: name, Event: ev, Err: err}
+				select {
+				case events <- te:
+				case <-done:
+					return
+				}
+				if err != nil {
+					return
+				}
+			}
+		}()
+	}
+
+	var err error
+	for open := len(clients); open > 0; {
+		te := <-events
+		if te.Err != nil {
+			open--
+		}
+		if err = fn(te); err != nil {
+			break
+		}
+	}
+	close(done)
+	closeAll()
+	wg.Wait()
+	return err
+}
@@ -0,0 +1,80 @@
+package daemon
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseEndpoint(t *testing.T) {
+	ep, err := ParseEndpoint("room-b=/tmp/room-b.sock")
+	if err != nil || ep.Name != "room-b" || ep.SocketPath != "/tmp/room-b.sock" {
+		t.Errorf("ParseEndpoint = %+v, %v", ep, err)
+	}
+	for _, bad := range []string{"room-b", "=/tmp/x.sock", "room-b="} {
+		if _, err := ParseEndpoint(bad); err == nil {
+			t.Errorf("ParseEndpoint(%q) should fail", bad)
+		}
+	}
+}
+
+func TestAggregateMergesTaggedStreams(t *testing.T) {
+	roomA, cleanupA := startMockEventStream(t, []Event{{Event: "segment", Text: "a1", SessionID: "sess-a"}, {Event: "segment", Text: "a2", SessionID: "sess-a"}})
+	defer cleanupA()
+	roomB, cleanupB := startMockEventStream(t, []Event{{Event: "segment", Text: "b1", SessionID: "sess-b"}})
+	defer cleanupB()
+
+	got := map[string][]string{}
+	ended := map[string]bool{}
+	err := Aggregate([]Endpoint{{"room-a", roomA}, {"room-b", roomB}}, func(te TaggedEvent) error {
+		if te.Err != nil {
+			ended[te.Daemon] = true
+			return nil
+		}
+		if ended[te.Daemon] {
+			t.Errorf("event from %s after its stream ended", te.Daemon)
+		}
+		got[te.Daemon] = append(got[te.Daemon], te.Event.SessionID+":"+te.Event.Text)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Aggregate: %v", err)
+	}
+	if strings.Join(got["room-a"], ",") != "sess-a:a1,sess-a:a2" || strings.Join(got["room-b"], ",") != "sess-b:b1" {
+		t.Errorf("events = %v", got)
+	}
+	if !ended["room-a"] || !ended["room-b"] {
+		t.Errorf("ended = %v, want both streams reported", ended)
+	}
+}
+
+func TestAggregateStopsOnCallbackError(t *testing.T) {
+	room, cleanup := startMockEventStream(t, []Event{{Event: "partial"}, {Event: "partial"}})
+	defer cleanup()
+
+	stop := errors.New("stop")
+	calls := 0
+	err := Aggregate([]Endpoint{{"room", room}}, func(TaggedEvent) error {
+		calls++
+		return stop
+	})
+	if !errors.Is(err, stop) || calls != 1 {
+		t.Errorf("err = %v after %d calls, want stop after 1", err, calls)
+	}
+}
+
+func TestAggregateConnectFailure(t *testing.T) {
+	room, cleanup := startMockEventStream(t, nil)
+	defer cleanup()
+
+	err := Aggregate([]Endpoint{{"room", room}, {"gone", "/nonexistent/steno.sock"}}, func(TaggedEvent) error { return nil })
+	if err == nil || !strings.Contains(err.Error(), "daemon gone") {
+		t.Errorf("err = %v, want the unreachable daemon named", err)
+	}
+	if err := Aggregate([]Endpoint{{"x", room}, {"x", room}}, nil); err == nil {
+		t.Error("duplicate names should be rejected")
+	}
+	if err := Aggregate(nil, nil); err == nil {
+		t.Error("no endpoints should be rejected")
+	}
+}
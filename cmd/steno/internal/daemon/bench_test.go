@@ -0,0 +1,42 @@
+package daemon
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/jwulff/steno/internal/perf"
+)
+
+var (
+	benchSegmentLine = []byte(`{"event":"segment","text":"So the plan for this sprint is mostly cleanup.","source":"microphone","sessionId":"2F6C1D1E-8B7A-4C4B-9E53-4C2D0C9B6A11","sequenceNumber":4,"startedAt":1777098200.25}`)
+	benchLevelLine   = []byte(`{"event":"level","mic":0.42,"sys":0.05}`)
+	benchFutureLine  = []byte(`{"event":"segment","text":"Next item is the budget.","source":"microphone","sequenceNumber":7,"speaker":{"id":"spk-2","label":"Speaker 2"},"healMarker":"merged"}`)
+)
+
+func benchDecode(b *testing.B, line []byte) {
+	b.ReportAllocs()
+	for b.Loop() {
+		var ev Event
+		if err := json.Unmarshal(line, &ev); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDecodeSegmentEvent(b *testing.B) { benchDecode(b, benchSegmentLine) }
+
+// BenchmarkDecodeLevelEvent covers the hottest event: the daemon emits
+// dozens of level events per second.
+func BenchmarkDecodeLevelEvent(b *testing.B) { benchDecode(b, benchLevelLine) }
+
+// BenchmarkDecodeUnknownFields covers the lenient slow path (compat.go).
+func BenchmarkDecodeUnknownFields(b *testing.B) { benchDecode(b, benchFutureLine) }
+
+func TestPerformanceBudgets(t *testing.T) {
+	perf.Check(t, []perf.Budget{
+		{Name: "decode segment event", Bench: BenchmarkDecodeSegmentEvent, Max: 50 * time.Microsecond},
+		{Name: "decode level event", Bench: BenchmarkDecodeLevelEvent, Max: 20 * time.Microsecond},
+		{Name: "decode event with unknown fields", Bench: BenchmarkDecodeUnknownFields, Max: 100 * time.Microsecond},
+	})
+}
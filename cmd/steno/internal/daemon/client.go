@@ -2,25 +2,130 @@ package daemon
 
 import (
 	"bufio"
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net"
 	"os"
 	"path/filepath"
 	"sync"
+	"time"
 )
 
+// maxLineBytes caps one NDJSON line. Longer lines are malformed.
+const maxLineBytes = 1024 * 1024
+
+// Strictness is how a Client treats lines it can't decode: not JSON, not
+// an object, or over maxLineBytes.
+type Strictness int
+
+const (
+	// SkipMalformed (the default) skips such lines, reports each to the
+	// OnMalformed handler, and reads on, so a daemon bug or stray write
+	// to the socket costs one message rather than the connection. On a
+	// daemon that doesn't echo IDs, the line is taken for the oldest
+	// command's response: that command fails with ErrMalformedLine
+	// rather than waiting out its timeout.
+	SkipMalformed Strictness = iota
+	// FailMalformed returns an error for the first such line, leaving the
+	// stream positioned after it. For tests and protocol debugging.
+	FailMalformed
+)
+
+// ErrMalformedLine wraps the error for a line rejected under
+// FailMalformed, and the one passed to the OnMalformed handler.
+var ErrMalformedLine = errors.New("malformed line")
+
+// ErrOutOfSync is returned by SendCommand once a command timed out on a
+// connection whose daemon doesn't echo command IDs: its late response
+// would be taken for the next command's, so the connection can't be
+// used for commands again. Reconnect.
+var ErrOutOfSync = errors.New("command connection out of sync")
+
+// DefaultCommandTimeout is how long SendCommand waits for a response,
+// unless SetCommandTimeout says otherwise. Commands that run a language
+// model or play audio get slowCommandTimeout.
+const DefaultCommandTimeout = 30 * time.Second
+
+// slowCommandTimeout bounds the commands in slowCommands.
+const slowCommandTimeout = 10 * time.Minute
+
+// slowCommands legitimately take longer than DefaultCommandTimeout.
+var slowCommands = map[string]bool{"clean_verbatim": true, "inject_audio": true}
+
 // SocketPath returns the default daemon socket path.
 func SocketPath() string {
 	home, _ := os.UserHomeDir()
 	return filepath.Join(home, "Library", "Application Support", "Steno", "steno.sock")
 }
 
-// Client communicates with steno-daemon over a Unix socket.
+// eventBuffer is how many events the reader holds for ReadEvent. Past
+// it, the reader waits, and so do responses behind it.
+const eventBuffer = 256
+
+// errConnectionClosed is the reader's error once the daemon hangs up.
+var errConnectionClosed = errors.New("connection closed")
+
+// Client communicates with steno-daemon over one Unix socket connection,
+// which carries both commands and, once subscribed, events. A reader
+// goroutine, started by the first SendCommand or ReadEvent, sorts what
+// arrives: a line with an "event" member is an event, queued for
+// ReadEvent; anything else is a response, handed to the command whose
+// ID it echoes. Lines it can't decode are skipped by default; see
+// Strictness and OnMalformed.
 type Client struct {
-	conn    net.Conn
-	scanner *bufio.Scanner
-	mu      sync.Mutex
+	conn   net.Conn
+	reader *bufio.Reader
+
+	// log, when set, records every line sent and received (bug reports).
+	log *MessageLog
+
+	strictness  Strictness
+	onMalformed func(line []byte, err error)
+	timeout     time.Duration
+
+	// wmu keeps concurrent commands' lines whole. serial, a one-slot
+	// semaphore so a context can give up waiting for it, holds commands
+	// to one in flight until the daemon is seen to echo IDs: before
+	// that, a response can only be paired with the oldest command.
+	wmu    sync.Mutex
+	serial chan struct{}
+
+	// mu guards the command state, shared with the reader. Commands are
+	// numbered on each connection (Command.ID) and wait in pending for
+	// the response echoing their ID; order is pending's IDs, oldest
+	// first. A response to a command that timed out finds nothing
+	// pending and is dropped; without IDs, a timeout leaves the
+	// connection unusable (outOfSync).
+	mu        sync.Mutex
+	lastID    int
+	pending   map[int]chan reply
+	order     []int
+	echoesIDs bool
+	outOfSync bool
+	readErr   error // why the reader stopped, once done is closed
+
+	readerOnce sync.Once
+	closeOnce  sync.Once
+	events     chan eventOrError
+	done       chan struct{}
+}
+
+// reply is what a command waiting in pending gets: its response, or the
+// error for a malformed line taken for it.
+type reply struct {
+	resp Response
+	err  error
+}
+
+// eventOrError is one item queued for ReadEvent: an event, or under
+// FailMalformed a line that couldn't be decoded.
+type eventOrError struct {
+	ev  Event
+	err error
 }
 
 // Connect dials the daemon Unix socket.
@@ -30,64 +135,398 @@ func Connect(socketPath string) (*Client, error) {
 		return nil, fmt.Errorf("connect to daemon: %w", err)
 	}
 
-	scanner := bufio.NewScanner(conn)
-	scanner.Buffer(make([]byte, 1024*1024), 1024*1024) // 1MB buffer
+	return newClient(conn), nil
+}
 
-	return &Client{conn: conn, scanner: scanner}, nil
+// ConnectTCP dials a TCP address serving the daemon's event stream: a
+// TUI sharing its view (`steno --share`, see package share), which lets
+// in a viewer presenting its token.
+func ConnectTCP(address, token string) (*Client, error) {
+	conn, err := net.Dial("tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("connect to %s: %w", address, err)
+	}
+	if _, err := io.WriteString(conn, token+"\n"); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("connect to %s: %w", address, err)
+	}
+
+	return newClient(conn), nil
 }
 
-// Close shuts down the connection.
-func (c *Client) Close() error {
-	if c.conn != nil {
-		return c.conn.Close()
+func newClient(conn net.Conn) *Client {
+	return &Client{
+		conn:    conn,
+		reader:  bufio.NewReaderSize(conn, 64*1024),
+		timeout: DefaultCommandTimeout,
+		pending: make(map[int]chan reply),
+		serial:  make(chan struct{}, 1),
+		events:  make(chan eventOrError, eventBuffer),
+		done:    make(chan struct{}),
 	}
-	return nil
 }
 
-// SendCommand sends a command and reads one response line.
+// SetCommandTimeout sets how long SendCommand waits for a response; zero
+// waits indefinitely. Call before the client is shared with other
+// goroutines.
+func (c *Client) SetCommandTimeout(d time.Duration) {
+	c.timeout = d
+}
+
+// SetMessageLog records this client's traffic into l. Call before the
+// client is shared with other goroutines.
+func (c *Client) SetMessageLog(l *MessageLog) {
+	c.log = l
+}
+
+// SetStrictness sets how malformed lines are handled (default
+// SkipMalformed). Call before the client is shared with other goroutines.
+func (c *Client) SetStrictness(s Strictness) {
+	c.strictness = s
+}
+
+// Done is closed once the connection's reader stops: the connection
+// closed or failed.
+func (c *Client) Done() <-chan struct{} {
+	return c.done
+}
+
+// OnMalformed calls fn with each malformed line received, under either
+// strictness, before it is skipped or returned as an error. line is
+// truncated for overlong lines and only valid during the call. fn runs on
+// the reading goroutine. Call before the client is shared with other
+// goroutines.
+func (c *Client) OnMalformed(fn func(line []byte, err error)) {
+	c.onMalformed = fn
+}
+
+// Close shuts down the connection, which ends the reader: commands
+// waiting for a response and ReadEvent return an error. Closing twice
+// is harmless, so code holding the client under two names (the TUI's
+// command and event clients) can close both.
+func (c *Client) Close() error {
+	var err error
+	c.closeOnce.Do(func() {
+		if c.conn != nil {
+			err = c.conn.Close()
+		}
+	})
+	return err
+}
+
+// SendCommand sends a command and waits for its response. It is safe to
+// call from several goroutines (a tea.Batch), and their commands are in
+// flight together once the daemon has echoed an ID. Each waits at most
+// the command timeout.
 func (c *Client) SendCommand(cmd Command) (Response, error) {
+	return c.SendCommandCtx(context.Background(), cmd)
+}
+
+// SendCommandCtx is SendCommand, giving up when ctx is done as well as
+// at the command timeout, whichever comes first; the error then wraps
+// ctx.Err(). Giving up on a command that was sent counts as a timeout:
+// without IDs, the connection is out of sync.
+func (c *Client) SendCommandCtx(ctx context.Context, cmd Command) (Response, error) {
+	if err := ctx.Err(); err != nil {
+		return Response{}, fmt.Errorf("%s: %w", cmd.Cmd, err)
+	}
 	c.mu.Lock()
-	defer c.mu.Unlock()
+	echoes := c.echoesIDs
+	c.mu.Unlock()
+	if !echoes {
+		select {
+		case c.serial <- struct{}{}:
+			defer func() { <-c.serial }()
+		case <-ctx.Done():
+			return Response{}, fmt.Errorf("%s: %w", cmd.Cmd, ctx.Err())
+		}
+	}
+
+	c.mu.Lock()
+	if c.outOfSync {
+		c.mu.Unlock()
+		return Response{}, fmt.Errorf("%s: %w", cmd.Cmd, ErrOutOfSync)
+	}
+	if c.readErr != nil {
+		err := c.readErr
+		c.mu.Unlock()
+		return Response{}, fmt.Errorf("read response: %w", err)
+	}
+	c.lastID++
+	cmd.ID = c.lastID
+	ch := make(chan reply, 1)
+	c.pending[cmd.ID] = ch
+	c.order = append(c.order, cmd.ID)
+	c.mu.Unlock()
 
 	data, err := json.Marshal(cmd)
 	if err != nil {
+		c.forget(cmd.ID)
 		return Response{}, fmt.Errorf("marshal command: %w", err)
 	}
-
-	data = append(data, '\n')
-	if _, err := c.conn.Write(data); err != nil {
+	c.log.record(true, data)
+	c.wmu.Lock()
+	deadline, bounded := ctx.Deadline()
+	if bounded {
+		c.conn.SetWriteDeadline(deadline)
+	}
+	err = writeFull(c.conn, append(data, '\n'))
+	if bounded {
+		c.conn.SetWriteDeadline(time.Time{})
+	}
+	c.wmu.Unlock()
+	if err != nil {
+		c.forget(cmd.ID)
 		return Response{}, fmt.Errorf("write command: %w", err)
 	}
+	c.startReader()
 
-	if !c.scanner.Scan() {
-		if err := c.scanner.Err(); err != nil {
-			return Response{}, fmt.Errorf("read response: %w", err)
+	var expired <-chan time.Time
+	if timeout := c.commandTimeout(cmd.Cmd); timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+	select {
+	case r := <-ch:
+		return r.answer(cmd.Cmd)
+	case <-c.done:
+		// The response may have been the reader's last line.
+		select {
+		case r := <-ch:
+			return r.answer(cmd.Cmd)
+		default:
 		}
-		return Response{}, fmt.Errorf("connection closed")
+		return Response{}, fmt.Errorf("read response: %w", c.readErr)
+	case <-expired:
+		c.abandon(cmd.ID)
+		return Response{}, fmt.Errorf("%s: no response within %v", cmd.Cmd, c.commandTimeout(cmd.Cmd))
+	case <-ctx.Done():
+		c.abandon(cmd.ID)
+		return Response{}, fmt.Errorf("%s: %w", cmd.Cmd, ctx.Err())
 	}
+}
 
-	var resp Response
-	if err := json.Unmarshal(c.scanner.Bytes(), &resp); err != nil {
-		return Response{}, fmt.Errorf("unmarshal response: %w", err)
+func (r reply) answer(cmd string) (Response, error) {
+	if r.err != nil {
+		return Response{}, fmt.Errorf("%s: %w", cmd, r.err)
+	}
+	return r.resp, nil
+}
+
+// abandon stops waiting for a command that was sent. Its response may
+// still come; without IDs there's no telling it from the next one's.
+func (c *Client) abandon(id int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.forgetLocked(id)
+	if !c.echoesIDs {
+		c.outOfSync = true
 	}
+}
 
-	return resp, nil
+// forget drops a command that won't be waiting for its response.
+func (c *Client) forget(id int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.forgetLocked(id)
 }
 
-// ReadEvent reads the next NDJSON event line. Blocks until data arrives.
-// After calling Subscribe, use this in a loop to receive events.
+func (c *Client) forgetLocked(id int) {
+	delete(c.pending, id)
+	for i, pending := range c.order {
+		if pending == id {
+			c.order = append(c.order[:i], c.order[i+1:]...)
+			break
+		}
+	}
+}
+
+// commandTimeout is how long to wait for the response to cmd.
+func (c *Client) commandTimeout(cmd string) time.Duration {
+	if c.timeout > 0 && slowCommands[cmd] {
+		return max(c.timeout, slowCommandTimeout)
+	}
+	return c.timeout
+}
+
+// ReadEvent returns the next event. Blocks until one arrives. After
+// subscribing with SendCommand, call it in a loop: the reader holds at
+// most eventBuffer events, and responses queue behind them.
 func (c *Client) ReadEvent() (Event, error) {
-	if !c.scanner.Scan() {
-		if err := c.scanner.Err(); err != nil {
-			return Event{}, fmt.Errorf("read event: %w", err)
+	return c.ReadEventCtx(context.Background())
+}
+
+// ReadEventCtx is ReadEvent, giving up when ctx is done with an error
+// wrapping ctx.Err(). No event is lost to giving up: the next read
+// returns the one that would have come.
+func (c *Client) ReadEventCtx(ctx context.Context) (Event, error) {
+	c.startReader()
+	select {
+	case item, ok := <-c.events:
+		if !ok {
+			return Event{}, fmt.Errorf("read event: %w", c.readErr)
+		}
+		if item.err != nil {
+			return Event{}, fmt.Errorf("read event: %w", item.err)
 		}
-		return Event{}, fmt.Errorf("connection closed")
+		return item.ev, nil
+	case <-ctx.Done():
+		return Event{}, fmt.Errorf("read event: %w", ctx.Err())
 	}
+}
+
+func (c *Client) startReader() {
+	c.readerOnce.Do(func() { go c.readLoop() })
+}
 
+// readLoop reads lines until the connection ends, sending events to
+// ReadEvent and responses to their commands. It skips blank lines and,
+// under SkipMalformed, malformed ones. A last line the daemon wrote
+// without its newline still counts once the connection closes.
+func (c *Client) readLoop() {
+	for {
+		line, tooLong, err := c.readLine()
+		if errors.Is(err, io.EOF) {
+			err = errConnectionClosed
+		}
+		if err != nil {
+			c.mu.Lock()
+			c.readErr = err
+			c.mu.Unlock()
+			close(c.done)
+			close(c.events)
+			return
+		}
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		c.log.record(false, line)
+		c.dispatch(line, tooLong)
+	}
+}
+
+// dispatch routes one line. Event lines are the hot path, so each line
+// is decoded as an event first and only lines without an "event" member
+// again as a response.
+func (c *Client) dispatch(line []byte, tooLong bool) {
 	var ev Event
-	if err := json.Unmarshal(c.scanner.Bytes(), &ev); err != nil {
-		return Event{}, fmt.Errorf("unmarshal event: %w", err)
+	err := decodeLine(line, tooLong, &ev)
+	if err == nil && ev.Event != "" {
+		c.events <- eventOrError{ev: ev}
+		return
 	}
+	var resp Response
+	if err == nil {
+		err = decodeLine(line, false, &resp)
+	}
+	if err != nil {
+		if c.onMalformed != nil {
+			c.onMalformed(line, err)
+		}
+		if c.strictness == FailMalformed {
+			c.events <- eventOrError{err: err}
+		} else {
+			c.failOldest(err)
+		}
+		return
+	}
+	c.deliver(resp)
+}
+
+// failOldest fails the oldest command with err, for a malformed line
+// from a daemon that doesn't echo IDs: the line was most likely its
+// response, which would otherwise never come. With IDs there's no
+// telling whose it was, and each command waits on.
+func (c *Client) failOldest(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.echoesIDs || len(c.order) == 0 {
+		return
+	}
+	id := c.order[0]
+	ch := c.pending[id]
+	c.forgetLocked(id)
+	ch <- reply{err: err}
+}
 
-	return ev, nil
+// deliver hands resp to the command it answers. A daemon that predates
+// IDs answers with none, and with one command in flight that's the
+// oldest. A response no command is waiting for is the late answer to
+// one that timed out.
+func (c *Client) deliver(resp Response) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	id := resp.ID
+	if id != 0 {
+		c.echoesIDs = true
+	} else if len(c.order) > 0 {
+		id = c.order[0]
+	}
+	ch, ok := c.pending[id]
+	if !ok {
+		return
+	}
+	c.forgetLocked(id)
+	ch <- reply{resp: resp}
+}
+
+// decodeLine unmarshals one line, which must be a single JSON object.
+// JSON's null would otherwise decode to a zero message.
+func decodeLine(line []byte, tooLong bool, v any) error {
+	if tooLong {
+		return fmt.Errorf("%w: longer than %d bytes", ErrMalformedLine, maxLineBytes)
+	}
+	if bytes.TrimSpace(line)[0] != '{' {
+		return fmt.Errorf("%w: not a JSON object", ErrMalformedLine)
+	}
+	if err := json.Unmarshal(line, v); err != nil {
+		return fmt.Errorf("%w: %v", ErrMalformedLine, err)
+	}
+	return nil
+}
+
+// readLine returns the next line without its line ending. A line over
+// maxLineBytes comes back cut short with tooLong set, the rest of it
+// discarded. err is only set once no line is left: io.EOF when the
+// connection closed cleanly.
+func (c *Client) readLine() (line []byte, tooLong bool, err error) {
+	for {
+		chunk, err := c.reader.ReadSlice('\n')
+		if !tooLong {
+			// +1 leaves room for the newline itself.
+			if room := maxLineBytes + 1 - len(line); len(chunk) > room {
+				line, tooLong = append(line, chunk[:room]...), true
+			} else {
+				line = append(line, chunk...)
+			}
+		}
+		switch {
+		case errors.Is(err, bufio.ErrBufferFull):
+			continue
+		case err == nil:
+			return bytes.TrimRight(line, "\r\n"), tooLong, nil
+		case errors.Is(err, io.EOF) && len(line) > 0:
+			// The next read reports the EOF again.
+			return bytes.TrimRight(line, "\r"), tooLong, nil
+		default:
+			return nil, false, err
+		}
+	}
+}
+
+// writeFull writes all of data. net.Conn writes are already all-or-error;
+// this also covers a writer that returns short without an error.
+func writeFull(w io.Writer, data []byte) error {
+	for len(data) > 0 {
+		n, err := w.Write(data)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return io.ErrShortWrite
+		}
+		data = data[n:]
+	}
+	return nil
 }
@@ -0,0 +1,232 @@
+package daemon
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// scriptedConn is a net.Conn that serves in as the daemon's output, in
+// reads of at most readSize bytes, and takes writes of at most writeSize
+// bytes per call (0 for no limit), as a slow or fragmenting peer would.
+type scriptedConn struct {
+	net.Conn
+	in        *bytes.Reader
+	written   bytes.Buffer
+	readSize  int
+	writeSize int
+}
+
+func newScriptedConn(in string) *scriptedConn {
+	return &scriptedConn{in: bytes.NewReader([]byte(in))}
+}
+
+func (c *scriptedConn) Read(p []byte) (int, error) {
+	if c.readSize > 0 && len(p) > c.readSize {
+		p = p[:c.readSize]
+	}
+	return c.in.Read(p)
+}
+
+func (c *scriptedConn) Write(p []byte) (int, error) {
+	if c.writeSize > 0 && len(p) > c.writeSize {
+		p = p[:c.writeSize]
+	}
+	return c.written.Write(p)
+}
+
+func (c *scriptedConn) Close() error { return nil }
+
+func (c *scriptedConn) SetReadDeadline(time.Time) error { return nil }
+
+// readAllEvents reads events until the stream ends, at most limit.
+func readAllEvents(t *testing.T, c *Client, limit int) ([]Event, error) {
+	t.Helper()
+	var evs []Event
+	for range limit {
+		ev, err := c.ReadEvent()
+		if err != nil {
+			return evs, err
+		}
+		evs = append(evs, ev)
+	}
+	t.Fatalf("stream didn't end within %d reads", limit)
+	return nil, nil
+}
+
+func TestClientSkipsMalformedLines(t *testing.T) {
+	stream := strings.Join([]string{
+		`{"event":"partial","text":"one"}`,
+		`not json at all`,
+		``,
+		`null`,
+		`[{"event":"segment"}]`,
+		`{"event":"segment","text":"trailing"} garbage`,
+		`{"event":"segment","text":`,
+		"\x00\xff\xfe",
+		`{"event":"segment","text":"two"}` + "\r",
+		`{"event":"level"}`, // no newline: ends the stream
+	}, "\n")
+	c := newClient(newScriptedConn(stream))
+	var reported []string
+	c.OnMalformed(func(line []byte, err error) {
+		if !errors.Is(err, ErrMalformedLine) {
+			t.Errorf("malformed error %v doesn't wrap ErrMalformedLine", err)
+		}
+		reported = append(reported, string(line))
+	})
+
+	evs, err := readAllEvents(t, c, 20)
+	if err == nil || err.Error() != "read event: connection closed" {
+		t.Errorf("end of stream err = %v", err)
+	}
+	var got []string
+	for _, ev := range evs {
+		got = append(got, ev.Event+":"+ev.Text)
+	}
+	if want := []string{"partial:one", "segment:two", "level:"}; strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("events = %v, want %v", got, want)
+	}
+	if len(reported) != 6 {
+		t.Errorf("reported %d malformed lines, want 6 (blank lines are skipped silently): %q", len(reported), reported)
+	}
+}
+
+func TestClientFailMalformed(t *testing.T) {
+	c := newClient(newScriptedConn("oops\n{\"event\":\"partial\"}\n"))
+	c.SetStrictness(FailMalformed)
+	calls := 0
+	c.OnMalformed(func([]byte, error) { calls++ })
+
+	if _, err := c.ReadEvent(); !errors.Is(err, ErrMalformedLine) {
+		t.Fatalf("err = %v, want ErrMalformedLine", err)
+	}
+	if calls != 1 {
+		t.Errorf("OnMalformed called %d times, want 1", calls)
+	}
+	// The stream carries on after the bad line.
+	if ev, err := c.ReadEvent(); err != nil || ev.Event != "partial" {
+		t.Errorf("next event = %+v, %v", ev, err)
+	}
+}
+
+func TestClientMalformedResponseFailsCommand(t *testing.T) {
+	c := newClient(newScriptedConn("{\"ok\":tru\n"))
+	c.SetCommandTimeout(5 * time.Second)
+	reported := 0
+	c.OnMalformed(func([]byte, error) { reported++ })
+
+	start := time.Now()
+	_, err := c.SendCommand(Command{Cmd: "status"})
+	if !errors.Is(err, ErrMalformedLine) {
+		t.Fatalf("err = %v, want ErrMalformedLine", err)
+	}
+	if waited := time.Since(start); waited > time.Second {
+		t.Errorf("waited %v, want the command failed at once rather than timing out", waited)
+	}
+	if reported != 1 {
+		t.Errorf("OnMalformed called %d times, want 1", reported)
+	}
+}
+
+func TestClientOverlongLine(t *testing.T) {
+	long := `{"event":"partial","text":"` + strings.Repeat("x", maxLineBytes) + `"}`
+	conn := newScriptedConn(long + "\n" + `{"event":"level"}` + "\n")
+	c := newClient(conn)
+	var reportedLen int
+	c.OnMalformed(func(line []byte, err error) { reportedLen = len(line) })
+
+	ev, err := c.ReadEvent()
+	if err != nil || ev.Event != "level" {
+		t.Fatalf("event after overlong line = %+v, %v", ev, err)
+	}
+	if reportedLen == 0 || reportedLen > maxLineBytes+1 {
+		t.Errorf("reported overlong line of %d bytes, want it cut to the limit", reportedLen)
+	}
+}
+
+func TestClientFragmentedIO(t *testing.T) {
+	conn := newScriptedConn("\n" + `{"ok":true,"sessionId":"sess-1"}` + "\n")
+	conn.readSize, conn.writeSize = 3, 5
+	c := newClient(conn)
+
+	resp, err := c.SendCommand(Command{Cmd: "status"})
+	if err != nil || !resp.OK || resp.SessionID != "sess-1" {
+		t.Fatalf("response = %+v, %v", resp, err)
+	}
+	if got := conn.written.String(); got != `{"cmd":"status","id":1}`+"\n" {
+		t.Errorf("written = %q, want the whole command", got)
+	}
+}
+
+// FuzzReadEvent feeds arbitrary bytes to the event reader, split across
+// reads, and checks it never panics or loops, returns only well-formed
+// events, and still reads a valid event that follows the garbage.
+func FuzzReadEvent(f *testing.F) {
+	for _, seed := range []string{
+		`{"event":"partial","text":"hi"}`,
+		`{"event":"segment","sequenceNumber":3}` + "\n" + `junk`,
+		"\n\n\r\n",
+		`{"event":`,
+		`null`,
+		`{}{}`,
+		"\xff\x00{",
+		`{"event":"level","mic":"loud"}`,
+	} {
+		f.Add(seed, 1)
+		f.Add(seed, 7)
+	}
+	f.Fuzz(func(t *testing.T, garbage string, readSize int) {
+		conn := newScriptedConn(garbage + "\n" + `{"event":"sentinel"}` + "\n")
+		conn.readSize = max(1, readSize%64)
+		c := newClient(conn)
+		c.OnMalformed(func(line []byte, err error) {
+			if !errors.Is(err, ErrMalformedLine) {
+				t.Errorf("malformed error %v doesn't wrap ErrMalformedLine", err)
+			}
+		})
+
+		evs, err := readAllEvents(t, c, len(garbage)+3)
+		if err == nil || !strings.Contains(err.Error(), "connection closed") {
+			t.Errorf("end of stream err = %v", err)
+		}
+		if len(evs) == 0 || evs[len(evs)-1].Event != "sentinel" {
+			t.Fatalf("sentinel event lost after %q: %+v", garbage, evs)
+		}
+	})
+}
+
+// FuzzSendCommand runs a command against an arbitrary response stream
+// and checks the whole command is written and the result is either a
+// response or an error, never a panic.
+func FuzzSendCommand(f *testing.F) {
+	for _, seed := range []string{
+		`{"ok":true}` + "\n",
+		`{"ok":false,"error":"busy"}`,
+		"garbage\n" + `{"ok":true}` + "\n",
+		`{"ok":"yes"}` + "\n",
+		"",
+		"\n",
+	} {
+		f.Add("status", seed)
+	}
+	f.Fuzz(func(t *testing.T, cmd, response string) {
+		conn := newScriptedConn(response)
+		conn.writeSize = 4
+		c := newClient(conn)
+
+		_, err := c.SendCommand(Command{Cmd: cmd})
+		want, _ := json.Marshal(Command{Cmd: cmd, ID: 1})
+		if got := conn.written.Bytes(); !bytes.Equal(got, append(want, '\n')) {
+			t.Errorf("written = %q, want %q", got, want)
+		}
+		if err != nil && errors.Is(err, io.ErrShortWrite) {
+			t.Errorf("short writes should be retried: %v", err)
+		}
+	})
+}
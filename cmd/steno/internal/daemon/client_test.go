@@ -1,11 +1,16 @@
 package daemon
 
 import (
+	"bufio"
+	"context"
 	"encoding/json"
+	"errors"
 	"net"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
+	"time"
 )
 
 // startMockDaemon creates a Unix socket that accepts one connection,
@@ -78,6 +83,30 @@ func TestClientSendCommand(t *testing.T) {
 	}
 }
 
+func TestClientRecordsMessageLog(t *testing.T) {
+	sockPath, cleanup := startMockDaemon(t, Response{OK: true, Status: "recording"})
+	defer cleanup()
+
+	client, err := Connect(sockPath)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer client.Close()
+	log := NewMessageLog(10)
+	client.SetMessageLog(log)
+
+	if _, err := client.SendCommand(Command{Cmd: "status"}); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	msgs := log.Messages()
+	if len(msgs) != 2 || !msgs[0].Sent || msgs[1].Sent {
+		t.Fatalf("messages = %+v, want sent command then received response", msgs)
+	}
+	if string(msgs[0].Line) != `{"cmd":"status","id":1}` {
+		t.Errorf("sent line = %s", msgs[0].Line)
+	}
+}
+
 func TestClientConnectFailure(t *testing.T) {
 	_, err := Connect("/nonexistent/path/steno.sock")
 	if err == nil {
@@ -166,3 +195,265 @@ func TestClientReadEvents(t *testing.T) {
 		t.Errorf("event2 = %+v", ev2)
 	}
 }
+
+// startConcurrentDaemon serves one connection the way the Swift daemon
+// does, answering each command on its own goroutine, so a slow command's
+// response can land after later ones. delay gives each command's
+// processing time; answered receives each command once its response is
+// written. With echo off the daemon predates command IDs.
+func startConcurrentDaemon(t *testing.T, delay map[string]time.Duration, echo bool) (string, <-chan string) {
+	t.Helper()
+	sockPath := filepath.Join(t.TempDir(), "test.sock")
+	ln, err := net.Listen("unix", sockPath)
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	answered := make(chan string, 16)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		var mu sync.Mutex
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			var cmd Command
+			if err := json.Unmarshal(scanner.Bytes(), &cmd); err != nil {
+				return
+			}
+			go func() {
+				time.Sleep(delay[cmd.Cmd])
+				resp := Response{OK: true, Status: cmd.Cmd}
+				if echo {
+					resp.ID = cmd.ID
+				}
+				data, _ := json.Marshal(resp)
+				mu.Lock()
+				conn.Write(append(data, '\n'))
+				mu.Unlock()
+				answered <- cmd.Cmd
+			}()
+		}
+	}()
+	return sockPath, answered
+}
+
+func TestClientSkipsLateResponses(t *testing.T) {
+	sockPath, answered := startConcurrentDaemon(t, map[string]time.Duration{"devices": 200 * time.Millisecond}, true)
+	client, err := Connect(sockPath)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer client.Close()
+	client.SetCommandTimeout(50 * time.Millisecond)
+
+	if resp, err := client.SendCommand(Command{Cmd: "status"}); err != nil || resp.Status != "status" {
+		t.Fatalf("status = %+v, %v", resp, err)
+	}
+	<-answered
+	if _, err := client.SendCommand(Command{Cmd: "devices"}); err == nil {
+		t.Fatal("a command slower than the timeout should fail")
+	}
+	// Answered ahead of the slow command...
+	if resp, err := client.SendCommand(Command{Cmd: "version"}); err != nil || resp.Status != "version" {
+		t.Fatalf("version = %+v, %v", resp, err)
+	}
+	for c := range answered {
+		if c == "devices" {
+			break
+		}
+	}
+	// ...whose late response is then skipped rather than taken for the
+	// next command's.
+	if resp, err := client.SendCommand(Command{Cmd: "status"}); err != nil || resp.Status != "status" {
+		t.Fatalf("status after the late response = %+v, %v", resp, err)
+	}
+}
+
+func TestClientOutOfSyncWithoutIDs(t *testing.T) {
+	sockPath, _ := startConcurrentDaemon(t, map[string]time.Duration{"devices": 200 * time.Millisecond}, false)
+	client, err := Connect(sockPath)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer client.Close()
+	client.SetCommandTimeout(50 * time.Millisecond)
+
+	if resp, err := client.SendCommand(Command{Cmd: "status"}); err != nil || resp.Status != "status" {
+		t.Fatalf("status from a daemon without IDs = %+v, %v", resp, err)
+	}
+	if _, err := client.SendCommand(Command{Cmd: "devices"}); err == nil {
+		t.Fatal("a command slower than the timeout should fail")
+	}
+	// The late devices response can't be told from the next one.
+	if _, err := client.SendCommand(Command{Cmd: "version"}); !errors.Is(err, ErrOutOfSync) {
+		t.Errorf("after a timeout without IDs, err = %v, want ErrOutOfSync", err)
+	}
+}
+
+func TestClientConcurrentCommands(t *testing.T) {
+	sockPath, _ := startConcurrentDaemon(t, map[string]time.Duration{"stop": 10 * time.Millisecond}, true)
+	client, err := Connect(sockPath)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer client.Close()
+
+	// As a tea.Batch of commands would send them.
+	cmds := []string{"status", "stop", "version", "devices", "status", "stop", "demarcate", "version"}
+	var wg sync.WaitGroup
+	for _, cmd := range cmds {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := client.SendCommand(Command{Cmd: cmd})
+			if err != nil || resp.Status != cmd {
+				t.Errorf("%s got %+v, %v", cmd, resp, err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// startMuxDaemon serves one connection that subscribes and then keeps
+// taking commands, as the Swift daemon does: each command's response,
+// with its ID echoed, follows an event, so the two interleave.
+func TestClientSendCommandCtx(t *testing.T) {
+	sockPath, _ := startConcurrentDaemon(t, map[string]time.Duration{"devices": 200 * time.Millisecond}, true)
+	client, err := Connect(sockPath)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer client.Close()
+
+	if resp, err := client.SendCommand(Command{Cmd: "status"}); err != nil || resp.Status != "status" {
+		t.Fatalf("status = %+v, %v", resp, err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := client.SendCommandCtx(ctx, Command{Cmd: "devices"}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("devices past its deadline: err = %v, want DeadlineExceeded", err)
+	}
+	done, stop := context.WithCancel(context.Background())
+	stop()
+	if _, err := client.SendCommandCtx(done, Command{Cmd: "version"}); !errors.Is(err, context.Canceled) {
+		t.Errorf("command on a cancelled context: err = %v, want Canceled", err)
+	}
+	// With IDs echoed, the late devices response is skipped and the
+	// connection carries on.
+	if resp, err := client.SendCommand(Command{Cmd: "version"}); err != nil || resp.Status != "version" {
+		t.Errorf("version after giving up on devices = %+v, %v", resp, err)
+	}
+}
+
+func TestClientReadEventCtx(t *testing.T) {
+	client, err := Connect(startMuxDaemon(t))
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := client.ReadEventCtx(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("read with nothing sent: err = %v, want DeadlineExceeded", err)
+	}
+	if _, err := client.SendCommand(Command{Cmd: "subscribe"}); err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	if ev, err := client.ReadEventCtx(context.Background()); err != nil || ev.Text != "before subscribe" {
+		t.Errorf("event after a read gave up = %+v, %v", ev, err)
+	}
+}
+
+func startMuxDaemon(t *testing.T) string {
+	t.Helper()
+	sockPath := filepath.Join(t.TempDir(), "test.sock")
+	ln, err := net.Listen("unix", sockPath)
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			var cmd Command
+			if err := json.Unmarshal(scanner.Bytes(), &cmd); err != nil {
+				return
+			}
+			if cmd.Cmd == "hangup" {
+				return
+			}
+			ev, _ := json.Marshal(Event{Event: "partial", Text: "before " + cmd.Cmd})
+			resp, _ := json.Marshal(Response{OK: true, Status: cmd.Cmd, ID: cmd.ID})
+			conn.Write(append(append(ev, '\n'), append(resp, '\n')...))
+		}
+	}()
+	return sockPath
+}
+
+func TestClientMultiplexesCommandsAndEvents(t *testing.T) {
+	client, err := Connect(startMuxDaemon(t))
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer client.Close()
+
+	if resp, err := client.SendCommand(Command{Cmd: "subscribe"}); err != nil || resp.Status != "subscribe" {
+		t.Fatalf("subscribe = %+v, %v", resp, err)
+	}
+	// Commands go out while the same connection streams events.
+	cmds := []string{"status", "devices", "version"}
+	var wg sync.WaitGroup
+	for _, cmd := range cmds {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if resp, err := client.SendCommand(Command{Cmd: cmd}); err != nil || resp.Status != cmd {
+				t.Errorf("%s got %+v, %v", cmd, resp, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	seen := map[string]bool{}
+	for range 1 + len(cmds) {
+		ev, err := client.ReadEvent()
+		if err != nil {
+			t.Fatalf("read event: %v", err)
+		}
+		seen[ev.Text] = true
+	}
+	for _, cmd := range append([]string{"subscribe"}, cmds...) {
+		if !seen["before "+cmd] {
+			t.Errorf("events = %v, missing the one before %s", seen, cmd)
+		}
+	}
+}
+
+func TestClientFailsPendingCommandsOnHangup(t *testing.T) {
+	client, err := Connect(startMuxDaemon(t))
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer client.Close()
+
+	if _, err := client.SendCommand(Command{Cmd: "hangup"}); err == nil || err.Error() != "read response: connection closed" {
+		t.Fatalf("command on a connection the daemon closed: err = %v", err)
+	}
+	if _, err := client.ReadEvent(); err == nil || err.Error() != "read event: connection closed" {
+		t.Errorf("event read after hang-up: err = %v", err)
+	}
+	if _, err := client.SendCommand(Command{Cmd: "status"}); err == nil {
+		t.Error("a command after hang-up should fail at once")
+	}
+}
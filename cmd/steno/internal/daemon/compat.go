@@ -0,0 +1,229 @@
+package daemon
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Forward-compatible (lenient) decoding.
+//
+// The TUI and the daemon ship from the same repo but are upgraded
+// independently, so either side may be a release ahead. Swift's Codable
+// already ignores unknown keys; on the Go side a plain json.Unmarshal
+// would silently drop them, and a field whose type changed would fail
+// the whole line — killing the event stream over one bad key.
+//
+// Command, Response, and Event therefore decode leniently:
+//   - Members the struct doesn't declare are kept verbatim in Extra.
+//   - A declared member whose value has the wrong JSON type is moved to
+//     Extra instead of failing the decode; the struct field stays zero.
+//
+// Extra is re-emitted on marshal, so a message round-trips without loss
+// (a proxy or recorder sitting between a newer daemon and an older TUI
+// forwards fields it doesn't understand).
+
+var (
+	commandFields  = jsonFieldNames(reflect.TypeOf(Command{}))
+	responseFields = jsonFieldNames(reflect.TypeOf(Response{}))
+	eventFields    = jsonFieldNames(reflect.TypeOf(Event{}))
+)
+
+// UnmarshalJSON implements lenient decoding for Command.
+func (c *Command) UnmarshalJSON(data []byte) error {
+	type plain Command
+	var p plain
+	extra, err := decodeLenient(data, &p, commandFields)
+	if err != nil {
+		return err
+	}
+	*c = Command(p)
+	c.Extra = extra
+	return nil
+}
+
+// MarshalJSON emits Command including any preserved Extra members.
+func (c Command) MarshalJSON() ([]byte, error) {
+	type plain Command
+	data, err := json.Marshal(plain(c))
+	if err != nil {
+		return nil, err
+	}
+	return appendExtra(data, c.Extra)
+}
+
+// UnmarshalJSON implements lenient decoding for Response.
+func (r *Response) UnmarshalJSON(data []byte) error {
+	type plain Response
+	var p plain
+	extra, err := decodeLenient(data, &p, responseFields)
+	if err != nil {
+		return err
+	}
+	*r = Response(p)
+	r.Extra = extra
+	return nil
+}
+
+// MarshalJSON emits Response including any preserved Extra members.
+func (r Response) MarshalJSON() ([]byte, error) {
+	type plain Response
+	data, err := json.Marshal(plain(r))
+	if err != nil {
+		return nil, err
+	}
+	return appendExtra(data, r.Extra)
+}
+
+// UnmarshalJSON implements lenient decoding for Event.
+func (e *Event) UnmarshalJSON(data []byte) error {
+	type plain Event
+	var p plain
+	extra, err := decodeLenient(data, &p, eventFields)
+	if err != nil {
+		return err
+	}
+	*e = Event(p)
+	e.Extra = extra
+	return nil
+}
+
+// MarshalJSON emits Event including any preserved Extra members.
+func (e Event) MarshalJSON() ([]byte, error) {
+	type plain Event
+	data, err := json.Marshal(plain(e))
+	if err != nil {
+		return nil, err
+	}
+	return appendExtra(data, e.Extra)
+}
+
+// decodeLenient decodes the JSON object in data into v (a pointer to a
+// struct without custom unmarshalers) and returns the members that were
+// unknown or type-mismatched. A non-object payload is still an error.
+func decodeLenient(data []byte, v any, known map[string]bool) (map[string]json.RawMessage, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	var extra map[string]json.RawMessage
+	for k, val := range raw {
+		if !known[k] {
+			if extra == nil {
+				extra = make(map[string]json.RawMessage)
+			}
+			extra[k] = val
+			delete(raw, k)
+		}
+	}
+
+	// Fast path: nothing unknown, decode the original bytes directly.
+	if extra == nil {
+		err := json.Unmarshal(data, v)
+		if err == nil {
+			return nil, nil
+		}
+		if !isFieldTypeError(err) {
+			return nil, err
+		}
+	}
+
+	// Slow path: decode the known members, evicting each one whose type
+	// doesn't match. Bounded by the number of members.
+	for range len(raw) + 1 {
+		filtered, err := json.Marshal(raw)
+		if err != nil {
+			return nil, err
+		}
+		reflect.ValueOf(v).Elem().SetZero()
+		err = json.Unmarshal(filtered, v)
+		if err == nil {
+			return extra, nil
+		}
+		var typeErr *json.UnmarshalTypeError
+		if !errors.As(err, &typeErr) {
+			return nil, err
+		}
+		field := topLevelField(typeErr.Field)
+		val, ok := raw[field]
+		if !ok {
+			return nil, err
+		}
+		if extra == nil {
+			extra = make(map[string]json.RawMessage)
+		}
+		extra[field] = val
+		delete(raw, field)
+	}
+	return extra, nil
+}
+
+func isFieldTypeError(err error) bool {
+	var typeErr *json.UnmarshalTypeError
+	return errors.As(err, &typeErr) && typeErr.Field != ""
+}
+
+// topLevelField maps an UnmarshalTypeError path ("devices.0") to the
+// member that carried it ("devices").
+func topLevelField(path string) string {
+	if i := strings.IndexByte(path, '.'); i >= 0 {
+		return path[:i]
+	}
+	return path
+}
+
+// appendExtra splices extra members into the JSON object data, in sorted
+// key order. Members already present in data win, so a known field with a
+// valid value is never duplicated.
+func appendExtra(data []byte, extra map[string]json.RawMessage) ([]byte, error) {
+	if len(extra) == 0 {
+		return data, nil
+	}
+	var present map[string]json.RawMessage
+	if err := json.Unmarshal(data, &present); err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(extra))
+	for k := range extra {
+		if _, dup := present[k]; !dup {
+			keys = append(keys, k)
+		}
+	}
+	if len(keys) == 0 {
+		return data, nil
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	buf.Write(data[:len(data)-1]) // drop closing brace
+	for i, k := range keys {
+		if i > 0 || len(present) > 0 {
+			buf.WriteByte(',')
+		}
+		name, _ := json.Marshal(k)
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(extra[k])
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// jsonFieldNames returns the JSON member names declared by t's tags.
+func jsonFieldNames(t reflect.Type) map[string]bool {
+	names := make(map[string]bool, t.NumField())
+	for i := range t.NumField() {
+		tag := t.Field(i).Tag.Get("json")
+		name, _, _ := strings.Cut(tag, ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		names[name] = true
+	}
+	return names
+}
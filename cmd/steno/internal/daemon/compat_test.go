@@ -0,0 +1,170 @@
+package daemon
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// fixtureLine is one recorded NDJSON message from testdata/protocol.
+type fixtureLine struct {
+	file string
+	line int
+	data []byte
+}
+
+func readFixture(t *testing.T, name string) []fixtureLine {
+	t.Helper()
+	path := filepath.Join("testdata", "protocol", name)
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open fixture: %v", err)
+	}
+	defer f.Close()
+
+	var lines []fixtureLine
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		lines = append(lines, fixtureLine{file: name, line: n, data: append([]byte(nil), scanner.Bytes()...)})
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+	return lines
+}
+
+// decodeFixture decodes a line into the message type its discriminator
+// key implies and returns the decoded value plus its Extra members.
+func decodeFixture(t *testing.T, fl fixtureLine) (any, map[string]json.RawMessage) {
+	t.Helper()
+	var probe map[string]json.RawMessage
+	if err := json.Unmarshal(fl.data, &probe); err != nil {
+		t.Fatalf("%s:%d: not a JSON object: %v", fl.file, fl.line, err)
+	}
+	switch {
+	case probe["cmd"] != nil:
+		var c Command
+		if err := json.Unmarshal(fl.data, &c); err != nil {
+			t.Fatalf("%s:%d: decode command: %v", fl.file, fl.line, err)
+		}
+		return c, c.Extra
+	case probe["event"] != nil:
+		var e Event
+		if err := json.Unmarshal(fl.data, &e); err != nil {
+			t.Fatalf("%s:%d: decode event: %v", fl.file, fl.line, err)
+		}
+		return e, e.Extra
+	default:
+		var r Response
+		if err := json.Unmarshal(fl.data, &r); err != nil {
+			t.Fatalf("%s:%d: decode response: %v", fl.file, fl.line, err)
+		}
+		return r, r.Extra
+	}
+}
+
+func assertRoundTrip(t *testing.T, fl fixtureLine, v any) {
+	t.Helper()
+	out, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("%s:%d: marshal: %v", fl.file, fl.line, err)
+	}
+	var want, got any
+	json.Unmarshal(fl.data, &want)
+	json.Unmarshal(out, &got)
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("%s:%d: round trip mismatch\n in: %s\nout: %s", fl.file, fl.line, fl.data, out)
+	}
+}
+
+// TestProtocolFixturesPreviousReleases checks that every message recorded
+// from earlier protocol revisions decodes with no leftover Extra (i.e.
+// this build still declares every field they sent) and round-trips.
+func TestProtocolFixturesPreviousReleases(t *testing.T) {
+	for _, name := range []string{"2026-02-go-tui.ndjson", "2026-04-always-on.ndjson"} {
+		for _, fl := range readFixture(t, name) {
+			v, extra := decodeFixture(t, fl)
+			if len(extra) != 0 {
+				t.Errorf("%s:%d: unexpected unknown fields %v", fl.file, fl.line, keys(extra))
+			}
+			assertRoundTrip(t, fl, v)
+		}
+	}
+}
+
+// TestProtocolFixturesFuture checks that messages from a hypothetical
+// newer daemon decode their known fields, keep the rest, and round-trip.
+func TestProtocolFixturesFuture(t *testing.T) {
+	lines := readFixture(t, "future.ndjson")
+	for _, fl := range lines {
+		v, extra := decodeFixture(t, fl)
+		if len(extra) == 0 {
+			t.Errorf("%s:%d: expected preserved unknown fields", fl.file, fl.line)
+		}
+		assertRoundTrip(t, fl, v)
+	}
+
+	cmd, _ := decodeFixture(t, lines[0])
+	if c := cmd.(Command); c.Device != "MacBook Pro Microphone" || c.Extra["micGain"] == nil || c.Extra["appAllowlist"] == nil {
+		t.Errorf("start command: %+v", c)
+	}
+
+	resp, _ := decodeFixture(t, lines[1])
+	if r := resp.(Response); !r.OK || r.SessionID == "" || r.ProtocolVersion != 3 || r.Extra["capabilities"] == nil {
+		t.Errorf("status response: %+v", r)
+	}
+
+	ev, _ := decodeFixture(t, lines[3])
+	if e := ev.(Event); e.Text != "Next item is the budget." || e.SequenceNumber == nil || *e.SequenceNumber != 7 {
+		t.Errorf("segment event: %+v", e)
+	}
+}
+
+func TestLenientDecodeTypeMismatch(t *testing.T) {
+	var r Response
+	err := json.Unmarshal([]byte(`{"ok":true,"segments":"many","devices":[1,2],"status":"recording"}`), &r)
+	if err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if !r.OK || r.Status != "recording" {
+		t.Errorf("known fields lost: %+v", r)
+	}
+	if r.Segments != nil || r.Devices != nil {
+		t.Errorf("mismatched fields should stay zero: segments=%v devices=%v", r.Segments, r.Devices)
+	}
+	if string(r.Extra["segments"]) != `"many"` || string(r.Extra["devices"]) != `[1,2]` {
+		t.Errorf("extra = %v", r.Extra)
+	}
+}
+
+func TestLenientDecodeRejectsNonObject(t *testing.T) {
+	var e Event
+	if err := json.Unmarshal([]byte(`["partial"]`), &e); err == nil {
+		t.Error("expected error for non-object payload")
+	}
+}
+
+func TestMarshalExtraDoesNotDuplicateKnownField(t *testing.T) {
+	r := Response{OK: true, Extra: map[string]json.RawMessage{"ok": json.RawMessage(`false`), "newField": json.RawMessage(`1`)}}
+	data, err := json.Marshal(r)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if string(data) != `{"ok":true,"newField":1}` {
+		t.Errorf("got %s", data)
+	}
+}
+
+func keys(m map[string]json.RawMessage) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	return out
+}
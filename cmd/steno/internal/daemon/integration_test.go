@@ -3,6 +3,7 @@ package daemon
 import (
 	"fmt"
 	"os"
+	"strings"
 	"testing"
 	"time"
 )
@@ -176,3 +177,87 @@ func TestLiveDaemonEventStream(t *testing.T) {
 		t.Error("expected at least some events during 3s recording")
 	}
 }
+
+// TestLiveInjectAudio drives a test-mode daemon (`steno-daemon run
+// --test-mode`) end to end: start a session, inject the WAV named by
+// STENO_INJECT_AUDIO, and wait for its transcript on the event stream.
+// STENO_INJECT_EXPECT, when set, is a word the transcript must contain.
+// The socket is STENO_SOCKET, or the default. Skipped without a file.
+func TestLiveInjectAudio(t *testing.T) {
+	audio := os.Getenv("STENO_INJECT_AUDIO")
+	if audio == "" {
+		t.Skip("STENO_INJECT_AUDIO not set")
+	}
+	sockPath := os.Getenv("STENO_SOCKET")
+	if sockPath == "" {
+		sockPath = SocketPath()
+	}
+	if _, err := os.Stat(sockPath); os.IsNotExist(err) {
+		t.Skip("daemon not running")
+	}
+
+	cmdClient, err := Connect(sockPath)
+	if err != nil {
+		t.Fatalf("connect cmd: %v", err)
+	}
+	defer cmdClient.Close()
+	evClient, err := Connect(sockPath)
+	if err != nil {
+		t.Fatalf("connect ev: %v", err)
+	}
+	defer evClient.Close()
+
+	resp, err := evClient.SendCommand(Command{Cmd: "subscribe", Events: []string{"segment"}})
+	if err != nil || !resp.OK {
+		t.Fatalf("subscribe: %v %s", err, resp.Error)
+	}
+	resp, err = cmdClient.SendCommand(StartCmd(StartOptions{}))
+	if err != nil || !resp.OK {
+		t.Fatalf("start: %v %s", err, resp.Error)
+	}
+	sessionID := resp.SessionID
+	defer cmdClient.SendCommand(Command{Cmd: "stop"})
+
+	resp, err = cmdClient.SendCommand(InjectAudioCmd(audio))
+	if err != nil {
+		t.Fatalf("inject_audio: %v", err)
+	}
+	if !resp.OK {
+		t.Fatalf("inject_audio failed: %s (is the daemon in --test-mode?)", resp.Error)
+	}
+
+	segments := make(chan Event)
+	go func() {
+		for {
+			ev, err := evClient.ReadEvent()
+			if err != nil {
+				close(segments)
+				return
+			}
+			if ev.Event == "segment" && ev.Source == "microphone" {
+				segments <- ev
+			}
+		}
+	}()
+
+	expect := strings.ToLower(os.Getenv("STENO_INJECT_EXPECT"))
+	var heard []string
+	deadline := time.After(30 * time.Second)
+	for {
+		select {
+		case ev, ok := <-segments:
+			if !ok {
+				t.Fatal("event stream closed before a segment arrived")
+			}
+			if ev.SessionID != "" && ev.SessionID != sessionID {
+				continue
+			}
+			heard = append(heard, ev.Text)
+			if expect == "" || strings.Contains(strings.ToLower(strings.Join(heard, " ")), expect) {
+				return
+			}
+		case <-deadline:
+			t.Fatalf("no segment containing %q within 30s; heard %q", expect, heard)
+		}
+	}
+}
@@ -251,6 +251,12 @@ func (m *Manager) startupError(cause error) error {
 	return fmt.Errorf("daemon did not start: %w", cause)
 }
 
+// LogTail returns the last n lines of the daemon log file, or "" when it
+// cannot be read. Used by bug reports.
+func (m *Manager) LogTail(n int) string {
+	return m.readLogTail(n)
+}
+
 // readLogTail returns the last n lines of the daemon log file.
 func (m *Manager) readLogTail(n int) string {
 	data, err := os.ReadFile(m.logPath())
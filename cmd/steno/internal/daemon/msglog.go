@@ -0,0 +1,58 @@
+package daemon
+
+import (
+	"sync"
+	"time"
+)
+
+// LoggedMessage is one NDJSON line that crossed the socket.
+type LoggedMessage struct {
+	Time time.Time
+	// Sent is true for client → daemon lines (commands).
+	Sent bool
+	Line []byte
+}
+
+// MessageLog is a fixed-size ring of the most recent protocol lines, kept
+// for bug reports. Safe for concurrent use by the command and event
+// clients.
+type MessageLog struct {
+	mu    sync.Mutex
+	buf   []LoggedMessage
+	next  int
+	full  bool
+	clock func() time.Time
+}
+
+// NewMessageLog returns a log holding the last n messages.
+func NewMessageLog(n int) *MessageLog {
+	return &MessageLog{buf: make([]LoggedMessage, max(n, 1)), clock: time.Now}
+}
+
+func (l *MessageLog) record(sent bool, line []byte) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.buf[l.next] = LoggedMessage{Time: l.clock(), Sent: sent, Line: append([]byte(nil), line...)}
+	l.next = (l.next + 1) % len(l.buf)
+	if l.next == 0 {
+		l.full = true
+	}
+}
+
+// Messages returns the retained messages, oldest first.
+func (l *MessageLog) Messages() []LoggedMessage {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.full {
+		return append([]LoggedMessage(nil), l.buf[:l.next]...)
+	}
+	out := make([]LoggedMessage, 0, len(l.buf))
+	out = append(out, l.buf[l.next:]...)
+	return append(out, l.buf[:l.next]...)
+}
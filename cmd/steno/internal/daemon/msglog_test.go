@@ -0,0 +1,31 @@
+package daemon
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestMessageLogKeepsMostRecent(t *testing.T) {
+	l := NewMessageLog(3)
+	for i := range 5 {
+		l.record(i%2 == 0, []byte(fmt.Sprintf("line-%d", i)))
+	}
+	msgs := l.Messages()
+	if len(msgs) != 3 {
+		t.Fatalf("got %d messages, want 3", len(msgs))
+	}
+	for i, want := range []string{"line-2", "line-3", "line-4"} {
+		if string(msgs[i].Line) != want {
+			t.Errorf("msgs[%d] = %q, want %q", i, msgs[i].Line, want)
+		}
+	}
+	if !msgs[0].Sent || msgs[1].Sent {
+		t.Error("Sent flag not preserved")
+	}
+
+	var nilLog *MessageLog
+	nilLog.record(true, []byte("x")) // must not panic
+	if nilLog.Messages() != nil {
+		t.Error("nil log should have no messages")
+	}
+}
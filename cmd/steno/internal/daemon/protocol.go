@@ -2,6 +2,15 @@
 // steno-daemon over a Unix socket using NDJSON.
 package daemon
 
+import "encoding/json"
+
+// ProtocolVersion is the revision of the protocol this build speaks,
+// exchanged with the daemon's on `version` (see VersionCmd). Bump it, and
+// BuildInfo.protocolVersion on the Swift side, when a change needs both
+// sides to understand it; new optional fields that older peers can
+// ignore don't. 1 is the protocol before versioning; 2 adds command IDs.
+const ProtocolVersion = 2
+
 // Command is sent from a client to the daemon.
 //
 // Mirrors `daemon/Sources/StenoDaemon/Socket/DaemonProtocol.swift` —
@@ -24,6 +33,30 @@ type Command struct {
 	// auto-resume timer. Mutually exclusive with AutoResumeSeconds.
 	// (U10, privacy-critical)
 	Indefinite *bool `json:"indefinite,omitempty"`
+
+	// Path is the audio file for `inject_audio`, read by the daemon.
+	Path string `json:"path,omitempty"`
+
+	// Texts are the transcript segments for `clean_verbatim`, in order.
+	Texts []string `json:"texts,omitempty"`
+
+	// ID numbers the command on its connection; the daemon echoes it in
+	// the response. Set by Client.SendCommand.
+	ID int `json:"id,omitempty"`
+
+	// ProtocolVersion is the client's ProtocolVersion, sent with
+	// `version`.
+	ProtocolVersion int `json:"protocolVersion,omitempty"`
+
+	// SinceSessionID and SinceSequence name the last segment a
+	// resubscribing client saw, for `subscribe`. The daemon replays the
+	// `segment` events after it before going live. See SubscribeSinceCmd.
+	SinceSessionID string `json:"sinceSessionId,omitempty"`
+	SinceSequence  int    `json:"sinceSequence,omitempty"`
+
+	// Extra holds JSON members this build doesn't know about (or whose
+	// type didn't match), preserved for round-tripping. See compat.go.
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 // Response is returned by the daemon after processing a command.
@@ -51,6 +84,62 @@ type Response struct {
 	// auto-resume timer will fire. Nil for indefinite pauses or when
 	// not paused. (U10)
 	PauseExpiresAt *float64 `json:"pauseExpiresAt,omitempty"`
+
+	// ForegroundApp is the app that had focus when the current session
+	// opened, as reported by `status`. Empty when unknown or from
+	// daemons that predate it.
+	ForegroundApp string `json:"foregroundApp,omitempty"`
+
+	// LastSegmentAt is the Unix timestamp (seconds) of the current
+	// session's most recent finalized segment, as reported by `status`.
+	// Nil before the first segment and from daemons that predate it.
+	LastSegmentAt *float64 `json:"lastSegmentAt,omitempty"`
+
+	// Locale is the recognition locale, on `status` and `set_locale`
+	// responses (while paused, the locale the next session opens with).
+	// Empty when idle and from daemons that predate it.
+	Locale string `json:"locale,omitempty"`
+
+	// Armed is true while voice-activated start is armed: the daemon is
+	// idle, metering the mic, and starts a session on sustained speech.
+	// On `status`, `arm` and `disarm` responses.
+	Armed *bool `json:"armed,omitempty"`
+
+	// CallApp is the conferencing app whose call was on when the current
+	// session opened, as reported by `status`. Empty outside calls and
+	// from daemons without call detection.
+	CallApp string `json:"callApp,omitempty"`
+
+	// ThermalState ("nominal", "fair", "serious", "critical"), LowPower
+	// (Low Power Mode) and SummariesDeferred (rolling summaries held back
+	// because of them, per the daemon's deferSummaries setting) report
+	// resource pressure on `status`. Empty and nil from daemons that
+	// predate them.
+	ThermalState      string `json:"thermalState,omitempty"`
+	LowPower          *bool  `json:"lowPower,omitempty"`
+	SummariesDeferred *bool  `json:"summariesDeferred,omitempty"`
+
+	// Version and Build answer the `version` command: the daemon's
+	// release version ("0.1.0") and a free-form build description
+	// (configuration, OS). Empty from daemons that predate the command.
+	Version string `json:"version,omitempty"`
+	Build   string `json:"build,omitempty"`
+
+	// ProtocolVersion is the daemon's protocol revision, on `version`
+	// responses. Zero from daemons that predate versioning.
+	ProtocolVersion int `json:"protocolVersion,omitempty"`
+
+	// Texts answers `clean_verbatim`: the cleaned segments, one per
+	// requested text and in the same order.
+	Texts []string `json:"texts,omitempty"`
+
+	// ID is the ID of the command answered. Zero from daemons that
+	// predate it.
+	ID int `json:"id,omitempty"`
+
+	// Extra holds JSON members this build doesn't know about (or whose
+	// type didn't match), preserved for round-tripping. See compat.go.
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
 // Event is streamed from the daemon to subscribed clients.
@@ -69,11 +158,59 @@ type Event struct {
 	ModelProcessing *bool    `json:"modelProcessing,omitempty"`
 	StartedAt       *float64 `json:"startedAt,omitempty"`
 
+	// EndedAt is when a `segment` ended, so its length is known. Daemons
+	// that predate it omit it.
+	EndedAt *float64 `json:"endedAt,omitempty"`
+
 	// Pause-state event payload. The daemon emits an `event:"pause_state"`
 	// on every transition into and out of `.paused`. (U10)
 	Paused             *bool    `json:"paused,omitempty"`
 	PausedIndefinitely *bool    `json:"pausedIndefinitely,omitempty"`
 	PauseExpiresAt     *float64 `json:"pauseExpiresAt,omitempty"`
+
+	// Speaker is the diarized speaker label ("Speaker 1") of a `segment`,
+	// empty on segments the daemon hasn't diarized. An `event:"speaker"`
+	// carries it too, with Source naming the stream they were heard on
+	// and an empty label meaning nobody is speaking; today's daemon sends
+	// none, so the TUI follows segments' labels.
+	Speaker string `json:"speaker,omitempty"`
+
+	// Locale is the recognizer's locale on a `segment` (set_locale can
+	// switch it mid-session), or the new locale on an `event:"locale"`.
+	Locale string `json:"locale,omitempty"`
+
+	// Topics is the session's full topic list, in order, on an
+	// `event:"topics"`. Daemons that predate it send only Title.
+	Topics []EventTopic `json:"topics,omitempty"`
+
+	// Armed is the new state on an `event:"armed"`. An armed daemon that
+	// hears speech sends `event:"auto_started"` with the new SessionID.
+	Armed *bool `json:"armed,omitempty"`
+
+	// App is the conferencing app on an `event:"call_started"` or
+	// `event:"call_ended"`. AutoRecorded, on call_started, is true when
+	// the daemon opened a session for the call (callDetection "auto").
+	App          string `json:"app,omitempty"`
+	AutoRecorded *bool  `json:"autoRecorded,omitempty"`
+
+	// ThermalState, LowPower and SummariesDeferred are the new pressure
+	// on an `event:"pressure"`, as on the `status` response.
+	ThermalState      string `json:"thermalState,omitempty"`
+	LowPower          *bool  `json:"lowPower,omitempty"`
+	SummariesDeferred *bool  `json:"summariesDeferred,omitempty"`
+
+	// Extra holds JSON members this build doesn't know about (or whose
+	// type didn't match), preserved for round-tripping. See compat.go.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// EventTopic is one topic on a `topics` event.
+type EventTopic struct {
+	ID                string `json:"id"`
+	Title             string `json:"title"`
+	Summary           string `json:"summary"`
+	SegmentRangeStart int    `json:"segmentRangeStart"`
+	SegmentRangeEnd   int    `json:"segmentRangeEnd"`
 }
 
 // BoolPtr returns a pointer to a bool value. Convenience for building commands.
@@ -103,7 +240,79 @@ func ResumeCmd() Command {
 	return Command{Cmd: "resume"}
 }
 
+// StartOptions are the optional settings for `start`.
+type StartOptions struct {
+	Locale      string
+	Device      string
+	SystemAudio bool
+}
+
+// StartCmd builds a `start` command from opts.
+func StartCmd(opts StartOptions) Command {
+	return Command{
+		Cmd:         "start",
+		Locale:      opts.Locale,
+		Device:      opts.Device,
+		SystemAudio: BoolPtr(opts.SystemAudio),
+	}
+}
+
+// VersionCmd builds a `version` command, which also tells the daemon
+// this client's ProtocolVersion.
+func VersionCmd() Command {
+	return Command{Cmd: "version", ProtocolVersion: ProtocolVersion}
+}
+
+// SubscribeSinceCmd builds a `subscribe` command for a client that last
+// saw segment seq of sessionID: the daemon first replays, as `segment`
+// events, the rest of that session and, if another has opened since, all
+// of the current one. Replayed segments may repeat ones already seen.
+// With no sessionID it is a plain subscribe. A daemon that predates
+// replay ignores the fields and only goes live.
+func SubscribeSinceCmd(sessionID string, seq int) Command {
+	return Command{Cmd: "subscribe", SinceSessionID: sessionID, SinceSequence: seq}
+}
+
 // DemarcateCmd builds a `demarcate` command (atomic session boundary).
 func DemarcateCmd() Command {
 	return Command{Cmd: "demarcate"}
 }
+
+// SetLocaleCmd builds a `set_locale` command: switch recognition to
+// locale without ending the session.
+func SetLocaleCmd(locale string) Command {
+	return Command{Cmd: "set_locale", Locale: locale}
+}
+
+// ArmCmd builds an `arm` command: start a session with opts' locale,
+// device and system audio once sustained speech is heard. The daemon
+// must be idle.
+func ArmCmd(opts StartOptions) Command {
+	return Command{
+		Cmd:         "arm",
+		Locale:      opts.Locale,
+		Device:      opts.Device,
+		SystemAudio: BoolPtr(opts.SystemAudio),
+	}
+}
+
+// DisarmCmd builds a `disarm` command.
+func DisarmCmd() Command {
+	return Command{Cmd: "disarm"}
+}
+
+// InjectAudioCmd builds an `inject_audio` command: feed the audio file at
+// path into the recording session in place of the mic, as if it were
+// being spoken. Only a daemon run with `--test-mode` accepts it; the
+// transcript arrives as ordinary partial and segment events.
+func InjectAudioCmd(path string) Command {
+	return Command{Cmd: "inject_audio", Path: path}
+}
+
+// CleanVerbatimCmd builds a `clean_verbatim` command: have the daemon's
+// language model rewrite each of texts as clean verbatim (filler words
+// dropped, casing and punctuation fixed, meaning kept). The response's
+// Texts line up with texts; a daemon without a model available refuses.
+func CleanVerbatimCmd(texts []string) Command {
+	return Command{Cmd: "clean_verbatim", Texts: texts}
+}
@@ -239,6 +239,19 @@ func TestEventTopics(t *testing.T) {
 	}
 }
 
+func TestEventTopicsPayload(t *testing.T) {
+	j := `{"event":"topics","title":"Budget","topics":[{"id":"t1","title":"Budget","summary":"Q3.","segmentRangeStart":1,"segmentRangeEnd":4}]}`
+
+	var ev Event
+	if err := json.Unmarshal([]byte(j), &ev); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	want := EventTopic{ID: "t1", Title: "Budget", Summary: "Q3.", SegmentRangeStart: 1, SegmentRangeEnd: 4}
+	if len(ev.Topics) != 1 || ev.Topics[0] != want || len(ev.Extra) != 0 {
+		t.Errorf("topics = %+v, extra = %v", ev.Topics, ev.Extra)
+	}
+}
+
 func TestBoolPtr(t *testing.T) {
 	p := BoolPtr(true)
 	if p == nil || !*p {
@@ -318,6 +331,68 @@ func TestDemarcateCmd(t *testing.T) {
 	}
 }
 
+func TestSetLocaleCmd(t *testing.T) {
+	data, err := json.Marshal(SetLocaleCmd("fr_FR"))
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if string(data) != `{"cmd":"set_locale","locale":"fr_FR"}` {
+		t.Errorf("SetLocaleCmd = %s", data)
+	}
+}
+
+func TestSubscribeSinceCmd(t *testing.T) {
+	data, err := json.Marshal(SubscribeSinceCmd("sess-1", 42))
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if string(data) != `{"cmd":"subscribe","sinceSessionId":"sess-1","sinceSequence":42}` {
+		t.Errorf("SubscribeSinceCmd = %s", data)
+	}
+	// Nothing seen yet: a plain subscribe.
+	if data, _ := json.Marshal(SubscribeSinceCmd("", 0)); string(data) != `{"cmd":"subscribe"}` {
+		t.Errorf("SubscribeSinceCmd(\"\", 0) = %s", data)
+	}
+}
+
+func TestInjectAudioCmd(t *testing.T) {
+	data, err := json.Marshal(InjectAudioCmd("/tmp/hello.wav"))
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if string(data) != `{"cmd":"inject_audio","path":"/tmp/hello.wav"}` {
+		t.Errorf("InjectAudioCmd = %s", data)
+	}
+}
+
+func TestCleanVerbatimCmd(t *testing.T) {
+	data, err := json.Marshal(CleanVerbatimCmd([]string{"um so", "yeah"}))
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if string(data) != `{"cmd":"clean_verbatim","texts":["um so","yeah"]}` {
+		t.Errorf("CleanVerbatimCmd = %s", data)
+	}
+}
+
+func TestArmCmd(t *testing.T) {
+	data, err := json.Marshal(ArmCmd(StartOptions{Device: "USB Mic", SystemAudio: true}))
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if string(data) != `{"cmd":"arm","device":"USB Mic","systemAudio":true}` {
+		t.Errorf("ArmCmd = %s", data)
+	}
+
+	var ev Event
+	if err := json.Unmarshal([]byte(`{"event":"armed","armed":false}`), &ev); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if ev.Armed == nil || *ev.Armed {
+		t.Errorf("Armed = %v, want false", ev.Armed)
+	}
+}
+
 func TestResponsePauseFields(t *testing.T) {
 	j := `{"ok":true,"paused":true,"pausedIndefinitely":false,"pauseExpiresAt":1700000000.5}`
 
@@ -0,0 +1,92 @@
+package db
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// AddAnnotation attaches an annotation to the segment with sequence number
+// seq in sessionID. Requires a Store opened with OpenClient.
+func (s *Store) AddAnnotation(sessionID string, seq int, kind, body string) (*Annotation, error) {
+	switch kind {
+	case AnnotationBookmark, AnnotationNote, AnnotationReaction:
+	default:
+		return nil, fmt.Errorf("add annotation: unknown kind %q", kind)
+	}
+	if kind != AnnotationBookmark && body == "" {
+		return nil, fmt.Errorf("add annotation: %s needs a body", kind)
+	}
+
+	a := Annotation{Kind: kind, Body: body, CreatedAt: time.Now()}
+	var startedAt float64
+	err := s.db.QueryRow(`SELECT id, sessionId, sequenceNumber, text, startedAt, source FROM segments
+		WHERE sessionId = ? AND sequenceNumber = ?`, sessionID, seq).
+		Scan(&a.SegmentID, &a.SessionID, &a.SegmentSeq, &a.SegmentText, &startedAt, &a.SegmentSource)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("add annotation: session %s has no segment #%d", sessionID, seq)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("add annotation: %w", err)
+	}
+	a.SegmentStartedAt = timeFromUnix(startedAt)
+	a.ID = newID()
+
+	var bodyVal sql.NullString
+	if body != "" {
+		bodyVal = sql.NullString{String: body, Valid: true}
+	}
+	if _, err := s.db.Exec(`INSERT INTO annotations (id, session_id, segment_id, kind, body, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)`, a.ID, a.SessionID, a.SegmentID, a.Kind, bodyVal, unixFromTime(a.CreatedAt)); err != nil {
+		return nil, fmt.Errorf("add annotation: %w", err)
+	}
+	return &a, nil
+}
+
+// AnnotationsForSession returns a session's annotations in transcript
+// order (segment sequence, then creation time). Empty when the table
+// does not exist yet.
+func (s *Store) AnnotationsForSession(sessionID string) ([]Annotation, error) {
+	ok, err := s.hasTable("annotations")
+	if err != nil || !ok {
+		return nil, err
+	}
+	rows, err := s.db.Query(`
+		SELECT a.id, a.session_id, a.segment_id, a.kind, a.body, a.created_at,
+			g.sequenceNumber, g.text, g.startedAt, g.source
+		FROM annotations a JOIN segments g ON g.id = a.segment_id
+		WHERE a.session_id = ?
+		ORDER BY g.sequenceNumber, a.created_at
+	`, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("query annotations: %w", err)
+	}
+	defer rows.Close()
+
+	var out []Annotation
+	for rows.Next() {
+		var a Annotation
+		var body sql.NullString
+		var createdAt, startedAt float64
+		if err := rows.Scan(&a.ID, &a.SessionID, &a.SegmentID, &a.Kind, &body, &createdAt,
+			&a.SegmentSeq, &a.SegmentText, &startedAt, &a.SegmentSource); err != nil {
+			return nil, fmt.Errorf("scan annotation: %w", err)
+		}
+		a.Body = body.String
+		a.CreatedAt = timeFromUnix(createdAt)
+		a.SegmentStartedAt = timeFromUnix(startedAt)
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
+
+// newID returns a random UUIDv4 string, the same shape the daemon uses
+// for its row IDs.
+func newID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%X-%X-%X-%X-%X", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
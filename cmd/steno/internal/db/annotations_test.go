@@ -0,0 +1,45 @@
+package db
+
+import "testing"
+
+func TestAnnotationsRoundTrip(t *testing.T) {
+	rawDB := createTestDB(t)
+	defer rawDB.Close()
+	seedTestData(t, rawDB)
+	store := &Store{db: rawDB}
+
+	// Readers tolerate the table not existing yet.
+	if anns, err := store.AnnotationsForSession("sess-1"); err != nil || anns != nil {
+		t.Fatalf("before schema: %v, %v", anns, err)
+	}
+	if err := store.EnsureClientSchema(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := store.AddAnnotation("sess-1", 2, AnnotationNote, "follow up"); err != nil {
+		t.Fatalf("AddAnnotation note: %v", err)
+	}
+	if _, err := store.AddAnnotation("sess-1", 1, AnnotationBookmark, ""); err != nil {
+		t.Fatalf("AddAnnotation bookmark: %v", err)
+	}
+	if _, err := store.AddAnnotation("sess-1", 99, AnnotationBookmark, ""); err == nil {
+		t.Error("expected error for missing segment")
+	}
+	if _, err := store.AddAnnotation("sess-1", 1, AnnotationReaction, ""); err == nil {
+		t.Error("expected error for reaction without body")
+	}
+
+	anns, err := store.AnnotationsForSession("sess-1")
+	if err != nil {
+		t.Fatalf("AnnotationsForSession: %v", err)
+	}
+	if len(anns) != 2 {
+		t.Fatalf("got %d annotations, want 2", len(anns))
+	}
+	if anns[0].SegmentSeq != 1 || anns[0].Kind != AnnotationBookmark {
+		t.Errorf("anns[0] = %+v, want bookmark on #1 first", anns[0])
+	}
+	if anns[1].Body != "follow up" || anns[1].SegmentText == "" {
+		t.Errorf("anns[1] = %+v", anns[1])
+	}
+}
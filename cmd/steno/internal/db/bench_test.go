@@ -0,0 +1,89 @@
+package db
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jwulff/steno/internal/perf"
+)
+
+// benchStore seeds 20 sessions of 500 segments each (10k segments).
+func benchStore(b *testing.B) *Store {
+	b.Helper()
+	rawDB := createTestDB(b)
+	b.Cleanup(func() { rawDB.Close() })
+
+	tx, err := rawDB.Begin()
+	if err != nil {
+		b.Fatal(err)
+	}
+	base := 1710000000.0
+	for s := range 20 {
+		id := fmt.Sprintf("sess-%d", s)
+		start := base + float64(s)*7200
+		tx.Exec(`INSERT INTO sessions (id, locale, startedAt, endedAt, status, createdAt) VALUES (?, 'en_US', ?, ?, 'completed', ?)`,
+			id, start, start+3600, start)
+		for i := 1; i <= 500; i++ {
+			tx.Exec(`INSERT INTO segments (id, sessionId, text, startedAt, endedAt, sequenceNumber, createdAt, source) VALUES (?, ?, ?, ?, ?, ?, ?, 'microphone')`,
+				fmt.Sprintf("%s-seg-%d", id, i), id,
+				fmt.Sprintf("Segment %d of session %d about the quarterly roadmap.", i, s),
+				start+float64(i)*5, start+float64(i)*5+4, i, start+float64(i)*5)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		b.Fatal(err)
+	}
+	return &Store{db: rawDB}
+}
+
+func BenchmarkListSessions(b *testing.B) {
+	store := benchStore(b)
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := store.ListSessions(20, nil, nil, ""); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSegmentsForSession(b *testing.B) {
+	store := benchStore(b)
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := store.SegmentsForSession("sess-10", 500, 0); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkSegmentsAfter reads the last page of a session, where an
+// offset page would step over the rest first.
+func BenchmarkSegmentsAfter(b *testing.B) {
+	store := benchStore(b)
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := store.SegmentsAfter("sess-10", 450, 50); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSearchSegments(b *testing.B) {
+	store := benchStore(b)
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := store.SearchSegments("roadmap", "", 50); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestPerformanceBudgets(t *testing.T) {
+	perf.Check(t, []perf.Budget{
+		{Name: "ListSessions/20", Bench: BenchmarkListSessions, Max: 50 * time.Millisecond},
+		{Name: "SegmentsForSession/500", Bench: BenchmarkSegmentsForSession, Max: 50 * time.Millisecond},
+		{Name: "SegmentsAfter/50", Bench: BenchmarkSegmentsAfter, Max: 10 * time.Millisecond},
+		{Name: "SearchSegments/10k", Bench: BenchmarkSearchSegments, Max: 100 * time.Millisecond},
+	})
+}
@@ -0,0 +1,76 @@
+package db
+
+import (
+	"fmt"
+	"time"
+)
+
+// SegmentCleanup is a clean-verbatim rewrite of a segment: the text an
+// export's cleanup pass produced from Source, the segment's text at the
+// time. A rewrite whose Source no longer matches the segment is stale.
+type SegmentCleanup struct {
+	Seq    int
+	Source string
+	Text   string
+}
+
+// SegmentCleanups returns a session's clean-verbatim rewrites by segment
+// sequence number. Empty when the table does not exist yet.
+func (s *Store) SegmentCleanups(sessionID string) (map[int]SegmentCleanup, error) {
+	ok, err := s.hasTable("segment_cleanups")
+	if err != nil || !ok {
+		return nil, err
+	}
+	rows, err := s.db.Query(`
+		SELECT g.sequenceNumber, c.source, c.text
+		FROM segment_cleanups c JOIN segments g ON g.id = c.segment_id
+		WHERE c.session_id = ?
+	`, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("query segment cleanups: %w", err)
+	}
+	defer rows.Close()
+
+	out := make(map[int]SegmentCleanup)
+	for rows.Next() {
+		var c SegmentCleanup
+		if err := rows.Scan(&c.Seq, &c.Source, &c.Text); err != nil {
+			return nil, fmt.Errorf("scan segment cleanup: %w", err)
+		}
+		out[c.Seq] = c
+	}
+	return out, rows.Err()
+}
+
+// SaveSegmentCleanups records clean-verbatim rewrites of a session's
+// segments, replacing earlier ones. Rewrites of segments the session no
+// longer has are skipped. Requires a Store opened with OpenClient.
+func (s *Store) SaveSegmentCleanups(sessionID string, cleanups []SegmentCleanup) error {
+	if len(cleanups) == 0 {
+		return nil
+	}
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("save segment cleanups: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := unixFromTime(time.Now())
+	for _, c := range cleanups {
+		_, err := tx.Exec(`
+			INSERT INTO segment_cleanups (segment_id, session_id, source, text, cleaned_at)
+			SELECT id, sessionId, ?, ?, ? FROM segments WHERE sessionId = ? AND sequenceNumber = ?
+			ON CONFLICT(segment_id) DO UPDATE SET
+				source = excluded.source,
+				text = excluded.text,
+				cleaned_at = excluded.cleaned_at
+		`, c.Source, c.Text, now, sessionID, c.Seq)
+		if err != nil {
+			return fmt.Errorf("save segment cleanup #%d: %w", c.Seq, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("save segment cleanups: %w", err)
+	}
+	return nil
+}
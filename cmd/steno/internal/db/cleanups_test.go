@@ -0,0 +1,39 @@
+package db
+
+import "testing"
+
+func TestSegmentCleanupsRoundTrip(t *testing.T) {
+	rawDB := createTestDB(t)
+	defer rawDB.Close()
+	seedTestData(t, rawDB)
+	store := &Store{db: rawDB}
+
+	// Readers tolerate the table not existing yet.
+	if got, err := store.SegmentCleanups("sess-1"); err != nil || len(got) != 0 {
+		t.Fatalf("before schema: %v, %v", got, err)
+	}
+	if err := store.EnsureClientSchema(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.SaveSegmentCleanups("sess-1", []SegmentCleanup{
+		{Seq: 1, Source: "um hello", Text: "Hello."},
+		{Seq: 2, Source: "so yeah", Text: "So, yeah."},
+		{Seq: 99, Source: "gone", Text: "Gone."},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SaveSegmentCleanups("sess-1", []SegmentCleanup{{Seq: 2, Source: "so yeah ok", Text: "So, yeah. OK."}}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := store.SegmentCleanups("sess-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[1].Text != "Hello." || got[2] != (SegmentCleanup{Seq: 2, Source: "so yeah ok", Text: "So, yeah. OK."}) {
+		t.Errorf("cleanups = %+v; want #1 and the latest #2, without the missing segment", got)
+	}
+	if other, _ := store.SegmentCleanups("sess-2"); len(other) != 0 {
+		t.Errorf("sess-2 cleanups = %+v, want none", other)
+	}
+}
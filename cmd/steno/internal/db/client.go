@@ -0,0 +1,209 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// clientSchema declares the tables owned by the steno Go binary rather
+// than the daemon. The daemon's GRDB migrator never touches them, so they
+// are created idempotently here instead of through a numbered migration.
+// See schema/README.md ("Client-owned tables").
+//
+// Every reader of these tables must tolerate their absence: the MCP
+// server and a TUI that has never run against this database only ever
+// see the daemon's schema.
+const clientSchema = `
+	CREATE TABLE IF NOT EXISTS session_metadata (
+		session_id   TEXT PRIMARY KEY REFERENCES sessions(id) ON DELETE CASCADE,
+		device       TEXT,
+		system_audio INTEGER,
+		updated_at   REAL NOT NULL,
+		foreground_app TEXT,
+		meeting_url    TEXT,
+		consent_acknowledged_at REAL
+	);
+	CREATE INDEX IF NOT EXISTS idx_session_metadata_device ON session_metadata(device);
+
+	CREATE TABLE IF NOT EXISTS annotations (
+		id         TEXT PRIMARY KEY,
+		session_id TEXT NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+		segment_id TEXT NOT NULL REFERENCES segments(id) ON DELETE CASCADE,
+		kind       TEXT NOT NULL,
+		body       TEXT,
+		created_at REAL NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS idx_annotations_session ON annotations(session_id, created_at);
+
+	CREATE TABLE IF NOT EXISTS topic_edits (
+		topic_id  TEXT PRIMARY KEY REFERENCES topics(id) ON DELETE CASCADE,
+		title     TEXT NOT NULL,
+		summary   TEXT NOT NULL,
+		edited_at REAL NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS segment_edits (
+		segment_id TEXT PRIMARY KEY REFERENCES segments(id) ON DELETE CASCADE,
+		session_id TEXT NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+		text       TEXT NOT NULL,
+		edited_at  REAL NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS idx_segment_edits_session ON segment_edits(session_id);
+
+	CREATE TABLE IF NOT EXISTS decisions (
+		segment_id TEXT PRIMARY KEY REFERENCES segments(id) ON DELETE CASCADE,
+		session_id TEXT NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+		cue        TEXT NOT NULL,
+		created_at REAL NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS idx_decisions_session ON decisions(session_id);
+
+	CREATE TABLE IF NOT EXISTS speaker_names (
+		session_id TEXT NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+		label      TEXT NOT NULL,
+		name       TEXT NOT NULL,
+		PRIMARY KEY (session_id, label)
+	);
+
+	CREATE TABLE IF NOT EXISTS saved_searches (
+		name           TEXT PRIMARY KEY,
+		text           TEXT NOT NULL,
+		sources        TEXT,
+		speakers       TEXT,
+		min_confidence REAL,
+		scope          TEXT NOT NULL,
+		alert          INTEGER NOT NULL DEFAULT 0,
+		created_at     REAL NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS dictation_macros (
+		id          INTEGER PRIMARY KEY AUTOINCREMENT,
+		profile     TEXT NOT NULL,
+		pattern     TEXT NOT NULL,
+		replacement TEXT NOT NULL,
+		created_at  REAL NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS idx_dictation_macros_profile ON dictation_macros(profile, id);
+
+	CREATE TABLE IF NOT EXISTS session_quality (
+		session_id       TEXT PRIMARY KEY REFERENCES sessions(id) ON DELETE CASCADE,
+		segments         INTEGER NOT NULL,
+		low_confidence   INTEGER NOT NULL,
+		clipped          INTEGER NOT NULL,
+		gaps             INTEGER NOT NULL,
+		gap_seconds      REAL NOT NULL,
+		duration_seconds REAL NOT NULL,
+		device           TEXT,
+		system_audio     INTEGER,
+		created_at       REAL NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS idx_session_quality_created ON session_quality(created_at);
+
+	CREATE TABLE IF NOT EXISTS session_notes (
+		session_id TEXT PRIMARY KEY REFERENCES sessions(id) ON DELETE CASCADE,
+		body       TEXT NOT NULL,
+		updated_at REAL NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS segment_cleanups (
+		segment_id TEXT PRIMARY KEY REFERENCES segments(id) ON DELETE CASCADE,
+		session_id TEXT NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+		source     TEXT NOT NULL,
+		text       TEXT NOT NULL,
+		cleaned_at REAL NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS idx_segment_cleanups_session ON segment_cleanups(session_id);
+
+	CREATE TABLE IF NOT EXISTS session_view_prefs (
+		session_id      TEXT PRIMARY KEY REFERENCES sessions(id) ON DELETE CASCADE,
+		density         TEXT,
+		filter_word     TEXT,
+		filter_pattern  TEXT,
+		filter_sources  TEXT,
+		expanded_topics TEXT,
+		updated_at      REAL NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS speaker_relabels (
+		id           INTEGER PRIMARY KEY AUTOINCREMENT,
+		session_id   TEXT NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+		from_label   TEXT NOT NULL,
+		to_label     TEXT NOT NULL,
+		segment_ids  TEXT NOT NULL,
+		relabeled_at REAL NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS idx_speaker_relabels_session ON speaker_relabels(session_id, id);
+
+	CREATE TABLE IF NOT EXISTS maintenance_runs (
+		ran_at      REAL NOT NULL,
+		size_before INTEGER NOT NULL,
+		size_after  INTEGER NOT NULL
+	);
+`
+
+// OpenClient opens the database read-write for the TUI's client-owned
+// tables and ensures they exist. Daemon-owned tables must still be
+// treated as read-only through this handle.
+func OpenClient(path string, opts Options) (*Store, error) {
+	db, err := sql.Open("sqlite", opts.dsn(path, "_journal_mode", "WAL"))
+	if err != nil {
+		return nil, fmt.Errorf("open database: %w", err)
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("ping database: %w", err)
+	}
+	s := &Store{db: db}
+	if err := s.EnsureClientSchema(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// metadataContextColumns were added to session_metadata after it first
+// shipped; EnsureClientSchema adds them to older tables.
+var metadataContextColumns = []string{"foreground_app", "meeting_url"}
+
+// consentColumn was added to session_metadata after the context columns.
+const consentColumn = "consent_acknowledged_at"
+
+// EnsureClientSchema creates the client-owned tables if they are missing
+// and adds columns introduced since a table was created.
+func (s *Store) EnsureClientSchema() error {
+	if _, err := s.db.Exec(clientSchema); err != nil {
+		return fmt.Errorf("create client schema: %w", err)
+	}
+	for _, col := range metadataContextColumns {
+		ok, err := hasColumn(s.db, "session_metadata", col)
+		if err != nil {
+			return fmt.Errorf("create client schema: %w", err)
+		}
+		if !ok {
+			if _, err := s.db.Exec(`ALTER TABLE session_metadata ADD COLUMN ` + col + ` TEXT`); err != nil {
+				return fmt.Errorf("add session_metadata.%s: %w", col, err)
+			}
+		}
+	}
+	ok, err := hasColumn(s.db, "session_metadata", consentColumn)
+	if err != nil {
+		return fmt.Errorf("create client schema: %w", err)
+	}
+	if !ok {
+		if _, err := s.db.Exec(`ALTER TABLE session_metadata ADD COLUMN ` + consentColumn + ` REAL`); err != nil {
+			return fmt.Errorf("add session_metadata.%s: %w", consentColumn, err)
+		}
+	}
+	return nil
+}
+
+// hasTable reports whether a table exists. Used to degrade gracefully
+// when a client-owned table has not been created yet.
+func (s *Store) hasTable(name string) (bool, error) {
+	var n int
+	err := s.db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?`, name).Scan(&n)
+	if err != nil {
+		return false, fmt.Errorf("check table %s: %w", name, err)
+	}
+	return n > 0, nil
+}
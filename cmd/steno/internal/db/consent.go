@@ -0,0 +1,55 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// AcknowledgeConsent records that the user confirmed, at at, that the
+// people in a session were told it is being recorded. The first
+// acknowledgment is kept; later calls don't move it. Requires a Store
+// opened with OpenClient.
+func (s *Store) AcknowledgeConsent(sessionID string, at time.Time) error {
+	if sessionID == "" {
+		return fmt.Errorf("acknowledge consent: empty session id")
+	}
+	if at.IsZero() {
+		at = time.Now()
+	}
+	_, err := s.db.Exec(`
+		INSERT INTO session_metadata (session_id, consent_acknowledged_at, updated_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT(session_id) DO UPDATE SET
+			consent_acknowledged_at = COALESCE(session_metadata.consent_acknowledged_at, excluded.consent_acknowledged_at),
+			updated_at = excluded.updated_at
+	`, sessionID, unixFromTime(at), unixFromTime(at))
+	if err != nil {
+		return fmt.Errorf("acknowledge consent: %w", err)
+	}
+	return nil
+}
+
+// ConsentAcknowledgedAt returns when consent was acknowledged for a
+// session, or nil when it wasn't (including when session_metadata or its
+// consent column doesn't exist yet).
+func (s *Store) ConsentAcknowledgedAt(sessionID string) (*time.Time, error) {
+	ok, err := s.hasTable("session_metadata")
+	if err != nil || !ok {
+		return nil, err
+	}
+	ok, err = hasColumn(s.db, "session_metadata", consentColumn)
+	if err != nil || !ok {
+		return nil, err
+	}
+	var at sql.NullFloat64
+	err = s.db.QueryRow(`SELECT consent_acknowledged_at FROM session_metadata WHERE session_id = ?`, sessionID).Scan(&at)
+	if err == sql.ErrNoRows || (err == nil && !at.Valid) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("consent acknowledgment: %w", err)
+	}
+	t := timeFromUnix(at.Float64)
+	return &t, nil
+}
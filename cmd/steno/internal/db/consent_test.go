@@ -0,0 +1,80 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAcknowledgeConsent(t *testing.T) {
+	rawDB := createTestDB(t)
+	defer rawDB.Close()
+	seedTestData(t, rawDB)
+	store := &Store{db: rawDB}
+
+	// Readers tolerate the table not existing yet.
+	if at, err := store.ConsentAcknowledgedAt("sess-1"); err != nil || at != nil {
+		t.Fatalf("before schema: %v, %v", at, err)
+	}
+	if err := store.EnsureClientSchema(); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.UpsertSessionMetadata(SessionMetadata{SessionID: "sess-1", Device: "Mic A"}); err != nil {
+		t.Fatal(err)
+	}
+	if at, err := store.ConsentAcknowledgedAt("sess-1"); err != nil || at != nil {
+		t.Fatalf("unacknowledged: %v, %v", at, err)
+	}
+
+	first := time.Unix(1710000060, 0)
+	if err := store.AcknowledgeConsent("sess-1", first); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.AcknowledgeConsent("sess-1", first.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	at, err := store.ConsentAcknowledgedAt("sess-1")
+	if err != nil || at == nil || !at.Equal(first) {
+		t.Errorf("acknowledged at = %v, %v; want the first acknowledgment %v", at, err, first)
+	}
+	// The rest of the metadata is kept.
+	if md, err := store.SessionMetadataFor("sess-1"); err != nil || md.Device != "Mic A" {
+		t.Errorf("metadata = %+v, %v", md, err)
+	}
+
+	// A session without metadata gets a row.
+	if err := store.AcknowledgeConsent("sess-2", first); err != nil {
+		t.Fatal(err)
+	}
+	if at, err := store.ConsentAcknowledgedAt("sess-2"); err != nil || at == nil {
+		t.Errorf("sess-2: %v, %v", at, err)
+	}
+	if err := store.AcknowledgeConsent("", first); err == nil {
+		t.Error("expected error for empty session id")
+	}
+}
+
+func TestEnsureClientSchemaAddsConsentColumn(t *testing.T) {
+	rawDB := createTestDB(t)
+	defer rawDB.Close()
+	seedTestData(t, rawDB)
+
+	// session_metadata with the context columns, before consent.
+	if _, err := rawDB.Exec(`CREATE TABLE session_metadata (
+		session_id TEXT PRIMARY KEY, device TEXT, system_audio INTEGER, updated_at REAL NOT NULL,
+		foreground_app TEXT, meeting_url TEXT)`); err != nil {
+		t.Fatal(err)
+	}
+	store := &Store{db: rawDB}
+	if at, err := store.ConsentAcknowledgedAt("sess-1"); err != nil || at != nil {
+		t.Fatalf("before migration: %v, %v", at, err)
+	}
+	if err := store.EnsureClientSchema(); err != nil {
+		t.Fatalf("EnsureClientSchema: %v", err)
+	}
+	if err := store.AcknowledgeConsent("sess-1", time.Unix(1710000060, 0)); err != nil {
+		t.Fatalf("AcknowledgeConsent: %v", err)
+	}
+	if at, err := store.ConsentAcknowledgedAt("sess-1"); err != nil || at == nil {
+		t.Errorf("after migration: %v, %v", at, err)
+	}
+}
@@ -0,0 +1,75 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Decision is a segment flagged as recording a decision, with the
+// segment's text for display.
+type Decision struct {
+	SegmentID string
+	SessionID string
+	// Cue is the phrase that flagged the segment, e.g. "we agreed".
+	Cue       string
+	CreatedAt time.Time
+
+	SegmentSeq       int
+	SegmentText      string
+	SegmentStartedAt time.Time
+	SegmentSource    string
+}
+
+// RecordDecision flags the segment with sequence number seq in sessionID
+// as a decision. Flagging a segment again keeps the first cue. Requires
+// a Store opened with OpenClient.
+func (s *Store) RecordDecision(sessionID string, seq int, cue string) error {
+	var segmentID string
+	err := s.db.QueryRow(`SELECT id FROM segments WHERE sessionId = ? AND sequenceNumber = ?`, sessionID, seq).Scan(&segmentID)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("record decision: session %s has no segment #%d", sessionID, seq)
+	}
+	if err != nil {
+		return fmt.Errorf("record decision: %w", err)
+	}
+	if _, err := s.db.Exec(`INSERT OR IGNORE INTO decisions (segment_id, session_id, cue, created_at)
+		VALUES (?, ?, ?, ?)`, segmentID, sessionID, cue, unixFromTime(time.Now())); err != nil {
+		return fmt.Errorf("record decision: %w", err)
+	}
+	return nil
+}
+
+// DecisionsForSession returns a session's decisions in transcript order.
+// Empty when the table does not exist yet.
+func (s *Store) DecisionsForSession(sessionID string) ([]Decision, error) {
+	ok, err := s.hasTable("decisions")
+	if err != nil || !ok {
+		return nil, err
+	}
+	rows, err := s.db.Query(`
+		SELECT d.segment_id, d.session_id, d.cue, d.created_at,
+			g.sequenceNumber, g.text, g.startedAt, g.source
+		FROM decisions d JOIN segments g ON g.id = d.segment_id
+		WHERE d.session_id = ?
+		ORDER BY g.sequenceNumber
+	`, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("query decisions: %w", err)
+	}
+	defer rows.Close()
+
+	var out []Decision
+	for rows.Next() {
+		var d Decision
+		var createdAt, startedAt float64
+		if err := rows.Scan(&d.SegmentID, &d.SessionID, &d.Cue, &createdAt,
+			&d.SegmentSeq, &d.SegmentText, &startedAt, &d.SegmentSource); err != nil {
+			return nil, fmt.Errorf("scan decision: %w", err)
+		}
+		d.CreatedAt = timeFromUnix(createdAt)
+		d.SegmentStartedAt = timeFromUnix(startedAt)
+		out = append(out, d)
+	}
+	return out, rows.Err()
+}
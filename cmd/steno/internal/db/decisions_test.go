@@ -0,0 +1,46 @@
+package db
+
+import "testing"
+
+func TestDecisionsRoundTrip(t *testing.T) {
+	rawDB := createTestDB(t)
+	defer rawDB.Close()
+	seedTestData(t, rawDB)
+	store := &Store{db: rawDB}
+
+	// Readers tolerate the table not existing yet.
+	if ds, err := store.DecisionsForSession("sess-1"); err != nil || ds != nil {
+		t.Fatalf("before schema: %v, %v", ds, err)
+	}
+	if err := store.EnsureClientSchema(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.RecordDecision("sess-1", 5, "we agreed"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.RecordDecision("sess-1", 2, "let's go with"); err != nil {
+		t.Fatal(err)
+	}
+	// A second flag on the same segment keeps the first cue.
+	if err := store.RecordDecision("sess-1", 5, "settled on"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.RecordDecision("sess-1", 99, "we agreed"); err == nil {
+		t.Error("expected error for missing segment")
+	}
+
+	ds, err := store.DecisionsForSession("sess-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ds) != 2 {
+		t.Fatalf("got %d decisions, want 2", len(ds))
+	}
+	if ds[0].SegmentSeq != 2 || ds[1].SegmentSeq != 5 || ds[1].Cue != "we agreed" {
+		t.Errorf("decisions = %+v, want #2 then #5 (we agreed)", ds)
+	}
+	if ds[1].SegmentText != "Segment 5 from session one." || ds[1].SegmentSource != "microphone" {
+		t.Errorf("decision segment = %+v", ds[1])
+	}
+}
@@ -0,0 +1,80 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DictationMacro is one rule of a dictation profile, kept in the
+// client-owned dictation_macros table. See package dictation for how
+// rules apply; the pattern is validated there, before it is added.
+type DictationMacro struct {
+	ID          int64
+	Profile     string
+	Pattern     string
+	Replacement string
+	CreatedAt   time.Time
+}
+
+// AddDictationMacro appends a rule to profile, returning its ID.
+// Requires a Store opened with OpenClient.
+func (s *Store) AddDictationMacro(profile, pattern, replacement string) (int64, error) {
+	profile = strings.TrimSpace(profile)
+	if profile == "" {
+		return 0, fmt.Errorf("add dictation macro: empty profile")
+	}
+	if pattern == "" {
+		return 0, fmt.Errorf("add dictation macro: empty pattern")
+	}
+	res, err := s.db.Exec(`INSERT INTO dictation_macros (profile, pattern, replacement, created_at) VALUES (?, ?, ?, ?)`,
+		profile, pattern, replacement, unixFromTime(time.Now()))
+	if err != nil {
+		return 0, fmt.Errorf("add dictation macro: %w", err)
+	}
+	return res.LastInsertId()
+}
+
+// DeleteDictationMacro removes the rule with this ID, reporting whether
+// it existed.
+func (s *Store) DeleteDictationMacro(id int64) (bool, error) {
+	res, err := s.db.Exec(`DELETE FROM dictation_macros WHERE id = ?`, id)
+	if err != nil {
+		return false, fmt.Errorf("delete dictation macro: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+// DictationMacros returns profile's rules in the order they apply, the
+// order they were added. An empty profile returns every profile's
+// rules, by profile. Empty when the table does not exist yet.
+func (s *Store) DictationMacros(profile string) ([]DictationMacro, error) {
+	ok, err := s.hasTable("dictation_macros")
+	if err != nil || !ok {
+		return nil, err
+	}
+	query := `SELECT id, profile, pattern, replacement, created_at FROM dictation_macros`
+	var args []any
+	if profile != "" {
+		query += ` WHERE profile = ?`
+		args = append(args, profile)
+	}
+	rows, err := s.db.Query(query+` ORDER BY profile, id`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query dictation macros: %w", err)
+	}
+	defer rows.Close()
+
+	var out []DictationMacro
+	for rows.Next() {
+		var m DictationMacro
+		var createdAt float64
+		if err := rows.Scan(&m.ID, &m.Profile, &m.Pattern, &m.Replacement, &createdAt); err != nil {
+			return nil, fmt.Errorf("scan dictation macro: %w", err)
+		}
+		m.CreatedAt = timeFromUnix(createdAt)
+		out = append(out, m)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,52 @@
+package db
+
+import "testing"
+
+func TestDictationMacrosRoundTrip(t *testing.T) {
+	rawDB := createTestDB(t)
+	defer rawDB.Close()
+	store := &Store{db: rawDB}
+
+	// Readers tolerate the table not existing yet.
+	if found, err := store.DictationMacros(""); err != nil || found != nil {
+		t.Fatalf("before schema: %v, %v", found, err)
+	}
+	if err := store.EnsureClientSchema(); err != nil {
+		t.Fatal(err)
+	}
+
+	second, err := store.AddDictationMacro("email", `\bsign off\b`, "Best,\nJo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.AddDictationMacro("default", `\bsmiley\b`, ":)"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.AddDictationMacro("email", `\bcc\b`, "CC:"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.AddDictationMacro(" ", "x", ""); err == nil {
+		t.Error("want an error for an empty profile")
+	}
+
+	email, err := store.DictationMacros("email")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(email) != 2 || email[0].ID != second || email[0].Replacement != "Best,\nJo" || email[1].Pattern != `\bcc\b` {
+		t.Fatalf("email macros = %+v, want sign off then cc", email)
+	}
+	if all, _ := store.DictationMacros(""); len(all) != 3 || all[0].Profile != "default" {
+		t.Errorf("all macros = %+v, want default's first", all)
+	}
+
+	if ok, err := store.DeleteDictationMacro(second); err != nil || !ok {
+		t.Fatalf("delete = %v, %v", ok, err)
+	}
+	if ok, _ := store.DeleteDictationMacro(second); ok {
+		t.Error("deleting twice should report it missing")
+	}
+	if email, _ := store.DictationMacros("email"); len(email) != 1 {
+		t.Errorf("after delete = %+v", email)
+	}
+}
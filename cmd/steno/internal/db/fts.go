@@ -0,0 +1,151 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// segmentIndex is a client-owned FTS5 index over segment text, for
+// searching every session at once. Rows are keyed by the segment's rowid
+// and hold its effective text: the user's correction (segment_edits)
+// when there is one, else what the ASR heard. The daemon owns segments
+// and knows nothing of the index, so it is reconciled before each search
+// rather than kept by triggers: rows whose segment was deleted (discard,
+// merge) or whose text changed are dropped, and segments without a row
+// are added.
+//
+// Searching through a read-only Store, or a SQLite built without FTS5,
+// falls back to LIKE.
+const segmentIndex = "segments_fts"
+
+const segmentIndexSchema = `CREATE VIRTUAL TABLE IF NOT EXISTS ` + segmentIndex + ` USING fts5(
+	text,
+	tokenize = 'unicode61 remove_diacritics 2'
+)`
+
+// SegmentHit is a segment matched by SearchAllSegments, with the
+// session it was said in.
+type SegmentHit struct {
+	Segment
+	SessionTitle     string
+	SessionStartedAt time.Time
+}
+
+// SearchAllSegments finds segments in every session that contain query
+// as a phrase, ignoring case and accents, newest first. Mic segments
+// marked as duplicates are left out.
+func (s *Store) SearchAllSegments(query string, limit int) ([]SegmentHit, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, nil
+	}
+	text, err := s.segmentText()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.syncSegmentIndex(text); err != nil {
+		return s.searchAllSegmentsLike(text, query, limit)
+	}
+	// A quoted FTS5 string is a phrase: the query's words, in order.
+	phrase := `"` + strings.ReplaceAll(query, `"`, `""`) + `"`
+	return s.searchHits(text, `
+		JOIN `+segmentIndex+` f ON f.rowid = seg.rowid
+		WHERE f.text MATCH ?`, phrase, limit)
+}
+
+// searchAllSegmentsLike is SearchAllSegments without the index.
+func (s *Store) searchAllSegmentsLike(text effectiveText, query string, limit int) ([]SegmentHit, error) {
+	return s.searchHits(text, `
+		WHERE `+text.expr+` LIKE ? ESCAPE '\'`, "%"+escapeLike(query)+"%", limit)
+}
+
+// effectiveText is the SQL for a segment's effective text: expr, over
+// segments seg joined with join.
+type effectiveText struct {
+	join, expr string
+}
+
+// segmentText returns the SQL for a segment's effective text: its
+// correction where segment_edits has one.
+func (s *Store) segmentText() (effectiveText, error) {
+	ok, err := s.hasTable("segment_edits")
+	if err != nil || !ok {
+		return effectiveText{expr: "seg.text"}, err
+	}
+	return effectiveText{
+		join: `
+		LEFT JOIN segment_edits edit ON edit.segment_id = seg.id`,
+		expr: "COALESCE(edit.text, seg.text)",
+	}, nil
+}
+
+// searchHits runs a search whose match clause (joins, then a WHERE with
+// one placeholder for arg) narrows segments, joined to their sessions.
+func (s *Store) searchHits(text effectiveText, match string, arg any, limit int) ([]SegmentHit, error) {
+	locale := "NULL"
+	if ok, err := hasColumn(s.db, "segments", "locale"); err != nil {
+		return nil, err
+	} else if ok {
+		locale = "seg.locale"
+	}
+	rows, err := s.db.Query(`
+		SELECT seg.id, seg.sessionId, `+text.expr+`, seg.startedAt, seg.endedAt, seg.confidence,
+			seg.sequenceNumber, seg.createdAt, seg.source, `+locale+`,
+			COALESCE(ses.title, ''), ses.startedAt
+		FROM segments seg
+		JOIN sessions ses ON ses.id = seg.sessionId`+text.join+match+`
+		  AND seg.duplicate_of IS NULL
+		ORDER BY seg.startedAt DESC
+		LIMIT ?
+	`, arg, limit)
+	if err != nil {
+		return nil, fmt.Errorf("search segments: %w", err)
+	}
+	defer rows.Close()
+	var hits []SegmentHit
+	for rows.Next() {
+		var h SegmentHit
+		var startedAt, endedAt, createdAt, sessionStartedAt float64
+		var confidence sql.NullFloat64
+		var loc sql.NullString
+		if err := rows.Scan(&h.ID, &h.SessionID, &h.Text, &startedAt, &endedAt, &confidence,
+			&h.SequenceNumber, &createdAt, &h.Source, &loc, &h.SessionTitle, &sessionStartedAt); err != nil {
+			return nil, fmt.Errorf("scan segment: %w", err)
+		}
+		h.StartedAt = timeFromUnix(startedAt)
+		h.EndedAt = timeFromUnix(endedAt)
+		h.CreatedAt = timeFromUnix(createdAt)
+		if confidence.Valid {
+			c := confidence.Float64
+			h.Confidence = &c
+		}
+		h.Locale = loc.String
+		h.SessionStartedAt = timeFromUnix(sessionStartedAt)
+		hits = append(hits, h)
+	}
+	return hits, rows.Err()
+}
+
+// syncSegmentIndex creates the index if needed and reconciles it with
+// segments: rows of deleted segments, and of segments whose effective
+// text has changed, are dropped, then every segment without a row is
+// added.
+func (s *Store) syncSegmentIndex(text effectiveText) error {
+	if _, err := s.db.Exec(segmentIndexSchema); err != nil {
+		return fmt.Errorf("create segment index: %w", err)
+	}
+	if _, err := s.db.Exec(`DELETE FROM ` + segmentIndex + ` WHERE rowid IN (
+		SELECT f.rowid FROM ` + segmentIndex + ` f
+		LEFT JOIN segments seg ON seg.rowid = f.rowid` + text.join + `
+		WHERE seg.rowid IS NULL OR f.text IS NOT ` + text.expr + `)`); err != nil {
+		return fmt.Errorf("update segment index: %w", err)
+	}
+	if _, err := s.db.Exec(`INSERT INTO ` + segmentIndex + ` (rowid, text)
+		SELECT seg.rowid, ` + text.expr + ` FROM segments seg` + text.join + `
+		WHERE seg.rowid NOT IN (SELECT rowid FROM ` + segmentIndex + `)`); err != nil {
+		return fmt.Errorf("update segment index: %w", err)
+	}
+	return nil
+}
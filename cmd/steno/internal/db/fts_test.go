@@ -0,0 +1,114 @@
+package db
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestSearchAllSegments(t *testing.T) {
+	rawDB := createTestDB(t)
+	defer rawDB.Close()
+	seedTestData(t, rawDB)
+	store := NewStore(rawDB)
+
+	hits, err := store.SearchAllSegments("SEGMENT 3", 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Newest first: the active session's segment 3, then session one's.
+	if len(hits) != 2 || hits[0].ID != "seg-2-3" || hits[1].ID != "seg-1-3" {
+		t.Fatalf("hits = %+v", hits)
+	}
+	if hits[1].SessionTitle != "Team Standup" || hits[1].SessionStartedAt.Unix() != 1710000000 || hits[0].SessionTitle != "" {
+		t.Errorf("session metadata = %q %v, %q", hits[1].SessionTitle, hits[1].SessionStartedAt, hits[0].SessionTitle)
+	}
+	if hits, _ := store.SearchAllSegments("session segment", 100); len(hits) != 3 {
+		t.Errorf("phrase search got %d hits, want 3", len(hits))
+	}
+	if hits, _ := store.SearchAllSegments("one segment", 100); len(hits) != 0 {
+		t.Errorf("words out of order matched %d hits", len(hits))
+	}
+
+	// New segments are indexed on the next search.
+	rawDB.Exec(`INSERT INTO segments (id, sessionId, text, startedAt, endedAt, sequenceNumber, createdAt)
+		VALUES ('seg-2-4', 'sess-2', 'Le café est prêt', 1710007300, 1710007301, 4, 1710007300)`)
+	if hits, err := store.SearchAllSegments("cafe", 10); err != nil || len(hits) != 1 {
+		t.Errorf("accent-insensitive search = %+v, %v", hits, err)
+	}
+
+	// Deleting the newest segments lets SQLite reuse their rowids.
+	rawDB.Exec(`DELETE FROM segments WHERE id IN ('seg-2-3', 'seg-2-4')`)
+	rawDB.Exec(`INSERT INTO segments (id, sessionId, text, startedAt, endedAt, sequenceNumber, createdAt)
+		VALUES ('seg-2-5', 'sess-2', 'a fresh remark', 1710007400, 1710007401, 5, 1710007400)`)
+	if hits, _ := store.SearchAllSegments("cafe", 10); len(hits) != 0 {
+		t.Errorf("deleted segment still found: %+v", hits)
+	}
+	if hits, _ := store.SearchAllSegments("fresh remark", 10); len(hits) != 1 || hits[0].ID != "seg-2-5" {
+		t.Errorf("segment with a reused rowid = %+v", hits)
+	}
+	if hits, _ := store.SearchAllSegments("  ", 10); hits != nil {
+		t.Errorf("empty query = %+v", hits)
+	}
+}
+
+func TestSearchAllSegmentsFollowsEditsAndDeletes(t *testing.T) {
+	rawDB := createTestDB(t)
+	defer rawDB.Close()
+	seedTestData(t, rawDB)
+	store := NewStore(rawDB)
+	if err := store.EnsureClientSchema(); err != nil {
+		t.Fatal(err)
+	}
+	if hits, err := store.SearchAllSegments("segment 4 from", 10); err != nil || len(hits) != 1 {
+		t.Fatalf("before changes = %+v, %v", hits, err)
+	}
+
+	// Older segments, not the newest indexed, change under the index.
+	rawDB.Exec(`UPDATE segments SET text = 'the budget moved to March' WHERE id = 'seg-1-4'`)
+	rawDB.Exec(`DELETE FROM segments WHERE id = 'seg-1-5'`)
+	if err := store.EditSegment("sess-1", 6, "ship the beta on Friday"); err != nil {
+		t.Fatal(err)
+	}
+
+	if hits, _ := store.SearchAllSegments("segment 4 from", 10); len(hits) != 0 {
+		t.Errorf("old text of a changed segment still found: %+v", hits)
+	}
+	if hits, _ := store.SearchAllSegments("budget moved", 10); len(hits) != 1 || hits[0].ID != "seg-1-4" {
+		t.Errorf("changed text = %+v", hits)
+	}
+	if hits, _ := store.SearchAllSegments("segment 5 from", 10); len(hits) != 0 {
+		t.Errorf("deleted segment still found: %+v", hits)
+	}
+	hits, _ := store.SearchAllSegments("beta on friday", 10)
+	if len(hits) != 1 || hits[0].ID != "seg-1-6" || hits[0].Text != "ship the beta on Friday" {
+		t.Errorf("corrected text = %+v", hits)
+	}
+	if hits, _ := store.SearchAllSegments("segment 6 from", 10); len(hits) != 0 {
+		t.Errorf("text the correction replaced still found: %+v", hits)
+	}
+}
+
+func TestSearchAllSegmentsReadOnlyFallsBackToLike(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "steno.sqlite")
+	rw, err := OpenClient(path, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rw.db.Exec(`CREATE TABLE sessions (id TEXT PRIMARY KEY, locale TEXT, startedAt REAL, title TEXT)`)
+	rw.db.Exec(`CREATE TABLE segments (id TEXT PRIMARY KEY, sessionId TEXT, text TEXT, startedAt REAL, endedAt REAL,
+		confidence REAL, sequenceNumber INTEGER, createdAt REAL, source TEXT DEFAULT 'microphone', duplicate_of TEXT)`)
+	rw.db.Exec(`INSERT INTO sessions VALUES ('s1', 'en_US', 100, 'Planning')`)
+	rw.db.Exec(`INSERT INTO segments (id, sessionId, text, startedAt, endedAt, sequenceNumber, createdAt)
+		VALUES ('a', 's1', 'ship it on Friday', 101, 102, 1, 101)`)
+	rw.Close()
+
+	ro, err := Open(path, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ro.Close()
+	hits, err := ro.SearchAllSegments("it on fri", 10)
+	if err != nil || len(hits) != 1 || hits[0].SessionTitle != "Planning" {
+		t.Errorf("hits = %+v, %v", hits, err)
+	}
+}
@@ -14,7 +14,7 @@ func TestLiveDatabase(t *testing.T) {
 		t.Skip("database not found at", dbPath)
 	}
 
-	store, err := Open(dbPath)
+	store, err := Open(dbPath, Options{})
 	if err != nil {
 		t.Fatalf("open: %v", err)
 	}
@@ -0,0 +1,111 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Maintenance steps, in the order RunMaintenance applies them.
+const (
+	// StepAnalyze refreshes the query planner's statistics.
+	StepAnalyze = "analyze"
+	// StepVacuum rebuilds the database file, returning pages freed by
+	// deleted and merged sessions. It holds a write lock for its duration.
+	StepVacuum = "vacuum"
+	// StepCheckpoint copies the WAL into the database and truncates it.
+	// Last, because VACUUM in WAL mode writes the rebuilt file through
+	// the WAL.
+	StepCheckpoint = "wal_checkpoint"
+)
+
+// ErrCheckpointBusy is StepCheckpoint's error when another connection,
+// such as the recording daemon's, kept it from truncating the WAL.
+var ErrCheckpointBusy = errors.New("database is busy; the WAL was only partly checkpointed")
+
+// MaintenanceSteps lists the steps in the order they run.
+var MaintenanceSteps = []string{StepAnalyze, StepVacuum, StepCheckpoint}
+
+// RunMaintenanceStep runs one maintenance step. The store must be opened
+// with OpenMaintenance. StepVacuum blocks the daemon's writes, so run it
+// only while the daemon is not writing.
+func (s *Store) RunMaintenanceStep(step string) error {
+	var err error
+	switch step {
+	case StepAnalyze:
+		_, err = s.db.Exec(`ANALYZE`)
+	case StepVacuum:
+		_, err = s.db.Exec(`VACUUM`)
+	case StepCheckpoint:
+		// busy reports whether a reader or writer kept the checkpoint
+		// from completing.
+		var busy, logPages, checkpointed int
+		err = s.db.QueryRow(`PRAGMA wal_checkpoint(TRUNCATE)`).Scan(&busy, &logPages, &checkpointed)
+		if err == nil && busy != 0 {
+			err = ErrCheckpointBusy
+		}
+	default:
+		return fmt.Errorf("unknown maintenance step %q", step)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", step, err)
+	}
+	return nil
+}
+
+// MaintenanceRun is one completed `steno maintain`, recorded in the
+// client-owned maintenance_runs table.
+type MaintenanceRun struct {
+	RanAt      time.Time
+	SizeBefore int64
+	SizeAfter  int64
+}
+
+// RecordMaintenance logs a completed run. Requires the client schema
+// (EnsureClientSchema).
+func (s *Store) RecordMaintenance(run MaintenanceRun) error {
+	_, err := s.db.Exec(`INSERT INTO maintenance_runs (ran_at, size_before, size_after) VALUES (?, ?, ?)`,
+		unixFromTime(run.RanAt), run.SizeBefore, run.SizeAfter)
+	if err != nil {
+		return fmt.Errorf("record maintenance: %w", err)
+	}
+	return nil
+}
+
+// LastMaintenance returns the most recent recorded run, or nil when there
+// is none (or the maintenance_runs table doesn't exist yet).
+func (s *Store) LastMaintenance() (*MaintenanceRun, error) {
+	ok, err := s.hasTable("maintenance_runs")
+	if err != nil || !ok {
+		return nil, err
+	}
+	var run MaintenanceRun
+	var ranAt float64
+	err = s.db.QueryRow(`SELECT ran_at, size_before, size_after FROM maintenance_runs ORDER BY ran_at DESC LIMIT 1`).
+		Scan(&ranAt, &run.SizeBefore, &run.SizeAfter)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("last maintenance: %w", err)
+	}
+	run.RanAt = timeFromUnix(ranAt)
+	return &run, nil
+}
+
+// DatabaseSize returns the on-disk size of the database at path: the main
+// file plus its WAL, which can grow well past the main file between
+// checkpoints.
+func DatabaseSize(path string) (int64, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, err
+	}
+	size := info.Size()
+	if wal, err := os.Stat(path + "-wal"); err == nil {
+		size += wal.Size()
+	}
+	return size, nil
+}
@@ -0,0 +1,84 @@
+package db
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMaintenanceShrinksDatabase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "steno.sqlite")
+	store, err := OpenMaintenance(path, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	if _, err := store.db.Exec(`CREATE TABLE sessions (id TEXT PRIMARY KEY, note TEXT)`); err != nil {
+		t.Fatal(err)
+	}
+	filler := strings.Repeat("x", 4096)
+	for i := 0; i < 200; i++ {
+		if _, err := store.db.Exec(`INSERT INTO sessions VALUES (?, ?)`, i, filler); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := store.db.Exec(`DELETE FROM sessions`); err != nil {
+		t.Fatal(err)
+	}
+	before, err := DatabaseSize(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, step := range MaintenanceSteps {
+		if err := store.RunMaintenanceStep(step); err != nil {
+			t.Fatalf("%s: %v", step, err)
+		}
+	}
+	after, err := DatabaseSize(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if after >= before/4 {
+		t.Errorf("size %d -> %d, want the freed pages returned", before, after)
+	}
+	if err := store.RunMaintenanceStep("reindex"); err == nil {
+		t.Error("expected error for an unknown step")
+	}
+}
+
+func TestMaintenanceRuns(t *testing.T) {
+	rawDB := createTestDB(t)
+	defer rawDB.Close()
+	store := &Store{db: rawDB}
+
+	// Readers tolerate the table not existing yet.
+	if last, err := store.LastMaintenance(); err != nil || last != nil {
+		t.Fatalf("before schema: %+v, %v", last, err)
+	}
+	if err := store.EnsureClientSchema(); err != nil {
+		t.Fatal(err)
+	}
+	if last, err := store.LastMaintenance(); err != nil || last != nil {
+		t.Fatalf("no runs: %+v, %v", last, err)
+	}
+
+	at := time.Unix(1710000000, 0)
+	for i, run := range []MaintenanceRun{
+		{RanAt: at, SizeBefore: 300, SizeAfter: 200},
+		{RanAt: at.Add(time.Hour), SizeBefore: 250, SizeAfter: 100},
+	} {
+		if err := store.RecordMaintenance(run); err != nil {
+			t.Fatalf("record %d: %v", i, err)
+		}
+	}
+	last, err := store.LastMaintenance()
+	if err != nil || last == nil {
+		t.Fatalf("LastMaintenance: %+v, %v", last, err)
+	}
+	if !last.RanAt.Equal(at.Add(time.Hour)) || last.SizeAfter != 100 {
+		t.Errorf("last = %+v", last)
+	}
+}
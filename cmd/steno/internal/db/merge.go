@@ -0,0 +1,523 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// OpenMaintenance opens the database read-write for explicit, offline
+// maintenance operations (`steno merge`, `steno maintain`) that must
+// modify daemon-owned tables. Callers are responsible for refusing to touch sessions the
+// daemon may still be writing (status 'active'). Writes take the lock up
+// front (immediate transactions), waiting out a daemon write for the
+// busy timeout instead of failing with SQLITE_BUSY midway.
+func OpenMaintenance(path string, opts Options) (*Store, error) {
+	db, err := sql.Open("sqlite", opts.dsn(path, "_journal_mode", "WAL", "_txlock", "immediate"))
+	if err != nil {
+		return nil, fmt.Errorf("open database: %w", err)
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("ping database: %w", err)
+	}
+	return &Store{db: db}, nil
+}
+
+// MergePlan describes the result of merging Source into Target: Target
+// keeps its ID and gains every segment, topic, and summary from Source,
+// and Source is deleted.
+type MergePlan struct {
+	Target Session
+	Source Session
+
+	// Segments is the merged timeline in its new order, including rows
+	// marked duplicate_of (they keep their pointers, just renumbered).
+	Segments []MergedSegment
+
+	// Interleaved counts source segments that land between two target
+	// segments — zero means the sessions were simply concatenated.
+	Interleaved int
+
+	Topics    []RangeRemap
+	Summaries []RangeRemap
+
+	// TimeAdjustments are the runs of segments whose timestamps move to
+	// undo a clock jump (see normalizeTimes). Segments carry their
+	// adjusted times.
+	TimeAdjustments []TimeAdjustment
+
+	StartedAt time.Time
+	EndedAt   *time.Time
+}
+
+// MergedSegment is one segment's old and new position.
+type MergedSegment struct {
+	ID            string
+	FromSessionID string
+	OldSeq        int
+	NewSeq        int
+	StartedAt     time.Time
+	EndedAt       time.Time
+	Source        string
+	Text          string
+	// Shift is how far the merge moves the segment's times.
+	Shift time.Duration
+}
+
+// RangeRemap is a topic or summary whose segment range is rewritten to
+// the merged numbering.
+type RangeRemap struct {
+	ID            string
+	FromSessionID string
+	Label         string
+	OldStart      int
+	OldEnd        int
+	NewStart      int
+	NewEnd        int
+}
+
+// PlanMerge computes, without writing, how sourceID would merge into
+// targetID: timestamps corrected for clock jumps, segments interleaved
+// by startedAt (ties keep target first, then original order), renumbered
+// from 1, with topic and summary ranges remapped onto the new numbers.
+func (s *Store) PlanMerge(targetID, sourceID string) (*MergePlan, error) {
+	if targetID == sourceID {
+		return nil, fmt.Errorf("cannot merge session %s into itself", targetID)
+	}
+	target, err := s.GetSession(targetID)
+	if err != nil {
+		return nil, err
+	}
+	if target == nil {
+		return nil, fmt.Errorf("session not found: %s", targetID)
+	}
+	source, err := s.GetSession(sourceID)
+	if err != nil {
+		return nil, err
+	}
+	if source == nil {
+		return nil, fmt.Errorf("session not found: %s", sourceID)
+	}
+	for _, sess := range []*Session{target, source} {
+		if sess.Status == "active" {
+			return nil, fmt.Errorf("session %s is active; the daemon may still be writing to it", sess.ID)
+		}
+	}
+
+	plan := &MergePlan{Target: *target, Source: *source}
+
+	rows, err := s.db.Query(`
+		SELECT id, sessionId, sequenceNumber, startedAt, endedAt, source, text
+		FROM segments WHERE sessionId IN (?, ?)
+	`, targetID, sourceID)
+	if err != nil {
+		return nil, fmt.Errorf("query segments: %w", err)
+	}
+	for rows.Next() {
+		var seg MergedSegment
+		var startedAt, endedAt float64
+		if err := rows.Scan(&seg.ID, &seg.FromSessionID, &seg.OldSeq, &startedAt, &endedAt, &seg.Source, &seg.Text); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan segment: %w", err)
+		}
+		seg.StartedAt, seg.EndedAt = timeFromUnix(startedAt), timeFromUnix(endedAt)
+		plan.Segments = append(plan.Segments, seg)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	earlierID, laterID := targetID, sourceID
+	if source.StartedAt.Before(target.StartedAt) {
+		earlierID, laterID = sourceID, targetID
+	}
+	plan.TimeAdjustments = normalizeTimes(plan.Segments, earlierID, laterID)
+
+	sort.SliceStable(plan.Segments, func(i, j int) bool {
+		a, b := plan.Segments[i], plan.Segments[j]
+		if !a.StartedAt.Equal(b.StartedAt) {
+			return a.StartedAt.Before(b.StartedAt)
+		}
+		if a.FromSessionID != b.FromSessionID {
+			return a.FromSessionID == targetID
+		}
+		return a.OldSeq < b.OldSeq
+	})
+
+	// newSeq[session][oldSeq] → new sequence number.
+	newSeq := map[string]map[int]int{targetID: {}, sourceID: {}}
+	seenTarget, targetTotal := 0, 0
+	for _, seg := range plan.Segments {
+		if seg.FromSessionID == targetID {
+			targetTotal++
+		}
+	}
+	for i := range plan.Segments {
+		seg := &plan.Segments[i]
+		seg.NewSeq = i + 1
+		newSeq[seg.FromSessionID][seg.OldSeq] = seg.NewSeq
+		if seg.FromSessionID == targetID {
+			seenTarget++
+		} else if seenTarget > 0 && seenTarget < targetTotal {
+			plan.Interleaved++
+		}
+	}
+
+	plan.Topics, err = s.planRangeRemaps(`SELECT id, sessionId, title, segmentRangeStart, segmentRangeEnd FROM topics WHERE sessionId IN (?, ?)`,
+		targetID, sourceID, newSeq)
+	if err != nil {
+		return nil, fmt.Errorf("plan topics: %w", err)
+	}
+	plan.Summaries, err = s.planRangeRemaps(`SELECT id, sessionId, summaryType, segmentRangeStart, segmentRangeEnd FROM summaries WHERE sessionId IN (?, ?)`,
+		targetID, sourceID, newSeq)
+	if err != nil {
+		return nil, fmt.Errorf("plan summaries: %w", err)
+	}
+
+	plan.StartedAt = target.StartedAt
+	if source.StartedAt.Before(plan.StartedAt) {
+		plan.StartedAt = source.StartedAt
+	}
+	switch {
+	case target.EndedAt == nil:
+		plan.EndedAt = source.EndedAt
+	case source.EndedAt == nil || target.EndedAt.After(*source.EndedAt):
+		plan.EndedAt = target.EndedAt
+	default:
+		plan.EndedAt = source.EndedAt
+	}
+	// A moved segment may now end after both sessions did.
+	for _, seg := range plan.Segments {
+		if seg.Shift > 0 && plan.EndedAt != nil && seg.EndedAt.After(*plan.EndedAt) {
+			end := seg.EndedAt
+			plan.EndedAt = &end
+		}
+	}
+	return plan, nil
+}
+
+// planRangeRemaps maps each row's [start, end] range onto the merged
+// numbering: the new range spans the smallest and largest new number of
+// the segments originally inside it. A range that covered no segments
+// (e.g. they were deleted) collapses onto the nearest following segment.
+func (s *Store) planRangeRemaps(query, targetID, sourceID string, newSeq map[string]map[int]int) ([]RangeRemap, error) {
+	rows, err := s.db.Query(query, targetID, sourceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var remaps []RangeRemap
+	for rows.Next() {
+		var r RangeRemap
+		if err := rows.Scan(&r.ID, &r.FromSessionID, &r.Label, &r.OldStart, &r.OldEnd); err != nil {
+			return nil, err
+		}
+		mapping := newSeq[r.FromSessionID]
+		r.NewStart, r.NewEnd = 0, 0
+		for old, n := range mapping {
+			if old < r.OldStart || old > r.OldEnd {
+				continue
+			}
+			if r.NewStart == 0 || n < r.NewStart {
+				r.NewStart = n
+			}
+			if n > r.NewEnd {
+				r.NewEnd = n
+			}
+		}
+		if r.NewStart == 0 {
+			r.NewStart = nearestSeq(mapping, r.OldStart)
+			r.NewEnd = r.NewStart
+		}
+		remaps = append(remaps, r)
+	}
+	sort.SliceStable(remaps, func(i, j int) bool { return remaps[i].NewStart < remaps[j].NewStart })
+	return remaps, rows.Err()
+}
+
+// nearestSeq returns the new number of the first segment at or after old,
+// or of the last segment when none follows. Zero when mapping is empty.
+func nearestSeq(mapping map[int]int, old int) int {
+	best, bestOld := 0, 0
+	last, lastOld := 0, 0
+	for o, n := range mapping {
+		if o >= old && (best == 0 || o < bestOld) {
+			best, bestOld = n, o
+		}
+		if last == 0 || o > lastOld {
+			last, lastOld = n, o
+		}
+	}
+	if best != 0 {
+		return best
+	}
+	return last
+}
+
+// MergeSessions applies PlanMerge(targetID, sourceID) in one transaction
+// and returns the plan that was applied. Requires a Store opened with
+// OpenMaintenance.
+func (s *Store) MergeSessions(targetID, sourceID string) (*MergePlan, error) {
+	plan, err := s.PlanMerge(targetID, sourceID)
+	if err != nil {
+		return nil, err
+	}
+	hasMeta, err := s.hasTable("session_metadata")
+	if err != nil {
+		return nil, err
+	}
+	hasAnnotations, err := s.hasTable("annotations")
+	if err != nil {
+		return nil, err
+	}
+	hasSpeakers, err := s.hasTable("speaker_names")
+	if err != nil {
+		return nil, err
+	}
+	hasDecisions, err := s.hasTable("decisions")
+	if err != nil {
+		return nil, err
+	}
+	hasNotes, err := s.hasTable("session_notes")
+	if err != nil {
+		return nil, err
+	}
+	hasEdits, err := s.hasTable("segment_edits")
+	if err != nil {
+		return nil, err
+	}
+	hasCleanups, err := s.hasTable("segment_cleanups")
+	if err != nil {
+		return nil, err
+	}
+	hasViewPrefs, err := s.hasTable("session_view_prefs")
+	if err != nil {
+		return nil, err
+	}
+	hasRelabels, err := s.hasTable("speaker_relabels")
+	if err != nil {
+		return nil, err
+	}
+	hasQuality, err := s.hasTable("session_quality")
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("begin merge: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Two passes so UNIQUE(sessionId, sequenceNumber) never collides with
+	// a target row that hasn't been renumbered yet: park every segment at
+	// its negated new number, then flip the sign.
+	for _, seg := range plan.Segments {
+		if _, err := tx.Exec(`UPDATE segments SET sessionId = ?, sequenceNumber = ? WHERE id = ?`,
+			targetID, -seg.NewSeq, seg.ID); err != nil {
+			return nil, fmt.Errorf("renumber segment %s: %w", seg.ID, err)
+		}
+		if seg.Shift != 0 {
+			if _, err := tx.Exec(`UPDATE segments SET startedAt = ?, endedAt = ? WHERE id = ?`,
+				unixFromTime(seg.StartedAt), unixFromTime(seg.EndedAt), seg.ID); err != nil {
+				return nil, fmt.Errorf("retime segment %s: %w", seg.ID, err)
+			}
+		}
+	}
+	if _, err := tx.Exec(`UPDATE segments SET sequenceNumber = -sequenceNumber WHERE sessionId = ? AND sequenceNumber < 0`, targetID); err != nil {
+		return nil, fmt.Errorf("renumber segments: %w", err)
+	}
+
+	for _, t := range plan.Topics {
+		if _, err := tx.Exec(`UPDATE topics SET sessionId = ?, segmentRangeStart = ?, segmentRangeEnd = ? WHERE id = ?`,
+			targetID, t.NewStart, t.NewEnd, t.ID); err != nil {
+			return nil, fmt.Errorf("remap topic %s: %w", t.ID, err)
+		}
+	}
+	for _, sum := range plan.Summaries {
+		if _, err := tx.Exec(`UPDATE summaries SET sessionId = ?, segmentRangeStart = ?, segmentRangeEnd = ? WHERE id = ?`,
+			targetID, sum.NewStart, sum.NewEnd, sum.ID); err != nil {
+			return nil, fmt.Errorf("remap summary %s: %w", sum.ID, err)
+		}
+	}
+
+	var endedAt any
+	if plan.EndedAt != nil {
+		endedAt = unixFromTime(*plan.EndedAt)
+	}
+	if _, err := tx.Exec(`
+		UPDATE sessions SET startedAt = ?, endedAt = ?,
+			title = COALESCE(NULLIF(title, ''), (SELECT title FROM sessions WHERE id = ?))
+		WHERE id = ?
+	`, unixFromTime(plan.StartedAt), endedAt, sourceID, targetID); err != nil {
+		return nil, fmt.Errorf("update target session: %w", err)
+	}
+
+	// Both sessions are finished (PlanMerge refuses active ones), so the
+	// dedup cursor can point at the end of the merged timeline. Older
+	// databases predate the column.
+	if ok, err := hasColumn(tx, "sessions", "last_deduped_segment_seq"); err != nil {
+		return nil, err
+	} else if ok {
+		if _, err := tx.Exec(`UPDATE sessions SET last_deduped_segment_seq = ? WHERE id = ?`, len(plan.Segments), targetID); err != nil {
+			return nil, fmt.Errorf("update dedup cursor: %w", err)
+		}
+	}
+
+	if hasMeta {
+		// Keep the target's metadata; adopt the source's only if the
+		// target has none.
+		if _, err := tx.Exec(`UPDATE session_metadata SET session_id = ? WHERE session_id = ?
+			AND NOT EXISTS (SELECT 1 FROM session_metadata WHERE session_id = ?)`, targetID, sourceID, targetID); err != nil {
+			return nil, fmt.Errorf("move session metadata: %w", err)
+		}
+		if _, err := tx.Exec(`DELETE FROM session_metadata WHERE session_id = ?`, sourceID); err != nil {
+			return nil, fmt.Errorf("delete session metadata: %w", err)
+		}
+	}
+
+	// Annotations point at segment IDs, which survive the renumbering;
+	// only their owning session changes.
+	if hasAnnotations {
+		if _, err := tx.Exec(`UPDATE annotations SET session_id = ? WHERE session_id = ?`, targetID, sourceID); err != nil {
+			return nil, fmt.Errorf("move annotations: %w", err)
+		}
+	}
+
+	// Decisions point at segment IDs too.
+	if hasDecisions {
+		if _, err := tx.Exec(`UPDATE decisions SET session_id = ? WHERE session_id = ?`, targetID, sourceID); err != nil {
+			return nil, fmt.Errorf("move decisions: %w", err)
+		}
+	}
+
+	// So do corrections.
+	if hasEdits {
+		if _, err := tx.Exec(`UPDATE segment_edits SET session_id = ? WHERE session_id = ?`, targetID, sourceID); err != nil {
+			return nil, fmt.Errorf("move segment edits: %w", err)
+		}
+	}
+
+	// So do clean-verbatim rewrites.
+	if hasCleanups {
+		if _, err := tx.Exec(`UPDATE segment_cleanups SET session_id = ? WHERE session_id = ?`, targetID, sourceID); err != nil {
+			return nil, fmt.Errorf("move segment cleanups: %w", err)
+		}
+	}
+
+	// So do speaker relabelings, which stay undoable after the merge.
+	if hasRelabels {
+		if _, err := tx.Exec(`UPDATE speaker_relabels SET session_id = ? WHERE session_id = ?`, targetID, sourceID); err != nil {
+			return nil, fmt.Errorf("move speaker relabels: %w", err)
+		}
+	}
+
+	// Speaker names: the target's win where both named a label.
+	if hasSpeakers {
+		if _, err := tx.Exec(`INSERT OR IGNORE INTO speaker_names (session_id, label, name)
+			SELECT ?, label, name FROM speaker_names WHERE session_id = ?`, targetID, sourceID); err != nil {
+			return nil, fmt.Errorf("move speaker names: %w", err)
+		}
+		if _, err := tx.Exec(`DELETE FROM speaker_names WHERE session_id = ?`, sourceID); err != nil {
+			return nil, fmt.Errorf("delete speaker names: %w", err)
+		}
+	}
+
+	// Notes: the source's follow the target's, so neither is lost.
+	if hasNotes {
+		if _, err := tx.Exec(`
+			INSERT INTO session_notes (session_id, body, updated_at)
+			SELECT ?, body, updated_at FROM session_notes WHERE session_id = ?
+			ON CONFLICT(session_id) DO UPDATE SET
+				body = session_notes.body || char(10) || char(10) || excluded.body,
+				updated_at = MAX(session_notes.updated_at, excluded.updated_at)
+		`, targetID, sourceID); err != nil {
+			return nil, fmt.Errorf("move session notes: %w", err)
+		}
+		if _, err := tx.Exec(`DELETE FROM session_notes WHERE session_id = ?`, sourceID); err != nil {
+			return nil, fmt.Errorf("delete session notes: %w", err)
+		}
+	}
+
+	// View prefs: the target's win; the source's apply if it had none.
+	if hasViewPrefs {
+		if _, err := tx.Exec(`
+			INSERT OR IGNORE INTO session_view_prefs (session_id, density, filter_word, filter_pattern, filter_sources, expanded_topics, updated_at)
+			SELECT ?, density, filter_word, filter_pattern, filter_sources, expanded_topics, updated_at
+			FROM session_view_prefs WHERE session_id = ?
+		`, targetID, sourceID); err != nil {
+			return nil, fmt.Errorf("move view prefs: %w", err)
+		}
+		if _, err := tx.Exec(`DELETE FROM session_view_prefs WHERE session_id = ?`, sourceID); err != nil {
+			return nil, fmt.Errorf("delete view prefs: %w", err)
+		}
+	}
+
+	// Quality reports add up, as the two sessions' segments do; only the
+	// duration is measured again, over the merged timeline. A session
+	// without a report adds nothing to the other's.
+	if hasQuality {
+		if _, err := tx.Exec(`
+			INSERT INTO session_quality (session_id, segments, low_confidence, clipped, gaps, gap_seconds,
+				duration_seconds, device, system_audio, created_at)
+			SELECT ?, segments, low_confidence, clipped, gaps, gap_seconds, duration_seconds, device, system_audio, created_at
+			FROM session_quality WHERE session_id = ?
+			ON CONFLICT(session_id) DO UPDATE SET
+				segments = session_quality.segments + excluded.segments,
+				low_confidence = session_quality.low_confidence + excluded.low_confidence,
+				clipped = session_quality.clipped + excluded.clipped,
+				gaps = session_quality.gaps + excluded.gaps,
+				gap_seconds = session_quality.gap_seconds + excluded.gap_seconds,
+				device = COALESCE(session_quality.device, excluded.device),
+				system_audio = COALESCE(session_quality.system_audio, excluded.system_audio),
+				created_at = MAX(session_quality.created_at, excluded.created_at)
+		`, targetID, sourceID); err != nil {
+			return nil, fmt.Errorf("move session quality: %w", err)
+		}
+		if _, err := tx.Exec(`DELETE FROM session_quality WHERE session_id = ?`, sourceID); err != nil {
+			return nil, fmt.Errorf("delete session quality: %w", err)
+		}
+		if _, err := tx.Exec(`
+			UPDATE session_quality SET duration_seconds = COALESCE((
+				SELECT MAX(endedAt) - MIN(startedAt) FROM segments WHERE sessionId = ? AND duplicate_of IS NULL
+			), 0) WHERE session_id = ?
+		`, targetID, targetID); err != nil {
+			return nil, fmt.Errorf("update session quality: %w", err)
+		}
+	}
+
+	if _, err := tx.Exec(`DELETE FROM sessions WHERE id = ?`, sourceID); err != nil {
+		return nil, fmt.Errorf("delete source session: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit merge: %w", err)
+	}
+	return plan, nil
+}
+
+// hasColumn reports whether table has the named column.
+func hasColumn(q interface {
+	Query(string, ...any) (*sql.Rows, error)
+}, table, column string) (bool, error) {
+	rows, err := q.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return false, fmt.Errorf("table info %s: %w", table, err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var cid, notNull, pk int
+		var name, typ string
+		var dflt sql.NullString
+		if err := rows.Scan(&cid, &name, &typ, &notNull, &dflt, &pk); err != nil {
+			return false, err
+		}
+		if name == column {
+			return true, nil
+		}
+	}
+	return false, rows.Err()
+}
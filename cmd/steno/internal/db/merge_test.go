@@ -0,0 +1,313 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// seedSplitMeeting creates two completed sessions that a crash split out
+// of one meeting, overlapping in time so their segments interleave:
+//
+//	part-a: seqs 1-4 at t=0,10,20,30 (topic over 1-4)
+//	part-b: seqs 1-3 at t=15,25,40   (topic over 1-3, summary over 2-3)
+func seedSplitMeeting(t *testing.T, rawDB *sql.DB) {
+	t.Helper()
+	base := 1710000000.0
+	rawDB.Exec(`INSERT INTO sessions (id, locale, startedAt, endedAt, title, status, createdAt)
+		VALUES ('part-a', 'en_US', ?, ?, 'Weekly Sync', 'interrupted', ?)`, base, base+35, base)
+	rawDB.Exec(`INSERT INTO sessions (id, locale, startedAt, endedAt, status, createdAt)
+		VALUES ('part-b', 'en_US', ?, ?, 'completed', ?)`, base+15, base+45, base+15)
+	for i, off := range []float64{0, 10, 20, 30} {
+		rawDB.Exec(`INSERT INTO segments (id, sessionId, text, startedAt, endedAt, sequenceNumber, createdAt)
+			VALUES (?, 'part-a', ?, ?, ?, ?, ?)`, fmt.Sprintf("a%d", i+1), fmt.Sprintf("A%d", i+1), base+off, base+off+5, i+1, base+off)
+	}
+	for i, off := range []float64{15, 25, 40} {
+		rawDB.Exec(`INSERT INTO segments (id, sessionId, text, startedAt, endedAt, sequenceNumber, createdAt)
+			VALUES (?, 'part-b', ?, ?, ?, ?, ?)`, fmt.Sprintf("b%d", i+1), fmt.Sprintf("B%d", i+1), base+off, base+off+5, i+1, base+off)
+	}
+	rawDB.Exec(`INSERT INTO topics (id, sessionId, title, summary, segmentRangeStart, segmentRangeEnd, createdAt)
+		VALUES ('ta', 'part-a', 'Roadmap', 'Roadmap review', 1, 4, ?)`, base)
+	rawDB.Exec(`INSERT INTO topics (id, sessionId, title, summary, segmentRangeStart, segmentRangeEnd, createdAt)
+		VALUES ('tb', 'part-b', 'Hiring', 'Hiring plan', 1, 3, ?)`, base)
+	rawDB.Exec(`INSERT INTO summaries (id, sessionId, content, summaryType, segmentRangeStart, segmentRangeEnd, modelId, createdAt)
+		VALUES ('sb', 'part-b', 'Summary', 'rolling', 2, 3, 'm', ?)`, base)
+}
+
+func TestPlanMergeInterleavesByTime(t *testing.T) {
+	rawDB := createTestDB(t)
+	defer rawDB.Close()
+	seedSplitMeeting(t, rawDB)
+	store := &Store{db: rawDB}
+
+	plan, err := store.PlanMerge("part-a", "part-b")
+	if err != nil {
+		t.Fatalf("PlanMerge: %v", err)
+	}
+
+	var order []string
+	for _, seg := range plan.Segments {
+		order = append(order, seg.Text)
+	}
+	want := []string{"A1", "A2", "B1", "A3", "B2", "A4", "B3"}
+	if fmt.Sprint(order) != fmt.Sprint(want) {
+		t.Errorf("order = %v, want %v", order, want)
+	}
+	if plan.Interleaved != 2 {
+		t.Errorf("Interleaved = %d, want 2 (B1, B2)", plan.Interleaved)
+	}
+
+	ranges := map[string][2]int{}
+	for _, r := range append(plan.Topics, plan.Summaries...) {
+		ranges[r.ID] = [2]int{r.NewStart, r.NewEnd}
+	}
+	if ranges["ta"] != [2]int{1, 6} || ranges["tb"] != [2]int{3, 7} || ranges["sb"] != [2]int{5, 7} {
+		t.Errorf("remapped ranges = %v", ranges)
+	}
+
+	// Dry run: nothing written.
+	var n int
+	rawDB.QueryRow(`SELECT COUNT(*) FROM sessions`).Scan(&n)
+	if n != 2 {
+		t.Errorf("PlanMerge wrote to the database: %d sessions", n)
+	}
+}
+
+func TestMergeSessionsApplies(t *testing.T) {
+	rawDB := createTestDB(t)
+	defer rawDB.Close()
+	seedSplitMeeting(t, rawDB)
+	store := &Store{db: rawDB}
+	if err := store.EnsureClientSchema(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.AddAnnotation("part-b", 1, AnnotationNote, "check B1"); err != nil {
+		t.Fatal(err)
+	}
+	store.SetSpeakerName("part-a", "Speaker 1", "Ana")
+	store.SetSpeakerName("part-b", "Speaker 1", "Anna")
+	store.SetSpeakerName("part-b", "Speaker 2", "Bo")
+	if err := store.RecordDecision("part-b", 3, "we agreed"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.EditSegment("part-b", 2, "B two"); err != nil {
+		t.Fatal(err)
+	}
+	store.SetSessionNotes("part-a", "Before the crash")
+	store.SetSessionNotes("part-b", "After the crash")
+	store.SetViewPrefs("part-b", ViewPrefs{Density: "compact"})
+	yes := true
+	store.SaveSessionQuality(SessionQuality{SessionID: "part-a", Segments: 4, LowConfidence: 1, Gaps: 1, GapTime: 12 * time.Second, Duration: 35 * time.Second, Device: "MacBook Pro Microphone"})
+	store.SaveSessionQuality(SessionQuality{SessionID: "part-b", Segments: 3, LowConfidence: 2, Clipped: 1, Duration: 30 * time.Second, Device: "USB Mic", SystemAudio: &yes})
+
+	if _, err := store.MergeSessions("part-a", "part-b"); err != nil {
+		t.Fatalf("MergeSessions: %v", err)
+	}
+
+	if sess, _ := store.GetSession("part-b"); sess != nil {
+		t.Error("source session should be deleted")
+	}
+	sess, err := store.GetSession("part-a")
+	if err != nil || sess == nil {
+		t.Fatalf("GetSession: %v", err)
+	}
+	if sess.EndedAt == nil || sess.EndedAt.Unix() != 1710000045 {
+		t.Errorf("endedAt = %v, want source's later end", sess.EndedAt)
+	}
+	if sess.Title != "Weekly Sync" {
+		t.Errorf("title = %q", sess.Title)
+	}
+
+	segs, err := store.SegmentsForSession("part-a", 100, 0)
+	if err != nil {
+		t.Fatalf("SegmentsForSession: %v", err)
+	}
+	if len(segs) != 7 {
+		t.Fatalf("got %d segments, want 7", len(segs))
+	}
+	for i, seg := range segs {
+		if seg.SequenceNumber != i+1 {
+			t.Errorf("segs[%d].SequenceNumber = %d, want %d", i, seg.SequenceNumber, i+1)
+		}
+	}
+	if segs[2].Text != "B1" {
+		t.Errorf("segs[2] = %q, want B1", segs[2].Text)
+	}
+
+	topics, _ := store.TopicsForSession("part-a")
+	if len(topics) != 2 {
+		t.Errorf("got %d topics, want 2", len(topics))
+	}
+	sums, _ := store.SummariesForSession("part-a")
+	if len(sums) != 1 || sums[0].SegmentRangeStart != 5 {
+		t.Errorf("summaries = %+v", sums)
+	}
+
+	// The annotation follows its segment to the new number.
+	anns, _ := store.AnnotationsForSession("part-a")
+	if len(anns) != 1 || anns[0].SegmentSeq != 3 || anns[0].SegmentText != "B1" {
+		t.Errorf("annotations = %+v, want note on merged #3", anns)
+	}
+
+	// So does the decision, like the annotation.
+	if ds, _ := store.DecisionsForSession("part-a"); len(ds) != 1 || ds[0].SegmentSeq != 7 || ds[0].SegmentText != "B3" {
+		t.Errorf("decisions = %+v, want B3 on merged #7", ds)
+	}
+
+	// Corrections follow their segments into the target.
+	samples, err := store.CalibrationSamples()
+	if err != nil {
+		t.Fatalf("CalibrationSamples: %v", err)
+	}
+	var edited []string
+	for _, c := range samples {
+		if c.SessionID != "part-a" {
+			t.Errorf("sample of %s, want part-a only", c.SessionID)
+		}
+		if c.Edited {
+			edited = append(edited, fmt.Sprintf("#%d %s", c.SegmentSeq, c.Corrected))
+		}
+	}
+	if fmt.Sprint(edited) != "[#5 B two]" {
+		t.Errorf("edited = %v, want B2's correction on merged #5", edited)
+	}
+
+	// The quality reports add up, over the merged timeline.
+	q, err := store.SessionQualityFor("part-a")
+	if err != nil || q == nil {
+		t.Fatalf("SessionQualityFor = %v, %v", q, err)
+	}
+	if q.Segments != 7 || q.LowConfidence != 3 || q.Clipped != 1 || q.Gaps != 1 || q.GapTime != 12*time.Second || q.Duration != 45*time.Second {
+		t.Errorf("quality = %+v, want both reports' counts over 45s", q)
+	}
+	if q.Device != "MacBook Pro Microphone" || q.SystemAudio == nil || !*q.SystemAudio {
+		t.Errorf("quality device = %q, %v, want the target's, filled in from the source's", q.Device, q.SystemAudio)
+	}
+	if q, _ := store.SessionQualityFor("part-b"); q != nil {
+		t.Errorf("source quality = %+v, want it gone", q)
+	}
+
+	// Speaker names move too; the target's own win.
+	if names, _ := store.SpeakerNames("part-a"); len(names) != 2 || names["Speaker 1"] != "Ana" || names["Speaker 2"] != "Bo" {
+		t.Errorf("speaker names = %v", names)
+	}
+
+	// Notes are kept from both, the target's first.
+	if notes, _ := store.SessionNotes("part-a"); notes != "Before the crash\n\nAfter the crash" {
+		t.Errorf("notes = %q", notes)
+	}
+	var orphans int
+	rawDB.QueryRow(`SELECT COUNT(*) FROM session_notes WHERE session_id = 'part-b'`).Scan(&orphans)
+	if orphans != 0 {
+		t.Error("the source's notes should be moved")
+	}
+
+	// The source's view prefs apply, as the target had none.
+	if p, _ := store.ViewPrefs("part-a"); p == nil || p.Density != "compact" {
+		t.Errorf("view prefs = %+v, want the source's", p)
+	}
+}
+
+func TestPlanMergeRefusesActiveSession(t *testing.T) {
+	rawDB := createTestDB(t)
+	defer rawDB.Close()
+	seedTestData(t, rawDB) // sess-2 is active
+	store := &Store{db: rawDB}
+
+	if _, err := store.PlanMerge("sess-1", "sess-2"); err == nil {
+		t.Error("expected error merging an active session")
+	}
+	if _, err := store.PlanMerge("sess-1", "sess-1"); err == nil {
+		t.Error("expected error merging a session into itself")
+	}
+	if _, err := store.PlanMerge("sess-1", "missing"); err == nil {
+		t.Error("expected error for unknown session")
+	}
+}
+
+// seedDriftedSessions creates two finished sessions whose clocks
+// disagree: part-b's mic overlaps part-a's, and part-a's clock went back
+// before its third segment. Its system audio overlaps the mic, as it
+// may.
+//
+//	part-a: mic seqs 1-3 at t=0,10,4; system audio seq 4 at t=11
+//	part-b: mic seqs 1-2 at t=2,17
+func seedDriftedSessions(t *testing.T, rawDB *sql.DB) {
+	t.Helper()
+	base := 1710000000.0
+	rawDB.Exec(`INSERT INTO sessions (id, locale, startedAt, endedAt, status, createdAt)
+		VALUES ('part-a', 'en_US', ?, ?, 'interrupted', ?)`, base, base+16, base)
+	rawDB.Exec(`INSERT INTO sessions (id, locale, startedAt, endedAt, status, createdAt)
+		VALUES ('part-b', 'en_US', ?, ?, 'completed', ?)`, base+2, base+22, base+2)
+	for i, seg := range []struct {
+		session, source string
+		seq             int
+		off             float64
+	}{
+		{"part-a", "microphone", 1, 0}, {"part-a", "microphone", 2, 10}, {"part-a", "microphone", 3, 4}, {"part-a", "systemAudio", 4, 11},
+		{"part-b", "microphone", 1, 2}, {"part-b", "microphone", 2, 17},
+	} {
+		rawDB.Exec(`INSERT INTO segments (id, sessionId, text, startedAt, endedAt, sequenceNumber, createdAt, source)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)`, fmt.Sprintf("s%d", i+1), seg.session, fmt.Sprintf("%s/%d", seg.session, seg.seq),
+			base+seg.off, base+seg.off+5, seg.seq, base+seg.off, seg.source)
+	}
+}
+
+func TestPlanMergeCorrectsClockDrift(t *testing.T) {
+	rawDB := createTestDB(t)
+	defer rawDB.Close()
+	seedDriftedSessions(t, rawDB)
+	store := &Store{db: rawDB}
+
+	plan, err := store.PlanMerge("part-a", "part-b")
+	if err != nil {
+		t.Fatalf("PlanMerge: %v", err)
+	}
+	want := []TimeAdjustment{
+		// part-a #3 overlapped #2 (10-15), so it starts at 15, and the
+		// system audio after it moves along.
+		{SessionID: "part-a", FromSeq: 3, ToSeq: 4, Shift: 11 * time.Second, Reason: "clock went back"},
+		// part-b #1 (2-7) overlapped part-a #1 (0-5): three seconds clear
+		// it, and put #2 right after part-a #3.
+		{SessionID: "part-b", FromSeq: 1, ToSeq: 2, Shift: 3 * time.Second, Reason: "overlapped part-a"},
+	}
+	if fmt.Sprint(plan.TimeAdjustments) != fmt.Sprint(want) {
+		t.Errorf("TimeAdjustments = %+v, want %+v", plan.TimeAdjustments, want)
+	}
+
+	var order []string
+	for _, seg := range plan.Segments {
+		order = append(order, seg.Text)
+	}
+	if got := fmt.Sprint(order); got != "[part-a/1 part-b/1 part-a/2 part-a/3 part-b/2 part-a/4]" {
+		t.Errorf("order = %s", got)
+	}
+	if plan.EndedAt == nil || plan.EndedAt.Unix() != 1710000027 {
+		t.Errorf("EndedAt = %v, want the moved system audio's end", plan.EndedAt)
+	}
+
+	if _, err := store.MergeSessions("part-a", "part-b"); err != nil {
+		t.Fatalf("MergeSessions: %v", err)
+	}
+	var startedAt float64
+	rawDB.QueryRow(`SELECT startedAt FROM segments WHERE id = 's3'`).Scan(&startedAt)
+	if startedAt != 1710000015 {
+		t.Errorf("part-a #3 startedAt = %v, want 1710000015", startedAt)
+	}
+}
+
+func TestPlanMergeKeepsInterleavedTimes(t *testing.T) {
+	rawDB := createTestDB(t)
+	defer rawDB.Close()
+	seedSplitMeeting(t, rawDB)
+	store := &Store{db: rawDB}
+
+	plan, err := store.PlanMerge("part-a", "part-b")
+	if err != nil {
+		t.Fatalf("PlanMerge: %v", err)
+	}
+	if len(plan.TimeAdjustments) != 0 {
+		t.Errorf("segments that only touch were moved: %+v", plan.TimeAdjustments)
+	}
+}
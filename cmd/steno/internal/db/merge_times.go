@@ -0,0 +1,130 @@
+package db
+
+import (
+	"sort"
+	"time"
+)
+
+// driftTolerance is how far two segments of one source may overlap
+// before a merge takes it for a clock jump rather than ASR jitter at a
+// segment boundary.
+const driftTolerance = time.Second
+
+// TimeAdjustment is a run of one session's segments whose timestamps a
+// merge moves forward. One source can't be heard twice at once, so
+// segments of it that overlap mean the clock jumped.
+type TimeAdjustment struct {
+	SessionID string
+	// FromSeq and ToSeq are the run's first and last sequence numbers
+	// before the merge.
+	FromSeq int
+	ToSeq   int
+	Shift   time.Duration
+	Reason  string
+}
+
+// normalizeTimes moves segments forward, in place, to undo clock jumps,
+// and returns the runs it moved in the order it found them. Within each
+// session, a segment overlapping an earlier one from the same source
+// moves with the rest of its session to start where that one ended.
+// Then, when the two sessions still overlap for some source, the
+// later-started one (laterID) moves by the least that separates them.
+// Segments that merely interleave are left alone.
+func normalizeTimes(segs []MergedSegment, earlierID, laterID string) []TimeAdjustment {
+	bySession := map[string][]*MergedSegment{}
+	for i := range segs {
+		seg := &segs[i]
+		bySession[seg.FromSessionID] = append(bySession[seg.FromSessionID], seg)
+	}
+	for _, run := range bySession {
+		sort.Slice(run, func(i, j int) bool { return run[i].OldSeq < run[j].OldSeq })
+	}
+
+	var adjs []TimeAdjustment
+	for _, id := range []string{earlierID, laterID} {
+		run := bySession[id]
+		var shift time.Duration
+		ends := map[string]time.Time{}
+		first := len(adjs)
+		for _, seg := range run {
+			seg.move(shift)
+			if end, ok := ends[seg.Source]; ok && end.Sub(seg.StartedAt) > driftTolerance {
+				jump := end.Sub(seg.StartedAt)
+				seg.move(jump)
+				shift += jump
+				adjs = append(adjs, TimeAdjustment{SessionID: id, FromSeq: seg.OldSeq, Shift: jump, Reason: "clock went back"})
+			}
+			if end, ok := ends[seg.Source]; !ok || seg.EndedAt.After(end) {
+				ends[seg.Source] = seg.EndedAt
+			}
+		}
+		for i := first; i < len(adjs); i++ {
+			adjs[i].ToSeq = run[len(run)-1].OldSeq
+		}
+	}
+
+	earlier, later := bySession[earlierID], bySession[laterID]
+	var shift time.Duration
+	for {
+		more := firstOverlap(earlier, later, shift)
+		if more == 0 {
+			break
+		}
+		shift += more
+	}
+	if shift > 0 {
+		for _, seg := range later {
+			seg.move(shift)
+		}
+		adjs = append(adjs, TimeAdjustment{
+			SessionID: laterID,
+			FromSeq:   later[0].OldSeq,
+			ToSeq:     later[len(later)-1].OldSeq,
+			Shift:     shift,
+			Reason:    "overlapped " + earlierID,
+		})
+	}
+	return adjs
+}
+
+// move shifts the segment's times by d.
+func (seg *MergedSegment) move(d time.Duration) {
+	if d == 0 {
+		return
+	}
+	seg.StartedAt = seg.StartedAt.Add(d)
+	seg.EndedAt = seg.EndedAt.Add(d)
+	seg.Shift += d
+}
+
+// firstOverlap returns how much further than shift later must move to
+// clear the first segment of earlier it overlaps from the same source,
+// or zero when it overlaps none.
+func firstOverlap(earlier, later []*MergedSegment, shift time.Duration) time.Duration {
+	for _, l := range later {
+		start, end := l.StartedAt.Add(shift), l.EndedAt.Add(shift)
+		for _, e := range earlier {
+			if e.Source != l.Source {
+				continue
+			}
+			if overlap := minTime(e.EndedAt, end).Sub(maxTime(e.StartedAt, start)); overlap > driftTolerance {
+				return e.EndedAt.Sub(start)
+			}
+		}
+	}
+	return 0
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,244 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// UpsertSessionMetadata records the device / system-audio configuration
+// and context for a session. Empty fields keep what was recorded before.
+// Requires a Store opened with OpenClient.
+func (s *Store) UpsertSessionMetadata(md SessionMetadata) error {
+	if md.SessionID == "" {
+		return fmt.Errorf("upsert session metadata: empty session id")
+	}
+	updatedAt := md.UpdatedAt
+	if updatedAt.IsZero() {
+		updatedAt = time.Now()
+	}
+	var sys sql.NullInt64
+	if md.SystemAudio != nil {
+		sys = sql.NullInt64{Int64: boolToInt(*md.SystemAudio), Valid: true}
+	}
+	_, err := s.db.Exec(`
+		INSERT INTO session_metadata (session_id, device, system_audio, foreground_app, meeting_url, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(session_id) DO UPDATE SET
+			device = COALESCE(excluded.device, session_metadata.device),
+			system_audio = COALESCE(excluded.system_audio, session_metadata.system_audio),
+			foreground_app = COALESCE(excluded.foreground_app, session_metadata.foreground_app),
+			meeting_url = COALESCE(excluded.meeting_url, session_metadata.meeting_url),
+			updated_at = excluded.updated_at
+	`, md.SessionID, nullString(md.Device), sys, nullString(md.ForegroundApp), nullString(md.MeetingURL), unixFromTime(updatedAt))
+	if err != nil {
+		return fmt.Errorf("upsert session metadata: %w", err)
+	}
+	return nil
+}
+
+// SessionMetadataFor returns the recorded metadata for a session, or nil
+// when none exists (including when the table itself is absent).
+func (s *Store) SessionMetadataFor(sessionID string) (*SessionMetadata, error) {
+	ok, err := s.hasTable("session_metadata")
+	if err != nil || !ok {
+		return nil, err
+	}
+	context, err := s.metadataContextSQL("")
+	if err != nil {
+		return nil, err
+	}
+	row := s.db.QueryRow(`
+		SELECT session_id, device, system_audio, `+context+`, updated_at
+		FROM session_metadata WHERE session_id = ?
+	`, sessionID)
+
+	var md SessionMetadata
+	var device, app, meetingURL sql.NullString
+	var sys sql.NullInt64
+	var updatedAt float64
+	if err := row.Scan(&md.SessionID, &device, &sys, &app, &meetingURL, &updatedAt); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("scan session metadata: %w", err)
+	}
+	md.Device = device.String
+	md.SystemAudio = nullIntToBoolPtr(sys)
+	md.ForegroundApp = app.String
+	md.MeetingURL = meetingURL.String
+	md.UpdatedAt = timeFromUnix(updatedAt)
+	return &md, nil
+}
+
+// metadataContextSQL returns the select list for the foreground_app and
+// meeting_url columns, qualified with prefix. A read-only Store can see a
+// session_metadata table created before those columns existed; it reads
+// them as NULL.
+func (s *Store) metadataContextSQL(prefix string) (string, error) {
+	ok, err := hasColumn(s.db, "session_metadata", "meeting_url")
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "NULL, NULL", nil
+	}
+	return prefix + "foreground_app, " + prefix + "meeting_url", nil
+}
+
+func nullString(v string) sql.NullString {
+	if v == "" {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: v, Valid: true}
+}
+
+// SessionDevices returns the distinct devices recorded in
+// session_metadata, alphabetically. Used to populate device filters.
+func (s *Store) SessionDevices() ([]string, error) {
+	ok, err := s.hasTable("session_metadata")
+	if err != nil || !ok {
+		return nil, err
+	}
+	rows, err := s.db.Query(`
+		SELECT DISTINCT device FROM session_metadata
+		WHERE device IS NOT NULL AND device != ''
+		ORDER BY device ASC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("query session devices: %w", err)
+	}
+	defer rows.Close()
+
+	var devices []string
+	for rows.Next() {
+		var d string
+		if err := rows.Scan(&d); err != nil {
+			return nil, fmt.Errorf("scan device: %w", err)
+		}
+		devices = append(devices, d)
+	}
+	return devices, rows.Err()
+}
+
+// FilterSessions returns sessions (newest first) with counts and any
+// recorded metadata, narrowed by f.
+func (s *Store) FilterSessions(f SessionFilter) ([]SessionWithCounts, error) {
+	hasMeta, err := s.hasTable("session_metadata")
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT s.id, s.locale, s.startedAt, s.endedAt, s.title, s.status, s.createdAt`
+	if hasMeta {
+		context, err := s.metadataContextSQL("md.")
+		if err != nil {
+			return nil, err
+		}
+		query += `, md.device, md.system_audio, ` + context + ` FROM sessions s
+			LEFT JOIN session_metadata md ON md.session_id = s.id WHERE 1=1`
+	} else {
+		query += `, NULL, NULL, NULL, NULL FROM sessions s WHERE 1=1`
+	}
+	var args []any
+
+	if f.Status != "" {
+		query += ` AND s.status = ?`
+		args = append(args, f.Status)
+	}
+	if f.Locale != "" {
+		query += ` AND s.locale = ?`
+		args = append(args, f.Locale)
+	}
+	if f.After != nil {
+		query += ` AND s.startedAt >= ?`
+		args = append(args, float64(f.After.Unix()))
+	}
+	if f.Before != nil {
+		query += ` AND s.startedAt <= ?`
+		args = append(args, float64(f.Before.Unix()))
+	}
+	if f.Device != "" || f.SystemAudio != nil {
+		if !hasMeta {
+			// Metadata filters can never match without the table.
+			return nil, nil
+		}
+		if f.Device != "" {
+			query += ` AND md.device = ?`
+			args = append(args, f.Device)
+		}
+		if f.SystemAudio != nil {
+			query += ` AND md.system_audio = ?`
+			args = append(args, boolToInt(*f.SystemAudio))
+		}
+	}
+
+	query += ` ORDER BY s.startedAt DESC`
+	if f.Limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, f.Limit)
+	}
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("filter sessions: %w", err)
+	}
+	var sessions []Session
+	for rows.Next() {
+		var sess Session
+		var startedAt, createdAt float64
+		var endedAt sql.NullFloat64
+		var title, device, app, meetingURL sql.NullString
+		var sys sql.NullInt64
+		if err := rows.Scan(&sess.ID, &sess.Locale, &startedAt, &endedAt,
+			&title, &sess.Status, &createdAt, &device, &sys, &app, &meetingURL); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan session: %w", err)
+		}
+		sess.StartedAt = timeFromUnix(startedAt)
+		sess.CreatedAt = timeFromUnix(createdAt)
+		if endedAt.Valid {
+			t := timeFromUnix(endedAt.Float64)
+			sess.EndedAt = &t
+		}
+		sess.Title = title.String
+		sess.Device = device.String
+		sess.SystemAudio = nullIntToBoolPtr(sys)
+		sess.ForegroundApp = app.String
+		sess.MeetingURL = meetingURL.String
+		sessions = append(sessions, sess)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var results []SessionWithCounts
+	for _, sess := range sessions {
+		counts, err := s.SessionCounts(sess.ID)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, SessionWithCounts{Session: sess, Counts: counts})
+	}
+	return results, nil
+}
+
+func boolToInt(b bool) int64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func nullIntToBoolPtr(v sql.NullInt64) *bool {
+	if !v.Valid {
+		return nil
+	}
+	b := v.Int64 != 0
+	return &b
+}
+
+func unixFromTime(t time.Time) float64 {
+	return float64(t.UnixNano()) / 1e9
+}
@@ -0,0 +1,184 @@
+package db
+
+import (
+	"testing"
+)
+
+func TestFilterSessionsByDeviceAndSystemAudio(t *testing.T) {
+	rawDB := createTestDB(t)
+	defer rawDB.Close()
+	seedTestData(t, rawDB)
+
+	store := &Store{db: rawDB}
+	if err := store.EnsureClientSchema(); err != nil {
+		t.Fatalf("EnsureClientSchema: %v", err)
+	}
+
+	yes, no := true, false
+	store.UpsertSessionMetadata(SessionMetadata{SessionID: "sess-1", Device: "Conference Room", SystemAudio: &no})
+	store.UpsertSessionMetadata(SessionMetadata{SessionID: "sess-2", Device: "MacBook Pro Microphone", SystemAudio: &yes})
+
+	sessions, err := store.FilterSessions(SessionFilter{Device: "Conference Room"})
+	if err != nil {
+		t.Fatalf("FilterSessions: %v", err)
+	}
+	if len(sessions) != 1 || sessions[0].Session.ID != "sess-1" {
+		t.Fatalf("got %+v, want only sess-1", sessions)
+	}
+	if sessions[0].Session.SystemAudio == nil || *sessions[0].Session.SystemAudio {
+		t.Errorf("sess-1 SystemAudio = %v, want false", sessions[0].Session.SystemAudio)
+	}
+
+	sessions, err = store.FilterSessions(SessionFilter{SystemAudio: &yes})
+	if err != nil {
+		t.Fatalf("FilterSessions: %v", err)
+	}
+	if len(sessions) != 1 || sessions[0].Session.ID != "sess-2" {
+		t.Fatalf("got %+v, want only sess-2", sessions)
+	}
+
+	// Unfiltered: all three, sess-3 without metadata.
+	sessions, err = store.FilterSessions(SessionFilter{})
+	if err != nil {
+		t.Fatalf("FilterSessions: %v", err)
+	}
+	if len(sessions) != 3 {
+		t.Fatalf("got %d sessions, want 3", len(sessions))
+	}
+	if sessions[2].Session.Device != "" || sessions[2].Session.SystemAudio != nil {
+		t.Errorf("sess-3 should have no metadata, got %+v", sessions[2].Session)
+	}
+
+	devices, err := store.SessionDevices()
+	if err != nil {
+		t.Fatalf("SessionDevices: %v", err)
+	}
+	if len(devices) != 2 || devices[0] != "Conference Room" {
+		t.Errorf("devices = %v", devices)
+	}
+}
+
+func TestUpsertSessionMetadataKeepsKnownFields(t *testing.T) {
+	rawDB := createTestDB(t)
+	defer rawDB.Close()
+	seedTestData(t, rawDB)
+
+	store := &Store{db: rawDB}
+	store.EnsureClientSchema()
+
+	yes := true
+	store.UpsertSessionMetadata(SessionMetadata{SessionID: "sess-1", Device: "Mic A", SystemAudio: &yes})
+	// A later update that only knows the device must not clear system_audio.
+	if err := store.UpsertSessionMetadata(SessionMetadata{SessionID: "sess-1", Device: "Mic B"}); err != nil {
+		t.Fatalf("UpsertSessionMetadata: %v", err)
+	}
+
+	md, err := store.SessionMetadataFor("sess-1")
+	if err != nil {
+		t.Fatalf("SessionMetadataFor: %v", err)
+	}
+	if md == nil || md.Device != "Mic B" || md.SystemAudio == nil || !*md.SystemAudio {
+		t.Errorf("metadata = %+v", md)
+	}
+}
+
+func TestFilterSessionsWithoutMetadataTable(t *testing.T) {
+	rawDB := createTestDB(t)
+	defer rawDB.Close()
+	seedTestData(t, rawDB)
+
+	store := &Store{db: rawDB}
+
+	sessions, err := store.FilterSessions(SessionFilter{})
+	if err != nil {
+		t.Fatalf("FilterSessions: %v", err)
+	}
+	if len(sessions) != 3 {
+		t.Errorf("got %d sessions, want 3", len(sessions))
+	}
+
+	sessions, err = store.FilterSessions(SessionFilter{Device: "Mic A"})
+	if err != nil {
+		t.Fatalf("FilterSessions: %v", err)
+	}
+	if len(sessions) != 0 {
+		t.Errorf("device filter without table: got %d sessions, want 0", len(sessions))
+	}
+
+	md, err := store.SessionMetadataFor("sess-1")
+	if err != nil || md != nil {
+		t.Errorf("SessionMetadataFor = %+v, %v; want nil, nil", md, err)
+	}
+}
+
+func TestSessionMetadataContext(t *testing.T) {
+	rawDB := createTestDB(t)
+	defer rawDB.Close()
+	seedTestData(t, rawDB)
+
+	store := &Store{db: rawDB}
+	store.EnsureClientSchema()
+
+	store.UpsertSessionMetadata(SessionMetadata{SessionID: "sess-1", Device: "Mic A", ForegroundApp: "zoom.us"})
+	// The meeting link arrives separately and must keep the app.
+	if err := store.UpsertSessionMetadata(SessionMetadata{SessionID: "sess-1", MeetingURL: "https://meet.example.com/abc"}); err != nil {
+		t.Fatalf("UpsertSessionMetadata: %v", err)
+	}
+
+	md, err := store.SessionMetadataFor("sess-1")
+	if err != nil {
+		t.Fatalf("SessionMetadataFor: %v", err)
+	}
+	if md.Device != "Mic A" || md.ForegroundApp != "zoom.us" || md.MeetingURL != "https://meet.example.com/abc" {
+		t.Errorf("metadata = %+v", md)
+	}
+
+	sessions, err := store.FilterSessions(SessionFilter{})
+	if err != nil {
+		t.Fatalf("FilterSessions: %v", err)
+	}
+	for _, swc := range sessions {
+		if swc.Session.ID == "sess-1" && (swc.Session.ForegroundApp != "zoom.us" || swc.Session.MeetingURL == "") {
+			t.Errorf("sess-1 = %+v", swc.Session)
+		}
+	}
+}
+
+func TestEnsureClientSchemaAddsContextColumns(t *testing.T) {
+	rawDB := createTestDB(t)
+	defer rawDB.Close()
+	seedTestData(t, rawDB)
+
+	// session_metadata as first shipped, before the context columns.
+	if _, err := rawDB.Exec(`CREATE TABLE session_metadata (
+		session_id TEXT PRIMARY KEY, device TEXT, system_audio INTEGER, updated_at REAL NOT NULL)`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rawDB.Exec(`INSERT INTO session_metadata VALUES ('sess-1', 'Mic A', 1, 0)`); err != nil {
+		t.Fatal(err)
+	}
+	store := &Store{db: rawDB}
+
+	// Read-only readers see the old table without erroring.
+	md, err := store.SessionMetadataFor("sess-1")
+	if err != nil || md == nil || md.Device != "Mic A" || md.ForegroundApp != "" {
+		t.Fatalf("before migration: %+v, %v", md, err)
+	}
+	if _, err := store.FilterSessions(SessionFilter{}); err != nil {
+		t.Fatalf("FilterSessions before migration: %v", err)
+	}
+
+	if err := store.EnsureClientSchema(); err != nil {
+		t.Fatalf("EnsureClientSchema: %v", err)
+	}
+	if err := store.EnsureClientSchema(); err != nil {
+		t.Fatalf("EnsureClientSchema is not idempotent: %v", err)
+	}
+	if err := store.UpsertSessionMetadata(SessionMetadata{SessionID: "sess-1", ForegroundApp: "Google Chrome"}); err != nil {
+		t.Fatalf("UpsertSessionMetadata: %v", err)
+	}
+	md, err = store.SessionMetadataFor("sess-1")
+	if err != nil || md.Device != "Mic A" || md.ForegroundApp != "Google Chrome" {
+		t.Errorf("after migration: %+v, %v", md, err)
+	}
+}
@@ -24,6 +24,77 @@ type Session struct {
 	// PausedIndefinitely is true when pause has no auto-resume. Privacy-
 	// critical: a corrupted/unmigrated row must not surprise-resume.
 	PausedIndefinitely bool
+
+	// Device and SystemAudio come from the client-owned session_metadata
+	// table (see SessionMetadata). Empty / nil when no row was recorded,
+	// e.g. for sessions captured while no TUI was attached.
+	Device      string
+	SystemAudio *bool
+
+	// ForegroundApp and MeetingURL are the optional context columns of
+	// session_metadata, used to tell similar meetings apart.
+	ForegroundApp string
+	MeetingURL    string
+}
+
+// SessionMetadata is the capture configuration the TUI observed for a
+// session. The daemon does not persist the device it recorded with, so the
+// TUI records it from `status` responses into session_metadata.
+type SessionMetadata struct {
+	SessionID   string
+	Device      string
+	SystemAudio *bool
+
+	// ForegroundApp is the app that had focus when the session opened.
+	// Recorded only when the TUI config enables metadata.foreground_app.
+	ForegroundApp string
+
+	// MeetingURL is the session's meeting link (Zoom, Meet, ...), set
+	// with `steno meeting`.
+	MeetingURL string
+
+	UpdatedAt time.Time
+}
+
+// SessionFilter narrows FilterSessions. Zero-valued fields match
+// everything; Limit <= 0 means no limit.
+type SessionFilter struct {
+	Limit  int
+	Before *time.Time
+	After  *time.Time
+	Status string
+	Locale string
+	// Device matches session_metadata.device exactly.
+	Device string
+	// SystemAudio, when set, keeps only sessions whose recorded flag
+	// matches. Sessions with no metadata never match a non-nil filter.
+	SystemAudio *bool
+}
+
+// Annotation kinds.
+const (
+	AnnotationBookmark = "bookmark"
+	AnnotationNote     = "note"
+	AnnotationReaction = "reaction"
+)
+
+// Annotation is a bookmark, note, or reaction the user attached to one
+// segment (client-owned `annotations` table). It references the segment
+// by ID, so it survives renumbering by `steno merge`; the Segment* fields
+// are joined in at read time.
+type Annotation struct {
+	ID        string
+	SessionID string
+	SegmentID string
+	Kind      string
+	// Body is the note text or reaction emoji; empty for bookmarks.
+	Body      string
+	CreatedAt time.Time
+
+	SegmentSeq       int
+	SegmentText      string
+	SegmentStartedAt time.Time
+	SegmentSource    string
 }
 
 // Segment represents a finalized transcript segment.
@@ -38,6 +109,16 @@ type Segment struct {
 	CreatedAt      time.Time
 	Source         string
 
+	// Locale is the recognizer's locale for this segment, which the
+	// daemon's set_locale can switch mid-session. Empty for segments
+	// recorded before per-segment locales: read those as the session's.
+	Locale string
+
+	// Speaker is the diarized speaker label ("Speaker 1"), stable within
+	// the session. Empty for segments nobody diarized; name a label with
+	// SetSpeakerName.
+	Speaker string
+
 	// DuplicateOf points at the canonical segment this row duplicates,
 	// when the daemon's DedupCoordinator (U11) has marked it. Nil means
 	// canonical / not yet evaluated. The default TUI/MCP query in U9
@@ -68,6 +149,11 @@ type Topic struct {
 	SegmentRangeStart int
 	SegmentRangeEnd   int
 	CreatedAt         time.Time
+
+	// UserEdited is true when Title and Summary come from a user edit
+	// (client-owned topic_edits) rather than the LLM. Regeneration must
+	// leave such topics alone.
+	UserEdited bool
 }
 
 // Summary represents an LLM-generated summary.
@@ -0,0 +1,61 @@
+package db
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// DefaultBusyTimeout is how long a statement waits out a lock the daemon
+// holds when Options.BusyTimeout is unset. The daemon writes in short
+// transactions, so a few seconds covers even a heavy summary pass.
+const DefaultBusyTimeout = 5 * time.Second
+
+// Options tunes the SQLite connections a Store opens (config `database`).
+// The zero value is the default: DefaultBusyTimeout, and SQLite's own
+// page cache and mmap settings.
+type Options struct {
+	// BusyTimeout is how long a statement waits on a lock before failing
+	// with SQLITE_BUSY ("database is locked"). Zero means
+	// DefaultBusyTimeout; negative doesn't wait.
+	BusyTimeout time.Duration
+
+	// CacheSize is the page cache per connection in bytes. Zero keeps
+	// SQLite's default (about 2 MB).
+	CacheSize int64
+
+	// MmapSize is how much of the file reads may memory-map, in bytes.
+	// Zero keeps SQLite's default (off).
+	MmapSize int64
+
+	// QueryOnly refuses every write through the handle, even a
+	// read-write one (PRAGMA query_only), so steno never modifies the
+	// database; features that save, such as topic edits and notes, then
+	// fail.
+	QueryOnly bool
+}
+
+// dsn builds the connection string for path. params are the opener's own
+// query parameters, ahead of the tuning pragmas.
+func (o Options) dsn(path string, params ...string) string {
+	q := url.Values{}
+	for i := 0; i+1 < len(params); i += 2 {
+		q.Add(params[i], params[i+1])
+	}
+	timeout := o.BusyTimeout
+	if timeout == 0 {
+		timeout = DefaultBusyTimeout
+	}
+	q.Add("_pragma", fmt.Sprintf("busy_timeout(%d)", max(0, timeout.Milliseconds())))
+	if o.CacheSize > 0 {
+		// A negative cache_size is in KiB rather than pages.
+		q.Add("_pragma", fmt.Sprintf("cache_size(-%d)", max(1, o.CacheSize/1024)))
+	}
+	if o.MmapSize > 0 {
+		q.Add("_pragma", fmt.Sprintf("mmap_size(%d)", o.MmapSize))
+	}
+	if o.QueryOnly {
+		q.Add("_pragma", "query_only(1)")
+	}
+	return "file:" + path + "?" + q.Encode()
+}
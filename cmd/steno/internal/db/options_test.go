@@ -0,0 +1,60 @@
+package db
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestOptionsPragmas(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "steno.sqlite")
+	pragma := func(s *Store, name string) int64 {
+		t.Helper()
+		var v int64
+		if err := s.db.QueryRow(`PRAGMA ` + name).Scan(&v); err != nil {
+			t.Fatalf("PRAGMA %s: %v", name, err)
+		}
+		return v
+	}
+
+	store, err := OpenClient(path, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := pragma(store, "busy_timeout"); got != DefaultBusyTimeout.Milliseconds() {
+		t.Errorf("default busy_timeout = %d, want %d", got, DefaultBusyTimeout.Milliseconds())
+	}
+	if got := pragma(store, "query_only"); got != 0 {
+		t.Errorf("default query_only = %d, want off", got)
+	}
+	store.Close()
+
+	store, err = Open(path, Options{BusyTimeout: 12 * time.Second, CacheSize: 8 << 20, MmapSize: 64 << 20, QueryOnly: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	for name, want := range map[string]int64{"busy_timeout": 12000, "cache_size": -8192, "mmap_size": 64 << 20, "query_only": 1} {
+		if got := pragma(store, name); got != want {
+			t.Errorf("%s = %d, want %d", name, got, want)
+		}
+	}
+}
+
+func TestQueryOnlyRefusesClientWrites(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "steno.sqlite")
+	store, err := OpenClient(path, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.Close()
+
+	store, err = OpenClient(path, Options{QueryOnly: true})
+	if err != nil {
+		t.Fatalf("a query-only handle should still open: %v", err)
+	}
+	defer store.Close()
+	if _, err := store.db.Exec(`INSERT INTO maintenance_runs VALUES (0, 0, 0)`); err == nil {
+		t.Error("a query-only handle should refuse writes")
+	}
+}
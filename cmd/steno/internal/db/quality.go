@@ -0,0 +1,137 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// SessionQuality is a session's capture-quality report (see
+// internal/quality), stored in the client-owned session_quality table so
+// `steno quality` can show trends across sessions.
+type SessionQuality struct {
+	SessionID string
+	// Segments counts the session's canonical segments.
+	Segments int
+	// LowConfidence counts segments the ASR scored below
+	// quality.LowConfidence.
+	LowConfidence int
+	// Clipped counts mic segments whose peak reached quality.ClipDB.
+	Clipped int
+	// Gaps counts stretches with audio but no transcription, lasting
+	// GapTime in total. Only the TUI measures them, from live levels.
+	Gaps    int
+	GapTime time.Duration
+	// Duration runs from the first segment's start to the last one's end.
+	Duration    time.Duration
+	Device      string
+	SystemAudio *bool
+	CreatedAt   time.Time
+}
+
+// LowConfidencePercent is the share of segments with low confidence, 0
+// for a session without segments.
+func (q SessionQuality) LowConfidencePercent() float64 {
+	if q.Segments == 0 {
+		return 0
+	}
+	return 100 * float64(q.LowConfidence) / float64(q.Segments)
+}
+
+// SaveSessionQuality stores a session's report, replacing any earlier one.
+// Requires a Store opened with OpenClient.
+func (s *Store) SaveSessionQuality(q SessionQuality) error {
+	if q.SessionID == "" {
+		return fmt.Errorf("save session quality: empty session id")
+	}
+	createdAt := q.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+	var sys sql.NullInt64
+	if q.SystemAudio != nil {
+		sys = sql.NullInt64{Int64: boolToInt(*q.SystemAudio), Valid: true}
+	}
+	_, err := s.db.Exec(`
+		INSERT OR REPLACE INTO session_quality (session_id, segments, low_confidence, clipped,
+			gaps, gap_seconds, duration_seconds, device, system_audio, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, q.SessionID, q.Segments, q.LowConfidence, q.Clipped, q.Gaps, q.GapTime.Seconds(),
+		q.Duration.Seconds(), nullString(q.Device), sys, unixFromTime(createdAt))
+	if err != nil {
+		return fmt.Errorf("save session quality: %w", err)
+	}
+	return nil
+}
+
+// SessionQualityFor returns a session's stored report, or nil when there
+// is none (including when the table itself is absent).
+func (s *Store) SessionQualityFor(sessionID string) (*SessionQuality, error) {
+	all, err := s.queryQuality(`WHERE session_id = ?`, sessionID)
+	if err != nil || len(all) == 0 {
+		return nil, err
+	}
+	return &all[0], nil
+}
+
+// SessionQualityHistory returns stored reports newest first. A
+// non-positive limit returns them all.
+func (s *Store) SessionQualityHistory(limit int) ([]SessionQuality, error) {
+	if limit <= 0 {
+		limit = -1
+	}
+	return s.queryQuality(`ORDER BY created_at DESC LIMIT ?`, limit)
+}
+
+func (s *Store) queryQuality(clause string, args ...any) ([]SessionQuality, error) {
+	ok, err := s.hasTable("session_quality")
+	if err != nil || !ok {
+		return nil, err
+	}
+	rows, err := s.db.Query(`
+		SELECT session_id, segments, low_confidence, clipped, gaps, gap_seconds,
+			duration_seconds, device, system_audio, created_at
+		FROM session_quality `+clause, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query session quality: %w", err)
+	}
+	defer rows.Close()
+
+	var out []SessionQuality
+	for rows.Next() {
+		var q SessionQuality
+		var gapSeconds, durationSeconds, createdAt float64
+		var device sql.NullString
+		var sys sql.NullInt64
+		if err := rows.Scan(&q.SessionID, &q.Segments, &q.LowConfidence, &q.Clipped, &q.Gaps,
+			&gapSeconds, &durationSeconds, &device, &sys, &createdAt); err != nil {
+			return nil, fmt.Errorf("scan session quality: %w", err)
+		}
+		q.GapTime = time.Duration(gapSeconds * float64(time.Second))
+		q.Duration = time.Duration(durationSeconds * float64(time.Second))
+		q.Device = device.String
+		q.SystemAudio = nullIntToBoolPtr(sys)
+		q.CreatedAt = timeFromUnix(createdAt)
+		out = append(out, q)
+	}
+	return out, rows.Err()
+}
+
+// ClippedSegments counts a session's canonical mic segments whose peak
+// level reached atDB dBFS. Zero for databases from before the daemon
+// recorded peaks.
+func (s *Store) ClippedSegments(sessionID string, atDB float64) (int, error) {
+	ok, err := hasColumn(s.db, "segments", "mic_peak_db")
+	if err != nil || !ok {
+		return 0, err
+	}
+	var n int
+	err = s.db.QueryRow(`
+		SELECT COUNT(*) FROM segments
+		WHERE sessionId = ? AND duplicate_of IS NULL AND mic_peak_db >= ?
+	`, sessionID, atDB).Scan(&n)
+	if err != nil {
+		return 0, fmt.Errorf("count clipped segments: %w", err)
+	}
+	return n, nil
+}
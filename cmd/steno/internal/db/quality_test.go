@@ -0,0 +1,85 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSessionQualityRoundTrip(t *testing.T) {
+	rawDB := createTestDB(t)
+	defer rawDB.Close()
+	seedTestData(t, rawDB)
+	store := &Store{db: rawDB}
+
+	// Readers tolerate the table not existing yet.
+	if q, err := store.SessionQualityFor("sess-1"); err != nil || q != nil {
+		t.Fatalf("before schema: %+v, %v", q, err)
+	}
+	if err := store.EnsureClientSchema(); err != nil {
+		t.Fatal(err)
+	}
+
+	on := true
+	older := SessionQuality{SessionID: "sess-3", Segments: 4, CreatedAt: time.Unix(1710000000, 0)}
+	newer := SessionQuality{
+		SessionID: "sess-1", Segments: 10, LowConfidence: 2, Clipped: 1,
+		Gaps: 1, GapTime: 12 * time.Second, Duration: time.Hour,
+		Device: "MacBook Pro Microphone", SystemAudio: &on, CreatedAt: time.Unix(1710003600, 0),
+	}
+	for _, q := range []SessionQuality{older, newer} {
+		if err := store.SaveSessionQuality(q); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Saving again replaces the earlier report.
+	newer.Clipped = 3
+	if err := store.SaveSessionQuality(newer); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := store.SessionQualityFor("sess-1")
+	if err != nil || got == nil {
+		t.Fatalf("SessionQualityFor: %+v, %v", got, err)
+	}
+	if got.Clipped != 3 || got.GapTime != 12*time.Second || got.Duration != time.Hour ||
+		got.Device != "MacBook Pro Microphone" || got.SystemAudio == nil || !*got.SystemAudio {
+		t.Errorf("report = %+v", got)
+	}
+	if pct := got.LowConfidencePercent(); pct != 20 {
+		t.Errorf("low confidence = %v%%, want 20%%", pct)
+	}
+
+	history, err := store.SessionQualityHistory(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 2 || history[0].SessionID != "sess-1" || history[1].SessionID != "sess-3" {
+		t.Errorf("history = %+v, want newest first", history)
+	}
+	if history[1].SystemAudio != nil {
+		t.Error("unknown system audio should read back as nil")
+	}
+	if limited, _ := store.SessionQualityHistory(1); len(limited) != 1 {
+		t.Errorf("limit 1 returned %d reports", len(limited))
+	}
+}
+
+func TestClippedSegments(t *testing.T) {
+	rawDB := createTestDB(t)
+	defer rawDB.Close()
+	seedTestData(t, rawDB)
+	store := &Store{db: rawDB}
+
+	for id, peak := range map[string]float64{"seg-1-1": -0.5, "seg-1-2": -1, "seg-1-3": -6} {
+		if _, err := rawDB.Exec(`UPDATE segments SET mic_peak_db = ? WHERE id = ?`, peak, id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Duplicates are hidden everywhere else, so they don't count here.
+	if _, err := rawDB.Exec(`UPDATE segments SET mic_peak_db = 0, duplicate_of = 'seg-2-1' WHERE id = 'seg-1-4'`); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := store.ClippedSegments("sess-1", -1); err != nil || n != 2 {
+		t.Errorf("clipped = %d, %v; want 2", n, err)
+	}
+}
@@ -0,0 +1,192 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Query selects segments for QuerySegments and CountSegments. Zero
+// fields don't filter, so Query{} is every canonical segment. Filters
+// combine with AND; values within one slice field combine with OR.
+//
+// Default-filter (U9): duplicates (`duplicate_of IS NOT NULL`) are always
+// excluded.
+type Query struct {
+	// Sessions limits results to these session IDs.
+	Sessions []string
+	// TimeRange limits results by segment start time.
+	TimeRange TimeRange
+	// Sources limits results to these sources ("microphone",
+	// "systemAudio").
+	Sources []string
+	// Speakers limits results to these diarized speaker labels. Only
+	// databases that record a speaker per segment can answer it; others
+	// return an error rather than an empty result.
+	Speakers []string
+	// TextMatch is a case-insensitive substring of the segment text.
+	TextMatch string
+	// MinConfidence drops segments below it, and segments without a
+	// confidence, when positive.
+	MinConfidence float64
+	// Newest orders results most recent first. Otherwise they come in
+	// transcript order: by sequence number within a session, sessions
+	// oldest first.
+	Newest bool
+	// Limit caps the results when positive; Offset skips that many
+	// first.
+	Limit  int
+	Offset int
+	// AfterSeq, when positive, keeps only segments numbered after it. It
+	// pages one session by key rather than by Offset (see SegmentsAfter),
+	// so it needs exactly one of Sessions.
+	AfterSeq int
+}
+
+// TimeRange bounds segment start times, inclusive. A nil bound is open.
+// Bounds are compared at whole-second precision.
+type TimeRange struct {
+	After, Before *time.Time
+}
+
+// QuerySegments returns the segments matching q.
+func (s *Store) QuerySegments(q Query) ([]Segment, error) {
+	cols, err := s.segmentColumns()
+	if err != nil {
+		return nil, err
+	}
+	where, args, err := s.segmentFilter(q)
+	if err != nil {
+		return nil, err
+	}
+	query := `SELECT ` + cols + ` FROM segments WHERE ` + where
+	switch {
+	case q.Newest:
+		query += ` ORDER BY startedAt DESC`
+	case len(q.Sessions) == 1:
+		query += ` ORDER BY sequenceNumber ASC`
+	default:
+		query += ` ORDER BY (SELECT startedAt FROM sessions WHERE id = segments.sessionId) ASC, sessionId, sequenceNumber ASC`
+	}
+	limit := q.Limit
+	if limit <= 0 {
+		limit = -1
+	}
+	query += ` LIMIT ? OFFSET ?`
+	args = append(args, limit, max(0, q.Offset))
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query segments: %w", err)
+	}
+	defer rows.Close()
+	return scanSegments(rows)
+}
+
+// CountSegments returns how many segments match q, ignoring its order,
+// Limit and Offset.
+func (s *Store) CountSegments(q Query) (int, error) {
+	where, args, err := s.segmentFilter(q)
+	if err != nil {
+		return 0, err
+	}
+	var n int
+	if err := s.db.QueryRow(`SELECT COUNT(*) FROM segments WHERE `+where, args...).Scan(&n); err != nil {
+		return 0, fmt.Errorf("count segments: %w", err)
+	}
+	return n, nil
+}
+
+// segmentFilter builds q's WHERE clause and its arguments.
+func (s *Store) segmentFilter(q Query) (string, []any, error) {
+	conds := []string{"duplicate_of IS NULL"}
+	var args []any
+	in := func(column string, values []string) {
+		conds = append(conds, column+" IN ("+strings.TrimSuffix(strings.Repeat("?, ", len(values)), ", ")+")")
+		for _, v := range values {
+			args = append(args, v)
+		}
+	}
+
+	if len(q.Sessions) > 0 {
+		in("sessionId", q.Sessions)
+	}
+	if q.TimeRange.After != nil {
+		conds = append(conds, "startedAt >= ?")
+		args = append(args, float64(q.TimeRange.After.Unix()))
+	}
+	if q.TimeRange.Before != nil {
+		conds = append(conds, "startedAt <= ?")
+		args = append(args, float64(q.TimeRange.Before.Unix()))
+	}
+	if len(q.Sources) > 0 {
+		in("source", q.Sources)
+	}
+	if len(q.Speakers) > 0 {
+		ok, err := hasColumn(s.db, "segments", "speaker")
+		if err != nil {
+			return "", nil, err
+		}
+		if !ok {
+			return "", nil, fmt.Errorf("query segments: this database doesn't record speakers per segment")
+		}
+		in("speaker", q.Speakers)
+	}
+	if q.TextMatch != "" {
+		conds = append(conds, `text LIKE ? ESCAPE '\'`)
+		args = append(args, "%"+escapeLike(q.TextMatch)+"%")
+	}
+	if q.MinConfidence > 0 {
+		conds = append(conds, "confidence >= ?")
+		args = append(args, q.MinConfidence)
+	}
+	if q.AfterSeq > 0 {
+		if len(q.Sessions) != 1 {
+			return "", nil, fmt.Errorf("query segments: AfterSeq needs exactly one session")
+		}
+		conds = append(conds, "sequenceNumber > ?")
+		args = append(args, q.AfterSeq)
+	}
+	return strings.Join(conds, " AND "), args, nil
+}
+
+// SegmentPageSize is the page SegmentPages reads a session in.
+const SegmentPageSize = 1000
+
+// SegmentsAfter returns up to limit of a session's segments, in order,
+// starting after sequence number afterSeq (from the start when 0). The
+// next page starts after the last one returned, so a page costs the same
+// however deep into the session it is: the query seeks the partial
+// (sessionId, sequenceNumber) index the daemon keeps for canonical
+// segments (idx_segments_dedup) instead of stepping over an offset's
+// worth of rows, and the index supplies the order. A non-positive limit
+// returns the rest of the session.
+//
+// Default-filter (U9): excludes `duplicate_of IS NOT NULL`.
+func (s *Store) SegmentsAfter(sessionID string, afterSeq, limit int) ([]Segment, error) {
+	return s.QuerySegments(Query{Sessions: []string{sessionID}, AfterSeq: afterSeq, Limit: limit})
+}
+
+// SegmentPages walks a session's segments in order, SegmentPageSize at a
+// time, calling fn with each page. It stops at the end of the session or
+// at the first error, fn's included. This is the access path for reading
+// a whole session, however long: memory holds one page, and each page is
+// one index seek (see SegmentsAfter).
+func (s *Store) SegmentPages(sessionID string, fn func(page []Segment) error) error {
+	after := 0
+	for {
+		page, err := s.SegmentsAfter(sessionID, after, SegmentPageSize)
+		if err != nil {
+			return err
+		}
+		if len(page) > 0 {
+			if err := fn(page); err != nil {
+				return err
+			}
+			after = page[len(page)-1].SequenceNumber
+		}
+		if len(page) < SegmentPageSize {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestQuerySegments(t *testing.T) {
+	rawDB := createTestDB(t)
+	defer rawDB.Close()
+	seedTestData(t, rawDB)
+	store := &Store{db: rawDB}
+	rawDB.Exec(`UPDATE segments SET confidence = NULL WHERE id = 'seg-1-1'`)
+	rawDB.Exec(`UPDATE segments SET duplicate_of = 'seg-2-1' WHERE id = 'seg-1-10'`)
+
+	after := time.Unix(1710000050, 0)
+	before := time.Unix(1710000080, 0)
+	for _, tc := range []struct {
+		name  string
+		q     Query
+		want  []string
+		count int
+	}{
+		{"all, sessions oldest first", Query{Limit: 2}, []string{"seg-1-1", "seg-1-2"}, 12},
+		{"offset into the next session", Query{Offset: 9, Limit: 2}, []string{"seg-2-1", "seg-2-2"}, 12},
+		{"one session", Query{Sessions: []string{"sess-2"}}, []string{"seg-2-1", "seg-2-2", "seg-2-3"}, 3},
+		{"time range", Query{TimeRange: TimeRange{After: &after, Before: &before}}, []string{"seg-1-5", "seg-1-6", "seg-1-7", "seg-1-8"}, 4},
+		{"source", Query{Sources: []string{"systemAudio"}, Newest: true, Limit: 1}, []string{"seg-2-3"}, 3},
+		{"text, case-insensitive", Query{TextMatch: "ACTIVE session"}, []string{"seg-2-1", "seg-2-2", "seg-2-3"}, 3},
+		{"text is literal", Query{TextMatch: "100%"}, nil, 0},
+		{"confidence", Query{Sessions: []string{"sess-1"}, MinConfidence: 0.98}, []string{"seg-1-8", "seg-1-9"}, 2},
+		{"after a segment", Query{Sessions: []string{"sess-1"}, AfterSeq: 7, Limit: 1}, []string{"seg-1-8"}, 2},
+		{"combined", Query{Sessions: []string{"sess-1", "sess-2"}, Sources: []string{"microphone"}, TextMatch: "segment 1 "}, []string{"seg-1-1"}, 1},
+	} {
+		segs, err := store.QuerySegments(tc.q)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		var ids []string
+		for _, s := range segs {
+			ids = append(ids, s.ID)
+		}
+		if strings.Join(ids, ",") != strings.Join(tc.want, ",") {
+			t.Errorf("%s: got %v, want %v", tc.name, ids, tc.want)
+		}
+		if n, err := store.CountSegments(tc.q); err != nil || n != tc.count {
+			t.Errorf("%s: count = %d, %v, want %d", tc.name, n, err, tc.count)
+		}
+	}
+
+	if _, err := store.QuerySegments(Query{AfterSeq: 3}); err == nil {
+		t.Error("expected an error for AfterSeq without a session")
+	}
+
+	// No per-segment speakers in this schema: say so rather than match
+	// nothing.
+	if _, err := store.QuerySegments(Query{Speakers: []string{"Speaker 1"}}); err == nil {
+		t.Error("expected an error filtering on speakers without the column")
+	}
+	rawDB.Exec(`ALTER TABLE segments ADD COLUMN speaker TEXT`)
+	rawDB.Exec(`UPDATE segments SET speaker = 'Speaker 2' WHERE id IN ('seg-1-3', 'seg-2-2')`)
+	if n, err := store.CountSegments(Query{Speakers: []string{"Speaker 2"}}); err != nil || n != 2 {
+		t.Errorf("speaker count = %d, %v, want 2", n, err)
+	}
+}
+
+func TestSegmentPages(t *testing.T) {
+	rawDB := createTestDB(t)
+	defer rawDB.Close()
+	rawDB.Exec(`CREATE INDEX idx_segments_dedup ON segments(sessionId, sequenceNumber) WHERE duplicate_of IS NULL`)
+	rawDB.Exec(`INSERT INTO sessions (id, locale, startedAt, status, createdAt) VALUES ('sess-1', 'en_US', 1710000000, 'completed', 1710000000)`)
+	total := 2*SegmentPageSize + 5
+	tx, _ := rawDB.Begin()
+	for i := 1; i <= total; i++ {
+		tx.Exec(`INSERT INTO segments (id, sessionId, text, startedAt, endedAt, sequenceNumber, createdAt) VALUES (?, 'sess-1', 'x', ?, ?, ?, ?)`,
+			fmt.Sprintf("seg-%d", i), 1710000000+i, 1710000000+i, i, 1710000000+i)
+	}
+	tx.Exec(`UPDATE segments SET duplicate_of = 'seg-1' WHERE id = 'seg-2'`)
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	store := &Store{db: rawDB}
+
+	var pages, n, last int
+	err := store.SegmentPages("sess-1", func(page []Segment) error {
+		pages++
+		for _, s := range page {
+			if s.SequenceNumber <= last {
+				t.Fatalf("segment %d after %d", s.SequenceNumber, last)
+			}
+			last = s.SequenceNumber
+			n++
+		}
+		return nil
+	})
+	if err != nil || pages != 3 || n != total-1 || last != total {
+		t.Errorf("walked %d pages, %d segments to %d (%v); want 3 pages, %d segments to %d", pages, n, last, err, total-1, total)
+	}
+
+	stop := errors.New("stop")
+	pages = 0
+	if err := store.SegmentPages("sess-1", func([]Segment) error { pages++; return stop }); err != stop || pages != 1 {
+		t.Errorf("SegmentPages = %v after %d pages, want fn's error after 1", err, pages)
+	}
+
+	page, err := store.SegmentsAfter("sess-1", total-2, 10)
+	if err != nil || len(page) != 2 || page[0].SequenceNumber != total-1 {
+		t.Errorf("last page = %d segments (%v)", len(page), err)
+	}
+
+	// A page is an index seek, already in order: no scan, no sort.
+	rows, err := rawDB.Query(`EXPLAIN QUERY PLAN SELECT id FROM segments WHERE duplicate_of IS NULL AND sessionId IN (?) AND sequenceNumber > ? ORDER BY sequenceNumber ASC LIMIT ?`, "sess-1", 500, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	var plan []string
+	for rows.Next() {
+		var id, parent, notused int
+		var detail string
+		rows.Scan(&id, &parent, &notused, &detail)
+		plan = append(plan, detail)
+	}
+	joined := strings.Join(plan, "; ")
+	if !strings.Contains(joined, "USING INDEX idx_segments_dedup (sessionId=? AND sequenceNumber>?)") || strings.Contains(joined, "TEMP B-TREE") {
+		t.Errorf("plan = %s", joined)
+	}
+}
@@ -0,0 +1,126 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// StrandedSession is a session still marked 'active' that the daemon
+// isn't recording: a crash or force quit ended it before the daemon
+// could close it. The daemon closes these as 'interrupted' when it next
+// starts; until then they claim to be in progress.
+type StrandedSession struct {
+	Session  Session
+	Segments int
+	// LastSegmentAt is when the last segment captured ended; nil when
+	// nothing was.
+	LastSegmentAt *time.Time
+	// Tail is the last segments captured, oldest first.
+	Tail []Segment
+}
+
+// StrandedSessions returns the active sessions other than recording (the
+// session the daemon is recording or paused in, or "" when it isn't
+// running), newest first, each with up to tail of its last segments.
+func (s *Store) StrandedSessions(recording string, tail int) ([]StrandedSession, error) {
+	active, err := s.FilterSessions(SessionFilter{Status: "active"})
+	if err != nil {
+		return nil, err
+	}
+	var out []StrandedSession
+	for _, a := range active {
+		if a.Session.ID == recording {
+			continue
+		}
+		st := StrandedSession{Session: a.Session, Segments: a.Counts.Segments}
+		var last sql.NullFloat64
+		if err := s.db.QueryRow(`SELECT MAX(endedAt) FROM segments WHERE sessionId = ?`, a.Session.ID).Scan(&last); err != nil {
+			return nil, fmt.Errorf("last segment: %w", err)
+		}
+		if last.Valid {
+			t := timeFromUnix(last.Float64)
+			st.LastSegmentAt = &t
+		}
+		if tail > 0 && st.Segments > 0 {
+			if st.Tail, err = s.SegmentsForSession(a.Session.ID, tail, max(0, st.Segments-tail)); err != nil {
+				return nil, err
+			}
+		}
+		out = append(out, st)
+	}
+	return out, nil
+}
+
+// FinalizeSession closes a stranded session the way the daemon's startup
+// sweep does: 'interrupted', ending when its last segment did (or when
+// it started, if nothing was captured). Requires a Store opened with
+// OpenMaintenance; callers must not pass the session the daemon is
+// recording.
+func (s *Store) FinalizeSession(sessionID string) error {
+	res, err := s.db.Exec(`
+		UPDATE sessions
+		SET status = 'interrupted',
+			endedAt = COALESCE((SELECT MAX(endedAt) FROM segments WHERE sessionId = sessions.id), startedAt)
+		WHERE id = ? AND status = 'active'
+	`, sessionID)
+	if err != nil {
+		return fmt.Errorf("finalize session: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("session %s is not active", sessionID)
+	}
+	return nil
+}
+
+// DiscardSession deletes a stranded session and everything recorded for
+// it, client-owned rows included. Only active sessions are discarded, so
+// a finished recording can't be lost by mistake. Requires a Store opened
+// with OpenMaintenance; callers must not pass the session the daemon is
+// recording.
+func (s *Store) DiscardSession(sessionID string) error {
+	sess, err := s.GetSession(sessionID)
+	if err != nil {
+		return err
+	}
+	if sess == nil || sess.Status != "active" {
+		return fmt.Errorf("session %s is not active", sessionID)
+	}
+
+	// Foreign keys aren't enforced on these connections, so the cascades
+	// in the schema don't fire; delete children explicitly.
+	var stmts []string
+	if ok, err := s.hasTable("topic_edits"); err != nil {
+		return err
+	} else if ok {
+		stmts = append(stmts, `DELETE FROM topic_edits WHERE topic_id IN (SELECT id FROM topics WHERE sessionId = ?)`)
+	}
+	for _, table := range []string{"session_metadata", "annotations", "segment_edits", "decisions", "speaker_names", "session_quality", "session_notes", "segment_cleanups", "session_view_prefs", "speaker_relabels"} {
+		if ok, err := s.hasTable(table); err != nil {
+			return err
+		} else if ok {
+			stmts = append(stmts, `DELETE FROM `+table+` WHERE session_id = ?`)
+		}
+	}
+	stmts = append(stmts,
+		`DELETE FROM segments WHERE sessionId = ?`,
+		`DELETE FROM topics WHERE sessionId = ?`,
+		`DELETE FROM summaries WHERE sessionId = ?`,
+		`DELETE FROM sessions WHERE id = ? AND status = 'active'`,
+	)
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin discard: %w", err)
+	}
+	defer tx.Rollback()
+	for _, stmt := range stmts {
+		if _, err := tx.Exec(stmt, sessionID); err != nil {
+			return fmt.Errorf("discard session: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit discard: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package db
+
+import "testing"
+
+func TestStrandedSessions(t *testing.T) {
+	rawDB := createTestDB(t)
+	defer rawDB.Close()
+	seedTestData(t, rawDB)
+	store := &Store{db: rawDB}
+
+	stranded, err := store.StrandedSessions("", 2)
+	if err != nil {
+		t.Fatalf("StrandedSessions: %v", err)
+	}
+	if len(stranded) != 1 || stranded[0].Session.ID != "sess-2" {
+		t.Fatalf("stranded = %+v, want sess-2 only", stranded)
+	}
+	st := stranded[0]
+	if st.Segments != 3 || len(st.Tail) != 2 || st.Tail[0].SequenceNumber != 2 || st.Tail[1].SequenceNumber != 3 {
+		t.Errorf("segments = %d, tail = %+v; want 3 with the last two", st.Segments, st.Tail)
+	}
+	if st.LastSegmentAt == nil || st.LastSegmentAt.Unix() != 1710007239 {
+		t.Errorf("LastSegmentAt = %v, want the third segment's end", st.LastSegmentAt)
+	}
+
+	// The daemon's own session isn't stranded.
+	if stranded, err := store.StrandedSessions("sess-2", 2); err != nil || len(stranded) != 0 {
+		t.Errorf("StrandedSessions(recording sess-2) = %+v, %v; want none", stranded, err)
+	}
+}
+
+func TestFinalizeSession(t *testing.T) {
+	rawDB := createTestDB(t)
+	defer rawDB.Close()
+	seedTestData(t, rawDB)
+	store := &Store{db: rawDB}
+
+	if err := store.FinalizeSession("sess-2"); err != nil {
+		t.Fatalf("FinalizeSession: %v", err)
+	}
+	sess, _ := store.GetSession("sess-2")
+	if sess.Status != "interrupted" || sess.EndedAt == nil || sess.EndedAt.Unix() != 1710007239 {
+		t.Errorf("session = %+v, want interrupted at its last segment's end", sess)
+	}
+	if err := store.FinalizeSession("sess-1"); err == nil {
+		t.Error("finalizing a completed session should fail")
+	}
+}
+
+func TestDiscardSession(t *testing.T) {
+	rawDB := createTestDB(t)
+	defer rawDB.Close()
+	seedTestData(t, rawDB)
+	store := &Store{db: rawDB}
+	if err := store.EnsureClientSchema(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.AddAnnotation("sess-2", 1, AnnotationBookmark, ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.EditTopic("top-3", "Renamed", "Still about MCP"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SetSessionNotes("sess-2", "Half-finished thought"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.DiscardSession("sess-1"); err == nil {
+		t.Error("discarding a completed session should fail")
+	}
+	if err := store.DiscardSession("sess-2"); err != nil {
+		t.Fatalf("DiscardSession: %v", err)
+	}
+	if sess, _ := store.GetSession("sess-2"); sess != nil {
+		t.Error("session should be deleted")
+	}
+	for table, col := range map[string]string{"segments": "sessionId", "topics": "sessionId", "annotations": "session_id", "session_notes": "session_id"} {
+		var n int
+		rawDB.QueryRow(`SELECT COUNT(*) FROM ` + table + ` WHERE ` + col + ` = 'sess-2'`).Scan(&n)
+		if n != 0 {
+			t.Errorf("%s has %d rows left for sess-2", table, n)
+		}
+	}
+	var edits int
+	rawDB.QueryRow(`SELECT COUNT(*) FROM topic_edits`).Scan(&edits)
+	if edits != 0 {
+		t.Errorf("topic_edits has %d rows left", edits)
+	}
+	if sess, _ := store.GetSession("sess-1"); sess == nil {
+		t.Error("other sessions should be untouched")
+	}
+}
@@ -0,0 +1,161 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// RelabelPlan is what reassigning a session's segments from one speaker
+// label to another does: Moved segments go from From to To, which
+// already has Joined of them.
+type RelabelPlan struct {
+	SessionID string
+	From      string
+	To        string
+	Moved     int
+	Joined    int
+}
+
+// Relabel is a relabeling as recorded for undo.
+type Relabel struct {
+	ID          int64
+	SessionID   string
+	From        string
+	To          string
+	SegmentIDs  []string
+	RelabeledAt time.Time
+}
+
+// PlanRelabel counts, without writing, the segments of sessionID that
+// relabeling from as to would reassign: the fix when diarization split
+// one voice in two, or took two similar voices for one.
+func (s *Store) PlanRelabel(sessionID, from, to string) (*RelabelPlan, error) {
+	from, to = strings.TrimSpace(from), strings.TrimSpace(to)
+	if from == "" || to == "" {
+		return nil, fmt.Errorf("relabel speaker: empty label")
+	}
+	if from == to {
+		return nil, fmt.Errorf("relabel speaker: %s is already %s", from, to)
+	}
+	sess, err := s.GetSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if sess == nil {
+		return nil, fmt.Errorf("session not found: %s", sessionID)
+	}
+	if sess.Status == "active" {
+		return nil, fmt.Errorf("session %s is active; the daemon may still be writing to it", sessionID)
+	}
+	if ok, err := hasColumn(s.db, "segments", "speaker"); err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, fmt.Errorf("relabel speaker: this database doesn't record speakers per segment")
+	}
+
+	plan := &RelabelPlan{SessionID: sessionID, From: from, To: to}
+	err = s.db.QueryRow(`SELECT COUNT(*) FILTER (WHERE speaker = ?), COUNT(*) FILTER (WHERE speaker = ?)
+		FROM segments WHERE sessionId = ?`, from, to, sessionID).Scan(&plan.Moved, &plan.Joined)
+	if err != nil {
+		return nil, fmt.Errorf("count speaker segments: %w", err)
+	}
+	if plan.Moved == 0 {
+		return nil, fmt.Errorf("session %s has no segments from %s", sessionID, from)
+	}
+	return plan, nil
+}
+
+// RelabelSpeaker reassigns every segment of sessionID from from to to,
+// recording which ones so UndoRelabel can put them back. Speaker names
+// are left as they are. Requires a Store opened with OpenMaintenance.
+func (s *Store) RelabelSpeaker(sessionID, from, to string) (*RelabelPlan, error) {
+	plan, err := s.PlanRelabel(sessionID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.EnsureClientSchema(); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("begin relabel: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`SELECT id FROM segments WHERE sessionId = ? AND speaker = ? ORDER BY sequenceNumber`, sessionID, plan.From)
+	if err != nil {
+		return nil, fmt.Errorf("query speaker segments: %w", err)
+	}
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan segment: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.Exec(`UPDATE segments SET speaker = ? WHERE sessionId = ? AND speaker = ?`, plan.To, sessionID, plan.From); err != nil {
+		return nil, fmt.Errorf("relabel segments: %w", err)
+	}
+	if _, err := tx.Exec(`INSERT INTO speaker_relabels (session_id, from_label, to_label, segment_ids, relabeled_at)
+		VALUES (?, ?, ?, ?, ?)`, sessionID, plan.From, plan.To, strings.Join(ids, "\n"), unixFromTime(time.Now())); err != nil {
+		return nil, fmt.Errorf("record relabel: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit relabel: %w", err)
+	}
+	plan.Moved = len(ids)
+	return plan, nil
+}
+
+// UndoRelabel puts back the segments of sessionID's latest relabeling
+// and forgets it; undoing again undoes the one before. Nil when there is
+// none left. A segment whose label changed since is left alone. Requires
+// a Store opened with OpenMaintenance.
+func (s *Store) UndoRelabel(sessionID string) (*Relabel, error) {
+	ok, err := s.hasTable("speaker_relabels")
+	if err != nil || !ok {
+		return nil, err
+	}
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("begin undo relabel: %w", err)
+	}
+	defer tx.Rollback()
+
+	r := &Relabel{SessionID: sessionID}
+	var ids string
+	var relabeledAt float64
+	err = tx.QueryRow(`SELECT id, from_label, to_label, segment_ids, relabeled_at FROM speaker_relabels
+		WHERE session_id = ? ORDER BY id DESC LIMIT 1`, sessionID).Scan(&r.ID, &r.From, &r.To, &ids, &relabeledAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("latest relabel: %w", err)
+	}
+	r.SegmentIDs = splitList(ids)
+	r.RelabeledAt = timeFromUnix(relabeledAt)
+
+	for _, id := range r.SegmentIDs {
+		if _, err := tx.Exec(`UPDATE segments SET speaker = ? WHERE id = ? AND speaker = ?`, r.From, id, r.To); err != nil {
+			return nil, fmt.Errorf("restore segment %s: %w", id, err)
+		}
+	}
+	if _, err := tx.Exec(`DELETE FROM speaker_relabels WHERE id = ?`, r.ID); err != nil {
+		return nil, fmt.Errorf("forget relabel: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit undo relabel: %w", err)
+	}
+	return r, nil
+}
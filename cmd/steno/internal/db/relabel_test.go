@@ -0,0 +1,95 @@
+package db
+
+import (
+	"slices"
+	"testing"
+)
+
+// speakersOf returns sess-1's speaker per sequence number.
+func speakersOf(t *testing.T, store *Store) []string {
+	t.Helper()
+	segs, err := store.SegmentsForSession("sess-1", 0, 0)
+	if err != nil {
+		t.Fatalf("SegmentsForSession: %v", err)
+	}
+	var out []string
+	for _, seg := range segs {
+		out = append(out, seg.Speaker)
+	}
+	return out
+}
+
+func TestRelabelSpeakerAndUndo(t *testing.T) {
+	rawDB := createTestDB(t)
+	defer rawDB.Close()
+	seedTestData(t, rawDB)
+	rawDB.Exec(`ALTER TABLE segments ADD COLUMN speaker TEXT`)
+	rawDB.Exec(`UPDATE segments SET speaker = 'Speaker 1' WHERE sessionId = 'sess-1' AND sequenceNumber <= 6`)
+	rawDB.Exec(`UPDATE segments SET speaker = 'Speaker 2' WHERE sessionId = 'sess-1' AND sequenceNumber IN (2, 4, 7)`)
+	rawDB.Exec(`UPDATE segments SET speaker = 'Speaker 3' WHERE sessionId = 'sess-1' AND sequenceNumber = 8`)
+	store := &Store{db: rawDB}
+	before := speakersOf(t, store)
+
+	plan, err := store.PlanRelabel("sess-1", "Speaker 2", "Speaker 1")
+	if err != nil {
+		t.Fatalf("PlanRelabel: %v", err)
+	}
+	if plan.Moved != 3 || plan.Joined != 4 {
+		t.Errorf("plan = %+v, want 3 moved joining 4", plan)
+	}
+	if got := speakersOf(t, store); got[1] != "Speaker 2" {
+		t.Fatalf("planning wrote: %v", got)
+	}
+
+	if _, err := store.RelabelSpeaker("sess-1", "Speaker 2", "Speaker 1"); err != nil {
+		t.Fatalf("RelabelSpeaker: %v", err)
+	}
+	if _, err := store.RelabelSpeaker("sess-1", "Speaker 3", "Speaker 1"); err != nil {
+		t.Fatalf("RelabelSpeaker: %v", err)
+	}
+	got := speakersOf(t, store)
+	for i := range 8 {
+		if got[i] != "Speaker 1" {
+			t.Fatalf("after relabels: %v, want #1-8 all Speaker 1", got)
+		}
+	}
+
+	// Undo goes back one relabeling at a time.
+	r, err := store.UndoRelabel("sess-1")
+	if err != nil || r == nil || r.From != "Speaker 3" || len(r.SegmentIDs) != 1 {
+		t.Fatalf("UndoRelabel = %+v, %v, want the Speaker 3 one", r, err)
+	}
+	if r, err = store.UndoRelabel("sess-1"); err != nil || r == nil || r.From != "Speaker 2" {
+		t.Fatalf("UndoRelabel = %+v, %v, want the Speaker 2 one", r, err)
+	}
+	if got := speakersOf(t, store); !slices.Equal(got, before) {
+		t.Errorf("after undo: %v, want %v", got, before)
+	}
+	if r, err = store.UndoRelabel("sess-1"); err != nil || r != nil {
+		t.Errorf("UndoRelabel = %+v, %v, want nothing left", r, err)
+	}
+}
+
+func TestPlanRelabelRefuses(t *testing.T) {
+	rawDB := createTestDB(t)
+	defer rawDB.Close()
+	seedTestData(t, rawDB)
+	store := &Store{db: rawDB}
+
+	if _, err := store.PlanRelabel("sess-1", "Speaker 1", "Speaker 2"); err == nil {
+		t.Error("expected an error without per-segment speakers")
+	}
+	rawDB.Exec(`ALTER TABLE segments ADD COLUMN speaker TEXT`)
+	rawDB.Exec(`UPDATE segments SET speaker = 'Speaker 1'`)
+	for _, c := range []struct{ session, from, to string }{
+		{"sess-2", "Speaker 1", "Speaker 2"}, // active
+		{"sess-1", "Speaker 1", "Speaker 1"},
+		{"sess-1", "Speaker 9", "Speaker 1"},
+		{"sess-1", "Speaker 1", " "},
+		{"missing", "Speaker 1", "Speaker 2"},
+	} {
+		if _, err := store.PlanRelabel(c.session, c.from, c.to); err == nil {
+			t.Errorf("PlanRelabel(%q, %q, %q): expected an error", c.session, c.from, c.to)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package db
+
+import "fmt"
+
+// SchemaInfo describes which migrations have been applied to a database,
+// for bug reports.
+type SchemaInfo struct {
+	// Migrations are the GRDB migration identifiers in the order they
+	// were applied; the last one is the effective schema version.
+	Migrations []string
+	// ClientTables lists the client-owned tables that exist.
+	ClientTables []string
+}
+
+// Latest returns the most recent migration identifier, or "" for a
+// database the daemon has never migrated.
+func (i SchemaInfo) Latest() string {
+	if len(i.Migrations) == 0 {
+		return ""
+	}
+	return i.Migrations[len(i.Migrations)-1]
+}
+
+// SchemaInfo reads the daemon's grdb_migrations table and probes for the
+// client-owned tables.
+func (s *Store) SchemaInfo() (SchemaInfo, error) {
+	var info SchemaInfo
+	ok, err := s.hasTable("grdb_migrations")
+	if err != nil {
+		return info, err
+	}
+	if ok {
+		rows, err := s.db.Query(`SELECT identifier FROM grdb_migrations ORDER BY rowid`)
+		if err != nil {
+			return info, fmt.Errorf("query migrations: %w", err)
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var id string
+			if err := rows.Scan(&id); err != nil {
+				return info, fmt.Errorf("scan migration: %w", err)
+			}
+			info.Migrations = append(info.Migrations, id)
+		}
+		if err := rows.Err(); err != nil {
+			return info, err
+		}
+	}
+	for _, name := range []string{"session_metadata", "annotations"} {
+		ok, err := s.hasTable(name)
+		if err != nil {
+			return info, err
+		}
+		if ok {
+			info.ClientTables = append(info.ClientTables, name)
+		}
+	}
+	return info, nil
+}
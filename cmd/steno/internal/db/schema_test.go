@@ -0,0 +1,33 @@
+package db
+
+import "testing"
+
+func TestSchemaInfo(t *testing.T) {
+	rawDB := createTestDB(t)
+	defer rawDB.Close()
+	store := &Store{db: rawDB}
+
+	info, err := store.SchemaInfo()
+	if err != nil {
+		t.Fatalf("SchemaInfo: %v", err)
+	}
+	if info.Latest() != "" || len(info.ClientTables) != 0 {
+		t.Errorf("fresh db info = %+v", info)
+	}
+
+	rawDB.Exec(`CREATE TABLE grdb_migrations (identifier TEXT NOT NULL PRIMARY KEY)`)
+	rawDB.Exec(`INSERT INTO grdb_migrations VALUES ('20260207_001_add_segment_source'), ('20260425_001_dedup_and_heal')`)
+	if err := store.EnsureClientSchema(); err != nil {
+		t.Fatal(err)
+	}
+	info, err = store.SchemaInfo()
+	if err != nil {
+		t.Fatalf("SchemaInfo: %v", err)
+	}
+	if info.Latest() != "20260425_001_dedup_and_heal" || len(info.Migrations) != 2 {
+		t.Errorf("migrations = %v", info.Migrations)
+	}
+	if len(info.ClientTables) != 2 {
+		t.Errorf("client tables = %v", info.ClientTables)
+	}
+}
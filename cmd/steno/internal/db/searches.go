@@ -0,0 +1,200 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Saved-search scopes. A scope may also be a Go duration ("168h"): the
+// segments spoken within that long before the run.
+const (
+	// ScopeAll searches every session.
+	ScopeAll = "all"
+	// ScopeSession searches the session the search is run against: the
+	// TUI's current session, or the latest for `steno searches --run`.
+	ScopeSession = "session"
+)
+
+// SavedSearch is a named query kept in the client-owned saved_searches
+// table. Alert searches are also run against each new segment the TUI
+// receives, as keyword alerts.
+type SavedSearch struct {
+	Name string
+	// Text is a case-insensitive substring of the segment text.
+	Text string
+	// Sources and Speakers filter as Query's do; empty matches all.
+	Sources       []string
+	Speakers      []string
+	MinConfidence float64
+	// Scope is ScopeAll, ScopeSession or a duration; empty means
+	// ScopeAll.
+	Scope     string
+	Alert     bool
+	CreatedAt time.Time
+}
+
+// ValidateSearchScope reports whether scope is a saved-search scope.
+func ValidateSearchScope(scope string) error {
+	switch scope {
+	case "", ScopeAll, ScopeSession:
+		return nil
+	}
+	if d, err := time.ParseDuration(scope); err != nil || d <= 0 {
+		return fmt.Errorf("scope %q: want %s, %s or a positive duration such as \"168h\"", scope, ScopeAll, ScopeSession)
+	}
+	return nil
+}
+
+// Query builds the segment query for ss, run at now against sessionID
+// (only read for ScopeSession). Results come newest first.
+func (ss SavedSearch) Query(sessionID string, now time.Time) (Query, error) {
+	if err := ValidateSearchScope(ss.Scope); err != nil {
+		return Query{}, err
+	}
+	q := Query{
+		TextMatch:     ss.Text,
+		Sources:       ss.Sources,
+		Speakers:      ss.Speakers,
+		MinConfidence: ss.MinConfidence,
+		Newest:        true,
+	}
+	switch ss.Scope {
+	case "", ScopeAll:
+	case ScopeSession:
+		if sessionID == "" {
+			return Query{}, fmt.Errorf("saved search %q: no session to search", ss.Name)
+		}
+		q.Sessions = []string{sessionID}
+	default:
+		d, _ := time.ParseDuration(ss.Scope)
+		after := now.Add(-d)
+		q.TimeRange.After = &after
+	}
+	return q, nil
+}
+
+// Matches reports whether a segment with this text, source and speaker
+// (empty when undiarized) passes ss's text and filters, for checking a
+// live segment before it is in the database. MinConfidence and Scope
+// aren't checked: live segments carry no confidence, and the live
+// session is in every scope.
+func (ss SavedSearch) Matches(text, source, speaker string) bool {
+	if len(ss.Sources) > 0 && !slices.Contains(ss.Sources, source) {
+		return false
+	}
+	if len(ss.Speakers) > 0 && !slices.Contains(ss.Speakers, speaker) {
+		return false
+	}
+	return strings.Contains(strings.ToLower(text), strings.ToLower(ss.Text))
+}
+
+// SaveSearch creates or replaces the saved search named ss.Name,
+// keeping its original creation time. Requires a Store opened with
+// OpenClient.
+func (s *Store) SaveSearch(ss SavedSearch) error {
+	ss.Name, ss.Text = strings.TrimSpace(ss.Name), strings.TrimSpace(ss.Text)
+	if ss.Name == "" {
+		return fmt.Errorf("save search: empty name")
+	}
+	if ss.Text == "" {
+		return fmt.Errorf("save search %q: empty query", ss.Name)
+	}
+	if err := ValidateSearchScope(ss.Scope); err != nil {
+		return fmt.Errorf("save search %q: %w", ss.Name, err)
+	}
+	if ss.Scope == "" {
+		ss.Scope = ScopeAll
+	}
+	if ss.CreatedAt.IsZero() {
+		ss.CreatedAt = time.Now()
+	}
+	var minConfidence sql.NullFloat64
+	if ss.MinConfidence > 0 {
+		minConfidence = sql.NullFloat64{Float64: ss.MinConfidence, Valid: true}
+	}
+	_, err := s.db.Exec(`
+		INSERT INTO saved_searches (name, text, sources, speakers, min_confidence, scope, alert, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(name) DO UPDATE SET
+			text = excluded.text,
+			sources = excluded.sources,
+			speakers = excluded.speakers,
+			min_confidence = excluded.min_confidence,
+			scope = excluded.scope,
+			alert = excluded.alert
+	`, ss.Name, ss.Text, nullString(strings.Join(ss.Sources, "\n")), nullString(strings.Join(ss.Speakers, "\n")),
+		minConfidence, ss.Scope, boolToInt(ss.Alert), unixFromTime(ss.CreatedAt))
+	if err != nil {
+		return fmt.Errorf("save search %q: %w", ss.Name, err)
+	}
+	return nil
+}
+
+// SavedSearches returns every saved search by name. Empty when the table
+// does not exist yet.
+func (s *Store) SavedSearches() ([]SavedSearch, error) {
+	return s.querySavedSearches(``)
+}
+
+// SavedSearch returns the saved search called name, or nil.
+func (s *Store) SavedSearch(name string) (*SavedSearch, error) {
+	found, err := s.querySavedSearches(`WHERE name = ?`, name)
+	if err != nil || len(found) == 0 {
+		return nil, err
+	}
+	return &found[0], nil
+}
+
+// DeleteSavedSearch removes the saved search called name, reporting
+// whether it existed.
+func (s *Store) DeleteSavedSearch(name string) (bool, error) {
+	res, err := s.db.Exec(`DELETE FROM saved_searches WHERE name = ?`, name)
+	if err != nil {
+		return false, fmt.Errorf("delete saved search: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	return n > 0, nil
+}
+
+func (s *Store) querySavedSearches(where string, args ...any) ([]SavedSearch, error) {
+	ok, err := s.hasTable("saved_searches")
+	if err != nil || !ok {
+		return nil, err
+	}
+	rows, err := s.db.Query(`SELECT name, text, sources, speakers, min_confidence, scope, alert, created_at
+		FROM saved_searches `+where+` ORDER BY name`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query saved searches: %w", err)
+	}
+	defer rows.Close()
+
+	var out []SavedSearch
+	for rows.Next() {
+		var ss SavedSearch
+		var sources, speakers sql.NullString
+		var minConfidence sql.NullFloat64
+		var alert int64
+		var createdAt float64
+		if err := rows.Scan(&ss.Name, &ss.Text, &sources, &speakers, &minConfidence, &ss.Scope, &alert, &createdAt); err != nil {
+			return nil, fmt.Errorf("scan saved search: %w", err)
+		}
+		ss.Sources = splitList(sources.String)
+		ss.Speakers = splitList(speakers.String)
+		ss.MinConfidence = minConfidence.Float64
+		ss.Alert = alert != 0
+		ss.CreatedAt = timeFromUnix(createdAt)
+		out = append(out, ss)
+	}
+	return out, rows.Err()
+}
+
+// splitList reads a newline-separated list column; "" is no values.
+func splitList(v string) []string {
+	if v == "" {
+		return nil
+	}
+	return strings.Split(v, "\n")
+}
@@ -0,0 +1,117 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSavedSearchesRoundTrip(t *testing.T) {
+	rawDB := createTestDB(t)
+	defer rawDB.Close()
+	seedTestData(t, rawDB)
+	store := &Store{db: rawDB}
+
+	// Readers tolerate the table not existing yet.
+	if found, err := store.SavedSearches(); err != nil || found != nil {
+		t.Fatalf("before schema: %v, %v", found, err)
+	}
+	if err := store.EnsureClientSchema(); err != nil {
+		t.Fatal(err)
+	}
+
+	created := time.Unix(1710000000, 0)
+	if err := store.SaveSearch(SavedSearch{Name: "launch", Text: "launch date", Sources: []string{"microphone", "systemAudio"},
+		MinConfidence: 0.5, Scope: "168h", Alert: true, CreatedAt: created}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SaveSearch(SavedSearch{Name: "budget", Text: "budget"}); err != nil {
+		t.Fatal(err)
+	}
+	// Saving again replaces the search but keeps when it was created.
+	if err := store.SaveSearch(SavedSearch{Name: "launch", Text: "ship date", Scope: ScopeSession}); err != nil {
+		t.Fatal(err)
+	}
+
+	found, err := store.SavedSearches()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 2 || found[0].Name != "budget" || found[1].Name != "launch" {
+		t.Fatalf("saved searches = %+v, want budget then launch", found)
+	}
+	if b := found[0]; b.Scope != ScopeAll || b.Alert || b.Sources != nil {
+		t.Errorf("budget = %+v, want the defaults", b)
+	}
+	if l := found[1]; l.Text != "ship date" || l.Scope != ScopeSession || l.Alert || l.Sources != nil || !l.CreatedAt.Equal(created) {
+		t.Errorf("launch = %+v", l)
+	}
+
+	if ok, err := store.DeleteSavedSearch("budget"); err != nil || !ok {
+		t.Errorf("delete = %v, %v", ok, err)
+	}
+	if ok, _ := store.DeleteSavedSearch("budget"); ok {
+		t.Error("deleted twice")
+	}
+	if ss, err := store.SavedSearch("budget"); err != nil || ss != nil {
+		t.Errorf("deleted search = %+v, %v", ss, err)
+	}
+
+	for _, bad := range []SavedSearch{
+		{Name: " ", Text: "x"},
+		{Name: "x", Text: " "},
+		{Name: "x", Text: "x", Scope: "last week"},
+	} {
+		if err := store.SaveSearch(bad); err == nil {
+			t.Errorf("SaveSearch(%+v): expected error", bad)
+		}
+	}
+}
+
+func TestSavedSearchQuery(t *testing.T) {
+	rawDB := createTestDB(t)
+	defer rawDB.Close()
+	seedTestData(t, rawDB)
+	store := &Store{db: rawDB}
+	now := time.Unix(1710007200+60, 0) // a minute into sess-2
+
+	run := func(ss SavedSearch, sessionID string) int {
+		t.Helper()
+		q, err := ss.Query(sessionID, now)
+		if err != nil {
+			t.Fatalf("Query(%+v): %v", ss, err)
+		}
+		segs, err := store.QuerySegments(q)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return len(segs)
+	}
+	if n := run(SavedSearch{Text: "SEGMENT"}, ""); n != 13 {
+		t.Errorf("all = %d, want 13", n)
+	}
+	if n := run(SavedSearch{Text: "segment", Scope: ScopeSession}, "sess-1"); n != 10 {
+		t.Errorf("session = %d, want 10", n)
+	}
+	if n := run(SavedSearch{Text: "segment", Scope: "1h"}, ""); n != 3 {
+		t.Errorf("last hour = %d, want sess-2's 3", n)
+	}
+	if n := run(SavedSearch{Text: "segment", Sources: []string{"systemAudio"}}, ""); n != 3 {
+		t.Errorf("system audio = %d, want 3", n)
+	}
+	if _, err := (SavedSearch{Text: "x", Scope: ScopeSession}).Query("", now); err == nil {
+		t.Error("session scope without a session: expected error")
+	}
+}
+
+func TestSavedSearchMatches(t *testing.T) {
+	ss := SavedSearch{Text: "Launch", Sources: []string{"systemAudio"}}
+	if !ss.Matches("the launch slipped", "systemAudio", "") {
+		t.Error("case-insensitive substring should match")
+	}
+	if ss.Matches("the launch slipped", "microphone", "") {
+		t.Error("source filter ignored")
+	}
+	if (SavedSearch{Text: "launch", Speakers: []string{"Speaker 1"}}).Matches("launch", "microphone", "Speaker 2") {
+		t.Error("speaker filter ignored")
+	}
+}
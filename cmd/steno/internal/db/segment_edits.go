@@ -0,0 +1,97 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// CalibrationSample is one segment of a session the user has corrected:
+// what the ASR heard, with the confidence it reported, against what was
+// actually said. Unedited segments of such a session count as heard
+// correctly.
+type CalibrationSample struct {
+	SessionID  string
+	SegmentSeq int
+	Text       string
+	// Corrected is the user's correction, or Text when unedited.
+	Corrected  string
+	Edited     bool
+	Confidence *float64
+	// Device is the recording device from session_metadata; empty when
+	// unknown.
+	Device string
+	Locale string
+}
+
+// EditSegment records the user's correction of what the ASR heard in the
+// segment with sequence number seq, replacing any earlier correction.
+// The daemon's row is left untouched. Requires a Store opened with
+// OpenClient.
+func (s *Store) EditSegment(sessionID string, seq int, text string) error {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return errors.New("segment correction cannot be empty")
+	}
+	var segmentID string
+	err := s.db.QueryRow(`SELECT id FROM segments WHERE sessionId = ? AND sequenceNumber = ?`, sessionID, seq).Scan(&segmentID)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("session %s has no segment #%d", sessionID, seq)
+	}
+	if err != nil {
+		return fmt.Errorf("look up segment: %w", err)
+	}
+	_, err = s.db.Exec(`
+		INSERT INTO segment_edits (segment_id, session_id, text, edited_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(segment_id) DO UPDATE SET
+			text = excluded.text,
+			edited_at = excluded.edited_at
+	`, segmentID, sessionID, text, unixFromTime(time.Now()))
+	if err != nil {
+		return fmt.Errorf("save segment edit: %w", err)
+	}
+	return nil
+}
+
+// CalibrationSamples returns every segment of every session with at
+// least one correction, in session and sequence order. Duplicates are
+// excluded, as everywhere. Empty when the table does not exist yet.
+func (s *Store) CalibrationSamples() ([]CalibrationSample, error) {
+	ok, err := s.hasTable("segment_edits")
+	if err != nil || !ok {
+		return nil, err
+	}
+	rows, err := s.db.Query(`
+		SELECT g.sessionId, g.sequenceNumber, g.text, COALESCE(e.text, g.text), e.segment_id IS NOT NULL,
+			g.confidence, COALESCE(m.device, ''), s.locale
+		FROM segments g
+		JOIN sessions s ON s.id = g.sessionId
+		LEFT JOIN segment_edits e ON e.segment_id = g.id
+		LEFT JOIN session_metadata m ON m.session_id = g.sessionId
+		WHERE g.duplicate_of IS NULL
+		  AND g.sessionId IN (SELECT session_id FROM segment_edits)
+		ORDER BY g.sessionId, g.sequenceNumber
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("query calibration samples: %w", err)
+	}
+	defer rows.Close()
+
+	var out []CalibrationSample
+	for rows.Next() {
+		var c CalibrationSample
+		var confidence sql.NullFloat64
+		if err := rows.Scan(&c.SessionID, &c.SegmentSeq, &c.Text, &c.Corrected, &c.Edited,
+			&confidence, &c.Device, &c.Locale); err != nil {
+			return nil, fmt.Errorf("scan calibration sample: %w", err)
+		}
+		if confidence.Valid {
+			c.Confidence = &confidence.Float64
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,56 @@
+package db
+
+import "testing"
+
+func TestCalibrationSamples(t *testing.T) {
+	rawDB := createTestDB(t)
+	defer rawDB.Close()
+	seedTestData(t, rawDB)
+	store := &Store{db: rawDB}
+
+	// Readers tolerate the table not existing yet.
+	if samples, err := store.CalibrationSamples(); err != nil || len(samples) != 0 {
+		t.Fatalf("before schema: %+v, %v", samples, err)
+	}
+	if err := store.EnsureClientSchema(); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.UpsertSessionMetadata(SessionMetadata{SessionID: "sess-1", Device: "MacBook Pro Microphone"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.EditSegment("sess-1", 2, "  Segment two from session one. "); err != nil {
+		t.Fatalf("EditSegment: %v", err)
+	}
+	if err := store.EditSegment("sess-1", 2, " "); err == nil {
+		t.Error("expected error for an empty correction")
+	}
+	if err := store.EditSegment("sess-1", 99, "x"); err == nil {
+		t.Error("expected error for an unknown segment")
+	}
+
+	samples, err := store.CalibrationSamples()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Only the corrected session, every segment of it.
+	if len(samples) != 10 {
+		t.Fatalf("samples = %d, want sess-1's 10", len(samples))
+	}
+	s := samples[1]
+	if !s.Edited || s.Text != "Segment 2 from session one." || s.Corrected != "Segment two from session one." ||
+		s.Confidence == nil || s.Device != "MacBook Pro Microphone" || s.Locale != "en_US" {
+		t.Errorf("edited sample = %+v", s)
+	}
+	if u := samples[0]; u.Edited || u.Corrected != u.Text {
+		t.Errorf("unedited sample = %+v", u)
+	}
+
+	// A second correction replaces the first.
+	if err := store.EditSegment("sess-1", 2, "Segment too."); err != nil {
+		t.Fatal(err)
+	}
+	if samples, _ := store.CalibrationSamples(); samples[1].Corrected != "Segment too." {
+		t.Errorf("after re-edit = %+v", samples[1])
+	}
+}
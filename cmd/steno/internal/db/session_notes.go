@@ -0,0 +1,49 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// NoteStampLayout is the time layout of the stamp the notes pane starts
+// each line with, e.g. "[14:03:07] ". Exports that strip wall-clock
+// times strip it too.
+const NoteStampLayout = "[15:04:05] "
+
+// SessionNotes returns a session's notes document, the freeform Markdown
+// written beside its transcript. Empty when the session has none or the
+// table does not exist yet.
+func (s *Store) SessionNotes(sessionID string) (string, error) {
+	ok, err := s.hasTable("session_notes")
+	if err != nil || !ok {
+		return "", err
+	}
+	var body string
+	err = s.db.QueryRow(`SELECT body FROM session_notes WHERE session_id = ?`, sessionID).Scan(&body)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("session notes: %w", err)
+	}
+	return body, nil
+}
+
+// SetSessionNotes replaces a session's notes document. Notes that are
+// only whitespace remove it. Requires a Store opened with OpenClient.
+func (s *Store) SetSessionNotes(sessionID, body string) error {
+	var err error
+	if strings.TrimSpace(body) == "" {
+		_, err = s.db.Exec(`DELETE FROM session_notes WHERE session_id = ?`, sessionID)
+	} else {
+		_, err = s.db.Exec(`INSERT INTO session_notes (session_id, body, updated_at) VALUES (?, ?, ?)
+			ON CONFLICT(session_id) DO UPDATE SET body = excluded.body, updated_at = excluded.updated_at`,
+			sessionID, body, unixFromTime(time.Now()))
+	}
+	if err != nil {
+		return fmt.Errorf("set session notes: %w", err)
+	}
+	return nil
+}
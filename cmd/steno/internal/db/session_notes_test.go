@@ -0,0 +1,44 @@
+package db
+
+import "testing"
+
+func TestSessionNotesRoundTrip(t *testing.T) {
+	rawDB := createTestDB(t)
+	defer rawDB.Close()
+	seedTestData(t, rawDB)
+	store := &Store{db: rawDB}
+
+	// Readers tolerate the table not existing yet.
+	if notes, err := store.SessionNotes("sess-1"); err != nil || notes != "" {
+		t.Fatalf("before schema: %q, %v", notes, err)
+	}
+	if err := store.EnsureClientSchema(); err != nil {
+		t.Fatal(err)
+	}
+	if notes, err := store.SessionNotes("sess-1"); err != nil || notes != "" {
+		t.Fatalf("no notes yet: %q, %v", notes, err)
+	}
+
+	if err := store.SetSessionNotes("sess-1", "[10:00:05] Ask about *budget*"); err != nil {
+		t.Fatal(err)
+	}
+	body := "[10:00:05] Ask about *budget*\n[10:04:30] Ana owns the rollout"
+	if err := store.SetSessionNotes("sess-1", body); err != nil {
+		t.Fatal(err)
+	}
+	if notes, err := store.SessionNotes("sess-1"); err != nil || notes != body {
+		t.Errorf("notes = %q, %v; want the latest save", notes, err)
+	}
+	if notes, _ := store.SessionNotes("sess-2"); notes != "" {
+		t.Errorf("sess-2 notes = %q, want none", notes)
+	}
+
+	if err := store.SetSessionNotes("sess-1", " \n "); err != nil {
+		t.Fatal(err)
+	}
+	var n int
+	rawDB.QueryRow(`SELECT COUNT(*) FROM session_notes`).Scan(&n)
+	if n != 0 {
+		t.Errorf("blank notes should remove the row, %d left", n)
+	}
+}
@@ -0,0 +1,106 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// SetSpeakerName names a diarized speaker label ("Speaker 1") within a
+// session. An empty name removes the mapping. Requires a Store opened
+// with OpenClient.
+func (s *Store) SetSpeakerName(sessionID, label, name string) error {
+	label, name = strings.TrimSpace(label), strings.TrimSpace(name)
+	if label == "" {
+		return fmt.Errorf("set speaker name: empty label")
+	}
+	var err error
+	if name == "" {
+		_, err = s.db.Exec(`DELETE FROM speaker_names WHERE session_id = ? AND label = ?`, sessionID, label)
+	} else {
+		_, err = s.db.Exec(`INSERT INTO speaker_names (session_id, label, name) VALUES (?, ?, ?)
+			ON CONFLICT(session_id, label) DO UPDATE SET name = excluded.name`, sessionID, label, name)
+	}
+	if err != nil {
+		return fmt.Errorf("set speaker name: %w", err)
+	}
+	return nil
+}
+
+// SpeakerNames returns a session's label → name mappings. Empty when the
+// table does not exist yet.
+func (s *Store) SpeakerNames(sessionID string) (map[string]string, error) {
+	ok, err := s.hasTable("speaker_names")
+	if err != nil || !ok {
+		return nil, err
+	}
+	rows, err := s.db.Query(`SELECT label, name FROM speaker_names WHERE session_id = ?`, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("speaker names: %w", err)
+	}
+	defer rows.Close()
+	names := map[string]string{}
+	for rows.Next() {
+		var label, name string
+		if err := rows.Scan(&label, &name); err != nil {
+			return nil, fmt.Errorf("scan speaker name: %w", err)
+		}
+		names[label] = name
+	}
+	return names, rows.Err()
+}
+
+// PreviousRecurringSession finds the latest earlier session of the same
+// recurring meeting that has speaker names: the same meeting link if
+// sessionID has one, otherwise the same title. steno has no calendar, so
+// the link (`steno meeting`) stands in for an event series. Nil when
+// there is none, or sessionID has neither link nor title yet.
+func (s *Store) PreviousRecurringSession(sessionID string) (*Session, error) {
+	ok, err := s.hasTable("speaker_names")
+	if err != nil || !ok {
+		return nil, err
+	}
+	sess, err := s.GetSession(sessionID)
+	if err != nil || sess == nil {
+		return nil, err
+	}
+	md, err := s.SessionMetadataFor(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	const named = `EXISTS (SELECT 1 FROM speaker_names n WHERE n.session_id = s.id)`
+	var id string
+	switch {
+	case md != nil && md.MeetingURL != "":
+		err = s.db.QueryRow(`SELECT s.id FROM sessions s JOIN session_metadata md ON md.session_id = s.id
+			WHERE md.meeting_url = ? AND s.id != ? AND s.startedAt < ? AND `+named+`
+			ORDER BY s.startedAt DESC LIMIT 1`, md.MeetingURL, sess.ID, unixFromTime(sess.StartedAt)).Scan(&id)
+	case sess.Title != "":
+		err = s.db.QueryRow(`SELECT s.id FROM sessions s
+			WHERE s.title = ? AND s.id != ? AND s.startedAt < ? AND `+named+`
+			ORDER BY s.startedAt DESC LIMIT 1`, sess.Title, sess.ID, unixFromTime(sess.StartedAt)).Scan(&id)
+	default:
+		return nil, nil
+	}
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("previous recurring session: %w", err)
+	}
+	return s.GetSession(id)
+}
+
+// CopySpeakerNames copies fromID's speaker names into toID, keeping any
+// toID already has. Returns how many were added. Requires a Store opened
+// with OpenClient.
+func (s *Store) CopySpeakerNames(fromID, toID string) (int, error) {
+	res, err := s.db.Exec(`INSERT OR IGNORE INTO speaker_names (session_id, label, name)
+		SELECT ?, label, name FROM speaker_names WHERE session_id = ?`, toID, fromID)
+	if err != nil {
+		return 0, fmt.Errorf("copy speaker names: %w", err)
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
@@ -0,0 +1,95 @@
+package db
+
+import "testing"
+
+func TestSpeakerNamesRoundTrip(t *testing.T) {
+	rawDB := createTestDB(t)
+	defer rawDB.Close()
+	seedTestData(t, rawDB)
+	store := &Store{db: rawDB}
+
+	// Readers tolerate the table not existing yet.
+	if names, err := store.SpeakerNames("sess-1"); err != nil || names != nil {
+		t.Fatalf("before schema: %v, %v", names, err)
+	}
+	if err := store.EnsureClientSchema(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.SetSpeakerName("sess-1", "Speaker 1", "Ana"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SetSpeakerName("sess-1", "Speaker 2", "Bo"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SetSpeakerName("sess-1", "Speaker 1", " Ana M. "); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SetSpeakerName("sess-1", "Speaker 2", ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SetSpeakerName("sess-1", " ", "X"); err == nil {
+		t.Error("expected error for empty label")
+	}
+
+	names, err := store.SpeakerNames("sess-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 1 || names["Speaker 1"] != "Ana M." {
+		t.Errorf("names = %v, want renamed Speaker 1 only", names)
+	}
+}
+
+func TestPreviousRecurringSession(t *testing.T) {
+	rawDB := createTestDB(t)
+	defer rawDB.Close()
+	seedTestData(t, rawDB)
+	store := &Store{db: rawDB}
+	if err := store.EnsureClientSchema(); err != nil {
+		t.Fatal(err)
+	}
+	base := 1710000000.0
+	for i, id := range []string{"standup-2", "standup-3"} {
+		start := base + float64(i+1)*86400
+		rawDB.Exec(`INSERT INTO sessions (id, locale, startedAt, endedAt, title, status, createdAt)
+			VALUES (?, 'en_US', ?, ?, 'Team Standup', 'completed', ?)`, id, start, start+900, start)
+	}
+
+	// No earlier session has names yet.
+	if prev, err := store.PreviousRecurringSession("standup-3"); err != nil || prev != nil {
+		t.Fatalf("unnamed: %v, %v", prev, err)
+	}
+
+	store.SetSpeakerName("sess-1", "Speaker 1", "Ana")
+	store.SetSpeakerName("standup-2", "Speaker 1", "Ana")
+	store.SetSpeakerName("standup-2", "Speaker 2", "Bo")
+	prev, err := store.PreviousRecurringSession("standup-3")
+	if err != nil || prev == nil || prev.ID != "standup-2" {
+		t.Fatalf("by title: %+v, %v, want standup-2", prev, err)
+	}
+	// Only earlier sessions count.
+	if prev, _ := store.PreviousRecurringSession("sess-1"); prev != nil {
+		t.Errorf("sess-1 has no earlier session, got %s", prev.ID)
+	}
+
+	// A meeting link outranks the title.
+	store.UpsertSessionMetadata(SessionMetadata{SessionID: "sess-1", MeetingURL: "https://meet.example.com/abc"})
+	store.UpsertSessionMetadata(SessionMetadata{SessionID: "standup-3", MeetingURL: "https://meet.example.com/abc"})
+	if prev, _ := store.PreviousRecurringSession("standup-3"); prev == nil || prev.ID != "sess-1" {
+		t.Errorf("by link: %+v, want sess-1", prev)
+	}
+
+	n, err := store.CopySpeakerNames("standup-2", "standup-3")
+	if err != nil || n != 2 {
+		t.Fatalf("CopySpeakerNames = %d, %v", n, err)
+	}
+	// Names already set on the target are kept.
+	store.SetSpeakerName("standup-3", "Speaker 2", "Bea")
+	if n, _ := store.CopySpeakerNames("standup-2", "standup-3"); n != 0 {
+		t.Errorf("second copy added %d", n)
+	}
+	if names, _ := store.SpeakerNames("standup-3"); names["Speaker 2"] != "Bea" || names["Speaker 1"] != "Ana" {
+		t.Errorf("names = %v", names)
+	}
+}
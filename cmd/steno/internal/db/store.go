@@ -28,9 +28,8 @@ func DefaultDBPath() string {
 }
 
 // Open opens the database in read-only mode with WAL.
-func Open(path string) (*Store, error) {
-	dsn := fmt.Sprintf("file:%s?mode=ro&_journal_mode=WAL", path)
-	db, err := sql.Open("sqlite", dsn)
+func Open(path string, opts Options) (*Store, error) {
+	db, err := sql.Open("sqlite", opts.dsn(path, "mode", "ro", "_journal_mode", "WAL"))
 	if err != nil {
 		return nil, fmt.Errorf("open database: %w", err)
 	}
@@ -116,54 +115,10 @@ func (s *Store) GetOverview() (*Overview, error) {
 	return overview, nil
 }
 
-// ListSessions returns sessions matching the given filters.
+// ListSessions returns sessions matching the given filters. See
+// FilterSessions for the metadata-aware variant.
 func (s *Store) ListSessions(limit int, before, after *time.Time, status string) ([]SessionWithCounts, error) {
-	query := `SELECT id, locale, startedAt, endedAt, title, status, createdAt FROM sessions WHERE 1=1`
-	var args []any
-
-	if status != "" {
-		query += ` AND status = ?`
-		args = append(args, status)
-	}
-	if after != nil {
-		query += ` AND startedAt >= ?`
-		args = append(args, float64(after.Unix()))
-	}
-	if before != nil {
-		query += ` AND startedAt <= ?`
-		args = append(args, float64(before.Unix()))
-	}
-
-	query += ` ORDER BY startedAt DESC LIMIT ?`
-	args = append(args, limit)
-
-	rows, err := s.db.Query(query, args...)
-	if err != nil {
-		return nil, fmt.Errorf("list sessions: %w", err)
-	}
-	var sessions []Session
-	for rows.Next() {
-		sess, err := scanSession(rows)
-		if err != nil {
-			rows.Close()
-			return nil, err
-		}
-		sessions = append(sessions, sess)
-	}
-	rows.Close()
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-
-	var results []SessionWithCounts
-	for _, sess := range sessions {
-		counts, err := s.SessionCounts(sess.ID)
-		if err != nil {
-			return nil, err
-		}
-		results = append(results, SessionWithCounts{Session: sess, Counts: counts})
-	}
-	return results, nil
+	return s.FilterSessions(SessionFilter{Limit: limit, Before: before, After: after, Status: status})
 }
 
 // GetSession returns a single session by ID.
@@ -271,10 +226,15 @@ func (s *Store) LatestSession() (*Session, error) {
 }
 
 // TopicsForSession returns all topics for a session, ordered by segment range.
+//
+// User edits (client-owned topic_edits) replace the LLM's title and
+// summary and set UserEdited.
 func (s *Store) TopicsForSession(sessionID string) ([]Topic, error) {
-	rows, err := s.db.Query(`
-		SELECT id, sessionId, title, summary, segmentRangeStart, segmentRangeEnd, createdAt
-		FROM topics
+	query, err := s.topicsQuery()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.db.Query(query+`
 		WHERE sessionId = ?
 		ORDER BY segmentRangeStart ASC
 	`, sessionID)
@@ -282,19 +242,7 @@ func (s *Store) TopicsForSession(sessionID string) ([]Topic, error) {
 		return nil, fmt.Errorf("query topics: %w", err)
 	}
 	defer rows.Close()
-
-	var topics []Topic
-	for rows.Next() {
-		var t Topic
-		var createdAt float64
-		if err := rows.Scan(&t.ID, &t.SessionID, &t.Title, &t.Summary,
-			&t.SegmentRangeStart, &t.SegmentRangeEnd, &createdAt); err != nil {
-			return nil, fmt.Errorf("scan topic: %w", err)
-		}
-		t.CreatedAt = timeFromUnix(createdAt)
-		topics = append(topics, t)
-	}
-	return topics, rows.Err()
+	return scanTopics(rows)
 }
 
 // LatestSummary returns the most recent summary for a session.
@@ -347,33 +295,28 @@ func (s *Store) SummariesForSession(sessionID string) ([]Summary, error) {
 	return summaries, rows.Err()
 }
 
-// SegmentsForSession returns paginated segments for a session.
+// SegmentsForSession returns paginated segments for a session. A
+// non-positive limit returns them all. Offset pages slow down the deeper
+// they start; page a long session with SegmentsAfter or SegmentPages.
 //
 // Default-filter (U9): rows where `duplicate_of IS NOT NULL` are excluded
 // — these are mic segments that the daemon's DedupCoordinator (U11)
 // marked as duplicates of an overlapping system-audio segment. Raw access
 // to all segments (including duplicates) is reserved for diagnostic SQL.
 func (s *Store) SegmentsForSession(sessionID string, limit, offset int) ([]Segment, error) {
-	rows, err := s.db.Query(`
-		SELECT id, sessionId, text, startedAt, endedAt, confidence, sequenceNumber, createdAt, source
-		FROM segments
-		WHERE sessionId = ? AND duplicate_of IS NULL
-		ORDER BY sequenceNumber ASC
-		LIMIT ? OFFSET ?
-	`, sessionID, limit, offset)
-	if err != nil {
-		return nil, fmt.Errorf("query segments: %w", err)
-	}
-	defer rows.Close()
-	return scanSegments(rows)
+	return s.QuerySegments(Query{Sessions: []string{sessionID}, Limit: limit, Offset: offset})
 }
 
 // SegmentsForRange returns segments within a sequence number range for a session.
 //
 // Default-filter (U9): excludes `duplicate_of IS NOT NULL`.
 func (s *Store) SegmentsForRange(sessionID string, start, end int) ([]Segment, error) {
+	cols, err := s.segmentColumns()
+	if err != nil {
+		return nil, err
+	}
 	rows, err := s.db.Query(`
-		SELECT id, sessionId, text, startedAt, endedAt, confidence, sequenceNumber, createdAt, source
+		SELECT `+cols+`
 		FROM segments
 		WHERE sessionId = ? AND sequenceNumber >= ? AND sequenceNumber <= ?
 		  AND duplicate_of IS NULL
@@ -390,59 +333,30 @@ func (s *Store) SegmentsForRange(sessionID string, start, end int) ([]Segment, e
 //
 // Default-filter (U9): excludes `duplicate_of IS NOT NULL`.
 func (s *Store) SegmentsForTimeRange(sessionID string, after, before *time.Time) ([]Segment, error) {
-	query := `SELECT id, sessionId, text, startedAt, endedAt, confidence, sequenceNumber, createdAt, source
-		FROM segments WHERE sessionId = ? AND duplicate_of IS NULL`
-	args := []any{sessionID}
-
-	if after != nil {
-		query += ` AND startedAt >= ?`
-		args = append(args, float64(after.Unix()))
-	}
-	if before != nil {
-		query += ` AND startedAt <= ?`
-		args = append(args, float64(before.Unix()))
-	}
-
-	query += ` ORDER BY sequenceNumber ASC`
-
-	rows, err := s.db.Query(query, args...)
-	if err != nil {
-		return nil, fmt.Errorf("query segments by time: %w", err)
-	}
-	defer rows.Close()
-	return scanSegments(rows)
+	return s.QuerySegments(Query{Sessions: []string{sessionID}, TimeRange: TimeRange{After: after, Before: before}})
 }
 
-// SearchSegments searches segment text using LIKE.
+// SearchSegments searches segment text using LIKE, newest first. An empty
+// sessionID searches every session.
 //
 // Default-filter (U9): excludes `duplicate_of IS NOT NULL`.
 func (s *Store) SearchSegments(query, sessionID string, limit int) ([]Segment, error) {
-	sqlQuery := `SELECT id, sessionId, text, startedAt, endedAt, confidence, sequenceNumber, createdAt, source
-		FROM segments WHERE text LIKE ? ESCAPE '\' AND duplicate_of IS NULL`
-	args := []any{"%" + escapeLike(query) + "%"}
-
+	q := Query{TextMatch: query, Newest: true, Limit: limit}
 	if sessionID != "" {
-		sqlQuery += ` AND sessionId = ?`
-		args = append(args, sessionID)
-	}
-
-	sqlQuery += ` ORDER BY startedAt DESC LIMIT ?`
-	args = append(args, limit)
-
-	rows, err := s.db.Query(sqlQuery, args...)
-	if err != nil {
-		return nil, fmt.Errorf("search segments: %w", err)
+		q.Sessions = []string{sessionID}
 	}
-	defer rows.Close()
-	return scanSegments(rows)
+	return s.QuerySegments(q)
 }
 
-// SearchTopics searches topic titles and summaries using LIKE.
+// SearchTopics searches topic titles and summaries using LIKE, matching
+// user edits rather than the text they replaced.
 func (s *Store) SearchTopics(query string, limit int) ([]Topic, error) {
+	topics, err := s.topicsQuery()
+	if err != nil {
+		return nil, err
+	}
 	pattern := "%" + escapeLike(query) + "%"
-	rows, err := s.db.Query(`
-		SELECT id, sessionId, title, summary, segmentRangeStart, segmentRangeEnd, createdAt
-		FROM topics
+	rows, err := s.db.Query(topics+`
 		WHERE title LIKE ? ESCAPE '\' OR summary LIKE ? ESCAPE '\'
 		ORDER BY createdAt DESC
 		LIMIT ?
@@ -451,19 +365,7 @@ func (s *Store) SearchTopics(query string, limit int) ([]Topic, error) {
 		return nil, fmt.Errorf("search topics: %w", err)
 	}
 	defer rows.Close()
-
-	var topics []Topic
-	for rows.Next() {
-		var t Topic
-		var createdAt float64
-		if err := rows.Scan(&t.ID, &t.SessionID, &t.Title, &t.Summary,
-			&t.SegmentRangeStart, &t.SegmentRangeEnd, &createdAt); err != nil {
-			return nil, fmt.Errorf("scan topic: %w", err)
-		}
-		t.CreatedAt = timeFromUnix(createdAt)
-		topics = append(topics, t)
-	}
-	return topics, rows.Err()
+	return scanTopics(rows)
 }
 
 // SearchSummaries searches summary content using LIKE.
@@ -506,9 +408,9 @@ func (s *Store) SearchSummaries(query, sessionID string, limit int) ([]Summary,
 // rows, matching the default-filter applied by the segment readers.
 func (s *Store) SessionCounts(sessionID string) (SessionCounts, error) {
 	var c SessionCounts
-	err := s.db.QueryRow(`SELECT COUNT(*) FROM segments WHERE sessionId = ? AND duplicate_of IS NULL`, sessionID).Scan(&c.Segments)
-	if err != nil {
-		return c, fmt.Errorf("count segments: %w", err)
+	var err error
+	if c.Segments, err = s.CountSegments(Query{Sessions: []string{sessionID}}); err != nil {
+		return c, err
 	}
 	err = s.db.QueryRow(`SELECT COUNT(*) FROM topics WHERE sessionId = ?`, sessionID).Scan(&c.Topics)
 	if err != nil {
@@ -546,15 +448,35 @@ func scanSession(rows *sql.Rows) (Session, error) {
 	return sess, nil
 }
 
-// scanSegments scans all segment rows.
+// segmentColumns is the select list scanSegments reads. The per-segment
+// locale and speaker arrived with migrations 20261014_001_segment_locale
+// and 20261014_002_segment_speaker; older databases select NULL in their
+// place.
+func (s *Store) segmentColumns() (string, error) {
+	optional := make([]string, 2)
+	for i, col := range []string{"locale", "speaker"} {
+		ok, err := hasColumn(s.db, "segments", col)
+		if err != nil {
+			return "", err
+		}
+		optional[i] = "NULL"
+		if ok {
+			optional[i] = col
+		}
+	}
+	return "id, sessionId, text, startedAt, endedAt, confidence, sequenceNumber, createdAt, source, " + strings.Join(optional, ", "), nil
+}
+
+// scanSegments scans all segment rows selected with segmentColumns.
 func scanSegments(rows *sql.Rows) ([]Segment, error) {
 	var segments []Segment
 	for rows.Next() {
 		var seg Segment
 		var startedAt, endedAt, createdAt float64
 		var confidence sql.NullFloat64
+		var locale, speaker sql.NullString
 		if err := rows.Scan(&seg.ID, &seg.SessionID, &seg.Text,
-			&startedAt, &endedAt, &confidence, &seg.SequenceNumber, &createdAt, &seg.Source); err != nil {
+			&startedAt, &endedAt, &confidence, &seg.SequenceNumber, &createdAt, &seg.Source, &locale, &speaker); err != nil {
 			return nil, fmt.Errorf("scan segment: %w", err)
 		}
 		seg.StartedAt = timeFromUnix(startedAt)
@@ -564,6 +486,8 @@ func scanSegments(rows *sql.Rows) ([]Segment, error) {
 			c := confidence.Float64
 			seg.Confidence = &c
 		}
+		seg.Locale = locale.String
+		seg.Speaker = speaker.String
 		segments = append(segments, seg)
 	}
 	return segments, rows.Err()
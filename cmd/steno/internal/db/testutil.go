@@ -9,7 +9,7 @@ import (
 )
 
 // createTestDB creates an in-memory SQLite database with the steno schema.
-func createTestDB(t *testing.T) *sql.DB {
+func createTestDB(t testing.TB) *sql.DB {
 	t.Helper()
 
 	db, err := sql.Open("sqlite", ":memory:")
@@ -0,0 +1,75 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// topicsQuery returns a SELECT over topics with any user edit applied,
+// ready for a WHERE clause, in scanTopics column order. Without the
+// client-owned topic_edits table it reads topics as-is.
+func (s *Store) topicsQuery() (string, error) {
+	ok, err := s.hasTable("topic_edits")
+	if err != nil {
+		return "", err
+	}
+	from := `(SELECT *, 0 AS userEdited FROM topics)`
+	if ok {
+		from = `(
+			SELECT tp.id, tp.sessionId, tp.segmentRangeStart, tp.segmentRangeEnd, tp.createdAt,
+				COALESCE(te.title, tp.title) AS title,
+				COALESCE(te.summary, tp.summary) AS summary,
+				te.topic_id IS NOT NULL AS userEdited
+			FROM topics tp LEFT JOIN topic_edits te ON te.topic_id = tp.id
+		)`
+	}
+	return `SELECT id, sessionId, title, summary, segmentRangeStart, segmentRangeEnd, createdAt, userEdited
+		FROM ` + from, nil
+}
+
+func scanTopics(rows *sql.Rows) ([]Topic, error) {
+	var topics []Topic
+	for rows.Next() {
+		var t Topic
+		var createdAt float64
+		if err := rows.Scan(&t.ID, &t.SessionID, &t.Title, &t.Summary,
+			&t.SegmentRangeStart, &t.SegmentRangeEnd, &createdAt, &t.UserEdited); err != nil {
+			return nil, fmt.Errorf("scan topic: %w", err)
+		}
+		t.CreatedAt = timeFromUnix(createdAt)
+		topics = append(topics, t)
+	}
+	return topics, rows.Err()
+}
+
+// EditTopic records the user's title and summary for a topic, replacing
+// any earlier edit. The daemon's row is left untouched; readers overlay
+// the edit (see TopicsForSession).
+func (s *Store) EditTopic(topicID, title, summary string) error {
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return errors.New("topic title cannot be empty")
+	}
+	var exists bool
+	if err := s.db.QueryRow(`SELECT EXISTS(SELECT 1 FROM topics WHERE id = ?)`, topicID).Scan(&exists); err != nil {
+		return fmt.Errorf("look up topic: %w", err)
+	}
+	if !exists {
+		return fmt.Errorf("topic %s not found", topicID)
+	}
+	_, err := s.db.Exec(`
+		INSERT INTO topic_edits (topic_id, title, summary, edited_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(topic_id) DO UPDATE SET
+			title = excluded.title,
+			summary = excluded.summary,
+			edited_at = excluded.edited_at
+	`, topicID, title, strings.TrimSpace(summary), float64(time.Now().UnixNano())/1e9)
+	if err != nil {
+		return fmt.Errorf("save topic edit: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package db
+
+import "testing"
+
+func TestEditTopicOverlaysLLMText(t *testing.T) {
+	rawDB := createTestDB(t)
+	defer rawDB.Close()
+	seedTestData(t, rawDB)
+	store := &Store{db: rawDB}
+
+	// Readers tolerate the table not existing yet.
+	topics, err := store.TopicsForSession("sess-1")
+	if err != nil || len(topics) != 2 || topics[0].UserEdited {
+		t.Fatalf("before schema: %+v, %v", topics, err)
+	}
+	if err := store.EnsureClientSchema(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.EditTopic("top-1", "  Q3 planning ", "Agreed on the Q3 scope"); err != nil {
+		t.Fatalf("EditTopic: %v", err)
+	}
+	if err := store.EditTopic("top-1", "", "x"); err == nil {
+		t.Error("expected error for an empty title")
+	}
+	if err := store.EditTopic("missing", "x", "y"); err == nil {
+		t.Error("expected error for an unknown topic")
+	}
+
+	topics, err = store.TopicsForSession("sess-1")
+	if err != nil {
+		t.Fatalf("TopicsForSession: %v", err)
+	}
+	if topics[0].Title != "Q3 planning" || topics[0].Summary != "Agreed on the Q3 scope" || !topics[0].UserEdited {
+		t.Errorf("edited topic = %+v", topics[0])
+	}
+	if topics[1].Title != "Code Review" || topics[1].UserEdited {
+		t.Errorf("unedited topic = %+v", topics[1])
+	}
+
+	// The daemon's row is untouched.
+	var title string
+	rawDB.QueryRow(`SELECT title FROM topics WHERE id = 'top-1'`).Scan(&title)
+	if title != "Sprint Planning" {
+		t.Errorf("topics.title = %q, want the LLM's original", title)
+	}
+
+	// Search matches the edit, not the text it replaced.
+	if found, _ := store.SearchTopics("Q3", 10); len(found) != 1 || !found[0].UserEdited {
+		t.Errorf("search for edit = %+v", found)
+	}
+	if found, _ := store.SearchTopics("Sprint Planning", 10); len(found) != 0 {
+		t.Errorf("search for replaced title = %+v", found)
+	}
+
+	// A second edit replaces the first.
+	if err := store.EditTopic("top-1", "Q3 planning", ""); err != nil {
+		t.Fatal(err)
+	}
+	topics, _ = store.TopicsForSession("sess-1")
+	if topics[0].Summary != "" {
+		t.Errorf("summary after re-edit = %q", topics[0].Summary)
+	}
+}
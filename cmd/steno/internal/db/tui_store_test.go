@@ -231,3 +231,76 @@ func TestLatestSession(t *testing.T) {
 		t.Errorf("session ID = %q, want %q", sess.ID, "sess-new")
 	}
 }
+
+// TestSegmentLocale reads the per-segment locale when the column exists,
+// and leaves it empty on databases that predate it.
+func TestSegmentLocale(t *testing.T) {
+	rawDB := createTestDB(t)
+	defer rawDB.Close()
+
+	now := float64(time.Now().Unix())
+	rawDB.Exec(`INSERT INTO sessions (id, locale, startedAt, status, createdAt)
+		VALUES ('sess-1', 'en_US', ?, 'active', ?)`, now, now)
+	rawDB.Exec(`INSERT INTO segments (id, sessionId, text, startedAt, endedAt, sequenceNumber, createdAt)
+		VALUES ('seg-1', 'sess-1', 'hello', ?, ?, 1, ?)`, now, now+1, now)
+
+	store := &Store{db: rawDB}
+	segments, err := store.SegmentsForSession("sess-1", -1, 0)
+	if err != nil {
+		t.Fatalf("SegmentsForSession before migration: %v", err)
+	}
+	if len(segments) != 1 || segments[0].Locale != "" {
+		t.Fatalf("before migration: %+v, want one segment with no locale", segments)
+	}
+
+	if _, err := rawDB.Exec(`ALTER TABLE segments ADD COLUMN locale TEXT`); err != nil {
+		t.Fatal(err)
+	}
+	rawDB.Exec(`INSERT INTO segments (id, sessionId, text, startedAt, endedAt, sequenceNumber, createdAt, locale)
+		VALUES ('seg-2', 'sess-1', 'bonjour', ?, ?, 2, ?, 'fr_FR')`, now+2, now+3, now)
+
+	segments, err = store.SegmentsForSession("sess-1", -1, 0)
+	if err != nil {
+		t.Fatalf("SegmentsForSession: %v", err)
+	}
+	if len(segments) != 2 || segments[0].Locale != "" || segments[1].Locale != "fr_FR" {
+		t.Errorf("segments = %+v, want locales \"\" then fr_FR", segments)
+	}
+	found, err := store.SearchSegments("bonjour", "", 10)
+	if err != nil || len(found) != 1 || found[0].Locale != "fr_FR" {
+		t.Errorf("SearchSegments = %+v, %v", found, err)
+	}
+}
+
+// TestSegmentSpeaker reads the per-segment speaker when the column
+// exists, and leaves it empty on databases that predate it.
+func TestSegmentSpeaker(t *testing.T) {
+	rawDB := createTestDB(t)
+	defer rawDB.Close()
+
+	now := float64(time.Now().Unix())
+	rawDB.Exec(`INSERT INTO sessions (id, locale, startedAt, status, createdAt)
+		VALUES ('sess-1', 'en_US', ?, 'active', ?)`, now, now)
+	rawDB.Exec(`INSERT INTO segments (id, sessionId, text, startedAt, endedAt, sequenceNumber, createdAt)
+		VALUES ('seg-1', 'sess-1', 'hello', ?, ?, 1, ?)`, now, now+1, now)
+
+	store := &Store{db: rawDB}
+	segments, err := store.SegmentsForSession("sess-1", -1, 0)
+	if err != nil || len(segments) != 1 || segments[0].Speaker != "" {
+		t.Fatalf("before migration: %+v, %v, want one segment with no speaker", segments, err)
+	}
+
+	if _, err := rawDB.Exec(`ALTER TABLE segments ADD COLUMN speaker TEXT`); err != nil {
+		t.Fatal(err)
+	}
+	rawDB.Exec(`INSERT INTO segments (id, sessionId, text, startedAt, endedAt, sequenceNumber, createdAt, speaker)
+		VALUES ('seg-2', 'sess-1', 'hi there', ?, ?, 2, ?, 'Speaker 2')`, now+2, now+3, now)
+
+	segments, err = store.SegmentsForSession("sess-1", -1, 0)
+	if err != nil {
+		t.Fatalf("SegmentsForSession: %v", err)
+	}
+	if len(segments) != 2 || segments[0].Speaker != "" || segments[1].Speaker != "Speaker 2" {
+		t.Errorf("segments = %+v, want speakers \"\" then Speaker 2", segments)
+	}
+}
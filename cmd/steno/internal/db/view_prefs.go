@@ -0,0 +1,75 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ViewPrefs is how the TUI last showed a session, restored when the
+// session is shown again. Speaker names are kept apart, in speaker_names,
+// since exports and `steno speakers` read them too.
+type ViewPrefs struct {
+	// Density is the transcript density's name ("compact"); empty keeps
+	// the configured one.
+	Density string
+	// FilterWord and FilterPattern are the transcript filter's word and
+	// regular expression, FilterSources the sources it is limited to.
+	// Empty when the transcript was unfiltered.
+	FilterWord    string
+	FilterPattern string
+	FilterSources []string
+	// ExpandedTopics are the IDs of the topics left unfolded.
+	ExpandedTopics []string
+	UpdatedAt      time.Time
+}
+
+// ViewPrefs returns a session's view preferences, nil when it has none
+// or the table does not exist yet.
+func (s *Store) ViewPrefs(sessionID string) (*ViewPrefs, error) {
+	ok, err := s.hasTable("session_view_prefs")
+	if err != nil || !ok {
+		return nil, err
+	}
+	var p ViewPrefs
+	var density, word, pattern, sources, topics sql.NullString
+	var updatedAt float64
+	err = s.db.QueryRow(`SELECT density, filter_word, filter_pattern, filter_sources, expanded_topics, updated_at
+		FROM session_view_prefs WHERE session_id = ?`, sessionID).
+		Scan(&density, &word, &pattern, &sources, &topics, &updatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("view prefs: %w", err)
+	}
+	p.Density = density.String
+	p.FilterWord, p.FilterPattern = word.String, pattern.String
+	p.FilterSources = splitList(sources.String)
+	p.ExpandedTopics = splitList(topics.String)
+	p.UpdatedAt = timeFromUnix(updatedAt)
+	return &p, nil
+}
+
+// SetViewPrefs replaces a session's view preferences. Requires a Store
+// opened with OpenClient.
+func (s *Store) SetViewPrefs(sessionID string, p ViewPrefs) error {
+	_, err := s.db.Exec(`
+		INSERT INTO session_view_prefs (session_id, density, filter_word, filter_pattern, filter_sources, expanded_topics, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(session_id) DO UPDATE SET
+			density = excluded.density,
+			filter_word = excluded.filter_word,
+			filter_pattern = excluded.filter_pattern,
+			filter_sources = excluded.filter_sources,
+			expanded_topics = excluded.expanded_topics,
+			updated_at = excluded.updated_at
+	`, sessionID, nullString(p.Density), nullString(p.FilterWord), nullString(p.FilterPattern),
+		nullString(strings.Join(p.FilterSources, "\n")), nullString(strings.Join(p.ExpandedTopics, "\n")),
+		unixFromTime(time.Now()))
+	if err != nil {
+		return fmt.Errorf("set view prefs: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package db
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestViewPrefsRoundTrip(t *testing.T) {
+	rawDB := createTestDB(t)
+	defer rawDB.Close()
+	seedTestData(t, rawDB)
+	store := &Store{db: rawDB}
+
+	// Readers tolerate the table not existing yet.
+	if p, err := store.ViewPrefs("sess-1"); err != nil || p != nil {
+		t.Fatalf("before schema: %+v, %v", p, err)
+	}
+	if err := store.EnsureClientSchema(); err != nil {
+		t.Fatal(err)
+	}
+	if p, err := store.ViewPrefs("sess-1"); err != nil || p != nil {
+		t.Fatalf("no prefs yet: %+v, %v", p, err)
+	}
+
+	want := ViewPrefs{
+		Density:        "compact",
+		FilterWord:     "budget",
+		FilterPattern:  `(?i)\bbudget\b`,
+		FilterSources:  []string{"microphone"},
+		ExpandedTopics: []string{"topic-1", "topic-2"},
+	}
+	if err := store.SetViewPrefs("sess-1", ViewPrefs{Density: "captions"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SetViewPrefs("sess-1", want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := store.ViewPrefs("sess-1")
+	if err != nil || got == nil {
+		t.Fatalf("ViewPrefs = %+v, %v", got, err)
+	}
+	if got.Density != want.Density || got.FilterWord != want.FilterWord || got.FilterPattern != want.FilterPattern ||
+		!slices.Equal(got.FilterSources, want.FilterSources) || !slices.Equal(got.ExpandedTopics, want.ExpandedTopics) {
+		t.Errorf("ViewPrefs = %+v, want the latest save %+v", got, want)
+	}
+	if got.UpdatedAt.IsZero() {
+		t.Error("UpdatedAt should be set")
+	}
+
+	// Saving an unfiltered, fully folded view clears those fields.
+	if err := store.SetViewPrefs("sess-1", ViewPrefs{Density: "compact"}); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := store.ViewPrefs("sess-1"); got.FilterWord != "" || got.FilterSources != nil || got.ExpandedTopics != nil {
+		t.Errorf("ViewPrefs = %+v, want no filter or expanded topics", got)
+	}
+	if p, _ := store.ViewPrefs("sess-2"); p != nil {
+		t.Errorf("sess-2 prefs = %+v, want none", p)
+	}
+}
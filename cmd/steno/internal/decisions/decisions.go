@@ -0,0 +1,101 @@
+// Package decisions flags transcript segments that record a decision
+// ("we agreed to ship Friday", "let's go with Postgres"). The classifier
+// is a handful of phrase patterns: cheap enough to run on every live
+// segment, and predictable enough that a missed or spurious decision is
+// easy to explain. Flagged segments are persisted in the client-owned
+// decisions table (see db/decisions.go) and lead Markdown exports.
+package decisions
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/jwulff/steno/internal/db"
+)
+
+// cues are the phrases that mark a decision, matched case-insensitively
+// on word boundaries. Each is reported by its first form.
+var cues = []struct {
+	name    string
+	pattern *regexp.Regexp
+}{
+	{"we agreed", regexp.MustCompile(`(?i)\bwe(?:'ve| have| all)? agreed\b`)},
+	{"we decided", regexp.MustCompile(`(?i)\bwe(?:'ve| have)? decided\b`)},
+	{"agreed to", regexp.MustCompile(`(?i)\bagreed (?:to|on|that)\b`)},
+	{"let's go with", regexp.MustCompile(`(?i)\blet'?s go (?:with|ahead)\b`)},
+	{"we'll go with", regexp.MustCompile(`(?i)\bwe(?:'ll| will|'re| are)(?: going to)? go(?:ing)? (?:with|ahead)\b`)},
+	{"the decision is", regexp.MustCompile(`(?i)\b(?:the|our|final) decision (?:is|was)\b`)},
+	{"settled on", regexp.MustCompile(`(?i)\b(?:settled|landed) on\b`)},
+	{"decided to", regexp.MustCompile(`(?i)\bdecided (?:to|on|that)\b`)},
+}
+
+// sentenceEnd splits text into sentences, keeping the terminator so
+// questions can be told apart.
+var sentenceEnd = regexp.MustCompile(`[^.!?]+[.!?]*`)
+
+// negation in the few words before a cue ("we haven't agreed to",
+// "never decided to") turns it into a non-decision.
+var negation = regexp.MustCompile(`(?i)\b(?:not|never)\b|n't\b`)
+
+// negationReach is how many words before a cue are checked for negation.
+const negationReach = 3
+
+// Detect returns the cue that marks text as a decision, or "". Questions
+// ("should we go with X?") and negated cues don't count.
+func Detect(text string) string {
+	for _, sentence := range sentenceEnd.FindAllString(text, -1) {
+		sentence = strings.TrimSpace(sentence)
+		if strings.HasSuffix(sentence, "?") {
+			continue
+		}
+		for _, c := range cues {
+			loc := c.pattern.FindStringIndex(sentence)
+			if loc == nil {
+				continue
+			}
+			before := strings.Fields(sentence[:loc[0]])
+			if negation.MatchString(strings.Join(before[max(0, len(before)-negationReach):], " ")) {
+				continue
+			}
+			return c.name
+		}
+	}
+	return ""
+}
+
+// Sequences returns the sequence numbers of the decisions among
+// segments — recorded ones plus any Detect finds — sorted and without
+// repeats. Exports use it so sessions the TUI never watched still list
+// their decisions.
+func Sequences(recorded []db.Decision, segments []db.Segment) []int {
+	var seqs []int
+	for _, d := range recorded {
+		seqs = append(seqs, d.SegmentSeq)
+	}
+	for _, s := range segments {
+		if Detect(s.Text) != "" {
+			seqs = append(seqs, s.SequenceNumber)
+		}
+	}
+	slices.Sort(seqs)
+	return slices.Compact(seqs)
+}
+
+// Sync records the decisions Detect finds among sessionID's segments and
+// returns every recorded decision for it. Requires a Store opened with
+// OpenClient.
+func Sync(store *db.Store, sessionID string) ([]db.Decision, error) {
+	segments, err := store.SegmentsForSession(sessionID, -1, 0)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range segments {
+		if cue := Detect(s.Text); cue != "" {
+			if err := store.RecordDecision(sessionID, s.SequenceNumber, cue); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return store.DecisionsForSession(sessionID)
+}
@@ -0,0 +1,50 @@
+package decisions
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jwulff/steno/internal/db"
+)
+
+func TestDetect(t *testing.T) {
+	for _, tc := range []struct {
+		text, want string
+	}{
+		{"Okay, we agreed to ship on Friday.", "we agreed"},
+		{"So we've decided: Postgres it is.", "we decided"},
+		{"Let's go with the blue design", "let's go with"},
+		{"lets go ahead and merge it", "let's go with"},
+		{"We're going with option B for now.", "we'll go with"},
+		{"The final decision was to delay the launch.", "the decision is"},
+		{"Everyone agreed on two-week sprints.", "agreed to"},
+		{"I think we settled on Tuesdays.", "settled on"},
+		{"No, let's go with the cheaper one.", "let's go with"},
+		{"Is that a question? We agreed, though.", "we agreed"},
+
+		{"Should we go with the blue design?", ""},
+		{"Have we agreed on a date?", ""},
+		{"We haven't agreed to anything yet.", ""},
+		{"They have not decided to renew.", ""},
+		{"I agree that it's risky.", ""},
+		{"Let me show you the dashboard.", ""},
+		{"", ""},
+	} {
+		if got := Detect(tc.text); got != tc.want {
+			t.Errorf("Detect(%q) = %q, want %q", tc.text, got, tc.want)
+		}
+	}
+}
+
+func TestSequences(t *testing.T) {
+	recorded := []db.Decision{{SegmentSeq: 4}, {SegmentSeq: 2}}
+	segments := []db.Segment{
+		{SequenceNumber: 1, Text: "Hello everyone."},
+		{SequenceNumber: 2, Text: "We agreed to ship Friday."},
+		{SequenceNumber: 3, Text: "Let's go with Postgres."},
+		{SequenceNumber: 4, Text: "Flagged some other way."},
+	}
+	if got := Sequences(recorded, segments); !reflect.DeepEqual(got, []int{2, 3, 4}) {
+		t.Errorf("Sequences = %v, want [2 3 4]", got)
+	}
+}
@@ -0,0 +1,89 @@
+// Package dictation turns spoken formatting commands in dictated text
+// into what they stand for: "new paragraph" into a paragraph break,
+// "open bracket" into "[". Rules are regular expressions with
+// replacements, kept per profile in the client-owned dictation_macros
+// table (`steno macros`). Like scrubbing, it is display-time only: the
+// TUI rewrites the mic segments it shows while in dictation mode, and
+// the database keeps what was heard.
+package dictation
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// Rule rewrites every match of Pattern, a Go regular expression matched
+// case-insensitively, to Replacement, which may refer to submatches as
+// $1 or ${name}.
+type Rule struct {
+	Pattern     string
+	Replacement string
+}
+
+// BuiltinRules run after a profile's own rules, so a profile can claim a
+// phrase first. The recognizer punctuates commands like speech ("New
+// paragraph."), so the rules swallow that punctuation and the spaces
+// around the break or bracket.
+var BuiltinRules = []Rule{
+	{`[,.]?\s*\bnew paragraph\b[,.]?\s*`, "\n\n"},
+	{`[,.]?\s*\bnew line\b[,.]?\s*`, "\n"},
+	{`\bopen (?:square )?bracket\b[,.]?\s*`, "["},
+	{`[,.]?\s*\bclose (?:square )?bracket\b`, "]"},
+	{`\bopen paren(?:thesis)?\b[,.]?\s*`, "("},
+	{`[,.]?\s*\bclose paren(?:thesis)?\b`, ")"},
+	{`\bopen quote\b[,.]?\s*`, `"`},
+	{`[,.]?\s*\bclose quote\b`, `"`},
+}
+
+// Macros applies a compiled rule set. A nil *Macros leaves text
+// unchanged.
+type Macros struct {
+	rules []compiled
+}
+
+type compiled struct {
+	re          *regexp.Regexp
+	replacement string
+}
+
+// Validate reports whether r's pattern compiles.
+func Validate(r Rule) error {
+	_, err := compile(r)
+	return err
+}
+
+// Compile builds the macros for rules, in order, followed by
+// BuiltinRules.
+func Compile(rules []Rule) (*Macros, error) {
+	m := &Macros{}
+	for _, r := range append(rules[:len(rules):len(rules)], BuiltinRules...) {
+		c, err := compile(r)
+		if err != nil {
+			return nil, err
+		}
+		m.rules = append(m.rules, c)
+	}
+	return m, nil
+}
+
+func compile(r Rule) (compiled, error) {
+	if r.Pattern == "" {
+		return compiled{}, fmt.Errorf("dictation macro: empty pattern")
+	}
+	re, err := regexp.Compile(`(?i)` + r.Pattern)
+	if err != nil {
+		return compiled{}, fmt.Errorf("dictation macro %q: %w", r.Pattern, err)
+	}
+	return compiled{re: re, replacement: r.Replacement}, nil
+}
+
+// Apply returns text with each rule applied in turn.
+func (m *Macros) Apply(text string) string {
+	if m == nil {
+		return text
+	}
+	for _, r := range m.rules {
+		text = r.re.ReplaceAllString(text, r.replacement)
+	}
+	return text
+}
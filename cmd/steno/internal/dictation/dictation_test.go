@@ -0,0 +1,50 @@
+package dictation
+
+import "testing"
+
+func TestBuiltinRules(t *testing.T) {
+	m, err := Compile(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct{ in, want string }{
+		{"Dear team, new paragraph. Thanks for coming.", "Dear team\n\nThanks for coming."},
+		{"First item New line second item", "First item\nsecond item"},
+		{"see open bracket appendix close bracket.", "see [appendix]."},
+		{"call it open quote done close quote", `call it "done"`},
+		{"a newline is not a command", "a newline is not a command"},
+	} {
+		if got := m.Apply(tc.in); got != tc.want {
+			t.Errorf("Apply(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestProfileRulesRunFirst(t *testing.T) {
+	m, err := Compile([]Rule{
+		{Pattern: `\bnew paragraph\b`, Replacement: " ¶ "},
+		{Pattern: `\bticket (\d+)`, Replacement: "JIRA-$1"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := m.Apply("see Ticket 42 new paragraph done"); got != "see JIRA-42  ¶  done" {
+		t.Errorf("Apply = %q", got)
+	}
+}
+
+func TestInvalidRules(t *testing.T) {
+	if err := Validate(Rule{Pattern: "(unclosed"}); err == nil {
+		t.Error("want an error for an invalid pattern")
+	}
+	if err := Validate(Rule{}); err == nil {
+		t.Error("want an error for an empty pattern")
+	}
+	if _, err := Compile([]Rule{{Pattern: "["}}); err == nil {
+		t.Error("Compile should reject an invalid rule")
+	}
+	var m *Macros
+	if m.Apply("new line") != "new line" {
+		t.Error("nil Macros should leave text alone")
+	}
+}
@@ -0,0 +1,122 @@
+// Package entities picks dates, times, money amounts and links out of
+// transcript text, for the TUI to highlight and list and for `steno
+// entities` to export. Like the decisions classifier, it's a handful of
+// patterns rather than a model: cheap enough for every live segment,
+// and tuned to how the ASR writes things ("March 3rd", "2:30 pm",
+// "$1,200", "5 million dollars").
+package entities
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jwulff/steno/internal/db"
+)
+
+// Kind is what an entity is.
+type Kind string
+
+// Entity kinds, in the order lists group them.
+const (
+	Date   Kind = "date"
+	Time   Kind = "time"
+	Amount Kind = "amount"
+	URL    Kind = "url"
+)
+
+// Kinds lists every kind, in list order.
+var Kinds = []Kind{Date, Time, Amount, URL}
+
+// Match is one entity found in a text, at text[Start:End].
+type Match struct {
+	Kind       Kind
+	Text       string
+	Start, End int
+}
+
+const (
+	month    = `(?:jan(?:uary)?|feb(?:ruary)?|mar(?:ch)?|apr(?:il)?|may|june?|july?|aug(?:ust)?|sept?(?:ember)?|oct(?:ober)?|nov(?:ember)?|dec(?:ember)?)`
+	day      = `(?:[12]\d|3[01]|0?[1-9])(?:st|nd|rd|th)?`
+	weekday  = `(?:mon|tues|wednes|thurs|fri|satur|sun)day`
+	scale    = `(?:\s?(?:k|m|bn|thousand|million|billion))?`
+	meridiem = `(?:[ap]m\b|[ap]\.m\.)`
+)
+
+// patterns are tried in order; where matches overlap, the earlier
+// pattern's wins, so the digits of a link aren't also an amount.
+var patterns = []struct {
+	kind Kind
+	re   *regexp.Regexp
+}{
+	{URL, regexp.MustCompile(`(?i)\b(?:https?://|www\.)[^\s<>"]*[^\s<>".,;:!?)\]'"]`)},
+	{Amount, regexp.MustCompile(`(?i)[$€£¥]\s?\d[\d,]*(?:\.\d+)?` + scale + `\b`)},
+	{Amount, regexp.MustCompile(`(?i)\b\d[\d,]*(?:\.\d+)?` + scale + `\s(?:dollars|euros|pounds|bucks|usd|eur|gbp)\b`)},
+	{Date, regexp.MustCompile(`(?i)\b` + month + `\.?\s` + day + `\b(?:,?\s\d{4}\b)?`)},
+	{Date, regexp.MustCompile(`(?i)\b(?:the\s)?` + day + `\sof\s` + month + `\b(?:,?\s\d{4}\b)?`)},
+	{Date, regexp.MustCompile(`\b\d{4}-\d{2}-\d{2}\b|\b\d{1,2}/\d{1,2}/\d{2,4}\b`)},
+	{Date, regexp.MustCompile(`(?i)\b(?:(?:next|this|last)\s)?` + weekday + `\b|\b(?:today|tomorrow|yesterday)\b|\b(?:next|last)\s(?:week|month|quarter|year)\b`)},
+	{Time, regexp.MustCompile(`(?i)\b(?:[01]?\d|2[0-3]):[0-5]\d(?:\s?` + meridiem + `)?|\b(?:1[0-2]|0?[1-9])\s?` + meridiem + `|\b(?:1[0-2]|0?[1-9])\so'clock\b|\b(?:noon|midnight)\b`)},
+}
+
+// Find returns the entities in text, in order and without overlaps.
+func Find(text string) []Match {
+	var found []Match
+	for _, p := range patterns {
+		for _, loc := range p.re.FindAllStringIndex(text, -1) {
+			if slices.ContainsFunc(found, func(f Match) bool { return loc[0] < f.End && f.Start < loc[1] }) {
+				continue
+			}
+			found = append(found, Match{Kind: p.kind, Text: text[loc[0]:loc[1]], Start: loc[0], End: loc[1]})
+		}
+	}
+	slices.SortFunc(found, func(a, b Match) int { return a.Start - b.Start })
+	return found
+}
+
+// Highlight returns text with each entity passed through mark.
+func Highlight(text string, mark func(string) string) string {
+	var b strings.Builder
+	end := 0
+	for _, m := range Find(text) {
+		b.WriteString(text[end:m.Start])
+		b.WriteString(mark(m.Text))
+		end = m.End
+	}
+	b.WriteString(text[end:])
+	return b.String()
+}
+
+// Entity is one distinct entity of a session: how often it came up, and
+// the segment it first came up in.
+type Entity struct {
+	Kind     Kind
+	Text     string
+	Count    int
+	FirstSeq int
+	FirstAt  time.Time
+}
+
+// Collect lists the distinct entities in segments, grouped by kind in
+// Kinds order and by first mention within a kind. Mentions that differ
+// only in case or spacing count as one, under the first one's spelling.
+func Collect(segments []db.Segment) []Entity {
+	var out []Entity
+	index := map[string]int{}
+	for _, s := range segments {
+		for _, m := range Find(s.Text) {
+			key := string(m.Kind) + "\x00" + strings.ToLower(strings.Join(strings.Fields(m.Text), " "))
+			if i, ok := index[key]; ok {
+				out[i].Count++
+				continue
+			}
+			index[key] = len(out)
+			out = append(out, Entity{Kind: m.Kind, Text: m.Text, Count: 1, FirstSeq: s.SequenceNumber, FirstAt: s.StartedAt})
+		}
+	}
+	slices.SortStableFunc(out, func(a, b Entity) int {
+		return slices.Index(Kinds, a.Kind) - slices.Index(Kinds, b.Kind)
+	})
+	return out
+}
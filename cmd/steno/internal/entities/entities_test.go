@@ -0,0 +1,61 @@
+package entities
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jwulff/steno/internal/db"
+)
+
+func TestFind(t *testing.T) {
+	for _, tc := range []struct {
+		text string
+		want []string
+	}{
+		{"Let's ship it on March 3rd, 2026 at 2:30 pm.", []string{"date:March 3rd, 2026", "time:2:30 pm"}},
+		{"The budget is $1,200, maybe $3.5m next year.", []string{"amount:$1,200", "amount:$3.5m", "date:next year"}},
+		{"They quoted 40 thousand dollars.", []string{"amount:40 thousand dollars"}},
+		{"See https://example.com/plan?id=42, or www.example.org.", []string{"url:https://example.com/plan?id=42", "url:www.example.org"}},
+		{"Review on the 14th of June, then again next Friday at noon.", []string{"date:the 14th of June", "date:next Friday", "time:noon"}},
+		{"Deadline 2026-04-01 14:00, or 4/2/26 at 9 a.m.", []string{"date:2026-04-01", "time:14:00", "date:4/2/26", "time:9 a.m."}},
+		{"I may have three points about version 2.", nil},
+		{"", nil},
+	} {
+		var got []string
+		for _, m := range Find(tc.text) {
+			if tc.text[m.Start:m.End] != m.Text {
+				t.Errorf("Find(%q): %q not at [%d:%d]", tc.text, m.Text, m.Start, m.End)
+			}
+			got = append(got, string(m.Kind)+":"+m.Text)
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("Find(%q) = %q, want %q", tc.text, got, tc.want)
+		}
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	got := Highlight("Pay $40 by Friday.", func(s string) string { return "<" + s + ">" })
+	if got != "Pay <$40> by <Friday>." {
+		t.Errorf("Highlight = %q", got)
+	}
+}
+
+func TestCollect(t *testing.T) {
+	at := time.Date(2026, 3, 2, 9, 30, 0, 0, time.UTC)
+	segments := []db.Segment{
+		{SequenceNumber: 1, StartedAt: at, Text: "Call at 3 pm about the $500 invoice."},
+		{SequenceNumber: 2, StartedAt: at.Add(time.Minute), Text: "Due Friday. Friday works."},
+		{SequenceNumber: 3, StartedAt: at.Add(2 * time.Minute), Text: "Make it 3 PM then, on friday."},
+	}
+	var got []string
+	for _, e := range Collect(segments) {
+		got = append(got, strings.Join([]string{string(e.Kind), e.Text, string(rune('0' + e.Count)), string(rune('0' + e.FirstSeq))}, "|"))
+	}
+	want := []string{"date|Friday|3|2", "time|3 pm|2|1", "amount|$500|1|1"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Collect = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,88 @@
+package export
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/jwulff/steno/internal/db"
+)
+
+// AnkiTSV writes one flashcard per annotated segment, as a tab-separated
+// file for Anki's File → Import. The front is the segment's text; the
+// back carries its notes and reactions, then where it was said, with a
+// link back to it. Header lines tell Anki the separator, note type and
+// columns, so the file imports without setup; deck, when set, is the
+// deck to import into.
+//
+// Each card's GUID is derived from its segment, so importing again after
+// more annotating updates the cards already made instead of duplicating
+// them. sessions supplies titles for the back; annotations of sessions
+// missing from it are still exported. It returns the number of cards.
+func AnkiTSV(w io.Writer, sessions []db.Session, anns []db.Annotation, deck string) (int, error) {
+	titles := make(map[string]string, len(sessions))
+	for _, s := range sessions {
+		titles[s.ID] = s.Title
+	}
+
+	type card struct {
+		first db.Annotation
+		backs []string
+		kinds []string
+	}
+	var cards []*card
+	bySegment := map[string]*card{}
+	for _, a := range anns {
+		c := bySegment[a.SegmentID]
+		if c == nil {
+			c = &card{first: a}
+			bySegment[a.SegmentID] = c
+			cards = append(cards, c)
+		}
+		switch a.Kind {
+		case db.AnnotationNote:
+			c.backs = append(c.backs, ankiField(a.Body))
+		case db.AnnotationReaction:
+			c.backs = append(c.backs, ankiField(kindLabel(a)))
+		}
+		if !slices.Contains(c.kinds, a.Kind) {
+			c.kinds = append(c.kinds, a.Kind)
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("#separator:tab\n#html:true\n#notetype:Basic\n")
+	if deck != "" {
+		fmt.Fprintf(&b, "#deck:%s\n", strings.Join(strings.Fields(deck), " "))
+	}
+	b.WriteString("#guid column:1\n#tags column:4\n#columns:GUID\tFront\tBack\tTags\n")
+	for _, c := range cards {
+		a := c.first
+		title := titles[a.SessionID]
+		if title == "" {
+			title = "Untitled session"
+		}
+		where := fmt.Sprintf(`<small>%s · %s · %s · <a href="%s">#%d</a></small>`,
+			ankiField(title), a.SegmentStartedAt.Local().Format("2006-01-02 15:04"), sourceLabel(a.SegmentSource),
+			html.EscapeString(SegmentPermalink(a.SessionID, a.SegmentSeq)), a.SegmentSeq)
+		back := strings.Join(append(c.backs, where), "<br>")
+		tags := "steno " + strings.Join(c.kinds, " ")
+		fmt.Fprintf(&b, "steno-%s\t%s\t%s\t%s\n", a.SegmentID, ankiField(a.SegmentText), back, tags)
+	}
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return 0, err
+	}
+	return len(cards), nil
+}
+
+// ankiField escapes text for an HTML field of a tab-separated row: tabs
+// would split the field and newlines the row, so they become a space and
+// a <br>.
+func ankiField(text string) string {
+	text = html.EscapeString(strings.TrimSpace(text))
+	text = strings.ReplaceAll(text, "\t", " ")
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	return strings.ReplaceAll(text, "\n", "<br>")
+}
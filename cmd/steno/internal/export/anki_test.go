@@ -0,0 +1,56 @@
+package export
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jwulff/steno/internal/db"
+)
+
+func TestAnkiTSV(t *testing.T) {
+	at := time.Date(2026, 3, 10, 14, 0, 0, 0, time.Local)
+	seg := func(id string, seq int, text string) db.Annotation {
+		return db.Annotation{SessionID: "s1", SegmentID: id, SegmentSeq: seq, SegmentText: text, SegmentStartedAt: at, SegmentSource: "systemAudio"}
+	}
+	verb := seg("g1", 3, "On va <enfin>\tlivrer vendredi")
+	verb.Kind = db.AnnotationBookmark
+	gloss := seg("g1", 3, "On va <enfin>\tlivrer vendredi")
+	gloss.Kind, gloss.Body = db.AnnotationNote, "livrer = to deliver\nenfin = finally"
+	other := seg("g2", 9, "C'est bon.")
+	other.Kind, other.Body = db.AnnotationReaction, "👍"
+
+	var b strings.Builder
+	n, err := AnkiTSV(&b, []db.Session{{ID: "s1", Title: "Cours & café"}}, []db.Annotation{verb, gloss, other}, "French  mining")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Errorf("cards = %d, want 2 (one per segment)", n)
+	}
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n")
+	if lines[0] != "#separator:tab" || lines[3] != "#deck:French mining" || lines[6] != "#columns:GUID\tFront\tBack\tTags" {
+		t.Errorf("header = %q", lines[:7])
+	}
+	rows := lines[7:]
+	if len(rows) != 2 {
+		t.Fatalf("rows = %q", rows)
+	}
+	first := strings.Split(rows[0], "\t")
+	if len(first) != 4 {
+		t.Fatalf("fields = %q", first)
+	}
+	if first[0] != "steno-g1" || first[1] != "On va &lt;enfin&gt; livrer vendredi" {
+		t.Errorf("guid/front = %q", first[:2])
+	}
+	if want := "livrer = to deliver<br>enfin = finally<br><small>Cours &amp; café · 2026-03-10 14:00 · system audio · " +
+		`<a href="steno://session/s1#seg-3">#3</a></small>`; first[2] != want {
+		t.Errorf("back = %q\nwant   %q", first[2], want)
+	}
+	if first[3] != "steno bookmark note" {
+		t.Errorf("tags = %q", first[3])
+	}
+	if second := strings.Split(rows[1], "\t"); !strings.HasPrefix(second[2], "Reaction 👍<br>") || second[3] != "steno reaction" {
+		t.Errorf("reaction row = %q", second)
+	}
+}
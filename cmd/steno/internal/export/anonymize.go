@@ -0,0 +1,226 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jwulff/steno/internal/scrub"
+)
+
+// Redaction levels: which patterns a preset masks in segment text.
+const (
+	// RedactSecrets masks scrub.DefaultPatterns: card numbers and API
+	// tokens.
+	RedactSecrets = "secrets"
+	// RedactPII also masks scrub.PIIPatterns: emails, phone numbers, SSNs
+	// and IP addresses.
+	RedactPII = "pii"
+)
+
+// Metadata fields a preset can strip from an export.
+const (
+	StripDevice        = "device"
+	StripForegroundApp = "foreground_app"
+	StripMeetingURL    = "meeting_url"
+	StripTitle         = "title"
+	StripSessionID     = "session_id"
+	// StripTimestamps drops wall-clock times; lines keep their offset
+	// from the start of the recording.
+	StripTimestamps = "timestamps"
+)
+
+// Preset is a named anonymization recipe applied at export time.
+type Preset struct {
+	Name    string
+	Summary string
+	// PseudonymizeSpeakers replaces speaker labels with "Speaker 1",
+	// "Speaker 2", ... in order of first appearance.
+	PseudonymizeSpeakers bool
+	// Redact is RedactSecrets, RedactPII, or empty for none.
+	Redact string
+	// Strip lists the metadata fields removed from the export.
+	Strip []string
+}
+
+// Presets are the built-in anonymization presets, least to most
+// aggressive.
+var Presets = []Preset{
+	{
+		Name:    "internal-share",
+		Summary: "mask secrets; drop device, app and meeting link",
+		Redact:  RedactSecrets,
+		Strip:   []string{StripDevice, StripForegroundApp, StripMeetingURL},
+	},
+	{
+		Name:                 "gdpr-minimal",
+		Summary:              "pseudonymize speakers; mask personal data; drop device, app and meeting link",
+		PseudonymizeSpeakers: true,
+		Redact:               RedactPII,
+		Strip:                []string{StripDevice, StripForegroundApp, StripMeetingURL},
+	},
+	{
+		Name:                 "full",
+		Summary:              "gdpr-minimal, plus drop the title, session ID and wall-clock times",
+		PseudonymizeSpeakers: true,
+		Redact:               RedactPII,
+		Strip:                []string{StripDevice, StripForegroundApp, StripMeetingURL, StripTitle, StripSessionID, StripTimestamps},
+	},
+}
+
+// PresetNames lists the accepted preset names.
+func PresetNames() []string {
+	names := make([]string, 0, len(Presets))
+	for _, p := range Presets {
+		names = append(names, p.Name)
+	}
+	return names
+}
+
+// LookupPreset returns the preset called name.
+func LookupPreset(name string) (Preset, error) {
+	for _, p := range Presets {
+		if p.Name == name {
+			return p, nil
+		}
+	}
+	return Preset{}, fmt.Errorf("unknown anonymization preset %q: want one of %s", name, strings.Join(PresetNames(), ", "))
+}
+
+// Anonymization records what a preset did to an export, for the manifest.
+type Anonymization struct {
+	Preset                string   `json:"preset"`
+	PseudonymizedSpeakers int      `json:"pseudonymized_speakers"`
+	Redact                string   `json:"redact,omitempty"`
+	Redactions            int      `json:"redactions"`
+	Stripped              []string `json:"stripped,omitempty"`
+}
+
+// Anonymize applies p to t and returns the rewritten transcript with a
+// record of what changed. t is not modified.
+func Anonymize(t Transcript, p Preset) (Transcript, Anonymization, error) {
+	rec := Anonymization{Preset: p.Name, Redact: p.Redact, Stripped: slices.Clone(p.Strip)}
+
+	var patterns []string
+	switch p.Redact {
+	case "":
+	case RedactSecrets:
+		patterns = scrub.DefaultPatterns
+	case RedactPII:
+		patterns = append(slices.Clone(scrub.DefaultPatterns), scrub.PIIPatterns...)
+	default:
+		return t, rec, fmt.Errorf("preset %s: unknown redaction %q", p.Name, p.Redact)
+	}
+	var scrubber *scrub.Scrubber
+	if len(patterns) > 0 {
+		var err error
+		if scrubber, err = scrub.New(patterns, ""); err != nil {
+			return t, rec, err
+		}
+	}
+
+	out := t
+	for _, field := range p.Strip {
+		switch field {
+		case StripDevice:
+			out.Device = ""
+		case StripForegroundApp:
+			out.ForegroundApp = ""
+		case StripMeetingURL:
+			out.MeetingURL = ""
+		case StripTitle:
+			out.Title = ""
+		case StripSessionID:
+			out.SessionID = ""
+		case StripTimestamps:
+			out.StartedAt = time.Time{}
+		default:
+			return t, rec, fmt.Errorf("preset %s: unknown metadata field %q", p.Name, field)
+		}
+	}
+	stripTimes := slices.Contains(p.Strip, StripTimestamps)
+	// Titles, topics and the summary are generated from the
+	// conversation, so they are redacted like the text when they survive.
+	out.Title, rec.Redactions = scrubber.ApplyCount(out.Title)
+	// Notes are the user's own words about the conversation: redacted
+	// like it, and their line stamps are wall-clock times.
+	var n, m int
+	out.Summary, m = scrubber.ApplyCount(out.Summary)
+	out.Notes, n = scrubber.ApplyCount(out.Notes)
+	rec.Redactions += n + m
+	if stripTimes {
+		out.Notes = noteStamp.ReplaceAllString(out.Notes, "")
+	}
+	out.Topics = make([]TranscriptTopic, len(t.Topics))
+	for i, topic := range t.Topics {
+		var n, m int
+		topic.Title, n = scrubber.ApplyCount(topic.Title)
+		topic.Summary, m = scrubber.ApplyCount(topic.Summary)
+		rec.Redactions += n + m
+		out.Topics[i] = topic
+	}
+
+	pseudonyms := map[string]string{}
+	out.Lines = make([]TranscriptLine, len(t.Lines))
+	for i, l := range t.Lines {
+		if p.PseudonymizeSpeakers {
+			alias, ok := pseudonyms[l.Speaker]
+			if !ok {
+				alias = fmt.Sprintf("Speaker %d", len(pseudonyms)+1)
+				pseudonyms[l.Speaker] = alias
+			}
+			l.Speaker = alias
+		}
+		if stripTimes {
+			l.At = time.Time{}
+		}
+		var n int
+		l.Text, n = scrubber.ApplyCount(l.Text)
+		rec.Redactions += n
+		out.Lines[i] = l
+	}
+	rec.PseudonymizedSpeakers = len(pseudonyms)
+	return out, rec, nil
+}
+
+// noteStamp matches the db.NoteStampLayout stamp at the start of a line.
+var noteStamp = regexp.MustCompile(`(?m)^\[\d{2}:\d{2}:\d{2}\] `)
+
+// Manifest describes one export: what was exported, when, in which
+// format, and how it was anonymized. It is written next to the export so
+// a reviewer can tell what a shared file does and doesn't contain.
+type Manifest struct {
+	Tool       string    `json:"tool"`
+	Version    string    `json:"version"`
+	ExportedAt time.Time `json:"exported_at"`
+	Format     string    `json:"format"`
+	// SessionID is omitted when the preset strips it.
+	SessionID     string         `json:"session_id,omitempty"`
+	Segments      int            `json:"segments"`
+	Range         *SegmentRange  `json:"range,omitempty"`
+	Anonymization *Anonymization `json:"anonymization,omitempty"`
+	// Clean marks the clean verbatim document (see CleanVerbatim), and
+	// an export --json that carries its text.
+	Clean bool `json:"clean,omitempty"`
+	// TermFixes counts the custom dictionary spellings replaced (see
+	// FixTerms).
+	TermFixes int `json:"term_fixes,omitempty"`
+}
+
+// SegmentRange is the part of a session exported, when not all of it:
+// the first and last segment numbers included.
+type SegmentRange struct {
+	First int `json:"first"`
+	Last  int `json:"last"`
+}
+
+// WriteManifest writes m as indented JSON.
+func WriteManifest(w io.Writer, m Manifest) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(m)
+}
@@ -0,0 +1,131 @@
+package export
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jwulff/steno/internal/db"
+)
+
+func anonymizeFixture() Transcript {
+	at := time.Date(2026, 3, 10, 14, 0, 0, 0, time.Local)
+	sess := db.Session{
+		ID: "sess-1", Title: "Call with jane@example.com", StartedAt: at,
+		Device: "MacBook Pro Microphone", ForegroundApp: "zoom.us", MeetingURL: "https://meet.example.com/abc",
+	}
+	return NewTranscript(sess, []db.Segment{
+		{SequenceNumber: 1, Text: "my number is 555-123-4567", StartedAt: at, Source: "microphone"},
+		{SequenceNumber: 2, Text: "the key is sk-abcdefghijklmnopqrstuv", StartedAt: at.Add(time.Minute), Source: "systemAudio"},
+		{SequenceNumber: 3, Text: "got it", StartedAt: at.Add(2 * time.Minute), Source: "microphone"},
+	})
+}
+
+func TestLookupPreset(t *testing.T) {
+	for _, name := range PresetNames() {
+		if p, err := LookupPreset(name); err != nil || p.Name != name {
+			t.Errorf("LookupPreset(%q) = %+v, %v", name, p, err)
+		}
+	}
+	if _, err := LookupPreset("hipaa"); err == nil || !strings.Contains(err.Error(), "gdpr-minimal") {
+		t.Errorf("unknown preset error = %v", err)
+	}
+}
+
+func TestAnonymizeInternalShare(t *testing.T) {
+	p, _ := LookupPreset("internal-share")
+	in := anonymizeFixture()
+	out, rec, err := Anonymize(in, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Device != "" || out.ForegroundApp != "" || out.MeetingURL != "" {
+		t.Errorf("context not stripped: %+v", out)
+	}
+	// Secrets only: the phone number and title's email stay.
+	if out.Lines[0].Text != "my number is 555-123-4567" || out.Lines[1].Text != "the key is [redacted]" {
+		t.Errorf("lines = %+v", out.Lines)
+	}
+	if out.Title != in.Title || out.Lines[0].Speaker != "mic" || out.Lines[0].At.IsZero() {
+		t.Errorf("kept fields changed: %+v", out)
+	}
+	if rec.Redactions != 1 || rec.PseudonymizedSpeakers != 0 || rec.Preset != "internal-share" {
+		t.Errorf("record = %+v", rec)
+	}
+	// The input is left alone.
+	if in.Device == "" || in.Lines[1].Text == out.Lines[1].Text {
+		t.Error("Anonymize modified its input")
+	}
+}
+
+func TestAnonymizeGDPRMinimal(t *testing.T) {
+	p, _ := LookupPreset("gdpr-minimal")
+	in := anonymizeFixture()
+	in.Topics = []TranscriptTopic{{Title: "Follow-up", Summary: "Jane will email bob@example.com.", StartSeq: 1, EndSeq: 3}}
+	out, rec, err := Anonymize(in, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Topics[0].Summary != "Jane will email [redacted]." || in.Topics[0].Summary == out.Topics[0].Summary {
+		t.Errorf("topic summary = %q, want it redacted in a copy", out.Topics[0].Summary)
+	}
+	var speakers []string
+	for _, l := range out.Lines {
+		speakers = append(speakers, l.Speaker)
+	}
+	if !slices.Equal(speakers, []string{"Speaker 1", "Speaker 2", "Speaker 1"}) {
+		t.Errorf("speakers = %v", speakers)
+	}
+	if out.Title != "Call with [redacted]" || out.Lines[0].Text != "my number is [redacted]" {
+		t.Errorf("title = %q, line = %q", out.Title, out.Lines[0].Text)
+	}
+	if rec.Redactions != 4 || rec.PseudonymizedSpeakers != 2 || rec.Redact != RedactPII {
+		t.Errorf("record = %+v", rec)
+	}
+	if out.SessionID != "sess-1" || out.StartedAt.IsZero() {
+		t.Error("gdpr-minimal keeps the session ID and times")
+	}
+}
+
+func TestAnonymizeFull(t *testing.T) {
+	p, _ := LookupPreset("full")
+	in := anonymizeFixture()
+	in.Notes = "[14:02:10] Call jane@example.com back\n[14:05:00] Ship it"
+	in.Summary = "Jane (jane@example.com) owns the launch."
+	out, rec, err := Anonymize(in, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.SessionID != "" || out.Title != "" || !out.StartedAt.IsZero() {
+		t.Errorf("identity not stripped: %+v", out)
+	}
+	for _, l := range out.Lines {
+		if !l.At.IsZero() {
+			t.Errorf("line %d kept its wall-clock time", l.Seq)
+		}
+	}
+	if out.Lines[2].Offset != 2*time.Minute {
+		t.Errorf("offset = %v", out.Lines[2].Offset)
+	}
+	if !slices.Contains(rec.Stripped, StripTimestamps) {
+		t.Errorf("record = %+v", rec)
+	}
+	if out.Notes != "Call [redacted] back\nShip it" {
+		t.Errorf("notes = %q, want redacted without their stamps", out.Notes)
+	}
+	if strings.Contains(out.Summary, "jane@example.com") {
+		t.Errorf("summary = %q, want redacted", out.Summary)
+	}
+
+	var b strings.Builder
+	if err := TranscriptMarkdown(&b, out); err != nil {
+		t.Fatal(err)
+	}
+	md := b.String()
+	for _, leak := range []string{"sess-1", "jane", "555-123", "zoom", "meet.example", "14:0"} {
+		if strings.Contains(md, leak) {
+			t.Errorf("full export leaks %q:\n%s", leak, md)
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jwulff/steno/internal/db"
+)
+
+// Chapter is one marker on a session's timeline. Start and End are
+// offsets from the start of the recording.
+type Chapter struct {
+	Start time.Duration
+	End   time.Duration
+	Title string
+}
+
+// Chapter sources: where boundaries come from.
+const (
+	ChaptersByTopic    = "topics"
+	ChaptersByInterval = "interval"
+)
+
+// ChapterSources lists the accepted chapter sources.
+var ChapterSources = []string{ChaptersByTopic, ChaptersByInterval}
+
+// Chapter output formats.
+const (
+	ChapterFormatSRT     = "srt"
+	ChapterFormatVTT     = "vtt"
+	ChapterFormatYouTube = "youtube"
+	ChapterFormatPodcast = "podcast"
+)
+
+// ChapterFormats lists the accepted output formats.
+var ChapterFormats = []string{ChapterFormatSRT, ChapterFormatVTT, ChapterFormatYouTube, ChapterFormatPodcast}
+
+// DefaultChapterInterval is the chapter length for ChaptersByInterval.
+const DefaultChapterInterval = 5 * time.Minute
+
+// MinChapterInterval is the shortest interval accepted. YouTube ignores
+// chapters shorter than ten seconds.
+const MinChapterInterval = 10 * time.Second
+
+// ChapterOptions selects how Chapters divides a session.
+type ChapterOptions struct {
+	// By is ChaptersByTopic (default) or ChaptersByInterval.
+	By string
+	// Interval is the chapter length for ChaptersByInterval. Zero means
+	// DefaultChapterInterval.
+	Interval time.Duration
+}
+
+// titleWords bounds interval chapter titles, which quote the chapter's
+// first segment.
+const titleWords = 8
+
+// Chapters divides a session's timeline into contiguous chapters. The
+// timeline starts at the first segment (YouTube requires a chapter at
+// 0:00) and ends with the last one. segments must be sorted by sequence
+// number, as SegmentsForSession returns them.
+//
+// ChaptersByTopic starts a chapter at each topic's first segment, titled
+// with the topic; the first chapter is stretched back to 0:00.
+// ChaptersByInterval cuts every opts.Interval, titled with the opening
+// words spoken in it.
+func Chapters(segments []db.Segment, topics []db.Topic, opts ChapterOptions) ([]Chapter, error) {
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("session has no segments")
+	}
+	origin, end := segments[0].StartedAt, segments[0].EndedAt
+	for _, s := range segments {
+		if s.StartedAt.Before(origin) {
+			origin = s.StartedAt
+		}
+		if s.EndedAt.After(end) {
+			end = s.EndedAt
+		}
+	}
+	total := end.Sub(origin)
+
+	var chapters []Chapter
+	switch opts.By {
+	case "", ChaptersByTopic:
+		chapters = topicChapters(segments, topics, origin)
+		if len(chapters) == 0 {
+			return nil, fmt.Errorf("session has no topics to chapter by; use %q", ChaptersByInterval)
+		}
+	case ChaptersByInterval:
+		interval := opts.Interval
+		if interval == 0 {
+			interval = DefaultChapterInterval
+		}
+		if interval < MinChapterInterval {
+			return nil, fmt.Errorf("chapter interval %v is shorter than %v", interval, MinChapterInterval)
+		}
+		chapters = intervalChapters(segments, origin, total, interval)
+	default:
+		return nil, fmt.Errorf("unknown chapter source %q: want one of %s", opts.By, strings.Join(ChapterSources, ", "))
+	}
+
+	// Contiguous: each chapter runs until the next begins.
+	for i := range chapters {
+		if i+1 < len(chapters) {
+			chapters[i].End = chapters[i+1].Start
+		} else {
+			chapters[i].End = max(total, chapters[i].Start)
+		}
+	}
+	return chapters, nil
+}
+
+func topicChapters(segments []db.Segment, topics []db.Topic, origin time.Time) []Chapter {
+	sorted := slices.Clone(topics)
+	slices.SortStableFunc(sorted, func(a, b db.Topic) int { return a.SegmentRangeStart - b.SegmentRangeStart })
+
+	var chapters []Chapter
+	for _, t := range sorted {
+		// The topic's first segment may have been dropped as a duplicate;
+		// start at the next surviving one.
+		i, _ := slices.BinarySearchFunc(segments, t.SegmentRangeStart, func(s db.Segment, seq int) int {
+			return s.SequenceNumber - seq
+		})
+		if i == len(segments) || segments[i].SequenceNumber > t.SegmentRangeEnd {
+			continue
+		}
+		start := segments[i].StartedAt.Sub(origin)
+		if len(chapters) == 0 {
+			start = 0
+		} else if start <= chapters[len(chapters)-1].Start {
+			continue // overlapping ranges: keep the earlier topic
+		}
+		chapters = append(chapters, Chapter{Start: start, Title: t.Title})
+	}
+	return chapters
+}
+
+func intervalChapters(segments []db.Segment, origin time.Time, total, interval time.Duration) []Chapter {
+	var chapters []Chapter
+	next := 0
+	for start := time.Duration(0); start == 0 || start < total; start += interval {
+		title := ""
+		for ; next < len(segments) && segments[next].StartedAt.Sub(origin) < start+interval; next++ {
+			if title == "" && segments[next].StartedAt.Sub(origin) >= start {
+				title = openingWords(segments[next].Text)
+			}
+		}
+		if title == "" {
+			title = fmt.Sprintf("Chapter %d", len(chapters)+1)
+		}
+		chapters = append(chapters, Chapter{Start: start, Title: title})
+	}
+	return chapters
+}
+
+// openingWords returns the first titleWords words of text, with an
+// ellipsis when there were more.
+func openingWords(text string) string {
+	words := strings.Fields(text)
+	if len(words) <= titleWords {
+		return strings.Join(words, " ")
+	}
+	return strings.Join(words[:titleWords], " ") + "…"
+}
+
+// WriteChapters writes chapters in format, one of ChapterFormats.
+func WriteChapters(w io.Writer, format string, chapters []Chapter) error {
+	var b strings.Builder
+	switch format {
+	case ChapterFormatSRT:
+		for i, c := range chapters {
+			fmt.Fprintf(&b, "%d\n%s --> %s\n%s\n\n", i+1, cueTime(c.Start, ","), cueTime(c.End, ","), oneLine(c.Title))
+		}
+	case ChapterFormatVTT:
+		// A WebVTT chapters track (<track kind="chapters">).
+		b.WriteString("WEBVTT\n\n")
+		for i, c := range chapters {
+			fmt.Fprintf(&b, "%d\n%s --> %s\n%s\n\n", i+1, cueTime(c.Start, "."), cueTime(c.End, "."), oneLine(c.Title))
+		}
+	case ChapterFormatYouTube:
+		// Paste into the video description. YouTube needs at least three
+		// chapters of ten seconds or more, the first at 0:00.
+		for _, c := range chapters {
+			fmt.Fprintf(&b, "%s %s\n", youTubeTime(c.Start), oneLine(c.Title))
+		}
+	case ChapterFormatPodcast:
+		return writePodcastChapters(w, chapters)
+	default:
+		return fmt.Errorf("unknown chapter format %q: want one of %s", format, strings.Join(ChapterFormats, ", "))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// podcastChapters is the Podcasting 2.0 chapters document
+// (https://github.com/Podcastindex-org/podcast-namespace, chapters.md).
+type podcastChapters struct {
+	Version  string           `json:"version"`
+	Chapters []podcastChapter `json:"chapters"`
+}
+
+type podcastChapter struct {
+	StartTime float64 `json:"startTime"`
+	EndTime   float64 `json:"endTime,omitempty"`
+	Title     string  `json:"title"`
+}
+
+func writePodcastChapters(w io.Writer, chapters []Chapter) error {
+	doc := podcastChapters{Version: "1.2.0", Chapters: make([]podcastChapter, 0, len(chapters))}
+	for _, c := range chapters {
+		doc.Chapters = append(doc.Chapters, podcastChapter{
+			StartTime: c.Start.Seconds(),
+			EndTime:   c.End.Seconds(),
+			Title:     oneLine(c.Title),
+		})
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
+
+// cueTime formats d as HH:MM:SS<sep>mmm for SRT (",") and WebVTT (".").
+func cueTime(d time.Duration, sep string) string {
+	ms := d.Milliseconds()
+	return fmt.Sprintf("%02d:%02d:%02d%s%03d", ms/3600000, ms/60000%60, ms/1000%60, sep, ms%1000)
+}
+
+// youTubeTime formats d as M:SS, or H:MM:SS from an hour on.
+func youTubeTime(d time.Duration) string {
+	s := int(d.Seconds())
+	if s >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", s/3600, s/60%60, s%60)
+	}
+	return fmt.Sprintf("%d:%02d", s/60, s%60)
+}
+
+// oneLine collapses whitespace so a title can't break a cue.
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
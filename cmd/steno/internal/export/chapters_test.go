@@ -0,0 +1,128 @@
+package export
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jwulff/steno/internal/db"
+)
+
+func chapterFixture() ([]db.Segment, []db.Topic) {
+	at := time.Unix(1710000000, 0)
+	seg := func(seq int, offset, length time.Duration, text string) db.Segment {
+		return db.Segment{SequenceNumber: seq, StartedAt: at.Add(offset), EndedAt: at.Add(offset + length), Text: text}
+	}
+	segments := []db.Segment{
+		seg(1, 0, 10*time.Second, "Good morning everyone, let's get started with the quarterly review"),
+		seg(2, 4*time.Minute, 20*time.Second, "Revenue is up"),
+		seg(4, 7*time.Minute, 30*time.Second, "Hiring plans next"),
+		seg(5, 61*time.Minute, 5*time.Second, "Thanks all"),
+	}
+	topics := []db.Topic{
+		{Title: "Hiring", SegmentRangeStart: 3, SegmentRangeEnd: 5},
+		{Title: "Opening\nremarks", SegmentRangeStart: 1, SegmentRangeEnd: 2},
+	}
+	return segments, topics
+}
+
+func TestChaptersByTopic(t *testing.T) {
+	segments, topics := chapterFixture()
+	chapters, err := Chapters(segments, topics, ChapterOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Sorted by range; Hiring's first segment (#3) is gone, so it opens
+	// at #4.
+	want := []Chapter{
+		{Start: 0, End: 7 * time.Minute, Title: "Opening\nremarks"},
+		{Start: 7 * time.Minute, End: 61*time.Minute + 5*time.Second, Title: "Hiring"},
+	}
+	if len(chapters) != len(want) {
+		t.Fatalf("chapters = %+v", chapters)
+	}
+	for i := range want {
+		if chapters[i] != want[i] {
+			t.Errorf("chapter %d = %+v, want %+v", i, chapters[i], want[i])
+		}
+	}
+
+	if _, err := Chapters(segments, nil, ChapterOptions{By: ChaptersByTopic}); err == nil {
+		t.Error("topic chapters without topics should fail")
+	}
+}
+
+func TestChaptersByInterval(t *testing.T) {
+	segments, _ := chapterFixture()
+	chapters, err := Chapters(segments, nil, ChapterOptions{By: ChaptersByInterval, Interval: 30 * time.Minute})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chapters) != 3 {
+		t.Fatalf("chapters = %+v, want 3", chapters)
+	}
+	if chapters[0].Title != "Good morning everyone, let's get started with the…" {
+		t.Errorf("first title = %q", chapters[0].Title)
+	}
+	if chapters[1].Title != "Chapter 2" || chapters[1].Start != 30*time.Minute {
+		t.Errorf("silent chapter = %+v", chapters[1])
+	}
+	if chapters[2].Title != "Thanks all" || chapters[2].End != 61*time.Minute+5*time.Second {
+		t.Errorf("last chapter = %+v", chapters[2])
+	}
+
+	if _, err := Chapters(segments, nil, ChapterOptions{By: ChaptersByInterval, Interval: time.Second}); err == nil {
+		t.Error("an interval under MinChapterInterval should fail")
+	}
+	if _, err := Chapters(segments, nil, ChapterOptions{By: "speaker"}); err == nil {
+		t.Error("an unknown source should fail")
+	}
+	if _, err := Chapters(nil, nil, ChapterOptions{By: ChaptersByInterval}); err == nil {
+		t.Error("a session without segments should fail")
+	}
+}
+
+func TestWriteChapters(t *testing.T) {
+	chapters := []Chapter{
+		{Start: 0, End: 90*time.Second + 500*time.Millisecond, Title: "Opening\nremarks"},
+		{Start: 90*time.Second + 500*time.Millisecond, End: 62 * time.Minute, Title: "Hiring"},
+	}
+	for format, wants := range map[string][]string{
+		ChapterFormatSRT:     {"1\n00:00:00,000 --> 00:01:30,500\nOpening remarks\n\n", "2\n00:01:30,500 --> 01:02:00,000\nHiring\n"},
+		ChapterFormatVTT:     {"WEBVTT\n\n1\n00:00:00.000 --> 00:01:30.500\nOpening remarks\n"},
+		ChapterFormatYouTube: {"0:00 Opening remarks\n1:30 Hiring\n"},
+	} {
+		var b strings.Builder
+		if err := WriteChapters(&b, format, chapters); err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+		for _, want := range wants {
+			if !strings.Contains(b.String(), want) {
+				t.Errorf("%s output missing %q:\n%s", format, want, b.String())
+			}
+		}
+	}
+
+	var b strings.Builder
+	if err := WriteChapters(&b, ChapterFormatPodcast, chapters); err != nil {
+		t.Fatal(err)
+	}
+	var doc podcastChapters
+	if err := json.Unmarshal([]byte(b.String()), &doc); err != nil {
+		t.Fatalf("podcast JSON: %v\n%s", err, b.String())
+	}
+	if doc.Version != "1.2.0" || len(doc.Chapters) != 2 || doc.Chapters[1].StartTime != 90.5 || doc.Chapters[0].Title != "Opening remarks" {
+		t.Errorf("podcast chapters = %+v", doc)
+	}
+
+	if err := WriteChapters(&b, "mp4", chapters); err == nil {
+		t.Error("an unknown format should fail")
+	}
+}
+
+func TestYouTubeTimeFromAnHour(t *testing.T) {
+	if got := youTubeTime(time.Hour + 2*time.Minute + 3*time.Second); got != "1:02:03" {
+		t.Errorf("youTubeTime = %q", got)
+	}
+}
@@ -0,0 +1,71 @@
+package export
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/jwulff/steno/internal/db"
+)
+
+// CleanBatch is how many segments one call to a Cleaner rewrites.
+const CleanBatch = 20
+
+// Cleaner rewrites transcript text as clean verbatim: filler words and
+// false starts dropped, casing and punctuation fixed, the meaning kept.
+// It returns one rewrite per text, in order.
+type Cleaner func(texts []string) ([]string, error)
+
+// CleanVerbatim returns t with each line's text replaced by its clean
+// verbatim rewrite, alongside the rewrites it had to make. cached holds
+// earlier rewrites by sequence number; one is reused while its source
+// still matches the line, so re-exporting a session only sends the
+// segments changed since. Lines go to clean CleanBatch at a time; on an
+// error, the rewrites made before it are still returned, to be kept.
+func CleanVerbatim(t Transcript, cached map[int]db.SegmentCleanup, clean Cleaner) (Transcript, []db.SegmentCleanup, error) {
+	out := t
+	out.Lines = slices.Clone(t.Lines)
+	var pending []int // indexes into out.Lines
+	for i, l := range out.Lines {
+		if c, ok := cached[l.Seq]; ok && c.Source == l.Text {
+			out.Lines[i].Text = c.Text
+		} else if strings.TrimSpace(l.Text) != "" {
+			pending = append(pending, i)
+		}
+	}
+
+	var fresh []db.SegmentCleanup
+	for start := 0; start < len(pending); start += CleanBatch {
+		batch := pending[start:min(start+CleanBatch, len(pending))]
+		texts := make([]string, len(batch))
+		for k, i := range batch {
+			texts[k] = out.Lines[i].Text
+		}
+		cleaned, err := clean(texts)
+		if err != nil {
+			return Transcript{}, fresh, fmt.Errorf("clean verbatim: %w", err)
+		}
+		if len(cleaned) != len(texts) {
+			return Transcript{}, fresh, fmt.Errorf("clean verbatim: got %d rewrites for %d segments", len(cleaned), len(texts))
+		}
+		for k, i := range batch {
+			text := strings.TrimSpace(cleaned[k])
+			if text == "" {
+				// A rewrite that drops the whole segment loses what was
+				// said; keep the verbatim text instead.
+				text = texts[k]
+			}
+			fresh = append(fresh, db.SegmentCleanup{Seq: out.Lines[i].Seq, Source: texts[k], Text: text})
+			out.Lines[i].Text = text
+		}
+	}
+	return out, fresh, nil
+}
+
+// CleanPath is where the clean verbatim document of an export to path
+// is written: beside it, with ".clean" ahead of the extension.
+func CleanPath(path string) string {
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + ".clean" + ext
+}
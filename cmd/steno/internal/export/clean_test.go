@@ -0,0 +1,76 @@
+package export
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jwulff/steno/internal/db"
+)
+
+func TestCleanVerbatim(t *testing.T) {
+	tr := Transcript{Lines: []TranscriptLine{
+		{Seq: 1, Text: "um so we uh ship friday"},
+		{Seq: 2, Text: "yeah"},
+		{Seq: 3, Text: "like you know the the budget"},
+	}}
+	cached := map[int]db.SegmentCleanup{
+		1: {Seq: 1, Source: "um so we uh ship friday", Text: "So we ship Friday."},
+		3: {Seq: 3, Source: "the budget", Text: "The budget."}, // stale: the segment changed since
+	}
+	var calls [][]string
+	clean := func(texts []string) ([]string, error) {
+		calls = append(calls, texts)
+		out := make([]string, len(texts))
+		for i, s := range texts {
+			out[i] = strings.ToUpper(s)
+		}
+		out[0] = " " // dropping a segment keeps it verbatim
+		return out, nil
+	}
+
+	got, fresh, err := CleanVerbatim(tr, cached, clean)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(calls) != 1 || strings.Join(calls[0], "|") != "yeah|like you know the the budget" {
+		t.Errorf("cleaner got %q; want only the uncached and stale lines", calls)
+	}
+	if texts := []string{got.Lines[0].Text, got.Lines[1].Text, got.Lines[2].Text}; strings.Join(texts, "|") != "So we ship Friday.|yeah|LIKE YOU KNOW THE THE BUDGET" {
+		t.Errorf("cleaned lines = %q", texts)
+	}
+	if len(fresh) != 2 || fresh[1] != (db.SegmentCleanup{Seq: 3, Source: "like you know the the budget", Text: "LIKE YOU KNOW THE THE BUDGET"}) {
+		t.Errorf("fresh rewrites = %+v", fresh)
+	}
+	if tr.Lines[0].Text != "um so we uh ship friday" {
+		t.Error("CleanVerbatim must not rewrite the verbatim transcript in place")
+	}
+
+	// Lines go in batches, and a failure keeps the batches already done.
+	tr.Lines = nil
+	for i := range CleanBatch + 5 {
+		tr.Lines = append(tr.Lines, TranscriptLine{Seq: i + 1, Text: "uh"})
+	}
+	calls = nil
+	_, fresh, err = CleanVerbatim(tr, nil, func(texts []string) ([]string, error) {
+		if calls = append(calls, texts); len(calls) == 2 {
+			return nil, errors.New("model busy")
+		}
+		return texts, nil
+	})
+	if err == nil || !strings.Contains(err.Error(), "model busy") || len(fresh) != CleanBatch {
+		t.Errorf("err = %v, %d rewrites kept; want the failure and the first batch", err, len(fresh))
+	}
+}
+
+func TestCleanPath(t *testing.T) {
+	for in, want := range map[string]string{
+		"out/standup.md": "out/standup.clean.md",
+		"standup":        "standup.clean",
+		"a.b/c.srt":      "a.b/c.clean.srt",
+	} {
+		if got := CleanPath(in); got != want {
+			t.Errorf("CleanPath(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/jwulff/steno/internal/coding"
+	"github.com/jwulff/steno/internal/db"
+	"github.com/jwulff/steno/internal/entities"
+)
+
+// csvTime is the CSV timestamp layout, in UTC. Spreadsheets parse it as a
+// date-time, which they don't for RFC 3339's "T" and "Z".
+const csvTime = "2006-01-02 15:04:05.000"
+
+// utf8BOM lets Excel detect UTF-8; without it, non-ASCII text opens as
+// mojibake.
+const utf8BOM = "\ufeff"
+
+// SegmentsCSVHeader is the header row of SegmentsCSV.
+var SegmentsCSVHeader = []string{"session_id", "seq", "start", "end", "source", "speaker", "confidence", "text"}
+
+// TopicsCSVHeader is the header row of TopicsCSV.
+var TopicsCSVHeader = []string{"session_id", "topic_id", "title", "summary", "segment_start", "segment_end", "user_edited", "created_at"}
+
+// SegmentsCSV writes segs, e.g. from several sessions, one row each.
+// Confidence is blank when the daemon didn't record one. With bom, the
+// output starts with a UTF-8 byte-order mark for Excel.
+func SegmentsCSV(w io.Writer, segs []db.Segment, bom bool) error {
+	rows := make([][]string, 0, len(segs))
+	for _, s := range segs {
+		var confidence string
+		if s.Confidence != nil {
+			confidence = strconv.FormatFloat(*s.Confidence, 'f', -1, 64)
+		}
+		rows = append(rows, []string{
+			s.SessionID,
+			strconv.Itoa(s.SequenceNumber),
+			csvTimestamp(s.StartedAt),
+			csvTimestamp(s.EndedAt),
+			s.Source,
+			sourceLabel(s.Source),
+			confidence,
+			s.Text,
+		})
+	}
+	return writeCSV(w, SegmentsCSVHeader, rows, bom)
+}
+
+// TopicsCSV writes topics one row each, with user edits already applied
+// as TopicsForSession returns them.
+func TopicsCSV(w io.Writer, topics []db.Topic, bom bool) error {
+	rows := make([][]string, 0, len(topics))
+	for _, t := range topics {
+		rows = append(rows, []string{
+			t.SessionID,
+			t.ID,
+			t.Title,
+			t.Summary,
+			strconv.Itoa(t.SegmentRangeStart),
+			strconv.Itoa(t.SegmentRangeEnd),
+			strconv.FormatBool(t.UserEdited),
+			csvTimestamp(t.CreatedAt),
+		})
+	}
+	return writeCSV(w, TopicsCSVHeader, rows, bom)
+}
+
+// EntitiesCSVHeader is the header row of EntitiesCSV.
+var EntitiesCSVHeader = []string{"session_id", "kind", "text", "count", "first_seq", "first_at", "permalink"}
+
+// EntitiesCSV writes a session's entities one row each, with a permalink
+// to the segment each was first mentioned in.
+func EntitiesCSV(w io.Writer, sessionID string, ents []entities.Entity, bom bool) error {
+	rows := make([][]string, 0, len(ents))
+	for _, e := range ents {
+		rows = append(rows, []string{
+			sessionID,
+			string(e.Kind),
+			e.Text,
+			strconv.Itoa(e.Count),
+			strconv.Itoa(e.FirstSeq),
+			csvTimestamp(e.FirstAt),
+			SegmentPermalink(sessionID, e.FirstSeq),
+		})
+	}
+	return writeCSV(w, EntitiesCSVHeader, rows, bom)
+}
+
+// CodesCSVHeader is the header row of CodesCSV.
+var CodesCSVHeader = []string{"session_id", "interval_start", "offset_seconds", "code", "count", "running_total"}
+
+// CodesCSV writes a codebook series in long form, one row per interval
+// and code, with each code's running total, so a spreadsheet or R can
+// pivot or plot it directly.
+func CodesCSV(w io.Writer, sessionID string, s coding.Series, bom bool) error {
+	rows := make([][]string, 0, len(s.Counts)*len(s.Codes))
+	totals := make([]int, len(s.Codes))
+	for i, counts := range s.Counts {
+		offset := time.Duration(i) * s.Interval
+		for c, n := range counts {
+			totals[c] += n
+			rows = append(rows, []string{
+				sessionID,
+				csvTimestamp(s.Start.Add(offset)),
+				strconv.Itoa(int(offset.Seconds())),
+				s.Codes[c],
+				strconv.Itoa(n),
+				strconv.Itoa(totals[c]),
+			})
+		}
+	}
+	return writeCSV(w, CodesCSVHeader, rows, bom)
+}
+
+func writeCSV(w io.Writer, header []string, rows [][]string, bom bool) error {
+	if bom {
+		if _, err := io.WriteString(w, utf8BOM); err != nil {
+			return err
+		}
+	}
+	cw := csv.NewWriter(w)
+	// Excel and Numbers both expect CRLF, and RFC 4180 specifies it.
+	cw.UseCRLF = true
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	if err := cw.WriteAll(rows); err != nil {
+		return err
+	}
+	return cw.Error()
+}
+
+func csvTimestamp(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(csvTime)
+}
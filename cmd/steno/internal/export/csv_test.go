@@ -0,0 +1,104 @@
+package export
+
+import (
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jwulff/steno/internal/coding"
+	"github.com/jwulff/steno/internal/db"
+	"github.com/jwulff/steno/internal/entities"
+)
+
+func TestSegmentsCSV(t *testing.T) {
+	at := time.Date(2026, 3, 10, 14, 0, 0, 0, time.UTC)
+	conf := 0.875
+	segs := []db.Segment{
+		{SessionID: "s1", SequenceNumber: 1, Text: `she said "ship it", then left`, StartedAt: at, EndedAt: at.Add(1500 * time.Millisecond), Confidence: &conf, Source: "microphone"},
+		{SessionID: "s2", SequenceNumber: 7, Text: "line one\nline two", StartedAt: at.Add(time.Hour), EndedAt: at.Add(time.Hour + time.Second), Source: "systemAudio"},
+	}
+
+	var b strings.Builder
+	if err := SegmentsCSV(&b, segs, false); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(strings.SplitN(b.String(), "\n", 2)[0], "\r") {
+		t.Error("rows should end in CRLF")
+	}
+	rows, err := csv.NewReader(strings.NewReader(b.String())).ReadAll()
+	if err != nil {
+		t.Fatalf("parse: %v\n%s", err, b.String())
+	}
+	want := [][]string{
+		SegmentsCSVHeader,
+		{"s1", "1", "2026-03-10 14:00:00.000", "2026-03-10 14:00:01.500", "microphone", "mic", "0.875", `she said "ship it", then left`},
+		{"s2", "7", "2026-03-10 15:00:00.000", "2026-03-10 15:00:01.000", "systemAudio", "system audio", "", "line one\nline two"},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("rows = %q", rows)
+	}
+	for i := range want {
+		if strings.Join(rows[i], "|") != strings.Join(want[i], "|") {
+			t.Errorf("row %d = %q, want %q", i, rows[i], want[i])
+		}
+	}
+}
+
+func TestTopicsCSVBOM(t *testing.T) {
+	topics := []db.Topic{{ID: "t1", SessionID: "s1", Title: "Café budget", Summary: "Q3, again.", SegmentRangeStart: 1, SegmentRangeEnd: 4, UserEdited: true}}
+
+	var b strings.Builder
+	if err := TopicsCSV(&b, topics, true); err != nil {
+		t.Fatal(err)
+	}
+	out, ok := strings.CutPrefix(b.String(), "\ufeff")
+	if !ok {
+		t.Fatalf("missing BOM: %q", b.String())
+	}
+	rows, err := csv.NewReader(strings.NewReader(out)).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rows[0][0] != "session_id" || strings.Join(rows[1], "|") != "s1|t1|Café budget|Q3, again.|1|4|true|" {
+		t.Errorf("rows = %q", rows)
+	}
+}
+
+func TestEntitiesCSV(t *testing.T) {
+	at := time.Date(2026, 3, 10, 14, 0, 0, 0, time.UTC)
+	ents := []entities.Entity{{Kind: entities.Amount, Text: "$1,200", Count: 2, FirstSeq: 3, FirstAt: at}}
+
+	var b strings.Builder
+	if err := EntitiesCSV(&b, "s1", ents, false); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(strings.NewReader(b.String())).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"s1", "amount", "$1,200", "2", "3", "2026-03-10 14:00:00.000", "steno://session/s1#seg-3"}
+	if len(rows) != 2 || strings.Join(rows[1], "|") != strings.Join(want, "|") {
+		t.Errorf("rows = %q, want the header and %q", rows, want)
+	}
+}
+
+func TestCodesCSV(t *testing.T) {
+	at := time.Date(2026, 3, 10, 14, 0, 0, 0, time.UTC)
+	s := coding.Series{Codes: []string{"trust", "cost"}, Start: at, Interval: time.Minute, Counts: [][]int{{2, 0}, {1, 3}}}
+
+	var b strings.Builder
+	if err := CodesCSV(&b, "s1", s, false); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(strings.NewReader(b.String())).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 5 || strings.Join(rows[0], "|") != strings.Join(CodesCSVHeader, "|") {
+		t.Fatalf("rows = %q, want the header and a row per interval and code", rows)
+	}
+	if got := strings.Join(rows[3], "|"); got != "s1|2026-03-10 14:01:00.000|60|trust|1|3" {
+		t.Errorf("row 3 = %q, want trust's running total", got)
+	}
+}
@@ -0,0 +1,74 @@
+// Package export renders steno data into shareable documents.
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jwulff/steno/internal/db"
+)
+
+// SegmentPermalink is the stable link to one segment:
+//
+//	steno://session/<session-id>#seg-<seq>
+//
+// The fragment matches the segment anchors in `steno serve --web`, so
+// swapping the scheme and host for the viewer's address opens the same
+// line in a browser.
+func SegmentPermalink(sessionID string, seq int) string {
+	return fmt.Sprintf("steno://session/%s#seg-%d", sessionID, seq)
+}
+
+// AnnotationsMarkdown writes anns as review-style comments: each one
+// quotes the segment it is attached to, links back to it, and carries the
+// note or reaction underneath, ready to paste into a doc review.
+func AnnotationsMarkdown(w io.Writer, sess db.Session, anns []db.Annotation) error {
+	var b strings.Builder
+	title := sess.Title
+	if title == "" {
+		title = "Untitled session"
+	}
+	fmt.Fprintf(&b, "# Annotations: %s\n\n", title)
+	fmt.Fprintf(&b, "Session `%s`, %s. %d annotation%s.\n",
+		sess.ID, sess.StartedAt.Local().Format("2006-01-02 15:04"), len(anns), plural(len(anns)))
+
+	for _, a := range anns {
+		link := SegmentPermalink(a.SessionID, a.SegmentSeq)
+		fmt.Fprintf(&b, "\n---\n\n**%s** on [#%d](%s) at %s (%s)\n\n",
+			kindLabel(a), a.SegmentSeq, link, a.SegmentStartedAt.Local().Format("15:04:05"), sourceLabel(a.SegmentSource))
+		for _, line := range strings.Split(a.SegmentText, "\n") {
+			fmt.Fprintf(&b, "> %s\n", line)
+		}
+		if a.Kind == db.AnnotationNote {
+			fmt.Fprintf(&b, "\n%s\n", a.Body)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func kindLabel(a db.Annotation) string {
+	switch a.Kind {
+	case db.AnnotationNote:
+		return "Note"
+	case db.AnnotationReaction:
+		return "Reaction " + a.Body
+	default:
+		return "Bookmark"
+	}
+}
+
+func sourceLabel(source string) string {
+	if source == "systemAudio" {
+		return "system audio"
+	}
+	return "mic"
+}
+
+func plural(n int) string {
+	if n == 1 {
+		return ""
+	}
+	return "s"
+}
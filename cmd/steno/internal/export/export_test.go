@@ -0,0 +1,164 @@
+package export
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jwulff/steno/internal/db"
+)
+
+func TestSegmentPermalink(t *testing.T) {
+	if got := SegmentPermalink("sess-1", 12); got != "steno://session/sess-1#seg-12" {
+		t.Errorf("SegmentPermalink = %q", got)
+	}
+}
+
+func TestAnnotationsMarkdown(t *testing.T) {
+	at := time.Unix(1710000000, 0)
+	sess := db.Session{ID: "sess-1", Title: "Design Review", StartedAt: at}
+	anns := []db.Annotation{
+		{SessionID: "sess-1", Kind: db.AnnotationBookmark, SegmentSeq: 1, SegmentText: "let's ship Friday", SegmentStartedAt: at},
+		{SessionID: "sess-1", Kind: db.AnnotationNote, Body: "Friday is a holiday", SegmentSeq: 4, SegmentText: "deadline is fixed", SegmentStartedAt: at, SegmentSource: "systemAudio"},
+		{SessionID: "sess-1", Kind: db.AnnotationReaction, Body: "👍", SegmentSeq: 5, SegmentText: "agreed", SegmentStartedAt: at},
+	}
+	var b strings.Builder
+	if err := AnnotationsMarkdown(&b, sess, anns); err != nil {
+		t.Fatal(err)
+	}
+	md := b.String()
+	for _, want := range []string{
+		"# Annotations: Design Review",
+		"3 annotations.",
+		"**Bookmark** on [#1](steno://session/sess-1#seg-1)",
+		"> deadline is fixed\n\nFriday is a holiday\n",
+		"**Reaction 👍** on [#5]",
+		"(system audio)",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q:\n%s", want, md)
+		}
+	}
+}
+
+func TestTranscriptMarkdown(t *testing.T) {
+	at := time.Date(2026, 3, 10, 14, 0, 0, 0, time.Local)
+	sess := db.Session{ID: "sess-1", Title: "Design Review", StartedAt: at, Device: "MacBook Pro Microphone", MeetingURL: "https://meet.example.com/abc"}
+	segments := []db.Segment{
+		{SequenceNumber: 1, Text: "let's ship\nFriday", StartedAt: at, Source: "microphone"},
+		{SequenceNumber: 2, Text: "agreed", StartedAt: at.Add(75 * time.Second), Source: "systemAudio"},
+	}
+	tr := NewTranscript(sess, segments)
+	if tr.Lines[1].Offset != 75*time.Second || tr.Lines[1].Speaker != "system audio" {
+		t.Errorf("line = %+v", tr.Lines[1])
+	}
+
+	var b strings.Builder
+	if err := TranscriptMarkdown(&b, tr); err != nil {
+		t.Fatal(err)
+	}
+	md := b.String()
+	for _, want := range []string{
+		"# Design Review\n",
+		"Session `sess-1`, 2026-03-10 14:00, recorded on MacBook Pro Microphone.",
+		"Meeting: https://meet.example.com/abc",
+		"**[14:00:00] mic:** let's ship Friday\n",
+		"**[14:01:15] system audio:** agreed\n",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q:\n%s", want, md)
+		}
+	}
+	if strings.Contains(md, "## Decisions") {
+		t.Errorf("decisions section without decisions:\n%s", md)
+	}
+
+	// Decisions lead the transcript.
+	tr.Decisions = []int{2}
+	b.Reset()
+	if err := TranscriptMarkdown(&b, tr); err != nil {
+		t.Fatal(err)
+	}
+	if md := b.String(); !strings.Contains(md, "## Decisions\n\n- **[14:01:15] system audio:** agreed\n\n## Transcript\n\n**[14:00:00] mic:**") {
+		t.Errorf("decisions markdown:\n%s", md)
+	}
+
+	// Notes come first, and the transcript is still set apart.
+	tr.Notes = "[14:00:30] Check the *release* date\n"
+	b.Reset()
+	if err := TranscriptMarkdown(&b, tr); err != nil {
+		t.Fatal(err)
+	}
+	if md := b.String(); !strings.Contains(md, "## Notes\n\n[14:00:30] Check the *release* date\n\n## Decisions\n") {
+		t.Errorf("notes markdown:\n%s", md)
+	}
+	tr.Decisions = nil
+	b.Reset()
+	if err := TranscriptMarkdown(&b, tr); err != nil {
+		t.Fatal(err)
+	}
+	if md := b.String(); !strings.Contains(md, "release* date\n\n## Transcript\n\n**[14:00:00] mic:**") {
+		t.Errorf("notes without decisions:\n%s", md)
+	}
+	tr.Notes = ""
+
+	// The summary leads, ahead of the notes.
+	tr.Summary = "Agreed to ship on Friday.\n"
+	tr.Notes = "[14:00:30] Check the date\n"
+	b.Reset()
+	if err := TranscriptMarkdown(&b, tr); err != nil {
+		t.Fatal(err)
+	}
+	if md := b.String(); !strings.Contains(md, "## Summary\n\nAgreed to ship on Friday.\n\n## Notes\n") {
+		t.Errorf("summary markdown:\n%s", md)
+	}
+	tr.Summary, tr.Notes = "", ""
+
+	// Without wall-clock times, lines fall back to offsets.
+	tr.StartedAt = time.Time{}
+	tr.Lines[1].At = time.Time{}
+	b.Reset()
+	if err := TranscriptMarkdown(&b, tr); err != nil {
+		t.Fatal(err)
+	}
+	if md := b.String(); !strings.Contains(md, "**[00:01:15] system audio:**") || strings.Contains(md, "2026-03-10") {
+		t.Errorf("offset markdown:\n%s", md)
+	}
+}
+
+func TestTranscriptMarkdownTopicSections(t *testing.T) {
+	at := time.Date(2026, 3, 10, 14, 0, 0, 0, time.Local)
+	var segments []db.Segment
+	for seq := 1; seq <= 5; seq++ {
+		segments = append(segments, db.Segment{SequenceNumber: seq, Text: fmt.Sprintf("line %d", seq), StartedAt: at.Add(time.Duration(seq) * time.Second), Source: "microphone"})
+	}
+	tr := NewTranscript(db.Session{ID: "sess-1", Title: "Planning"}, segments[1:]) // seq 1 dropped as a duplicate
+	tr.Topics = NewTranscriptTopics([]db.Topic{
+		{Title: "Launch", Summary: "Ship on Friday.", SegmentRangeStart: 4, SegmentRangeEnd: 5},
+		{Title: "Budget", SegmentRangeStart: 1, SegmentRangeEnd: 3},
+		{Title: "Gone", SegmentRangeStart: 9, SegmentRangeEnd: 9},
+	})
+
+	var b strings.Builder
+	if err := TranscriptMarkdown(&b, tr); err != nil {
+		t.Fatal(err)
+	}
+	md := b.String()
+	want := "## Budget\n\n**[14:00:02] mic:** line 2\n\n**[14:00:03] mic:** line 3\n\n" +
+		"## Launch\n\nShip on Friday.\n\n**[14:00:04] mic:** line 4\n\n"
+	if !strings.Contains(md, want) || strings.Contains(md, "Gone") {
+		t.Errorf("sectioned markdown:\n%s", md)
+	}
+
+	// With decisions, the first topic's heading takes the place of
+	// "## Transcript".
+	tr.Decisions = []int{4}
+	b.Reset()
+	if err := TranscriptMarkdown(&b, tr); err != nil {
+		t.Fatal(err)
+	}
+	if md := b.String(); strings.Contains(md, "## Transcript") || !strings.Contains(md, "line 4\n\n## Budget\n\n") {
+		t.Errorf("sectioned markdown with decisions:\n%s", md)
+	}
+}
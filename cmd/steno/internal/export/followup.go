@@ -0,0 +1,144 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/jwulff/steno/internal/db"
+)
+
+// Follow-up kinds.
+const (
+	// FollowUpEvent is a calendar event (VEVENT).
+	FollowUpEvent = "event"
+	// FollowUpTodo is a to-do (VTODO), for Reminders and task apps.
+	FollowUpTodo = "todo"
+)
+
+// FollowUpKinds lists the accepted follow-up kinds.
+var FollowUpKinds = []string{FollowUpEvent, FollowUpTodo}
+
+// DefaultFollowUpDuration is how long a follow-up event is booked for.
+const DefaultFollowUpDuration = 30 * time.Minute
+
+// FollowUp is a calendar event or to-do following up on a topic.
+type FollowUp struct {
+	Kind string
+	// UID identifies the follow-up, so importing it again updates the
+	// entry instead of adding a second one.
+	UID       string
+	Title     string
+	Summary   string
+	Permalink string
+	// Start is when the event starts, or when the to-do is due.
+	Start    time.Time
+	Duration time.Duration
+}
+
+// NewFollowUp fills a follow-up for a session's topic, linked to the
+// topic's first segment.
+func NewFollowUp(kind string, topic db.Topic, start time.Time, duration time.Duration) FollowUp {
+	return FollowUp{
+		Kind:      kind,
+		UID:       "steno-followup-" + topic.ID,
+		Title:     "Follow up: " + oneLine(topic.Title),
+		Summary:   strings.TrimSpace(topic.Summary),
+		Permalink: SegmentPermalink(topic.SessionID, topic.SegmentRangeStart),
+		Start:     start,
+		Duration:  duration,
+	}
+}
+
+// NextFollowUpSlot is the default follow-up time: 9:00 on the next
+// weekday after now, in now's location.
+func NextFollowUpSlot(now time.Time) time.Time {
+	day := time.Date(now.Year(), now.Month(), now.Day()+1, 9, 0, 0, 0, now.Location())
+	for day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+		day = day.AddDate(0, 0, 1)
+	}
+	return day
+}
+
+// FollowUpICS writes f as an iCalendar (RFC 5545) file that Calendar and
+// most task apps import on open. The description carries the topic's
+// summary and its permalink, which is also the entry's URL. now stamps
+// the entry.
+func FollowUpICS(w io.Writer, f FollowUp, now time.Time) error {
+	duration := f.Duration
+	if duration <= 0 {
+		duration = DefaultFollowUpDuration
+	}
+	description := f.Summary
+	if f.Permalink != "" {
+		if description != "" {
+			description += "\n\n"
+		}
+		description += f.Permalink
+	}
+
+	lines := []string{"BEGIN:VCALENDAR", "VERSION:2.0", "PRODID:-//steno//follow-up//EN", "CALSCALE:GREGORIAN"}
+	var component string
+	switch f.Kind {
+	case FollowUpEvent, "":
+		component = "VEVENT"
+	case FollowUpTodo:
+		component = "VTODO"
+	default:
+		return fmt.Errorf("unknown follow-up kind %q: want one of %s", f.Kind, strings.Join(FollowUpKinds, ", "))
+	}
+	lines = append(lines,
+		"BEGIN:"+component,
+		"UID:"+icsText(f.UID),
+		"DTSTAMP:"+icsTime(now),
+		"SUMMARY:"+icsText(f.Title),
+	)
+	if component == "VEVENT" {
+		lines = append(lines, "DTSTART:"+icsTime(f.Start), "DTEND:"+icsTime(f.Start.Add(duration)))
+	} else {
+		lines = append(lines, "DUE:"+icsTime(f.Start), "STATUS:NEEDS-ACTION")
+	}
+	if description != "" {
+		lines = append(lines, "DESCRIPTION:"+icsText(description))
+	}
+	if f.Permalink != "" {
+		lines = append(lines, "URL:"+f.Permalink)
+	}
+	lines = append(lines, "END:"+component, "END:VCALENDAR")
+
+	var b strings.Builder
+	for _, l := range lines {
+		b.WriteString(icsFold(l))
+		b.WriteString("\r\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// icsTime formats t as a UTC date-time.
+func icsTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// icsText escapes a TEXT value: backslashes, separators and newlines.
+func icsText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+// icsFold folds a content line at 75 octets, continuing on lines that
+// start with a space, without splitting a UTF-8 character.
+func icsFold(line string) string {
+	var b strings.Builder
+	width := 0
+	for _, r := range line {
+		n := len(string(r))
+		if width+n > 75 {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += n
+	}
+	return b.String()
+}
@@ -0,0 +1,63 @@
+package export
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jwulff/steno/internal/db"
+)
+
+func TestFollowUpICS(t *testing.T) {
+	topic := db.Topic{ID: "top-1", SessionID: "sess-1", Title: "Launch,  date", Summary: "Move launch; tell sales.\nCheck budget.", SegmentRangeStart: 12}
+	start := time.Date(2026, 3, 11, 9, 0, 0, 0, time.UTC)
+	now := time.Date(2026, 3, 10, 17, 30, 0, 0, time.UTC)
+
+	var b strings.Builder
+	if err := FollowUpICS(&b, NewFollowUp(FollowUpEvent, topic, start, 0), now); err != nil {
+		t.Fatal(err)
+	}
+	got := b.String()
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\nVERSION:2.0\r\n",
+		"BEGIN:VEVENT\r\nUID:steno-followup-top-1\r\nDTSTAMP:20260310T173000Z\r\n",
+		"SUMMARY:Follow up: Launch\\, date\r\n",
+		"DTSTART:20260311T090000Z\r\nDTEND:20260311T093000Z\r\n",
+		"DESCRIPTION:Move launch\\; tell sales.\\nCheck budget.\\n\\nsteno://session/ses\r\n s-1#seg-12\r\n",
+		"URL:steno://session/sess-1#seg-12\r\n",
+		"END:VEVENT\r\nEND:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("ics missing %q:\n%s", want, got)
+		}
+	}
+	for _, line := range strings.Split(got, "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("line over 75 octets: %q", line)
+		}
+	}
+
+	b.Reset()
+	if err := FollowUpICS(&b, NewFollowUp(FollowUpTodo, topic, start, 0), now); err != nil {
+		t.Fatal(err)
+	}
+	if got := b.String(); !strings.Contains(got, "BEGIN:VTODO\r\n") || !strings.Contains(got, "DUE:20260311T090000Z\r\n") || strings.Contains(got, "DTEND") {
+		t.Errorf("todo ics:\n%s", got)
+	}
+	if err := FollowUpICS(&b, FollowUp{Kind: "memo"}, now); err == nil {
+		t.Error("expected error for an unknown kind")
+	}
+}
+
+func TestNextFollowUpSlot(t *testing.T) {
+	for _, tc := range []struct{ now, want string }{
+		{"2026-03-10 17:30", "2026-03-11 09:00"}, // Tuesday → Wednesday
+		{"2026-03-13 08:00", "2026-03-16 09:00"}, // Friday → Monday
+		{"2026-03-14 12:00", "2026-03-16 09:00"}, // Saturday → Monday
+	} {
+		now, _ := time.Parse("2006-01-02 15:04", tc.now)
+		if got := NextFollowUpSlot(now).Format("2006-01-02 15:04"); got != tc.want {
+			t.Errorf("NextFollowUpSlot(%s) = %s, want %s", tc.now, got, tc.want)
+		}
+	}
+}
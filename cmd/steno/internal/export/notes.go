@@ -0,0 +1,152 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jwulff/steno/internal/db"
+)
+
+// The notes repository is a directory meant to be committed to git:
+//
+//	README.md                          index of every session
+//	sessions/2026-03-10-1a2b3c4d.md    one note per session
+//
+// Everything in it is a pure function of the database, so re-exporting
+// an unchanged session reproduces its file byte for byte and git sees no
+// diff. Filenames use the start date and ID prefix rather than the title,
+// which the LLM may rewrite; times are UTC so two teammates' exports
+// agree; nothing records when the export ran.
+
+// NotesDir is the repository subdirectory that holds session notes.
+const NotesDir = "sessions"
+
+// NotesIndexFile is the repository's index file.
+const NotesIndexFile = "README.md"
+
+// NoteFilename is the stable filename of sess's note within NotesDir.
+func NoteFilename(sess db.Session) string {
+	id := sess.ID
+	if len(id) > 8 {
+		id = id[:8]
+	}
+	return fmt.Sprintf("%s-%s.md", sess.StartedAt.UTC().Format("2006-01-02"), strings.ToLower(id))
+}
+
+// SessionNote writes one session's note: metadata, the notes written
+// during it, the segments numbered in decisions, then the transcript,
+// sectioned by topic with each topic's summary ahead of its segments.
+func SessionNote(w io.Writer, sess db.Session, segments []db.Segment, topics []db.Topic, decisions []int, notes string) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", noteTitle(sess))
+
+	fmt.Fprintf(&b, "- Session: `%s`\n", sess.ID)
+	fmt.Fprintf(&b, "- Started: %s\n", noteTime(sess.StartedAt))
+	if sess.EndedAt != nil {
+		fmt.Fprintf(&b, "- Duration: %s\n", noteDuration(sess.EndedAt.Sub(sess.StartedAt)))
+	}
+	if sess.Locale != "" {
+		fmt.Fprintf(&b, "- Language: %s\n", sess.Locale)
+	}
+	if sess.Device != "" {
+		fmt.Fprintf(&b, "- Device: %s\n", sess.Device)
+	}
+	if sess.ForegroundApp != "" {
+		fmt.Fprintf(&b, "- App: %s\n", sess.ForegroundApp)
+	}
+	if sess.MeetingURL != "" {
+		fmt.Fprintf(&b, "- Meeting: %s\n", sess.MeetingURL)
+	}
+
+	if notes = strings.TrimSpace(notes); notes != "" {
+		fmt.Fprintf(&b, "\n## Notes\n\n%s\n", notes)
+	}
+
+	var decided []db.Segment
+	for _, s := range segments {
+		if slices.Contains(decisions, s.SequenceNumber) {
+			decided = append(decided, s)
+		}
+	}
+	if len(decided) > 0 {
+		b.WriteString("\n## Decisions\n\n")
+		for _, s := range decided {
+			fmt.Fprintf(&b, "- **[%s] %s:** %s\n", s.StartedAt.UTC().Format("15:04:05"), sourceLabel(s.Source), oneLine(s.Text))
+		}
+	}
+
+	fmt.Fprintf(&b, "\n## Transcript\n\n")
+	if len(segments) == 0 {
+		b.WriteString("_No segments._\n")
+	}
+	seqs := make([]int, len(segments))
+	for i, s := range segments {
+		seqs[i] = s.SequenceNumber
+	}
+	sections := topicSections(seqs, NewTranscriptTopics(topics))
+	for i, s := range segments {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		if topic, ok := sections[i]; ok {
+			writeTopicHeading(&b, "###", topic)
+		}
+		fmt.Fprintf(&b, "**[%s] %s:** %s\n", s.StartedAt.UTC().Format("15:04:05"), sourceLabel(s.Source), oneLine(s.Text))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// NotesIndexEntry is one session's line in the index.
+type NotesIndexEntry struct {
+	Session  db.Session
+	Segments int
+}
+
+// NotesIndex writes the repository index: one line per session, oldest
+// first, so a new meeting shows up in git as one added line at the end.
+// Entries are expected in that order.
+func NotesIndex(w io.Writer, entries []NotesIndexEntry) error {
+	var b strings.Builder
+	b.WriteString("# Meeting notes\n\n")
+	fmt.Fprintf(&b, "%d session%s, exported from steno.\n\n", len(entries), plural(len(entries)))
+	for _, e := range entries {
+		s := e.Session
+		fmt.Fprintf(&b, "- %s [%s](%s/%s)", s.StartedAt.UTC().Format("2006-01-02 15:04"), linkText.Replace(noteTitle(s)), NotesDir, NoteFilename(s))
+		if s.EndedAt != nil {
+			fmt.Fprintf(&b, ", %s", noteDuration(s.EndedAt.Sub(s.StartedAt)))
+		}
+		fmt.Fprintf(&b, ", %d segment%s\n", e.Segments, plural(e.Segments))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// linkText escapes the brackets that would end a Markdown link early.
+var linkText = strings.NewReplacer("[", `\[`, "]", `\]`)
+
+func noteTitle(sess db.Session) string {
+	if t := oneLine(sess.Title); t != "" {
+		return t
+	}
+	return "Untitled session"
+}
+
+func noteTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02 15:04 UTC")
+}
+
+// noteDuration is d to the minute, e.g. "42m" or "1h05m".
+func noteDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	if d < time.Minute {
+		return "under a minute"
+	}
+	if d < time.Hour {
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+	return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+}
@@ -0,0 +1,93 @@
+package export
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jwulff/steno/internal/db"
+)
+
+func TestSessionNoteIsDeterministic(t *testing.T) {
+	start := time.Date(2026, 3, 10, 14, 0, 0, 0, time.UTC)
+	end := start.Add(42*time.Minute + 20*time.Second)
+	sess := db.Session{ID: "1A2B3C4D-0000-4000-8000-000000000000", Title: "Budget\nreview", Locale: "en_US", StartedAt: start, EndedAt: &end, Device: "USB Mic"}
+	segs := []db.Segment{
+		{SequenceNumber: 1, Text: "Let's start.", StartedAt: start.Add(5 * time.Second), Source: "microphone"},
+		{SequenceNumber: 2, Text: "Sounds   good", StartedAt: start.Add(9 * time.Second), Source: "systemAudio"},
+		{SequenceNumber: 3, Text: "We agreed to cut travel.", StartedAt: start.Add(14 * time.Second), Source: "microphone"},
+	}
+	topics := []db.Topic{{Title: "Q3 budget", Summary: "Agreed to cut travel.", SegmentRangeStart: 1, SegmentRangeEnd: 2}}
+
+	render := func() string {
+		var b strings.Builder
+		if err := SessionNote(&b, sess, segs, topics, []int{3}, ""); err != nil {
+			t.Fatal(err)
+		}
+		return b.String()
+	}
+	want := `# Budget review
+
+- Session: ` + "`1A2B3C4D-0000-4000-8000-000000000000`" + `
+- Started: 2026-03-10 14:00 UTC
+- Duration: 42m
+- Language: en_US
+- Device: USB Mic
+
+## Decisions
+
+- **[14:00:14] mic:** We agreed to cut travel.
+
+## Transcript
+
+### Q3 budget
+
+Agreed to cut travel.
+
+**[14:00:05] mic:** Let's start.
+
+**[14:00:09] system audio:** Sounds good
+
+**[14:00:14] mic:** We agreed to cut travel.
+`
+	if got := render(); got != want {
+		t.Errorf("note:\n%s\nwant:\n%s", got, want)
+	}
+
+	// The local zone must not leak into the output.
+	sess.StartedAt = start.In(time.FixedZone("PDT", -7*3600))
+	if got := render(); got != want {
+		t.Errorf("note depends on the time zone:\n%s", got)
+	}
+	if got := NoteFilename(sess); got != "2026-03-10-1a2b3c4d.md" {
+		t.Errorf("NoteFilename = %q", got)
+	}
+
+	// The session's notes follow the metadata.
+	var b strings.Builder
+	if err := SessionNote(&b, sess, segs, topics, []int{3}, "[14:00:20] Cut travel, not training\n"); err != nil {
+		t.Fatal(err)
+	}
+	if got := b.String(); !strings.Contains(got, "- Device: USB Mic\n\n## Notes\n\n[14:00:20] Cut travel, not training\n\n## Decisions\n") {
+		t.Errorf("note with notes:\n%s", got)
+	}
+}
+
+func TestNotesIndex(t *testing.T) {
+	start := time.Date(2026, 3, 10, 14, 0, 0, 0, time.UTC)
+	end := start.Add(90 * time.Minute)
+	var b strings.Builder
+	err := NotesIndex(&b, []NotesIndexEntry{
+		{Session: db.Session{ID: "aaaaaaaa-1", Title: "[Draft] plan", StartedAt: start, EndedAt: &end}, Segments: 12},
+		{Session: db.Session{ID: "bbbbbbbb-2", StartedAt: start.Add(24 * time.Hour)}, Segments: 1},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "# Meeting notes\n\n2 sessions, exported from steno.\n\n" +
+		"- 2026-03-10 14:00 [\\[Draft\\] plan](sessions/2026-03-10-aaaaaaaa.md), 1h30m, 12 segments\n" +
+		"- 2026-03-11 14:00 [Untitled session](sessions/2026-03-11-bbbbbbbb.md), 1 segment\n"
+	if b.String() != want {
+		t.Errorf("index:\n%s\nwant:\n%s", b.String(), want)
+	}
+}
@@ -0,0 +1,48 @@
+package export
+
+import (
+	"slices"
+
+	"github.com/jwulff/steno/internal/db"
+)
+
+// TranscriptTopic is a topic as exported: a section of the transcript
+// with its heading and summary.
+type TranscriptTopic struct {
+	Title   string
+	Summary string
+	// StartSeq and EndSeq are the topic's segment range, inclusive.
+	StartSeq int
+	EndSeq   int
+}
+
+// NewTranscriptTopics converts a session's topics for export.
+func NewTranscriptTopics(topics []db.Topic) []TranscriptTopic {
+	out := make([]TranscriptTopic, 0, len(topics))
+	for _, t := range topics {
+		out = append(out, TranscriptTopic{Title: t.Title, Summary: t.Summary, StartSeq: t.SegmentRangeStart, EndSeq: t.SegmentRangeEnd})
+	}
+	return out
+}
+
+// topicSections maps each line index in seqs (ascending sequence numbers)
+// at which a topic's section begins to that topic. As with topic
+// chapters, a topic whose first segment was dropped starts at the next
+// surviving one in its range, and one with none left, or starting no
+// later than the previous section, is left out.
+func topicSections(seqs []int, topics []TranscriptTopic) map[int]TranscriptTopic {
+	sorted := slices.Clone(topics)
+	slices.SortStableFunc(sorted, func(a, b TranscriptTopic) int { return a.StartSeq - b.StartSeq })
+
+	sections := map[int]TranscriptTopic{}
+	last := -1
+	for _, t := range sorted {
+		i, _ := slices.BinarySearch(seqs, t.StartSeq)
+		if i == len(seqs) || seqs[i] > t.EndSeq || i <= last {
+			continue
+		}
+		sections[i] = t
+		last = i
+	}
+	return sections
+}
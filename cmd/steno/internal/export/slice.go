@@ -0,0 +1,84 @@
+package export
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Bound is one end of a slice of a transcript: a segment number, or an
+// offset from the session's first segment. The zero Bound leaves that
+// end open.
+type Bound struct {
+	Seq    int
+	Offset time.Duration
+	// IsOffset reports that Offset, not Seq, is set.
+	IsOffset bool
+}
+
+// Open reports whether b leaves its end of the slice open.
+func (b Bound) Open() bool {
+	return !b.IsOffset && b.Seq == 0
+}
+
+// ParseBound reads a slice bound as typed: a segment number ("12" or
+// "#12"), an offset as m:ss or h:mm:ss ("5:30", "1:02:00"), or nothing
+// for an open end.
+func ParseBound(s string) (Bound, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Bound{}, nil
+	}
+	if !strings.Contains(s, ":") {
+		n, err := strconv.Atoi(strings.TrimPrefix(s, "#"))
+		if err != nil || n < 1 {
+			return Bound{}, fmt.Errorf("%q is not a segment number or an m:ss offset", s)
+		}
+		return Bound{Seq: n}, nil
+	}
+	parts := strings.Split(s, ":")
+	if len(parts) > 3 {
+		return Bound{}, fmt.Errorf("%q is not an m:ss or h:mm:ss offset", s)
+	}
+	var d time.Duration
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 || (i > 0 && (len(p) != 2 || n > 59)) {
+			return Bound{}, fmt.Errorf("%q is not an m:ss or h:mm:ss offset", s)
+		}
+		d = d*60 + time.Duration(n)
+	}
+	return Bound{Offset: d * time.Second, IsOffset: true}, nil
+}
+
+// Slice returns the transcript's lines from from through to: those that
+// start at or after from and at or before to. Decisions and topics are
+// kept; only those among the remaining lines are written.
+func (t Transcript) Slice(from, to Bound) Transcript {
+	out := t
+	out.Lines = nil
+	for _, l := range t.Lines {
+		if after(l, from) && before(l, to) {
+			out.Lines = append(out.Lines, l)
+		}
+	}
+	return out
+}
+
+func after(l TranscriptLine, b Bound) bool {
+	if b.IsOffset {
+		return l.Offset >= b.Offset
+	}
+	return l.Seq >= b.Seq
+}
+
+func before(l TranscriptLine, b Bound) bool {
+	switch {
+	case b.IsOffset:
+		return l.Offset <= b.Offset
+	case b.Seq == 0:
+		return true
+	}
+	return l.Seq <= b.Seq
+}
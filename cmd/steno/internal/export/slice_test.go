@@ -0,0 +1,63 @@
+package export
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseBound(t *testing.T) {
+	for _, c := range []struct {
+		in   string
+		want Bound
+	}{
+		{"", Bound{}},
+		{" 12 ", Bound{Seq: 12}},
+		{"#3", Bound{Seq: 3}},
+		{"5:30", Bound{Offset: 5*time.Minute + 30*time.Second, IsOffset: true}},
+		{"0:00", Bound{IsOffset: true}},
+		{"90:00", Bound{Offset: 90 * time.Minute, IsOffset: true}},
+		{"1:02:03", Bound{Offset: time.Hour + 2*time.Minute + 3*time.Second, IsOffset: true}},
+	} {
+		got, err := ParseBound(c.in)
+		if err != nil || got != c.want {
+			t.Errorf("ParseBound(%q) = %+v, %v, want %+v", c.in, got, err, c.want)
+		}
+	}
+	for _, in := range []string{"0", "-2", "abc", "5:3", "5:60", "1:2:3:4", "1::00"} {
+		if _, err := ParseBound(in); err == nil {
+			t.Errorf("ParseBound(%q) should fail", in)
+		}
+	}
+}
+
+func TestTranscriptSlice(t *testing.T) {
+	tr := Transcript{Decisions: []int{2}}
+	for i := range 5 {
+		tr.Lines = append(tr.Lines, TranscriptLine{Seq: i + 1, Offset: time.Duration(i) * time.Minute})
+	}
+	seqs := func(t Transcript) []int {
+		var out []int
+		for _, l := range t.Lines {
+			out = append(out, l.Seq)
+		}
+		return out
+	}
+	for _, c := range []struct {
+		from, to Bound
+		want     int
+	}{
+		{Bound{}, Bound{}, 5},
+		{Bound{Seq: 2}, Bound{Seq: 4}, 3},
+		{Bound{Offset: 90 * time.Second, IsOffset: true}, Bound{}, 3},
+		{Bound{}, Bound{Offset: time.Minute, IsOffset: true}, 2},
+		{Bound{Seq: 4}, Bound{Seq: 2}, 0},
+	} {
+		got := tr.Slice(c.from, c.to)
+		if len(got.Lines) != c.want {
+			t.Errorf("Slice(%+v, %+v) = %v, want %d lines", c.from, c.to, seqs(got), c.want)
+		}
+	}
+	if got := tr.Slice(Bound{Seq: 2}, Bound{}); len(tr.Lines) != 5 || got.Decisions[0] != 2 {
+		t.Error("Slice should leave the transcript alone and keep its decisions")
+	}
+}
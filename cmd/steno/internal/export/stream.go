@@ -0,0 +1,297 @@
+package export
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+	"time"
+)
+
+// CheckpointLines is how many transcript lines an export to a file writes
+// between updates of its progress manifest, and so the most it
+// rewrites after an interruption.
+const CheckpointLines = 500
+
+// WriteTranscript writes t in format, one of TranscriptFormats. sub
+// bounds the cues of the subtitle formats; the others ignore it.
+func WriteTranscript(w io.Writer, format string, t Transcript, sub SubtitleOptions) error {
+	parts, err := transcriptPartsFor(format, t, sub)
+	if err != nil {
+		return err
+	}
+	return parts.writeTo(w)
+}
+
+// transcriptParts is a transcript document in pieces: a head, one piece
+// per transcript line, and a tail. Each format renders its line pieces
+// on demand and in order, so a long session is written as it goes
+// rather than built in memory first, and an interrupted file export can
+// pick up after the last line it wrote.
+type transcriptParts struct {
+	head  string
+	lines int
+	line  func(i int) string
+	tail  string
+	// cue, for the subtitle formats, is the number of the next cue,
+	// which line advances; a resumed export sets it from its progress
+	// manifest.
+	cue *int
+}
+
+func transcriptPartsFor(format string, t Transcript, sub SubtitleOptions) (transcriptParts, error) {
+	switch format {
+	case TranscriptFormatMarkdown:
+		return markdownParts(t), nil
+	case TranscriptFormatWhisper:
+		return whisperParts(t)
+	case TranscriptFormatSRT:
+		return srtParts(t, sub), nil
+	case TranscriptFormatVTT:
+		return vttParts(t, sub), nil
+	default:
+		return transcriptParts{}, fmt.Errorf("unknown transcript format %q: want one of %s", format, strings.Join(TranscriptFormats, ", "))
+	}
+}
+
+func (p transcriptParts) writeTo(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString(p.head)
+	for i := range p.lines {
+		if _, err := bw.WriteString(p.line(i)); err != nil {
+			return err
+		}
+	}
+	bw.WriteString(p.tail)
+	return bw.Flush()
+}
+
+// digest identifies the document by its head, and the subtitle formats'
+// by their options too, without rendering its lines. The heads carry the
+// segment count, and Whisper's the full text, so with the last segment
+// written (see ExportProgress.LastSeq) it tells a resumed export whether
+// it is continuing the document the interrupted one was writing.
+func (p transcriptParts) digest(format string, sub SubtitleOptions) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n", format)
+	if p.cue != nil {
+		fmt.Fprintf(h, "%+v\n", sub)
+	}
+	h.Write([]byte(p.head))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// ExportProgress is the progress manifest of an export to a file,
+// written beside it (see ProgressPath) every CheckpointLines lines and
+// removed once the file is complete.
+type ExportProgress struct {
+	Format    string `json:"format"`
+	SessionID string `json:"session_id,omitempty"`
+	// Digest is the SHA-256 of the document's format, subtitle options
+	// and head.
+	Digest string `json:"digest"`
+	Lines  int    `json:"lines"`
+	Done   int    `json:"done"`
+	// LastSeq is the sequence number of the last segment written, 0
+	// before the first.
+	LastSeq int `json:"last_seq,omitempty"`
+	// Cue is the number of the next subtitle cue, for the subtitle
+	// formats.
+	Cue int `json:"cue,omitempty"`
+	// Offset is the size of the file's head and first Done lines; bytes
+	// past it were written after the checkpoint and are rewritten.
+	Offset    int64     `json:"offset"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ProgressPath is where the progress manifest of an export to path is
+// kept while the export runs.
+func ProgressPath(path string) string {
+	return path + ".progress.json"
+}
+
+// ManifestPath is where the Manifest of a finished export to path is
+// written.
+func ManifestPath(path string) string {
+	return path + ".manifest.json"
+}
+
+// WriteManifestFile writes m to ManifestPath(path).
+func WriteManifestFile(path string, m Manifest) error {
+	f, err := os.Create(ManifestPath(path))
+	if err != nil {
+		return err
+	}
+	werr := WriteManifest(f, m)
+	return errors.Join(werr, f.Close())
+}
+
+// FileExport is a transcript export to a file that survives being
+// interrupted: run again with the same transcript and options, it
+// carries on from its last checkpoint instead of starting over.
+type FileExport struct {
+	path     string
+	parts    transcriptParts
+	lines    []TranscriptLine
+	progress ExportProgress
+	resumed  int
+	f        *os.File
+	w        *bufio.Writer
+}
+
+// ResumeExport starts an export of t to path in format. If path has the
+// progress manifest of an interrupted export of the same document, the
+// file is cut back to that export's last checkpoint and only the lines
+// after it are rendered; otherwise, including when the session has
+// gained segments since, it is written from the start. Write writes the
+// lines.
+func ResumeExport(path, format string, t Transcript, sub SubtitleOptions) (*FileExport, error) {
+	parts, err := transcriptPartsFor(format, t, sub)
+	if err != nil {
+		return nil, err
+	}
+	e := &FileExport{path: path, parts: parts, lines: t.Lines, progress: ExportProgress{
+		Format: format, SessionID: t.SessionID, Digest: parts.digest(format, sub), Lines: parts.lines}}
+	if p, ok := readProgress(ProgressPath(path)); ok && e.continues(p) {
+		if f, err := os.OpenFile(path, os.O_WRONLY, 0); err == nil {
+			if info, err := f.Stat(); err == nil && info.Size() >= p.Offset && f.Truncate(p.Offset) == nil {
+				if _, err := f.Seek(p.Offset, io.SeekStart); err == nil {
+					e.progress, e.resumed = p, p.Done
+					if parts.cue != nil {
+						*parts.cue = p.Cue
+					}
+					e.f, e.w = f, bufio.NewWriter(f)
+					return e, nil
+				}
+			}
+			f.Close()
+		}
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	e.f, e.w = f, bufio.NewWriter(f)
+	e.w.WriteString(parts.head)
+	e.progress.Offset = int64(len(parts.head))
+	if err := e.checkpoint(); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return e, nil
+}
+
+// continues reports whether p is the progress of an export of the
+// document e writes: the same head and options, and the same segment
+// last of those written.
+func (e *FileExport) continues(p ExportProgress) bool {
+	want := e.progress
+	if p.Format != want.Format || p.SessionID != want.SessionID || p.Digest != want.Digest || p.Lines != want.Lines ||
+		p.Done < 0 || p.Done > p.Lines || p.Offset < int64(len(e.parts.head)) {
+		return false
+	}
+	if e.parts.cue != nil && p.Cue < 1 {
+		return false
+	}
+	if p.Done == 0 {
+		return p.LastSeq == 0
+	}
+	return e.lines[p.Done-1].Seq == p.LastSeq
+}
+
+// readProgress reads a progress manifest; ok is false when there is
+// none or it can't be parsed.
+func readProgress(path string) (p ExportProgress, ok bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return p, false
+	}
+	return p, json.Unmarshal(data, &p) == nil
+}
+
+// Resumed is how many lines an interrupted export had already written,
+// or 0 when this one started over.
+func (e *FileExport) Resumed() int { return e.resumed }
+
+// Done is how many lines have been written.
+func (e *FileExport) Done() int { return e.progress.Done }
+
+// Total is how many lines the export writes.
+func (e *FileExport) Total() int { return e.progress.Lines }
+
+// Path is the file being written.
+func (e *FileExport) Path() string { return e.path }
+
+// Write writes up to n more lines, or all that are left when n <= 0,
+// checkpointing as it goes. It reports whether the file is complete, in
+// which case the file is closed and its progress manifest removed.
+func (e *FileExport) Write(n int) (complete bool, err error) {
+	if e.f == nil {
+		return true, nil
+	}
+	for i := 0; (n <= 0 || i < n) && e.progress.Done < e.progress.Lines; i++ {
+		s := e.parts.line(e.progress.Done)
+		if _, err := e.w.WriteString(s); err != nil {
+			return false, err
+		}
+		e.progress.LastSeq = e.lines[e.progress.Done].Seq
+		e.progress.Done++
+		e.progress.Offset += int64(len(s))
+		if e.progress.Done%CheckpointLines == 0 {
+			if err := e.checkpoint(); err != nil {
+				return false, err
+			}
+		}
+	}
+	if e.progress.Done < e.progress.Lines {
+		return false, e.checkpoint()
+	}
+	e.w.WriteString(e.parts.tail)
+	if err := errors.Join(e.w.Flush(), e.f.Close()); err != nil {
+		return false, err
+	}
+	e.f = nil
+	if err := os.Remove(ProgressPath(e.path)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return true, err
+	}
+	return true, nil
+}
+
+// Close stops an unfinished export at a checkpoint, for a later
+// ResumeExport to pick up. It does nothing once the file is complete.
+func (e *FileExport) Close() error {
+	if e.f == nil {
+		return nil
+	}
+	err := errors.Join(e.checkpoint(), e.f.Close())
+	e.f = nil
+	return err
+}
+
+// checkpoint makes the lines written so far durable, then records them
+// in the progress manifest, so the manifest never claims more than the
+// file holds.
+func (e *FileExport) checkpoint() error {
+	if err := e.w.Flush(); err != nil {
+		return err
+	}
+	if err := e.f.Sync(); err != nil {
+		return err
+	}
+	if e.parts.cue != nil {
+		e.progress.Cue = *e.parts.cue
+	}
+	e.progress.UpdatedAt = time.Now().UTC().Truncate(time.Second)
+	data, err := json.MarshalIndent(e.progress, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(ProgressPath(e.path), append(data, '\n'), 0o644)
+}
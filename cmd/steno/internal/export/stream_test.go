@@ -0,0 +1,144 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// longTranscript is a session long enough to take several checkpoints.
+func longTranscript(n int) Transcript {
+	at := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	tr := Transcript{SessionID: "sess-1", Title: "Conference", Locale: "en_US", StartedAt: at,
+		Topics: []TranscriptTopic{{Title: "Keynote", StartSeq: 1, EndSeq: n / 2}, {Title: "Panel", StartSeq: n/2 + 1, EndSeq: n}}}
+	for i := range n {
+		off := time.Duration(i) * 3 * time.Second
+		tr.Lines = append(tr.Lines, TranscriptLine{Seq: i + 1, Offset: off, End: off + 2*time.Second, At: at.Add(off),
+			Speaker: "mic", Text: fmt.Sprintf("line number %d of the talk", i+1)})
+	}
+	return tr
+}
+
+func TestWhisperStreamMatchesEncoder(t *testing.T) {
+	for _, tr := range []Transcript{longTranscript(3), {Locale: "en_US"}} {
+		var b strings.Builder
+		if err := TranscriptWhisper(&b, tr); err != nil {
+			t.Fatal(err)
+		}
+		var doc whisperTranscript
+		if err := json.Unmarshal([]byte(b.String()), &doc); err != nil {
+			t.Fatalf("unmarshal: %v\n%s", err, b.String())
+		}
+		var want strings.Builder
+		enc := json.NewEncoder(&want)
+		enc.SetIndent("", "  ")
+		enc.Encode(doc)
+		if b.String() != want.String() {
+			t.Errorf("streamed whisper =\n%s\nwant\n%s", b.String(), want.String())
+		}
+	}
+}
+
+func TestResumeExport(t *testing.T) {
+	tr := longTranscript(1200)
+	for _, format := range TranscriptFormats {
+		t.Run(format, func(t *testing.T) {
+			var want strings.Builder
+			if err := WriteTranscript(&want, format, tr, SubtitleOptions{}); err != nil {
+				t.Fatal(err)
+			}
+			path := filepath.Join(t.TempDir(), "out")
+
+			e, err := ResumeExport(path, format, tr, SubtitleOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if complete, err := e.Write(CheckpointLines + 200); complete || err != nil {
+				t.Fatalf("Write = %v, %v; want an unfinished export", complete, err)
+			}
+			// Bytes written after the last checkpoint, as if the export
+			// were killed mid-line.
+			f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			f.WriteString("**[09:3")
+			f.Close()
+
+			e, err = ResumeExport(path, format, tr, SubtitleOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if e.Resumed() != CheckpointLines+200 || e.Total() != 1200 {
+				t.Errorf("resumed %d of %d, want %d of 1200", e.Resumed(), e.Total(), CheckpointLines+200)
+			}
+			if complete, err := e.Write(0); !complete || err != nil {
+				t.Fatalf("Write = %v, %v; want a complete export", complete, err)
+			}
+			got, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != want.String() {
+				t.Errorf("resumed export differs from a straight one (%d bytes, want %d)", len(got), want.Len())
+			}
+			if _, err := os.Stat(ProgressPath(path)); !os.IsNotExist(err) {
+				t.Errorf("progress manifest left behind: %v", err)
+			}
+		})
+	}
+}
+
+func TestResumeExportStartsOverWhenChanged(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.md")
+	tr := longTranscript(800)
+	e, err := ResumeExport(path, TranscriptFormatMarkdown, tr, SubtitleOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	e.Write(600)
+	if err := e.Close(); err != nil {
+		t.Fatal(err)
+	}
+	var p ExportProgress
+	data, _ := os.ReadFile(ProgressPath(path))
+	if err := json.Unmarshal(data, &p); err != nil || p.Done != 600 || p.Lines != 800 || p.LastSeq != 600 {
+		t.Fatalf("progress manifest = %s (%v)", data, err)
+	}
+
+	// Segments renumbered since (a merge, say): the last one written is
+	// no longer where the manifest left it.
+	renumbered := longTranscript(800)
+	for i := range renumbered.Lines {
+		renumbered.Lines[i].Seq += 1000
+	}
+	e, err = ResumeExport(path, TranscriptFormatMarkdown, renumbered, SubtitleOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Resumed() != 0 {
+		t.Errorf("resumed = %d, want a fresh start after renumbering", e.Resumed())
+	}
+	e.Write(600)
+	e.Close()
+
+	// The session grew since: the finished file must not mix the two.
+	tr = longTranscript(801)
+	e, err = ResumeExport(path, TranscriptFormatMarkdown, tr, SubtitleOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Resumed() != 0 {
+		t.Errorf("resumed = %d, want a fresh start", e.Resumed())
+	}
+	e.Write(0)
+	var want strings.Builder
+	TranscriptMarkdown(&want, tr)
+	if got, _ := os.ReadFile(path); string(got) != want.String() {
+		t.Errorf("export after restart differs from a straight one")
+	}
+}
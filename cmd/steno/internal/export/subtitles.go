@@ -0,0 +1,173 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// SubtitleOptions bounds the cues SplitSubtitles makes. Zero fields take
+// the defaults, which follow common broadcast guidelines.
+type SubtitleOptions struct {
+	// MaxChars is the longest line, in characters (default 42). A word
+	// longer than this gets a line of its own.
+	MaxChars int
+	// MaxLines is the most lines shown at once (default 2).
+	MaxLines int
+	// MaxDuration is the longest a cue stays up (default 7s).
+	MaxDuration time.Duration
+}
+
+// Subtitle defaults.
+const (
+	DefaultSubtitleChars    = 42
+	DefaultSubtitleLines    = 2
+	DefaultSubtitleDuration = 7 * time.Second
+)
+
+func (o SubtitleOptions) withDefaults() SubtitleOptions {
+	if o.MaxChars <= 0 {
+		o.MaxChars = DefaultSubtitleChars
+	}
+	if o.MaxLines <= 0 {
+		o.MaxLines = DefaultSubtitleLines
+	}
+	if o.MaxDuration <= 0 {
+		o.MaxDuration = DefaultSubtitleDuration
+	}
+	return o
+}
+
+// Cue is one subtitle, shown from Start to End, offsets like
+// TranscriptLine's.
+type Cue struct {
+	Start, End time.Duration
+	Speaker    string
+	Lines      []string
+}
+
+// SplitSubtitles breaks transcript lines into cues no wider, taller or
+// longer than opts allows. ASR segments often run to several sentences,
+// far more than a viewer can read at once. A segment's words are spread
+// evenly over its time by character count, so each cue's start and end
+// are interpolated from where its words fall in the segment.
+func SplitSubtitles(lines []TranscriptLine, opts SubtitleOptions) []Cue {
+	opts = opts.withDefaults()
+	var cues []Cue
+	for _, l := range lines {
+		words := strings.Fields(l.Text)
+		if len(words) == 0 {
+			continue
+		}
+		total := utf8.RuneCountInString(strings.Join(words, " "))
+		span := l.End - l.Offset
+		// A cue may hold as many characters as are spoken in MaxDuration.
+		capacity := total
+		if span > opts.MaxDuration {
+			capacity = int(int64(total) * int64(opts.MaxDuration) / int64(span))
+		}
+		at := func(chars int) time.Duration {
+			return l.Offset + time.Duration(int64(span)*int64(chars)/int64(total))
+		}
+
+		pos := 0
+		for len(words) > 0 {
+			n := 1
+			for n < len(words) && utf8.RuneCountInString(strings.Join(words[:n+1], " ")) <= capacity &&
+				len(wrapWords(words[:n+1], opts.MaxChars)) <= opts.MaxLines {
+				n++
+			}
+			chars := utf8.RuneCountInString(strings.Join(words[:n], " "))
+			end := pos + chars
+			if n < len(words) {
+				end++ // the space before the next cue's first word
+			}
+			cues = append(cues, Cue{Start: at(pos), End: at(min(end, total)), Speaker: l.Speaker, Lines: wrapWords(words[:n], opts.MaxChars)})
+			pos = end
+			words = words[n:]
+		}
+	}
+	return cues
+}
+
+// wrapWords fills lines of at most width characters, greedily.
+func wrapWords(words []string, width int) []string {
+	var lines []string
+	var line string
+	for _, w := range words {
+		switch {
+		case line == "":
+			line = w
+		case utf8.RuneCountInString(line)+1+utf8.RuneCountInString(w) <= width:
+			line += " " + w
+		default:
+			lines = append(lines, line)
+			line = w
+		}
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// TranscriptSRT writes t as SubRip subtitles, split by SplitSubtitles.
+// SubRip has no voice markup, so the first cue of each speaker's turn
+// leads with "speaker: ", ahead of (and not counted in) MaxChars.
+func TranscriptSRT(w io.Writer, t Transcript, opts SubtitleOptions) error {
+	return srtParts(t, opts).writeTo(w)
+}
+
+func srtParts(t Transcript, opts SubtitleOptions) transcriptParts {
+	return subtitleParts(t, opts, "", func(n int, c Cue, turn bool) string {
+		text := strings.Join(c.Lines, "\n")
+		if turn && c.Speaker != "" {
+			text = c.Speaker + ": " + text
+		}
+		return fmt.Sprintf("%d\n%s --> %s\n%s\n\n", n, cueTime(c.Start, ","), cueTime(c.End, ","), text)
+	})
+}
+
+// TranscriptVTT writes t as WebVTT subtitles, split by SplitSubtitles.
+// Each cue names its speaker in a voice span.
+func TranscriptVTT(w io.Writer, t Transcript, opts SubtitleOptions) error {
+	return vttParts(t, opts).writeTo(w)
+}
+
+func vttParts(t Transcript, opts SubtitleOptions) transcriptParts {
+	return subtitleParts(t, opts, "WEBVTT\n\n", func(n int, c Cue, _ bool) string {
+		text := vttEscape(strings.Join(c.Lines, "\n"))
+		if c.Speaker != "" {
+			text = "<v " + vttEscape(c.Speaker) + ">" + text
+		}
+		return fmt.Sprintf("%d\n%s --> %s\n%s\n\n", n, cueTime(c.Start, "."), cueTime(c.End, "."), text)
+	})
+}
+
+// subtitleParts splits each line into its own cues, numbered on from the
+// previous line's, and renders them with cue. turn is set on a line's
+// first cue when its speaker differs from the line before's.
+func subtitleParts(t Transcript, opts SubtitleOptions, head string, cue func(n int, c Cue, turn bool) string) transcriptParts {
+	n := 1
+	return transcriptParts{
+		head:  head,
+		lines: len(t.Lines),
+		line: func(i int) string {
+			var b strings.Builder
+			turn := i == 0 || t.Lines[i-1].Speaker != t.Lines[i].Speaker
+			for j, c := range SplitSubtitles(t.Lines[i:i+1], opts) {
+				b.WriteString(cue(n, c, turn && j == 0))
+				n++
+			}
+			return b.String()
+		},
+		cue: &n,
+	}
+}
+
+// vttEscape escapes the characters WebVTT cue text treats as markup.
+func vttEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
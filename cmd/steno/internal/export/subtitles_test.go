@@ -0,0 +1,90 @@
+package export
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSplitSubtitles(t *testing.T) {
+	lines := []TranscriptLine{
+		{Offset: 0, End: 2 * time.Second, Speaker: "mic", Text: "short one"},
+		// 88 characters over 16s: three cues by length, capped at 7s each.
+		{Offset: 10 * time.Second, End: 26 * time.Second, Speaker: "them", Text: "we looked at the numbers again and the launch has to move to the second week of March now"},
+		{Offset: 30 * time.Second, End: 30 * time.Second, Text: "  "},
+	}
+	cues := SplitSubtitles(lines, SubtitleOptions{MaxChars: 20})
+	if len(cues) < 3 {
+		t.Fatalf("cues = %+v", cues)
+	}
+	if c := cues[0]; c.Start != 0 || c.End != 2*time.Second || strings.Join(c.Lines, "|") != "short one" || c.Speaker != "mic" {
+		t.Errorf("first cue = %+v", c)
+	}
+
+	var words []string
+	prevEnd := 10 * time.Second
+	for _, c := range cues[1:] {
+		if c.Start != prevEnd {
+			t.Errorf("cue %+v doesn't start where the last ended (%v)", c, prevEnd)
+		}
+		if c.End-c.Start > DefaultSubtitleDuration {
+			t.Errorf("cue %+v is up longer than %v", c, DefaultSubtitleDuration)
+		}
+		if len(c.Lines) > DefaultSubtitleLines {
+			t.Errorf("cue %+v has too many lines", c)
+		}
+		for _, l := range c.Lines {
+			if len(l) > 20 {
+				t.Errorf("line %q is over 20 characters", l)
+			}
+			words = append(words, l)
+		}
+		prevEnd = c.End
+	}
+	if prevEnd != 26*time.Second {
+		t.Errorf("last cue ends at %v, want the segment's end", prevEnd)
+	}
+	if got := strings.Join(words, " "); got != lines[1].Text {
+		t.Errorf("cues lost words: %q", got)
+	}
+}
+
+func TestSplitSubtitlesLongWord(t *testing.T) {
+	cues := SplitSubtitles([]TranscriptLine{{End: time.Second, Text: "supercalifragilistic ok"}}, SubtitleOptions{MaxChars: 10, MaxLines: 1})
+	if len(cues) != 2 || cues[0].Lines[0] != "supercalifragilistic" || cues[1].Lines[0] != "ok" {
+		t.Errorf("cues = %+v", cues)
+	}
+}
+
+func TestTranscriptSubtitleFormats(t *testing.T) {
+	tr := Transcript{Lines: []TranscriptLine{{Offset: 1500 * time.Millisecond, End: 3 * time.Second, Speaker: "them", Text: "use <b> & go"}}}
+
+	var srt strings.Builder
+	if err := WriteTranscript(&srt, TranscriptFormatSRT, tr, SubtitleOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if want := "1\n00:00:01,500 --> 00:00:03,000\nthem: use <b> & go\n\n"; srt.String() != want {
+		t.Errorf("srt = %q, want %q", srt.String(), want)
+	}
+
+	// The speaker is named where the turn changes, not on every cue.
+	turns := tr
+	turns.Lines = append(turns.Lines,
+		TranscriptLine{Offset: 4 * time.Second, End: 5 * time.Second, Speaker: "them", Text: "then ship"},
+		TranscriptLine{Offset: 6 * time.Second, End: 7 * time.Second, Speaker: "mic", Text: "agreed"})
+	srt.Reset()
+	if err := WriteTranscript(&srt, TranscriptFormatSRT, turns, SubtitleOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if got := srt.String(); !strings.Contains(got, "\nthen ship\n") || !strings.Contains(got, "\nmic: agreed\n") {
+		t.Errorf("srt turns:\n%s", got)
+	}
+
+	var vtt strings.Builder
+	if err := WriteTranscript(&vtt, TranscriptFormatVTT, tr, SubtitleOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if want := "WEBVTT\n\n1\n00:00:01.500 --> 00:00:03.000\n<v them>use &lt;b&gt; &amp; go\n\n"; vtt.String() != want {
+		t.Errorf("vtt = %q, want %q", vtt.String(), want)
+	}
+}
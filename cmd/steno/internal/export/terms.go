@@ -0,0 +1,150 @@
+package export
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Term is a custom dictionary entry: a name or term as it should be
+// spelled, and the ways the recognizer is known to mishear it.
+type Term struct {
+	Term string
+	// Variants are other spellings to flag as this term, such as
+	// "cube or netties" for "Kubernetes". Casing differences of the term
+	// itself are always flagged.
+	Variants []string
+}
+
+// TermIssue is a dictionary term spelled more than one way in a
+// transcript, or never the dictionary's way.
+type TermIssue struct {
+	Term      string         `json:"term"`
+	Spellings []TermSpelling `json:"spellings"`
+}
+
+// TermSpelling is one spelling of a term other than the dictionary's,
+// and the segments it appears in.
+type TermSpelling struct {
+	Text string `json:"text"`
+	Seqs []int  `json:"segments"`
+}
+
+// Count is how many segments the issue's spellings appear in, counting a
+// segment once per spelling.
+func (i TermIssue) Count() int {
+	n := 0
+	for _, s := range i.Spellings {
+		n += len(s.Seqs)
+	}
+	return n
+}
+
+// String renders the issue for a lint report, with its bulk fix:
+// `Kubernetes: "kubernetes" (segments 4, 9), "cube or netties" (segment 7)`.
+func (i TermIssue) String() string {
+	parts := make([]string, len(i.Spellings))
+	for k, s := range i.Spellings {
+		seqs := make([]string, len(s.Seqs))
+		for j, seq := range s.Seqs {
+			seqs[j] = fmt.Sprint(seq)
+		}
+		noun := "segments"
+		if len(s.Seqs) == 1 {
+			noun = "segment"
+		}
+		parts[k] = fmt.Sprintf("%q (%s %s)", s.Text, noun, strings.Join(seqs, ", "))
+	}
+	return fmt.Sprintf("%s: %s → replace with %q", i.Term, strings.Join(parts, ", "), i.Term)
+}
+
+// termPattern matches term or any of its variants as whole words, in any
+// case and with any run of spaces between words.
+func termPattern(t Term) *regexp.Regexp {
+	alts := make([]string, 0, 1+len(t.Variants))
+	for _, s := range append([]string{t.Term}, t.Variants...) {
+		words := strings.Fields(s)
+		if len(words) == 0 {
+			continue
+		}
+		first, last := words[0], words[len(words)-1]
+		for i, w := range words {
+			words[i] = regexp.QuoteMeta(w)
+		}
+		alt := strings.Join(words, `\s+`)
+		// Word boundaries only hold at word characters: "C++" ends in
+		// none.
+		if wordChar.MatchString(first[:1]) {
+			alt = `\b` + alt
+		}
+		if wordChar.MatchString(last[len(last)-1:]) {
+			alt += `\b`
+		}
+		alts = append(alts, alt)
+	}
+	// Longer spellings first, so a variant containing the term wins.
+	slices.SortStableFunc(alts, func(a, b string) int { return len(b) - len(a) })
+	return regexp.MustCompile(`(?i)` + strings.Join(alts, "|"))
+}
+
+var wordChar = regexp.MustCompile(`^\w$`)
+
+// LintTerms reports the dictionary terms t's segments spell other than
+// the dictionary's way, in dictionary order. Spellings are listed by
+// first appearance.
+func LintTerms(t Transcript, terms []Term) []TermIssue {
+	var issues []TermIssue
+	for _, term := range terms {
+		if strings.TrimSpace(term.Term) == "" {
+			continue
+		}
+		re := termPattern(term)
+		issue := TermIssue{Term: term.Term}
+		seen := map[string]int{} // spelling → index in issue.Spellings
+		for _, l := range t.Lines {
+			for _, m := range re.FindAllString(l.Text, -1) {
+				if m == term.Term {
+					continue
+				}
+				k, ok := seen[m]
+				if !ok {
+					k = len(issue.Spellings)
+					seen[m] = k
+					issue.Spellings = append(issue.Spellings, TermSpelling{Text: m})
+				}
+				if s := &issue.Spellings[k]; !slices.Contains(s.Seqs, l.Seq) {
+					s.Seqs = append(s.Seqs, l.Seq)
+				}
+			}
+		}
+		if len(issue.Spellings) > 0 {
+			issues = append(issues, issue)
+		}
+	}
+	return issues
+}
+
+// FixTerms applies the bulk fixes: every spelling of a dictionary term in
+// t's segments is replaced with the dictionary's. It returns the fixed
+// transcript and how many spellings it replaced; t is not modified.
+func FixTerms(t Transcript, terms []Term) (Transcript, int) {
+	out := t
+	out.Lines = slices.Clone(t.Lines)
+	fixed := 0
+	for _, term := range terms {
+		if strings.TrimSpace(term.Term) == "" {
+			continue
+		}
+		re := termPattern(term)
+		for i, l := range out.Lines {
+			out.Lines[i].Text = re.ReplaceAllStringFunc(l.Text, func(m string) string {
+				if m != term.Term {
+					fixed++
+				}
+				return term.Term
+			})
+		}
+	}
+	return out, fixed
+}
@@ -0,0 +1,45 @@
+package export
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLintTerms(t *testing.T) {
+	tr := Transcript{Lines: []TranscriptLine{
+		{Seq: 1, Text: "we run Kubernetes in prod"},
+		{Seq: 2, Text: "kubernetes and cube or  netties again, kubernetes"},
+		{Seq: 3, Text: "C++ or c++? cubernetes is not listed"},
+		{Seq: 4, Text: "kubernetesy is another word"},
+	}}
+	terms := []Term{{Term: "Kubernetes", Variants: []string{"cube or netties"}}, {Term: "C++"}, {Term: "Go"}}
+
+	got := LintTerms(tr, terms)
+	want := []TermIssue{
+		{Term: "Kubernetes", Spellings: []TermSpelling{{Text: "kubernetes", Seqs: []int{2}}, {Text: "cube or  netties", Seqs: []int{2}}}},
+		{Term: "C++", Spellings: []TermSpelling{{Text: "c++", Seqs: []int{3}}}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("LintTerms = %+v, want %+v", got, want)
+	}
+	if s := got[1].String(); s != `C++: "c++" (segment 3) → replace with "C++"` {
+		t.Errorf("String = %q", s)
+	}
+
+	fixed, n := FixTerms(tr, terms)
+	if n != 4 {
+		t.Errorf("fixed %d spellings, want 4", n)
+	}
+	if got := fixed.Lines[1].Text; got != "Kubernetes and Kubernetes again, Kubernetes" {
+		t.Errorf("line 2 = %q", got)
+	}
+	if got := fixed.Lines[2].Text; got != "C++ or C++? cubernetes is not listed" {
+		t.Errorf("line 3 = %q", got)
+	}
+	if tr.Lines[1].Text == fixed.Lines[1].Text {
+		t.Error("FixTerms should not modify its input")
+	}
+	if issues := LintTerms(fixed, terms); len(issues) != 0 {
+		t.Errorf("fixed transcript still has issues: %+v", issues)
+	}
+}
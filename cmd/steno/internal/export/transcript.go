@@ -0,0 +1,240 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jwulff/steno/internal/db"
+	"github.com/jwulff/steno/internal/decisions"
+)
+
+// Transcript is a session prepared for export: the session's metadata and
+// one line per segment. Anonymize rewrites it before it is written, so
+// every field here is one a preset may blank out.
+type Transcript struct {
+	SessionID     string
+	Title         string
+	Locale        string
+	StartedAt     time.Time // zero when stripped
+	Device        string
+	ForegroundApp string
+	MeetingURL    string
+	Lines         []TranscriptLine
+	// Decisions are the sequence numbers of lines that record a
+	// decision (see package decisions), listed ahead of the transcript.
+	Decisions []int
+	// Topics section the Markdown transcript: each topic's heading and
+	// summary lead the lines in its range.
+	Topics []TranscriptTopic
+	// Summary is the session's latest rolling summary, written first.
+	// Empty when the daemon hasn't summarized the session.
+	Summary string
+	// Notes is the session's notes document (Markdown), written ahead of
+	// the transcript. Empty when the session has none.
+	Notes string
+}
+
+// TranscriptLine is one exported segment.
+type TranscriptLine struct {
+	Seq int
+	// Offset is the segment's start relative to the session's first
+	// segment, which survives stripping wall-clock times.
+	Offset time.Duration
+	// End is the segment's end, as an offset like Offset.
+	End time.Duration
+	// At is the segment's wall-clock start; zero when stripped.
+	At      time.Time
+	Speaker string
+	Text    string
+	// Confidence is the recognizer's 0-1 confidence; nil when the daemon
+	// didn't record one.
+	Confidence *float64
+}
+
+// NewTranscript builds a Transcript from a session and its segments,
+// sorted by sequence number as SegmentsForSession returns them. Segments
+// carry no diarization, so the speaker is the capture source.
+func NewTranscript(sess db.Session, segments []db.Segment) Transcript {
+	t := Transcript{
+		SessionID:     sess.ID,
+		Title:         sess.Title,
+		Locale:        sess.Locale,
+		StartedAt:     sess.StartedAt,
+		Device:        sess.Device,
+		ForegroundApp: sess.ForegroundApp,
+		MeetingURL:    sess.MeetingURL,
+		Lines:         make([]TranscriptLine, 0, len(segments)),
+	}
+	var origin time.Time
+	for _, s := range segments {
+		if origin.IsZero() || s.StartedAt.Before(origin) {
+			origin = s.StartedAt
+		}
+	}
+	for _, s := range segments {
+		end := s.EndedAt
+		if end.Before(s.StartedAt) {
+			end = s.StartedAt
+		}
+		t.Lines = append(t.Lines, TranscriptLine{
+			Seq:        s.SequenceNumber,
+			Offset:     s.StartedAt.Sub(origin),
+			End:        end.Sub(origin),
+			At:         s.StartedAt,
+			Speaker:    sourceLabel(s.Source),
+			Text:       s.Text,
+			Confidence: s.Confidence,
+		})
+	}
+	return t
+}
+
+// LoadTranscript reads sessionID from store as a Transcript, with its
+// notes, its topics, and its decisions, recorded or detected.
+func LoadTranscript(store *db.Store, sessionID string) (Transcript, error) {
+	sess, err := store.GetSession(sessionID)
+	if err != nil {
+		return Transcript{}, err
+	}
+	if sess == nil {
+		return Transcript{}, fmt.Errorf("session %s not found", sessionID)
+	}
+	var segments []db.Segment
+	if err := store.SegmentPages(sess.ID, func(page []db.Segment) error {
+		segments = append(segments, page...)
+		return nil
+	}); err != nil {
+		return Transcript{}, err
+	}
+	recorded, err := store.DecisionsForSession(sess.ID)
+	if err != nil {
+		return Transcript{}, err
+	}
+	topics, err := store.TopicsForSession(sess.ID)
+	if err != nil {
+		return Transcript{}, err
+	}
+	notes, err := store.SessionNotes(sess.ID)
+	if err != nil {
+		return Transcript{}, err
+	}
+	summary, err := store.LatestSummary(sess.ID)
+	if err != nil {
+		return Transcript{}, err
+	}
+	t := NewTranscript(*sess, segments)
+	t.Notes = notes
+	if summary != nil {
+		t.Summary = summary.Content
+	}
+	t.Decisions = decisions.Sequences(recorded, segments)
+	t.Topics = NewTranscriptTopics(topics)
+	return t, nil
+}
+
+// TranscriptMarkdown writes t as a Markdown transcript: a heading, the
+// session's metadata, its summary and notes, then one timestamped line per segment, under a
+// heading and summary for each topic when t has topics. Fields a preset
+// stripped are left out rather than printed empty.
+func TranscriptMarkdown(w io.Writer, t Transcript) error {
+	return markdownParts(t).writeTo(w)
+}
+
+func markdownParts(t Transcript) transcriptParts {
+	var b strings.Builder
+	title := t.Title
+	if title == "" {
+		title = "Untitled session"
+	}
+	fmt.Fprintf(&b, "# %s\n\n", oneLine(title))
+
+	var meta []string
+	if t.SessionID != "" {
+		meta = append(meta, fmt.Sprintf("Session `%s`", t.SessionID))
+	}
+	if !t.StartedAt.IsZero() {
+		meta = append(meta, t.StartedAt.Local().Format("2006-01-02 15:04"))
+	}
+	if t.Device != "" {
+		meta = append(meta, "recorded on "+t.Device)
+	}
+	if t.ForegroundApp != "" {
+		meta = append(meta, "in "+t.ForegroundApp)
+	}
+	if len(meta) > 0 {
+		fmt.Fprintf(&b, "%s.\n", strings.Join(meta, ", "))
+	}
+	if t.MeetingURL != "" {
+		fmt.Fprintf(&b, "Meeting: %s\n", t.MeetingURL)
+	}
+	fmt.Fprintf(&b, "%d segment%s.\n\n", len(t.Lines), plural(len(t.Lines)))
+
+	stamp := func(l TranscriptLine) string {
+		if !l.At.IsZero() {
+			return l.At.Local().Format("15:04:05")
+		}
+		return cueTime(l.Offset, ".")[:8]
+	}
+	seqs := make([]int, len(t.Lines))
+	for i, l := range t.Lines {
+		seqs[i] = l.Seq
+	}
+	sections := topicSections(seqs, t.Topics)
+	summary := strings.TrimSpace(t.Summary)
+	if summary != "" {
+		fmt.Fprintf(&b, "## Summary\n\n%s\n\n", summary)
+	}
+	notes := strings.TrimSpace(t.Notes)
+	if notes != "" {
+		fmt.Fprintf(&b, "## Notes\n\n%s\n\n", notes)
+	}
+	decided := t.decisionLines()
+	if len(decided) > 0 {
+		b.WriteString("## Decisions\n\n")
+		for _, l := range decided {
+			fmt.Fprintf(&b, "- **[%s] %s:** %s\n", stamp(l), l.Speaker, oneLine(l.Text))
+		}
+		b.WriteString("\n")
+	}
+	// Lines ahead of the first topic still need setting apart.
+	if _, ok := sections[0]; !ok && (summary != "" || notes != "" || len(decided) > 0) {
+		b.WriteString("## Transcript\n\n")
+	}
+
+	return transcriptParts{
+		head:  b.String(),
+		lines: len(t.Lines),
+		line: func(i int) string {
+			var b strings.Builder
+			if topic, ok := sections[i]; ok {
+				writeTopicHeading(&b, "##", topic)
+			}
+			l := t.Lines[i]
+			fmt.Fprintf(&b, "**[%s] %s:** %s\n\n", stamp(l), l.Speaker, oneLine(l.Text))
+			return b.String()
+		},
+	}
+}
+
+// decisionLines are the lines named in t.Decisions, in transcript order.
+func (t Transcript) decisionLines() []TranscriptLine {
+	var out []TranscriptLine
+	for _, l := range t.Lines {
+		if slices.Contains(t.Decisions, l.Seq) {
+			out = append(out, l)
+		}
+	}
+	return out
+}
+
+// writeTopicHeading starts a topic's section: its title at the heading
+// level given, then its summary.
+func writeTopicHeading(b *strings.Builder, level string, t TranscriptTopic) {
+	fmt.Fprintf(b, "%s %s\n\n", level, oneLine(t.Title))
+	if s := strings.TrimSpace(t.Summary); s != "" {
+		fmt.Fprintf(b, "%s\n\n", s)
+	}
+}
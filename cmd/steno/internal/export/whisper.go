@@ -0,0 +1,147 @@
+package export
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/json"
+	"io"
+	"math"
+	"strings"
+)
+
+// Transcript output formats.
+const (
+	TranscriptFormatMarkdown = "markdown"
+	// TranscriptFormatWhisper is OpenAI Whisper's verbose JSON, as
+	// written by `whisper --output_format json` and returned by the API's
+	// response_format=verbose_json.
+	TranscriptFormatWhisper = "whisper"
+	// TranscriptFormatSRT and TranscriptFormatVTT are subtitles, with
+	// long segments split into readable cues (see SplitSubtitles).
+	TranscriptFormatSRT = "srt"
+	TranscriptFormatVTT = "vtt"
+)
+
+// TranscriptFormats lists the accepted transcript formats.
+var TranscriptFormats = []string{TranscriptFormatMarkdown, TranscriptFormatWhisper, TranscriptFormatSRT, TranscriptFormatVTT}
+
+// minConfidence keeps avg_logprob finite for segments the recognizer
+// scored 0.
+const minConfidence = 1e-4
+
+// whisperTranscript mirrors Whisper's verbose JSON result.
+type whisperTranscript struct {
+	Task     string           `json:"task"`
+	Language string           `json:"language,omitempty"`
+	Duration float64          `json:"duration"`
+	Text     string           `json:"text"`
+	Segments []whisperSegment `json:"segments"`
+}
+
+// whisperSegment is one Whisper segment. Speaker is not part of Whisper's
+// output; it follows WhisperX, which adds it for diarized transcripts.
+type whisperSegment struct {
+	ID               int     `json:"id"`
+	Seek             int     `json:"seek"`
+	Start            float64 `json:"start"`
+	End              float64 `json:"end"`
+	Text             string  `json:"text"`
+	Tokens           []int   `json:"tokens"`
+	Temperature      float64 `json:"temperature"`
+	AvgLogprob       float64 `json:"avg_logprob"`
+	CompressionRatio float64 `json:"compression_ratio"`
+	NoSpeechProb     float64 `json:"no_speech_prob"`
+	Speaker          string  `json:"speaker,omitempty"`
+}
+
+// TranscriptWhisper writes t as Whisper verbose JSON. Times are seconds
+// from the first segment. steno has no tokenizer or sampling, so tokens
+// are empty and temperature and no_speech_prob are 0. avg_logprob is the
+// log of the segment's confidence, or 0 when none was recorded.
+func TranscriptWhisper(w io.Writer, t Transcript) error {
+	parts, err := whisperParts(t)
+	if err != nil {
+		return err
+	}
+	return parts.writeTo(w)
+}
+
+// whisperParts encodes the document as json.Encoder would with a two-space
+// indent, but a segment at a time after the header.
+func whisperParts(t Transcript) (transcriptParts, error) {
+	doc := whisperTranscript{
+		Task:     "transcribe",
+		Language: whisperLanguage(t.Locale),
+		Segments: []whisperSegment{},
+	}
+	var text strings.Builder
+	segments := make([]whisperSegment, 0, len(t.Lines))
+	for i, l := range t.Lines {
+		// Whisper segment text carries its own leading space, so the
+		// full text is the plain concatenation.
+		line := " " + oneLine(l.Text)
+		text.WriteString(line)
+		seg := whisperSegment{
+			ID:               i,
+			Start:            roundMillis(l.Offset.Seconds()),
+			End:              roundMillis(l.End.Seconds()),
+			Text:             line,
+			Tokens:           []int{},
+			CompressionRatio: compressionRatio(line),
+			Speaker:          l.Speaker,
+		}
+		if l.Confidence != nil {
+			seg.AvgLogprob = math.Log(max(*l.Confidence, minConfidence))
+		}
+		doc.Duration = max(doc.Duration, seg.End)
+		segments = append(segments, seg)
+	}
+	doc.Text = text.String()
+
+	head, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return transcriptParts{}, err
+	}
+	tail := "]\n}\n"
+	if len(segments) > 0 {
+		tail = "\n  " + tail
+	}
+	return transcriptParts{
+		head:  strings.TrimSuffix(string(head), "]\n}"),
+		lines: len(segments),
+		line: func(i int) string {
+			// Segments have no fields that fail to encode.
+			seg, _ := json.MarshalIndent(segments[i], "    ", "  ")
+			sep := ",\n    "
+			if i == 0 {
+				sep = "\n    "
+			}
+			return sep + string(seg)
+		},
+		tail: tail,
+	}, nil
+}
+
+// whisperLanguage reduces a locale like "en_US" or "pt-BR" to the
+// two-letter code Whisper reports.
+func whisperLanguage(locale string) string {
+	lang, _, _ := strings.Cut(strings.ReplaceAll(locale, "_", "-"), "-")
+	return strings.ToLower(lang)
+}
+
+// compressionRatio is Whisper's gzip-ratio hallucination signal: the text's
+// size over its zlib-compressed size. Repetitive text scores high.
+func compressionRatio(text string) float64 {
+	if text == "" {
+		return 0
+	}
+	var b bytes.Buffer
+	zw := zlib.NewWriter(&b)
+	zw.Write([]byte(text))
+	zw.Close()
+	return roundMillis(float64(len(text)) / float64(b.Len()))
+}
+
+func roundMillis(f float64) float64 {
+	return math.Round(f*1000) / 1000
+}
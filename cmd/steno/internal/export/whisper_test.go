@@ -0,0 +1,74 @@
+package export
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jwulff/steno/internal/db"
+)
+
+func TestTranscriptWhisper(t *testing.T) {
+	at := time.Date(2026, 3, 10, 14, 0, 0, 0, time.UTC)
+	conf := 0.5
+	zero := 0.0
+	sess := db.Session{ID: "sess-1", Locale: "en_US", StartedAt: at}
+	segments := []db.Segment{
+		{SequenceNumber: 1, Text: "let's ship\nFriday", StartedAt: at, EndedAt: at.Add(2500 * time.Millisecond), Confidence: &conf, Source: "microphone"},
+		{SequenceNumber: 2, Text: "agreed", StartedAt: at.Add(75 * time.Second), EndedAt: at.Add(76 * time.Second), Confidence: &zero, Source: "systemAudio"},
+		{SequenceNumber: 3, Text: "ok", StartedAt: at.Add(80 * time.Second), EndedAt: at.Add(81 * time.Second), Source: "microphone"},
+	}
+
+	var b strings.Builder
+	if err := WriteTranscript(&b, TranscriptFormatWhisper, NewTranscript(sess, segments), SubtitleOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	var got whisperTranscript
+	if err := json.Unmarshal([]byte(b.String()), &got); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, b.String())
+	}
+	if got.Task != "transcribe" || got.Language != "en" || got.Duration != 81 {
+		t.Errorf("header = %+v", got)
+	}
+	if got.Text != " let's ship Friday agreed ok" {
+		t.Errorf("text = %q", got.Text)
+	}
+	if len(got.Segments) != 3 {
+		t.Fatalf("segments = %d, want 3", len(got.Segments))
+	}
+	s0, s1, s2 := got.Segments[0], got.Segments[1], got.Segments[2]
+	if s0.ID != 0 || s0.Start != 0 || s0.End != 2.5 || s0.Text != " let's ship Friday" || s0.Speaker != "mic" {
+		t.Errorf("segment 0 = %+v", s0)
+	}
+	if want := roundMillis(math.Log(0.5)); roundMillis(s0.AvgLogprob) != want {
+		t.Errorf("avg_logprob = %v, want %v", s0.AvgLogprob, want)
+	}
+	if s1.Start != 75 || math.IsInf(s1.AvgLogprob, 0) || s1.AvgLogprob >= 0 {
+		t.Errorf("segment 1 = %+v, want a finite negative avg_logprob", s1)
+	}
+	if s2.AvgLogprob != 0 {
+		t.Errorf("segment without confidence: avg_logprob = %v, want 0", s2.AvgLogprob)
+	}
+	if s0.CompressionRatio <= 0 || s0.Tokens == nil {
+		t.Errorf("segment 0 = %+v, want a compression ratio and an empty token list", s0)
+	}
+	if !strings.Contains(b.String(), `"tokens": []`) {
+		t.Errorf("tokens should encode as [], got:\n%s", b.String())
+	}
+}
+
+func TestWhisperLanguage(t *testing.T) {
+	for locale, want := range map[string]string{"en_US": "en", "pt-BR": "pt", "DE": "de", "": ""} {
+		if got := whisperLanguage(locale); got != want {
+			t.Errorf("whisperLanguage(%q) = %q, want %q", locale, got, want)
+		}
+	}
+}
+
+func TestWriteTranscriptUnknownFormat(t *testing.T) {
+	if err := WriteTranscript(&strings.Builder{}, "docx", Transcript{}, SubtitleOptions{}); err == nil {
+		t.Error("want error for unknown format")
+	}
+}
@@ -0,0 +1,45 @@
+// Package perf runs benchmark functions against fixed time budgets so a
+// regression in the render or event paths fails `make bench` instead of
+// going unnoticed. Budgets are deliberately generous (3-10x the numbers
+// measured when they were set): they catch order-of-magnitude regressions
+// such as an accidental O(n²) re-render, not small drift.
+package perf
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+// EnvVar gates budget checks. Timing assertions are too noisy for the
+// default `go test ./...` run (shared CI runners, -race), so they only
+// run when `make bench` sets it.
+const EnvVar = "STENO_BENCH_BUDGETS"
+
+// Budget is the maximum acceptable time per operation for one benchmark.
+type Budget struct {
+	Name  string
+	Bench func(b *testing.B)
+	Max   time.Duration
+}
+
+// Check runs each budget's benchmark via testing.Benchmark and fails t
+// when ns/op exceeds Max. Skips unless EnvVar is set.
+func Check(t *testing.T, budgets []Budget) {
+	t.Helper()
+	if os.Getenv(EnvVar) == "" {
+		t.Skipf("set %s=1 (or run `make bench`) to check performance budgets", EnvVar)
+	}
+	for _, bg := range budgets {
+		res := testing.Benchmark(bg.Bench)
+		if res.N == 0 {
+			t.Errorf("%s: benchmark did not run", bg.Name)
+			continue
+		}
+		perOp := time.Duration(res.NsPerOp())
+		t.Logf("%s: %v/op (budget %v), %d B/op", bg.Name, perOp, bg.Max, res.AllocedBytesPerOp())
+		if perOp > bg.Max {
+			t.Errorf("%s: %v/op exceeds budget %v", bg.Name, perOp, bg.Max)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+// Package quality reports how well a session was captured: how much of
+// it the ASR was unsure of, how often the mic clipped, how long there
+// was audio but no transcript, and what recorded it. The TUI computes a
+// report when a session ends and stores it, so trends across sessions (a
+// failing mic, a noisy room) show up in `steno quality`.
+package quality
+
+import (
+	"slices"
+	"time"
+
+	"github.com/jwulff/steno/internal/db"
+)
+
+const (
+	// LowConfidence is the confidence below which a segment counts as
+	// low-confidence: calibration's lowest bucket.
+	LowConfidence = 0.5
+	// ClipDB is the mic peak, in dBFS, at which a segment counts as
+	// clipped.
+	ClipDB = -1.0
+	// SpeechLevel is the linear peak at which audio counts as possibly
+	// speech, as the daemon's speech-onset detector uses.
+	SpeechLevel = 0.05
+	// MinGap is the shortest stretch of audio without transcription
+	// reported as a gap; shorter ones are the ASR catching up.
+	MinGap = 10 * time.Second
+	// QuietReset is how long audio must stay below SpeechLevel to end a
+	// stretch. Pauses between words are shorter.
+	QuietReset = 3 * time.Second
+)
+
+// Gap is a stretch with audio loud enough to be speech but no
+// transcription.
+type Gap struct {
+	Start, End time.Time
+}
+
+// Duration is the gap's length.
+func (g Gap) Duration() time.Duration {
+	return g.End.Sub(g.Start)
+}
+
+// Compute builds a session's report from its canonical segments, the
+// clipped-segment count (db.ClippedSegments at ClipDB), its metadata (nil
+// when none was recorded) and the gaps a Monitor found.
+func Compute(sessionID string, segs []db.Segment, clipped int, md *db.SessionMetadata, gaps []Gap, now time.Time) db.SessionQuality {
+	q := db.SessionQuality{SessionID: sessionID, Segments: len(segs), Clipped: clipped, Gaps: len(gaps), CreatedAt: now}
+	for _, seg := range segs {
+		if seg.Confidence != nil && *seg.Confidence < LowConfidence {
+			q.LowConfidence++
+		}
+	}
+	if len(segs) > 0 {
+		q.Duration = segs[len(segs)-1].EndedAt.Sub(segs[0].StartedAt)
+	}
+	for _, g := range gaps {
+		q.GapTime += g.Duration()
+	}
+	if md != nil {
+		q.Device = md.Device
+		q.SystemAudio = md.SystemAudio
+	}
+	return q
+}
+
+// ForSession reads what Compute needs for sessionID from store.
+func ForSession(store *db.Store, sessionID string, gaps []Gap, now time.Time) (db.SessionQuality, error) {
+	segs, err := store.SegmentsForSession(sessionID, 0, 0)
+	if err != nil {
+		return db.SessionQuality{}, err
+	}
+	clipped, err := store.ClippedSegments(sessionID, ClipDB)
+	if err != nil {
+		return db.SessionQuality{}, err
+	}
+	md, err := store.SessionMetadataFor(sessionID)
+	if err != nil {
+		return db.SessionQuality{}, err
+	}
+	return Compute(sessionID, segs, clipped, md, gaps, now), nil
+}
+
+// Monitor finds gaps in a live session from its level events and
+// transcription. The zero value is ready to use.
+type Monitor struct {
+	loudFrom, loudTo time.Time
+	gaps             []Gap
+}
+
+// Level feeds one level reading: the louder of the mic and system audio
+// peaks.
+func (m *Monitor) Level(peak float64, at time.Time) {
+	if peak < SpeechLevel {
+		if !m.loudFrom.IsZero() && at.Sub(m.loudTo) > QuietReset {
+			m.closeStretch()
+		}
+		return
+	}
+	if m.loudFrom.IsZero() {
+		m.loudFrom = at
+	}
+	m.loudTo = at
+}
+
+// Transcribed marks the ASR producing text (a partial or a segment),
+// which ends the stretch in progress.
+func (m *Monitor) Transcribed() {
+	m.closeStretch()
+}
+
+// Gaps ends the stretch in progress and returns the gaps found so far.
+func (m *Monitor) Gaps() []Gap {
+	m.closeStretch()
+	return slices.Clone(m.gaps)
+}
+
+func (m *Monitor) closeStretch() {
+	if !m.loudFrom.IsZero() && m.loudTo.Sub(m.loudFrom) >= MinGap {
+		m.gaps = append(m.gaps, Gap{Start: m.loudFrom, End: m.loudTo})
+	}
+	m.loudFrom, m.loudTo = time.Time{}, time.Time{}
+}
@@ -0,0 +1,76 @@
+package quality
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jwulff/steno/internal/db"
+)
+
+func TestCompute(t *testing.T) {
+	conf := func(v float64) *float64 { return &v }
+	start := time.Unix(1710000000, 0)
+	segs := []db.Segment{
+		{StartedAt: start, EndedAt: start.Add(5 * time.Second), Confidence: conf(0.9)},
+		{StartedAt: start.Add(10 * time.Second), EndedAt: start.Add(15 * time.Second), Confidence: conf(0.3)},
+		{StartedAt: start.Add(20 * time.Second), EndedAt: start.Add(time.Minute)}, // no confidence: not low
+		{StartedAt: start.Add(time.Minute), EndedAt: start.Add(2 * time.Minute), Confidence: conf(0.49)},
+	}
+	off := false
+	md := &db.SessionMetadata{Device: "USB Mic", SystemAudio: &off}
+	gaps := []Gap{{start, start.Add(12 * time.Second)}, {start.Add(time.Minute), start.Add(80 * time.Second)}}
+
+	q := Compute("s1", segs, 1, md, gaps, start)
+	if q.Segments != 4 || q.LowConfidence != 2 || q.Clipped != 1 || q.Gaps != 2 {
+		t.Errorf("counts = %+v", q)
+	}
+	if q.GapTime != 32*time.Second || q.Duration != 2*time.Minute {
+		t.Errorf("gap time %v, duration %v", q.GapTime, q.Duration)
+	}
+	if q.Device != "USB Mic" || q.SystemAudio == nil || *q.SystemAudio {
+		t.Errorf("device = %q, system audio %v", q.Device, q.SystemAudio)
+	}
+	if q.LowConfidencePercent() != 50 {
+		t.Errorf("low confidence = %v%%", q.LowConfidencePercent())
+	}
+
+	if empty := Compute("s2", nil, 0, nil, nil, start); empty.Duration != 0 || empty.LowConfidencePercent() != 0 {
+		t.Errorf("empty session = %+v", empty)
+	}
+}
+
+func TestMonitorGaps(t *testing.T) {
+	start := time.Unix(1710000000, 0)
+	at := func(d time.Duration) time.Time { return start.Add(d) }
+	loud := func(m *Monitor, from, to time.Duration) {
+		for d := from; d <= to; d += 100 * time.Millisecond {
+			m.Level(0.2, at(d))
+		}
+	}
+
+	var m Monitor
+	// 15s of audio with no transcript: a gap, despite a 2s pause inside.
+	loud(&m, 0, 7*time.Second)
+	m.Level(0.01, at(8*time.Second))
+	loud(&m, 9*time.Second, 15*time.Second)
+	// A quiet spell past QuietReset ends it.
+	m.Level(0.01, at(20*time.Second))
+	// 30s of audio the ASR kept up with: no gap.
+	loud(&m, 30*time.Second, 38*time.Second)
+	m.Transcribed()
+	loud(&m, 38*time.Second, 46*time.Second)
+	m.Transcribed()
+	// A stretch still open at the end counts.
+	loud(&m, time.Minute, 72*time.Second)
+
+	gaps := m.Gaps()
+	want := []Gap{{at(0), at(15 * time.Second)}, {at(time.Minute), at(72 * time.Second)}}
+	if len(gaps) != len(want) {
+		t.Fatalf("gaps = %+v, want %+v", gaps, want)
+	}
+	for i := range want {
+		if !gaps[i].Start.Equal(want[i].Start) || !gaps[i].End.Equal(want[i].End) {
+			t.Errorf("gap %d = %+v, want %+v", i, gaps[i], want[i])
+		}
+	}
+}
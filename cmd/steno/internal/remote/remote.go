@@ -0,0 +1,214 @@
+// Package remote serves the inbound control endpoint (`steno serve
+// --control`): start, stop, mark and note over HTTP, so Stream Deck
+// buttons, Raycast scripts and home automation can drive recording
+// without a terminal. Every request must carry the token as
+// "Authorization: Bearer <token>".
+//
+// Routes are POST-only and answer JSON:
+//
+//	POST /start  start recording, or report the session already recording
+//	POST /stop   stop recording; stopping an idle daemon is not an error
+//	POST /mark   bookmark the latest segment of the current session
+//	POST /note   attach {"text": "..."} as a note on the latest segment
+package remote
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/jwulff/steno/internal/daemon"
+	"github.com/jwulff/steno/internal/db"
+)
+
+// maxBodyBytes caps a request body; a note is a line or two.
+const maxBodyBytes = 64 << 10
+
+// Server is an http.Handler for the control endpoint. It holds no
+// connection between requests: each one dials the daemon, and mark and
+// note open the database only for as long as they write.
+type Server struct {
+	socketPath string
+	open       func() (*db.Store, error)
+	token      string
+	start      daemon.StartOptions
+	mux        *http.ServeMux
+}
+
+// New returns a Server sending commands to the daemon at socketPath and
+// writing annotations through open, which should open the database
+// read-write for client tables. start are the options a start command
+// records with. token must not be empty.
+func New(socketPath string, open func() (*db.Store, error), token string, start daemon.StartOptions) *Server {
+	s := &Server{socketPath: socketPath, open: open, token: token, start: start, mux: http.NewServeMux()}
+	s.mux.HandleFunc("POST /start", s.handleStart)
+	s.mux.HandleFunc("POST /stop", s.handleStop)
+	s.mux.HandleFunc("POST /mark", s.handleMark)
+	s.mux.HandleFunc("POST /note", s.handleNote)
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !s.authorized(r) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="steno"`)
+		writeError(w, http.StatusUnauthorized, errors.New("missing or wrong token"))
+		return
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+// authorized reports whether r carries the token, compared in constant
+// time.
+func (s *Server) authorized(r *http.Request) bool {
+	got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && s.token != "" && subtle.ConstantTimeCompare([]byte(got), []byte(s.token)) == 1
+}
+
+// result is the JSON answer to every request.
+type result struct {
+	OK         bool   `json:"ok"`
+	Error      string `json:"error,omitempty"`
+	Action     string `json:"action,omitempty"`
+	SessionID  string `json:"session_id,omitempty"`
+	SegmentSeq int    `json:"segment_seq,omitempty"`
+}
+
+// httpError is an error with the status code it answers with.
+type httpError struct {
+	code int
+	err  error
+}
+
+func (e *httpError) Error() string { return e.err.Error() }
+
+func writeJSON(w http.ResponseWriter, code int, res result) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(res)
+}
+
+func writeError(w http.ResponseWriter, code int, err error) {
+	var he *httpError
+	if errors.As(err, &he) {
+		code = he.code
+	}
+	writeJSON(w, code, result{Error: err.Error()})
+}
+
+// command sends cmd to the daemon on a connection of its own. A daemon
+// that isn't running, or that refuses the command, is a 502: the
+// endpoint only relays.
+func (s *Server) command(cmd daemon.Command) (daemon.Response, error) {
+	client, err := daemon.Connect(s.socketPath)
+	if err != nil {
+		return daemon.Response{}, &httpError{http.StatusBadGateway, fmt.Errorf("daemon not running: %w", err)}
+	}
+	defer client.Close()
+	resp, err := client.SendCommand(cmd)
+	if err != nil {
+		return daemon.Response{}, &httpError{http.StatusBadGateway, err}
+	}
+	if !resp.OK {
+		return daemon.Response{}, &httpError{http.StatusBadGateway, fmt.Errorf("%s: %s", cmd.Cmd, resp.Error)}
+	}
+	return resp, nil
+}
+
+func recording(resp daemon.Response) bool {
+	return resp.Recording != nil && *resp.Recording
+}
+
+func (s *Server) handleStart(w http.ResponseWriter, r *http.Request) {
+	status, err := s.command(daemon.Command{Cmd: "status"})
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	if recording(status) {
+		writeJSON(w, http.StatusOK, result{OK: true, Action: "already recording", SessionID: status.SessionID})
+		return
+	}
+	resp, err := s.command(daemon.StartCmd(s.start))
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result{OK: true, Action: "started", SessionID: resp.SessionID})
+}
+
+func (s *Server) handleStop(w http.ResponseWriter, r *http.Request) {
+	status, err := s.command(daemon.Command{Cmd: "status"})
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	if !recording(status) {
+		writeJSON(w, http.StatusOK, result{OK: true, Action: "not recording"})
+		return
+	}
+	if _, err := s.command(daemon.Command{Cmd: "stop"}); err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result{OK: true, Action: "stopped", SessionID: status.SessionID})
+}
+
+func (s *Server) handleMark(w http.ResponseWriter, r *http.Request) {
+	s.annotate(w, db.AnnotationBookmark, "")
+}
+
+func (s *Server) handleNote(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Text string `json:"text"`
+	}
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxBodyBytes)).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("want a JSON body like {\"text\": \"...\"}: %w", err))
+		return
+	}
+	text := strings.TrimSpace(body.Text)
+	if text == "" {
+		writeError(w, http.StatusBadRequest, errors.New("note text is empty"))
+		return
+	}
+	s.annotate(w, db.AnnotationNote, text)
+}
+
+// annotate attaches an annotation of kind to the newest segment of the
+// session recording now. Without one there is nothing to attach it to,
+// which is a 409 rather than a failure of the endpoint.
+func (s *Server) annotate(w http.ResponseWriter, kind, body string) {
+	status, err := s.command(daemon.Command{Cmd: "status"})
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	if !recording(status) || status.SessionID == "" {
+		writeError(w, http.StatusConflict, errors.New("not recording"))
+		return
+	}
+	store, err := s.open()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	defer store.Close()
+	latest, err := store.QuerySegments(db.Query{Sessions: []string{status.SessionID}, Newest: true, Limit: 1})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if len(latest) == 0 {
+		writeError(w, http.StatusConflict, errors.New("nothing said yet to "+kind))
+		return
+	}
+	a, err := store.AddAnnotation(status.SessionID, latest[0].SequenceNumber, kind, body)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result{OK: true, Action: a.Kind, SessionID: a.SessionID, SegmentSeq: a.SegmentSeq})
+}
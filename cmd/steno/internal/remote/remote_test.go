@@ -0,0 +1,194 @@
+package remote
+
+import (
+	"bufio"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jwulff/steno/internal/daemon"
+	"github.com/jwulff/steno/internal/db"
+
+	_ "modernc.org/sqlite"
+)
+
+const testToken = "s3cret"
+
+// mockDaemon answers commands from responses and records their names.
+func mockDaemon(t *testing.T, responses map[string]daemon.Response) (string, chan string) {
+	t.Helper()
+
+	// Short /tmp path: macOS caps sun_path at 104 bytes.
+	sockPath := fmt.Sprintf("/tmp/steno-remote-%d.sock", time.Now().UnixNano())
+	ln, err := net.Listen("unix", sockPath)
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() {
+		ln.Close()
+		os.Remove(sockPath)
+	})
+
+	seen := make(chan string, 16)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				scanner := bufio.NewScanner(conn)
+				for scanner.Scan() {
+					var cmd daemon.Command
+					_ = json.Unmarshal(scanner.Bytes(), &cmd)
+					seen <- cmd.Cmd
+					resp, ok := responses[cmd.Cmd]
+					if !ok {
+						resp = daemon.Response{OK: false, Error: "Unknown command: " + cmd.Cmd}
+					}
+					data, _ := json.Marshal(resp)
+					conn.Write(append(data, '\n'))
+				}
+			}(conn)
+		}
+	}()
+	return sockPath, seen
+}
+
+// testDBFile creates an on-disk database with one recording session of
+// two segments.
+func testDBFile(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "steno.sqlite")
+	d, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer d.Close()
+
+	stmts := []string{
+		`CREATE TABLE sessions (id TEXT PRIMARY KEY, locale TEXT NOT NULL, startedAt REAL NOT NULL, endedAt REAL, title TEXT, status TEXT NOT NULL DEFAULT 'active', createdAt REAL NOT NULL)`,
+		`CREATE TABLE segments (id TEXT PRIMARY KEY, sessionId TEXT NOT NULL, text TEXT NOT NULL, startedAt REAL NOT NULL, endedAt REAL NOT NULL, confidence REAL, sequenceNumber INTEGER NOT NULL, createdAt REAL NOT NULL, source TEXT NOT NULL DEFAULT 'microphone', duplicate_of TEXT)`,
+		`INSERT INTO sessions (id, locale, startedAt, status, createdAt) VALUES ('sess-1', 'en_US', 1710000000, 'active', 1710000000)`,
+		`INSERT INTO segments (id, sessionId, text, startedAt, endedAt, sequenceNumber, createdAt) VALUES ('seg-1', 'sess-1', 'hello', 1710000010, 1710000019, 1, 1710000010)`,
+		`INSERT INTO segments (id, sessionId, text, startedAt, endedAt, sequenceNumber, createdAt) VALUES ('seg-2', 'sess-1', 'the budget is due Friday', 1710000020, 1710000029, 2, 1710000020)`,
+	}
+	for _, s := range stmts {
+		if _, err := d.Exec(s); err != nil {
+			t.Fatalf("exec %q: %v", s, err)
+		}
+	}
+	return path
+}
+
+func testServer(t *testing.T, responses map[string]daemon.Response) (*Server, chan string, string) {
+	t.Helper()
+	sock, seen := mockDaemon(t, responses)
+	path := testDBFile(t)
+	open := func() (*db.Store, error) { return db.OpenClient(path, db.Options{}) }
+	return New(sock, open, testToken, daemon.StartOptions{}), seen, path
+}
+
+func post(t *testing.T, h http.Handler, target, token, body string) (int, result) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	var res result
+	if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+		t.Fatalf("%s: body %q: %v", target, rec.Body.String(), err)
+	}
+	return rec.Code, res
+}
+
+func TestTokenRequired(t *testing.T) {
+	s, seen, _ := testServer(t, nil)
+	for _, token := range []string{"", "wrong", testToken + "x"} {
+		if code, res := post(t, s, "/start", token, ""); code != http.StatusUnauthorized || res.OK {
+			t.Errorf("token %q: %d %+v, want 401", token, code, res)
+		}
+	}
+	select {
+	case cmd := <-seen:
+		t.Errorf("unauthorized request reached the daemon: %s", cmd)
+	default:
+	}
+}
+
+func TestStartAndStop(t *testing.T) {
+	s, seen, _ := testServer(t, map[string]daemon.Response{
+		"status": {OK: true, Recording: daemon.BoolPtr(false)},
+		"start":  {OK: true, SessionID: "sess-9"},
+	})
+	code, res := post(t, s, "/start", testToken, "")
+	if code != http.StatusOK || res.Action != "started" || res.SessionID != "sess-9" {
+		t.Errorf("start: %d %+v", code, res)
+	}
+	if a, b := <-seen, <-seen; a != "status" || b != "start" {
+		t.Errorf("daemon saw %s, %s; want status, start", a, b)
+	}
+
+	// Stopping an idle daemon is a no-op.
+	code, res = post(t, s, "/stop", testToken, "")
+	if code != http.StatusOK || res.Action != "not recording" {
+		t.Errorf("stop: %d %+v", code, res)
+	}
+}
+
+func TestMarkAndNote(t *testing.T) {
+	s, _, path := testServer(t, map[string]daemon.Response{
+		"status": {OK: true, Recording: daemon.BoolPtr(true), SessionID: "sess-1"},
+	})
+	code, res := post(t, s, "/mark", testToken, "")
+	if code != http.StatusOK || res.Action != db.AnnotationBookmark || res.SegmentSeq != 2 {
+		t.Errorf("mark: %d %+v, want a bookmark on #2", code, res)
+	}
+	code, res = post(t, s, "/note", testToken, `{"text": "follow up on the budget"}`)
+	if code != http.StatusOK || res.Action != db.AnnotationNote {
+		t.Errorf("note: %d %+v", code, res)
+	}
+	if code, _ := post(t, s, "/note", testToken, `{"text": "  "}`); code != http.StatusBadRequest {
+		t.Errorf("empty note: %d, want 400", code)
+	}
+
+	store, err := db.Open(path, db.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	anns, err := store.AnnotationsForSession("sess-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(anns) != 2 || anns[1].Body != "follow up on the budget" {
+		t.Errorf("annotations = %+v", anns)
+	}
+}
+
+func TestMarkWhileIdle(t *testing.T) {
+	s, _, _ := testServer(t, map[string]daemon.Response{
+		"status": {OK: true, Recording: daemon.BoolPtr(false)},
+	})
+	if code, res := post(t, s, "/mark", testToken, ""); code != http.StatusConflict || res.OK {
+		t.Errorf("mark while idle: %d %+v, want 409", code, res)
+	}
+}
+
+func TestDaemonDown(t *testing.T) {
+	s := New("/tmp/steno-remote-missing.sock", nil, testToken, daemon.StartOptions{})
+	if code, res := post(t, s, "/stop", testToken, ""); code != http.StatusBadGateway || !strings.Contains(res.Error, "daemon not running") {
+		t.Errorf("daemon down: %d %+v", code, res)
+	}
+}
@@ -0,0 +1,86 @@
+// Package scrub masks sensitive substrings (card numbers, API tokens, ...)
+// in transcript text. It is display-time only: callers scrub what they
+// render or emit, never what is stored in SQLite.
+package scrub
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// DefaultReplacement is substituted for each match when none is configured.
+const DefaultReplacement = "[redacted]"
+
+// DefaultPatterns are used when scrubbing is enabled without an explicit
+// pattern list. ASR renders spoken digits as numerals, so a read-aloud
+// card number arrives as "4111 1111 1111 1111".
+var DefaultPatterns = []string{
+	// Payment card numbers: 13-19 digits, optionally grouped by spaces or dashes.
+	`\b(?:\d[ -]?){12,18}\d\b`,
+	// Common API token prefixes (OpenAI, GitHub, Slack, AWS access keys).
+	`\b(?:sk-[A-Za-z0-9_-]{16,}|gh[pousr]_[A-Za-z0-9]{20,}|xox[abprs]-[A-Za-z0-9-]{10,}|AKIA[0-9A-Z]{16})\b`,
+	// Long hex secrets (32+ hex chars).
+	`\b[0-9a-fA-F]{32,}\b`,
+}
+
+// PIIPatterns match personal data that DefaultPatterns leave alone: email
+// addresses, phone numbers, US social security numbers and IPv4
+// addresses. Used on top of DefaultPatterns by export anonymization;
+// names are not detected.
+var PIIPatterns = []string{
+	// Email addresses.
+	`\b[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}\b`,
+	// US social security numbers. Before phone numbers, which would
+	// otherwise take the digits apart.
+	`\b\d{3}-\d{2}-\d{4}\b`,
+	// Phone numbers: 10 digits grouped 3-3-4, optionally with a country
+	// code and a parenthesized area code.
+	`(?:\+\d{1,3}[ .-]?)?(?:\(\d{3}\) ?|\b\d{3}[ .-]?)\d{3}[ .-]?\d{4}\b`,
+	// IPv4 addresses.
+	`\b(?:\d{1,3}\.){3}\d{1,3}\b`,
+}
+
+// Scrubber replaces every match of its patterns. A nil *Scrubber is valid
+// and leaves text unchanged, so callers don't need to branch on whether
+// scrubbing is enabled.
+type Scrubber struct {
+	patterns    []*regexp.Regexp
+	replacement string
+}
+
+// New compiles patterns into a Scrubber. An empty replacement means
+// DefaultReplacement.
+func New(patterns []string, replacement string) (*Scrubber, error) {
+	if replacement == "" {
+		replacement = DefaultReplacement
+	}
+	s := &Scrubber{replacement: replacement}
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("scrub pattern %q: %w", p, err)
+		}
+		s.patterns = append(s.patterns, re)
+	}
+	return s, nil
+}
+
+// Apply returns text with every pattern match replaced.
+func (s *Scrubber) Apply(text string) string {
+	text, _ = s.ApplyCount(text)
+	return text
+}
+
+// ApplyCount is Apply that also reports how many matches were replaced,
+// for callers that record what they redacted.
+func (s *Scrubber) ApplyCount(text string) (string, int) {
+	if s == nil || text == "" {
+		return text, 0
+	}
+	n := 0
+	for _, re := range s.patterns {
+		n += len(re.FindAllStringIndex(text, -1))
+		text = re.ReplaceAllLiteralString(text, s.replacement)
+	}
+	return text, n
+}
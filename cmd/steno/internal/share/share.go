@@ -0,0 +1,272 @@
+// Package share broadcasts a TUI's live view to read-only viewers over
+// TCP (`steno --share ADDR`, watched with `steno --view HOST:PORT`). The
+// wire is the daemon's own event stream, NDJSON daemon.Event lines, so a
+// viewer reads it with a daemon.Client and renders it with the TUI's
+// event handlers. A viewer's first line must be the hub's token, which
+// the host shows and hands out; nothing it sends after that is read, so
+// there is no command path back to the host or its daemon. The stream is
+// not encrypted.
+package share
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/json"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jwulff/steno/internal/daemon"
+)
+
+const (
+	// maxBacklog caps the segments replayed to a viewer who joins
+	// mid-session; older ones are dropped first.
+	maxBacklog = 5000
+	// viewerQueue is how many events a viewer may fall behind before it
+	// is dropped, so one slow connection can't hold up the host.
+	viewerQueue = 256
+	// writeTimeout bounds one write to a viewer.
+	writeTimeout = 10 * time.Second
+	// tokenTimeout is how long a new connection has to present the token.
+	tokenTimeout = 10 * time.Second
+	// maxTokenLine caps what is read while waiting for the token.
+	maxTokenLine = 256
+)
+
+// Hub accepts viewers and sends each of them the events published to it.
+// A viewer who joins mid-session is first sent the session so far: the
+// last status, its segments, the topic list and the pause state.
+type Hub struct {
+	ln    net.Listener
+	token string
+
+	mu        sync.Mutex
+	viewers   map[*viewer]struct{}
+	closed    bool
+	sessionID string
+	status    *daemon.Event
+	segments  []daemon.Event
+	topics    *daemon.Event
+	pause     *daemon.Event
+}
+
+type viewer struct {
+	conn net.Conn
+	out  chan daemon.Event
+	once sync.Once
+}
+
+func (v *viewer) close() {
+	v.once.Do(func() {
+		close(v.out)
+		v.conn.Close()
+	})
+}
+
+// Listen starts a Hub accepting viewers on the TCP address addr, with a
+// new random token for them to present.
+func Listen(addr string) (*Hub, error) {
+	var b [10]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return nil, err
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	token := strings.ToLower(base32.StdEncoding.EncodeToString(b[:]))
+	h := &Hub{ln: ln, token: token, viewers: map[*viewer]struct{}{}}
+	go h.serve()
+	return h, nil
+}
+
+// AllInterfaces reports whether the TCP address addr listens on every
+// interface, as ":7345" or "0.0.0.0:7345" do, rather than on one the
+// host named.
+func AllInterfaces(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsUnspecified()
+}
+
+// Addr is the address viewers connect to.
+func (h *Hub) Addr() net.Addr {
+	return h.ln.Addr()
+}
+
+// Token is what a viewer must send to be let in.
+func (h *Hub) Token() string {
+	return h.token
+}
+
+// Viewers returns how many viewers are connected.
+func (h *Hub) Viewers() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.viewers)
+}
+
+// Close stops accepting viewers and disconnects those connected.
+func (h *Hub) Close() error {
+	h.mu.Lock()
+	h.closed = true
+	for v := range h.viewers {
+		delete(h.viewers, v)
+		v.close()
+	}
+	h.mu.Unlock()
+	return h.ln.Close()
+}
+
+// Publish sends ev to every viewer and records what a later viewer needs
+// of it. A viewer that has fallen too far behind is dropped.
+func (h *Hub) Publish(ev daemon.Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return
+	}
+	h.record(ev)
+	for v := range h.viewers {
+		select {
+		case v.out <- ev:
+		default:
+			delete(h.viewers, v)
+			v.close()
+		}
+	}
+}
+
+// record keeps ev for the backlog. Partials and levels are gone by the
+// time anyone else joins, so only the live viewers see them.
+func (h *Hub) record(ev daemon.Event) {
+	switch ev.Event {
+	case "status":
+		h.switchSession(ev.SessionID)
+		h.status = &ev
+	case "auto_started":
+		h.switchSession(ev.SessionID)
+	case "segment":
+		h.switchSession(ev.SessionID)
+		h.segments = append(h.segments, ev)
+		if len(h.segments) > maxBacklog {
+			h.segments = h.segments[len(h.segments)-maxBacklog:]
+		}
+	case "topics":
+		h.topics = &ev
+	case "pause_state":
+		h.pause = &ev
+	}
+}
+
+// switchSession starts a new backlog when id names a session other than
+// the current one.
+func (h *Hub) switchSession(id string) {
+	if id == "" || id == h.sessionID {
+		return
+	}
+	h.sessionID = id
+	h.segments = nil
+	h.topics = nil
+}
+
+// backlog is what a joining viewer is sent before the live events.
+func (h *Hub) backlog() []daemon.Event {
+	var out []daemon.Event
+	if h.status != nil {
+		st := *h.status
+		st.SessionID = h.sessionID
+		out = append(out, st)
+	}
+	out = append(out, h.segments...)
+	for _, ev := range []*daemon.Event{h.topics, h.pause} {
+		if ev != nil {
+			out = append(out, *ev)
+		}
+	}
+	return out
+}
+
+func (h *Hub) serve() {
+	for {
+		conn, err := h.ln.Accept()
+		if err != nil {
+			return
+		}
+		go h.admit(conn)
+	}
+}
+
+// admit adds conn as a viewer once it presents the token. A wrong one is
+// told so and disconnected.
+func (h *Hub) admit(conn net.Conn) {
+	conn.SetReadDeadline(time.Now().Add(tokenTimeout))
+	line, err := bufio.NewReaderSize(io.LimitReader(conn, maxTokenLine), maxTokenLine).ReadString('\n')
+	conn.SetReadDeadline(time.Time{})
+	if err != nil || subtle.ConstantTimeCompare([]byte(strings.TrimSpace(line)), []byte(h.token)) != 1 {
+		if err == nil {
+			v := &viewer{conn: conn}
+			h.send(v, daemon.Event{Event: "error", Message: "wrong share token"})
+		}
+		conn.Close()
+		return
+	}
+
+	v := &viewer{conn: conn, out: make(chan daemon.Event, viewerQueue)}
+	h.mu.Lock()
+	if h.closed {
+		h.mu.Unlock()
+		conn.Close()
+		return
+	}
+	// The backlog is taken with the viewer added, so no event falls
+	// between the two.
+	backlog := h.backlog()
+	h.viewers[v] = struct{}{}
+	h.mu.Unlock()
+	h.write(v, backlog)
+}
+
+// write sends v the backlog, then the live events until v is dropped or
+// a write fails.
+func (h *Hub) write(v *viewer, backlog []daemon.Event) {
+	defer h.drop(v)
+	for _, ev := range backlog {
+		if !h.send(v, ev) {
+			return
+		}
+	}
+	for ev := range v.out {
+		if !h.send(v, ev) {
+			return
+		}
+	}
+}
+
+func (h *Hub) send(v *viewer, ev daemon.Event) bool {
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return true
+	}
+	v.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	_, err = v.conn.Write(append(data, '\n'))
+	return err == nil
+}
+
+func (h *Hub) drop(v *viewer) {
+	h.mu.Lock()
+	delete(h.viewers, v)
+	h.mu.Unlock()
+	v.close()
+}
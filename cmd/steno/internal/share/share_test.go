@@ -0,0 +1,152 @@
+package share
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jwulff/steno/internal/daemon"
+)
+
+func listen(t *testing.T) *Hub {
+	t.Helper()
+	h, err := Listen("127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { h.Close() })
+	return h
+}
+
+func view(t *testing.T, h *Hub) *daemon.Client {
+	t.Helper()
+	c, err := daemon.ConnectTCP(h.Addr().String(), h.Token())
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+func segment(session string, seq int, text string) daemon.Event {
+	return daemon.Event{Event: "segment", SessionID: session, SequenceNumber: &seq, Text: text}
+}
+
+func waitViewers(t *testing.T, h *Hub, n int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for h.Viewers() != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("viewers = %d, want %d", h.Viewers(), n)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestReplaysSessionToNewViewer(t *testing.T) {
+	h := listen(t)
+	h.Publish(segment("sess-1", 1, "from the old session"))
+	h.Publish(daemon.Event{Event: "status", Recording: daemon.BoolPtr(true), SessionID: "sess-2"})
+	h.Publish(segment("sess-2", 1, "hello"))
+	h.Publish(daemon.Event{Event: "partial", Text: "wor"})
+	h.Publish(daemon.Event{Event: "topics", Topics: []daemon.EventTopic{{ID: "t-1", Title: "Greetings"}}})
+	h.Publish(daemon.Event{Event: "status", Recording: daemon.BoolPtr(true)})
+
+	c := view(t, h)
+	var got []daemon.Event
+	for range 3 {
+		ev, err := c.ReadEvent()
+		if err != nil {
+			t.Fatalf("read: %v", err)
+		}
+		got = append(got, ev)
+	}
+	if got[0].Event != "status" || got[0].SessionID != "sess-2" {
+		t.Errorf("first = %+v, want the status naming the session", got[0])
+	}
+	if got[1].Event != "segment" || got[1].Text != "hello" {
+		t.Errorf("second = %+v, want only the current session's segment", got[1])
+	}
+	if got[2].Event != "topics" || len(got[2].Topics) != 1 {
+		t.Errorf("third = %+v, want the topic list", got[2])
+	}
+
+	// Live events follow the backlog.
+	waitViewers(t, h, 1)
+	h.Publish(segment("sess-2", 2, "again"))
+	ev, err := c.ReadEvent()
+	if err != nil || ev.Text != "again" {
+		t.Errorf("live event = %+v, %v", ev, err)
+	}
+}
+
+func TestBacklogIsCapped(t *testing.T) {
+	h := listen(t)
+	for i := 1; i <= maxBacklog+10; i++ {
+		h.Publish(segment("sess-1", i, "x"))
+	}
+	backlog := h.backlog()
+	if len(backlog) != maxBacklog {
+		t.Fatalf("backlog = %d, want %d", len(backlog), maxBacklog)
+	}
+	if *backlog[0].SequenceNumber != 11 {
+		t.Errorf("oldest = %d, want the oldest dropped first", *backlog[0].SequenceNumber)
+	}
+}
+
+func TestDropsSlowViewer(t *testing.T) {
+	h := listen(t)
+	view(t, h) // never reads
+	waitViewers(t, h, 1)
+	// Fill the socket buffers and the queue behind them.
+	text := string(make([]byte, 64*1024))
+	for i := 0; i < viewerQueue*8 && h.Viewers() > 0; i++ {
+		h.Publish(daemon.Event{Event: "partial", Text: text})
+	}
+	waitViewers(t, h, 0)
+}
+
+func TestCloseDisconnectsViewers(t *testing.T) {
+	h := listen(t)
+	c := view(t, h)
+	waitViewers(t, h, 1)
+	h.Close()
+	if _, err := c.ReadEvent(); err == nil {
+		t.Error("a viewer should be disconnected when the hub closes")
+	}
+	h.Publish(segment("sess-1", 1, "after close")) // no panic
+}
+
+func TestRefusesWrongToken(t *testing.T) {
+	h := listen(t)
+	c, err := daemon.ConnectTCP(h.Addr().String(), "not-the-token")
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer c.Close()
+	ev, err := c.ReadEvent()
+	if err != nil || ev.Event != "error" {
+		t.Fatalf("first = %+v, %v, want an error event", ev, err)
+	}
+	if _, err := c.ReadEvent(); err == nil {
+		t.Error("a viewer with the wrong token should be disconnected")
+	}
+	h.Publish(segment("sess-1", 1, "secret"))
+	if h.Viewers() != 0 {
+		t.Errorf("viewers = %d, want none let in", h.Viewers())
+	}
+}
+
+func TestAllInterfaces(t *testing.T) {
+	for addr, want := range map[string]bool{
+		":7345":              true,
+		"0.0.0.0:7345":       true,
+		"[::]:7345":          true,
+		"127.0.0.1:7345":     false,
+		"192.168.1.5:7345":   false,
+		"notetaker.lan:7345": false,
+	} {
+		if got := AllInterfaces(addr); got != want {
+			t.Errorf("AllInterfaces(%q) = %v, want %v", addr, got, want)
+		}
+	}
+}
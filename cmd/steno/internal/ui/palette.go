@@ -0,0 +1,164 @@
+package ui
+
+import (
+	"slices"
+	"sort"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Palette is the set of colors the styles are built from. The names are
+// roles rather than hues: Red marks recording and errors, Green the mic
+// and healthy levels, White panel titles, and a palette may pick any
+// color for each, as the light and color-blind presets do.
+type Palette struct {
+	Red, Green, Yellow, Cyan      lipgloss.Color
+	Gray, DimGray, White, Magenta lipgloss.Color
+	Speakers                      []lipgloss.Color
+	// Symbols turns on SymbolCues, for palettes that don't tell red from
+	// green.
+	Symbols bool
+}
+
+// Preset palette names accepted by UsePalette and config
+// display.palette, in the order the TUI's theme key cycles them.
+const (
+	DefaultPalette      = "default"
+	DarkPalette         = "dark"
+	LightPalette        = "light"
+	DeuteranopiaPalette = "deuteranopia"
+	ProtanopiaPalette   = "protanopia"
+)
+
+// Presets lists the preset palettes, in cycling order.
+var Presets = []string{DefaultPalette, DarkPalette, LightPalette, DeuteranopiaPalette, ProtanopiaPalette}
+
+// presets maps names to colors. dark softens the default's pure hues,
+// and light darkens them (and "white" to near-black) for light terminal
+// backgrounds, where pure yellow and green can't be read. The
+// color-blind presets never pair red with green: "red" (recording,
+// errors) and "green" (mic, healthy levels) become orange and blue,
+// which stay apart under both red-green deficiencies. Protanopes see
+// long-wavelength red as dark, so their warning color is a brighter
+// vermillion.
+var presets = map[string]Palette{
+	DefaultPalette: {
+		Red: "#FF0000", Green: "#00FF00", Yellow: "#FFFF00", Cyan: "#00FFFF",
+		Gray: "#666666", DimGray: "#444444", White: "#FFFFFF", Magenta: "#FF00FF",
+		Speakers: []lipgloss.Color{"#5FAFFF", "#FF8700", "#D787FF", "#5FD7AF", "#FF5F87", "#D7D75F"},
+	},
+	DarkPalette: {
+		Red: "#FF5F5F", Green: "#87D75F", Yellow: "#FFD75F", Cyan: "#5FD7FF",
+		Gray: "#8A8A8A", DimGray: "#585858", White: "#EEEEEE", Magenta: "#D787D7",
+		Speakers: []lipgloss.Color{"#5FAFFF", "#FFAF5F", "#D7AFFF", "#87D7AF", "#FF87AF", "#D7D787"},
+	},
+	LightPalette: {
+		Red: "#C4001A", Green: "#007A33", Yellow: "#8A5A00", Cyan: "#005F87",
+		Gray: "#6C6C6C", DimGray: "#A8A8A8", White: "#1C1C1C", Magenta: "#870087",
+		Speakers: []lipgloss.Color{"#005FAF", "#AF5F00", "#8700AF", "#00875F", "#AF005F", "#5F5F00"},
+	},
+	DeuteranopiaPalette: {
+		Red: "#E66100", Green: "#5D9CEC", Yellow: "#F0E442", Cyan: "#56B4E9",
+		Gray: "#808080", DimGray: "#505050", White: "#FFFFFF", Magenta: "#CC79A7",
+		Speakers: []lipgloss.Color{"#0072B2", "#E69F00", "#CC79A7", "#56B4E9", "#F0E442", "#FFFFFF"},
+		Symbols:  true,
+	},
+	ProtanopiaPalette: {
+		Red: "#FE6100", Green: "#648FFF", Yellow: "#FFB000", Cyan: "#56B4E9",
+		Gray: "#808080", DimGray: "#505050", White: "#FFFFFF", Magenta: "#785EF0",
+		Speakers: []lipgloss.Color{"#648FFF", "#FFB000", "#785EF0", "#56B4E9", "#FE6100", "#FFFFFF"},
+		Symbols:  true,
+	},
+}
+
+// custom holds the user-defined palettes, by name. See DefinePalettes.
+var custom = map[string]Palette{}
+
+// current names the palette in use: "" when color is off.
+var current = DefaultPalette
+
+// PaletteName returns the name of the palette the styles were last built
+// from, or "" after DisableColor.
+func PaletteName() string {
+	return current
+}
+
+// Preset returns the named preset palette.
+func Preset(name string) (Palette, bool) {
+	p, ok := presets[name]
+	return p, ok
+}
+
+// DefinePalettes replaces the user-defined palettes with defs. A name
+// shared with a preset is ignored.
+func DefinePalettes(defs map[string]Palette) {
+	custom = map[string]Palette{}
+	for name, p := range defs {
+		if _, ok := presets[name]; !ok {
+			custom[name] = p
+		}
+	}
+}
+
+// PaletteNames lists every palette UsePalette accepts: the presets, then
+// the user-defined ones by name.
+func PaletteNames() []string {
+	names := slices.Clone(Presets)
+	var defined []string
+	for name := range custom {
+		defined = append(defined, name)
+	}
+	sort.Strings(defined)
+	return append(names, defined...)
+}
+
+// noColor is every color unset: lipgloss then emits attributes (bold,
+// underline, reverse) but no color codes.
+var noColor = Palette{Speakers: make([]lipgloss.Color, len(SpeakerGlyphs))}
+
+// SymbolCues is true when color alone can't be relied on: a color-blind
+// palette, or no color at all. Renderers then add a glyph wherever the
+// default palette tells things apart by color only.
+var SymbolCues bool
+
+// SpeakerGlyphs fill a speaker's level meter under SymbolCues, indexed
+// like SpeakerStyles, so two speakers differ in shape as well as hue.
+var SpeakerGlyphs = []string{"█", "▓", "▒", "▞", "■", "▚"}
+
+// UsePalette rebuilds every style from the named palette, a preset or a
+// user-defined one. It reports false, leaving the styles alone, for an
+// unknown name.
+func UsePalette(name string) bool {
+	p, ok := presets[name]
+	if !ok {
+		if p, ok = custom[name]; !ok {
+			return false
+		}
+	}
+	SymbolCues = p.Symbols
+	current = name
+	buildStyles(p)
+	return true
+}
+
+// DisableColor rebuilds every style without color, for `--no-color` and
+// NO_COLOR.
+func DisableColor() {
+	SymbolCues = true
+	current = ""
+	buildStyles(noColor)
+}
+
+// Marker leads a row or panel title under SymbolCues: "▸ " when it's
+// the selected or focused one, blank padding otherwise. Empty without
+// SymbolCues, where color marks the selection.
+func Marker(active bool) string {
+	switch {
+	case !SymbolCues:
+		return ""
+	case active:
+		return "▸ "
+	default:
+		return "  "
+	}
+}
@@ -0,0 +1,32 @@
+package ui
+
+import "strings"
+
+// sgrReset ends every span lipgloss renders, which would also end an
+// enclosing style; Unfocused re-opens its own after each one.
+const sgrReset = "\x1b[0m"
+
+// PanelTitle renders a side-by-side panel's title. The focused panel's
+// is underlined in the active color, and led by Marker under SymbolCues,
+// so focus doesn't rest on the title color alone.
+func PanelTitle(title string, focused bool) string {
+	if focused {
+		return PanelTitleFocusedStyle.Render(Marker(true) + title)
+	}
+	return PanelTitleStyle.Render(Marker(false) + title)
+}
+
+// Unfocused dims the rendered lines of a panel that doesn't have focus,
+// through any styling already inside them. Lines come back unchanged
+// when the terminal shows no styling.
+func Unfocused(lines []string) []string {
+	open, _, _ := strings.Cut(UnfocusedPanelStyle.Render("x"), "x")
+	if open == "" {
+		return lines
+	}
+	out := make([]string, len(lines))
+	for i, l := range lines {
+		out[i] = open + strings.ReplaceAll(l, sgrReset, sgrReset+open) + sgrReset
+	}
+	return out
+}
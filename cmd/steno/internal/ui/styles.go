@@ -2,150 +2,295 @@ package ui
 
 import "github.com/charmbracelet/lipgloss"
 
-// Colors used throughout the TUI.
+// Colors used throughout the TUI, set from the palette by UsePalette.
 var (
-	ColorRed     = lipgloss.Color("#FF0000")
-	ColorGreen   = lipgloss.Color("#00FF00")
-	ColorYellow  = lipgloss.Color("#FFFF00")
-	ColorCyan    = lipgloss.Color("#00FFFF")
-	ColorGray    = lipgloss.Color("#666666")
-	ColorDimGray = lipgloss.Color("#444444")
-	ColorWhite   = lipgloss.Color("#FFFFFF")
-	ColorMagenta = lipgloss.Color("#FF00FF")
+	ColorRed     lipgloss.Color
+	ColorGreen   lipgloss.Color
+	ColorYellow  lipgloss.Color
+	ColorCyan    lipgloss.Color
+	ColorGray    lipgloss.Color
+	ColorDimGray lipgloss.Color
+	ColorWhite   lipgloss.Color
+	ColorMagenta lipgloss.Color
 )
 
-// Base styles reused by UI components.
+// Base styles reused by UI components, built from the palette by
+// UsePalette.
 var (
+	TitleStyle             lipgloss.Style
+	HeaderStyle            lipgloss.Style
+	StatusStyle            lipgloss.Style
+	RecordingDotStyle      lipgloss.Style
+	IdleDotStyle           lipgloss.Style
+	ErrorStyle             lipgloss.Style
+	ErrorTextStyle         lipgloss.Style
+	WarnStyle              lipgloss.Style
+	InfoStyle              lipgloss.Style
+	PartialTextStyle       lipgloss.Style
+	TimestampStyle         lipgloss.Style
+	MicLabelStyle          lipgloss.Style
+	SysLabelStyle          lipgloss.Style
+	PanelTitleStyle        lipgloss.Style
+	PanelTitleActiveStyle  lipgloss.Style
+	PanelTitleFocusedStyle lipgloss.Style
+	UnfocusedPanelStyle    lipgloss.Style
+	SelectedStyle          lipgloss.Style
+	CursorStyle            lipgloss.Style
+	DimStyle               lipgloss.Style
+	FooterKeyStyle         lipgloss.Style
+	FooterDescStyle        lipgloss.Style
+	DividerStyle           lipgloss.Style
+	LevelGreenStyle        lipgloss.Style
+	LevelYellowStyle       lipgloss.Style
+	LevelGrayStyle         lipgloss.Style
+	SpeakerStyles          []lipgloss.Style
+	LiveBadgeStyle         lipgloss.Style
+	ScrollBadgeStyle       lipgloss.Style
+	SpinnerStyle           lipgloss.Style
+	MagentaStyle           lipgloss.Style
+	PausedStyle            lipgloss.Style
+	RecoveringStyle        lipgloss.Style
+	FailedStyle            lipgloss.Style
+	DisconnectedStyle      lipgloss.Style
+	LastSegWarnStyle       lipgloss.Style
+	BackfillStyle          lipgloss.Style
+	SearchMatchStyle       lipgloss.Style
+	DecisionStyle          lipgloss.Style
+	EntityStyle            lipgloss.Style
+	HealMarkerStyle        lipgloss.Style
+	FirstLaunchBannerStyle lipgloss.Style
+	ConsentBannerStyle     lipgloss.Style
+	ErrorModalStyle        lipgloss.Style
+	CaptionTextStyle       lipgloss.Style
+	CaptionPartialStyle    lipgloss.Style
+	FlashStyle             lipgloss.Style
+	SessionBrowserStyle    lipgloss.Style
+)
+
+func init() { UsePalette(DefaultPalette) }
+
+// buildStyles (re)derives every style from p.
+func buildStyles(p Palette) {
+	ColorRed, ColorGreen, ColorYellow, ColorCyan = p.Red, p.Green, p.Yellow, p.Cyan
+	ColorGray, ColorDimGray, ColorWhite, ColorMagenta = p.Gray, p.DimGray, p.White, p.Magenta
+
 	TitleStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(ColorCyan)
+		Bold(true).
+		Foreground(ColorCyan)
 
 	HeaderStyle = lipgloss.NewStyle().
-			Foreground(ColorCyan)
+		Foreground(ColorCyan)
 
 	StatusStyle = lipgloss.NewStyle().
-			Foreground(ColorGray)
+		Foreground(ColorGray)
 
 	RecordingDotStyle = lipgloss.NewStyle().
-				Foreground(ColorRed).
-				Bold(true)
+		Foreground(ColorRed).
+		Bold(true)
 
 	IdleDotStyle = lipgloss.NewStyle().
-			Foreground(ColorGray)
+		Foreground(ColorGray)
 
 	ErrorStyle = lipgloss.NewStyle().
-			Foreground(ColorRed).
-			Bold(true)
+		Foreground(ColorRed).
+		Bold(true)
 
 	ErrorTextStyle = lipgloss.NewStyle().
-			Foreground(ColorRed)
+		Foreground(ColorRed)
+
+	// WarnStyle / InfoStyle: error-bar prefixes for the lower severities.
+	WarnStyle = lipgloss.NewStyle().
+		Foreground(ColorYellow).
+		Bold(true)
+
+	InfoStyle = lipgloss.NewStyle().
+		Foreground(ColorCyan).
+		Bold(true)
 
 	PartialTextStyle = lipgloss.NewStyle().
-				Foreground(ColorYellow)
+		Foreground(ColorYellow)
 
 	TimestampStyle = lipgloss.NewStyle().
-			Foreground(ColorGray)
+		Foreground(ColorGray)
 
 	MicLabelStyle = lipgloss.NewStyle().
-			Foreground(ColorGreen)
+		Foreground(ColorGreen)
 
 	SysLabelStyle = lipgloss.NewStyle().
-			Foreground(ColorCyan)
+		Foreground(ColorCyan)
 
 	PanelTitleStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(ColorWhite)
+		Bold(true).
+		Foreground(ColorWhite)
 
 	PanelTitleActiveStyle = lipgloss.NewStyle().
-				Bold(true).
-				Foreground(ColorCyan)
+		Bold(true).
+		Foreground(ColorCyan)
+
+	// PanelTitleFocusedStyle / UnfocusedPanelStyle: focus cues for the
+	// topics and transcript panels. See PanelTitle and Unfocused.
+	PanelTitleFocusedStyle = PanelTitleActiveStyle.
+		Underline(true)
+
+	UnfocusedPanelStyle = lipgloss.NewStyle().
+		Faint(true)
 
 	SelectedStyle = lipgloss.NewStyle().
-			Foreground(ColorCyan).
-			Bold(true)
+		Foreground(ColorCyan).
+		Bold(true)
+
+	// CursorStyle: the gutter bar beside the transcript's current
+	// segment.
+	CursorStyle = lipgloss.NewStyle().
+		Foreground(ColorCyan).
+		Bold(true)
 
 	DimStyle = lipgloss.NewStyle().
-			Foreground(ColorGray)
+		Foreground(ColorGray)
 
 	FooterKeyStyle = lipgloss.NewStyle().
-			Foreground(ColorYellow).
-			Bold(true)
+		Foreground(ColorYellow).
+		Bold(true)
 
 	FooterDescStyle = lipgloss.NewStyle().
-			Foreground(ColorGray)
+		Foreground(ColorGray)
 
 	DividerStyle = lipgloss.NewStyle().
-			Foreground(ColorDimGray)
+		Foreground(ColorDimGray)
 
 	LevelGreenStyle = lipgloss.NewStyle().
-			Foreground(ColorGreen)
+		Foreground(ColorGreen)
 
 	LevelYellowStyle = lipgloss.NewStyle().
-				Foreground(ColorYellow)
+		Foreground(ColorYellow)
 
 	LevelGrayStyle = lipgloss.NewStyle().
-			Foreground(ColorGray)
+		Foreground(ColorGray)
+
+	// SpeakerStyles tint the level meter of the active diarized speaker.
+	// A speaker keeps one color, picked by hashing their label.
+	SpeakerStyles = make([]lipgloss.Style, len(p.Speakers))
+	for i, c := range p.Speakers {
+		SpeakerStyles[i] = lipgloss.NewStyle().Foreground(c)
+	}
 
 	LiveBadgeStyle = lipgloss.NewStyle().
-			Foreground(ColorGreen).
-			Bold(true)
+		Foreground(ColorGreen).
+		Bold(true)
 
 	ScrollBadgeStyle = lipgloss.NewStyle().
-				Foreground(ColorYellow).
-				Bold(true)
+		Foreground(ColorYellow).
+		Bold(true)
 
 	SpinnerStyle = lipgloss.NewStyle().
-			Foreground(ColorMagenta)
+		Foreground(ColorMagenta)
 
 	MagentaStyle = lipgloss.NewStyle().
-			Foreground(ColorMagenta).
-			Bold(true)
+		Foreground(ColorMagenta).
+		Bold(true)
 
 	// U9 — health-surface state styles.
 
 	// PausedStyle: blue/cyan paused indicator.
 	PausedStyle = lipgloss.NewStyle().
-			Foreground(ColorCyan).
-			Bold(true)
+		Foreground(ColorCyan).
+		Bold(true)
 
 	// RecoveringStyle: yellow ⚠ for in-progress pipeline restart (transient).
 	RecoveringStyle = lipgloss.NewStyle().
-			Foreground(ColorYellow).
-			Bold(true)
+		Foreground(ColorYellow).
+		Bold(true)
 
 	// FailedStyle: red ✗ for non-transient surrender.
 	FailedStyle = lipgloss.NewStyle().
-			Foreground(ColorRed).
-			Bold(true)
+		Foreground(ColorRed).
+		Bold(true)
 
 	// DisconnectedStyle: gray ◌ for daemon socket lost / TUI-side reconnect.
 	// Visually distinct from RECOVERING (which is daemon-internal).
 	DisconnectedStyle = lipgloss.NewStyle().
-				Foreground(ColorGray).
-				Bold(true)
+		Foreground(ColorGray).
+		Bold(true)
 
-	// LastSegWarnStyle: yellow text for the "last segment Ns ago" annotation
+	// LastSegWarnStyle: yellow text for the "last heard Ns ago" annotation
 	// when N >= 60s while not paused.
 	LastSegWarnStyle = lipgloss.NewStyle().
-				Foreground(ColorYellow)
+		Foreground(ColorYellow)
+
+	// BackfillStyle: briefly marks a late segment inserted above the
+	// transcript's tail.
+	BackfillStyle = lipgloss.NewStyle().
+		Foreground(ColorCyan).
+		Underline(true)
+
+	// SearchMatchStyle: a transcript word matched by the keyword filter.
+	SearchMatchStyle = lipgloss.NewStyle().
+		Foreground(ColorYellow).
+		Bold(true).
+		Underline(true)
+
+	// DecisionStyle: the ✓ leading each entry in the decisions lane.
+	DecisionStyle = lipgloss.NewStyle().
+		Foreground(ColorGreen).
+		Bold(true)
+
+	// EntityStyle: a date, time, amount or link in transcript text. The
+	// underline carries it without color.
+	EntityStyle = lipgloss.NewStyle().
+		Foreground(ColorMagenta).
+		Underline(true)
 
 	// HealMarkerStyle: dim yellow inline annotation in the segment timeline.
 	HealMarkerStyle = lipgloss.NewStyle().
-			Foreground(ColorYellow).
-			Italic(true)
+		Foreground(ColorYellow).
+		Italic(true)
 
 	// FirstLaunchBannerStyle: cyan banner for the consent disclosure on
 	// first launch.
 	FirstLaunchBannerStyle = lipgloss.NewStyle().
-				Foreground(ColorCyan).
-				Bold(true).
-				Border(lipgloss.NormalBorder()).
-				BorderForeground(ColorCyan).
-				Padding(0, 1)
+		Foreground(ColorCyan).
+		Bold(true).
+		Border(lipgloss.NormalBorder()).
+		BorderForeground(ColorCyan).
+		Padding(0, 1)
+
+	// ConsentBannerStyle: yellow banner for the per-session recording
+	// consent reminder.
+	ConsentBannerStyle = lipgloss.NewStyle().
+		Foreground(ColorYellow).
+		Border(lipgloss.NormalBorder()).
+		BorderForeground(ColorYellow).
+		Padding(0, 1)
 
 	// ErrorModalStyle: bordered overlay for the `e` error-history modal.
 	ErrorModalStyle = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(ColorRed).
-			Padding(0, 1)
-)
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(ColorRed).
+		Padding(0, 1)
+
+	// CaptionTextStyle / CaptionPartialStyle: bold segment text for the
+	// captions density mode, read from across a room rather than up close.
+	CaptionTextStyle = lipgloss.NewStyle().
+		Bold(true)
+
+	CaptionPartialStyle = lipgloss.NewStyle().
+		Foreground(ColorYellow).
+		Bold(true)
+
+	// FlashStyle: the dividers during an alert flash.
+	FlashStyle = lipgloss.NewStyle().
+		Background(ColorRed).
+		Foreground(ColorWhite).
+		Bold(true)
+
+	// SessionBrowserStyle: bordered overlay for the `b` session browser.
+	SessionBrowserStyle = lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(ColorCyan).
+		Padding(0, 1)
+
+	if p.Red == "" {
+		// No color: the flash needs something to show.
+		FlashStyle = FlashStyle.Reverse(true)
+	}
+}
@@ -0,0 +1,94 @@
+// Package version reports the steno binary's version and compares it with
+// the daemon's (`version` protocol command).
+package version
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+)
+
+// Version is the release version of the Go binary. Kept in step with the
+// daemon's BuildInfo.version; bumped together at release time.
+var Version = "0.1.0"
+
+// Build describes how this binary was built: VCS revision (with a
+// "-dirty" suffix for modified trees) and Go toolchain, as recorded by
+// `go build`. Empty fields are omitted.
+func Build() string {
+	parts := []string{runtime.Version()}
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return parts[0]
+	}
+	var rev string
+	var dirty bool
+	for _, s := range info.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			rev = s.Value
+		case "vcs.modified":
+			dirty = s.Value == "true"
+		}
+	}
+	if rev != "" {
+		if len(rev) > 12 {
+			rev = rev[:12]
+		}
+		if dirty {
+			rev += "-dirty"
+		}
+		parts = append([]string{rev}, parts...)
+	}
+	return strings.Join(parts, " ")
+}
+
+// MinorSkew reports whether a and b differ in major or minor version
+// ("0.1.3" vs "0.2.0"). Patch differences are compatible. Unparseable or
+// empty versions never count as skew: a daemon that predates the
+// `version` command is reported as unknown instead.
+func MinorSkew(a, b string) bool {
+	amaj, amin, ok1 := majorMinor(a)
+	bmaj, bmin, ok2 := majorMinor(b)
+	if !ok1 || !ok2 {
+		return false
+	}
+	return amaj != bmaj || amin != bmin
+}
+
+func majorMinor(v string) (int, int, bool) {
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	fields := strings.SplitN(v, ".", 3)
+	if len(fields) < 2 {
+		return 0, 0, false
+	}
+	major, err1 := strconv.Atoi(fields[0])
+	minor, err2 := strconv.Atoi(fields[1])
+	if err1 != nil || err2 != nil {
+		return 0, 0, false
+	}
+	return major, minor, true
+}
+
+// ProtocolSkew reports whether the TUI and daemon speak different
+// protocol revisions. A daemon that predates versioning (0) is unknown,
+// not skewed, as with MinorSkew.
+func ProtocolSkew(tui, daemon int) bool {
+	return daemon != 0 && daemon != tui
+}
+
+// ProtocolWarning is the one-line warning shown when ProtocolSkew(tui,
+// daemon): which side is behind, and what still works.
+func ProtocolWarning(tui, daemon int) string {
+	if daemon > tui {
+		return fmt.Sprintf("steno-daemon speaks protocol %d, newer than this steno's %d: fields steno doesn't know are ignored; upgrade steno with `make install`", daemon, tui)
+	}
+	return fmt.Sprintf("steno-daemon speaks protocol %d, older than this steno's %d: newer features may not respond; upgrade the daemon with `make install`", daemon, tui)
+}
+
+// SkewWarning is the one-line warning shown when MinorSkew(tui, daemon).
+func SkewWarning(tui, daemon string) string {
+	return fmt.Sprintf("steno %s and steno-daemon %s differ in minor version; reinstall both with `make install`", tui, daemon)
+}
@@ -0,0 +1,43 @@
+package version
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMinorSkew(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"0.1.0", "0.1.7", false},
+		{"0.1.0", "0.2.0", true},
+		{"1.2.0", "2.2.0", true},
+		{"v0.3.1", "0.3.0", false},
+		{"0.1.0", "", false},
+		{"0.1.0", "dev", false},
+	}
+	for _, tt := range tests {
+		if got := MinorSkew(tt.a, tt.b); got != tt.want {
+			t.Errorf("MinorSkew(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestProtocolSkew(t *testing.T) {
+	if ProtocolSkew(2, 2) || ProtocolSkew(2, 0) || !ProtocolSkew(2, 3) || !ProtocolSkew(2, 1) {
+		t.Error("only a known, different daemon protocol is skew")
+	}
+	if w := ProtocolWarning(2, 3); !strings.Contains(w, "newer") || !strings.Contains(w, "upgrade steno") {
+		t.Errorf("newer daemon: %q", w)
+	}
+	if w := ProtocolWarning(2, 1); !strings.Contains(w, "older") || !strings.Contains(w, "upgrade the daemon") {
+		t.Errorf("older daemon: %q", w)
+	}
+}
+
+func TestBuildIncludesToolchain(t *testing.T) {
+	if Build() == "" {
+		t.Error("Build() should never be empty")
+	}
+}
@@ -0,0 +1,187 @@
+// Package web serves a small read-only HTML viewer over the steno
+// database (`steno serve --web`). Pages are server-rendered from embedded
+// templates; there is no JavaScript build and no write path.
+package web
+
+import (
+	"embed"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jwulff/steno/internal/db"
+	"github.com/jwulff/steno/internal/scrub"
+)
+
+//go:embed templates/*.html
+var templateFS embed.FS
+
+const (
+	// sessionListLimit caps the index page.
+	sessionListLimit = 200
+	// segmentPageLimit caps one transcript page. Longer sessions are
+	// paged with ?after=, the last sequence number shown.
+	segmentPageLimit = 2000
+	// searchLimit caps search results, global and per-session.
+	searchLimit = 200
+)
+
+// Server is an http.Handler for the viewer. It only ever reads from the
+// store; every route is GET-only.
+type Server struct {
+	store    *db.Store
+	scrubber *scrub.Scrubber
+	tmpl     *template.Template
+	mux      *http.ServeMux
+}
+
+// New returns a Server reading from store. scrubber may be nil; when set,
+// transcript, topic and summary text is masked before rendering, exactly
+// as the TUI does for shared screens.
+func New(store *db.Store, scrubber *scrub.Scrubber) *Server {
+	s := &Server{store: store, scrubber: scrubber, mux: http.NewServeMux()}
+	s.tmpl = template.Must(template.New("").Funcs(template.FuncMap{
+		"scrub":    s.scrubber.Apply,
+		"datetime": func(t time.Time) string { return t.Local().Format("2006-01-02 15:04") },
+		"clock":    func(t time.Time) string { return t.Local().Format("15:04:05") },
+		"duration": formatDuration,
+	}).ParseFS(templateFS, "templates/*.html"))
+
+	s.mux.HandleFunc("GET /{$}", s.handleSessions)
+	s.mux.HandleFunc("GET /sessions/{id}", s.handleSession)
+	s.mux.HandleFunc("GET /search", s.handleSearch)
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+type sessionsPage struct {
+	Sessions []db.SessionWithCounts
+}
+
+func (s *Server) handleSessions(w http.ResponseWriter, r *http.Request) {
+	sessions, err := s.store.ListSessions(sessionListLimit, nil, nil, "")
+	if err != nil {
+		s.serverError(w, err)
+		return
+	}
+	s.render(w, "sessions.html", sessionsPage{Sessions: sessions})
+}
+
+type sessionPage struct {
+	Session   *db.Session
+	Query     string
+	Segments  []db.Segment
+	Topics    []db.Topic
+	Summary   *db.Summary
+	NextPage  int // ?after= for the next page; 0 when this is the last page
+	Searching bool
+}
+
+func (s *Server) handleSession(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	sess, err := s.store.GetSession(id)
+	if err != nil {
+		s.serverError(w, err)
+		return
+	}
+	if sess == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	page := sessionPage{Session: sess, Query: strings.TrimSpace(r.URL.Query().Get("q"))}
+	if page.Query != "" {
+		page.Searching = true
+		segs, err := s.store.SearchSegments(page.Query, id, searchLimit)
+		if err != nil {
+			s.serverError(w, err)
+			return
+		}
+		// SearchSegments is newest-first; a transcript reads oldest-first.
+		for i, j := 0, len(segs)-1; i < j; i, j = i+1, j-1 {
+			segs[i], segs[j] = segs[j], segs[i]
+		}
+		page.Segments = segs
+	} else {
+		after, _ := strconv.Atoi(r.URL.Query().Get("after"))
+		segs, err := s.store.SegmentsAfter(id, after, segmentPageLimit+1)
+		if err != nil {
+			s.serverError(w, err)
+			return
+		}
+		if len(segs) > segmentPageLimit {
+			segs = segs[:segmentPageLimit]
+			page.NextPage = segs[len(segs)-1].SequenceNumber
+		}
+		page.Segments = segs
+	}
+
+	if page.Topics, err = s.store.TopicsForSession(id); err != nil {
+		s.serverError(w, err)
+		return
+	}
+	if page.Summary, err = s.store.LatestSummary(id); err != nil {
+		s.serverError(w, err)
+		return
+	}
+	s.render(w, "session.html", page)
+}
+
+type searchPage struct {
+	Query    string
+	Segments []db.Segment
+	Topics   []db.Topic
+}
+
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	page := searchPage{Query: strings.TrimSpace(r.URL.Query().Get("q"))}
+	if page.Query != "" {
+		var err error
+		if page.Segments, err = s.store.SearchSegments(page.Query, "", searchLimit); err != nil {
+			s.serverError(w, err)
+			return
+		}
+		if page.Topics, err = s.store.SearchTopics(page.Query, searchLimit); err != nil {
+			s.serverError(w, err)
+			return
+		}
+	}
+	s.render(w, "search.html", page)
+}
+
+func (s *Server) render(w http.ResponseWriter, name string, data any) {
+	var buf strings.Builder
+	if err := s.tmpl.ExecuteTemplate(&buf, name, data); err != nil {
+		s.serverError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprint(w, buf.String())
+}
+
+func (s *Server) serverError(w http.ResponseWriter, err error) {
+	http.Error(w, "steno: "+err.Error(), http.StatusInternalServerError)
+}
+
+// formatDuration renders a session length as "1h05m" / "12m" / "45s";
+// empty for sessions that have not ended.
+func formatDuration(start time.Time, end *time.Time) string {
+	if end == nil {
+		return ""
+	}
+	d := end.Sub(start).Round(time.Second)
+	switch {
+	case d >= time.Hour:
+		return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+	case d >= time.Minute:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	default:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	}
+}
@@ -0,0 +1,120 @@
+package web
+
+import (
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jwulff/steno/internal/db"
+	"github.com/jwulff/steno/internal/scrub"
+
+	_ "modernc.org/sqlite"
+)
+
+func testServer(t *testing.T, scrubber *scrub.Scrubber) *Server {
+	t.Helper()
+	d, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	d.SetMaxOpenConns(1)
+	t.Cleanup(func() { d.Close() })
+
+	stmts := []string{
+		`CREATE TABLE sessions (id TEXT PRIMARY KEY, locale TEXT NOT NULL, startedAt REAL NOT NULL, endedAt REAL, title TEXT, status TEXT NOT NULL DEFAULT 'active', createdAt REAL NOT NULL)`,
+		`CREATE TABLE segments (id TEXT PRIMARY KEY, sessionId TEXT NOT NULL, text TEXT NOT NULL, startedAt REAL NOT NULL, endedAt REAL NOT NULL, confidence REAL, sequenceNumber INTEGER NOT NULL, createdAt REAL NOT NULL, source TEXT NOT NULL DEFAULT 'microphone', duplicate_of TEXT)`,
+		`CREATE TABLE topics (id TEXT PRIMARY KEY, sessionId TEXT NOT NULL, title TEXT NOT NULL, summary TEXT NOT NULL, segmentRangeStart INTEGER NOT NULL, segmentRangeEnd INTEGER NOT NULL, createdAt REAL NOT NULL)`,
+		`CREATE TABLE summaries (id TEXT PRIMARY KEY, sessionId TEXT NOT NULL, content TEXT NOT NULL, summaryType TEXT NOT NULL, segmentRangeStart INTEGER NOT NULL, segmentRangeEnd INTEGER NOT NULL, modelId TEXT NOT NULL, createdAt REAL NOT NULL)`,
+		`INSERT INTO sessions (id, locale, startedAt, endedAt, title, status, createdAt) VALUES ('sess-1', 'en_US', 1710000000, 1710003600, 'Team <Standup>: rotate sk-zyxwvutsrqponmlkjihg', 'completed', 1710000000)`,
+		`INSERT INTO segments (id, sessionId, text, startedAt, endedAt, sequenceNumber, createdAt) VALUES ('seg-1', 'sess-1', 'the budget is due Friday', 1710000010, 1710000019, 1, 1710000010)`,
+		`INSERT INTO segments (id, sessionId, text, startedAt, endedAt, sequenceNumber, createdAt) VALUES ('seg-2', 'sess-1', 'my key is sk-abcdefghijklmnopqrstuv', 1710000020, 1710000029, 2, 1710000020)`,
+		`INSERT INTO topics (id, sessionId, title, summary, segmentRangeStart, segmentRangeEnd, createdAt) VALUES ('t-1', 'sess-1', 'Budget', 'Budget deadline', 1, 2, 1710000030)`,
+	}
+	for _, s := range stmts {
+		if _, err := d.Exec(s); err != nil {
+			t.Fatalf("exec %q: %v", s, err)
+		}
+	}
+	return New(db.NewStore(d), scrubber)
+}
+
+func get(t *testing.T, h http.Handler, target string) (int, string) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	return rec.Code, rec.Body.String()
+}
+
+func TestSessionsIndex(t *testing.T) {
+	code, body := get(t, testServer(t, nil), "/")
+	if code != http.StatusOK {
+		t.Fatalf("status = %d", code)
+	}
+	if !strings.Contains(body, `href="/sessions/sess-1"`) {
+		t.Error("index should link to the session")
+	}
+	if !strings.Contains(body, "Team &lt;Standup&gt;") {
+		t.Error("session title should be HTML-escaped")
+	}
+}
+
+func TestSessionTranscriptAndTopics(t *testing.T) {
+	code, body := get(t, testServer(t, nil), "/sessions/sess-1")
+	if code != http.StatusOK {
+		t.Fatalf("status = %d", code)
+	}
+	for _, want := range []string{"the budget is due Friday", "Budget deadline", `id="seg-2"`, "1h00m"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("session page missing %q", want)
+		}
+	}
+
+	// Pages continue after the last segment shown.
+	_, body = get(t, testServer(t, nil), "/sessions/sess-1?after=1")
+	if strings.Contains(body, `id="seg-1"`) || !strings.Contains(body, `id="seg-2"`) {
+		t.Errorf("?after=1 should start at segment 2:\n%s", body)
+	}
+}
+
+func TestSessionSearch(t *testing.T) {
+	_, body := get(t, testServer(t, nil), "/sessions/sess-1?q=budget")
+	if !strings.Contains(body, "due Friday") || strings.Contains(body, "my key is") {
+		t.Errorf("in-session search should keep only matches")
+	}
+
+	_, body = get(t, testServer(t, nil), "/search?q=budget")
+	if !strings.Contains(body, `/sessions/sess-1#seg-1`) || !strings.Contains(body, "Budget deadline") {
+		t.Errorf("global search should link segment and topic hits")
+	}
+}
+
+func TestScrubbedRendering(t *testing.T) {
+	s, err := scrub.New(scrub.DefaultPatterns, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, body := get(t, testServer(t, s), "/sessions/sess-1")
+	if strings.Contains(body, "sk-abcdefghijklmnopqrstuv") || !strings.Contains(body, scrub.DefaultReplacement) {
+		t.Error("token should be masked when a scrubber is set")
+	}
+	// Titles are generated from the transcript, so they're masked too.
+	for _, target := range []string{"/", "/sessions/sess-1"} {
+		if _, body := get(t, testServer(t, s), target); strings.Contains(body, "sk-zyxwvutsrqponmlkjihg") {
+			t.Errorf("%s shows the token in the session title", target)
+		}
+	}
+}
+
+func TestReadOnlyAndNotFound(t *testing.T) {
+	srv := testServer(t, nil)
+	if code, _ := get(t, srv, "/sessions/missing"); code != http.StatusNotFound {
+		t.Errorf("missing session status = %d, want 404", code)
+	}
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST status = %d, want 405", rec.Code)
+	}
+}
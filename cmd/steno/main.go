@@ -1,13 +1,20 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"os"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jwulff/steno/internal/cli"
+	"github.com/jwulff/steno/internal/config"
+	"github.com/jwulff/steno/internal/control"
+	"github.com/jwulff/steno/internal/daemon"
 	"github.com/jwulff/steno/internal/db"
 	stenoMCP "github.com/jwulff/steno/internal/mcp"
+	"github.com/jwulff/steno/internal/share"
+	"github.com/jwulff/steno/internal/ui"
 	"github.com/mark3labs/mcp-go/server"
 
 	"github.com/jwulff/steno/internal/app"
@@ -15,18 +22,90 @@ import (
 
 func main() {
 	mcpMode := flag.Bool("mcp", false, "Run as MCP stdio server (read-only database access)")
+	controlMode := flag.Bool("control-stdin", false, "Run headless: read NDJSON daemon commands on stdin, write responses and events to stdout")
+	scrubMode := flag.Bool("scrub", false, "Mask card numbers, tokens, and config-listed patterns in the transcript (for shared screens)")
+	noColor := flag.Bool("no-color", false, "Render the TUI without color; focus, selection and speakers are marked with symbols")
+	dbOnly := flag.Bool("db-only", false, "Browse recorded sessions from the database without starting or connecting to the daemon")
+	shareAddr := flag.String("share", "", "Share the live view read-only over TCP on this address (e.g. 192.168.1.5:7345), for `steno --view`")
+	shareAll := flag.Bool("share-all-interfaces", false, "Let --share listen on every interface, for an address without a host such as :7345")
+	viewAddr := flag.String("view", "", "Watch the live view another steno shares with --share, at HOST:PORT, instead of running the daemon")
+	viewToken := flag.String("token", "", "The share token the host's footer shows, for --view")
+	exportID := flag.String("export", "", "Print a session's transcript as Markdown and exit (same as `steno export <session-id>`)")
 	flag.Parse()
 
 	if *mcpMode {
 		runMCP()
-	} else {
-		runTUI()
+		return
 	}
+	if *controlMode {
+		runControl()
+		return
+	}
+	if *exportID != "" {
+		os.Exit(cli.Run(cli.DefaultEnv(), []string{"export", *exportID}))
+	}
+
+	noColorMode := *noColor || os.Getenv("NO_COLOR") != ""
+
+	// `steno tutorial` is the TUI itself, on a canned meeting.
+	if args := flag.Args(); len(args) == 1 && args[0] == "tutorial" {
+		runTUI(*scrubMode, noColorMode, false, true, "", false, "", "")
+		return
+	}
+
+	// `steno <command> [--json]` runs a one-shot CLI subcommand instead
+	// of the TUI.
+	if args := flag.Args(); len(args) > 0 {
+		os.Exit(cli.Run(cli.DefaultEnv(), args))
+	}
+
+	runTUI(*scrubMode, noColorMode, *dbOnly, false, *shareAddr, *shareAll, *viewAddr, *viewToken)
 }
 
-func runTUI() {
+func runTUI(forceScrub, noColor, dbOnly, tutorial bool, shareAddr string, shareAll bool, viewAddr, viewToken string) {
+	cfg, err := config.Load(config.Path())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "steno: %v\n", err)
+		os.Exit(1)
+	}
+	if forceScrub {
+		cfg.Scrub.Enabled = true
+	}
+	if palettes, err := cfg.Display.CustomPalettes(); err == nil { // validated by config.Load
+		ui.DefinePalettes(palettes)
+	}
+	if cfg.Display.Palette != "" {
+		ui.UsePalette(cfg.Display.Palette) // validated by config.Load
+	}
+	if noColor || cfg.Display.NoColor {
+		ui.DisableColor()
+	}
+
+	model := app.NewWithConfig(cfg).WithConfigWatch(config.Path(), cfg, noColor, forceScrub)
+	switch {
+	case tutorial:
+		model = model.WithTutorial()
+	case dbOnly:
+		model = model.WithDBOnly()
+	case viewAddr != "":
+		model = model.WithViewer(viewAddr, viewToken)
+	case shareAddr != "":
+		// Anyone who can reach the port and has the token sees the
+		// transcript, so every interface is opt-in.
+		if share.AllInterfaces(shareAddr) && !shareAll {
+			fmt.Fprintf(os.Stderr, "steno: share: %s listens on every interface; name the one to share on (e.g. 192.168.1.5:7345), or pass --share-all-interfaces\n", shareAddr)
+			os.Exit(1)
+		}
+		hub, err := share.Listen(shareAddr)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "steno: share: %v\n", err)
+			os.Exit(1)
+		}
+		defer hub.Close()
+		model = model.WithShare(hub)
+	}
 	p := tea.NewProgram(
-		app.New(),
+		model,
 		tea.WithAltScreen(),
 	)
 
@@ -36,6 +115,19 @@ func runTUI() {
 	}
 }
 
+// runControl bridges stdin/stdout to the daemon for embedding apps,
+// starting the daemon first if needed.
+func runControl() {
+	if err := daemon.NewManager().EnsureRunning(context.Background()); err != nil {
+		fmt.Fprintf(os.Stderr, "steno: %v\n", err)
+		os.Exit(1)
+	}
+	if err := control.Run(os.Stdin, os.Stdout, daemon.SocketPath()); err != nil {
+		fmt.Fprintf(os.Stderr, "steno: %v\n", err)
+		os.Exit(1)
+	}
+}
+
 func runMCP() {
 	dbPath := db.DefaultDBPath()
 	if p := os.Getenv("STENO_DB"); p != "" {
@@ -47,7 +139,11 @@ func runMCP() {
 		os.Exit(1)
 	}
 
-	store, err := db.Open(dbPath)
+	opts, err := config.DatabaseOptions(config.Path())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "steno: warning: %v; opening the database anyway\n", err)
+	}
+	store, err := db.Open(dbPath, opts)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "steno: %v\n", err)
 		os.Exit(1)
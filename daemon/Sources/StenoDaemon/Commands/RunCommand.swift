@@ -13,6 +13,9 @@ struct RunCommand: ParsableCommand {
     @Option(name: .long, help: "Database path (default: ~/Library/Application Support/Steno/steno.sqlite)")
     var dbPath: String?
 
+    @Flag(name: .long, help: "Read the mic from files sent with inject_audio instead of a live device, and don't auto-start (integration tests)")
+    var testMode = false
+
     func run() throws {
         let log = DaemonLogger.daemon
 
@@ -41,6 +44,7 @@ struct RunCommand: ParsableCommand {
 
         let socketPath = self.socketPath
         let dbPath = self.dbPath
+        let testMode = self.testMode
 
         // Launch all async work in a Task, then keep the main RunLoop alive
         // via dispatchMain(). SpeechAnalyzer requires the main RunLoop.
@@ -54,7 +58,9 @@ struct RunCommand: ParsableCommand {
                 let repository = SQLiteTranscriptRepository(dbQueue: dbQueue)
 
                 // 4. Initialize services
-                let permissionService = SystemPermissionService()
+                let permissionService: PermissionService = testMode
+                    ? InjectedAudioPermissionService()
+                    : SystemPermissionService()
                 let summarizer: SummarizationService = FoundationModelSummarizationService()
 
                 // Load settings once and reuse for every wiring step. A
@@ -70,8 +76,33 @@ struct RunCommand: ParsableCommand {
                     minSegmentsForExtraction: settings.topicExtractionMinSegments
                 )
 
-                let audioSourceFactory = DefaultAudioSourceFactory()
-                let speechRecognizerFactory = DefaultSpeechRecognizerFactory()
+                // Test mode swaps the live mic for files pushed with
+                // `inject_audio`, so a CI run can drive the pipeline.
+                let audioInjector = testMode ? InjectedAudioSourceFactory() : nil
+                let audioSourceFactory: AudioSourceFactory = audioInjector ?? DefaultAudioSourceFactory()
+                if testMode {
+                    log.info("Test mode: mic input comes from inject_audio")
+                }
+                // Transcribe on-device unless this Mac can't run
+                // SpeechAnalyzer (or settings ask for remote). Remote
+                // results take the same path to the DB and event stream.
+                let remoteASRKey = settings.effectiveRemoteASRAPIKey ?? ""
+                let backend = settings.transcriptionBackend.resolved(
+                    localAvailable: DefaultSpeechRecognizerFactory.isSupported,
+                    remoteConfigured: !remoteASRKey.isEmpty
+                )
+                if settings.transcriptionBackend == .remote && backend != .remote {
+                    log.error("Remote ASR selected but no API key is set; transcribing on-device")
+                } else if settings.transcriptionBackend == .auto && !DefaultSpeechRecognizerFactory.isSupported && backend != .remote {
+                    log.error("On-device ASR is unavailable on this Mac and no remote ASR key is set")
+                }
+                let speechRecognizerFactory: SpeechRecognizerFactory = switch backend {
+                case .remote:
+                    RemoteSpeechRecognizerFactory(endpoint: settings.remoteASRURL, apiKey: remoteASRKey)
+                case .auto, .local:
+                    DefaultSpeechRecognizerFactory()
+                }
+                log.info("Transcription backend: \(backend.rawValue)")
 
                 // 5. Create engine, broadcaster, dispatcher
                 let broadcaster = EventBroadcaster()
@@ -93,15 +124,23 @@ struct RunCommand: ParsableCommand {
                     speechRecognizerFactory: speechRecognizerFactory,
                     delegate: broadcaster,
                     deviceUIDProvider: { defaultInputDeviceUID() },
+                    foregroundAppProvider: { frontmostApplicationName() },
+                    audioSetupProvider: { AudioSetup.fingerprint(inputDeviceUIDs()) },
                     healThresholdSeconds: settings.healGapSeconds,
                     dedupCoordinator: dedupCoordinator,
                     dedupTriggerDebounce: .seconds(settings.dedupTriggerDebounceSeconds),
                     emptySessionMinChars: settings.emptySessionMinChars,
                     emptySessionMinDurationSeconds: settings.emptySessionMinDurationSeconds,
-                    retentionDays: settings.retentionDays
+                    retentionDays: settings.retentionDays,
+                    summaryDeferral: settings.deferSummaries
                 )
 
-                let dispatcher = CommandDispatcher(engine: engine, broadcaster: broadcaster)
+                let dispatcher = CommandDispatcher(
+                    engine: engine,
+                    broadcaster: broadcaster,
+                    audioInjector: audioInjector,
+                    summarizer: summarizer
+                )
 
                 // U6: register IOKit power observer BEFORE auto-start so
                 // a willSleep arriving during the orphan sweep is
@@ -153,15 +192,53 @@ struct RunCommand: ParsableCommand {
                 // NOT crash the daemon — the engine surfaces the error
                 // (e.g., mic permission denied) and the user can grant
                 // permission and trigger a retry via the TUI. Settings
-                // restore the last-known device + systemAudio choice.
-                do {
-                    _ = try await engine.recoverOrphansAndAutoStart(
-                        locale: .current,
-                        device: settings.lastDevice,
-                        systemAudio: settings.lastSystemAudioEnabled
-                    )
-                } catch {
-                    log.error("Auto-start failed: \(error). Engine remains in error state; awaiting external resume.")
+                // restore the device last used with the devices attached
+                // now (docked vs. not) + the systemAudio choice. Test
+                // mode stays idle: each test starts its own sessions.
+                if testMode {
+                    log.info("Test mode: skipping auto-start")
+                } else {
+                    do {
+                        _ = try await engine.recoverOrphansAndAutoStart(
+                            locale: .current,
+                            device: settings.preferredDevice(forSetup: AudioSetup.fingerprint(inputDeviceUIDs())),
+                            systemAudio: settings.lastSystemAudioEnabled
+                        )
+                    } catch {
+                        log.error("Auto-start failed: \(error). Engine remains in error state; awaiting external resume.")
+                    }
+                }
+
+                // 5c. Watch for conferencing calls, after auto-start so
+                // a call already on at launch splits the recovered
+                // session rather than racing it. A call started from
+                // idle records with the last-known audio config, read
+                // at the time so a start made since is honoured.
+                let callObserver = ConferencingAppObserver()
+                if settings.callDetection != .off {
+                    let autoRecord = settings.callDetection == .auto
+                    callObserver.start { [engine] transition in
+                        let current = StenoSettings.load()
+                        await engine.handleCallTransition(
+                            transition,
+                            autoRecord: autoRecord,
+                            locale: .current,
+                            device: current.preferredDevice(forSetup: AudioSetup.fingerprint(inputDeviceUIDs())),
+                            systemAudio: current.lastSystemAudioEnabled
+                        )
+                    }
+                    log.info("Call detection: \(settings.callDetection.rawValue)")
+                }
+
+                // 5d. Follow thermal and power pressure, reported on
+                // `status` and as `pressure` events, and which holds
+                // summaries back under `deferSummaries`.
+                let pressureObserver = ResourcePressureObserver()
+                pressureObserver.start { [engine] pressure in
+                    await engine.handlePressureChange(pressure)
+                }
+                if settings.deferSummaries != .off {
+                    log.info("Summaries deferred under pressure: \(settings.deferSummaries.rawValue)")
                 }
 
                 // 6. Start socket server
@@ -190,6 +267,8 @@ struct RunCommand: ParsableCommand {
                 // 8. Graceful shutdown
                 powerObserver.stop()
                 deviceObserver.stop()
+                callObserver.stop()
+                pressureObserver.stop()
                 await engine.stop()
                 await server.stop()
                 pidFile.release()
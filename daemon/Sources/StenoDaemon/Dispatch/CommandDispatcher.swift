@@ -5,15 +5,29 @@ public actor CommandDispatcher {
     private let engine: RecordingEngine
     private let broadcaster: EventBroadcaster
 
+    /// The test-mode mic source that `inject_audio` feeds. `nil` outside
+    /// `run --test-mode`, where the command is refused.
+    private let audioInjector: InjectedAudioSourceFactory?
+
+    /// The LLM behind `clean_verbatim`. `nil` refuses the command.
+    private let summarizer: SummarizationService?
+
     /// Default auto-resume window for `pause` commands that omit both
     /// `autoResumeSeconds` and `indefinite`. 30 minutes matches the
     /// plan's UX choice and is intentionally explicit (not a magic
     /// number sprinkled in the engine).
     public static let defaultPauseAutoResumeSeconds: Double = 1800
 
-    public init(engine: RecordingEngine, broadcaster: EventBroadcaster) {
+    public init(
+        engine: RecordingEngine,
+        broadcaster: EventBroadcaster,
+        audioInjector: InjectedAudioSourceFactory? = nil,
+        summarizer: SummarizationService? = nil
+    ) {
         self.engine = engine
         self.broadcaster = broadcaster
+        self.audioInjector = audioInjector
+        self.summarizer = summarizer
     }
 
     /// Handle a command from a client and send a response.
@@ -21,7 +35,7 @@ public actor CommandDispatcher {
         _ command: DaemonCommand,
         from client: any ClientConnection
     ) async {
-        let response: DaemonResponse
+        var response: DaemonResponse
 
         switch command.cmd {
         case "start":
@@ -48,14 +62,57 @@ public actor CommandDispatcher {
         case "demarcate":
             response = await handleDemarcate()
 
+        case "set_locale":
+            response = await handleSetLocale(command)
+
+        case "arm":
+            response = await handleArm(command)
+
+        case "disarm":
+            await engine.disarm()
+            response = DaemonResponse(ok: true, armed: false)
+
+        case "version":
+            // A client on another protocol still gets an answer; it
+            // decides how to degrade.
+            response = DaemonResponse(
+                ok: true,
+                version: BuildInfo.version,
+                build: BuildInfo.build,
+                protocolVersion: BuildInfo.protocolVersion
+            )
+
+        case "inject_audio":
+            response = await handleInjectAudio(command)
+
+        case "clean_verbatim":
+            response = await handleCleanVerbatim(command)
+
         default:
             response = DaemonResponse.failure("Unknown command: \(command.cmd)")
         }
 
+        // Commands are handled concurrently, so responses can go out of
+        // order; the echoed id pairs each with its command.
+        response.id = command.id
+
         // Send response
         if let data = try? JSONEncoder().encode(response) {
             try? await client.send(data + Data("\n".utf8))
         }
+
+        // A resubscribing client's missed segments follow the response,
+        // so it reads them as events.
+        if command.cmd == "subscribe", let since = command.sinceSessionId {
+            var missed: [StoredSegment] = []
+            if let sessionId = UUID(uuidString: since) {
+                missed = await engine.segmentsMissed(
+                    sinceSessionId: sessionId,
+                    sinceSequence: command.sinceSequence ?? 0
+                )
+            }
+            await broadcaster.replay(missed, to: client.id)
+        }
     }
 
     // MARK: - Command Handlers
@@ -93,9 +150,16 @@ public actor CommandDispatcher {
         let status = await engine.status
         let session = await engine.currentSession
         let segments = await engine.segmentCount
+        let lastSegmentAt = await engine.lastSegmentAt
         let device = await engine.currentDevice
         let systemAudio = await engine.isSystemAudioEnabled
         let pause = await engine.pauseStateSnapshot()
+        let foregroundApp = await engine.sessionForegroundApp
+        let locale = await engine.currentLocale
+        let armed = await engine.isArmed
+        let callApp = await engine.sessionCallApp
+        let pressure = await engine.pressure
+        let summariesDeferred = await engine.summariesDeferred
 
         return DaemonResponse(
             ok: true,
@@ -107,10 +171,66 @@ public actor CommandDispatcher {
             systemAudio: systemAudio,
             paused: pause.paused,
             pausedIndefinitely: pause.indefinite,
-            pauseExpiresAt: pause.expiresAt?.timeIntervalSince1970
+            pauseExpiresAt: pause.expiresAt?.timeIntervalSince1970,
+            foregroundApp: foregroundApp,
+            lastSegmentAt: lastSegmentAt?.timeIntervalSince1970,
+            locale: status == .idle ? nil : locale.identifier,
+            armed: armed,
+            callApp: callApp,
+            thermalState: pressure.thermalState,
+            lowPower: pressure.lowPower,
+            summariesDeferred: summariesDeferred
         )
     }
 
+    // MARK: - Export
+
+    /// Clean each of `texts` for a clean-verbatim export. All or nothing:
+    /// one failed segment fails the command, and the client keeps the
+    /// verbatim text.
+    private func handleCleanVerbatim(_ command: DaemonCommand) async -> DaemonResponse {
+        guard let summarizer, await summarizer.isAvailable else {
+            return DaemonResponse.failure("clean_verbatim requires an available language model")
+        }
+        guard let texts = command.texts, !texts.isEmpty else {
+            return DaemonResponse.failure("clean_verbatim requires texts")
+        }
+        var cleaned: [String] = []
+        cleaned.reserveCapacity(texts.count)
+        do {
+            for text in texts {
+                cleaned.append(try await summarizer.cleanVerbatim(text))
+            }
+        } catch {
+            return DaemonResponse.failure(error.localizedDescription)
+        }
+        return DaemonResponse(ok: true, texts: cleaned)
+    }
+
+    // MARK: - Test mode
+
+    /// Feed an audio file into the recording session's mic source. The
+    /// response comes once the file is queued; its transcript arrives as
+    /// ordinary `partial` / `segment` events.
+    private func handleInjectAudio(_ command: DaemonCommand) async -> DaemonResponse {
+        guard let audioInjector else {
+            return DaemonResponse.failure("inject_audio requires a daemon run with --test-mode")
+        }
+        guard let path = command.path, !path.isEmpty else {
+            return DaemonResponse.failure("inject_audio requires a path")
+        }
+        guard await engine.status == .recording else {
+            return DaemonResponse.failure(InjectedAudioSourceFactory.InjectionError.notRecording.localizedDescription)
+        }
+        do {
+            try audioInjector.inject(contentsOf: URL(fileURLWithPath: path))
+            let session = await engine.currentSession
+            return DaemonResponse(ok: true, sessionId: session?.id.uuidString, recording: true)
+        } catch {
+            return DaemonResponse.failure(error.localizedDescription)
+        }
+    }
+
     // MARK: - U10 pause / resume / demarcate
 
     private func handlePause(_ command: DaemonCommand) async -> DaemonResponse {
@@ -182,6 +302,43 @@ public actor CommandDispatcher {
         }
     }
 
+    /// Switch the recognition locale without ending the session. Requires
+    /// `locale`; valid while recording or paused.
+    private func handleSetLocale(_ command: DaemonCommand) async -> DaemonResponse {
+        guard let localeId = command.locale, !localeId.isEmpty else {
+            return DaemonResponse.failure("set_locale requires a locale")
+        }
+        do {
+            try await engine.setLocale(Locale(identifier: localeId))
+            let status = await engine.status
+            let session = await engine.currentSession
+            return DaemonResponse(
+                ok: true,
+                sessionId: session?.id.uuidString,
+                recording: status == .recording,
+                status: status.rawValue,
+                locale: await engine.currentLocale.identifier
+            )
+        } catch {
+            return DaemonResponse.failure(error.localizedDescription)
+        }
+    }
+
+    /// `arm`: start a session on its own once sustained speech is heard,
+    /// with the same device / systemAudio / locale options as `start`.
+    private func handleArm(_ command: DaemonCommand) async -> DaemonResponse {
+        do {
+            try await engine.arm(
+                locale: command.locale.map { Locale(identifier: $0) } ?? .current,
+                device: command.device,
+                systemAudio: command.systemAudio ?? false
+            )
+            return DaemonResponse(ok: true, armed: true)
+        } catch {
+            return DaemonResponse.failure(error.localizedDescription)
+        }
+    }
+
     private func handleDevices() async -> DaemonResponse {
         let devices = await engine.availableDevices()
         return DaemonResponse(
@@ -201,7 +358,12 @@ public actor CommandDispatcher {
             eventTypes = Set(EventType.allCases)
         }
 
-        await broadcaster.subscribe(client: client, events: eventTypes)
+        // Live events wait for the replay of missed segments (see handle).
+        await broadcaster.subscribe(
+            client: client,
+            events: eventTypes,
+            holdingBack: command.sinceSessionId != nil
+        )
         return DaemonResponse.success()
     }
 }
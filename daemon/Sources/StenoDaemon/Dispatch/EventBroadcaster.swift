@@ -19,19 +19,63 @@ public actor EventBroadcaster: RecordingEngineDelegate {
     private var subscriptions: [UUID: (client: any ClientConnection, events: Set<EventType>)] = [:]
     private let encoder = JSONEncoder()
 
+    /// Live events held back from clients whose missed segments haven't
+    /// been replayed yet, so the replay reaches them first.
+    private var heldBack: [UUID: [Data]] = [:]
+
     public init() {}
 
-    /// Subscribe a client to receive specific event types.
+    /// Subscribe a client to receive specific event types. With
+    /// `holdingBack`, its events queue until `replay(_:to:)`.
     public func subscribe(
         client: any ClientConnection,
-        events: Set<EventType>
+        events: Set<EventType>,
+        holdingBack: Bool = false
     ) {
         subscriptions[client.id] = (client: client, events: events)
+        if holdingBack {
+            heldBack[client.id] = []
+        }
     }
 
     /// Unsubscribe a client.
     public func unsubscribe(_ clientId: UUID) {
         subscriptions.removeValue(forKey: clientId)
+        heldBack.removeValue(forKey: clientId)
+    }
+
+    /// Send `segments` to a client subscribed with `holdingBack` as
+    /// `segment` events, then the events held back meanwhile, and go
+    /// live. Some of the replay may repeat held-back or already-seen
+    /// segments; clients drop those by session and sequence number.
+    public func replay(_ segments: [StoredSegment], to clientId: UUID) async {
+        guard let sub = subscriptions[clientId] else {
+            heldBack.removeValue(forKey: clientId)
+            return
+        }
+        var lines: [Data] = []
+        if sub.events.contains(.segment) {
+            lines = segments.compactMap { segment in
+                try? encoder.encode(mapEvent(.segmentFinalized(segment)).1) + Data("\n".utf8)
+            }
+        }
+        while true {
+            for line in lines {
+                do {
+                    try await sub.client.send(line)
+                } catch {
+                    unsubscribe(clientId)
+                    return
+                }
+            }
+            // Events broadcast while sending queued up behind the replay.
+            guard let pending = heldBack[clientId], !pending.isEmpty else {
+                heldBack.removeValue(forKey: clientId)
+                return
+            }
+            heldBack[clientId] = []
+            lines = pending
+        }
     }
 
     // MARK: - RecordingEngineDelegate
@@ -50,6 +94,10 @@ public actor EventBroadcaster: RecordingEngineDelegate {
 
         for (id, sub) in subscriptions {
             guard sub.events.contains(eventType) else { continue }
+            if heldBack[id] != nil {
+                heldBack[id]?.append(line)
+                continue
+            }
             do {
                 try await sub.client.send(line)
             } catch {
@@ -86,14 +134,19 @@ public actor EventBroadcaster: RecordingEngineDelegate {
                 source: segment.source.rawValue,
                 sessionId: segment.sessionId.uuidString,
                 sequenceNumber: segment.sequenceNumber,
-                startedAt: segment.startedAt.timeIntervalSince1970
+                startedAt: segment.startedAt.timeIntervalSince1970,
+                endedAt: segment.endedAt.timeIntervalSince1970,
+                locale: segment.locale,
+                speaker: segment.speaker
             ))
 
         case .topicsUpdated(let topics):
-            // Send just the titles as a simple signal; clients query DB for full data
+            // The full list, in order; title keeps the old titles-only
+            // signal for clients that predate the payload.
             return (.topics, DaemonEvent(
                 event: "topics",
-                title: topics.map(\.title).joined(separator: ", ")
+                title: topics.map(\.title).joined(separator: ", "),
+                topics: topics.map(DaemonTopic.init)
             ))
 
         case .statusChanged(let status):
@@ -154,6 +207,51 @@ public actor EventBroadcaster: RecordingEngineDelegate {
                 pausedIndefinitely: indefinite,
                 pauseExpiresAt: expiresAt?.timeIntervalSince1970
             ))
+
+        // Locale switches ride the `.status` channel too: they change
+        // what the TUI's status bar shows.
+        case .localeChanged(let locale):
+            return (.status, DaemonEvent(
+                event: "locale",
+                locale: locale.identifier
+            ))
+
+        // Voice-activated start: arming and the start it triggers are
+        // status changes as far as clients are concerned.
+        case .armedChanged(let armed):
+            return (.status, DaemonEvent(
+                event: "armed",
+                armed: armed
+            ))
+
+        case .autoStarted(let sessionId):
+            return (.status, DaemonEvent(
+                event: "auto_started",
+                sessionId: sessionId.uuidString
+            ))
+
+        // Calls change whether (and which) session is recording.
+        case .callStarted(let app, let autoRecorded):
+            return (.status, DaemonEvent(
+                event: "call_started",
+                app: app,
+                autoRecorded: autoRecorded
+            ))
+
+        case .callEnded(let app):
+            return (.status, DaemonEvent(
+                event: "call_ended",
+                app: app
+            ))
+
+        // Pressure is shown in the status bar.
+        case .pressureChanged(let pressure, let summariesDeferred):
+            return (.status, DaemonEvent(
+                event: "pressure",
+                thermalState: pressure.thermalState,
+                lowPower: pressure.lowPower,
+                summariesDeferred: summariesDeferred
+            ))
         }
     }
 }
@@ -0,0 +1,20 @@
+import Foundation
+
+/// Identifies a hardware configuration by the input devices attached.
+///
+/// Docking a laptop brings a USB mic or interface; undocking leaves the
+/// built-in mic. Keying the remembered device on the set of devices
+/// present lets each setup restore its own choice, instead of the last
+/// choice overall following the laptop from desk to couch — or the
+/// system default silently taking over when the docked mic appears.
+public enum AudioSetup {
+
+    /// The fingerprint of a set of input device UIDs: sorted and
+    /// de-duplicated so enumeration order doesn't matter. `nil` when no
+    /// devices are known, which callers treat as "setup unknown".
+    public static func fingerprint(_ deviceUIDs: [String]) -> String? {
+        let uids = Set(deviceUIDs.filter { !$0.isEmpty }).sorted()
+        guard !uids.isEmpty else { return nil }
+        return uids.joined(separator: "|")
+    }
+}
@@ -6,6 +6,12 @@ import Speech
 public final class DefaultSpeechRecognizerFactory: SpeechRecognizerFactory, Sendable {
     public init() {}
 
+    /// Whether this Mac's hardware can run SpeechTranscriber. When it
+    /// can't, `TranscriptionBackend.auto` falls back to remote ASR.
+    public static var isSupported: Bool {
+        SpeechTranscriber.isAvailable
+    }
+
     public func makeRecognizer(locale: Locale, format: AVAudioFormat, source: AudioSourceType)
         async throws -> SpeechRecognizerHandle {
         DefaultSpeechRecognizerHandle(locale: locale, inputFormat: format, source: source)
@@ -0,0 +1,183 @@
+@preconcurrency import AVFoundation
+
+/// Audio source factory for `run --test-mode`: the microphone source
+/// plays audio files pushed with the `inject_audio` command instead of
+/// opening a live input device.
+///
+/// Integration tests start a session, inject a recorded file, and assert
+/// on the `segment` events and rows it produces — the whole pipeline from
+/// recognizer to database to event stream, on a CI runner with no mic.
+/// System audio is still captured for real when a start asks for it;
+/// tests that want determinism start with `systemAudio: false`.
+public final class InjectedAudioSourceFactory: AudioSourceFactory, @unchecked Sendable {
+
+    public enum InjectionError: Error, LocalizedError, Equatable {
+        /// No microphone source is open: the engine isn't recording.
+        case notRecording
+        /// The file couldn't be read or converted to the source format.
+        case unreadable(String)
+
+        public var errorDescription: String? {
+            switch self {
+            case .notRecording:
+                return "Not recording"
+            case .unreadable(let reason):
+                return "Couldn't read audio: \(reason)"
+            }
+        }
+    }
+
+    /// Format of the injected mic stream: 16kHz mono float, what the
+    /// recognizers are tuned for. Files are converted to it.
+    public static let format = AVAudioFormat(
+        commonFormat: .pcmFormatFloat32,
+        sampleRate: 16000,
+        channels: 1,
+        interleaved: false
+    )!
+
+    /// Frames per yielded buffer: 100ms, close to a live tap's size.
+    static let chunkFrames: AVAudioFrameCount = 1600
+
+    /// Silence appended to each file so the recognizer finalizes the last
+    /// utterance rather than leaving it as a partial.
+    static let trailingSilenceSeconds: Double = 1.5
+
+    private let lock = NSLock()
+    private var micContinuation: AsyncStream<AVAudioPCMBuffer>.Continuation?
+    /// Bumped per mic source, so stopping an old one after a rebuild
+    /// leaves the newer stream in place.
+    private var micGeneration = 0
+
+    public init() {}
+
+    public func makeMicrophoneSource(device: String?) async throws
+        -> (buffers: AsyncStream<AVAudioPCMBuffer>, format: AVAudioFormat, stop: @Sendable () async -> Void) {
+        let (stream, continuation) = AsyncStream<AVAudioPCMBuffer>.makeStream()
+        let generation = lock.withLock {
+            micGeneration += 1
+            micContinuation = continuation
+            return micGeneration
+        }
+
+        let stop: @Sendable () async -> Void = { [weak self, continuation] in
+            continuation.finish()
+            self?.closeMicSource(generation)
+        }
+        return (buffers: stream, format: Self.format, stop: stop)
+    }
+
+    private func closeMicSource(_ generation: Int) {
+        lock.withLock {
+            if micGeneration == generation {
+                micContinuation = nil
+            }
+        }
+    }
+
+    public func makeSystemAudioSource() -> AudioSource {
+        SystemAudioSource()
+    }
+
+    /// Feed the audio file at `url` into the open microphone source.
+    /// Buffers are queued as fast as they're read; the recognizer
+    /// consumes them at its own pace.
+    ///
+    /// - Returns: The injected audio's duration in seconds, without the
+    ///   trailing silence.
+    @discardableResult
+    public func inject(contentsOf url: URL) throws -> Double {
+        let buffers = try Self.readBuffers(contentsOf: url)
+        guard let continuation = lock.withLock({ micContinuation }) else {
+            throw InjectionError.notRecording
+        }
+        for buffer in buffers.audio + buffers.silence {
+            nonisolated(unsafe) let unsafeBuffer = buffer
+            continuation.yield(unsafeBuffer)
+        }
+        let frames = buffers.audio.reduce(0) { $0 + Int($1.frameLength) }
+        return Double(frames) / Self.format.sampleRate
+    }
+
+    // MARK: - Decoding
+
+    /// Read `url`, convert it to `format`, and cut it into
+    /// `chunkFrames`-sized buffers, plus the trailing silence.
+    static func readBuffers(contentsOf url: URL) throws
+        -> (audio: [AVAudioPCMBuffer], silence: [AVAudioPCMBuffer]) {
+        let file: AVAudioFile
+        do {
+            file = try AVAudioFile(forReading: url)
+        } catch {
+            throw InjectionError.unreadable(error.localizedDescription)
+        }
+        let source = file.processingFormat
+        guard file.length > 0,
+              let input = AVAudioPCMBuffer(pcmFormat: source, frameCapacity: AVAudioFrameCount(file.length)) else {
+            throw InjectionError.unreadable("\(url.lastPathComponent) is empty")
+        }
+        do {
+            try file.read(into: input)
+        } catch {
+            throw InjectionError.unreadable(error.localizedDescription)
+        }
+
+        guard let converter = AVAudioConverter(from: source, to: format) else {
+            throw InjectionError.unreadable("can't convert from \(source)")
+        }
+        let capacity = AVAudioFrameCount(Double(input.frameLength) * format.sampleRate / source.sampleRate) + 1
+        guard let converted = AVAudioPCMBuffer(pcmFormat: format, frameCapacity: capacity) else {
+            throw InjectionError.unreadable("can't allocate \(capacity) frames")
+        }
+        nonisolated(unsafe) var consumed = false
+        var conversionError: NSError?
+        converter.convert(to: converted, error: &conversionError) { _, status in
+            if consumed {
+                status.pointee = .endOfStream
+                return nil
+            }
+            consumed = true
+            status.pointee = .haveData
+            return input
+        }
+        if let conversionError {
+            throw InjectionError.unreadable(conversionError.localizedDescription)
+        }
+
+        let silenceFrames = AVAudioFrameCount(trailingSilenceSeconds * format.sampleRate)
+        return (chunks(of: converted), silence(frames: silenceFrames))
+    }
+
+    /// Split a mono buffer into `chunkFrames`-sized buffers.
+    private static func chunks(of buffer: AVAudioPCMBuffer) -> [AVAudioPCMBuffer] {
+        guard let samples = buffer.floatChannelData?[0] else { return [] }
+        var chunks: [AVAudioPCMBuffer] = []
+        var offset: AVAudioFrameCount = 0
+        while offset < buffer.frameLength {
+            let count = min(chunkFrames, buffer.frameLength - offset)
+            guard let chunk = AVAudioPCMBuffer(pcmFormat: format, frameCapacity: count),
+                  let dest = chunk.floatChannelData?[0] else { break }
+            dest.update(from: samples + Int(offset), count: Int(count))
+            chunk.frameLength = count
+            chunks.append(chunk)
+            offset += count
+        }
+        return chunks
+    }
+
+    /// `frames` of silence in `chunkFrames`-sized buffers.
+    private static func silence(frames: AVAudioFrameCount) -> [AVAudioPCMBuffer] {
+        var chunks: [AVAudioPCMBuffer] = []
+        var remaining = frames
+        while remaining > 0 {
+            let count = min(chunkFrames, remaining)
+            guard let chunk = AVAudioPCMBuffer(pcmFormat: format, frameCapacity: count),
+                  let dest = chunk.floatChannelData?[0] else { break }
+            dest.update(repeating: 0, count: Int(count))
+            chunk.frameLength = count
+            chunks.append(chunk)
+            remaining -= count
+        }
+        return chunks
+    }
+}
@@ -18,10 +18,31 @@ public actor RecordingEngine {
     // MARK: - Read-only state
 
     public private(set) var status: EngineStatus = .idle
-    public private(set) var currentSession: Session?
+    public private(set) var currentSession: Session? {
+        didSet {
+            guard currentSession?.id != oldValue?.id else { return }
+            sessionForegroundApp = currentSession == nil ? nil : foregroundAppProvider()
+            sessionCallApp = currentSession == nil ? nil : callApp
+        }
+    }
+    /// App that had focus when `currentSession` opened. Snapshotted once
+    /// per session; `nil` when unknown or no session is open.
+    public private(set) var sessionForegroundApp: String?
+    /// Conferencing app whose call was on when `currentSession` opened,
+    /// which tags the session. Snapshotted like `sessionForegroundApp`.
+    public private(set) var sessionCallApp: String?
+    /// Conferencing app on a call right now, as reported through
+    /// `handleCallTransition`; `nil` when none is.
+    public private(set) var callApp: String?
+    /// Thermal and power pressure, as last reported through
+    /// `handlePressureChange`. Reported by `status`.
+    public private(set) var pressure: ResourcePressure = .nominal
     public private(set) var currentDevice: String?
     public private(set) var isSystemAudioEnabled: Bool = false
     public private(set) var segmentCount: Int = 0
+    /// Wall-clock time of the current session's most recent finalized
+    /// segment; `nil` until one arrives. Reported by `status`.
+    public private(set) var lastSegmentAt: Date?
 
     // MARK: - Dependencies
 
@@ -49,6 +70,10 @@ public actor RecordingEngine {
     /// Resolved from `StenoSettings.retentionDays`.
     private let retentionDays: Int
 
+    /// When rolling summaries wait out pressure. Resolved from
+    /// `StenoSettings.deferSummaries`.
+    private let summaryDeferral: SummaryDeferral
+
     /// Sleep used by the U5 restart-with-backoff loop. Production passes
     /// `Task.sleep(for:)`; tests inject a faster (or zero-duration)
     /// closure so the curve is observable without paying real wall-clock.
@@ -72,6 +97,18 @@ public actor RecordingEngine {
     /// "device changed across sleep" rollover branch.
     private let deviceUIDProvider: @Sendable () -> String?
 
+    /// Resolves the frontmost application's name when a session opens.
+    /// Production injects `frontmostApplicationName()`; tests inject a
+    /// fixed name.
+    private let foregroundAppProvider: @Sendable () -> String?
+
+    /// Resolves the current audio setup's fingerprint
+    /// (`AudioSetup.fingerprint(_:)`) so a successful start records its
+    /// device against the setup. Production injects a Core Audio input
+    /// device listing; tests inject fixed setups. `nil` records
+    /// `lastDevice` only.
+    private let audioSetupProvider: @Sendable () -> String?
+
     /// Heal-rule reuse threshold in seconds. Resolved from
     /// `StenoSettings.healGapSeconds` at construction time.
     private let healThresholdSeconds: Int
@@ -118,7 +155,8 @@ public actor RecordingEngine {
 
     /// Locale captured at start time so the restart path can rebuild
     /// recognizers without re-threading locale through every call.
-    private var currentLocale: Locale = .current
+    /// `setLocale(_:)` changes it mid-session. Reported by `status`.
+    public private(set) var currentLocale: Locale = .current
 
     /// Independent backoff policies per source. The mic and system audio
     /// pipelines fail (and recover) independently per the plan: a
@@ -234,6 +272,15 @@ public actor RecordingEngine {
     /// during a transient blip should still split the session.
     private var pendingDemarcate: Bool = false
 
+    // MARK: - Voice-activated start state
+
+    /// True while armed: the mic is open for level metering only (no
+    /// recognizer, nothing persisted) and `armMonitorTask` is watching
+    /// for sustained speech. Status stays `.idle` throughout.
+    public private(set) var isArmed: Bool = false
+    private var armMonitorTask: Task<Void, Never>?
+    private var armStopClosure: (@Sendable () async -> Void)?
+
     // MARK: - Init
 
     public init(
@@ -248,6 +295,8 @@ public actor RecordingEngine {
         },
         powerAssertion: (any PowerAssertionManaging)? = nil,
         deviceUIDProvider: @Sendable @escaping () -> String? = { nil },
+        foregroundAppProvider: @Sendable @escaping () -> String? = { nil },
+        audioSetupProvider: @Sendable @escaping () -> String? = { nil },
         healThresholdSeconds: Int = 30,
         now: @Sendable @escaping () -> Date = { Date() },
         dedupCoordinator: DedupCoordinator? = nil,
@@ -255,6 +304,7 @@ public actor RecordingEngine {
         emptySessionMinChars: Int = 20,
         emptySessionMinDurationSeconds: Double = 3.0,
         retentionDays: Int = 90,
+        summaryDeferral: SummaryDeferral = .off,
         pauseTimer: PauseTimer? = nil
     ) {
         self.repository = repository
@@ -266,6 +316,8 @@ public actor RecordingEngine {
         self.backoffSleep = backoffSleep
         self.powerAssertion = powerAssertion ?? PowerAssertion()
         self.deviceUIDProvider = deviceUIDProvider
+        self.foregroundAppProvider = foregroundAppProvider
+        self.audioSetupProvider = audioSetupProvider
         self.healThresholdSeconds = healThresholdSeconds
         self.nowProvider = now
         self.dedupCoordinator = dedupCoordinator
@@ -273,6 +325,7 @@ public actor RecordingEngine {
         self.emptySessionMinChars = emptySessionMinChars
         self.emptySessionMinDurationSeconds = emptySessionMinDurationSeconds
         self.retentionDays = retentionDays
+        self.summaryDeferral = summaryDeferral
         self.pauseTimer = pauseTimer ?? PauseTimer()
     }
 
@@ -299,6 +352,8 @@ public actor RecordingEngine {
         guard status == .idle || status == .error else {
             throw RecordingEngineError.alreadyRecording
         }
+        // A manual start while armed takes over from the metering mic.
+        await disarm()
 
         // Reset backoff state on every entry into `.starting`. This is
         // a no-op when we came from `.idle` (a fresh `BackoffPolicy` is
@@ -350,7 +405,7 @@ public actor RecordingEngine {
     /// next-launch convenience, not a runtime requirement.
     private func persistLastKnownAudioConfig(device: String?, systemAudio: Bool) async {
         var settings = StenoSettings.load()
-        settings.lastDevice = device
+        settings.rememberDevice(device, forSetup: audioSetupProvider())
         settings.lastSystemAudioEnabled = systemAudio
         do {
             try settings.save()
@@ -655,8 +710,29 @@ public actor RecordingEngine {
         return result
     }
 
+    /// Segments a client that last saw `sequence` of `sessionId` missed,
+    /// in order: the rest of that session and, when another has opened
+    /// since, all of the current one. A failed read replays nothing; the
+    /// client still has the database.
+    public func segmentsMissed(
+        sinceSessionId sessionId: UUID,
+        sinceSequence sequence: Int
+    ) async -> [StoredSegment] {
+        var missed = ((try? await repository.segments(for: sessionId)) ?? [])
+            .filter { $0.sequenceNumber > sequence }
+            .sorted { $0.sequenceNumber < $1.sequenceNumber }
+        if let current = currentSession, current.id != sessionId {
+            missed += ((try? await repository.segments(for: current.id)) ?? [])
+                .sorted { $0.sequenceNumber < $1.sequenceNumber }
+        }
+        return missed
+    }
+
     /// Stop recording and finalize the session.
     public func stop() async {
+        // Stopping an armed, idle engine just disarms it.
+        await disarm()
+
         // Allow stopping from `.recovering` too (U5): an in-flight
         // restart must be cancellable mid-backoff so user-initiated
         // teardown is not blocked by the wait.
@@ -819,6 +895,7 @@ public actor RecordingEngine {
 
             currentSequenceNumber += 1
             segmentCount = currentSequenceNumber
+            lastSegmentAt = Date()
 
             // U10 demarcate timestamp routing: a finalized segment whose
             // audio-frame `startedAt` precedes the demarcate moment T is
@@ -901,7 +978,8 @@ public actor RecordingEngine {
                 sequenceNumber: segmentSequence,
                 source: result.source,
                 healMarker: healMarker,
-                micPeakDb: micPeakDb
+                micPeakDb: micPeakDb,
+                locale: currentLocale.identifier
             )
 
             // Persist
@@ -951,13 +1029,17 @@ public actor RecordingEngine {
                 }
             }
 
-            // Trigger summary against the session the segment landed on.
-            await emit(.modelProcessing(true))
-            let summaryResult = await summaryCoordinator.onSegmentSaved(sessionId: routingSessionId)
-            await emit(.modelProcessing(false))
+            // Trigger summary against the session the segment landed on,
+            // unless it's deferred; the first segment after the pressure
+            // eases catches up.
+            if !summariesDeferred {
+                await emit(.modelProcessing(true))
+                let summaryResult = await summaryCoordinator.onSegmentSaved(sessionId: routingSessionId)
+                await emit(.modelProcessing(false))
 
-            if let summaryResult {
-                await emit(.topicsUpdated(summaryResult.topics))
+                if let summaryResult {
+                    await emit(.topicsUpdated(summaryResult.topics))
+                }
             }
         } else {
             await emit(.partialText(result.text, result.source))
@@ -1397,6 +1479,171 @@ public actor RecordingEngine {
         }
     }
 
+    // MARK: - Voice-activated start
+
+    /// Arm voice-activated start: open the mic for level metering and,
+    /// once `detector` hears sustained speech, start a session with the
+    /// given configuration and emit `.autoStarted`. Levels stream to
+    /// clients while armed so they can show the meter.
+    ///
+    /// Only from `.idle` / `.error`. A paused engine stays deaf — the
+    /// U10 privacy invariant holds until the user resumes. Arming an
+    /// already-armed engine is a no-op.
+    public func arm(
+        locale: Locale = .current,
+        device: String? = nil,
+        systemAudio: Bool = false,
+        detector: SpeechOnsetDetector = SpeechOnsetDetector()
+    ) async throws {
+        guard status == .idle || status == .error else {
+            throw RecordingEngineError.alreadyRecording
+        }
+        guard !isArmed else { return }
+
+        let permissions = await permissionService.checkPermissions()
+        guard permissions.allGranted else {
+            throw RecordingEngineError.permissionDenied(permissions.errorMessage ?? "Permissions denied")
+        }
+
+        let buffers: AsyncStream<AVAudioPCMBuffer>
+        do {
+            let source = try await audioSourceFactory.makeMicrophoneSource(device: device)
+            buffers = source.buffers
+            armStopClosure = source.stop
+        } catch {
+            throw RecordingEngineError.audioSourceFailed(error.localizedDescription)
+        }
+        isArmed = true
+        startLevelThrottle()
+        await emit(.armedChanged(true))
+
+        struct Box: @unchecked Sendable {
+            let source: AsyncStream<AVAudioPCMBuffer>
+        }
+        let box = Box(source: buffers)
+        let now = nowProvider
+        armMonitorTask = Task { [weak self] in
+            var detector = detector
+            for await buffer in box.source {
+                let peak = RecordingEngine.peakLevel(buffer)
+                await self?.updateArmedLevel(peak)
+                if detector.feed(peak, at: now()) {
+                    await self?.armTriggered(locale: locale, device: device, systemAudio: systemAudio)
+                    return
+                }
+            }
+        }
+    }
+
+    /// Disarm without starting. No-op when not armed.
+    public func disarm() async {
+        guard isArmed else { return }
+        let task = armMonitorTask
+        armMonitorTask = nil
+        task?.cancel()
+        await releaseArmedMic()
+    }
+
+    /// The monitor heard speech: hand the mic over to a real start. Runs
+    /// on the monitor task, so it must not await that task.
+    private func armTriggered(locale: Locale, device: String?, systemAudio: Bool) async {
+        guard isArmed else { return }
+        armMonitorTask = nil
+        await releaseArmedMic()
+        // A failed start has already set `.error` and emitted why.
+        guard let session = try? await start(locale: locale, device: device, systemAudio: systemAudio) else { return }
+        await emit(.autoStarted(sessionId: session.id))
+    }
+
+    private func releaseArmedMic() async {
+        await armStopClosure?()
+        armStopClosure = nil
+        levelThrottleTask?.cancel()
+        levelThrottleTask = nil
+        pendingMicLevel = 0
+        isArmed = false
+        await emit(.armedChanged(false))
+    }
+
+    // MARK: - Call detection
+
+    /// A conferencing app started or stopped using the mic (see
+    /// `ConferencingAppObserver`). Any session that opens during the call
+    /// is tagged with the app. With `autoRecord`, the call also gets a
+    /// session of its own: an idle engine starts recording with the given
+    /// configuration, a recording one demarcates, and the session is
+    /// demarcated again when the call ends so what follows isn't tagged.
+    /// A paused engine stays paused (U10 privacy invariant); clients are
+    /// still told about the call.
+    public func handleCallTransition(
+        _ transition: CallTransition,
+        autoRecord: Bool,
+        locale: Locale = .current,
+        device: String? = nil,
+        systemAudio: Bool = false
+    ) async {
+        let deferred = summariesDeferred
+        switch transition {
+        case .started(let app):
+            callApp = app.name
+            var recorded = false
+            if autoRecord {
+                recorded = await openCallSession(locale: locale, device: device, systemAudio: systemAudio)
+            }
+            await emit(.callStarted(app: app.name, autoRecorded: recorded))
+        case .ended(let app):
+            callApp = nil
+            if autoRecord, sessionCallApp == app.name, status == .recording {
+                _ = try? await demarcate()
+            }
+            await emit(.callEnded(app: app.name))
+        }
+        // Under `SummaryDeferral.calls`, the call is what defers.
+        if summariesDeferred != deferred {
+            await emit(.pressureChanged(pressure, summariesDeferred: summariesDeferred))
+        }
+    }
+
+    /// Opens a session for a call that just started. Returns false when
+    /// the engine's state doesn't allow one (or the start failed, which
+    /// has already emitted why).
+    private func openCallSession(locale: Locale, device: String?, systemAudio: Bool) async -> Bool {
+        switch status {
+        case .recording, .recovering:
+            // While recovering, the boundary is queued and applied on
+            // the return to `.recording`; the session it opens is still
+            // tagged if the call is on by then.
+            return (try? await demarcate()) != nil
+        case .idle, .error:
+            return (try? await start(locale: locale, device: device, systemAudio: systemAudio)) != nil
+        case .paused, .starting, .stopping:
+            return false
+        }
+    }
+
+    // MARK: - Resource pressure
+
+    /// Whether rolling summaries are being held back: the machine is
+    /// under pressure and `summaryDeferral` says to wait, which for
+    /// `.calls` means only while a call is on.
+    public var summariesDeferred: Bool {
+        guard pressure.isUnderPressure else { return false }
+        switch summaryDeferral {
+        case .off: return false
+        case .calls: return callApp != nil
+        case .always: return true
+        }
+    }
+
+    /// The thermal state or Low Power Mode changed (see
+    /// `ResourcePressureObserver`). Clients are told, with whether
+    /// summaries are now deferred.
+    public func handlePressureChange(_ pressure: ResourcePressure) async {
+        guard pressure != self.pressure else { return }
+        self.pressure = pressure
+        await emit(.pressureChanged(pressure, summariesDeferred: summariesDeferred))
+    }
+
     // MARK: - Level Metering
 
     /// Wrap a buffer stream to compute peak levels as buffers pass through.
@@ -1439,6 +1686,12 @@ public actor RecordingEngine {
         pendingSystemLevel = max(pendingSystemLevel, peak)
     }
 
+    /// Armed metering feeds the level meter but not the U11 segment
+    /// peak: no segment is in flight.
+    private func updateArmedLevel(_ peak: Float) {
+        pendingMicLevel = max(pendingMicLevel, peak)
+    }
+
     /// Start a 10Hz throttle task that emits audioLevel events.
     private func startLevelThrottle() {
         guard levelThrottleTask == nil else { return }
@@ -1881,6 +2134,7 @@ public actor RecordingEngine {
                 // Reset segment counter for the fresh session.
                 currentSequenceNumber = 0
                 segmentCount = 0
+                lastSegmentAt = nil
                 // Clear any pending mic heal-marker — the new session
                 // does not carry one (per HealRule contract).
                 pendingMicHealMarker = nil
@@ -2149,6 +2403,67 @@ public actor RecordingEngine {
         await emit(.pauseStateChanged(paused: false, indefinite: false, expiresAt: nil))
     }
 
+    /// Switch the recognition locale without ending the session, for
+    /// meetings that alternate languages. Both recognizers are rebuilt
+    /// around the current session; segments finalized afterwards carry
+    /// the new locale, while the session row keeps the one it opened with.
+    /// As with a pause, words mid-utterance at the switch may be lost.
+    ///
+    /// While paused only the remembered locale changes, so the next
+    /// `resume()` opens its session in it. `.recovering` is rejected
+    /// rather than queued: the restart in flight already rebuilds from
+    /// `currentLocale`, and a second rebuild would race its teardown.
+    public func setLocale(_ locale: Locale) async throws {
+        switch status {
+        case .recording:
+            break
+        case .paused:
+            currentLocale = locale
+            await emit(.localeChanged(locale))
+            return
+        case .idle, .starting, .stopping, .recovering, .error:
+            throw RecordingEngineError.notRecording
+        }
+        // `pauseInProgress` also serializes this against pause/resume:
+        // all three tear pipelines down across suspension points.
+        guard !pauseInProgress, let session = currentSession else {
+            throw RecordingEngineError.notRecording
+        }
+        guard locale.identifier != currentLocale.identifier else { return }
+        pauseInProgress = true
+        defer { pauseInProgress = false }
+
+        // Tear down both pipelines, consumers first (see `pause`), but
+        // keep the session open.
+        recognizerTask?.cancel()
+        recognizerTask = nil
+        await micRecognizerHandle?.stop()
+        micRecognizerHandle = nil
+        await micStopClosure?()
+        micStopClosure = nil
+
+        systemRecognizerTask?.cancel()
+        systemRecognizerTask = nil
+        await sysRecognizerHandle?.stop()
+        sysRecognizerHandle = nil
+        await systemAudioSource?.stop()
+        systemAudioSource = nil
+
+        levelThrottleTask?.cancel()
+        levelThrottleTask = nil
+
+        // bringUpPipelines records the new locale and rehydrates the
+        // sequence counter from the session's rows. On failure it has
+        // already cleaned up, moved to `.error` and emitted the cause.
+        _ = try await bringUpPipelines(
+            session: session,
+            locale: locale,
+            device: currentDevice,
+            systemAudio: isSystemAudioEnabled
+        )
+        await emit(.localeChanged(locale))
+    }
+
     /// Atomic session boundary at T = `nowProvider()`. Closes the current
     /// session at T (sets `endedAt`), opens a fresh active session at T,
     /// and seeds timestamp-based segment routing so in-flight partial
@@ -2223,6 +2538,7 @@ public actor RecordingEngine {
         // don't collide.
         currentSequenceNumber = 0
         segmentCount = 0
+        lastSegmentAt = nil
 
         // Cancel any pending heal-marker — a demarcate is a clean session
         // boundary, NOT a heal-in-place. The first segment of the new
@@ -47,6 +47,24 @@ public enum EngineEvent: Sendable {
     /// - `expiresAt`: wall-clock instant the auto-resume timer will fire.
     ///   `nil` for indefinite pauses and on resume.
     case pauseStateChanged(paused: Bool, indefinite: Bool, expiresAt: Date?)
+    /// Ephemeral: the recognition locale changed mid-session
+    /// (`set_locale`). Also emitted while paused, when only the locale
+    /// the next session opens with changes.
+    case localeChanged(Locale)
+    /// Ephemeral: voice-activated start was armed or disarmed. Disarming
+    /// includes the automatic release when speech starts a session.
+    case armedChanged(Bool)
+    /// Ephemeral: an armed engine heard sustained speech and started
+    /// this session on its own. Follows the `.recording` status change.
+    case autoStarted(sessionId: UUID)
+    /// Ephemeral: a conferencing app started using the mic. `autoRecorded`
+    /// when the engine opened a session for the call (`CallDetection.auto`).
+    case callStarted(app: String, autoRecorded: Bool)
+    /// Ephemeral: the call's app stopped using the mic.
+    case callEnded(app: String)
+    /// Ephemeral: the thermal state or Low Power Mode changed, or a call
+    /// starting or ending changed whether summaries are deferred.
+    case pressureChanged(ResourcePressure, summariesDeferred: Bool)
 }
 
 /// Status of the recording engine.
@@ -0,0 +1,277 @@
+@preconcurrency import AVFoundation
+import Foundation
+
+/// Errors from the remote streaming ASR backend.
+public enum RemoteASRError: Error, Equatable, LocalizedError {
+    /// `remoteASRURL` isn't a ws:// or wss:// URL.
+    case invalidEndpoint(String)
+    /// The capture format can't be converted to the PCM we stream.
+    case unsupportedFormat
+    /// The server reported an error and closed the stream.
+    case serverError(String)
+
+    public var errorDescription: String? {
+        switch self {
+        case .invalidEndpoint(let endpoint):
+            return "Remote ASR endpoint \(endpoint) is not a ws:// or wss:// URL"
+        case .unsupportedFormat:
+            return "Audio format can't be converted for remote ASR"
+        case .serverError(let message):
+            return "Remote ASR error: \(message)"
+        }
+    }
+}
+
+/// Speech recognizer factory that streams audio to a remote ASR service
+/// over WebSocket, speaking Deepgram's live `/v1/listen` protocol. Used
+/// on Macs that can't run SpeechAnalyzer (see `TranscriptionBackend`).
+/// Transcripts come back as the same `RecognizerResult`s the on-device
+/// recognizer yields, so the engine, database and event stream don't
+/// know which backend produced them.
+public final class RemoteSpeechRecognizerFactory: SpeechRecognizerFactory, Sendable {
+    /// Deepgram's live transcription endpoint.
+    public static let defaultEndpoint = "wss://api.deepgram.com/v1/listen"
+
+    /// Audio is streamed as 16 kHz mono 16-bit PCM: enough for speech,
+    /// and a fraction of the capture format's bandwidth.
+    static let sampleRate: Double = 16_000
+
+    private let endpoint: String
+    private let apiKey: String
+
+    public init(endpoint: String = RemoteSpeechRecognizerFactory.defaultEndpoint, apiKey: String) {
+        self.endpoint = endpoint
+        self.apiKey = apiKey
+    }
+
+    public func makeRecognizer(locale: Locale, format: AVAudioFormat, source: AudioSourceType)
+        async throws -> SpeechRecognizerHandle {
+        let url = try Self.streamURL(endpoint: endpoint, locale: locale)
+        guard let streamFormat = AVAudioFormat(
+            commonFormat: .pcmFormatInt16,
+            sampleRate: Self.sampleRate,
+            channels: 1,
+            interleaved: true
+        ), let converter = AVAudioConverter(from: format, to: streamFormat) else {
+            throw RemoteASRError.unsupportedFormat
+        }
+        var request = URLRequest(url: url)
+        request.setValue("Token \(apiKey)", forHTTPHeaderField: "Authorization")
+        return RemoteSpeechRecognizerHandle(request: request, converter: converter, source: source)
+    }
+
+    /// Builds the stream URL: `endpoint` with the audio format we send,
+    /// plus interim results, punctuation and the locale's language unless
+    /// the endpoint already sets them (e.g. `?language=multi`).
+    static func streamURL(endpoint: String, locale: Locale) throws -> URL {
+        guard var components = URLComponents(string: endpoint),
+              components.scheme == "wss" || components.scheme == "ws",
+              components.host?.isEmpty == false else {
+            throw RemoteASRError.invalidEndpoint(endpoint)
+        }
+        let audio = ["encoding": "linear16", "sample_rate": "16000", "channels": "1"]
+        var items = (components.queryItems ?? []).filter { audio[$0.name] == nil }
+        for (name, value) in [
+            ("interim_results", "true"),
+            ("punctuate", "true"),
+            ("language", locale.identifier(.bcp47)),
+        ] where !items.contains(where: { $0.name == name }) {
+            items.append(URLQueryItem(name: name, value: value))
+        }
+        items += audio.sorted { $0.key < $1.key }.map { URLQueryItem(name: $0.key, value: $0.value) }
+        components.queryItems = items
+        guard let url = components.url else {
+            throw RemoteASRError.invalidEndpoint(endpoint)
+        }
+        return url
+    }
+}
+
+/// Remote recognizer handle wrapping one WebSocket stream.
+///
+/// Uses `@unchecked Sendable` because `AVAudioConverter` is not marked
+/// Sendable. The converter is only used by the stream's send task; the
+/// socket reference is guarded by `lock`.
+final class RemoteSpeechRecognizerHandle: SpeechRecognizerHandle, @unchecked Sendable {
+    private let request: URLRequest
+    private let converter: AVAudioConverter
+    private let source: AudioSourceType
+    private let lock = NSLock()
+    private var socket: URLSessionWebSocketTask?
+
+    init(request: URLRequest, converter: AVAudioConverter, source: AudioSourceType) {
+        self.request = request
+        self.converter = converter
+        self.source = source
+    }
+
+    func transcribe(buffers: AsyncStream<AVAudioPCMBuffer>)
+        -> AsyncThrowingStream<RecognizerResult, Error> {
+        let socket = URLSession.shared.webSocketTask(with: request)
+        lock.withLock { self.socket = socket }
+
+        let stream = RemoteStream(
+            socket: socket,
+            buffers: buffers,
+            converter: converter,
+            source: source
+        )
+
+        return AsyncThrowingStream { continuation in
+            let task = Task.detached {
+                await stream.run(continuation: continuation)
+            }
+            continuation.onTermination = { _ in
+                task.cancel()
+                // receive() doesn't return on task cancellation alone.
+                socket.cancel(with: .goingAway, reason: nil)
+            }
+        }
+    }
+
+    func stop() async {
+        // Ask the server to flush its final results; it closes the
+        // socket once done, which ends the stream.
+        let socket = lock.withLock { () -> URLSessionWebSocketTask? in
+            defer { self.socket = nil }
+            return self.socket
+        }
+        try? await socket?.send(.string(RemoteASRMessage.closeStream))
+    }
+}
+
+/// Packages the stream state into a Sendable value for `Task.detached`,
+/// as `Pipeline` does for the on-device recognizer.
+private struct RemoteStream: @unchecked Sendable {
+    let socket: URLSessionWebSocketTask
+    let buffers: AsyncStream<AVAudioPCMBuffer>
+    let converter: AVAudioConverter
+    let source: AudioSourceType
+
+    func run(continuation: AsyncThrowingStream<RecognizerResult, Error>.Continuation) async {
+        socket.resume()
+        do {
+            try await withThrowingTaskGroup(of: Void.self) { group in
+                // Task 1: Send audio as binary frames, then CloseStream
+                // once capture ends.
+                group.addTask {
+                    for await buffer in self.buffers {
+                        if let data = self.pcmData(buffer) {
+                            try await self.socket.send(.data(data))
+                        }
+                    }
+                    try await self.socket.send(.string(RemoteASRMessage.closeStream))
+                }
+
+                // Task 2: Read results until the server closes the socket.
+                group.addTask {
+                    while true {
+                        let message: URLSessionWebSocketTask.Message
+                        do {
+                            message = try await self.socket.receive()
+                        } catch {
+                            if self.socket.closeCode == .normalClosure {
+                                return
+                            }
+                            throw error
+                        }
+                        let data: Data
+                        switch message {
+                        case .string(let text):
+                            data = Data(text.utf8)
+                        case .data(let bytes):
+                            data = bytes
+                        @unknown default:
+                            continue
+                        }
+                        if let result = try RemoteASRMessage.result(from: data, source: self.source) {
+                            continuation.yield(result)
+                        }
+                    }
+                }
+
+                try await group.waitForAll()
+            }
+            continuation.finish()
+        } catch {
+            continuation.finish(throwing: error)
+        }
+        socket.cancel(with: .normalClosure, reason: nil)
+    }
+
+    /// Converts a capture buffer to the 16 kHz mono Int16 bytes we stream.
+    private func pcmData(_ buffer: AVAudioPCMBuffer) -> Data? {
+        let ratio = RemoteSpeechRecognizerFactory.sampleRate / buffer.format.sampleRate
+        let capacity = AVAudioFrameCount((Double(buffer.frameLength) * ratio).rounded(.up)) + 1
+        guard let converted = AVAudioPCMBuffer(pcmFormat: converter.outputFormat, frameCapacity: capacity) else {
+            return nil
+        }
+        // Hand the converter this buffer once; .noDataNow keeps its
+        // resampler state for the next buffer instead of ending the stream.
+        var supplied = false
+        var error: NSError?
+        converter.convert(to: converted, error: &error) { _, status in
+            if supplied {
+                status.pointee = .noDataNow
+                return nil
+            }
+            supplied = true
+            status.pointee = .haveData
+            return buffer
+        }
+        guard error == nil, converted.frameLength > 0, let samples = converted.int16ChannelData else {
+            return nil
+        }
+        return Data(bytes: samples[0], count: Int(converted.frameLength) * MemoryLayout<Int16>.size)
+    }
+}
+
+/// Deepgram live-protocol messages.
+enum RemoteASRMessage {
+    /// Tells the server no more audio is coming.
+    static let closeStream = #"{"type":"CloseStream"}"#
+
+    /// Decodes a server message into a recognizer result. Nil for
+    /// metadata, speech-started and other non-transcript messages, and
+    /// for results with no words.
+    static func result(from data: Data, source: AudioSourceType) throws -> RecognizerResult? {
+        let message = try JSONDecoder().decode(Message.self, from: data)
+        switch message.type {
+        case "Results":
+            guard let alternative = message.channel?.alternatives.first else {
+                return nil
+            }
+            let text = alternative.transcript.trimmingCharacters(in: .whitespacesAndNewlines)
+            guard !text.isEmpty else {
+                return nil
+            }
+            return RecognizerResult(
+                text: text,
+                isFinal: message.is_final ?? false,
+                confidence: alternative.confidence.map(Float.init),
+                source: source
+            )
+        case "Error":
+            throw RemoteASRError.serverError(message.description ?? message.message ?? "unknown error")
+        default:
+            return nil
+        }
+    }
+
+    private struct Message: Decodable {
+        let type: String
+        let is_final: Bool?
+        let channel: Channel?
+        let description: String?
+        let message: String?
+    }
+
+    private struct Channel: Decodable {
+        let alternatives: [Alternative]
+    }
+
+    private struct Alternative: Decodable {
+        let transcript: String
+        let confidence: Double?
+    }
+}
@@ -0,0 +1,41 @@
+import Foundation
+
+/// Decides when an armed engine has heard enough speech to start a
+/// session. The mic peak must stay at or above `threshold` for `sustain`
+/// seconds, with quiet dips no longer than `maxGap` (the pauses between
+/// words). A level heuristic rather than a recognizer, so arming costs
+/// no speech model, and a door slam or one cough doesn't start recording.
+public struct SpeechOnsetDetector: Sendable {
+    /// Linear peak (0.0–1.0) that counts as speech. 0.05 is about -26 dBFS.
+    public let threshold: Float
+    public let sustain: TimeInterval
+    public let maxGap: TimeInterval
+
+    private var onsetAt: Date?
+    private var lastLoudAt: Date?
+
+    public init(threshold: Float = 0.05, sustain: TimeInterval = 2.0, maxGap: TimeInterval = 0.6) {
+        self.threshold = threshold
+        self.sustain = sustain
+        self.maxGap = maxGap
+    }
+
+    /// Feed one buffer's peak level, observed at `now`. Returns true once
+    /// speech has been sustained long enough.
+    public mutating func feed(_ peak: Float, at now: Date) -> Bool {
+        guard peak >= threshold else {
+            if let last = lastLoudAt, now.timeIntervalSince(last) > maxGap {
+                onsetAt = nil
+                lastLoudAt = nil
+            }
+            return false
+        }
+        if let last = lastLoudAt, now.timeIntervalSince(last) > maxGap {
+            onsetAt = nil
+        }
+        let onset = onsetAt ?? now
+        onsetAt = onset
+        lastLoudAt = now
+        return now.timeIntervalSince(onset) >= sustain
+    }
+}
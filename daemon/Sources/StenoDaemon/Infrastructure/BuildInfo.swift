@@ -0,0 +1,26 @@
+import Foundation
+
+/// Version and build description reported by `steno-daemon --version` and
+/// the `version` socket command. The Go TUI compares `version` against its
+/// own (`internal/version`) and warns on a major/minor mismatch.
+public enum BuildInfo {
+    /// Release version. Keep in step with `CFBundleShortVersionString` in
+    /// Resources/Info.plist and `version.Version` in the Go module.
+    public static let version = "0.1.0"
+
+    /// Revision of the socket protocol, exchanged on `version`. Keep in
+    /// step with `daemon.ProtocolVersion` in the Go module; bump both when
+    /// a change needs both sides to understand it. 2 adds command ids.
+    public static let protocolVersion = 2
+
+    /// Build configuration and host OS, e.g. "release macOS 26.0.1".
+    public static var build: String {
+        #if DEBUG
+        let configuration = "debug"
+        #else
+        let configuration = "release"
+        #endif
+        let os = ProcessInfo.processInfo.operatingSystemVersion
+        return "\(configuration) macOS \(os.majorVersion).\(os.minorVersion).\(os.patchVersion)"
+    }
+}
@@ -0,0 +1,233 @@
+import CoreAudio
+import Foundation
+
+// MARK: - Known apps
+
+/// A conferencing app whose use of the mic means a call is on.
+public struct ConferencingApp: Sendable, Equatable {
+    public let bundleID: String
+    /// Name sessions are tagged with.
+    public let name: String
+
+    public init(bundleID: String, name: String) {
+        self.bundleID = bundleID
+        self.name = name
+    }
+
+    /// The apps detection looks for, in priority order when several are
+    /// on the mic at once. Browsers are here because Google Meet (and the
+    /// web versions of the others) run in one; their calls are tagged
+    /// with the browser's name.
+    public static let known: [ConferencingApp] = [
+        ConferencingApp(bundleID: "us.zoom.xos", name: "Zoom"),
+        ConferencingApp(bundleID: "com.microsoft.teams2", name: "Microsoft Teams"),
+        ConferencingApp(bundleID: "com.microsoft.teams", name: "Microsoft Teams"),
+        ConferencingApp(bundleID: "com.cisco.webexmeetingsapp", name: "Webex"),
+        ConferencingApp(bundleID: "Cisco-Systems.Spark", name: "Webex"),
+        ConferencingApp(bundleID: "com.apple.FaceTime", name: "FaceTime"),
+        ConferencingApp(bundleID: "com.tinyspeck.slackmacgap", name: "Slack"),
+        ConferencingApp(bundleID: "com.hnc.Discord", name: "Discord"),
+        ConferencingApp(bundleID: "com.google.Chrome", name: "Google Chrome"),
+        ConferencingApp(bundleID: "com.apple.Safari", name: "Safari"),
+        ConferencingApp(bundleID: "company.thebrowser.Browser", name: "Arc"),
+        ConferencingApp(bundleID: "com.microsoft.edgemac", name: "Microsoft Edge"),
+        ConferencingApp(bundleID: "com.brave.Browser", name: "Brave"),
+        ConferencingApp(bundleID: "org.mozilla.firefox", name: "Firefox"),
+    ]
+
+    /// The known app a capturing process belongs to. Apps often capture
+    /// from a helper (`com.google.Chrome.helper`), so a bundle ID nested
+    /// under a known one counts as that app.
+    public static func owning(bundleID: String) -> ConferencingApp? {
+        known.first { bundleID == $0.bundleID || bundleID.hasPrefix($0.bundleID + ".") }
+    }
+}
+
+// MARK: - Detector
+
+/// A call starting or ending, as `CallDetector` sees it.
+public enum CallTransition: Sendable, Equatable {
+    case started(ConferencingApp)
+    case ended(ConferencingApp)
+}
+
+/// Turns polls of which processes are using the mic into call starts and
+/// ends. An app must stay on the mic for `startAfter` polls before its
+/// call counts, so an audio-settings preview doesn't start one, and off
+/// it for `endAfter` polls before the call ends, so a mute-and-reopen
+/// doesn't split one. One call is tracked at a time.
+public struct CallDetector: Sendable {
+    public let startAfter: Int
+    public let endAfter: Int
+
+    /// The app whose call is in progress, if any.
+    public private(set) var current: ConferencingApp?
+    private var candidate: ConferencingApp?
+    private var candidatePolls = 0
+    private var missedPolls = 0
+
+    public init(startAfter: Int = 2, endAfter: Int = 3) {
+        self.startAfter = startAfter
+        self.endAfter = endAfter
+    }
+
+    /// Feed one poll: the bundle IDs of processes capturing input.
+    /// Returns what changed, in order (an end, then a start, when one
+    /// call hands over to another app's).
+    public mutating func observe(_ capturing: Set<String>) -> [CallTransition] {
+        let apps = ConferencingApp.known.filter { app in
+            capturing.contains { ConferencingApp.owning(bundleID: $0) == app }
+        }
+        var transitions: [CallTransition] = []
+
+        if let call = current {
+            if apps.contains(call) {
+                missedPolls = 0
+                return []
+            }
+            missedPolls += 1
+            guard missedPolls >= endAfter else { return [] }
+            current = nil
+            missedPolls = 0
+            transitions.append(.ended(call))
+        }
+
+        guard let app = apps.first else {
+            candidate = nil
+            candidatePolls = 0
+            return transitions
+        }
+        candidatePolls = candidate == app ? candidatePolls + 1 : 1
+        candidate = app
+        if candidatePolls >= startAfter {
+            current = app
+            candidate = nil
+            candidatePolls = 0
+            transitions.append(.started(app))
+        }
+        return transitions
+    }
+}
+
+// MARK: - Core Audio
+
+/// Bundle IDs of the processes currently capturing audio input, other
+/// than this one, via the Core Audio HAL's process objects. The daemon's
+/// own mic capture is excluded so it never looks like a call. Empty when
+/// the HAL can't be read.
+public func processesCapturingInput() -> Set<String> {
+    let system = AudioObjectID(kAudioObjectSystemObject)
+    var address = AudioObjectPropertyAddress(
+        mSelector: kAudioHardwarePropertyProcessObjectList,
+        mScope: kAudioObjectPropertyScopeGlobal,
+        mElement: kAudioObjectPropertyElementMain
+    )
+    var size: UInt32 = 0
+    guard AudioObjectGetPropertyDataSize(system, &address, 0, nil, &size) == noErr, size > 0 else {
+        return []
+    }
+    var processes = [AudioObjectID](repeating: 0, count: Int(size) / MemoryLayout<AudioObjectID>.size)
+    guard AudioObjectGetPropertyData(system, &address, 0, nil, &size, &processes) == noErr else {
+        return []
+    }
+
+    let ownPID = ProcessInfo.processInfo.processIdentifier
+    var bundleIDs = Set<String>()
+    for process in processes {
+        var running: UInt32 = 0
+        guard processProperty(process, kAudioProcessPropertyIsRunningInput, &running), running != 0 else {
+            continue
+        }
+        var pid: pid_t = 0
+        if processProperty(process, kAudioProcessPropertyPID, &pid), pid == ownPID {
+            continue
+        }
+        var bundleID: CFString = "" as CFString
+        // As in `defaultInputDeviceUID()`: let ARC retain the CFString.
+        guard processProperty(process, kAudioProcessPropertyBundleID, &bundleID) else { continue }
+        let id = bundleID as String
+        if !id.isEmpty {
+            bundleIDs.insert(id)
+        }
+    }
+    return bundleIDs
+}
+
+/// Reads one global property of a Core Audio process object.
+private func processProperty<T>(_ process: AudioObjectID, _ selector: AudioObjectPropertySelector, _ value: inout T) -> Bool {
+    var address = AudioObjectPropertyAddress(
+        mSelector: selector,
+        mScope: kAudioObjectPropertyScopeGlobal,
+        mElement: kAudioObjectPropertyElementMain
+    )
+    var size = UInt32(MemoryLayout<T>.size)
+    return withUnsafeMutablePointer(to: &value) { ptr in
+        AudioObjectGetPropertyData(process, &address, 0, nil, &size, ptr) == noErr
+    }
+}
+
+// MARK: - Observer
+
+/// Polls which processes are using the mic and reports conferencing
+/// calls starting and ending (see `CallDetector`). The HAL's process list
+/// has no change notification for "started capturing" that covers
+/// helpers reliably, so this polls; a 2s interval costs a handful of
+/// property reads.
+public final class ConferencingAppObserver: @unchecked Sendable {
+    private let interval: Duration
+    private let capturingProvider: @Sendable () -> Set<String>
+
+    private let lock = NSLock()
+    private var detector: CallDetector
+    private var task: Task<Void, Never>?
+
+    /// - Parameters:
+    ///   - interval: Time between polls.
+    ///   - detector: Start / end thresholds, in polls.
+    ///   - capturingProvider: Production passes
+    ///     `processesCapturingInput()`; tests inject synthetic sets.
+    public init(
+        interval: Duration = .seconds(2),
+        detector: CallDetector = CallDetector(),
+        capturingProvider: @Sendable @escaping () -> Set<String> = { processesCapturingInput() }
+    ) {
+        self.interval = interval
+        self.detector = detector
+        self.capturingProvider = capturingProvider
+    }
+
+    deinit {
+        task?.cancel()
+    }
+
+    /// Begin polling, reporting each transition to `handler` in order.
+    /// Starting a started observer is a no-op.
+    public func start(handler: @Sendable @escaping (CallTransition) async -> Void) {
+        lock.lock(); defer { lock.unlock() }
+        guard task == nil else { return }
+        let interval = interval
+        task = Task { [weak self] in
+            while !Task.isCancelled {
+                guard let transitions = self?.poll() else { return }
+                for transition in transitions {
+                    await handler(transition)
+                }
+                try? await Task.sleep(for: interval)
+            }
+        }
+    }
+
+    /// Stop polling. A call in progress is not reported as ended.
+    public func stop() {
+        lock.lock(); defer { lock.unlock() }
+        task?.cancel()
+        task = nil
+    }
+
+    /// Run one poll. Exposed for tests, which drive polls directly.
+    func poll() -> [CallTransition] {
+        let capturing = capturingProvider()
+        lock.lock(); defer { lock.unlock() }
+        return detector.observe(capturing)
+    }
+}
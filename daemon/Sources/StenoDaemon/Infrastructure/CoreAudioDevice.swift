@@ -55,3 +55,56 @@ public func defaultInputDeviceUID() -> String? {
     guard uidStatus == noErr else { return nil }
     return cfStringRef as String
 }
+
+/// List the UIDs of every device with at least one input stream via Core
+/// Audio HAL.
+///
+/// Used to fingerprint the current audio setup (see `AudioSetup`) so the
+/// daemon can restore the microphone last chosen with this set of
+/// devices attached. Devices whose UID can't be read are skipped; an
+/// empty list means the HAL couldn't be queried.
+public func inputDeviceUIDs() -> [String] {
+    let system = AudioObjectID(kAudioObjectSystemObject)
+    var devicesAddress = AudioObjectPropertyAddress(
+        mSelector: kAudioHardwarePropertyDevices,
+        mScope: kAudioObjectPropertyScopeGlobal,
+        mElement: kAudioObjectPropertyElementMain
+    )
+    var size: UInt32 = 0
+    guard AudioObjectGetPropertyDataSize(system, &devicesAddress, 0, nil, &size) == noErr, size > 0 else {
+        return []
+    }
+    var devices = [AudioDeviceID](repeating: 0, count: Int(size) / MemoryLayout<AudioDeviceID>.size)
+    guard AudioObjectGetPropertyData(system, &devicesAddress, 0, nil, &size, &devices) == noErr else {
+        return []
+    }
+
+    var uids: [String] = []
+    for device in devices {
+        var streamsAddress = AudioObjectPropertyAddress(
+            mSelector: kAudioDevicePropertyStreams,
+            mScope: kAudioDevicePropertyScopeInput,
+            mElement: kAudioObjectPropertyElementMain
+        )
+        var streamsSize: UInt32 = 0
+        guard AudioObjectGetPropertyDataSize(device, &streamsAddress, 0, nil, &streamsSize) == noErr,
+              streamsSize > 0 else {
+            continue
+        }
+
+        var uidAddress = AudioObjectPropertyAddress(
+            mSelector: kAudioDevicePropertyDeviceUID,
+            mScope: kAudioObjectPropertyScopeGlobal,
+            mElement: kAudioObjectPropertyElementMain
+        )
+        var cfStringRef: CFString = "" as CFString
+        var uidSize = UInt32(MemoryLayout<CFString?>.size)
+        // As in `defaultInputDeviceUID()`: let ARC retain the CFString.
+        let uidStatus = withUnsafeMutablePointer(to: &cfStringRef) { ptr -> OSStatus in
+            AudioObjectGetPropertyData(device, &uidAddress, 0, nil, &uidSize, ptr)
+        }
+        guard uidStatus == noErr else { continue }
+        uids.append(cfStringRef as String)
+    }
+    return uids
+}
@@ -0,0 +1,12 @@
+import AppKit
+
+/// Name of the application that currently has focus (e.g. "zoom.us"),
+/// falling back to its bundle identifier.
+///
+/// The engine snapshots this whenever a session opens so clients can
+/// tell apart similar meetings in the session browser. Returns `nil` when
+/// nothing is frontmost (login window, locked screen).
+public func frontmostApplicationName() -> String? {
+    guard let app = NSWorkspace.shared.frontmostApplication else { return nil }
+    return app.localizedName ?? app.bundleIdentifier
+}
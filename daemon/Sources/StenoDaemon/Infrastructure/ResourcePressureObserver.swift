@@ -0,0 +1,117 @@
+import Foundation
+
+// MARK: - Pressure
+
+/// How hard-pressed the machine is, as far as macOS lets a process see.
+/// There's no public API for GPU or Neural Engine load, but the system
+/// throttles both as the thermal state rises, so the thermal state (with
+/// Low Power Mode, which caps them too) stands in for contention.
+public struct ResourcePressure: Sendable, Equatable {
+    /// `ProcessInfo.ThermalState` as a wire string: "nominal", "fair",
+    /// "serious" or "critical".
+    public let thermalState: String
+    /// Whether Low Power Mode is on.
+    public let lowPower: Bool
+
+    public init(thermalState: String, lowPower: Bool) {
+        self.thermalState = thermalState
+        self.lowPower = lowPower
+    }
+
+    public static let nominal = ResourcePressure(thermalState: "nominal", lowPower: false)
+
+    /// True at "serious" or "critical", where the system is already
+    /// throttling, or in Low Power Mode. Summaries deferred under
+    /// `SummaryDeferral` wait while this holds.
+    public var isUnderPressure: Bool {
+        lowPower || thermalState == "serious" || thermalState == "critical"
+    }
+
+    /// The pressure right now.
+    public static func current() -> ResourcePressure {
+        let info = ProcessInfo.processInfo
+        return ResourcePressure(thermalState: name(of: info.thermalState), lowPower: info.isLowPowerModeEnabled)
+    }
+
+    static func name(of state: ProcessInfo.ThermalState) -> String {
+        switch state {
+        case .nominal: return "nominal"
+        case .fair: return "fair"
+        case .serious: return "serious"
+        case .critical: return "critical"
+        @unknown default: return "nominal"
+        }
+    }
+}
+
+// MARK: - Observer
+
+/// Reports thermal state and Low Power Mode changes. Both come with
+/// notifications on `NotificationCenter.default`, so unlike call
+/// detection nothing is polled; each notification re-reads both and
+/// reports only an actual change.
+public final class ResourcePressureObserver: @unchecked Sendable {
+    private let pressureProvider: @Sendable () -> ResourcePressure
+    private let center: NotificationCenter
+
+    private let lock = NSLock()
+    private var last: ResourcePressure?
+    private var tokens: [NSObjectProtocol] = []
+
+    /// - Parameters:
+    ///   - center: Where the notifications are posted. Tests pass their
+    ///     own center and post to it.
+    ///   - pressureProvider: Production passes `ResourcePressure.current()`;
+    ///     tests inject synthetic values.
+    public init(
+        center: NotificationCenter = .default,
+        pressureProvider: @Sendable @escaping () -> ResourcePressure = { ResourcePressure.current() }
+    ) {
+        self.center = center
+        self.pressureProvider = pressureProvider
+    }
+
+    deinit {
+        stop()
+    }
+
+    /// Report the pressure now, then each change, to `handler`. Starting
+    /// a started observer is a no-op.
+    public func start(handler: @Sendable @escaping (ResourcePressure) async -> Void) {
+        lock.lock()
+        guard tokens.isEmpty else { lock.unlock(); return }
+        let names: [Notification.Name] = [
+            ProcessInfo.thermalStateDidChangeNotification,
+            .NSProcessInfoPowerStateDidChange,
+        ]
+        tokens = names.map { name in
+            center.addObserver(forName: name, object: nil, queue: nil) { [weak self] _ in
+                guard let pressure = self?.check() else { return }
+                Task { await handler(pressure) }
+            }
+        }
+        lock.unlock()
+        if let pressure = check() {
+            Task { await handler(pressure) }
+        }
+    }
+
+    /// Stop observing.
+    public func stop() {
+        lock.lock(); defer { lock.unlock() }
+        for token in tokens {
+            center.removeObserver(token)
+        }
+        tokens = []
+    }
+
+    /// Re-read the pressure, returning it if it changed since the last
+    /// read. Exposed for tests.
+    func check() -> ResourcePressure? {
+        let pressure = pressureProvider()
+        lock.lock(); defer { lock.unlock() }
+        guard pressure != last else { return nil }
+        last = pressure
+        return pressure
+    }
+}
@@ -13,6 +13,58 @@ public enum SummarizationProvider: String, Codable, CaseIterable, Sendable {
     }
 }
 
+/// Which speech recognizer transcribes the audio.
+public enum TranscriptionBackend: String, Codable, CaseIterable, Sendable {
+    /// On-device SpeechAnalyzer when this Mac supports it, else remote
+    /// streaming ASR if configured.
+    case auto = "auto"
+    /// On-device SpeechAnalyzer only.
+    case local = "local"
+    /// Remote streaming ASR (Deepgram's live API or a compatible server).
+    case remote = "remote"
+
+    /// The backend to record with, given whether on-device ASR works on
+    /// this Mac and whether a remote API key is set. Remote without a key
+    /// can't connect, so it falls back to local.
+    public func resolved(localAvailable: Bool, remoteConfigured: Bool) -> TranscriptionBackend {
+        switch self {
+        case .local:
+            return .local
+        case .remote:
+            return remoteConfigured ? .remote : .local
+        case .auto:
+            return !localAvailable && remoteConfigured ? .remote : .local
+        }
+    }
+}
+
+/// What the daemon does when a conferencing app starts using the mic
+/// (see `ConferencingAppObserver`).
+public enum CallDetection: String, Codable, CaseIterable, Sendable {
+    /// Don't watch for calls.
+    case off = "off"
+    /// Tell clients, which offer to give the call its own session.
+    case prompt = "prompt"
+    /// Give the call its own session: start recording, or split the
+    /// current session, and split again when the call ends.
+    case auto = "auto"
+}
+
+/// When rolling summaries wait for the machine to cool down (see
+/// `ResourcePressure.isUnderPressure`). Deferred summaries aren't lost:
+/// the next segment after the pressure eases summarizes everything
+/// since the last one.
+public enum SummaryDeferral: String, Codable, CaseIterable, Sendable {
+    /// Summarize regardless.
+    case off = "off"
+    /// Defer while under pressure during a detected call, when the
+    /// screen is most likely being shared and a stutter shows. Needs
+    /// `callDetection`, since the daemon can't see screen sharing itself.
+    case calls = "calls"
+    /// Defer whenever under pressure.
+    case always = "always"
+}
+
 /// Application settings persisted to disk.
 public struct StenoSettings: Codable, Sendable {
     /// The preferred summarization provider.
@@ -34,6 +86,26 @@ public struct StenoSettings: Codable, Sendable {
     /// Anthropic model to use.
     public var anthropicModel: String
 
+    /// Speech recognizer to transcribe with. Default `.auto`: on-device
+    /// unless this Mac can't run SpeechAnalyzer.
+    public var transcriptionBackend: TranscriptionBackend
+
+    /// Remote streaming ASR WebSocket endpoint. Speaks Deepgram's live
+    /// `/v1/listen` protocol.
+    public var remoteASRURL: String
+
+    /// Remote ASR API key. Can also be set via DEEPGRAM_API_KEY.
+    public var remoteASRAPIKey: String?
+
+    /// Returns the effective remote ASR key, checking the environment
+    /// variable first.
+    public var effectiveRemoteASRAPIKey: String? {
+        if let envKey = ProcessInfo.processInfo.environment["DEEPGRAM_API_KEY"], !envKey.isEmpty {
+            return envKey
+        }
+        return remoteASRAPIKey
+    }
+
     /// Last device string used in a successful `start(...)` call. Used by
     /// U4's daemon-start auto-start path to restore the user's last-known
     /// microphone selection. `nil` means "use the system default mic" —
@@ -41,6 +113,13 @@ public struct StenoSettings: Codable, Sendable {
     /// so we prefer that over a hardcoded device name (which varies per Mac).
     public var lastDevice: String?
 
+    /// The device last used with each audio setup, keyed by
+    /// `AudioSetup.fingerprint(_:)` of the input devices attached. An
+    /// empty string records a choice of the system default mic. Lets
+    /// docking and undocking each restore their own mic rather than
+    /// whichever was used last anywhere. See `preferredDevice(forSetup:)`.
+    public var devicePreferences: [String: String]
+
     /// Last system-audio capture flag. Defaults to `true` per the plan's
     /// always-on goal — system audio is part of the captured world unless
     /// the user explicitly turns it off.
@@ -106,11 +185,23 @@ public struct StenoSettings: Codable, Sendable {
     /// rolling window for privacy or storage reasons.
     public var retentionDays: Int
 
+    /// Whether to watch for conferencing calls, and what to do about one.
+    /// Default `.off`: watching polls which apps use the mic.
+    public var callDetection: CallDetection
+
+    /// Whether to hold rolling summaries back while the machine is under
+    /// thermal or power pressure. Default `.off`.
+    public var deferSummaries: SummaryDeferral
+
     public init(
         summarizationProvider: SummarizationProvider = .local,
         anthropicAPIKey: String? = nil,
         anthropicModel: String = "claude-3-5-haiku-20241022",
+        transcriptionBackend: TranscriptionBackend = .auto,
+        remoteASRURL: String = RemoteSpeechRecognizerFactory.defaultEndpoint,
+        remoteASRAPIKey: String? = nil,
         lastDevice: String? = nil,
+        devicePreferences: [String: String] = [:],
         lastSystemAudioEnabled: Bool = true,
         healGapSeconds: Int = 30,
         dedupOverlapSeconds: Double = 3.0,
@@ -120,12 +211,18 @@ public struct StenoSettings: Codable, Sendable {
         emptySessionMinChars: Int = 20,
         emptySessionMinDurationSeconds: Double = 3.0,
         topicExtractionMinSegments: Int = 3,
-        retentionDays: Int = 0
+        retentionDays: Int = 0,
+        callDetection: CallDetection = .off,
+        deferSummaries: SummaryDeferral = .off
     ) {
         self.summarizationProvider = summarizationProvider
         self.anthropicAPIKey = anthropicAPIKey
         self.anthropicModel = anthropicModel
+        self.transcriptionBackend = transcriptionBackend
+        self.remoteASRURL = remoteASRURL
+        self.remoteASRAPIKey = remoteASRAPIKey
         self.lastDevice = lastDevice
+        self.devicePreferences = devicePreferences
         self.lastSystemAudioEnabled = lastSystemAudioEnabled
         self.healGapSeconds = healGapSeconds
         self.dedupOverlapSeconds = dedupOverlapSeconds
@@ -136,6 +233,8 @@ public struct StenoSettings: Codable, Sendable {
         self.emptySessionMinDurationSeconds = emptySessionMinDurationSeconds
         self.topicExtractionMinSegments = topicExtractionMinSegments
         self.retentionDays = retentionDays
+        self.callDetection = callDetection
+        self.deferSummaries = deferSummaries
     }
 
     // MARK: - Codable
@@ -147,7 +246,11 @@ public struct StenoSettings: Codable, Sendable {
         case summarizationProvider
         case anthropicAPIKey
         case anthropicModel
+        case transcriptionBackend
+        case remoteASRURL
+        case remoteASRAPIKey
         case lastDevice
+        case devicePreferences
         case lastSystemAudioEnabled
         case healGapSeconds
         case dedupOverlapSeconds
@@ -158,6 +261,8 @@ public struct StenoSettings: Codable, Sendable {
         case emptySessionMinDurationSeconds
         case topicExtractionMinSegments
         case retentionDays
+        case callDetection
+        case deferSummaries
     }
 
     public init(from decoder: Decoder) throws {
@@ -165,7 +270,11 @@ public struct StenoSettings: Codable, Sendable {
         self.summarizationProvider = try container.decodeIfPresent(SummarizationProvider.self, forKey: .summarizationProvider) ?? .local
         self.anthropicAPIKey = try container.decodeIfPresent(String.self, forKey: .anthropicAPIKey)
         self.anthropicModel = try container.decodeIfPresent(String.self, forKey: .anthropicModel) ?? "claude-3-5-haiku-20241022"
+        self.transcriptionBackend = try container.decodeIfPresent(TranscriptionBackend.self, forKey: .transcriptionBackend) ?? .auto
+        self.remoteASRURL = try container.decodeIfPresent(String.self, forKey: .remoteASRURL) ?? RemoteSpeechRecognizerFactory.defaultEndpoint
+        self.remoteASRAPIKey = try container.decodeIfPresent(String.self, forKey: .remoteASRAPIKey)
         self.lastDevice = try container.decodeIfPresent(String.self, forKey: .lastDevice)
+        self.devicePreferences = try container.decodeIfPresent([String: String].self, forKey: .devicePreferences) ?? [:]
         self.lastSystemAudioEnabled = try container.decodeIfPresent(Bool.self, forKey: .lastSystemAudioEnabled) ?? true
         self.healGapSeconds = try container.decodeIfPresent(Int.self, forKey: .healGapSeconds) ?? 30
         self.dedupOverlapSeconds = try container.decodeIfPresent(Double.self, forKey: .dedupOverlapSeconds) ?? 3.0
@@ -176,6 +285,29 @@ public struct StenoSettings: Codable, Sendable {
         self.emptySessionMinDurationSeconds = try container.decodeIfPresent(Double.self, forKey: .emptySessionMinDurationSeconds) ?? 3.0
         self.topicExtractionMinSegments = try container.decodeIfPresent(Int.self, forKey: .topicExtractionMinSegments) ?? 3
         self.retentionDays = try container.decodeIfPresent(Int.self, forKey: .retentionDays) ?? 0
+        self.callDetection = try container.decodeIfPresent(CallDetection.self, forKey: .callDetection) ?? .off
+        self.deferSummaries = try container.decodeIfPresent(SummaryDeferral.self, forKey: .deferSummaries) ?? .off
+    }
+
+    // MARK: - Device preferences
+
+    /// The device to start with on the audio setup `setup`: the one last
+    /// used with it, else `lastDevice`. A `nil` setup (devices couldn't
+    /// be listed) or one never seen falls back to `lastDevice`.
+    public func preferredDevice(forSetup setup: String?) -> String? {
+        guard let setup, let device = devicePreferences[setup] else {
+            return lastDevice
+        }
+        return device.isEmpty ? nil : device
+    }
+
+    /// Record `device` as the last used, overall and for `setup` when
+    /// it's known.
+    public mutating func rememberDevice(_ device: String?, forSetup setup: String?) {
+        lastDevice = device
+        if let setup {
+            devicePreferences[setup] = device ?? ""
+        }
     }
 
     // MARK: - Persistence
@@ -55,6 +55,16 @@ public struct StoredSegment: Sendable, Codable, Identifiable, Equatable {
     /// per-segment metering landed.
     public let micPeakDb: Double?
 
+    /// Recognition locale in force when this segment was finalized, which
+    /// `set_locale` can change mid-session. `nil` for rows persisted
+    /// before per-segment locales; read those as the session's locale.
+    public let locale: String?
+
+    /// Diarized speaker label (e.g. "Speaker 1"), stable within the
+    /// session. `nil` until diarization labels the segment, and for rows
+    /// persisted before per-segment speakers.
+    public let speaker: String?
+
     public init(
         id: UUID = UUID(),
         sessionId: UUID,
@@ -68,7 +78,9 @@ public struct StoredSegment: Sendable, Codable, Identifiable, Equatable {
         healMarker: String? = nil,
         duplicateOf: UUID? = nil,
         dedupMethod: DedupMethod? = nil,
-        micPeakDb: Double? = nil
+        micPeakDb: Double? = nil,
+        locale: String? = nil,
+        speaker: String? = nil
     ) {
         self.id = id
         self.sessionId = sessionId
@@ -83,6 +95,8 @@ public struct StoredSegment: Sendable, Codable, Identifiable, Equatable {
         self.duplicateOf = duplicateOf
         self.dedupMethod = dedupMethod
         self.micPeakDb = micPeakDb
+        self.locale = locale
+        self.speaker = speaker
     }
 
     /// Create a stored segment from a streaming TranscriptSegment.
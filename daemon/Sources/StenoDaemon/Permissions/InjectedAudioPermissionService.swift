@@ -0,0 +1,18 @@
+import Foundation
+
+/// Permission service for `run --test-mode`. The mic source plays
+/// injected files (`InjectedAudioSourceFactory`), so no input device is
+/// opened and there is no microphone grant to wait for — which a CI
+/// runner has no way to give.
+public final class InjectedAudioPermissionService: PermissionService, Sendable {
+
+    public init() {}
+
+    public func requestMicrophoneAccess() async -> Bool {
+        true
+    }
+
+    public func checkPermissions() async -> PermissionStatus {
+        .granted
+    }
+}
@@ -219,6 +219,55 @@ public final class AnthropicSummarizationService: SummarizationService, Sendable
         return TopicParser.parse(textContent.text, sessionId: sessionId)
     }
 
+    private let cleanVerbatimPrompt = """
+        You are a transcript editor. Rewrite the transcribed speech as clean verbatim:
+        remove filler words (um, uh, like, you know), stutters and false starts,
+        and fix casing and punctuation. Keep the speaker's words and meaning;
+        do not summarize, answer, or add anything.
+        Return ONLY the rewritten text.
+        """
+
+    public func cleanVerbatim(_ text: String) async throws -> String {
+        guard await isAvailable else {
+            throw SummarizationError.modelNotAvailable
+        }
+
+        guard !text.trimmingCharacters(in: .whitespacesAndNewlines).isEmpty else { return text }
+
+        let requestBody = AnthropicRequest(
+            model: model,
+            max_tokens: 400,
+            system: cleanVerbatimPrompt,
+            messages: [
+                AnthropicMessage(role: "user", content: text)
+            ]
+        )
+
+        let request = try makeRequest(body: requestBody)
+        let (data, response) = try await URLSession.shared.data(for: request)
+
+        guard let httpResponse = response as? HTTPURLResponse else {
+            throw SummarizationError.networkError("Invalid response")
+        }
+
+        guard httpResponse.statusCode == 200 else {
+            let errorBody = String(data: data, encoding: .utf8) ?? "Unknown error"
+            throw SummarizationError.apiError(httpResponse.statusCode, errorBody)
+        }
+
+        let anthropicResponse = try JSONDecoder().decode(AnthropicResponse.self, from: data)
+
+        if let usage = anthropicResponse.usage {
+            onTokenUsage?(TokenUsage(inputTokens: usage.input_tokens, outputTokens: usage.output_tokens))
+        }
+
+        guard let textContent = anthropicResponse.content.first(where: { $0.type == "text" }) else {
+            throw SummarizationError.networkError("No text content in response")
+        }
+
+        return textContent.text.trimmingCharacters(in: .whitespacesAndNewlines)
+    }
+
     private func makeRequest(body: AnthropicRequest) throws -> URLRequest {
         guard let url = URL(string: baseURL) else {
             throw SummarizationError.networkError("Invalid URL")
@@ -198,4 +198,30 @@ public final class FoundationModelSummarizationService: SummarizationService, Se
         let response = try await session.respond(to: prompt, options: options)
         return TopicParser.parse(response.content, sessionId: sessionId)
     }
+
+    private let cleanVerbatimPrompt = """
+        You are a transcript editor. Rewrite the transcribed speech as clean verbatim:
+        remove filler words (um, uh, like, you know), stutters and false starts,
+        and fix casing and punctuation. Keep the speaker's words and meaning;
+        do not summarize, answer, or add anything.
+        Return ONLY the rewritten text.
+        """
+
+    public func cleanVerbatim(_ text: String) async throws -> String {
+        guard await isAvailable else {
+            throw SummarizationError.modelNotAvailable
+        }
+
+        guard !text.trimmingCharacters(in: .whitespacesAndNewlines).isEmpty else { return text }
+
+        let session = LanguageModelSession { cleanVerbatimPrompt }
+
+        let options = GenerationOptions(
+            sampling: .greedy,
+            maximumResponseTokens: 400
+        )
+
+        let response = try await session.respond(to: text, options: options)
+        return response.content.trimmingCharacters(in: .whitespacesAndNewlines)
+    }
 }
@@ -35,6 +35,15 @@ public protocol SummarizationService: Sendable {
     /// - Returns: Array of extracted topics, or empty array on failure.
     /// - Throws: `SummarizationError` if extraction fails.
     func extractTopics(segments: [StoredSegment], previousTopics: [Topic], sessionId: UUID) async throws -> [Topic]
+
+    /// Rewrite one transcript segment as clean verbatim: filler words and
+    /// false starts removed, casing and punctuation fixed, the wording and
+    /// meaning otherwise kept.
+    ///
+    /// - Parameter text: The segment's verbatim text.
+    /// - Returns: The cleaned text.
+    /// - Throws: `SummarizationError` if generation fails.
+    func cleanVerbatim(_ text: String) async throws -> String
 }
 
 /// Errors that can occur during summarization.
@@ -19,6 +19,28 @@ public struct DaemonCommand: Codable, Sendable {
     /// only by `paused_indefinitely=1` on the most-recent session row.
     public let indefinite: Bool?
 
+    /// `inject_audio`: the audio file to feed into the microphone source.
+    /// Only a daemon run with `--test-mode` accepts it.
+    public let path: String?
+
+    /// `clean_verbatim`: the transcript segments to clean, in order.
+    public let texts: [String]?
+
+    /// The client's number for this command, echoed as the response's
+    /// `id` so responses can be paired with commands. `nil` from clients
+    /// that predate it.
+    public let id: Int?
+
+    /// `version`: the client's protocol revision. `nil` from clients that
+    /// predate it.
+    public let protocolVersion: Int?
+
+    /// `subscribe`: the last segment a resubscribing client saw. The
+    /// segments after it are replayed as `segment` events before live
+    /// ones, so a reconnect doesn't lose what was said meanwhile.
+    public let sinceSessionId: String?
+    public let sinceSequence: Int?
+
     public init(
         cmd: String,
         locale: String? = nil,
@@ -26,7 +48,13 @@ public struct DaemonCommand: Codable, Sendable {
         systemAudio: Bool? = nil,
         events: [String]? = nil,
         autoResumeSeconds: Double? = nil,
-        indefinite: Bool? = nil
+        indefinite: Bool? = nil,
+        path: String? = nil,
+        texts: [String]? = nil,
+        id: Int? = nil,
+        protocolVersion: Int? = nil,
+        sinceSessionId: String? = nil,
+        sinceSequence: Int? = nil
     ) {
         self.cmd = cmd
         self.locale = locale
@@ -35,6 +63,12 @@ public struct DaemonCommand: Codable, Sendable {
         self.events = events
         self.autoResumeSeconds = autoResumeSeconds
         self.indefinite = indefinite
+        self.path = path
+        self.texts = texts
+        self.id = id
+        self.protocolVersion = protocolVersion
+        self.sinceSessionId = sinceSessionId
+        self.sinceSequence = sinceSequence
     }
 }
 
@@ -63,6 +97,49 @@ public struct DaemonResponse: Codable, Sendable {
     /// fire. `nil` for indefinite pauses or when not paused.
     public var pauseExpiresAt: Double?
 
+    /// `status`: name of the app that had focus when the current session
+    /// opened. Clients may record it to tell similar meetings apart.
+    public var foregroundApp: String?
+
+    /// `status`: Unix timestamp (seconds) of the current session's most
+    /// recent finalized segment, so a client can show that audio is still
+    /// flowing through a quiet stretch. `nil` before the first segment.
+    public var lastSegmentAt: Double?
+
+    /// `status` and `set_locale`: the locale the recognizers are running
+    /// in (or, while paused, the one the next session opens with).
+    public var locale: String?
+
+    /// `status`, `arm` and `disarm`: whether voice-activated start is
+    /// armed (the engine is idle, metering the mic for speech).
+    public var armed: Bool?
+
+    /// `status`: the conferencing app whose call was on when the current
+    /// session opened. Clients record it to tag the session.
+    public var callApp: String?
+
+    /// `status`: thermal state ("nominal", "fair", "serious",
+    /// "critical"), whether Low Power Mode is on, and whether rolling
+    /// summaries are being deferred because of them. See
+    /// `ResourcePressure`.
+    public var thermalState: String?
+    public var lowPower: Bool?
+    public var summariesDeferred: Bool?
+
+    /// `version` command: release version and build description, so a
+    /// client can detect version skew. See `BuildInfo`.
+    public var version: String?
+    public var build: String?
+    /// `version`: this daemon's `BuildInfo.protocolVersion`.
+    public var protocolVersion: Int?
+
+    /// `clean_verbatim`: the cleaned segments, one per requested text and
+    /// in the same order.
+    public var texts: [String]?
+
+    /// The `id` of the command answered, when it had one.
+    public var id: Int?
+
     public init(
         ok: Bool,
         sessionId: String? = nil,
@@ -75,7 +152,20 @@ public struct DaemonResponse: Codable, Sendable {
         systemAudio: Bool? = nil,
         paused: Bool? = nil,
         pausedIndefinitely: Bool? = nil,
-        pauseExpiresAt: Double? = nil
+        pauseExpiresAt: Double? = nil,
+        foregroundApp: String? = nil,
+        lastSegmentAt: Double? = nil,
+        locale: String? = nil,
+        armed: Bool? = nil,
+        callApp: String? = nil,
+        thermalState: String? = nil,
+        lowPower: Bool? = nil,
+        summariesDeferred: Bool? = nil,
+        version: String? = nil,
+        build: String? = nil,
+        protocolVersion: Int? = nil,
+        texts: [String]? = nil,
+        id: Int? = nil
     ) {
         self.ok = ok
         self.sessionId = sessionId
@@ -89,6 +179,19 @@ public struct DaemonResponse: Codable, Sendable {
         self.paused = paused
         self.pausedIndefinitely = pausedIndefinitely
         self.pauseExpiresAt = pauseExpiresAt
+        self.foregroundApp = foregroundApp
+        self.lastSegmentAt = lastSegmentAt
+        self.locale = locale
+        self.armed = armed
+        self.callApp = callApp
+        self.thermalState = thermalState
+        self.lowPower = lowPower
+        self.summariesDeferred = summariesDeferred
+        self.version = version
+        self.build = build
+        self.protocolVersion = protocolVersion
+        self.texts = texts
+        self.id = id
     }
 
     /// Convenience: success response.
@@ -118,11 +221,41 @@ public struct DaemonEvent: Codable, Sendable {
     public var modelProcessing: Bool?
     public var startedAt: Double?
 
+    /// When a `segment` ended (Unix seconds), so clients can tell how
+    /// long it was spoken for.
+    public var endedAt: Double?
+
     /// U10 — pause-state event payload.
     public var paused: Bool?
     public var pausedIndefinitely: Bool?
     public var pauseExpiresAt: Double?
 
+    /// Locale of a `segment` (the recognizer's, which `set_locale` can
+    /// change mid-session), or the new locale on a `locale` event.
+    public var locale: String?
+
+    /// Diarized speaker label of a `segment` (e.g. "Speaker 1"). `nil`
+    /// until diarization labels the segment.
+    public var speaker: String?
+
+    /// The session's full topic list on a `topics` event, so clients can
+    /// merge it in place without re-reading the database.
+    public var topics: [DaemonTopic]?
+
+    /// `armed` event: whether voice-activated start is armed.
+    public var armed: Bool?
+
+    /// `call_started` / `call_ended`: the conferencing app, and on
+    /// `call_started` whether the daemon opened a session for the call.
+    public var app: String?
+    public var autoRecorded: Bool?
+
+    /// `pressure` event: the new thermal state and Low Power Mode, and
+    /// whether rolling summaries are deferred.
+    public var thermalState: String?
+    public var lowPower: Bool?
+    public var summariesDeferred: Bool?
+
     public init(
         event: String,
         text: String? = nil,
@@ -137,9 +270,19 @@ public struct DaemonEvent: Codable, Sendable {
         recording: Bool? = nil,
         modelProcessing: Bool? = nil,
         startedAt: Double? = nil,
+        endedAt: Double? = nil,
         paused: Bool? = nil,
         pausedIndefinitely: Bool? = nil,
-        pauseExpiresAt: Double? = nil
+        pauseExpiresAt: Double? = nil,
+        locale: String? = nil,
+        speaker: String? = nil,
+        topics: [DaemonTopic]? = nil,
+        armed: Bool? = nil,
+        app: String? = nil,
+        autoRecorded: Bool? = nil,
+        thermalState: String? = nil,
+        lowPower: Bool? = nil,
+        summariesDeferred: Bool? = nil
     ) {
         self.event = event
         self.text = text
@@ -154,8 +297,35 @@ public struct DaemonEvent: Codable, Sendable {
         self.recording = recording
         self.modelProcessing = modelProcessing
         self.startedAt = startedAt
+        self.endedAt = endedAt
         self.paused = paused
         self.pausedIndefinitely = pausedIndefinitely
         self.pauseExpiresAt = pauseExpiresAt
+        self.locale = locale
+        self.speaker = speaker
+        self.topics = topics
+        self.armed = armed
+        self.app = app
+        self.autoRecorded = autoRecorded
+        self.thermalState = thermalState
+        self.lowPower = lowPower
+        self.summariesDeferred = summariesDeferred
+    }
+}
+
+/// A topic as carried on the `topics` event.
+public struct DaemonTopic: Codable, Sendable, Equatable {
+    public let id: String
+    public let title: String
+    public let summary: String
+    public let segmentRangeStart: Int
+    public let segmentRangeEnd: Int
+
+    public init(_ topic: Topic) {
+        self.id = topic.id.uuidString
+        self.title = topic.title
+        self.summary = topic.summary
+        self.segmentRangeStart = topic.segmentRange.lowerBound
+        self.segmentRangeEnd = topic.segmentRange.upperBound
     }
 }
@@ -6,7 +6,7 @@ struct StenoDaemon: ParsableCommand {
     static let configuration = CommandConfiguration(
         commandName: "steno-daemon",
         abstract: "Headless recording, transcription, and analysis daemon for Steno",
-        version: "0.1.0",
+        version: BuildInfo.version,
         subcommands: [RunCommand.self, StatusCommand.self, InstallCommand.self, UninstallCommand.self],
         defaultSubcommand: RunCommand.self
     )
@@ -182,6 +182,19 @@ public enum DatabaseConfiguration {
             """)
         }
 
+        // Per-segment recognition locale, for sessions that switch language
+        // mid-recording via `set_locale`. Nullable: older rows read as the
+        // session's `locale`.
+        migrator.registerMigration("20261014_001_segment_locale") { db in
+            try db.execute(sql: "ALTER TABLE segments ADD COLUMN locale TEXT")
+        }
+
+        // Per-segment diarized speaker label. Nullable: segments nobody
+        // diarized, and older rows, have none.
+        migrator.registerMigration("20261014_002_segment_speaker") { db in
+            try db.execute(sql: "ALTER TABLE segments ADD COLUMN speaker TEXT")
+        }
+
         return migrator
     }
 }
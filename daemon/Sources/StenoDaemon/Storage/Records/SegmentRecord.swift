@@ -42,6 +42,14 @@ struct SegmentRecord: Codable, FetchableRecord, PersistableRecord {
     /// NULL for non-mic segments and for older rows.
     var micPeakDb: Double?
 
+    /// Recognition locale for the segment (`set_locale` can change it
+    /// mid-session). NULL for older rows, which use the session's locale.
+    var locale: String?
+
+    /// Diarized speaker label. NULL until diarization labels the segment,
+    /// and for older rows.
+    var speaker: String?
+
     enum CodingKeys: String, CodingKey {
         case id, sessionId, text, startedAt, endedAt, confidence
         case sequenceNumber, createdAt, source
@@ -49,6 +57,8 @@ struct SegmentRecord: Codable, FetchableRecord, PersistableRecord {
         case dedupMethod = "dedup_method"
         case healMarker = "heal_marker"
         case micPeakDb = "mic_peak_db"
+        case locale
+        case speaker
     }
 
     /// Convert to domain model.
@@ -76,7 +86,9 @@ struct SegmentRecord: Codable, FetchableRecord, PersistableRecord {
             healMarker: healMarker,
             duplicateOf: dupUUID,
             dedupMethod: method,
-            micPeakDb: micPeakDb
+            micPeakDb: micPeakDb,
+            locale: locale,
+            speaker: speaker
         )
     }
 
@@ -106,7 +118,9 @@ struct SegmentRecord: Codable, FetchableRecord, PersistableRecord {
             duplicateOf: segment.duplicateOf?.uuidString,
             dedupMethod: segment.dedupMethod?.rawValue,
             healMarker: segment.healMarker,
-            micPeakDb: segment.micPeakDb
+            micPeakDb: segment.micPeakDb,
+            locale: segment.locale,
+            speaker: segment.speaker
         )
     }
 }
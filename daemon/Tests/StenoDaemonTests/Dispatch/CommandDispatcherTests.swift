@@ -54,6 +54,32 @@ struct CommandDispatcherTests {
         await engine.stop()
     }
 
+    @Test @MainActor func versionCommandReportsBuildInfo() async throws {
+        let (dispatcher, _, _) = makeDispatcher()
+        let client = MockClientConnection()
+
+        await dispatcher.handle(DaemonCommand(cmd: "version"), from: client)
+
+        let responses = await client.sentResponses
+        #expect(responses.count == 1)
+        #expect(responses[0].ok == true)
+        #expect(responses[0].version == BuildInfo.version)
+        #expect(responses[0].build?.isEmpty == false)
+        #expect(responses[0].protocolVersion == BuildInfo.protocolVersion)
+    }
+
+    @Test @MainActor func responsesEchoTheCommandID() async throws {
+        let (dispatcher, _, _) = makeDispatcher()
+        let client = MockClientConnection()
+
+        await dispatcher.handle(DaemonCommand(cmd: "version", id: 7), from: client)
+        await dispatcher.handle(DaemonCommand(cmd: "bogus", id: 8), from: client)
+        await dispatcher.handle(DaemonCommand(cmd: "version"), from: client)
+
+        let responses = await client.sentResponses
+        #expect(responses.map(\.id) == [7, 8, nil])
+    }
+
     @Test @MainActor func stopCommandStopsEngine() async throws {
         let (dispatcher, engine, _) = makeDispatcher()
         let client = MockClientConnection()
@@ -87,6 +113,199 @@ struct CommandDispatcherTests {
         #expect(responses[0].recording == false)
     }
 
+    @Test @MainActor func statusCommandReportsSessionForegroundApp() async throws {
+        let repo = MockTranscriptRepository()
+        let engine = RecordingEngine(
+            repository: repo,
+            permissionService: MockPermissionService(),
+            summaryCoordinator: RollingSummaryCoordinator(repository: repo, summarizer: MockSummarizationService()),
+            audioSourceFactory: MockAudioSourceFactory(),
+            speechRecognizerFactory: MockSpeechRecognizerFactory(),
+            foregroundAppProvider: { "zoom.us" }
+        )
+        let dispatcher = CommandDispatcher(engine: engine, broadcaster: EventBroadcaster())
+        let client = MockClientConnection()
+
+        await dispatcher.handle(DaemonCommand(cmd: "status"), from: client)
+        #expect(await client.sentResponses[0].foregroundApp == nil)
+
+        try await engine.start(locale: Locale(identifier: "en_US"))
+        await dispatcher.handle(DaemonCommand(cmd: "status"), from: client)
+        #expect(await client.sentResponses[1].foregroundApp == "zoom.us")
+
+        await engine.stop()
+    }
+
+    @Test @MainActor func statusCommandReportsLastSegmentTime() async throws {
+        let repo = MockTranscriptRepository()
+        let rf = MockSpeechRecognizerFactory()
+        rf.handle.resultsToYield = [
+            RecognizerResult(text: "hello world", isFinal: true, confidence: 0.95, source: .microphone)
+        ]
+        let engine = RecordingEngine(
+            repository: repo,
+            permissionService: MockPermissionService(),
+            summaryCoordinator: RollingSummaryCoordinator(repository: repo, summarizer: MockSummarizationService()),
+            audioSourceFactory: MockAudioSourceFactory(),
+            speechRecognizerFactory: rf
+        )
+        let dispatcher = CommandDispatcher(engine: engine, broadcaster: EventBroadcaster())
+        let client = MockClientConnection()
+
+        await dispatcher.handle(DaemonCommand(cmd: "status"), from: client)
+        #expect(await client.sentResponses[0].lastSegmentAt == nil)
+
+        let before = Date().timeIntervalSince1970
+        try await engine.start(locale: Locale(identifier: "en_US"))
+        try await Task.sleep(for: .milliseconds(50))
+        await dispatcher.handle(DaemonCommand(cmd: "status"), from: client)
+        let response = await client.sentResponses[1]
+        #expect(response.segments == 1)
+        #expect((response.lastSegmentAt ?? 0) >= before)
+
+        await engine.stop()
+    }
+
+    @Test @MainActor func setLocaleSwitchesRecognizersWithinSession() async throws {
+        let repo = MockTranscriptRepository()
+        let rf = MockSpeechRecognizerFactory()
+        rf.handle.resultsToYield = [
+            RecognizerResult(text: "hello world", isFinal: true, confidence: 0.95, source: .microphone)
+        ]
+        let engine = RecordingEngine(
+            repository: repo,
+            permissionService: MockPermissionService(),
+            summaryCoordinator: RollingSummaryCoordinator(repository: repo, summarizer: MockSummarizationService()),
+            audioSourceFactory: MockAudioSourceFactory(),
+            speechRecognizerFactory: rf
+        )
+        let dispatcher = CommandDispatcher(engine: engine, broadcaster: EventBroadcaster())
+        let client = MockClientConnection()
+
+        // Nothing to switch while idle.
+        await dispatcher.handle(DaemonCommand(cmd: "set_locale", locale: "fr_FR"), from: client)
+        #expect(await client.sentResponses[0].ok == false)
+
+        let session = try await engine.start(locale: Locale(identifier: "en_US"))
+        try await Task.sleep(for: .milliseconds(50))
+
+        await dispatcher.handle(DaemonCommand(cmd: "set_locale"), from: client)
+        #expect(await client.sentResponses[1].error == "set_locale requires a locale")
+
+        // The rebuilt recognizer replays its canned result, which lands as
+        // the session's second segment in the new locale.
+        await dispatcher.handle(DaemonCommand(cmd: "set_locale", locale: "fr_FR"), from: client)
+        let response = await client.sentResponses[2]
+        #expect(response.ok == true)
+        #expect(response.sessionId == session.id.uuidString)
+        #expect(response.locale == "fr_FR")
+        #expect(rf.lastLocale?.identifier == "fr_FR")
+        try await Task.sleep(for: .milliseconds(50))
+
+        let segments = try await repo.segments(for: session.id)
+        #expect(segments.map(\.sequenceNumber) == [1, 2])
+        #expect(segments.map(\.locale) == ["en_US", "fr_FR"])
+
+        await dispatcher.handle(DaemonCommand(cmd: "status"), from: client)
+        #expect(await client.sentResponses[3].locale == "fr_FR")
+
+        await engine.stop()
+    }
+
+    @Test @MainActor func injectAudioOnlyInTestMode() async throws {
+        let (dispatcher, _, _) = makeDispatcher()
+        let client = MockClientConnection()
+
+        await dispatcher.handle(DaemonCommand(cmd: "inject_audio", path: "/tmp/x.wav"), from: client)
+        let response = await client.sentResponses[0]
+        #expect(response.ok == false)
+        #expect(response.error == "inject_audio requires a daemon run with --test-mode")
+    }
+
+    @Test @MainActor func injectAudioFeedsTheRecordingSession() async throws {
+        let injector = InjectedAudioSourceFactory()
+        let repo = MockTranscriptRepository()
+        let engine = RecordingEngine(
+            repository: repo,
+            permissionService: MockPermissionService(),
+            summaryCoordinator: RollingSummaryCoordinator(
+                repository: repo,
+                summarizer: MockSummarizationService(),
+                triggerCount: 100,
+                timeThreshold: 3600
+            ),
+            audioSourceFactory: injector,
+            speechRecognizerFactory: MockSpeechRecognizerFactory()
+        )
+        let dispatcher = CommandDispatcher(engine: engine, broadcaster: EventBroadcaster(), audioInjector: injector)
+        let client = MockClientConnection()
+        let url = try InjectedAudioSourceFactoryTests.writeTone(seconds: 0.5)
+        defer { try? FileManager.default.removeItem(at: url) }
+
+        await dispatcher.handle(DaemonCommand(cmd: "inject_audio", path: url.path), from: client)
+        #expect(await client.sentResponses[0].error == "Not recording")
+
+        let session = try await engine.start(systemAudio: false)
+        await dispatcher.handle(DaemonCommand(cmd: "inject_audio"), from: client)
+        #expect(await client.sentResponses[1].error == "inject_audio requires a path")
+
+        await dispatcher.handle(DaemonCommand(cmd: "inject_audio", path: url.path), from: client)
+        let response = await client.sentResponses[2]
+        #expect(response.ok == true)
+        #expect(response.sessionId == session.id.uuidString)
+
+        await engine.stop()
+    }
+
+    @Test @MainActor func cleanVerbatimCleansEachText() async throws {
+        let (_, engine, broadcaster) = makeDispatcher()
+        let summarizer = MockSummarizationService()
+        let dispatcher = CommandDispatcher(engine: engine, broadcaster: broadcaster, summarizer: summarizer)
+        let client = MockClientConnection()
+
+        await dispatcher.handle(DaemonCommand(cmd: "clean_verbatim", texts: ["um so yeah", "we ship friday"]), from: client)
+        var response = await client.sentResponses[0]
+        #expect(response.ok == true)
+        #expect(response.texts == ["Clean: um so yeah", "Clean: we ship friday"])
+
+        await dispatcher.handle(DaemonCommand(cmd: "clean_verbatim"), from: client)
+        #expect(await client.sentResponses[1].error == "clean_verbatim requires texts")
+
+        await summarizer.setShouldThrow(.generationFailed("busy"))
+        await dispatcher.handle(DaemonCommand(cmd: "clean_verbatim", texts: ["uh hi"]), from: client)
+        response = await client.sentResponses[2]
+        #expect(response.ok == false)
+        #expect(response.texts == nil)
+    }
+
+    @Test @MainActor func cleanVerbatimWithoutModel() async throws {
+        let (dispatcher, _, _) = makeDispatcher()
+        let client = MockClientConnection()
+
+        await dispatcher.handle(DaemonCommand(cmd: "clean_verbatim", texts: ["uh hi"]), from: client)
+        let response = await client.sentResponses[0]
+        #expect(response.ok == false)
+        #expect(response.error == "clean_verbatim requires an available language model")
+    }
+
+    @Test @MainActor func armAndDisarmCommands() async throws {
+        let (dispatcher, engine, _) = makeDispatcher()
+        let client = MockClientConnection()
+
+        await dispatcher.handle(DaemonCommand(cmd: "arm"), from: client)
+        #expect(await client.sentResponses[0].armed == true)
+        #expect(await engine.isArmed)
+
+        await dispatcher.handle(DaemonCommand(cmd: "status"), from: client)
+        let status = await client.sentResponses[1]
+        #expect(status.armed == true)
+        #expect(status.recording == false)
+
+        await dispatcher.handle(DaemonCommand(cmd: "disarm"), from: client)
+        #expect(await client.sentResponses[2].armed == false)
+        #expect(await engine.isArmed == false)
+    }
+
     @Test @MainActor func devicesCommandReturnsList() async throws {
         let (dispatcher, _, _) = makeDispatcher()
         let client = MockClientConnection()
@@ -119,6 +338,52 @@ struct CommandDispatcherTests {
         #expect(events.count == 1)
     }
 
+    @Test @MainActor func subscribeSinceReplaysMissedSegments() async throws {
+        let repo = MockTranscriptRepository()
+        let session = try await repo.createSession(locale: Locale(identifier: "en_US"))
+        for seq in 1...3 {
+            try await repo.saveSegment(StoredSegment(
+                sessionId: session.id,
+                text: "segment \(seq)",
+                startedAt: Date(timeIntervalSince1970: Double(seq)),
+                endedAt: Date(timeIntervalSince1970: Double(seq) + 1),
+                sequenceNumber: seq
+            ))
+        }
+        let engine = RecordingEngine(
+            repository: repo,
+            permissionService: MockPermissionService(),
+            summaryCoordinator: RollingSummaryCoordinator(
+                repository: repo,
+                summarizer: MockSummarizationService(),
+                triggerCount: 100,
+                timeThreshold: 3600
+            ),
+            audioSourceFactory: MockAudioSourceFactory(),
+            speechRecognizerFactory: MockSpeechRecognizerFactory()
+        )
+        let dispatcher = CommandDispatcher(engine: engine, broadcaster: EventBroadcaster())
+        let client = MockClientConnection()
+
+        let command = DaemonCommand(
+            cmd: "subscribe",
+            sinceSessionId: session.id.uuidString,
+            sinceSequence: 1
+        )
+        await dispatcher.handle(command, from: client)
+
+        // The response comes first, then the segments after #1.
+        let first = await client.allSentData.first
+        let response = try JSONDecoder().decode(
+            DaemonResponse.self,
+            from: try #require(first).filter { $0 != UInt8(ascii: "\n") }
+        )
+        #expect(response.ok == true)
+        let events = await client.sentEvents
+        #expect(events.map(\.sequenceNumber) == [2, 3])
+        #expect(events.map(\.text) == ["segment 2", "segment 3"])
+    }
+
     @Test @MainActor func unknownCommandReturnsError() async throws {
         let (dispatcher, _, _) = makeDispatcher()
         let client = MockClientConnection()
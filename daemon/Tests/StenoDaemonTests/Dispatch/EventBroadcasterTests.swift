@@ -36,6 +36,34 @@ struct EventBroadcasterTests {
         #expect(events.isEmpty)
     }
 
+    @Test func heldBackEventsFollowTheReplay() async throws {
+        let broadcaster = EventBroadcaster()
+        let client = MockClientConnection()
+        let sessionId = UUID()
+
+        await broadcaster.subscribe(client: client, events: [.segment, .partial], holdingBack: true)
+        let engine = await makeTestEngine()
+        await broadcaster.engine(engine, didEmit: .partialText("live", .microphone))
+        #expect(await client.sentEvents.isEmpty)
+
+        let missed = StoredSegment(
+            sessionId: sessionId,
+            text: "missed",
+            startedAt: Date(timeIntervalSince1970: 1),
+            endedAt: Date(timeIntervalSince1970: 2),
+            sequenceNumber: 4
+        )
+        await broadcaster.replay([missed], to: client.id)
+
+        let events = await client.sentEvents
+        #expect(events.map(\.event) == ["segment", "partial"])
+        #expect(events.first?.sequenceNumber == 4)
+
+        // Live from here on.
+        await broadcaster.engine(engine, didEmit: .partialText("after", .microphone))
+        #expect(await client.sentEvents.count == 3)
+    }
+
     @Test func disconnectedClientRemoved() async throws {
         let broadcaster = EventBroadcaster()
         let client = MockClientConnection()
@@ -69,7 +97,7 @@ struct EventBroadcasterTests {
             sessionId: UUID(),
             text: "test segment",
             startedAt: segmentStart,
-            endedAt: Date(),
+            endedAt: segmentStart.addingTimeInterval(4),
             sequenceNumber: 5,
             source: .systemAudio
         )
@@ -84,6 +112,7 @@ struct EventBroadcasterTests {
         #expect(events[0].source == "systemAudio")
         #expect(events[0].sequenceNumber == 5)
         #expect(events[0].startedAt == 1700000000)
+        #expect(events[0].endedAt == 1700000004)
     }
 
     @Test func statusEventMapped() async throws {
@@ -146,6 +175,8 @@ struct EventBroadcasterTests {
         #expect(events.count == 1)
         #expect(events[0].event == "topics")
         #expect(events[0].title == "Budget, Hiring")
+        #expect(events[0].topics == topics.map(DaemonTopic.init))
+        #expect(events[0].topics?[1].segmentRangeStart == 4)
     }
 
     @Test func unsubscribeStopsDelivery() async throws {
@@ -0,0 +1,169 @@
+import Testing
+import Foundation
+import AVFoundation
+@testable import StenoDaemon
+
+/// Tests for voice-activated start: `SpeechOnsetDetector` and the engine's
+/// `arm()` / `disarm()` metering mode.
+@Suite("Arm / Voice-Activated Start Tests")
+struct ArmTests {
+
+    // MARK: - SpeechOnsetDetector
+
+    @Test("Sustained speech across short word gaps triggers")
+    func detectorTriggersOnSustainedSpeech() {
+        var detector = SpeechOnsetDetector(threshold: 0.1, sustain: 1.0, maxGap: 0.5)
+        let t0 = Date(timeIntervalSince1970: 1_000)
+
+        #expect(detector.feed(0.3, at: t0) == false)
+        #expect(detector.feed(0.0, at: t0.addingTimeInterval(0.3)) == false)
+        #expect(detector.feed(0.3, at: t0.addingTimeInterval(0.6)) == false)
+        #expect(detector.feed(0.3, at: t0.addingTimeInterval(1.0)) == true)
+    }
+
+    @Test("A gap longer than maxGap restarts the onset")
+    func detectorResetsAfterLongGap() {
+        var detector = SpeechOnsetDetector(threshold: 0.1, sustain: 1.0, maxGap: 0.5)
+        let t0 = Date(timeIntervalSince1970: 1_000)
+
+        #expect(detector.feed(0.3, at: t0) == false)
+        #expect(detector.feed(0.0, at: t0.addingTimeInterval(0.9)) == false)
+        // 1.2s after the first sound, but the onset restarted at 1.0.
+        #expect(detector.feed(0.3, at: t0.addingTimeInterval(1.0)) == false)
+        #expect(detector.feed(0.3, at: t0.addingTimeInterval(1.2)) == false)
+        #expect(detector.feed(0.3, at: t0.addingTimeInterval(2.0)) == true)
+    }
+
+    @Test("Quiet input never triggers")
+    func detectorIgnoresQuiet() {
+        var detector = SpeechOnsetDetector(threshold: 0.1, sustain: 0.5, maxGap: 0.5)
+        let t0 = Date(timeIntervalSince1970: 1_000)
+        for i in 0..<20 {
+            #expect(detector.feed(0.05, at: t0.addingTimeInterval(Double(i) * 0.1)) == false)
+        }
+    }
+
+    // MARK: - Engine
+
+    @Test("arm() opens a metering mic and stays idle")
+    func armStaysIdle() async throws {
+        let (engine, repo, af, del) = await makeEngine()
+
+        try await engine.arm()
+
+        #expect(await engine.isArmed)
+        #expect(await engine.status == .idle)
+        #expect(af.micCreateCount == 1)
+        #expect(try await repo.allSessions().isEmpty)
+        #expect(await del.events.contains { if case .armedChanged(true) = $0 { return true }; return false })
+    }
+
+    @Test("Speech while armed starts a session and emits autoStarted")
+    func speechAutoStarts() async throws {
+        let (engine, _, af, del) = await makeEngine()
+
+        try await engine.arm(detector: SpeechOnsetDetector(threshold: 0.1, sustain: 0, maxGap: 0.5))
+        af.emitMicBuffer(makeBuffer(format: af.micFormat, peak: 0.5))
+
+        let started = await waitFor(timeout: 2.0) { await engine.status == .recording }
+        #expect(started)
+        #expect(await engine.isArmed == false)
+        let autoStarted = await waitFor(timeout: 1.0) {
+            await del.events.contains { if case .autoStarted = $0 { return true }; return false }
+        }
+        #expect(autoStarted)
+    }
+
+    @Test("disarm() releases the mic without starting")
+    func disarmReleases() async throws {
+        let (engine, _, _, del) = await makeEngine()
+
+        try await engine.arm()
+        await engine.disarm()
+
+        #expect(await engine.isArmed == false)
+        #expect(await engine.status == .idle)
+        #expect(await del.events.contains { if case .armedChanged(false) = $0 { return true }; return false })
+    }
+
+    @Test("arm() is refused while paused")
+    func armRefusedWhilePaused() async throws {
+        let (engine, _, af, _) = await makeEngine()
+        _ = try await engine.start()
+        try await engine.pause(autoResumeSeconds: nil)
+        let created = af.micCreateCount
+
+        await #expect(throws: RecordingEngineError.self) {
+            try await engine.arm()
+        }
+        #expect(af.micCreateCount == created, "a paused engine must not open the mic")
+    }
+
+    @Test("A manual start while armed disarms first")
+    func manualStartDisarms() async throws {
+        let (engine, _, _, _) = await makeEngine()
+
+        try await engine.arm()
+        _ = try await engine.start()
+
+        #expect(await engine.isArmed == false)
+        #expect(await engine.status == .recording)
+    }
+
+    // MARK: - Helpers
+
+    @MainActor
+    private func makeEngine() async -> (
+        engine: RecordingEngine,
+        repo: MockTranscriptRepository,
+        audioFactory: MockAudioSourceFactory,
+        delegate: MockRecordingEngineDelegate
+    ) {
+        let repo = MockTranscriptRepository()
+        let af = MockAudioSourceFactory()
+        let del = MockRecordingEngineDelegate()
+        let coordinator = RollingSummaryCoordinator(
+            repository: repo,
+            summarizer: MockSummarizationService(),
+            triggerCount: 100,
+            timeThreshold: 3600
+        )
+        let engine = RecordingEngine(
+            repository: repo,
+            permissionService: MockPermissionService(),
+            summaryCoordinator: coordinator,
+            audioSourceFactory: af,
+            speechRecognizerFactory: MockSpeechRecognizerFactory(),
+            delegate: del,
+            backoffSleep: { _ in },
+            emptySessionMinChars: 0,
+            emptySessionMinDurationSeconds: 0,
+            retentionDays: 0
+        )
+        return (engine, repo, af, del)
+    }
+
+    /// A 100ms buffer whose samples all sit at `peak`.
+    private func makeBuffer(format: AVAudioFormat, peak: Float) -> AVAudioPCMBuffer {
+        let frames = AVAudioFrameCount(format.sampleRate / 10)
+        let buffer = AVAudioPCMBuffer(pcmFormat: format, frameCapacity: frames)!
+        buffer.frameLength = frames
+        for i in 0..<Int(frames) {
+            buffer.floatChannelData![0][i] = peak
+        }
+        return buffer
+    }
+
+    private func waitFor(
+        timeout: TimeInterval,
+        step: TimeInterval = 0.020,
+        _ predicate: @Sendable () async -> Bool
+    ) async -> Bool {
+        let deadline = Date().addingTimeInterval(timeout)
+        while Date() < deadline {
+            if await predicate() { return true }
+            try? await Task.sleep(for: .milliseconds(Int(step * 1000)))
+        }
+        return false
+    }
+}
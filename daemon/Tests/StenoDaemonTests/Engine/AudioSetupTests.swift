@@ -0,0 +1,62 @@
+import Testing
+import Foundation
+@testable import StenoDaemon
+
+/// Tests for the per-setup device preference: the setup fingerprint and
+/// how `StenoSettings` records and restores a device against it.
+@Suite("Audio Setup Tests")
+struct AudioSetupTests {
+
+    private let undocked = AudioSetup.fingerprint(["BuiltInMicrophoneDevice"])
+    private let docked = AudioSetup.fingerprint(["BuiltInMicrophoneDevice", "AppleUSBAudioEngine:Yeti"])
+
+    @Test("Fingerprint ignores order and duplicates")
+    func fingerprintStable() {
+        #expect(AudioSetup.fingerprint(["b", "a", "b"]) == "a|b")
+        #expect(AudioSetup.fingerprint(["a", "b"]) == AudioSetup.fingerprint(["b", "a"]))
+        #expect(docked != undocked)
+    }
+
+    @Test("No devices is an unknown setup")
+    func fingerprintEmpty() {
+        #expect(AudioSetup.fingerprint([]) == nil)
+        #expect(AudioSetup.fingerprint([""]) == nil)
+    }
+
+    @Test("Each setup restores its own device")
+    func perSetupPreference() {
+        var settings = StenoSettings()
+        settings.rememberDevice("Yeti", forSetup: docked)
+        settings.rememberDevice(nil, forSetup: undocked)
+
+        #expect(settings.preferredDevice(forSetup: docked) == "Yeti")
+        // The built-in default was chosen last, but docking brings the
+        // Yeti back rather than sticking with it.
+        #expect(settings.lastDevice == nil)
+        #expect(settings.preferredDevice(forSetup: undocked) == nil)
+    }
+
+    @Test("An unseen or unknown setup falls back to the last device")
+    func fallbackToLastDevice() {
+        var settings = StenoSettings()
+        settings.rememberDevice("Yeti", forSetup: docked)
+        #expect(settings.preferredDevice(forSetup: AudioSetup.fingerprint(["AirPods"])) == "Yeti")
+        #expect(settings.preferredDevice(forSetup: nil) == "Yeti")
+
+        settings.rememberDevice("AirPods", forSetup: nil)
+        #expect(settings.devicePreferences[docked!] == "Yeti")
+        #expect(settings.lastDevice == "AirPods")
+    }
+
+    @Test("Preferences round-trip, and older settings decode without them")
+    func codable() throws {
+        var settings = StenoSettings()
+        settings.rememberDevice("Yeti", forSetup: docked)
+        let decoded = try JSONDecoder().decode(StenoSettings.self, from: JSONEncoder().encode(settings))
+        #expect(decoded.devicePreferences == settings.devicePreferences)
+
+        let old = try JSONDecoder().decode(StenoSettings.self, from: Data(#"{"lastDevice":"Yeti"}"#.utf8))
+        #expect(old.devicePreferences.isEmpty)
+        #expect(old.preferredDevice(forSetup: docked) == "Yeti")
+    }
+}
@@ -0,0 +1,115 @@
+import Testing
+import Foundation
+@testable import StenoDaemon
+
+/// Tests for `RecordingEngine.handleCallTransition` — tagging sessions
+/// with a detected call and, under `CallDetection.auto`, giving the call
+/// its own session.
+@Suite("Call Detection Tests")
+struct CallDetectionTests {
+
+    private let zoom = ConferencingApp(bundleID: "us.zoom.xos", name: "Zoom")
+
+    @Test("Auto from idle starts a session tagged with the app")
+    func autoStartsFromIdle() async throws {
+        let (engine, repo, del) = await makeEngine()
+
+        await engine.handleCallTransition(.started(zoom), autoRecord: true)
+
+        #expect(await engine.status == .recording)
+        #expect(await engine.sessionCallApp == "Zoom")
+        #expect(try await repo.allSessions().count == 1)
+        #expect(await del.events.contains {
+            if case .callStarted("Zoom", true) = $0 { return true }; return false
+        })
+    }
+
+    @Test("Auto while recording splits the session, and again at hang-up")
+    func autoSplitsWhileRecording() async throws {
+        let (engine, repo, del) = await makeEngine()
+        let before = try await engine.start()
+        #expect(await engine.sessionCallApp == nil)
+
+        await engine.handleCallTransition(.started(zoom), autoRecord: true)
+        let call = try #require(await engine.currentSession)
+        #expect(call.id != before.id)
+        #expect(await engine.sessionCallApp == "Zoom")
+
+        await engine.handleCallTransition(.ended(zoom), autoRecord: true)
+        let after = try #require(await engine.currentSession)
+        #expect(after.id != call.id)
+        #expect(await engine.sessionCallApp == nil)
+        #expect(await engine.status == .recording)
+        #expect(try await repo.allSessions().count == 3)
+        #expect(await del.events.contains {
+            if case .callEnded("Zoom") = $0 { return true }; return false
+        })
+    }
+
+    @Test("Prompt mode only reports the call; a later split is tagged")
+    func promptTagsManualSplit() async throws {
+        let (engine, _, del) = await makeEngine()
+        let before = try await engine.start()
+
+        await engine.handleCallTransition(.started(zoom), autoRecord: false)
+        #expect(await engine.currentSession?.id == before.id)
+        #expect(await engine.sessionCallApp == nil)
+        #expect(await del.events.contains {
+            if case .callStarted("Zoom", false) = $0 { return true }; return false
+        })
+
+        try await engine.demarcate()
+        #expect(await engine.sessionCallApp == "Zoom")
+
+        // No auto-record: hanging up leaves the session alone.
+        let call = await engine.currentSession?.id
+        await engine.handleCallTransition(.ended(zoom), autoRecord: false)
+        #expect(await engine.currentSession?.id == call)
+        #expect(await engine.callApp == nil)
+    }
+
+    @Test("A paused engine stays paused through a call")
+    func pausedStaysPaused() async throws {
+        let (engine, _, del) = await makeEngine()
+        _ = try await engine.start()
+        try await engine.pause(autoResumeSeconds: nil)
+
+        await engine.handleCallTransition(.started(zoom), autoRecord: true)
+
+        #expect(await engine.status == .paused)
+        #expect(await del.events.contains {
+            if case .callStarted("Zoom", false) = $0 { return true }; return false
+        })
+    }
+
+    // MARK: - Helpers
+
+    @MainActor
+    private func makeEngine() async -> (
+        engine: RecordingEngine,
+        repo: MockTranscriptRepository,
+        delegate: MockRecordingEngineDelegate
+    ) {
+        let repo = MockTranscriptRepository()
+        let del = MockRecordingEngineDelegate()
+        let coordinator = RollingSummaryCoordinator(
+            repository: repo,
+            summarizer: MockSummarizationService(),
+            triggerCount: 100,
+            timeThreshold: 3600
+        )
+        let engine = RecordingEngine(
+            repository: repo,
+            permissionService: MockPermissionService(),
+            summaryCoordinator: coordinator,
+            audioSourceFactory: MockAudioSourceFactory(),
+            speechRecognizerFactory: MockSpeechRecognizerFactory(),
+            delegate: del,
+            backoffSleep: { _ in },
+            emptySessionMinChars: 0,
+            emptySessionMinDurationSeconds: 0,
+            retentionDays: 0
+        )
+        return (engine, repo, del)
+    }
+}
@@ -323,4 +323,7 @@ actor SlowMockSummarizer: SummarizationService {
             createdAt: Date()
         )]
     }
+    func cleanVerbatim(_ text: String) async throws -> String {
+        return text
+    }
 }
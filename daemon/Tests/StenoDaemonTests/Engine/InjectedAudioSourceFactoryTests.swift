@@ -0,0 +1,98 @@
+import Testing
+import Foundation
+@preconcurrency import AVFoundation
+@testable import StenoDaemon
+
+/// Tests for the test-mode mic source that `inject_audio` feeds.
+@Suite("Injected Audio Source Tests")
+struct InjectedAudioSourceFactoryTests {
+
+    /// Write `seconds` of a 440Hz tone as a 44.1kHz stereo WAV, so reading
+    /// it back exercises the conversion to 16kHz mono.
+    static func writeTone(seconds: Double) throws -> URL {
+        let url = FileManager.default.temporaryDirectory
+            .appendingPathComponent("steno-inject-\(UUID().uuidString).wav")
+        let format = AVAudioFormat(standardFormatWithSampleRate: 44100, channels: 2)!
+        let file = try AVAudioFile(forWriting: url, settings: [
+            AVFormatIDKey: kAudioFormatLinearPCM,
+            AVSampleRateKey: 44100,
+            AVNumberOfChannelsKey: 2,
+            AVLinearPCMBitDepthKey: 16,
+        ])
+        let frames = AVAudioFrameCount(seconds * 44100)
+        let buffer = AVAudioPCMBuffer(pcmFormat: format, frameCapacity: frames)!
+        buffer.frameLength = frames
+        for channel in 0..<2 {
+            let samples = buffer.floatChannelData![channel]
+            for i in 0..<Int(frames) {
+                samples[i] = 0.5 * sin(2 * .pi * 440 * Float(i) / 44100)
+            }
+        }
+        try file.write(from: buffer)
+        return url
+    }
+
+    @Test("Injecting with no mic source open fails")
+    func notRecording() throws {
+        let url = try Self.writeTone(seconds: 0.5)
+        defer { try? FileManager.default.removeItem(at: url) }
+        let factory = InjectedAudioSourceFactory()
+        #expect(throws: InjectedAudioSourceFactory.InjectionError.notRecording) {
+            try factory.inject(contentsOf: url)
+        }
+    }
+
+    @Test("A missing file is reported as unreadable")
+    func unreadable() async throws {
+        let factory = InjectedAudioSourceFactory()
+        _ = try await factory.makeMicrophoneSource(device: nil)
+        #expect(throws: InjectedAudioSourceFactory.InjectionError.self) {
+            try factory.inject(contentsOf: URL(fileURLWithPath: "/nonexistent/steno.wav"))
+        }
+    }
+
+    @Test("A file is converted to 16kHz mono chunks, then silence")
+    func injectsConvertedChunks() async throws {
+        let url = try Self.writeTone(seconds: 1)
+        defer { try? FileManager.default.removeItem(at: url) }
+        let factory = InjectedAudioSourceFactory()
+        let (buffers, format, stop) = try await factory.makeMicrophoneSource(device: nil)
+        #expect(format.sampleRate == 16000)
+        #expect(format.channelCount == 1)
+
+        let seconds = try factory.inject(contentsOf: url)
+        #expect(abs(seconds - 1) < 0.01)
+        await stop()
+
+        var frames = 0
+        var maxChunk: AVAudioFrameCount = 0
+        var lastPeak: Float = 1
+        for await buffer in buffers {
+            #expect(buffer.format.sampleRate == 16000)
+            frames += Int(buffer.frameLength)
+            maxChunk = max(maxChunk, buffer.frameLength)
+            let samples = buffer.floatChannelData![0]
+            lastPeak = (0..<Int(buffer.frameLength)).reduce(0) { max($0, abs(samples[$1])) }
+        }
+        let expected = Int((1 + InjectedAudioSourceFactory.trailingSilenceSeconds) * 16000)
+        #expect(abs(frames - expected) < 20)
+        #expect(maxChunk == InjectedAudioSourceFactory.chunkFrames)
+        #expect(lastPeak == 0)
+    }
+
+    @Test("Stopping an old source leaves a rebuilt one injectable")
+    func rebuildKeepsNewestSource() async throws {
+        let url = try Self.writeTone(seconds: 0.2)
+        defer { try? FileManager.default.removeItem(at: url) }
+        let factory = InjectedAudioSourceFactory()
+        let (_, _, stopOld) = try await factory.makeMicrophoneSource(device: nil)
+        let (_, _, stopNew) = try await factory.makeMicrophoneSource(device: nil)
+        await stopOld()
+        #expect(throws: Never.self) { try factory.inject(contentsOf: url) }
+
+        await stopNew()
+        #expect(throws: InjectedAudioSourceFactory.InjectionError.notRecording) {
+            try factory.inject(contentsOf: url)
+        }
+    }
+}
@@ -0,0 +1,108 @@
+import Testing
+import Foundation
+@testable import StenoDaemon
+
+/// Tests for the remote streaming ASR backend's pure parts: backend
+/// selection, stream URL building and Deepgram message decoding. The
+/// WebSocket itself isn't exercised here.
+@Suite("RemoteSpeechRecognizer Tests")
+struct RemoteSpeechRecognizerTests {
+
+    // MARK: - Backend selection
+
+    @Test("Auto uses local ASR when this Mac supports it")
+    func autoPrefersLocal() {
+        #expect(TranscriptionBackend.auto.resolved(localAvailable: true, remoteConfigured: true) == .local)
+        #expect(TranscriptionBackend.auto.resolved(localAvailable: false, remoteConfigured: true) == .remote)
+        // Nothing to fall back to: local, which will surface its own error.
+        #expect(TranscriptionBackend.auto.resolved(localAvailable: false, remoteConfigured: false) == .local)
+    }
+
+    @Test("Explicit backends hold unless remote has no key")
+    func explicitBackends() {
+        #expect(TranscriptionBackend.local.resolved(localAvailable: false, remoteConfigured: true) == .local)
+        #expect(TranscriptionBackend.remote.resolved(localAvailable: true, remoteConfigured: true) == .remote)
+        #expect(TranscriptionBackend.remote.resolved(localAvailable: true, remoteConfigured: false) == .local)
+    }
+
+    @Test("Settings written before remote ASR decode to auto")
+    func settingsDefaults() throws {
+        let settings = try JSONDecoder().decode(StenoSettings.self, from: Data(#"{"summarizationProvider":"local"}"#.utf8))
+        #expect(settings.transcriptionBackend == .auto)
+        #expect(settings.remoteASRURL == RemoteSpeechRecognizerFactory.defaultEndpoint)
+        #expect(settings.remoteASRAPIKey == nil)
+    }
+
+    // MARK: - Stream URL
+
+    @Test("Stream URL carries the audio format and locale")
+    func streamURL() throws {
+        let url = try RemoteSpeechRecognizerFactory.streamURL(
+            endpoint: RemoteSpeechRecognizerFactory.defaultEndpoint,
+            locale: Locale(identifier: "en_US")
+        )
+        let items = URLComponents(url: url, resolvingAgainstBaseURL: false)?.queryItems ?? []
+        let query = Dictionary(uniqueKeysWithValues: items.map { ($0.name, $0.value ?? "") })
+        #expect(url.host == "api.deepgram.com")
+        #expect(query["encoding"] == "linear16")
+        #expect(query["sample_rate"] == "16000")
+        #expect(query["channels"] == "1")
+        #expect(query["interim_results"] == "true")
+        #expect(query["language"] == "en-US")
+    }
+
+    @Test("Endpoint options are kept, except the audio format")
+    func streamURLKeepsOptions() throws {
+        let url = try RemoteSpeechRecognizerFactory.streamURL(
+            endpoint: "wss://asr.example.com/v1/listen?model=nova-2&language=multi&sample_rate=48000",
+            locale: Locale(identifier: "en_US")
+        )
+        let items = URLComponents(url: url, resolvingAgainstBaseURL: false)?.queryItems ?? []
+        #expect(items.filter { $0.name == "language" }.map(\.value) == ["multi"])
+        #expect(items.filter { $0.name == "sample_rate" }.map(\.value) == ["16000"])
+        #expect(items.contains(URLQueryItem(name: "model", value: "nova-2")))
+    }
+
+    @Test("Non-WebSocket endpoints are rejected")
+    func streamURLRejectsHTTP() {
+        #expect(throws: RemoteASRError.invalidEndpoint("https://api.deepgram.com/v1/listen")) {
+            try RemoteSpeechRecognizerFactory.streamURL(
+                endpoint: "https://api.deepgram.com/v1/listen",
+                locale: .current
+            )
+        }
+    }
+
+    // MARK: - Messages
+
+    @Test("Results decode to recognizer results")
+    func decodeResults() throws {
+        let interim = #"{"type":"Results","is_final":false,"channel":{"alternatives":[{"transcript":"let's ship","confidence":0.91}]}}"#
+        let result = try #require(try RemoteASRMessage.result(from: Data(interim.utf8), source: .systemAudio))
+        #expect(result.text == "let's ship")
+        #expect(!result.isFinal)
+        #expect(result.confidence == Float(0.91))
+        #expect(result.source == .systemAudio)
+
+        let final = #"{"type":"Results","is_final":true,"channel":{"alternatives":[{"transcript":"let's ship Friday."}]}}"#
+        let finalResult = try #require(try RemoteASRMessage.result(from: Data(final.utf8), source: .microphone))
+        #expect(finalResult.isFinal)
+        #expect(finalResult.confidence == nil)
+    }
+
+    @Test("Silence and metadata yield nothing")
+    func decodeIgnored() throws {
+        let silent = #"{"type":"Results","is_final":true,"channel":{"alternatives":[{"transcript":"  ","confidence":0}]}}"#
+        #expect(try RemoteASRMessage.result(from: Data(silent.utf8), source: .microphone) == nil)
+        let metadata = #"{"type":"Metadata","request_id":"abc","duration":1.5}"#
+        #expect(try RemoteASRMessage.result(from: Data(metadata.utf8), source: .microphone) == nil)
+    }
+
+    @Test("Server errors throw")
+    func decodeError() {
+        let error = #"{"type":"Error","description":"invalid credentials"}"#
+        #expect(throws: RemoteASRError.serverError("invalid credentials")) {
+            try RemoteASRMessage.result(from: Data(error.utf8), source: .microphone)
+        }
+    }
+}
@@ -0,0 +1,112 @@
+import Testing
+import Foundation
+@testable import StenoDaemon
+
+/// Tests for `RecordingEngine.handlePressureChange` and deferring
+/// rolling summaries under `SummaryDeferral`.
+@Suite("Resource Pressure Tests")
+struct ResourcePressureTests {
+
+    private let hot = ResourcePressure(thermalState: "serious", lowPower: false)
+    private let zoom = ConferencingApp(bundleID: "us.zoom.xos", name: "Zoom")
+
+    @Test("Fair isn't pressure; serious, critical and Low Power Mode are")
+    func pressureThresholds() {
+        #expect(!ResourcePressure.nominal.isUnderPressure)
+        #expect(!ResourcePressure(thermalState: "fair", lowPower: false).isUnderPressure)
+        #expect(ResourcePressure(thermalState: "serious", lowPower: false).isUnderPressure)
+        #expect(ResourcePressure(thermalState: "critical", lowPower: false).isUnderPressure)
+        #expect(ResourcePressure(thermalState: "nominal", lowPower: true).isUnderPressure)
+    }
+
+    @Test("A change is reported once, with whether summaries are deferred")
+    func changeIsReported() async {
+        let (engine, _, _, del) = await makeEngine(deferral: .always)
+
+        await engine.handlePressureChange(hot)
+        await engine.handlePressureChange(hot)
+
+        #expect(await engine.pressure == hot)
+        #expect(await engine.summariesDeferred)
+        let reports = await del.events.filter {
+            if case .pressureChanged(_, true) = $0 { return true }; return false
+        }
+        #expect(reports.count == 1)
+    }
+
+    @Test("Off never defers")
+    func offNeverDefers() async {
+        let (engine, _, _, _) = await makeEngine(deferral: .off)
+        await engine.handlePressureChange(hot)
+        #expect(await !engine.summariesDeferred)
+    }
+
+    @Test("Calls defers only while a call is on, and says so")
+    func callsDefersDuringCalls() async {
+        let (engine, _, _, del) = await makeEngine(deferral: .calls)
+        await engine.handlePressureChange(hot)
+        #expect(await !engine.summariesDeferred)
+
+        await engine.handleCallTransition(.started(zoom), autoRecord: false)
+        #expect(await engine.summariesDeferred)
+        #expect(await del.events.contains {
+            if case .pressureChanged(_, true) = $0 { return true }; return false
+        })
+
+        await engine.handleCallTransition(.ended(zoom), autoRecord: false)
+        #expect(await !engine.summariesDeferred)
+    }
+
+    @Test("Segments don't summarize while deferred")
+    func deferredSegmentsSkipSummary() async throws {
+        let (engine, summarizer, rf, _) = await makeEngine(deferral: .always)
+        await engine.handlePressureChange(hot)
+
+        rf.handle.resultsToYield = [
+            RecognizerResult(text: "test segment", isFinal: true, source: .microphone)
+        ]
+        _ = try await engine.start()
+        try await Task.sleep(for: .milliseconds(100))
+
+        #expect(await summarizer.summarizeCallCount == 0)
+        #expect(await engine.segmentCount == 1)
+
+        await engine.stop()
+    }
+
+    // MARK: - Helpers
+
+    @MainActor
+    private func makeEngine(deferral: SummaryDeferral) async -> (
+        engine: RecordingEngine,
+        summarizer: MockSummarizationService,
+        recognizerFactory: MockSpeechRecognizerFactory,
+        delegate: MockRecordingEngineDelegate
+    ) {
+        let repo = MockTranscriptRepository()
+        let summarizer = MockSummarizationService()
+        let rf = MockSpeechRecognizerFactory()
+        let del = MockRecordingEngineDelegate()
+        let coordinator = RollingSummaryCoordinator(
+            repository: repo,
+            summarizer: summarizer,
+            triggerCount: 1,
+            timeThreshold: 0,
+            minSegmentsForExtraction: 1
+        )
+        let engine = RecordingEngine(
+            repository: repo,
+            permissionService: MockPermissionService(),
+            summaryCoordinator: coordinator,
+            audioSourceFactory: MockAudioSourceFactory(),
+            speechRecognizerFactory: rf,
+            delegate: del,
+            backoffSleep: { _ in },
+            emptySessionMinChars: 0,
+            emptySessionMinDurationSeconds: 0,
+            retentionDays: 0,
+            summaryDeferral: deferral
+        )
+        return (engine, summarizer, rf, del)
+    }
+}
@@ -0,0 +1,92 @@
+import Testing
+import Foundation
+@testable import StenoDaemon
+
+/// Tests for conferencing-call detection's pure parts: app matching and
+/// `CallDetector`'s start / end thresholds. The Core Audio process list
+/// itself isn't exercised here.
+@Suite("ConferencingAppObserver Tests")
+struct ConferencingAppObserverTests {
+
+    private let zoom = ConferencingApp(bundleID: "us.zoom.xos", name: "Zoom")
+    private let chrome = ConferencingApp(bundleID: "com.google.Chrome", name: "Google Chrome")
+
+    // MARK: - App matching
+
+    @Test("Helpers count as their app; lookalikes don't")
+    func owningMatchesHelpers() {
+        #expect(ConferencingApp.owning(bundleID: "us.zoom.xos") == zoom)
+        #expect(ConferencingApp.owning(bundleID: "com.google.Chrome.helper") == chrome)
+        #expect(ConferencingApp.owning(bundleID: "com.google.Chromecast") == nil)
+        #expect(ConferencingApp.owning(bundleID: "com.apple.VoiceMemos") == nil)
+    }
+
+    // MARK: - Detector
+
+    @Test("A call starts once the app stays on the mic")
+    func startsAfterThreshold() {
+        var detector = CallDetector(startAfter: 2, endAfter: 2)
+        #expect(detector.observe(["us.zoom.xos"]).isEmpty)
+        #expect(detector.observe(["us.zoom.xos"]) == [.started(zoom)])
+        #expect(detector.current == zoom)
+        #expect(detector.observe(["us.zoom.xos"]).isEmpty)
+    }
+
+    @Test("A brief mic preview doesn't start a call")
+    func ignoresPreview() {
+        var detector = CallDetector(startAfter: 2, endAfter: 2)
+        #expect(detector.observe(["us.zoom.xos"]).isEmpty)
+        #expect(detector.observe([]).isEmpty)
+        #expect(detector.observe(["us.zoom.xos"]).isEmpty)
+        #expect(detector.current == nil)
+    }
+
+    @Test("Other apps on the mic are ignored")
+    func ignoresUnknownApps() {
+        var detector = CallDetector(startAfter: 1, endAfter: 1)
+        #expect(detector.observe(["com.apple.VoiceMemos"]).isEmpty)
+    }
+
+    @Test("A mute-and-reopen doesn't end the call")
+    func toleratesShortGaps() {
+        var detector = CallDetector(startAfter: 1, endAfter: 3)
+        #expect(detector.observe(["us.zoom.xos"]) == [.started(zoom)])
+        #expect(detector.observe([]).isEmpty)
+        #expect(detector.observe([]).isEmpty)
+        #expect(detector.observe(["us.zoom.xos"]).isEmpty)
+        #expect(detector.observe([]).isEmpty)
+        #expect(detector.observe([]).isEmpty)
+        #expect(detector.observe([]) == [.ended(zoom)])
+        #expect(detector.current == nil)
+    }
+
+    @Test("One call at a time, in priority order")
+    func oneCallAtATime() {
+        var detector = CallDetector(startAfter: 1, endAfter: 1)
+        #expect(detector.observe(["com.google.Chrome.helper", "us.zoom.xos"]) == [.started(zoom)])
+        // Zoom hangs up while Chrome is still on the mic: hand over.
+        #expect(detector.observe(["com.google.Chrome.helper"]) == [.ended(zoom), .started(chrome)])
+    }
+
+    // MARK: - Observer
+
+    @Test("poll() feeds the provider's capture set to the detector")
+    func observerPolls() {
+        let observer = ConferencingAppObserver(
+            detector: CallDetector(startAfter: 1, endAfter: 1),
+            capturingProvider: { ["us.zoom.xos"] }
+        )
+        #expect(observer.poll() == [.started(zoom)])
+        #expect(observer.poll().isEmpty)
+    }
+
+    // MARK: - Settings
+
+    @Test("Settings written before call detection decode to off")
+    func settingsDefault() throws {
+        let settings = try JSONDecoder().decode(StenoSettings.self, from: Data(#"{"summarizationProvider":"local"}"#.utf8))
+        #expect(settings.callDetection == .off)
+        let auto = try JSONDecoder().decode(StenoSettings.self, from: Data(#"{"callDetection":"auto"}"#.utf8))
+        #expect(auto.callDetection == .auto)
+    }
+}
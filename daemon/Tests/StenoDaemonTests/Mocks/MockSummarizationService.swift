@@ -44,6 +44,14 @@ actor MockSummarizationService: SummarizationService {
         return topicsToReturn
     }
 
+    private(set) var cleanVerbatimCallCount = 0
+
+    func cleanVerbatim(_ text: String) async throws -> String {
+        cleanVerbatimCallCount += 1
+        if let error = shouldThrow { throw error }
+        return "Clean: \(text)"
+    }
+
     func setTopicsToReturn(_ value: [Topic]) {
         topicsToReturn = value
     }
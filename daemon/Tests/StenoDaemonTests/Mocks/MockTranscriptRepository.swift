@@ -283,7 +283,9 @@ actor MockTranscriptRepository: TranscriptRepository {
                     healMarker: old.healMarker,
                     duplicateOf: sysSegmentId,
                     dedupMethod: method,
-                    micPeakDb: old.micPeakDb
+                    micPeakDb: old.micPeakDb,
+                    locale: old.locale,
+                    speaker: old.speaker
                 )
                 var copy = segs
                 copy[idx] = updated